@@ -2,7 +2,11 @@ package agents
 
 import (
 	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/agents/drain"
 	"github.com/airplanedev/cli/cmd/airplane/agents/ecslogs"
+	"github.com/airplanedev/cli/cmd/airplane/agents/labels"
+	"github.com/airplanedev/cli/cmd/airplane/agents/list"
+	"github.com/airplanedev/cli/cmd/airplane/agents/status"
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/utils"
@@ -13,9 +17,12 @@ import (
 func New(c *cli.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "agents",
-		Short: "Debug self-hosted agents",
-		Long:  "Debug self-hosted agents",
+		Short: "Manage and debug self-hosted agents",
+		Long:  "Manage and debug self-hosted agents",
 		Example: heredoc.Doc(`
+			airplane agents list
+			airplane agents drain <id>
+			airplane agents labels my_task region=us-west-2
 			airplane agents ecslogs --cluster-name=my-airplane-ecs-cluster
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
@@ -23,6 +30,10 @@ func New(c *cli.Config) *cobra.Command {
 		}),
 	}
 
+	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(status.New(c))
+	cmd.AddCommand(drain.New(c))
+	cmd.AddCommand(labels.New(c))
 	cmd.AddCommand(ecslogs.New(c))
 
 	return cmd
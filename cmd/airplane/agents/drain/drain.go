@@ -0,0 +1,86 @@
+package drain
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root *cli.Config
+
+	envSlug   string
+	agentID   string
+	assumeYes bool
+}
+
+// New returns a new drain command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "drain <id>",
+		Short: "Stops assigning runs to a self-hosted agent",
+		Long: heredoc.Doc(`
+			Stops assigning new runs to a self-hosted agent, so that it can be shut down without
+			interrupting runs. Runs that the agent is executing continue until they finish.
+
+			Use "airplane agents status" to check whether the agent has finished its runs.
+		`),
+		Example: heredoc.Doc(`
+			airplane agents drain <id>
+			airplane agents drain <id> --yes
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.agentID = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "Drain the agent without asking for confirmation.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	client := cfg.root.Client
+	a, err := client.GetAgent(ctx, api.GetAgentRequest{
+		AgentID: cfg.agentID,
+		EnvSlug: cfg.envSlug,
+	})
+	if err != nil {
+		return err
+	}
+	if a.Status == api.AgentStatusDraining {
+		logger.Log("Agent %s is already draining.", logger.Bold(a.ID))
+		return nil
+	}
+
+	question := "Drain agent " + a.ID + "?"
+	if a.Pool != "" {
+		question = "Drain agent " + a.ID + " in pool " + a.Pool + "?"
+	}
+	if ok, err := cfg.root.Prompter.ConfirmWithAssumptions(question, cfg.assumeYes, false); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	if err := client.DrainAgent(ctx, api.DrainAgentRequest{
+		AgentID: a.ID,
+		EnvSlug: cfg.envSlug,
+	}); err != nil {
+		return errors.Wrap(err, "draining agent")
+	}
+	logger.Log("Draining agent %s. It will finish %d active run(s) before it can be shut down.", logger.Bold(a.ID), a.RunningRunsCount)
+	return nil
+}
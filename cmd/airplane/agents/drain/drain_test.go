@@ -0,0 +1,36 @@
+package drain
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/prompts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrain(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	client := &api.MockClient{
+		Agents: []api.Agent{{ID: "agt1", Pool: "batch", Status: api.AgentStatusHealthy, RunningRunsCount: 2}},
+	}
+	cfg := config{
+		root:    &cli.Config{Client: client, Prompter: prompts.NewMock(false, true)},
+		agentID: "agt1",
+	}
+
+	// Declining the confirmation doesn't drain the agent.
+	require.NoError(run(ctx, cfg))
+	require.Equal(api.AgentStatusHealthy, client.Agents[0].Status)
+
+	require.NoError(run(ctx, cfg))
+	require.Equal(api.AgentStatusDraining, client.Agents[0].Status)
+
+	// Draining agents aren't drained again, so there's nothing to confirm.
+	require.NoError(run(ctx, cfg))
+
+	cfg.agentID = "agt2"
+	require.EqualError(run(ctx, cfg), "no agent agt2")
+}
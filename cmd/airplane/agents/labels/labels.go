@@ -0,0 +1,182 @@
+package labels
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	envSlug string
+	slug    string
+	set     []string
+	remove  []string
+	pool    *string
+}
+
+// New returns a new labels command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+	var pool string
+
+	cmd := &cobra.Command{
+		Use:   "labels <task slug> [key=value...]",
+		Short: "Shows or edits the agent labels that a task runs on",
+		Long: heredoc.Doc(`
+			Shows or edits the run constraints of a task: the labels that self-hosted agents need to
+			run it, and the agent pool that it's pinned to.
+
+			Constraints of tasks that are deployed from a definition file are replaced on their next
+			deploy, unless they're also set in the definition with constraints and agentPool.
+		`),
+		Example: heredoc.Doc(`
+			airplane agents labels my_task
+			airplane agents labels my_task region=us-west-2 gpu=true
+			airplane agents labels my_task --remove gpu
+			airplane agents labels my_task --pool batch
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slug = args[0]
+			cfg.set = args[1:]
+			if cmd.Flags().Changed("pool") {
+				cfg.pool = &pool
+			}
+			return run(cmd.Root().Context(), c.Client, cfg)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&cfg.remove, "remove", nil, "Keys of labels to remove.")
+	cmd.Flags().StringVar(&pool, "pool", "", "The agent pool to pin the task to. An empty value unpins it.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+func run(ctx context.Context, client api.APIClient, cfg config) error {
+	task, err := client.GetTask(ctx, libapi.GetTaskRequest{Slug: cfg.slug, EnvSlug: cfg.envSlug})
+	if err != nil {
+		return err
+	}
+
+	constraints := task.Constraints
+	if len(cfg.set) > 0 || len(cfg.remove) > 0 || cfg.pool != nil {
+		constraints, err = editConstraints(task.Constraints, cfg.set, cfg.remove, cfg.pool)
+		if err != nil {
+			return err
+		}
+		req := task.AsUpdateTaskRequest()
+		// Fields that aren't managed as code are kept as they are.
+		req.Repo = task.Repo
+		req.ResourceRequests = task.ResourceRequests
+		req.Constraints = constraints
+		req.EnvSlug = cfg.envSlug
+		if _, err := client.UpdateTask(ctx, req); err != nil {
+			return errors.Wrap(err, "updating task")
+		}
+		logger.Log("Updated the run constraints of task %s.", logger.Bold(task.Slug))
+
+		if err := warnIfUnmatched(ctx, client, cfg.envSlug, constraints); err != nil {
+			logger.Debug("Unable to check for matching agents: %v", err)
+		}
+	}
+
+	print.Print(constraints, func() {
+		if constraints.Pool != "" {
+			logger.Log("%s %s", logger.Bold("Pool:"), constraints.Pool)
+		}
+		if len(constraints.Labels) == 0 {
+			if constraints.Pool != "" {
+				logger.Log("Task %s runs on any agent in pool %s.", logger.Bold(task.Slug), constraints.Pool)
+			} else {
+				logger.Log("Task %s runs on any agent.", logger.Bold(task.Slug))
+			}
+			return
+		}
+		tw := tablewriter.NewWriter(os.Stdout)
+		tw.SetBorder(false)
+		tw.SetHeader([]string{"key", "value"})
+		for _, l := range constraints.Labels {
+			tw.Append([]string{l.Key, l.Value})
+		}
+		tw.Render()
+	})
+	return nil
+}
+
+// editConstraints returns constraints with the labels in set (as key=value) added or replaced,
+// the labels with keys in remove removed, and the pool replaced if it's set. Labels are sorted by
+// key.
+func editConstraints(constraints libapi.RunConstraints, set, remove []string, pool *string) (libapi.RunConstraints, error) {
+	labels := map[string]string{}
+	for _, l := range constraints.Labels {
+		labels[l.Key] = l.Value
+	}
+	for _, kv := range set {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return libapi.RunConstraints{}, errors.Errorf("invalid label %q: expected key=value", kv)
+		}
+		labels[k] = v
+	}
+	for _, k := range remove {
+		if _, ok := labels[k]; !ok {
+			return libapi.RunConstraints{}, errors.Errorf("task has no label %q", k)
+		}
+		delete(labels, k)
+	}
+
+	edited := libapi.RunConstraints{
+		Labels: []libapi.AgentLabel{},
+		Pool:   constraints.Pool,
+	}
+	for k, v := range labels {
+		edited.Labels = append(edited.Labels, libapi.AgentLabel{Key: k, Value: v})
+	}
+	sort.Slice(edited.Labels, func(i, j int) bool {
+		return edited.Labels[i].Key < edited.Labels[j].Key
+	})
+	if pool != nil {
+		edited.Pool = *pool
+	}
+	return edited, nil
+}
+
+// warnIfUnmatched warns if no healthy agent satisfies constraints, in which case runs of the task
+// stay queued.
+func warnIfUnmatched(ctx context.Context, client api.APIClient, envSlug string, constraints libapi.RunConstraints) error {
+	resp, err := client.ListAgents(ctx, api.ListAgentsRequest{EnvSlug: envSlug, Pool: constraints.Pool})
+	if err != nil {
+		return err
+	}
+	for _, a := range resp.Agents {
+		if a.Status == api.AgentStatusHealthy && matches(a, constraints) {
+			return nil
+		}
+	}
+	logger.Warning("No healthy agent matches these constraints, so runs of the task will be queued until one does.")
+	return nil
+}
+
+func matches(a api.Agent, constraints libapi.RunConstraints) bool {
+	if constraints.Pool != "" && a.Pool != constraints.Pool {
+		return false
+	}
+	for _, l := range constraints.Labels {
+		if v, ok := a.Labels[l.Key]; !ok || v != l.Value {
+			return false
+		}
+	}
+	return true
+}
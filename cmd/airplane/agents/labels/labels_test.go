@@ -0,0 +1,61 @@
+package labels
+
+import (
+	"context"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	client := &api.MockClient{
+		Tasks: map[string]libapi.Task{
+			"my_task": {
+				ID:   "tsk1",
+				Slug: "my_task",
+				Constraints: libapi.RunConstraints{
+					Labels: []libapi.AgentLabel{{Key: "region", Value: "us-east-1"}, {Key: "gpu", Value: "true"}},
+				},
+			},
+		},
+	}
+
+	require.NoError(run(ctx, client, config{
+		slug:   "my_task",
+		set:    []string{"region=us-west-2", "team=data"},
+		remove: []string{"gpu"},
+		pool:   pointers.String("batch"),
+	}))
+	require.Equal(libapi.RunConstraints{
+		Labels: []libapi.AgentLabel{{Key: "region", Value: "us-west-2"}, {Key: "team", Value: "data"}},
+		Pool:   "batch",
+	}, client.Tasks["my_task"].Constraints)
+
+	// The pool is kept unless it's set, and an empty pool unpins the task.
+	require.NoError(run(ctx, client, config{slug: "my_task", remove: []string{"team"}}))
+	require.Equal("batch", client.Tasks["my_task"].Constraints.Pool)
+	unpinned := ""
+	require.NoError(run(ctx, client, config{slug: "my_task", pool: &unpinned}))
+	require.Equal(libapi.RunConstraints{
+		Labels: []libapi.AgentLabel{{Key: "region", Value: "us-west-2"}},
+	}, client.Tasks["my_task"].Constraints)
+
+	require.EqualError(run(ctx, client, config{slug: "my_task", set: []string{"region"}}), `invalid label "region": expected key=value`)
+	require.EqualError(run(ctx, client, config{slug: "my_task", remove: []string{"gpu"}}), `task has no label "gpu"`)
+}
+
+func TestMatches(t *testing.T) {
+	require := require.New(t)
+
+	agent := api.Agent{Pool: "batch", Labels: map[string]string{"region": "us-west-2", "gpu": "true"}}
+	require.True(matches(agent, libapi.RunConstraints{}))
+	require.True(matches(agent, libapi.RunConstraints{Labels: []libapi.AgentLabel{{Key: "gpu", Value: "true"}}, Pool: "batch"}))
+	require.False(matches(agent, libapi.RunConstraints{Labels: []libapi.AgentLabel{{Key: "region", Value: "us-east-1"}}}))
+	require.False(matches(agent, libapi.RunConstraints{Labels: []libapi.AgentLabel{{Key: "team", Value: "data"}}}))
+	require.False(matches(agent, libapi.RunConstraints{Pool: "default"}))
+}
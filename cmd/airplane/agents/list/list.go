@@ -0,0 +1,86 @@
+package list
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	envSlug string
+	pool    string
+}
+
+// New returns a new list command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists self-hosted agents and their status",
+		Example: heredoc.Doc(`
+			airplane agents list
+			airplane agents list --pool gpu
+			airplane agents list -o json
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c.Client, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.pool, "pool", "", "Only list the agents in this agent pool.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+func run(ctx context.Context, client api.APIClient, cfg config) error {
+	resp, err := client.ListAgents(ctx, api.ListAgentsRequest{
+		EnvSlug: cfg.envSlug,
+		Pool:    cfg.pool,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing agents")
+	}
+
+	print.Print(resp.Agents, func() {
+		tw := tablewriter.NewWriter(os.Stdout)
+		tw.SetBorder(false)
+		tw.SetHeader([]string{"id", "hostname", "pool", "status", "running", "labels", "last heartbeat"})
+		for _, a := range resp.Agents {
+			tw.Append([]string{
+				a.ID,
+				a.Hostname,
+				a.Pool,
+				string(a.Status),
+				strconv.Itoa(a.RunningRunsCount),
+				formatLabels(a.Labels),
+				a.LastHeartbeatAt.Format(time.RFC3339),
+			})
+		}
+		tw.Render()
+	})
+	return nil
+}
+
+// formatLabels formats labels as comma-separated key=value pairs, sorted by key.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,31 @@
+package list
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	client := &api.MockClient{
+		Agents: []api.Agent{
+			{ID: "agt1", Pool: "batch", Status: api.AgentStatusHealthy, Labels: map[string]string{"region": "us-west-2", "gpu": "true"}},
+			{ID: "agt2", Status: api.AgentStatusDraining},
+		},
+	}
+
+	require.NoError(run(ctx, client, config{}))
+	require.NoError(run(ctx, client, config{pool: "batch"}))
+	require.NoError(run(ctx, &api.MockClient{}, config{}))
+}
+
+func TestFormatLabels(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("", formatLabels(nil))
+	require.Equal("gpu=true, region=us-west-2", formatLabels(map[string]string{"region": "us-west-2", "gpu": "true"}))
+}
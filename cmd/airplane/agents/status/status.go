@@ -0,0 +1,86 @@
+package status
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	envSlug string
+	agentID string
+}
+
+// New returns a new status command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "status <id>",
+		Short: "Shows the status of a self-hosted agent",
+		Example: heredoc.Doc(`
+			airplane agents status <id>
+			airplane agents status <id> -o json
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.agentID = args[0]
+			return run(cmd.Root().Context(), c.Client, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+func run(ctx context.Context, client api.APIClient, cfg config) error {
+	a, err := client.GetAgent(ctx, api.GetAgentRequest{
+		AgentID: cfg.agentID,
+		EnvSlug: cfg.envSlug,
+	})
+	if err != nil {
+		return err
+	}
+
+	print.Print(a, func() {
+		logger.Log("%s %s", logger.Bold("Agent:"), a.ID)
+		logger.Log("%s %s", logger.Bold("Hostname:"), a.Hostname)
+		logger.Log("%s %s", logger.Bold("Pool:"), a.Pool)
+		logger.Log("%s %s", logger.Bold("Version:"), a.Version)
+		logger.Log("%s %s", logger.Bold("Status:"), a.Status)
+		logger.Log("%s %s", logger.Bold("Running runs:"), strconv.Itoa(a.RunningRunsCount))
+		logger.Log("%s %s", logger.Bold("Last heartbeat:"), a.LastHeartbeatAt.Format(time.RFC3339))
+		if a.Status == api.AgentStatusDraining && a.RunningRunsCount == 0 {
+			logger.Log("")
+			logger.Log("The agent has finished its runs and can be shut down.")
+		}
+
+		if len(a.Labels) > 0 {
+			logger.Log("")
+			logger.Log("%s", logger.Bold("Labels"))
+			keys := make([]string, 0, len(a.Labels))
+			for k := range a.Labels {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			tw := tablewriter.NewWriter(os.Stdout)
+			tw.SetBorder(false)
+			for _, k := range keys {
+				tw.Append([]string{k, a.Labels[k]})
+			}
+			tw.Render()
+		}
+	})
+	return nil
+}
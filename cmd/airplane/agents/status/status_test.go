@@ -0,0 +1,24 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	client := &api.MockClient{
+		Agents: []api.Agent{
+			{ID: "agt1", Pool: "batch", Status: api.AgentStatusHealthy, Labels: map[string]string{"region": "us-west-2"}},
+			{ID: "agt2", Status: api.AgentStatusDraining},
+		},
+	}
+
+	require.NoError(run(ctx, client, config{agentID: "agt1"}))
+	require.NoError(run(ctx, client, config{agentID: "agt2"}))
+	require.EqualError(run(ctx, client, config{agentID: "agt3"}), "no agent agt3")
+}
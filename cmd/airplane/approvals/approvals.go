@@ -0,0 +1,34 @@
+package approvals
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/approvals/request"
+	"github.com/airplanedev/cli/cmd/airplane/approvals/wait"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "approvals",
+		Short:   "Request approvals",
+		Long:    "Request approvals from users and groups, f.e. to gate a script on a human approval.",
+		Aliases: []string{"approval"},
+		Example: heredoc.Doc(`
+			airplane approvals request --message "Deploy to production?" --approvers group:oncall --timeout 2h
+			airplane approvals request --message "Drop the users table?" --no-wait
+			airplane approvals wait <id> --timeout 2h
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+	}
+
+	cmd.AddCommand(request.New(c))
+	cmd.AddCommand(wait.New(c))
+
+	return cmd
+}
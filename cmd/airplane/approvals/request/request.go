@@ -0,0 +1,101 @@
+package request
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/approvals"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	runID             string
+	message           string
+	approvers         []string
+	allowSelfApproval bool
+	timeout           utils.DurationValue
+	noWait            bool
+}
+
+// New returns a new request command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "request",
+		Short: "Request an approval and wait for a response",
+		Long: heredoc.Doc(`
+			Requests an approval and waits until it is approved or denied. The command exits with a
+			non-zero exit code if the request is denied or times out, so it can gate the next step
+			of a script.
+
+			With --no-wait, the ID of the request is printed instead, to wait for it later with
+			"airplane approvals wait".
+
+			Approval requests belong to a run: by default, the run of the script that runs the
+			command, from AIRPLANE_RUN_ID.
+		`),
+		Example: heredoc.Doc(`
+			airplane approvals request --message "Deploy to production?" --approvers group:oncall --timeout 2h && ./deploy.sh
+			airplane approvals request --message "Rotate keys?" --approvers user:alice@example.com --approvers group:security
+			id=$(airplane approvals request --message "Drop the users table?" --no-wait)
+			airplane approvals request --message "Deploy to production?" --run-id run20230101abcdef
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.runID, "run-id", os.Getenv("AIRPLANE_RUN_ID"), "ID of the run that requests the approval. Defaults to AIRPLANE_RUN_ID.")
+	cmd.Flags().StringVarP(&cfg.message, "message", "m", "", "Message shown to approvers.")
+	cmd.Flags().StringSliceVar(&cfg.approvers, "approvers", nil, "Users and groups that can respond, as user:<email> or group:<slug>. Defaults to anyone in the team.")
+	cmd.Flags().BoolVar(&cfg.allowSelfApproval, "allow-self-approval", false, "Allow the requester to respond to their own request.")
+	cmd.Flags().Var(&cfg.timeout, "timeout", `How long to wait for a response, e.g. "30m" or "2h". Waits indefinitely if not set.`)
+	cmd.Flags().BoolVar(&cfg.noWait, "no-wait", false, "Print the ID of the request instead of waiting for a response.")
+
+	if err := cmd.MarkFlagRequired("message"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+
+	return cmd
+}
+
+func run(ctx context.Context, c *cli.Config, cfg config) error {
+	if cfg.runID == "" {
+		return errors.New("expected --run-id: approval requests can only be created for a run")
+	}
+	id, err := approvals.Create(ctx, c.Client, approvals.Request{
+		RunID:             cfg.runID,
+		Message:           cfg.message,
+		Approvers:         cfg.approvers,
+		AllowSelfApproval: cfg.allowSelfApproval,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cfg.noWait {
+		fmt.Println(id)
+		return nil
+	}
+
+	logger.Log("Waiting for a response to approval request %s...", logger.Bold(id))
+	prompt, err := approvals.Wait(ctx, c.Client, id, time.Duration(cfg.timeout), time.Second)
+	if err != nil {
+		return errors.Wrapf(err, "approval request %s", id)
+	}
+	if prompt.SubmittedBy != nil {
+		logger.Log("Approved by %s.", *prompt.SubmittedBy)
+	} else {
+		logger.Log("Approved.")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package wait
+
+import (
+	"context"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/approvals"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	id      string
+	timeout utils.DurationValue
+}
+
+// New returns a new wait command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "wait <id>",
+		Short: "Wait for a response to an approval request",
+		Long: heredoc.Doc(`
+			Waits until an approval request is approved or denied. The command exits with a non-zero
+			exit code if the request is denied or times out.
+		`),
+		Example: heredoc.Doc(`
+			airplane approvals wait <id>
+			airplane approvals wait <id> --timeout 2h
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.id = args[0]
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+
+	cmd.Flags().Var(&cfg.timeout, "timeout", `How long to wait for a response, e.g. "30m" or "2h". Waits indefinitely if not set.`)
+
+	return cmd
+}
+
+func run(ctx context.Context, c *cli.Config, cfg config) error {
+	logger.Log("Waiting for a response to approval request %s...", logger.Bold(cfg.id))
+	prompt, err := approvals.Wait(ctx, c.Client, cfg.id, time.Duration(cfg.timeout), time.Second)
+	if err != nil {
+		return errors.Wrapf(err, "approval request %s", cfg.id)
+	}
+	if prompt.SubmittedBy != nil {
+		logger.Log("Approved by %s.", *prompt.SubmittedBy)
+	} else {
+		logger.Log("Approved.")
+	}
+	return nil
+}
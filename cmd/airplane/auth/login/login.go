@@ -2,7 +2,9 @@ package login
 
 import (
 	"context"
+	"time"
 
+	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/pkg/analytics"
 	libhttp "github.com/airplanedev/cli/pkg/api/http"
 	"github.com/airplanedev/cli/pkg/cli"
@@ -18,6 +20,9 @@ import (
 type config struct {
 	root  *cli.Config
 	token string
+	// ssoOrg is the slug of the organization to log in to with SSO, if set.
+	ssoOrg     string
+	deviceCode bool
 }
 
 // New returns a new login command.
@@ -29,12 +34,22 @@ func New(c *cli.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Login to Airplane",
+		Example: heredoc.Doc(`
+			airplane login
+			airplane login --sso my-org
+			airplane login --sso my-org --device-code
+		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.deviceCode && cfg.ssoOrg == "" {
+				return errors.New("--device-code requires --sso")
+			}
 			return run(cmd.Root().Context(), cfg)
 		},
 	}
 
 	cmd.Flags().StringVar(&cfg.token, "token", "", "pass the cli token directly instead of via prompt")
+	cmd.Flags().StringVar(&cfg.ssoOrg, "sso", "", "Log in through the SSO identity provider of the organization with this slug.")
+	cmd.Flags().BoolVar(&cfg.deviceCode, "device-code", false, "Complete the SSO login on another device, e.g. on machines without a browser. Used by default when no terminal is attached.")
 	if err := cmd.Flags().MarkHidden("token"); err != nil {
 		logger.Debug("error: %s", err)
 	}
@@ -44,7 +59,11 @@ func New(c *cli.Config) *cobra.Command {
 
 // Run runs the login command.
 func run(ctx context.Context, cfg config) error {
-	if err := login(ctx, cfg); err != nil {
+	if cfg.ssoOrg != "" {
+		if err := loginSSO(ctx, cfg); err != nil {
+			return err
+		}
+	} else if err := login(ctx, cfg); err != nil {
 		return err
 	}
 
@@ -58,15 +77,24 @@ var (
 )
 
 // validateToken returns a boolean indicating whether the current
-// client token is valid.
+// client token is valid. Expired tokens of SSO sessions are refreshed.
 func validateToken(ctx context.Context, c *cli.Config) (bool, error) {
 	if c.Client.Token() == "" {
 		return false, nil
 	}
 
+	if session, ok := currentSession(c); ok && time.Now().After(session.ExpiresAt) {
+		logger.Debug("Found an expired SSO session. Refreshing.")
+		return refreshSession(ctx, c, session)
+	}
+
 	_, err := c.Client.AuthInfo(ctx)
 	var errsc libhttp.ErrStatusCode
 	if errors.As(err, &errsc) && errsc.StatusCode == 401 {
+		if session, ok := currentSession(c); ok {
+			logger.Debug("Found an expired SSO session token. Refreshing.")
+			return refreshSession(ctx, c, session)
+		}
 		logger.Debug("Found an expired token. Re-authenticating.")
 		return false, nil
 	} else if err != nil {
@@ -76,8 +104,8 @@ func validateToken(ctx context.Context, c *cli.Config) (bool, error) {
 	return true, nil
 }
 
-func validateAPIKey(ctx context.Context) bool {
-	return conf.GetAPIKey() != "" && conf.GetTeamID() != ""
+func validateAPIKey(ctx context.Context, c *cli.Config) bool {
+	return c.Resolver.Get("api-key") != "" && c.Resolver.Get("team") != ""
 }
 
 func EnsureLoggedIn(ctx context.Context, c *cli.Config) error {
@@ -87,7 +115,7 @@ func EnsureLoggedIn(ctx context.Context, c *cli.Config) error {
 		return nil
 	}
 
-	if ok := validateAPIKey(ctx); ok {
+	if ok := validateAPIKey(ctx, c); ok {
 		return nil
 	}
 
@@ -114,19 +142,7 @@ func EnsureLoggedIn(ctx context.Context, c *cli.Config) error {
 
 func login(ctx context.Context, cfg config) error {
 	writeToken := func(token string) error {
-		cfg.root.Client.SetToken(token)
-		userConf, err := conf.ReadDefaultUserConfig()
-		if err != nil && !errors.Is(err, conf.ErrMissing) {
-			return err
-		}
-		if userConf.Tokens == nil {
-			userConf.Tokens = map[string]string{}
-		}
-		userConf.Tokens[cfg.root.Client.Host()] = token
-		if err := conf.WriteDefaultUserConfig(userConf); err != nil {
-			return err
-		}
-		return nil
+		return saveToken(cfg.root, token, nil)
 	}
 
 	if cfg.token != "" {
@@ -175,3 +191,24 @@ func login(ctx context.Context, cfg config) error {
 
 	return nil
 }
+
+// saveToken sets the client's token and persists it for the client's host, along with the SSO
+// session that issued it, if any.
+func saveToken(c *cli.Config, token string, session *conf.Session) error {
+	c.Client.SetToken(token)
+	return conf.UpdateDefaultUserConfig(func(userConf *conf.UserConfig) error {
+		if userConf.Tokens == nil {
+			userConf.Tokens = map[string]string{}
+		}
+		userConf.Tokens[c.Client.Host()] = token
+		if session != nil {
+			if userConf.Sessions == nil {
+				userConf.Sessions = map[string]conf.Session{}
+			}
+			userConf.Sessions[c.Client.Host()] = *session
+		} else {
+			delete(userConf.Sessions, c.Client.Host())
+		}
+		return nil
+	})
+}
@@ -0,0 +1,158 @@
+package login
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/prompts"
+	"github.com/airplanedev/cli/pkg/token"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// defaultDevicePollInterval is used if the API doesn't specify how often to poll a device login.
+const defaultDevicePollInterval = 5 * time.Second
+
+// loginSSO logs in through the identity provider of cfg.ssoOrg, so that the resulting session is
+// subject to the organization's SSO session policies.
+//
+// By default, the login is completed in a browser that redirects back to a local server. On
+// machines without a terminal (e.g. CI), or if requested, the login is completed on another
+// device instead.
+func loginSSO(ctx context.Context, cfg config) error {
+	if cfg.deviceCode || !prompts.CanPrompt() {
+		return loginSSODevice(ctx, cfg)
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return err
+	}
+	srv, err := token.NewSSOServer(ctx, cfg.root.Client.LoginSuccessURL(), state)
+	if err != nil {
+		return err
+	}
+	//nolint: contextcheck
+	defer srv.Close()
+
+	url := cfg.root.Client.SSOLoginURL(cfg.ssoOrg, srv.URL(), state)
+	if ok := utils.Open(url); !ok {
+		logger.Log("Visit %s to finish logging in", logger.Blue("%s", url))
+	} else {
+		logger.Log("Opening %s in your browser...", logger.Blue("%s", url))
+	}
+
+	var code string
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-srv.Err():
+		return err
+	case code = <-srv.Code():
+	}
+
+	resp, err := cfg.root.Client.ExchangeSSOCode(ctx, api.ExchangeSSOCodeRequest{
+		OrgSlug: cfg.ssoOrg,
+		Code:    code,
+	})
+	if err != nil {
+		return errors.Wrap(err, "completing SSO login")
+	}
+	return saveSession(cfg.root, cfg.ssoOrg, resp)
+}
+
+// loginSSODevice logs in with a code that the user enters on another device.
+func loginSSODevice(ctx context.Context, cfg config) error {
+	start, err := cfg.root.Client.StartSSODeviceAuth(ctx, api.StartSSODeviceAuthRequest{
+		OrgSlug: cfg.ssoOrg,
+	})
+	if err != nil {
+		return errors.Wrap(err, "starting SSO login")
+	}
+
+	logger.Log("To finish logging in, visit %s and enter the code %s", logger.Blue("%s", start.VerificationURL), logger.Bold(start.UserCode))
+
+	interval := time.Duration(start.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = defaultDevicePollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		resp, err := cfg.root.Client.PollSSODeviceAuth(ctx, api.PollSSODeviceAuthRequest{
+			DeviceCode: start.DeviceCode,
+		})
+		if err != nil {
+			return errors.Wrap(err, "completing SSO login")
+		}
+		if !resp.Pending {
+			return saveSession(cfg.root, cfg.ssoOrg, resp.SSOSessionResponse)
+		}
+		if !start.ExpiresAt.IsZero() && time.Now().After(start.ExpiresAt) {
+			return errors.New("the login code expired before the login was completed. Please run airplane login again")
+		}
+	}
+}
+
+// currentSession returns the SSO session of the client's host, if its token was issued by one.
+func currentSession(c *cli.Config) (conf.Session, bool) {
+	userConf, err := conf.ReadDefaultUserConfig()
+	if err != nil {
+		return conf.Session{}, false
+	}
+	session, ok := userConf.Sessions[c.Client.Host()]
+	if !ok || session.RefreshToken == "" || userConf.Tokens[c.Client.Host()] != c.Client.Token() {
+		return conf.Session{}, false
+	}
+	return session, true
+}
+
+// refreshSession exchanges the refresh token of session for a new token, and returns whether the
+// refresh succeeded. If the session can no longer be refreshed, the user has to log in again.
+func refreshSession(ctx context.Context, c *cli.Config, session conf.Session) (bool, error) {
+	resp, err := c.Client.RefreshSSOSession(ctx, api.RefreshSSOSessionRequest{
+		RefreshToken: session.RefreshToken,
+	})
+	var errsc libhttp.ErrStatusCode
+	if errors.As(err, &errsc) && errsc.StatusCode == 401 {
+		logger.Debug("SSO session can no longer be refreshed. Re-authenticating.")
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrap(err, "refreshing SSO session")
+	}
+
+	if err := saveSession(c, session.OrgSlug, resp); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func saveSession(c *cli.Config, orgSlug string, resp api.SSOSessionResponse) error {
+	return saveToken(c, resp.Token, &conf.Session{
+		OrgSlug:      orgSlug,
+		RefreshToken: resp.RefreshToken,
+		ExpiresAt:    resp.ExpiresAt,
+	})
+}
+
+// randomState returns an unguessable value that ties an SSO callback to this login attempt.
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "generating login state")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,61 @@
+package login
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTokenRefreshesSession(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	t.Setenv("HOME", t.TempDir())
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	client := api.NewMockClient()
+	client.SSOSessions = map[string]api.SSOSessionResponse{
+		"refresh1": {Token: "token2", RefreshToken: "refresh2", ExpiresAt: expiresAt},
+	}
+	c := &cli.Config{Client: client}
+
+	require.NoError(saveToken(c, "token1", &conf.Session{
+		OrgSlug:      "my-org",
+		RefreshToken: "refresh1",
+		ExpiresAt:    time.Now().Add(-time.Minute),
+	}))
+
+	ok, err := validateToken(ctx, c)
+	require.NoError(err)
+	require.True(ok)
+	require.Equal("token2", client.Token())
+
+	userConf, err := conf.ReadDefaultUserConfig()
+	require.NoError(err)
+	require.Equal("token2", userConf.Tokens[client.Host()])
+	require.Equal(conf.Session{
+		OrgSlug:      "my-org",
+		RefreshToken: "refresh2",
+		ExpiresAt:    expiresAt,
+	}, userConf.Sessions[client.Host()])
+
+	// Once the refresh token is no longer valid, the user has to log in again.
+	require.NoError(saveToken(c, "token3", &conf.Session{
+		OrgSlug:      "my-org",
+		RefreshToken: "revoked",
+		ExpiresAt:    time.Now().Add(-time.Minute),
+	}))
+	ok, err = validateToken(ctx, c)
+	require.NoError(err)
+	require.False(ok)
+
+	// Logging in with a token replaces the session.
+	require.NoError(saveToken(c, "token4", nil))
+	userConf, err = conf.ReadDefaultUserConfig()
+	require.NoError(err)
+	require.Empty(userConf.Sessions)
+}
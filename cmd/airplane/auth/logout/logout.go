@@ -22,15 +22,17 @@ func New(c *cli.Config) *cobra.Command {
 }
 
 func run(ctx context.Context, c *cli.Config) error {
-	cfg, err := conf.ReadDefaultUserConfig()
+	_, err := conf.ReadDefaultUserConfig()
 	if !errors.Is(err, conf.ErrMissing) {
 		if err != nil {
 			return err
 		}
 
-		delete(cfg.Tokens, c.Client.Host())
-
-		if err := conf.WriteDefaultUserConfig(cfg); err != nil {
+		if err := conf.UpdateDefaultUserConfig(func(cfg *conf.UserConfig) error {
+			delete(cfg.Tokens, c.Client.Host())
+			delete(cfg.Sessions, c.Client.Host())
+			return nil
+		}); err != nil {
 			return err
 		}
 	}
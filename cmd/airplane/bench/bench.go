@@ -0,0 +1,31 @@
+package bench
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/bench/deploy"
+	"github.com/airplanedev/cli/cmd/airplane/bench/discovery"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Benchmark CLI commands",
+		Long: heredoc.Doc(`
+			Measures the wall time and allocations of CLI commands over the current directory, and
+			compares them against stored baselines.
+		`),
+		Example: heredoc.Doc(`
+			airplane bench discovery --iterations 10
+			airplane bench deploy --save
+			airplane bench deploy --budget 10 --fail
+		`),
+	}
+
+	cmd.AddCommand(discovery.New(c))
+	cmd.AddCommand(deploy.New(c))
+
+	return cmd
+}
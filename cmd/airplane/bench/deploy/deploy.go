@@ -0,0 +1,109 @@
+package deploy
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/bench"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/deploy/archive"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root  *cli.Config
+	paths []string
+	opts  bench.Options
+}
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "deploy [path ...]",
+		Short: "Benchmark a dry-run deploy",
+		Long: heredoc.Doc(`
+			Measures a dry-run deploy of the given paths, which default to the current directory.
+			Bundles are discovered and packaged as they would be by "airplane deploy", but nothing
+			is uploaded or deployed.
+		`),
+		Example: heredoc.Doc(`
+			airplane bench deploy
+			airplane bench deploy ./tasks --iterations 3 --budget 10 --fail
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cfg.opts.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	report, err := bench.Run(ctx, "deploy", cfg.opts, func(ctx context.Context) error {
+		return dryRunDeploy(ctx, cfg.root.Client, cfg.paths...)
+	})
+	if err != nil {
+		return err
+	}
+	return report.Print(cfg.opts)
+}
+
+// dryRunDeploy discovers the bundles in paths and packages each of them, without verifying that
+// their tasks and views exist in Airplane or uploading the packages.
+func dryRunDeploy(ctx context.Context, client libapi.IAPIClient, paths ...string) error {
+	l := logger.NewNoopLogger()
+	d := &bundlediscover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingTasks: true,
+			},
+		},
+		ViewDiscoverers: []discover.ViewDiscoverer{
+			&discover.ViewDefnDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingViews: true,
+			},
+			&discover.CodeViewDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingViews: true,
+			},
+		},
+		Client: client,
+		Logger: l,
+	}
+	bundles, err := d.Discover(ctx, paths...)
+	if err != nil {
+		return errors.Wrap(err, "discovering bundles")
+	}
+
+	archiver := archive.NewLocalArchiver()
+	for _, b := range bundles {
+		if _, err := archiver.Archive(ctx, b.RootPath); err != nil {
+			return errors.Wrapf(err, "packaging %s", b.RootPath)
+		}
+	}
+	return nil
+}
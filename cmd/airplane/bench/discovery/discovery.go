@@ -0,0 +1,102 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/bench"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root        *cli.Config
+	paths       []string
+	opts        bench.Options
+	concurrency int
+}
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "discovery [path ...]",
+		Short: "Benchmark task and view discovery",
+		Long: heredoc.Doc(`
+			Measures discovering the tasks and views in the given paths, which default to the
+			current directory. Discovered entities are not looked up in Airplane.
+		`),
+		Example: heredoc.Doc(`
+			airplane bench discovery
+			airplane bench discovery ./tasks --iterations 10
+			airplane bench discovery --discovery-concurrency 1
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cfg.opts.AddFlags(cmd.Flags())
+	cmd.Flags().IntVar(&cfg.concurrency, "discovery-concurrency", discover.DefaultConcurrency, "The maximum number of files to inspect at once.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	report, err := bench.Run(ctx, "discovery", cfg.opts, func(ctx context.Context) error {
+		return discoverEntities(ctx, cfg.root.Client, cfg.concurrency, cfg.paths...)
+	})
+	if err != nil {
+		return err
+	}
+	return report.Print(cfg.opts)
+}
+
+// discoverEntities discovers the tasks and views in paths, up to concurrency files at a time,
+// without verifying that they exist in Airplane.
+func discoverEntities(ctx context.Context, client libapi.IAPIClient, concurrency int, paths ...string) error {
+	l := logger.NewNoopLogger()
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingTasks: true,
+			},
+		},
+		ViewDiscoverers: []discover.ViewDiscoverer{
+			&discover.ViewDefnDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingViews: true,
+			},
+			&discover.CodeViewDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingViews: true,
+			},
+		},
+		Client:      client,
+		Logger:      l,
+		Concurrency: concurrency,
+	}
+	if _, _, err := d.Discover(ctx, paths...); err != nil {
+		return errors.Wrap(err, "discovering tasks and views")
+	}
+	return nil
+}
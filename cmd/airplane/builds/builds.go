@@ -0,0 +1,31 @@
+package builds
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/builds/debug"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "builds",
+		Short:   "Manage builds",
+		Long:    "Manage builds",
+		Aliases: []string{"build"},
+		Example: heredoc.Doc(`
+			airplane builds debug <id>
+			airplane builds debug <id> --rerun
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+	}
+
+	cmd.AddCommand(debug.New(c))
+
+	return cmd
+}
@@ -0,0 +1,294 @@
+package debug
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Files written to the debugging bundle.
+const (
+	buildFile       = "build.json"
+	manifestFile    = "manifest.json"
+	dockerfileFile  = "Dockerfile"
+	logsFile        = "failed-step.log"
+	reproDockerfile = "Dockerfile.repro"
+)
+
+// maxReportedDrifts is the maximum number of files that differ from the build context that
+// --rerun lists.
+const maxReportedDrifts = 20
+
+type config struct {
+	buildID    string
+	dir        string
+	rerun      bool
+	contextDir string
+}
+
+// runDocker runs the docker CLI with the given arguments. It is a variable so that tests can
+// replace it.
+var runDocker = func(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// New returns a new debug command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "debug <id>",
+		Short: "Download the context of a failed build for debugging",
+		Long: heredoc.Doc(`
+			Downloads the generated Dockerfile, the manifest of the build context and the logs of
+			the failing step of a remote build into a local directory.
+
+			With --rerun, the Dockerfile is built locally up to and including the failing step,
+			from the same base image and the local copy of the build context. Files that differ
+			from the remote build context are reported first, since they are a common cause of
+			failures that only happen remotely. Build args are not set, as their values are not
+			downloaded.
+		`),
+		Example: heredoc.Doc(`
+			airplane builds debug <id>
+			airplane builds debug <id> --dir ./build-debug
+			airplane builds debug <id> --rerun --context ./my_task
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.buildID = args[0]
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.dir, "dir", "", "The directory to download to. Defaults to build-<id>.")
+	cmd.Flags().BoolVar(&cfg.rerun, "rerun", false, "Re-run the failing step locally with Docker.")
+	cmd.Flags().StringVar(&cfg.contextDir, "context", ".", "The local directory to use as the build context with --rerun.")
+
+	return cmd
+}
+
+func run(ctx context.Context, c *cli.Config, cfg config) error {
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+	if cfg.dir == "" {
+		cfg.dir = "build-" + cfg.buildID
+	}
+
+	build, files, err := download(ctx, c.Client, l, cfg.buildID, cfg.dir)
+	if err != nil {
+		return err
+	}
+	if !cfg.rerun {
+		return nil
+	}
+	return rerun(ctx, l, build, files, cfg.dir, cfg.contextDir)
+}
+
+// download writes the debugging bundle of a failed build to dir.
+func download(ctx context.Context, client api.APIClient, l logger.Logger, buildID, dir string) (api.Build, []api.BuildContextFile, error) {
+	build, err := client.GetBuild(ctx, buildID)
+	if err != nil {
+		return api.Build{}, nil, errors.Wrap(err, "getting build")
+	}
+	if build.Status != api.BuildFailed {
+		return api.Build{}, nil, errors.Errorf("build %s has not failed (status: %s)", buildID, build.Status)
+	}
+	manifest, err := client.GetBuildContextManifest(ctx, buildID)
+	if err != nil {
+		return api.Build{}, nil, errors.Wrap(err, "getting build context manifest")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return api.Build{}, nil, errors.Wrap(err, "creating directory")
+	}
+	if err := writeJSON(filepath.Join(dir, buildFile), build); err != nil {
+		return api.Build{}, nil, err
+	}
+	if err := writeJSON(filepath.Join(dir, manifestFile), manifest.Files); err != nil {
+		return api.Build{}, nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, dockerfileFile), []byte(build.Dockerfile), 0644); err != nil {
+		return api.Build{}, nil, errors.Wrap(err, "writing Dockerfile")
+	}
+
+	if build.FailedStep != nil {
+		logs, err := getStepLogs(ctx, client, buildID, build.FailedStep.Index)
+		if err != nil {
+			return api.Build{}, nil, err
+		}
+		if err := writeLogs(filepath.Join(dir, logsFile), logs); err != nil {
+			return api.Build{}, nil, err
+		}
+	}
+
+	l.Log("Downloaded build %s to %s", buildID, dir)
+	if build.FailedReason != "" {
+		l.Log("Failure reason: %s", build.FailedReason)
+	}
+	if build.FailedStep != nil {
+		l.Log("Failed at step %d: %s", build.FailedStep.Index+1, build.FailedStep.Instruction)
+		l.Log("Logs of the failed step are in %s", filepath.Join(dir, logsFile))
+	}
+	return build, manifest.Files, nil
+}
+
+// getStepLogs pages through all of the logs of a build step.
+func getStepLogs(ctx context.Context, client api.APIClient, buildID string, step int) ([]api.LogItem, error) {
+	var logs []api.LogItem
+	var prevToken string
+	for {
+		resp, err := client.GetBuildLogs(ctx, buildID, step, prevToken)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting build logs")
+		}
+		logs = append(logs, resp.Logs...)
+		if len(resp.Logs) == 0 || resp.PrevPageToken == "" || resp.PrevPageToken == prevToken {
+			break
+		}
+		prevToken = resp.PrevPageToken
+	}
+	api.SortLogs(logs)
+	return logs, nil
+}
+
+// rerun builds the Dockerfile of build locally, up to and including the step that failed remotely.
+func rerun(ctx context.Context, l logger.Logger, build api.Build, files []api.BuildContextFile, dir, contextDir string) error {
+	if build.FailedStep == nil {
+		return errors.New("the build did not fail at a Dockerfile step, so there is no step to re-run")
+	}
+
+	dockerfile, err := truncateDockerfile(build.Dockerfile, build.FailedStep.Index)
+	if err != nil {
+		return err
+	}
+	dockerfilePath := filepath.Join(dir, reproDockerfile)
+	if err := os.WriteFile(dockerfilePath, []byte(dockerfile), 0644); err != nil {
+		return errors.Wrap(err, "writing Dockerfile")
+	}
+
+	drifts, err := contextDrift(contextDir, files)
+	if err != nil {
+		return err
+	}
+	if len(drifts) > 0 {
+		l.Warning("%d files in %s differ from the remote build context:", len(drifts), contextDir)
+		for i, d := range drifts {
+			if i == maxReportedDrifts {
+				l.Warning("  ... and %d more", len(drifts)-maxReportedDrifts)
+				break
+			}
+			l.Warning("  %s", d)
+		}
+	}
+
+	l.Log("Re-running the build up to step %d from %s", build.FailedStep.Index+1, build.BaseImage)
+	err = runDocker(ctx, "build", "--platform", "linux/amd64", "--progress", "plain", "--file", dockerfilePath, contextDir)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		l.Log("Reproduced the failure locally.")
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "running docker build")
+	}
+	l.Log("The failed step succeeded locally. The failure may depend on the remote build environment, its build args, or files that differ from the build context.")
+	return nil
+}
+
+// truncateDockerfile returns the instructions of a Dockerfile up to and including the instruction
+// with the given zero-based index. Comments and parser directives before that instruction are kept.
+func truncateDockerfile(dockerfile string, index int) (string, error) {
+	// Dockerfiles may inline large files on a single line, so they're split rather than scanned.
+	lines := strings.Split(dockerfile, "\n")
+	instruction := 0
+	continued := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !continued && (trimmed == "" || strings.HasPrefix(trimmed, "#")) {
+			continue
+		}
+		continued = strings.HasSuffix(trimmed, `\`)
+		if continued {
+			continue
+		}
+		if instruction == index {
+			return strings.Join(lines[:i+1], "\n") + "\n", nil
+		}
+		instruction++
+	}
+	return "", errors.Errorf("Dockerfile has %d steps, expected at least %d", instruction, index+1)
+}
+
+// contextDrift describes the files of a remote build context that are missing or different in the
+// local directory root.
+func contextDrift(root string, files []api.BuildContextFile) ([]string, error) {
+	var drifts []string
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f.Path))
+		size, hash, err := hashFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			drifts = append(drifts, fmt.Sprintf("%s: missing", f.Path))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if size != f.Size || hash != f.SHA256 {
+			drifts = append(drifts, fmt.Sprintf("%s: changed", f.Path))
+		}
+	}
+	return drifts, nil
+}
+
+func hashFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "hashing %s", path)
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "marshaling %s", filepath.Base(path))
+	}
+	if err := os.WriteFile(path, append(buf, '\n'), 0644); err != nil {
+		return errors.Wrapf(err, "writing %s", filepath.Base(path))
+	}
+	return nil
+}
+
+func writeLogs(path string, logs []api.LogItem) error {
+	var b strings.Builder
+	for _, log := range logs {
+		b.WriteString(log.Text)
+		b.WriteString("\n")
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return errors.Wrap(err, "writing logs")
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package debug
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+const dockerfile = `FROM node:18
+
+WORKDIR /airplane
+
+# Install dependencies.
+COPY package.json /airplane
+RUN npm install \
+	--production
+
+COPY . /airplane
+RUN npm run build
+`
+
+func TestTruncateDockerfile(t *testing.T) {
+	require := require.New(t)
+
+	out, err := truncateDockerfile(dockerfile, 0)
+	require.NoError(err)
+	require.Equal("FROM node:18\n", out)
+
+	out, err = truncateDockerfile(dockerfile, 3)
+	require.NoError(err)
+	require.Equal(`FROM node:18
+
+WORKDIR /airplane
+
+# Install dependencies.
+COPY package.json /airplane
+RUN npm install \
+	--production
+`, out)
+
+	out, err = truncateDockerfile(dockerfile, 5)
+	require.NoError(err)
+	require.Equal(dockerfile, out)
+
+	_, err = truncateDockerfile(dockerfile, 6)
+	require.Error(err)
+}
+
+func TestDebug(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	contextDir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(contextDir, "package.json"), []byte("{}"), 0644))
+	client := &api.MockClient{
+		Builds: map[string]api.Build{
+			"bld1": {
+				ID:           "bld1",
+				Status:       api.BuildFailed,
+				FailedReason: "npm install exited with code 1",
+				Dockerfile:   dockerfile,
+				BaseImage:    "node:18",
+				FailedStep:   &api.BuildStep{Index: 3, Instruction: "RUN npm install --production"},
+			},
+			"bld2": {ID: "bld2", Status: api.BuildSucceeded},
+		},
+		BuildContextFiles: map[string][]api.BuildContextFile{
+			"bld1": {
+				{Path: "package.json", Size: 2, SHA256: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"},
+				{Path: "main.ts", Size: 10, SHA256: "abc"},
+			},
+		},
+		BuildLogs: map[string][]api.LogItem{
+			"bld1": {
+				{Timestamp: time.Unix(2, 0), InsertID: "2", Text: "npm ERR! missing script"},
+				{Timestamp: time.Unix(1, 0), InsertID: "1", Text: "npm install"},
+			},
+		},
+	}
+	l := logger.NewNoopLogger()
+
+	_, _, err := download(ctx, client, l, "bld2", t.TempDir())
+	require.ErrorContains(err, "has not failed")
+
+	dir := filepath.Join(t.TempDir(), "bundle")
+	build, files, err := download(ctx, client, l, "bld1", dir)
+	require.NoError(err)
+	for _, name := range []string{buildFile, manifestFile, dockerfileFile} {
+		require.FileExists(filepath.Join(dir, name))
+	}
+	logs, err := os.ReadFile(filepath.Join(dir, logsFile))
+	require.NoError(err)
+	require.Equal("npm install\nnpm ERR! missing script\n", string(logs))
+
+	drifts, err := contextDrift(contextDir, files)
+	require.NoError(err)
+	require.Equal([]string{"main.ts: missing"}, drifts)
+
+	var dockerArgs []string
+	defer func(orig func(context.Context, ...string) error) { runDocker = orig }(runDocker)
+	runDocker = func(ctx context.Context, args ...string) error {
+		dockerArgs = args
+		return &exec.ExitError{}
+	}
+	require.NoError(rerun(ctx, l, build, files, dir, contextDir))
+	require.Equal(contextDir, dockerArgs[len(dockerArgs)-1])
+	repro, err := os.ReadFile(filepath.Join(dir, reproDockerfile))
+	require.NoError(err)
+	require.Contains(string(repro), "RUN npm install")
+	require.NotContains(string(repro), "npm run build")
+}
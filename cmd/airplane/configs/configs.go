@@ -3,8 +3,12 @@ package configs
 import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/configs/delete"
+	"github.com/airplanedev/cli/cmd/airplane/configs/explain"
 	"github.com/airplanedev/cli/cmd/airplane/configs/get"
+	"github.com/airplanedev/cli/cmd/airplane/configs/list"
 	"github.com/airplanedev/cli/cmd/airplane/configs/set"
+	"github.com/airplanedev/cli/cmd/airplane/configs/sync"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/spf13/cobra"
@@ -12,13 +16,23 @@ import (
 
 func New(c *cli.Config) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "configs",
-		Short:   "Manage config variables",
-		Long:    "Manage config variables",
+		Use:   "configs",
+		Short: "Manage config variables",
+		Long: heredoc.Doc(`
+			Manage config variables.
+
+			config is an alias of configs. ` + "`airplane config explain <key>`" + ` explains
+			a local CLI setting, such as the API host or default environment, rather than
+			a config variable.
+		`),
 		Aliases: []string{"config"},
 		Example: heredoc.Doc(`
 			$ airplane configs set my_database_url postgresql://my_database
 			$ airplane configs get my_config_name
+			$ airplane configs list --env prod
+			$ airplane configs delete my_config_name
+			$ airplane configs sync .env --secret
+			$ airplane config explain env
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -27,6 +41,10 @@ func New(c *cli.Config) *cobra.Command {
 
 	cmd.AddCommand(set.New(c))
 	cmd.AddCommand(get.New(c))
+	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(delete.New(c))
+	cmd.AddCommand(sync.New(c))
+	cmd.AddCommand(explain.New(c))
 
 	return cmd
 }
@@ -0,0 +1,95 @@
+package delete //nolint: predeclared
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/configs"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root *cli.Config
+
+	names     []string
+	envSlug   string
+	assumeYes bool
+}
+
+// New returns a new delete command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+	cmd := &cobra.Command{
+		Use:   "delete <name>...",
+		Short: "Deletes one or more config variables",
+		Example: heredoc.Doc(`
+			$ airplane configs delete my_config
+			$ airplane configs delete my_config:tag other_config --env prod --yes
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.names = args
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "Delete without asking for confirmation.")
+	return cmd
+}
+
+// Run runs the delete command.
+func run(ctx context.Context, cfg config) error {
+	var client = cfg.root.Client
+
+	nts := make([]configs.NameTag, 0, len(cfg.names))
+	for _, name := range cfg.names {
+		nt, err := configs.ParseName(name)
+		if err != nil {
+			return errors.Errorf("invalid config name: %s - expected my_config or my_config:tag", name)
+		}
+		nts = append(nts, nt)
+	}
+
+	question := "Delete config " + cfg.names[0] + "?"
+	if len(cfg.names) > 1 {
+		question = "Delete these configs?"
+	}
+	if ok, err := cfg.root.Prompter.ConfirmWithAssumptions(question, cfg.assumeYes, false); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	for _, nt := range nts {
+		// Configs are deleted by ID, so look up the config of the name and tag first.
+		resp, err := client.GetConfig(ctx, api.GetConfigRequest{
+			Name:    nt.Name,
+			Tag:     nt.Tag,
+			EnvSlug: cfg.envSlug,
+		})
+		if err != nil {
+			var errsc libhttp.ErrStatusCode
+			if errors.As(err, &errsc) && errsc.StatusCode == 404 {
+				logger.Log("  Config %s not found.", configs.JoinName(nt))
+				continue
+			}
+			return errors.Wrap(err, "getting config")
+		}
+		logger.Log("  Deleting %s...", logger.Red(configs.JoinName(nt)))
+		if err := client.DeleteConfig(ctx, api.DeleteConfigRequest{
+			ID:      resp.Config.ID,
+			EnvSlug: cfg.envSlug,
+		}); err != nil {
+			return errors.Wrap(err, "deleting config")
+		}
+	}
+	logger.Log("  Done.")
+	return nil
+}
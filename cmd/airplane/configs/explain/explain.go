@@ -0,0 +1,84 @@
+package explain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new explain command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain <key>",
+		Short: "Explain where a CLI setting's value comes from",
+		Long: heredoc.Doc(fmt.Sprintf(`
+			Report the effective value of a CLI setting and which source provided it.
+
+			Sources are consulted in order: flags, env vars, the user config file
+			(~/.airplane/config), the project config file (airplane.yaml) and finally
+			the built-in default.
+
+			Available settings: %s
+		`, strings.Join(conf.SettingKeys(), ", "))),
+		Example: heredoc.Doc(`
+			$ airplane config explain host
+			$ airplane config explain env
+		`),
+		Args: cobra.ExactArgs(1),
+		// Settings are local, so unlike the other configs commands, explain doesn't require
+		// logging in: only the root command's hook runs.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if root := cmd.Root(); root.PersistentPreRunE != nil {
+				return root.PersistentPreRunE(cmd, args)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(c, args[0])
+		},
+	}
+	return cmd
+}
+
+func run(c *cli.Config, key string) error {
+	e, err := c.Resolver.Explain(key)
+	if err != nil {
+		return err
+	}
+
+	print.Print(e, func() {
+		if e.Source == "" {
+			logger.Log("%s is not set", e.Key)
+		} else {
+			logger.Log("%s = %s", e.Key, e.Value.Value)
+			logger.Log("  from %s%s", e.Source, formatOrigin(e.Origin))
+		}
+		logger.Log("")
+		logger.Log("Sources, in order of precedence:")
+		for _, cand := range e.Candidates {
+			value := logger.Gray("(not set)")
+			if cand.IsSet {
+				value = cand.Value
+			}
+			marker := " "
+			if cand.IsSet && cand.Source == e.Source {
+				marker = "*"
+			}
+			logger.Log("%s %s%s: %s", marker, cand.Source, formatOrigin(cand.Origin), value)
+		}
+	})
+	return nil
+}
+
+func formatOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", origin)
+}
@@ -0,0 +1,65 @@
+package list
+
+import (
+	"context"
+	"sort"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root *cli.Config
+
+	showSecrets bool
+	envSlug     string
+}
+
+// New returns a new list command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists config variables",
+		Example: heredoc.Doc(`
+			$ airplane configs list
+			$ airplane configs list --env prod -o json
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+	cmd.Flags().BoolVar(&cfg.showSecrets, "show-secrets", false, "Include the values of secrets.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	return cmd
+}
+
+// Run runs the list command.
+func run(ctx context.Context, cfg config) error {
+	var client = cfg.root.Client
+
+	resp, err := client.ListConfigs(ctx, api.ListConfigsRequest{
+		ShowSecrets: cfg.showSecrets,
+		EnvSlug:     cfg.envSlug,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing configs")
+	}
+
+	configs := resp.Configs
+	sort.Slice(configs, func(i, j int) bool {
+		if configs[i].Name != configs[j].Name {
+			return configs[i].Name < configs[j].Name
+		}
+		return configs[i].Tag < configs[j].Tag
+	})
+	print.Configs(configs)
+	return nil
+}
@@ -2,6 +2,7 @@ package set
 
 import (
 	"context"
+	"os"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/pkg/cli"
@@ -15,6 +16,7 @@ type config struct {
 
 	name    string
 	value   *string
+	file    string
 	secret  bool
 	envSlug string
 }
@@ -35,12 +37,18 @@ func New(c *cli.Config) *cobra.Command {
 			# Pass in a value by piping it in via stdin
 			$ cat my_secret_value.txt | airplane configs set --secret secret_config
 
+			# Pass in a value from a file
+			$ airplane configs set --secret --file ./service_account.json gcp_credentials
+
 			# Recommended for non-secrets only - pass in a value via arguments
 			$ airplane configs set nonsecret_config my_value
 		`),
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 2 {
+				if cfg.file != "" {
+					return errors.New("only one of a value and --file may be set")
+				}
 				cfg.value = &args[1]
 			}
 			cfg.name = args[0]
@@ -48,6 +56,7 @@ func New(c *cli.Config) *cobra.Command {
 		},
 	}
 	cmd.Flags().BoolVar(&cfg.secret, "secret", false, "Whether to set config var as a secret")
+	cmd.Flags().StringVar(&cfg.file, "file", "", "A file to read the config value from.")
 	// Unhide this flag once we release environments.
 	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
 	return cmd
@@ -65,6 +74,13 @@ func run(ctx context.Context, c *cli.Config, cfg config) error {
 	var value string
 	if cfg.value != nil {
 		value = *cfg.value
+	} else if cfg.file != "" {
+		buf, err := os.ReadFile(cfg.file)
+		if err != nil {
+			return errors.Wrap(err, "reading config value")
+		}
+		// The file is the value as it is, e.g. a key that must end with a newline.
+		value = string(buf)
 	} else {
 		var err error
 		value, err = configs.ReadValue(cfg.secret, cfg.root.Prompter)
@@ -0,0 +1,125 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/configs"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root *cli.Config
+
+	file      string
+	secret    bool
+	prune     bool
+	dryRun    bool
+	envSlug   string
+	assumeYes bool
+}
+
+// New returns a new sync command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+	cmd := &cobra.Command{
+		Use:   "sync <file>",
+		Short: "Sync config variables with a dotenv or YAML file",
+		Long: heredoc.Doc(`
+			Creates and updates config variables so that they match the variables declared in a
+			dotenv or YAML file. Config variables that aren't declared in the file are only deleted
+			if --prune is set. The values of secrets aren't read, so declared secrets are always
+			updated.
+
+			YAML files map config names to a value, or to an object with a value and whether the
+			config is a secret:
+
+			  db_host: localhost
+			  db_password:
+			    value: hunter2
+			    secret: true
+		`),
+		Example: heredoc.Doc(`
+			$ airplane configs sync .env --secret
+			$ airplane configs sync configs.yaml --env prod --dry-run
+			$ airplane configs sync configs.yaml --env prod --prune --yes
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.file = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+	cmd.Flags().BoolVar(&cfg.secret, "secret", false, "Whether to set config vars as secrets, unless the file says otherwise.")
+	cmd.Flags().BoolVar(&cfg.prune, "prune", false, "Delete config vars that aren't declared in the file.")
+	cmd.Flags().BoolVar(&cfg.dryRun, "dry-run", false, "Print the changes that syncing would make, without making them.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "Delete config vars without asking for confirmation.")
+	return cmd
+}
+
+// Run runs the sync command.
+func run(ctx context.Context, cfg config) error {
+	var client = cfg.root.Client
+
+	local, err := configs.ReadConfigFile(cfg.file, cfg.secret)
+	if err != nil {
+		return err
+	}
+	resp, err := client.ListConfigs(ctx, api.ListConfigsRequest{
+		EnvSlug: cfg.envSlug,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing configs")
+	}
+
+	changes := configs.PlanSync(local, resp.Configs, cfg.prune)
+	var toApply []configs.SyncChange
+	var deletes int
+	for _, c := range changes {
+		name := configs.JoinName(c.Config.NameTag)
+		switch c.Action {
+		case configs.SyncCreate:
+			logger.Log("%s %s", logger.Green("+"), logger.Bold(name))
+		case configs.SyncUpdate:
+			logger.Log("%s %s", logger.Yellow("~"), logger.Bold(name))
+		case configs.SyncDelete:
+			logger.Log("%s %s", logger.Red("-"), logger.Bold(name))
+			deletes++
+		case configs.SyncUnchanged:
+			logger.Debug("  %s", name)
+			continue
+		}
+		toApply = append(toApply, c)
+	}
+
+	if len(toApply) == 0 {
+		logger.Log("Config vars are up to date.")
+		return nil
+	}
+	if cfg.dryRun {
+		logger.Log("")
+		logger.Log("Dry run: %d change(s), nothing was synced.", len(toApply))
+		return nil
+	}
+	if deletes > 0 {
+		if ok, err := cfg.root.Prompter.ConfirmWithAssumptions("Delete config vars that aren't in the file?", cfg.assumeYes, false); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+	}
+
+	logger.Log("")
+	if err := configs.ApplySync(ctx, client, cfg.envSlug, toApply); err != nil {
+		return err
+	}
+	logger.Log("Synced %d config var(s).", len(toApply))
+	return nil
+}
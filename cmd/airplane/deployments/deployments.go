@@ -0,0 +1,31 @@
+package deployments
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/deployments/rollback"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "deployments",
+		Short:   "Manage deployments",
+		Long:    "Manage deployments",
+		Aliases: []string{"deployment"},
+		Example: heredoc.Doc(`
+			airplane deployments rollback <id>
+			airplane deployments rollback --last
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+	}
+
+	cmd.AddCommand(rollback.New(c))
+
+	return cmd
+}
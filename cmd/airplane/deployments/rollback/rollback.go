@@ -0,0 +1,189 @@
+package rollback
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root *cli.Config
+
+	deploymentID string
+	last         bool
+	envSlug      string
+	assumeYes    bool
+}
+
+// New returns a new rollback command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "rollback <id|--last>",
+		Short: "Rolls back the tasks of a deployment",
+		Long: heredoc.Doc(`
+			Rolls back the tasks of a deployment by re-activating the revisions that were active
+			before it, without deploying them again. The deployment must be one of the recent
+			successful deployments to the environment given by --env.
+
+			Tasks that the deployment created, and tasks that were changed after it, are left as
+			they are. Views aren't rolled back: redeploy them from the version to roll back to.
+		`),
+		Example: heredoc.Doc(`
+			airplane deployments rollback <id>
+			airplane deployments rollback --last
+			airplane deployments rollback --last --env staging --yes
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				cfg.deploymentID = args[0]
+			}
+			if (cfg.deploymentID == "") == !cfg.last {
+				return errors.New("expected a deployment ID or --last")
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cfg.last, "last", false, "Roll back the most recent successful deployment to the environment.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "Roll back without asking for confirmation.")
+
+	return cmd
+}
+
+// step is the rollback of a single task. Steps with a skip reason leave the task as it is.
+type step struct {
+	revision api.DeploymentRevision
+	skip     string
+}
+
+func run(ctx context.Context, cfg config) error {
+	client := cfg.root.Client
+	d, err := getDeployment(ctx, client, cfg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ListDeploymentRevisions(ctx, d.ID)
+	if err != nil {
+		return errors.Wrap(err, "listing deployment revisions")
+	}
+	steps := plan(resp.Revisions)
+
+	var n int
+	logger.Log("Rolling back deployment %s:", logger.Bold(d.ID))
+	for _, s := range steps {
+		if s.skip != "" {
+			logger.Log("  %s: %s", s.revision.TaskSlug, logger.Gray("skipped, %s", s.skip))
+			continue
+		}
+		logger.Log("  %s: %s -> %s", s.revision.TaskSlug, s.revision.RevisionID, s.revision.PreviousRevisionID)
+		n++
+	}
+	if n == 0 {
+		logger.Log("There are no tasks to roll back.")
+		return nil
+	}
+
+	question := "Roll back " + pluralize(n) + "?"
+	if ok, err := cfg.root.Prompter.ConfirmWithAssumptions(question, cfg.assumeYes, false); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	var failed int
+	for _, s := range steps {
+		if s.skip != "" {
+			continue
+		}
+		if err := client.ActivateTaskRevision(ctx, api.ActivateTaskRevisionRequest{
+			TaskID:     s.revision.TaskID,
+			RevisionID: s.revision.PreviousRevisionID,
+			EnvSlug:    cfg.envSlug,
+		}); err != nil {
+			logger.Warning("Failed to roll back task %s: %s", s.revision.TaskSlug, err)
+			failed++
+		}
+	}
+	logger.Log("Rolled back %s. Views aren't rolled back, redeploy them to roll them back.", pluralize(n-failed))
+	if failed > 0 {
+		return errors.Errorf("failed to roll back %s", pluralize(failed))
+	}
+	return nil
+}
+
+// recentDeployments is the number of recent deployments to the environment that a rollback looks
+// for the deployment in.
+const recentDeployments = 50
+
+// getDeployment returns the deployment to roll back. Deployments don't say which environment they
+// deployed to, so the deployment is looked up among those of the environment that its revisions
+// are activated in: a deployment to another environment is rejected rather than rolled back in
+// this one.
+func getDeployment(ctx context.Context, client api.APIClient, cfg config) (api.Deployment, error) {
+	resp, err := client.ListDeployments(ctx, api.ListDeploymentsRequest{EnvSlug: cfg.envSlug, Limit: recentDeployments})
+	if err != nil {
+		return api.Deployment{}, errors.Wrap(err, "listing deployments")
+	}
+	for _, d := range resp.Deployments {
+		if cfg.last {
+			// Failed and cancelled deployments didn't activate any revisions.
+			if d.SucceededAt != nil {
+				return d, nil
+			}
+			continue
+		}
+		if d.ID != cfg.deploymentID {
+			continue
+		}
+		switch {
+		case d.SucceededAt != nil:
+			return d, nil
+		case d.FailedAt == nil && d.CancelledAt == nil:
+			return api.Deployment{}, errors.Errorf("deployment %s is still in progress", d.ID)
+		default:
+			return api.Deployment{}, errors.Errorf("deployment %s didn't succeed, so it didn't activate any revisions", d.ID)
+		}
+	}
+	if cfg.last {
+		return api.Deployment{}, errors.New("there are no successful deployments to roll back")
+	}
+	return api.Deployment{}, errors.Errorf("deployment %s isn't one of the last %d deployments to this environment: pass the environment that it deployed to with --env", cfg.deploymentID, recentDeployments)
+}
+
+// plan returns the steps that roll back the revisions that a deployment activated. Tasks that the
+// deployment created have no revision to roll back to, and tasks that were changed after the
+// deployment would lose those changes, so both are skipped.
+func plan(revisions []api.DeploymentRevision) []step {
+	steps := make([]step, 0, len(revisions))
+	for _, r := range revisions {
+		s := step{revision: r}
+		switch {
+		case r.PreviousRevisionID == "":
+			s.skip = "the deployment created it"
+		case r.ActiveRevisionID != r.RevisionID:
+			s.skip = "it was changed after the deployment"
+		}
+		steps = append(steps, s)
+	}
+	return steps
+}
+
+func pluralize(n int) string {
+	if n == 1 {
+		return "1 task"
+	}
+	return strconv.Itoa(n) + " tasks"
+}
@@ -0,0 +1,57 @@
+package rollback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/prompts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollback(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	now := time.Now()
+	client := &api.MockClient{
+		Deployments: []api.Deployment{
+			{ID: "dep4"},
+			{ID: "dep3", FailedAt: &now},
+			{ID: "dep2", SucceededAt: &now},
+			{ID: "dep1", SucceededAt: &now},
+		},
+		DeploymentRevisions: map[string][]api.DeploymentRevision{
+			"dep2": {
+				{TaskID: "tsk1", TaskSlug: "sync_users", RevisionID: "rev2", PreviousRevisionID: "rev1", ActiveRevisionID: "rev2"},
+				{TaskID: "tsk2", TaskSlug: "new_task", RevisionID: "rev3", ActiveRevisionID: "rev3"},
+				{TaskID: "tsk3", TaskSlug: "send_report", RevisionID: "rev5", PreviousRevisionID: "rev4", ActiveRevisionID: "rev6"},
+			},
+		},
+	}
+	cfg := config{
+		root:    &cli.Config{Client: client, Prompter: prompts.NewMock(false, true)},
+		last:    true,
+		envSlug: "prod",
+	}
+
+	// Declining the confirmation doesn't roll anything back.
+	require.NoError(run(ctx, cfg))
+	require.Empty(client.ActivatedRevisions)
+
+	// --last skips deployments that didn't succeed. Only the task that the deployment changed, and
+	// that wasn't changed since, is rolled back.
+	require.NoError(run(ctx, cfg))
+	require.Equal([]api.ActivateTaskRevisionRequest{
+		{TaskID: "tsk1", RevisionID: "rev1", EnvSlug: "prod"},
+	}, client.ActivatedRevisions)
+
+	cfg = config{root: cfg.root, deploymentID: "dep4"}
+	require.EqualError(run(ctx, cfg), "deployment dep4 is still in progress")
+	cfg.deploymentID = "dep3"
+	require.EqualError(run(ctx, cfg), "deployment dep3 didn't succeed, so it didn't activate any revisions")
+	// Deployments to other environments aren't listed for this one.
+	cfg.deploymentID = "dep9"
+	require.EqualError(run(ctx, cfg), "deployment dep9 isn't one of the last 50 deployments to this environment: pass the environment that it deployed to with --env")
+}
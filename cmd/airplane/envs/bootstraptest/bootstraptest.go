@@ -0,0 +1,291 @@
+package bootstraptest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/goccy/go-yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// SlugPrefix is the prefix of the slugs of environments created by bootstrap-test. Only environments
+// with this prefix can be torn down, so that the command can't be used to delete a real environment.
+const SlugPrefix = "test-"
+
+// EnvSlugEnvVar is set to the slug of the test environment when running a command against it.
+const EnvSlugEnvVar = "AIRPLANE_TEST_ENV_SLUG"
+
+type config struct {
+	fixture  string
+	name     string
+	teardown string
+	keep     bool
+	command  []string
+}
+
+// New returns a new bootstrap-test command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "bootstrap-test [-- command...]",
+		Short: "Creates a disposable environment for integration tests",
+		Long: heredoc.Doc(`
+			Creates a disposable environment and seeds it with the resources and config variables
+			declared in a fixture file, so that integration tests run against a consistent,
+			isolated environment.
+
+			If a command is passed after --, it is run with AIRPLANE_TEST_ENV_SLUG set to the slug
+			of the environment, and the environment is torn down once the command exits. Otherwise,
+			the slug is printed and the environment can be torn down later with --teardown.
+
+			Stubs are mock REST APIs that are served locally while the command runs; a REST
+			resource pointing at each stub is created in the environment. Since stubs are local,
+			they can only be reached by tasks that run on this machine, e.g. with airplane dev.
+			There is no SQL stub, since there is no embedded database resource kind.
+
+			Fixture files are YAML:
+
+			  configs:
+			    API_KEY:
+			      value: test-key
+			      secret: true
+			  resources:
+			    staging_api:
+			      name: Staging API
+			      kind: rest
+			      config:
+			        baseURL: https://staging.example.com
+			  stubs:
+			    mock_api:
+			      routes:
+			        - method: GET
+			          path: /users
+			          body: [{"id": 1, "name": "Alfred"}]
+		`),
+		Example: heredoc.Doc(`
+			airplane envs bootstrap-test --fixture ./test_env.yaml -- go test ./...
+			airplane envs bootstrap-test --fixture ./test_env.yaml
+			airplane envs bootstrap-test --teardown test-abc123
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.command = args
+			if cfg.teardown != "" {
+				if len(args) > 0 || cfg.fixture != "" {
+					return errors.New("--teardown cannot be combined with --fixture or a command")
+				}
+				return teardown(cmd.Root().Context(), c.Client, cfg.teardown)
+			}
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.fixture, "fixture", "", "A YAML file of resources and config variables to create in the environment.")
+	cmd.Flags().StringVar(&cfg.name, "name", "Integration tests", "The name of the environment.")
+	cmd.Flags().StringVar(&cfg.teardown, "teardown", "", "The slug of a test environment to tear down.")
+	cmd.Flags().BoolVar(&cfg.keep, "keep", false, "Keep the environment after the command exits, e.g. to debug a failing test.")
+
+	return cmd
+}
+
+// Fixture declares the contents of a test environment.
+type Fixture struct {
+	Configs   map[string]ConfigValue `json:"configs"`
+	Resources map[string]Resource    `json:"resources"`
+	Stubs     map[string]RESTStub    `json:"stubs"`
+}
+
+// ConfigValue is the value of a config variable. It may be written as a plain string, or as an
+// object to mark the value as secret.
+type ConfigValue struct {
+	Value  string `json:"value"`
+	Secret bool   `json:"secret"`
+}
+
+func (v *ConfigValue) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*v = ConfigValue{Value: s}
+		return nil
+	}
+	type rawValue ConfigValue
+	var raw rawValue
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return errors.New("expected config value to be a string or an object with a value")
+	}
+	*v = ConfigValue(raw)
+	return nil
+}
+
+// Resource is a resource to create in the test environment. Config holds the kind-specific fields
+// of the resource, e.g. baseURL for REST resources.
+type Resource struct {
+	Name   string                 `json:"name"`
+	Kind   libapi.ResourceKind    `json:"kind"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// ReadFixture reads a fixture file.
+func ReadFixture(path string) (Fixture, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return Fixture{}, errors.Wrap(err, "reading fixture")
+	}
+	buf, err = yaml.YAMLToJSON(buf)
+	if err != nil {
+		return Fixture{}, errors.Wrapf(err, "parsing fixture %s", path)
+	}
+	var f Fixture
+	if err := json.Unmarshal(buf, &f); err != nil {
+		return Fixture{}, errors.Wrapf(err, "parsing fixture %s", path)
+	}
+	for slug, r := range f.Resources {
+		if r.Kind == "" {
+			return Fixture{}, errors.Errorf("resource %s in fixture %s is missing a kind", slug, path)
+		}
+		if _, ok := f.Stubs[slug]; ok {
+			return Fixture{}, errors.Errorf("%s in fixture %s is declared as both a resource and a stub", slug, path)
+		}
+	}
+	return f, nil
+}
+
+func run(ctx context.Context, c *cli.Config, cfg config) (rerr error) {
+	var fixture Fixture
+	if cfg.fixture != "" {
+		var err error
+		if fixture, err = ReadFixture(cfg.fixture); err != nil {
+			return err
+		}
+	}
+
+	if len(fixture.Stubs) > 0 {
+		if len(cfg.command) == 0 {
+			return errors.New("stubs are only served while a command runs: pass the command after --")
+		}
+		stubResources, stop, err := serveStubs(fixture.Stubs)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		if fixture.Resources == nil {
+			fixture.Resources = map[string]Resource{}
+		}
+		for slug, r := range stubResources {
+			fixture.Resources[slug] = r
+		}
+	}
+
+	env, err := Bootstrap(ctx, c.Client, cfg.name, fixture)
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.command) == 0 {
+		logger.Log("Created test environment %s. Tear it down with:", logger.Bold(env.Slug))
+		logger.Log("  airplane envs bootstrap-test --teardown %s", env.Slug)
+		fmt.Println(env.Slug)
+		return nil
+	}
+
+	if !cfg.keep {
+		defer func() {
+			// Tear down with a fresh context, so that the environment is removed even if the command
+			// was interrupted.
+			//nolint: contextcheck
+			if err := teardown(context.Background(), c.Client, env.Slug); err != nil && rerr == nil {
+				rerr = err
+			}
+		}()
+	}
+
+	cmd := exec.CommandContext(ctx, cfg.command[0], cfg.command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", EnvSlugEnvVar, env.Slug))
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "running %s", strings.Join(cfg.command, " "))
+	}
+	return nil
+}
+
+// Bootstrap creates a test environment and seeds it with the fixture's resources and config
+// variables. If seeding fails, the environment is torn down.
+func Bootstrap(ctx context.Context, client api.APIClient, name string, fixture Fixture) (_ libapi.Env, rerr error) {
+	env, err := client.CreateEnv(ctx, api.CreateEnvRequest{
+		Name: name,
+		Slug: SlugPrefix + utils.RandomString(8, utils.CharsetLowercaseNumeric),
+	})
+	if err != nil {
+		return libapi.Env{}, errors.Wrap(err, "creating environment")
+	}
+	defer func() {
+		if rerr != nil {
+			if err := client.DeleteEnv(ctx, api.DeleteEnvRequest{Slug: env.Slug}); err != nil {
+				logger.Warning("Unable to tear down environment %s: %v", env.Slug, err)
+			}
+		}
+	}()
+
+	for _, slug := range sortedKeys(fixture.Resources) {
+		r := fixture.Resources[slug]
+		name := r.Name
+		if name == "" {
+			name = slug
+		}
+		if _, err := client.CreateResource(ctx, api.CreateResourceRequest{
+			Slug:     slug,
+			Name:     name,
+			Kind:     r.Kind,
+			EnvSlug:  env.Slug,
+			Resource: r.Config,
+		}); err != nil {
+			return libapi.Env{}, errors.Wrapf(err, "creating resource %s", slug)
+		}
+	}
+
+	for _, configName := range sortedKeys(fixture.Configs) {
+		v := fixture.Configs[configName]
+		if err := client.SetConfig(ctx, api.SetConfigRequest{
+			Name:     configName,
+			Value:    v.Value,
+			IsSecret: v.Secret,
+			EnvSlug:  env.Slug,
+		}); err != nil {
+			return libapi.Env{}, errors.Wrapf(err, "setting config %s", configName)
+		}
+	}
+
+	return env, nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+func teardown(ctx context.Context, client api.APIClient, slug string) error {
+	if !strings.HasPrefix(slug, SlugPrefix) {
+		return errors.Errorf("%s is not a test environment: only environments created by bootstrap-test can be torn down", slug)
+	}
+	if err := client.DeleteEnv(ctx, api.DeleteEnvRequest{Slug: slug}); err != nil {
+		return errors.Wrapf(err, "tearing down environment %s", slug)
+	}
+	logger.Log("Tore down test environment %s.", slug)
+	return nil
+}
@@ -0,0 +1,121 @@
+package bootstraptest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFixture(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "fixture.yaml")
+	require.NoError(os.WriteFile(path, []byte(`configs:
+  API_URL: http://localhost:8080
+  API_KEY:
+    value: test-key
+    secret: true
+resources:
+  api:
+    name: Mock API
+    kind: rest
+    config:
+      baseURL: http://localhost:8080
+`), 0644))
+
+	f, err := ReadFixture(path)
+	require.NoError(err)
+	require.Equal(Fixture{
+		Configs: map[string]ConfigValue{
+			"API_URL": {Value: "http://localhost:8080"},
+			"API_KEY": {Value: "test-key", Secret: true},
+		},
+		Resources: map[string]Resource{
+			"api": {
+				Name:   "Mock API",
+				Kind:   libapi.KindREST,
+				Config: map[string]interface{}{"baseURL": "http://localhost:8080"},
+			},
+		},
+	}, f)
+
+	require.NoError(os.WriteFile(path, []byte("resources:\n  api:\n    name: Mock API\n"), 0644))
+	_, err = ReadFixture(path)
+	require.ErrorContains(err, "missing a kind")
+}
+
+func TestBootstrap(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	client := &api.MockClient{}
+
+	env, err := Bootstrap(ctx, client, "Integration tests", Fixture{
+		Configs: map[string]ConfigValue{
+			"API_KEY": {Value: "test-key", Secret: true},
+		},
+		Resources: map[string]Resource{
+			"api": {Kind: libapi.KindREST, Config: map[string]interface{}{"baseURL": "http://localhost:8080"}},
+		},
+	})
+	require.NoError(err)
+	require.True(strings.HasPrefix(env.Slug, SlugPrefix))
+	require.Contains(client.Envs, env.Slug)
+	require.Len(client.Resources, 1)
+	require.Equal("api", client.Resources[0].Slug)
+	require.Equal("api", client.Resources[0].Name)
+	require.Len(client.Configs, 1)
+	require.Equal("test-key", client.Configs[0].Value)
+	require.True(client.Configs[0].IsSecret)
+
+	require.Error(teardown(ctx, client, "prod"))
+	require.NoError(teardown(ctx, client, env.Slug))
+	require.Empty(client.Envs)
+}
+
+func TestServeStubs(t *testing.T) {
+	require := require.New(t)
+
+	resources, stop, err := serveStubs(map[string]RESTStub{
+		"mock_api": {
+			Routes: []StubRoute{
+				{Path: "/users", Body: []interface{}{map[string]interface{}{"id": 1}}},
+				{Method: "POST", Path: "/users", Status: http.StatusCreated, Body: "created"},
+			},
+		},
+	})
+	require.NoError(err)
+	defer stop()
+	require.Len(resources, 1)
+	require.Equal("mock_api", resources["mock_api"].Name)
+	require.Equal(libapi.KindREST, resources["mock_api"].Kind)
+	baseURL := resources["mock_api"].Config["baseURL"].(string)
+
+	resp, err := http.Get(baseURL + "/users")
+	require.NoError(err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(err)
+	resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+	require.Equal("application/json", resp.Header.Get("Content-Type"))
+	require.JSONEq(`[{"id": 1}]`, string(body))
+
+	resp, err = http.Post(baseURL+"/users", "text/plain", nil)
+	require.NoError(err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(err)
+	resp.Body.Close()
+	require.Equal(http.StatusCreated, resp.StatusCode)
+	require.Equal("created", string(body))
+
+	resp, err = http.Get(baseURL + "/missing")
+	require.NoError(err)
+	resp.Body.Close()
+	require.Equal(http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,106 @@
+package bootstraptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// RESTStub is a mock REST API that is served by the CLI while a command runs against the test
+// environment. A REST resource pointing at it is created in the environment.
+type RESTStub struct {
+	Name   string      `json:"name"`
+	Routes []StubRoute `json:"routes"`
+}
+
+// StubRoute is a canned response to requests with a given method and path.
+type StubRoute struct {
+	// Method defaults to GET.
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Status defaults to 200.
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	// Body is written as-is if it is a string, and as JSON otherwise.
+	Body interface{} `json:"body"`
+}
+
+// ServeHTTP responds with the route that matches the request's method and path, or a 404 if there
+// is no such route.
+func (s RESTStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, route := range s.Routes {
+		method := route.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		if !strings.EqualFold(method, r.Method) || route.Path != r.URL.Path {
+			continue
+		}
+
+		var body []byte
+		switch b := route.Body.(type) {
+		case nil:
+		case string:
+			body = []byte(b)
+		default:
+			var err error
+			if body, err = json.Marshal(b); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+		}
+		for k, v := range route.Headers {
+			w.Header().Set(k, v)
+		}
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
+		return
+	}
+	http.Error(w, fmt.Sprintf("no stub route for %s %s", r.Method, r.URL.Path), http.StatusNotFound)
+}
+
+// serveStubs starts a local server for each stub and returns the REST resources that point at them,
+// along with a function that stops the servers.
+func serveStubs(stubs map[string]RESTStub) (map[string]Resource, func(), error) {
+	resources := map[string]Resource{}
+	var servers []*http.Server
+	stop := func() {
+		for _, s := range servers {
+			_ = s.Close()
+		}
+	}
+	for _, slug := range sortedKeys(stubs) {
+		stub := stubs[slug]
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			stop()
+			return nil, nil, errors.Wrapf(err, "serving stub %s", slug)
+		}
+		s := &http.Server{Handler: stub}
+		servers = append(servers, s)
+		go func() {
+			_ = s.Serve(l)
+		}()
+
+		name := stub.Name
+		if name == "" {
+			name = slug
+		}
+		resources[slug] = Resource{
+			Name:   name,
+			Kind:   libapi.KindREST,
+			Config: map[string]interface{}{"baseURL": "http://" + l.Addr().String()},
+		}
+	}
+	return resources, stop, nil
+}
@@ -0,0 +1,30 @@
+package envs
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/envs/bootstraptest"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "envs",
+		Short:   "Manage environments",
+		Long:    "Manage environments.",
+		Aliases: []string{"env"},
+		Example: heredoc.Doc(`
+			airplane envs bootstrap-test --fixture ./test_env.yaml -- go test ./...
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+	}
+
+	cmd.AddCommand(bootstraptest.New(c))
+
+	return cmd
+}
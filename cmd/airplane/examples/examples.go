@@ -0,0 +1,29 @@
+package examples
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/examples/initcmd"
+	"github.com/airplanedev/cli/cmd/airplane/examples/list"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "examples",
+		Short:   "Browse and scaffold example tasks and views",
+		Long:    "Browse and scaffold runnable example tasks and views.",
+		Aliases: []string{"example"},
+		Example: heredoc.Doc(`
+			airplane examples list
+			airplane examples list --url https://example.com/gallery.tar.gz
+			airplane examples init sql_report
+		`),
+	}
+
+	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(initcmd.New(c))
+
+	return cmd
+}
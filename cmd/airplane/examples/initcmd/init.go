@@ -0,0 +1,73 @@
+package initcmd
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/gallery"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	name  string
+	dir   string
+	force bool
+}
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "init <name>",
+		Short: "Scaffolds an example into the current directory",
+		Long: heredoc.Doc(`
+			Writes the files of an example into the current directory, or --dir. Run
+			"airplane examples list" to see all examples.
+		`),
+		Example: heredoc.Doc(`
+			airplane examples init sql_report
+			airplane examples init approval_workflow --dir ./workflows
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.name = args[0]
+			return run(cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.dir, "dir", ".", "Directory to write the example's files into.")
+	cmd.Flags().BoolVar(&cfg.force, "force", false, "Overwrite existing files.")
+
+	return cmd
+}
+
+func run(cfg config) error {
+	exs, err := gallery.List(gallery.CacheDir(conf.Dir()))
+	if err != nil {
+		return err
+	}
+	ex, err := gallery.Find(exs, cfg.name)
+	if err != nil {
+		return err
+	}
+
+	written, err := gallery.Init(ex, cfg.dir, cfg.force)
+	if err != nil {
+		return err
+	}
+
+	print.Print(written, func() {
+		for _, p := range written {
+			logger.Log("Created %s", p)
+		}
+		logger.Suggest(
+			"⚡ To develop the example locally:",
+			"airplane dev %s",
+			cfg.dir,
+		)
+	})
+	return nil
+}
@@ -0,0 +1,70 @@
+package list
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/gallery"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	url string
+}
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists example tasks and views",
+		Long: heredoc.Doc(`
+			Lists the examples that can be scaffolded with "airplane examples init".
+
+			Pass --url to add the examples of a remote gallery: a gzipped tarball of a directory
+			that contains a gallery.json file, which lists the examples, and a directory per example
+			with its files. The gallery is cached, and replaces any gallery downloaded before.
+		`),
+		Example: heredoc.Doc(`
+			airplane examples list
+			airplane examples list --url https://example.com/gallery.tar.gz
+		`),
+		Aliases: []string{"ls"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.url, "url", "", "URL of a remote gallery to download before listing examples.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	cacheDir := gallery.CacheDir(conf.Dir())
+	if cfg.url != "" {
+		if err := gallery.Refresh(ctx, cfg.url, cacheDir); err != nil {
+			return err
+		}
+	}
+
+	exs, err := gallery.List(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	print.Print(exs, func() {
+		for _, ex := range exs {
+			logger.Log("%s (%s)", logger.Bold(ex.Name), ex.Kind)
+			logger.Log("  %s", ex.Description)
+		}
+		logger.Log("")
+		logger.Log("Run `airplane examples init <name>` to add an example to the current directory.")
+	})
+	return nil
+}
@@ -0,0 +1,73 @@
+package list
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	envSlug  string
+	capacity bool
+}
+
+// New returns a new list command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists agent pools and the health of their agents",
+		Example: heredoc.Doc(`
+			airplane pools list
+			airplane pools list --capacity
+			airplane pools list -o json
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cfg.capacity, "capacity", false, "Include the number of running and queued runs in each pool.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+func run(ctx context.Context, c *cli.Config, cfg config) error {
+	resp, err := c.Client.ListAgentPools(ctx, api.ListAgentPoolsRequest{
+		EnvSlug:         cfg.envSlug,
+		IncludeCapacity: cfg.capacity,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing agent pools")
+	}
+
+	print.Print(resp.Pools, func() {
+		tw := tablewriter.NewWriter(os.Stdout)
+		tw.SetBorder(false)
+		header := []string{"name", "healthy agents", "unhealthy agents"}
+		if cfg.capacity {
+			header = append(header, "running", "queued")
+		}
+		tw.SetHeader(header)
+		for _, p := range resp.Pools {
+			row := []string{p.Name, strconv.Itoa(p.HealthyAgents), strconv.Itoa(p.UnhealthyAgents)}
+			if cfg.capacity {
+				row = append(row, strconv.Itoa(p.RunningRunsCount), strconv.Itoa(p.QueuedRunsCount))
+			}
+			tw.Append(row)
+		}
+		tw.Render()
+	})
+	return nil
+}
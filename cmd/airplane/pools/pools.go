@@ -0,0 +1,31 @@
+package pools
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/pools/list"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pools",
+		Short:   "Manage agent pools",
+		Long:    "Manage the agent pools that tasks can be pinned to with agentPool.",
+		Aliases: []string{"pool"},
+		Example: heredoc.Doc(`
+			airplane pools list
+			airplane pools list --capacity
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+	}
+
+	cmd.AddCommand(list.New(c))
+
+	return cmd
+}
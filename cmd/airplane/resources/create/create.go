@@ -0,0 +1,90 @@
+package create
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/resources/kinds"
+	"github.com/airplanedev/cli/pkg/resources/resourcedef"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	file    string
+	envSlug string
+	dryRun  bool
+}
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "create <file>",
+		Short: "Creates a resource from a definition file",
+		Long: heredoc.Doc(`
+			Creates a resource from a definition file, such as one scaffolded by
+			"airplane resources init". The definition is validated against the schema of its kind
+			before anything is uploaded.
+		`),
+		Example: heredoc.Doc(`
+			airplane resources create db.resource.yaml
+			airplane resources create db.resource.yaml --env staging
+			airplane resources create db.resource.yaml --dry-run
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.file = args[0]
+			return run(cmd.Root().Context(), c.Client, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to create the resource in. Defaults to your team's default environment.")
+	cmd.Flags().BoolVar(&cfg.dryRun, "dry-run", false, "Validate the definition without creating the resource.")
+
+	return cmd
+}
+
+func run(ctx context.Context, client api.APIClient, cfg config) error {
+	def, err := resourcedef.Read(cfg.file)
+	if err != nil {
+		return err
+	}
+	r, err := def.Resource()
+	if err != nil {
+		return errors.Wrapf(err, "validating %s", cfg.file)
+	}
+	if cfg.dryRun {
+		logger.Log("%s is a valid %s resource.", cfg.file, r.Kind)
+		return nil
+	}
+
+	// Calculated fields, e.g. the DSN of SQL resources, are computed by the API.
+	r.ExportResource.ScrubCalculatedFields()
+	serialized, err := kinds.ToMap(r)
+	if err != nil {
+		return err
+	}
+	for _, k := range []string{"id", "slug", "name", "kind"} {
+		delete(serialized, k)
+	}
+
+	resp, err := client.CreateResource(ctx, api.CreateResourceRequest{
+		Slug:     r.Slug,
+		Name:     r.Name,
+		Kind:     libapi.ResourceKind(r.Kind),
+		EnvSlug:  cfg.envSlug,
+		Resource: serialized,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "creating resource %s", r.Slug)
+	}
+
+	logger.Step("Created resource %s (%s)", logger.Bold(r.Slug), resp.ResourceID)
+	return nil
+}
@@ -0,0 +1,48 @@
+package create
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDef(t *testing.T, def string) string {
+	path := filepath.Join(t.TempDir(), "def.resource.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(def), 0644))
+	return path
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	const valid = "slug: slack\nname: Slack\nkind: slack\naccessToken: xoxb-1\n"
+
+	t.Run("valid", func(t *testing.T) {
+		require := require.New(t)
+		client := &api.MockClient{}
+		require.NoError(run(ctx, client, config{file: writeDef(t, valid)}))
+		require.Len(client.Resources, 1)
+		require.Equal("slack", client.Resources[0].Slug)
+		require.Equal("Slack", client.Resources[0].Name)
+		require.Equal(libapi.ResourceKind("slack"), client.Resources[0].Kind)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		require := require.New(t)
+		client := &api.MockClient{}
+		require.NoError(run(ctx, client, config{file: writeDef(t, valid), dryRun: true}))
+		require.Empty(client.Resources)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require := require.New(t)
+		client := &api.MockClient{}
+		err := run(ctx, client, config{file: writeDef(t, "slug: slack\nkind: slack\ntoken: xoxb-1\n")})
+		require.ErrorContains(err, "slack resources have no field(s): token")
+		require.Empty(client.Resources)
+	})
+}
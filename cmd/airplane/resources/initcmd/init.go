@@ -0,0 +1,100 @@
+package initcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/resources"
+	"github.com/airplanedev/cli/pkg/resources/resourcedef"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	kind  resources.ResourceKind
+	slug  string
+	name  string
+	file  string
+	force bool
+}
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	var kinds []string
+	for _, kind := range resourcedef.Kinds() {
+		kinds = append(kinds, string(kind))
+	}
+
+	cmd := &cobra.Command{
+		Use:   "init <kind>",
+		Short: "Scaffolds a resource definition file",
+		Long: heredoc.Docf(`
+			Writes a definition file for a new resource of the given kind, with every field of the kind
+			documented inline. Fill in its fields and create the resource with
+			"airplane resources create".
+
+			Supported kinds: %s.
+		`, strings.Join(kinds, ", ")),
+		Example: heredoc.Doc(`
+			airplane resources init postgres --slug db --name "Production DB"
+			airplane resources init rest --slug stripe --file ./resources/stripe.resource.yaml
+		`),
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: kinds,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.kind = resources.ResourceKind(args[0])
+			return run(cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.slug, "slug", "", "Slug of the resource. Defaults to the kind.")
+	cmd.Flags().StringVar(&cfg.name, "name", "", "Name of the resource. Defaults to the slug.")
+	cmd.Flags().StringVar(&cfg.file, "file", "", "Path to write the definition to. Defaults to <slug>.resource.yaml in the current directory.")
+	cmd.Flags().BoolVar(&cfg.force, "force", false, "Overwrite the file if it exists.")
+
+	return cmd
+}
+
+func run(cfg config) error {
+	if cfg.slug == "" {
+		cfg.slug = string(cfg.kind)
+	}
+	if cfg.name == "" {
+		cfg.name = cfg.slug
+	}
+	if cfg.file == "" {
+		cfg.file = cfg.slug + resourcedef.FileSuffix
+	}
+
+	buf, err := resourcedef.Generate(cfg.kind, cfg.slug, cfg.name)
+	if err != nil {
+		return err
+	}
+	if fsx.Exists(cfg.file) && !cfg.force {
+		return errors.Errorf("%s already exists, pass --force to overwrite it", cfg.file)
+	}
+	if dir := filepath.Dir(cfg.file); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errors.Wrapf(err, "creating %s", dir)
+		}
+	}
+	if err := os.WriteFile(cfg.file, buf, 0644); err != nil {
+		return errors.Wrapf(err, "writing %s", cfg.file)
+	}
+
+	logger.Step("Created %s", cfg.file)
+	logger.Suggest(
+		fmt.Sprintf("⚡ Fill in the fields of %s, then create the resource:", cfg.file),
+		"airplane resources create %s",
+		cfg.file,
+	)
+	return nil
+}
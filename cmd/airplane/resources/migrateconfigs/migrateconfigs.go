@@ -0,0 +1,78 @@
+package migrateconfigs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/devconf"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	path   string
+	dryRun bool
+}
+
+// New returns a new migrate-configs command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "migrate-configs",
+		Short: "Converts resources in the dev config file from the deprecated kindConfig format",
+		Long: heredoc.Doc(`
+			Rewrites resources in the dev config file that use the deprecated kindConfig format into the
+			current format, and reports any fields that could not be converted.
+		`),
+		Example: heredoc.Doc(`
+			airplane resources migrate-configs
+			airplane resources migrate-configs --config-path ./airplane.dev.yaml --dry-run
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.path == "" {
+				wd, err := os.Getwd()
+				if err != nil {
+					return errors.Wrap(err, "error determining current working directory")
+				}
+				cfg.path = filepath.Join(wd, devconf.DefaultDevConfigFileName)
+			}
+			return run(cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.path, "config-path", "", "Path to the dev config file. Defaults to airplane.dev.yaml in the current directory.")
+	cmd.Flags().BoolVar(&cfg.dryRun, "dry-run", false, "Report which resources would be converted without rewriting the file.")
+
+	return cmd
+}
+
+func run(cfg config) error {
+	migrations, err := devconf.MigrateKindConfigs(cfg.path, cfg.dryRun)
+	if err != nil {
+		return err
+	}
+
+	print.Print(migrations, func() {
+		if len(migrations) == 0 {
+			logger.Log("No resources in %s use the kindConfig format.", cfg.path)
+			return
+		}
+		verb := "Converted"
+		if cfg.dryRun {
+			verb = "Would convert"
+		}
+		for _, m := range migrations {
+			logger.Log("%s resource %s (%s).", verb, logger.Bold(m.Slug), m.Kind)
+			if len(m.Unconvertible) > 0 {
+				logger.Warning("  Dropped unsupported fields: %s", strings.Join(m.Unconvertible, ", "))
+			}
+		}
+	})
+	return nil
+}
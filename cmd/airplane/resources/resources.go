@@ -0,0 +1,32 @@
+package resources
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/resources/create"
+	"github.com/airplanedev/cli/cmd/airplane/resources/initcmd"
+	"github.com/airplanedev/cli/cmd/airplane/resources/migrateconfigs"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "resources",
+		Short:   "Manage resources",
+		Long:    "Manage resources.",
+		Aliases: []string{"resource"},
+		Example: heredoc.Doc(`
+			airplane resources init postgres --slug db
+			airplane resources create db.resource.yaml
+			airplane resources migrate-configs
+			airplane resources migrate-configs --config-path ./airplane.dev.yaml --dry-run
+		`),
+	}
+
+	cmd.AddCommand(initcmd.New(c))
+	cmd.AddCommand(create.New(c))
+	cmd.AddCommand(migrateconfigs.New(c))
+
+	return cmd
+}
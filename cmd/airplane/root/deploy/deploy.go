@@ -1,30 +1,19 @@
 package deploy
 
 import (
-	"context"
-
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
-	"github.com/airplanedev/cli/pkg/api/cliapi"
-	"github.com/airplanedev/cli/pkg/build/clibuild"
 	"github.com/airplanedev/cli/pkg/cli"
+	libdeploy "github.com/airplanedev/cli/pkg/deploy"
+	"github.com/airplanedev/cli/pkg/deploy/config"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/spf13/cobra"
 )
 
-type Config struct {
-	Root         *cli.Config
-	Client       api.APIClient
-	Paths        []string
-	ChangedFiles utils.NewlineFileValue
-	EnvSlug      string
-	assumeYes    bool
-	assumeNo     bool
-}
-
 func New(c *cli.Config) *cobra.Command {
-	var cfg = Config{
+	var cfg = libdeploy.Config{
 		Root:   c,
 		Client: c.Client,
 	}
@@ -38,15 +27,29 @@ func New(c *cli.Config) *cobra.Command {
 			airplane deploy my_directory
 			airplane tasks deploy my_task.airplane.ts
 			airplane tasks deploy my_directory my_task1.airplane.ts
+			airplane deploy --plan
+			airplane deploy --graph
+			airplane deploy --dry-run
+			airplane deploy --dry-run --build-concurrency 4 --fail-fast
+			airplane deploy --dry-run --timings
+			airplane deploy --dry-run --offline-cache ~/.cache/airplane-offline
+			airplane deploy --dry-run --build-arg PIP_INDEX_URL=https://pypi.example.com/simple --build-secret GOPRIVATE_TOKEN=CI_GO_TOKEN
+			airplane deploy --sign-key cosign.key --attestations-dir ./attestations
+			airplane deploy --cache-from us-docker.pkg.dev/my-project/cache --cache-to us-docker.pkg.dev/my-project/cache
 		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
 				cfg.Paths = args
+			} else if memberPaths, ok, err := config.WorkspaceMemberPaths("."); err != nil {
+				return err
+			} else if ok {
+				// Default to the members of the workspace in the current directory.
+				cfg.Paths = memberPaths
 			} else {
 				// Default to current directory.
 				cfg.Paths = []string{"."}
 			}
-			return run(cmd.Root().Context(), cfg)
+			return libdeploy.Deploy(cmd.Root().Context(), cfg)
 		},
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -55,9 +58,29 @@ func New(c *cli.Config) *cobra.Command {
 	}
 
 	cmd.Flags().Var(&cfg.ChangedFiles, "changed-files", "A file with a list of file paths that were changed, one path per line. Only tasks with changed files will be deployed")
+	cmd.Flags().StringVar(&cfg.ChangedSince, "changed-since", "", "A git ref, e.g. origin/main. Only tasks and views affected by files changed since this ref will be deployed.")
 	cmd.Flags().StringVar(&cfg.EnvSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
-	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "True to specify automatic yes to prompts.")
-	cmd.Flags().BoolVarP(&cfg.assumeNo, "no", "n", false, "True to specify automatic no to prompts.")
+	cmd.Flags().BoolVar(&cfg.PinIDs, "pin-ids", false, "Write the IDs of deployed tasks back into their definition files so that future deploys match tasks by ID instead of slug.")
+	cmd.Flags().IntVar(&cfg.EventsFD, "events-fd", 0, "A file descriptor to write newline-delimited JSON deploy events to, e.g. 3.")
+	cmd.Flags().StringVar(&cfg.EventsFile, "events-file", "", "A file to write newline-delimited JSON deploy events to.")
+	cmd.Flags().IntVar(&cfg.DiscoveryConcurrency, "discovery-concurrency", discover.DefaultConcurrency, "The maximum number of files to inspect at once while discovering tasks and views.")
+	cmd.Flags().BoolVar(&cfg.Plan, "plan", false, "Print the tasks and views that deploying would create or update, without deploying. Exits with an error if there are any changes.")
+	cmd.Flags().BoolVar(&cfg.Graph, "graph", false, "Print the order that tasks would be deployed in and the tasks that each task calls, without deploying.")
+	cmd.Flags().BoolVar(&cfg.DryRun, "dry-run", false, "Build the images of the tasks and views locally, without uploading code or deploying. Requires Docker.")
+	cmd.Flags().IntVar(&cfg.BuildConcurrency, "build-concurrency", 1, "The maximum number of images to build at once with --dry-run.")
+	cmd.Flags().BoolVar(&cfg.FailFast, "fail-fast", false, "Stop building images with --dry-run after the first build fails, instead of building every image.")
+	cmd.Flags().BoolVar(&cfg.Timings, "timings", false, "Print how long each step of each build, e.g. installing dependencies, took with --dry-run.")
+	cmd.Flags().StringToStringVar(&cfg.BuildArgs, "build-arg", nil, "A build arg to build images with --dry-run, e.g. KEY=VALUE. Overrides the buildArgs of task definitions.")
+	cmd.Flags().StringToStringVar(&cfg.BuildSecrets, "build-secret", nil, "The env var to read a build secret from with --dry-run, e.g. ID=ENV_VAR. By default, the value of a build secret is read from the env var of the same name.")
+	cmd.Flags().StringVar(&cfg.OfflineCache, "offline-cache", "", "A directory with an npm cache to install the dependencies of shims from with --dry-run, for Docker daemons without registry access. It's populated from the registry if it doesn't exist yet.")
+	cmd.Flags().StringSliceVar(&cfg.CacheFrom, "cache-from", nil, "Images in the registry to reuse build layers from, e.g. from a previous deploy's --cache-to.")
+	cmd.Flags().StringVar(&cfg.CacheTo, "cache-to", "", "An image in the registry to push build layers to, so that later deploys can reuse them with --cache-from. Ignored by --dry-run, which doesn't push anything.")
+	cmd.Flags().StringVar(&cfg.SignKey, "sign-key", "", "A cosign private key to sign the provenance of each uploaded bundle with. The key's password is read from COSIGN_PASSWORD.")
+	cmd.Flags().StringVar(&cfg.AttestationsDir, "attestations-dir", "", "A directory to write the signed provenance of each uploaded bundle to, as DSSE envelopes. Requires --sign-key.")
+	cmd.Flags().BoolVar(&cfg.StrictVersions, "strict-versions", false, "Fail if tasks depend on versions of an SDK that this version of the CLI can't build, rather than warning.")
+	cmd.Flags().BoolVar(&cfg.DiscoverInline, "discover-inline", false, "Also check tasks and views configured inline in code, e.g. their runAs and env vars, before deploying. Their code is run locally to read their configs, so this requires their runtimes, e.g. Node or Python. --plan, --graph, --dry-run and --changed-since always do this.")
+	cmd.Flags().BoolVarP(&cfg.AssumeYes, "yes", "y", false, "True to specify automatic yes to prompts.")
+	cmd.Flags().BoolVarP(&cfg.AssumeNo, "no", "n", false, "True to specify automatic no to prompts.")
 
 	if err := cmd.Flags().MarkHidden("yes"); err != nil {
 		logger.Debug("error: %s", err)
@@ -68,20 +91,3 @@ func New(c *cli.Config) *cobra.Command {
 
 	return cmd
 }
-
-func run(ctx context.Context, cfg Config) error {
-	return Deploy(ctx, cfg)
-}
-
-func Deploy(ctx context.Context, cfg Config) error {
-	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{WithLoader: true})
-	defer l.StopLoader()
-
-	d := build.BundleDiscoverer(cfg.Client, l, cfg.EnvSlug)
-	bundles, err := d.Discover(ctx, cfg.Paths...)
-	if err != nil {
-		return err
-	}
-
-	return NewDeployer(cfg, l, DeployerOpts{}).Deploy(ctx, bundles)
-}
@@ -0,0 +1,208 @@
+package fix
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/definitions/updaters"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	client api.APIClient
+	paths  []string
+	apply  bool
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{client: c.Client}
+
+	cmd := &cobra.Command{
+		Use:   "fix [path...]",
+		Short: "Find and fix deprecated fields in task definitions",
+		Long: heredoc.Doc(`
+			Lists the task definitions that use deprecated fields, along with what to use instead.
+
+			With --apply, the definitions are rewritten to use the replacements. Rewritten
+			definitions behave the same as before.
+		`),
+		Example: heredoc.Doc(`
+			airplane fix
+			airplane fix ./tasks --apply
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cfg.apply, "apply", false, "Rewrite definitions to replace deprecated fields.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+	f := &fixer{client: cfg.client, logger: l, apply: cfg.apply}
+	fixes, err := f.Fix(ctx, cfg.paths...)
+	if err != nil {
+		return err
+	}
+
+	if len(fixes) == 0 {
+		l.Log("No deprecated fields found.")
+		return nil
+	}
+	for _, fix := range fixes {
+		l.Log("Task %s (%s):", logger.Bold(fix.Slug), relativePath(fix.File))
+		for _, d := range fix.Deprecations {
+			l.Log("  - %s", d)
+		}
+	}
+	l.Log("")
+	if cfg.apply {
+		l.Log("Updated %d definition(s).", len(fixes))
+	} else {
+		l.Log("Run %s to update %d definition(s).", logger.Blue("airplane fix --apply"), len(fixes))
+	}
+	return nil
+}
+
+// Fix is a task definition that uses deprecated fields.
+type Fix struct {
+	Slug         string
+	File         string
+	Deprecations []definitions.Deprecation
+}
+
+type fixer struct {
+	client api.APIClient
+	logger logger.Logger
+	// apply rewrites the definitions that use deprecated fields. Otherwise, definitions are only
+	// checked.
+	apply bool
+}
+
+// Fix finds the task definitions discovered in paths that use deprecated fields, and rewrites them
+// if the fixer applies fixes.
+func (f *fixer) Fix(ctx context.Context, paths ...string) ([]Fix, error) {
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			// The logger is left unset so that deprecations aren't also logged while discovering.
+			&discover.DefnDiscoverer{
+				Client:                  f.client,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+		},
+		Client: f.client,
+		Logger: f.logger,
+	}
+	taskConfigs, _, err := d.Discover(ctx, paths...)
+	if err != nil {
+		return nil, errors.Wrap(err, "discovering tasks")
+	}
+	if len(taskConfigs) == 0 {
+		return nil, nil
+	}
+
+	resp, err := f.client.ListResourceMetadata(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing resources")
+	}
+
+	var fixes []Fix
+	for _, tc := range taskConfigs {
+		fix, err := f.fixTask(tc, resp.Resources)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fixing task %s", tc.Def.GetSlug())
+		}
+		if fix != nil {
+			fixes = append(fixes, *fix)
+		}
+	}
+	return fixes, nil
+}
+
+func (f *fixer) fixTask(tc discover.TaskConfig, resources []libapi.ResourceMetadata) (*Fix, error) {
+	file := tc.Def.GetDefnFilePath()
+	format := definitions.GetTaskDefFormat(file)
+	if format == definitions.DefFormatUnknown {
+		return nil, nil
+	}
+
+	// Re-read the definition file, since the discovered definition includes values, such as env
+	// vars, that are inherited from elsewhere.
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading task definition")
+	}
+	var def definitions.Definition
+	if err := def.UnmarshalFile(file, buf); err != nil {
+		return nil, err
+	}
+
+	deprecations := def.Deprecations(resources)
+	if len(deprecations) == 0 {
+		return nil, nil
+	}
+
+	if f.apply && len(def.GetExtendedFiles()) > 0 {
+		// The deprecated fields may be set in the shared files that the definition extends.
+		f.logger.Warning("%s extends shared files, so its deprecations need to be fixed by hand.", relativePath(file))
+	} else if f.apply {
+		fixed := def.FixDeprecations(resources)
+		if format == definitions.DefFormatYAML {
+			// Only the deprecated fields are rewritten, so that comments are kept.
+			if err := updaters.FixYAMLTaskDeprecations(file, fixed); err != nil {
+				return nil, err
+			}
+		} else {
+			// JSON has no comments to keep, so the definition is rewritten.
+			content, err := def.Marshal(format)
+			if err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(file, content, 0644); err != nil {
+				return nil, errors.Wrap(err, "updating task definition")
+			}
+		}
+	}
+
+	return &Fix{
+		Slug:         def.GetSlug(),
+		File:         file,
+		Deprecations: deprecations,
+	}, nil
+}
+
+// relativePath returns path relative to the working directory, if possible.
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
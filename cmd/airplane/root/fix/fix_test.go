@@ -0,0 +1,74 @@
+package fix
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+const taskDef = `slug: my_task
+name: My task
+sql:
+  # The warehouse.
+  resource: Database
+  entrypoint: query.sql
+  configs:
+  - API_KEY
+`
+
+func setup(t *testing.T) (string, *api.MockClient) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "my_task.task.yaml"), []byte(taskDef), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "query.sql"), []byte("select 1;\n"), 0644))
+
+	return dir, &api.MockClient{
+		Resources: []libapi.Resource{{ID: "res123", Slug: "db", Name: "Database"}},
+	}
+}
+
+func TestFix(t *testing.T) {
+	require := require.New(t)
+	dir, client := setup(t)
+
+	f := &fixer{client: client, logger: logger.NewNoopLogger()}
+	fixes, err := f.Fix(context.Background(), dir)
+	require.NoError(err)
+	require.Len(fixes, 1)
+	require.Equal("my_task", fixes[0].Slug)
+	require.Len(fixes[0].Deprecations, 2)
+
+	// Without apply, definitions are left untouched.
+	buf, err := os.ReadFile(filepath.Join(dir, "my_task.task.yaml"))
+	require.NoError(err)
+	require.Equal(taskDef, string(buf))
+
+	f.apply = true
+	fixes, err = f.Fix(context.Background(), dir)
+	require.NoError(err)
+	require.Len(fixes, 1)
+
+	buf, err = os.ReadFile(filepath.Join(dir, "my_task.task.yaml"))
+	require.NoError(err)
+	require.Equal(`slug: my_task
+name: My task
+sql:
+  # The warehouse.
+  resource: db
+  entrypoint: query.sql
+configs:
+- API_KEY
+`, string(buf))
+
+	// Once fixed, there is nothing left to fix.
+	fixes, err = f.Fix(context.Background(), dir)
+	require.NoError(err)
+	require.Empty(fixes)
+}
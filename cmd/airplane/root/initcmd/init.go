@@ -30,6 +30,11 @@ type config struct {
 	workspace   string
 	envSlug     string
 	fromRunbook string
+
+	registry     string
+	templateDirs []string
+	sdkVersion   string
+	gitHooks     bool
 }
 
 func New(c *cli.Config) *cobra.Command {
@@ -38,10 +43,19 @@ func New(c *cli.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize a task, view, or template",
+		Long: heredoc.Doc(`
+			Initializes a task, view, or template with an interactive wizard.
+
+			Templates are listed from the template registry and from local template directories,
+			whose subdirectories with a template.json are templates. Local templates take
+			precedence over templates of the registry with the same name.
+		`),
 		Example: heredoc.Doc(`
 		    $ airplane init
 			$ airplane init --template getting_started
 			$ airplane init --template github.com/airplanedev/templates/getting_started
+			$ airplane init --template-dir ~/airplane-templates
+			$ airplane init --sdk-version 0.2.40 --git-hooks
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -71,6 +85,10 @@ func New(c *cli.Config) *cobra.Command {
 		logger.Debug("marking --workspace as hidden: %s", err)
 	}
 	cmd.Flags().StringVar(&cfg.fromRunbook, "from-runbook", "", "Initialize a task from a runbook.")
+	cmd.Flags().StringVar(&cfg.registry, "registry", initcmd.DefaultRegistryURL, "URL of the template registry to list templates from.")
+	cmd.Flags().StringSliceVar(&cfg.templateDirs, "template-dir", nil, "Local directory of templates to list besides the registry. Can be repeated.")
+	cmd.Flags().StringVar(&cfg.sdkVersion, "sdk-version", "", "Version of the Airplane SDK to install for new tasks and views, e.g. 0.2.40 or latest.")
+	cmd.Flags().BoolVar(&cfg.gitHooks, "git-hooks", false, "Install a git pre-commit hook that runs `airplane lint`.")
 
 	return cmd
 }
@@ -114,15 +132,29 @@ func run(ctx context.Context, cfg config) error {
 				"template_path": cfg.template,
 			})
 
-			return utils.CopyFromGithubPath(cfg.root.Prompter, l, cfg.template)
+			if err := utils.CopyFromGithubPath(cfg.root.Prompter, l, cfg.template); err != nil {
+				return err
+			}
+			return setUpGitHooks(cfg, l)
 		}
 
-		return initcmd.InitFromTemplate(ctx, initcmd.InitFromTemplateRequest{
+		templates, err := initcmd.ListTemplates(ctx, initcmd.ListTemplatesRequest{
+			RegistryURL: cfg.registry,
+			LocalDirs:   cfg.templateDirs,
+		})
+		if err != nil {
+			return err
+		}
+		if err := initcmd.InitFromTemplate(ctx, initcmd.InitFromTemplateRequest{
 			Client:       cfg.root.Client,
 			Prompter:     cfg.root.Prompter,
 			Logger:       l,
+			Templates:    templates,
 			TemplateSlug: cfg.template,
-		})
+		}); err != nil {
+			return err
+		}
+		return setUpGitHooks(cfg, l)
 	}
 
 	if cfg.fromRunbook != "" {
@@ -144,11 +176,25 @@ func run(ctx context.Context, cfg config) error {
 		return err
 	}
 	if selectedInit == taskOption {
-		return taskinit.Run(ctx, taskinit.GetConfig(taskinit.ConfigOpts{Client: cfg.client, Root: cfg.root}))
+		if err := taskinit.Run(ctx, taskinit.GetConfig(taskinit.ConfigOpts{
+			Client:     cfg.client,
+			Root:       cfg.root,
+			SDKVersion: cfg.sdkVersion,
+		})); err != nil {
+			return err
+		}
 	} else if selectedInit == viewOption {
-		return viewinit.Run(ctx, viewinit.GetConfig(cfg.root))
+		if err := viewinit.Run(ctx, viewinit.GetConfig(viewinit.ConfigOpts{
+			Root:       cfg.root,
+			SDKVersion: cfg.sdkVersion,
+		})); err != nil {
+			return err
+		}
 	} else if selectedInit == templateOption {
-		templates, err := initcmd.ListTemplates(ctx)
+		templates, err := initcmd.ListTemplates(ctx, initcmd.ListTemplatesRequest{
+			RegistryURL: cfg.registry,
+			LocalDirs:   cfg.templateDirs,
+		})
 		if err != nil {
 			return err
 		}
@@ -157,26 +203,67 @@ func run(ctx context.Context, cfg config) error {
 			return err
 		}
 
-		return initcmd.InitFromTemplate(ctx, initcmd.InitFromTemplateRequest{
+		if err := initcmd.InitFromTemplate(ctx, initcmd.InitFromTemplateRequest{
 			Client:       cfg.root.Client,
 			Prompter:     cfg.root.Prompter,
 			Logger:       l,
 			Templates:    templates,
 			TemplateSlug: selectedTemplate,
-		})
+		}); err != nil {
+			return err
+		}
 	}
 
+	return setUpGitHooks(cfg, l)
+}
+
+// setUpGitHooks installs a pre-commit hook that runs `airplane lint` if --git-hooks is set.
+func setUpGitHooks(cfg config, l logger.Logger) error {
+	if !cfg.gitHooks {
+		return nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	hookPath, err := initcmd.LintHookPath(wd)
+	if errors.Is(err, initcmd.ErrNotGitRepository) {
+		l.Warning("Skipping git hooks: %s is not in a git repository.", wd)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if ok, err := initcmd.HasLintHook(hookPath); err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+
+	if err := initcmd.InstallLintHook(hookPath); err != nil {
+		return err
+	}
+	l.Step("Installed git pre-commit hook at %s", hookPath)
 	return nil
 }
 
 func selectTemplate(p prompts.Prompter, l logger.Logger, templates []initcmd.Template) (string, error) {
 	const templateBrowser = "Explore templates in the browser"
 	optionToPath := map[string]string{}
+	seen := map[string]bool{}
 
 	templateShortPaths := []string{templateBrowser}
 	for _, t := range templates {
-		shortPath := strings.TrimPrefix(t.GitHubPath, "github.com/airplanedev/templates/")
+		shortPath := t.Slug()
+		if seen[shortPath] {
+			// Local templates, which are listed first, take precedence over templates of the
+			// registry with the same slug.
+			continue
+		}
+		seen[shortPath] = true
 		option := fmt.Sprintf("%s (%s)", t.Name, shortPath)
+		if t.LocalPath != "" {
+			option = fmt.Sprintf("%s (local: %s)", t.Name, shortPath)
+		}
 		optionToPath[option] = shortPath
 		templateShortPaths = append(templateShortPaths, option)
 	}
@@ -0,0 +1,170 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/definitions/lint"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	client api.APIClient
+	paths  []string
+	format string
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{client: c.Client}
+
+	cmd := &cobra.Command{
+		Use:   "lint [path...]",
+		Short: "Check task definitions for likely mistakes",
+		Long: heredoc.Doc(`
+			Checks the tasks discovered in the given paths for likely mistakes, such as parameters
+			that are never used, invalid cron expressions and resources that don't exist.
+
+			Exits with an error if any errors are found. Warnings are reported, but don't fail the
+			command.
+		`),
+		Example: heredoc.Doc(`
+			airplane lint
+			airplane lint ./tasks --format json
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.format, "format", "text", "The format to report findings in (text|json).")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	if cfg.format != "text" && cfg.format != "json" {
+		return errors.Errorf("unknown format %q: expected text or json", cfg.format)
+	}
+
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+	numTasks, findings, err := lintTasks(ctx, cfg.client, l, cfg.paths...)
+	if err != nil {
+		return err
+	}
+
+	if cfg.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return errors.Wrap(err, "encoding findings")
+		}
+	} else {
+		report(l, numTasks, findings)
+	}
+
+	var errs int
+	for _, f := range findings {
+		if f.Severity == lint.SeverityError {
+			errs++
+		}
+	}
+	if errs > 0 {
+		return errors.Errorf("found %d error(s)", errs)
+	}
+	return nil
+}
+
+// lintTasks lints the tasks discovered in paths. It returns the number of tasks that were linted,
+// and their findings.
+func lintTasks(ctx context.Context, client api.APIClient, l logger.Logger, paths ...string) (int, []lint.Finding, error) {
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingTasks: true,
+			},
+		},
+		Client: client,
+		Logger: l,
+	}
+	taskConfigs, _, err := d.Discover(ctx, paths...)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "discovering tasks")
+	}
+
+	resp, err := client.ListResourceMetadata(ctx)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "listing resources")
+	}
+	opts := lint.Options{Resources: resp.Resources}
+
+	findings := []lint.Finding{}
+	for i := range taskConfigs {
+		findings = append(findings, lint.Lint(&taskConfigs[i].Def, opts)...)
+	}
+	return len(taskConfigs), findings, nil
+}
+
+func report(l logger.Logger, numTasks int, findings []lint.Finding) {
+	if len(findings) == 0 {
+		l.Log("No problems found in %d task(s).", numTasks)
+		return
+	}
+
+	var slug string
+	for _, f := range findings {
+		if f.Slug != slug {
+			slug = f.Slug
+			if f.File != "" {
+				l.Log("Task %s (%s):", logger.Bold(f.Slug), relativePath(f.File))
+			} else {
+				l.Log("Task %s:", logger.Bold(f.Slug))
+			}
+		}
+		if f.Severity == lint.SeverityError {
+			l.Log("  - %s", logger.Red("%s", f))
+		} else {
+			l.Log("  - %s", logger.Yellow("%s", f))
+		}
+	}
+	l.Log("")
+	l.Log("Found %d problem(s) in %d task(s).", len(findings), numTasks)
+}
+
+// relativePath returns path relative to the working directory, if possible.
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
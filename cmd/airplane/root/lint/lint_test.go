@@ -0,0 +1,56 @@
+package lint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintTasks(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "my_task.task.yaml"), []byte(`slug: my_task
+name: My task
+description: Lists users.
+parameters:
+- slug: limit
+  type: integer
+- slug: offset
+  type: integer
+sql:
+  resource: missing_db
+  entrypoint: query.sql
+  queryArgs:
+    limit: "{{params.limit}}"
+schedules:
+  hourly:
+    cron: "0 * * * * *"
+`), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "query.sql"), []byte("select * from users limit :limit;\n"), 0644))
+
+	client := &api.MockClient{
+		Resources: []libapi.Resource{{ID: "res123", Slug: "db", Name: "Database"}},
+	}
+	numTasks, findings, err := lintTasks(context.Background(), client, logger.NewNoopLogger(), dir)
+	require.NoError(err)
+	require.Equal(1, numTasks)
+
+	var rules []string
+	for _, f := range findings {
+		require.Equal("my_task", f.Slug)
+		require.Equal(filepath.Join(dir, "my_task.task.yaml"), f.File)
+		rules = append(rules, f.Rule+" "+f.Field)
+	}
+	require.Equal([]string{
+		"unused-parameter parameters[1]",
+		"invalid-cron schedules.hourly.cron",
+		"unknown-resource resources.db",
+	}, rules)
+}
@@ -0,0 +1,461 @@
+package pull
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/prompts"
+	"github.com/airplanedev/cli/pkg/runtime"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	client   api.APIClient
+	prompter prompts.Prompter
+	paths    []string
+	envSlug  string
+	watch    bool
+	interval time.Duration
+	patch    string
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{client: c.Client, prompter: c.Prompter}
+
+	cmd := &cobra.Command{
+		Use:   "pull [path...]",
+		Short: "Pull remote changes to tasks and views into local definitions",
+		Long: heredoc.Doc(`
+			Updates the definitions of local tasks and views to match their deployed versions in an
+			environment, e.g. after a task was edited in the web UI.
+
+			With --watch, the environment is polled for changes until interrupted. Before remote
+			changes overwrite a file that may have been edited locally since it was last pulled, you
+			are asked to confirm; if you can't be prompted, the file is skipped. With --patch, local
+			files are left untouched and the changes are written to a patch file instead, which can
+			be applied with "git apply".
+		`),
+		Example: heredoc.Doc(`
+			airplane pull
+			airplane pull ./tasks --env staging
+			airplane pull --watch --patch ./remote-changes.patch
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to pull from. Defaults to your team's default environment.")
+	cmd.Flags().BoolVar(&cfg.watch, "watch", false, "Keep polling the environment for changes until interrupted.")
+	cmd.Flags().DurationVar(&cfg.interval, "interval", 30*time.Second, "How often to poll for changes with --watch.")
+	cmd.Flags().StringVar(&cfg.patch, "patch", "", "Write changes to this patch file instead of updating local files.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+	p := &puller{
+		client:   cfg.client,
+		logger:   l,
+		envSlug:  cfg.envSlug,
+		dryRun:   cfg.patch != "",
+		watch:    cfg.watch,
+		prompter: cfg.prompter,
+	}
+
+	if !cfg.watch {
+		return pullOnce(ctx, p, cfg)
+	}
+
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+	for {
+		// Errors are transient while watching, e.g. a definition that is being edited locally, so
+		// keep polling.
+		if err := pullOnce(ctx, p, cfg); err != nil {
+			l.Warning("Unable to pull changes: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func pullOnce(ctx context.Context, p *puller, cfg config) error {
+	changes, err := p.Pull(ctx, cfg.paths...)
+	if err != nil {
+		return err
+	}
+	if cfg.patch != "" {
+		if err := writePatch(cfg.patch, changes); err != nil {
+			return err
+		}
+	}
+	for _, c := range changes {
+		if cfg.patch != "" {
+			p.logger.Log("Remote changes to %s %s written to %s.", c.Kind, logger.Bold(c.Slug), cfg.patch)
+		} else if c.Skipped {
+			p.logger.Log("Skipped remote changes to %s %s in %s.", c.Kind, logger.Bold(c.Slug), relativePath(c.File))
+		} else {
+			p.logger.Log("Updated %s %s in %s.", c.Kind, logger.Bold(c.Slug), relativePath(c.File))
+		}
+	}
+	if len(changes) == 0 && !cfg.watch {
+		p.logger.Log("Local definitions are up to date.")
+	}
+	return nil
+}
+
+// Change is a local definition that differs from its remote version.
+type Change struct {
+	Kind string
+	Slug string
+	File string
+	// Before and After are the contents of File before and after the remote changes were applied.
+	Before string
+	After  string
+	// Skipped is set if the change was not written, because overwriting local edits was declined.
+	Skipped bool
+}
+
+// Diff returns the change as a unified diff that can be applied with `git apply`.
+func (c Change) Diff() (string, error) {
+	rel := filepath.ToSlash(relativePath(c.File))
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(c.Before),
+		B:        difflib.SplitLines(c.After),
+		FromFile: "a/" + rel,
+		ToFile:   "b/" + rel,
+		Context:  3,
+	})
+}
+
+type puller struct {
+	client  api.APIClient
+	logger  logger.Logger
+	envSlug string
+	// dryRun leaves local files unchanged.
+	dryRun bool
+	// watch is set if the puller is called repeatedly. Remote changes then only overwrite a file
+	// without confirmation if it hasn't changed since it was last seen.
+	watch    bool
+	prompter prompts.Prompter
+	// seen maps files to their contents when they were last pulled or found to be up to date.
+	seen map[string]string
+	// declined maps files to the contents that the user declined to overwrite them with, so that
+	// they aren't asked again until the remote changes again.
+	declined map[string]string
+}
+
+// Pull updates the tasks and views discovered in paths to match their remote versions, and returns
+// the files that changed.
+func (p *puller) Pull(ctx context.Context, paths ...string) ([]Change, error) {
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:           p.client,
+				Logger:           p.logger,
+				DisableNormalize: true,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:  p.client,
+				Logger:  p.logger,
+				EnvSlug: p.envSlug,
+			},
+		},
+		ViewDiscoverers: []discover.ViewDiscoverer{
+			&discover.ViewDefnDiscoverer{
+				Client: p.client,
+				Logger: p.logger,
+			},
+		},
+		Client:  p.client,
+		Logger:  p.logger,
+		EnvSlug: p.envSlug,
+	}
+	taskConfigs, viewConfigs, err := d.Discover(ctx, paths...)
+	if err != nil {
+		return nil, errors.Wrap(err, "discovering tasks and views")
+	}
+
+	var changes []Change
+	if len(taskConfigs) > 0 {
+		resp, err := p.client.ListResourceMetadata(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing resources")
+		}
+		for _, tc := range taskConfigs {
+			change, err := p.pullTask(ctx, tc, resp.Resources)
+			if err != nil {
+				return nil, errors.Wrapf(err, "pulling task %s", tc.Def.GetSlug())
+			}
+			if change != nil {
+				changes = append(changes, *change)
+			}
+		}
+	}
+	for _, vc := range viewConfigs {
+		change, err := p.pullView(ctx, vc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pulling view %s", vc.Def.Slug)
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+	return changes, nil
+}
+
+func (p *puller) pullTask(ctx context.Context, tc discover.TaskConfig, resources []libapi.ResourceMetadata) (*Change, error) {
+	slug := tc.Def.GetSlug()
+	task, err := p.client.GetTask(ctx, libapi.GetTaskRequest{Slug: slug, EnvSlug: p.envSlug})
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := tc.Def.Marshal(definitions.DefFormatYAML)
+	if err != nil {
+		return nil, err
+	}
+	triggers := task.Triggers
+	if triggers == nil {
+		triggers = []libapi.Trigger{}
+	}
+	if err := tc.Def.Update(task.AsUpdateTaskRequest(), definitions.UpdateOptions{
+		Triggers:           triggers,
+		AvailableResources: resources,
+	}); err != nil {
+		return nil, err
+	}
+	after, err := tc.Def.Marshal(definitions.DefFormatYAML)
+	if err != nil {
+		return nil, err
+	}
+	if string(before) == string(after) {
+		return nil, p.see(tc.Def.GetDefnFilePath())
+	}
+
+	kind, err := tc.Def.Kind()
+	if err != nil {
+		return nil, err
+	}
+	rt, err := runtime.Lookup(tc.TaskEntrypoint, kind)
+	if err != nil {
+		return nil, err
+	}
+	return p.updateFile("task", slug, tc.Def.GetDefnFilePath(), func(path string) error {
+		return rt.Update(ctx, p.logger, path, slug, tc.Def)
+	})
+}
+
+// pullView updates the name and description of views defined in view definition files. Views
+// defined in code, and environment variables, are not updated.
+func (p *puller) pullView(ctx context.Context, vc discover.ViewConfig) (*Change, error) {
+	file := vc.Def.DefnFilePath
+	format := definitions.GetViewDefFormat(file)
+	if format == definitions.DefFormatUnknown {
+		return nil, nil
+	}
+	view, err := p.client.GetView(ctx, libapi.GetViewRequest{Slug: vc.Def.Slug})
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-read the definition file, since the discovered definition includes values, such as env
+	// vars, that are inherited from elsewhere.
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading view definition")
+	}
+	var def definitions.ViewDefinition
+	if err := def.Unmarshal(format, buf); err != nil {
+		return nil, err
+	}
+	if def.Name == view.Name && def.Description == view.Description {
+		return nil, p.see(file)
+	}
+	def.Name = view.Name
+	def.Description = view.Description
+
+	return p.updateFile("view", vc.Def.Slug, file, func(path string) error {
+		content, err := def.Marshal(format)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, content, 0644)
+	})
+}
+
+// updateFile captures how update changes file. The update is applied to a copy of file, which
+// is only written back if the puller isn't a dry run and any local edits may be overwritten.
+func (p *puller) updateFile(kind, slug, file string, update func(path string) error) (*Change, error) {
+	before, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", file)
+	}
+	after, err := updateCopy(file, before, update)
+	if err != nil {
+		return nil, err
+	}
+	if string(before) == string(after) {
+		return nil, p.see(file)
+	}
+	change := &Change{
+		Kind:   kind,
+		Slug:   slug,
+		File:   file,
+		Before: string(before),
+		After:  string(after),
+	}
+	if p.dryRun {
+		return change, nil
+	}
+
+	if ok, err := p.confirmOverwrite(change); err != nil {
+		return nil, err
+	} else if !ok {
+		change.Skipped = true
+		return change, nil
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", file)
+	}
+	if err := os.WriteFile(file, after, info.Mode().Perm()); err != nil {
+		return nil, errors.Wrapf(err, "writing %s", file)
+	}
+	if p.watch {
+		p.seen[file] = change.After
+	}
+	return change, nil
+}
+
+// updateCopy runs update against a temporary copy of file, with the given contents, and returns
+// the updated contents. The copy keeps the name of file, since updaters are chosen by extension.
+func updateCopy(file string, contents []byte, update func(path string) error) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "airplane-pull-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temporary directory")
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(file))
+	if err := os.WriteFile(path, contents, 0644); err != nil {
+		return nil, errors.Wrapf(err, "copying %s", file)
+	}
+	if err := update(path); err != nil {
+		return nil, err
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+	return after, nil
+}
+
+// see records the current contents of file while watching, so that later local edits to it can
+// be detected.
+func (p *puller) see(file string) error {
+	if !p.watch {
+		return nil
+	}
+	if _, ok := p.seen[file]; ok {
+		return nil
+	}
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", file)
+	}
+	if p.seen == nil {
+		p.seen = map[string]string{}
+	}
+	p.seen[file] = string(buf)
+	return nil
+}
+
+// confirmOverwrite returns whether change may be written. While watching, files that may have
+// been edited locally since they were last seen are only overwritten if the user confirms.
+func (p *puller) confirmOverwrite(change *Change) (bool, error) {
+	if !p.watch {
+		return true, nil
+	}
+	if p.seen == nil {
+		p.seen = map[string]string{}
+	}
+	if p.declined == nil {
+		p.declined = map[string]string{}
+	}
+	if seen, ok := p.seen[change.File]; ok && seen == change.Before {
+		return true, nil
+	}
+	if p.declined[change.File] == change.After {
+		return false, nil
+	}
+	rel := relativePath(change.File)
+	if !prompts.CanPrompt() || p.prompter == nil {
+		p.logger.Warning("%s may have been edited locally, so remote changes to %s %s were not pulled.", rel, change.Kind, change.Slug)
+		p.declined[change.File] = change.After
+		return false, nil
+	}
+	ok, err := p.prompter.Confirm(fmt.Sprintf("%s may have been edited locally. Overwrite it with the remote changes to %s %s?", rel, change.Kind, change.Slug), prompts.WithDefault(false))
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		p.declined[change.File] = change.After
+	}
+	return ok, nil
+}
+
+func writePatch(path string, changes []Change) error {
+	var patch strings.Builder
+	for _, c := range changes {
+		diff, err := c.Diff()
+		if err != nil {
+			return errors.Wrapf(err, "generating diff of %s", c.File)
+		}
+		patch.WriteString(diff)
+	}
+	if err := os.WriteFile(path, []byte(patch.String()), 0644); err != nil {
+		return errors.Wrap(err, "writing patch")
+	}
+	return nil
+}
+
+// relativePath returns path relative to the working directory, if possible.
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,147 @@
+package pull
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+const taskDef = `slug: my_task
+name: My task
+python:
+  entrypoint: main.py
+`
+
+const viewDef = `slug: my_view
+name: My view
+entrypoint: main.tsx
+`
+
+func setup(t *testing.T) (string, *api.MockClient) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "my_task.task.yaml"), []byte(taskDef), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "main.py"), []byte("print('hello')\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "my_view.view.yaml"), []byte(viewDef), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "main.tsx"), []byte("export default () => null;\n"), 0644))
+
+	var def definitions.Definition
+	require.NoError(def.Unmarshal(definitions.DefFormatYAML, []byte(taskDef)))
+	req, err := def.GetTask(definitions.GetTaskOpts{})
+	require.NoError(err)
+	req.ID = "tsk123"
+	req.Name = "My renamed task"
+	req.Description = "Edited in the UI"
+
+	return dir, &api.MockClient{
+		Tasks: map[string]libapi.Task{"my_task": req},
+		Views: map[string]libapi.View{
+			"my_view": {ID: "vew123", Slug: "my_view", Name: "My renamed view"},
+		},
+	}
+}
+
+func TestPull(t *testing.T) {
+	require := require.New(t)
+	dir, client := setup(t)
+
+	p := &puller{client: client, logger: logger.NewNoopLogger()}
+	changes, err := p.Pull(context.Background(), dir)
+	require.NoError(err)
+	require.Len(changes, 2)
+
+	buf, err := os.ReadFile(filepath.Join(dir, "my_task.task.yaml"))
+	require.NoError(err)
+	require.Contains(string(buf), "name: My renamed task")
+	require.Contains(string(buf), "description: Edited in the UI")
+
+	buf, err = os.ReadFile(filepath.Join(dir, "my_view.view.yaml"))
+	require.NoError(err)
+	require.Contains(string(buf), "name: My renamed view")
+
+	// Once local definitions match, there is nothing left to pull.
+	changes, err = p.Pull(context.Background(), dir)
+	require.NoError(err)
+	require.Empty(changes)
+}
+
+func TestPullDryRun(t *testing.T) {
+	require := require.New(t)
+	dir, client := setup(t)
+
+	p := &puller{client: client, logger: logger.NewNoopLogger(), dryRun: true}
+	changes, err := p.Pull(context.Background(), dir)
+	require.NoError(err)
+	require.Len(changes, 2)
+
+	// Local files are left untouched.
+	buf, err := os.ReadFile(filepath.Join(dir, "my_task.task.yaml"))
+	require.NoError(err)
+	require.Equal(taskDef, string(buf))
+
+	diff, err := changes[0].Diff()
+	require.NoError(err)
+	require.Contains(diff, "-name: My task\n")
+	require.Contains(diff, "+name: My renamed task\n")
+	require.Contains(diff, "+description: Edited in the UI\n")
+}
+
+func TestPullWatch(t *testing.T) {
+	require := require.New(t)
+	dir, client := setup(t)
+	taskFile := filepath.Join(dir, "my_task.task.yaml")
+	viewFile := filepath.Join(dir, "my_view.view.yaml")
+
+	// Files that weren't seen before the remote changed may have local edits, so they aren't
+	// overwritten without confirmation.
+	p := &puller{client: client, logger: logger.NewNoopLogger(), watch: true}
+	changes, err := p.Pull(context.Background(), dir)
+	require.NoError(err)
+	require.Len(changes, 2)
+	for _, c := range changes {
+		require.True(c.Skipped)
+	}
+	buf, err := os.ReadFile(taskFile)
+	require.NoError(err)
+	require.Equal(taskDef, string(buf))
+
+	// Files that are unchanged since they were last seen are updated.
+	p = &puller{client: client, logger: logger.NewNoopLogger(), watch: true}
+	view := client.Views["my_view"]
+	view.Name = "My view"
+	client.Views["my_view"] = view
+	changes, err = p.Pull(context.Background(), dir)
+	require.NoError(err)
+	require.Len(changes, 1)
+	require.True(changes[0].Skipped)
+
+	view.Name = "My renamed view"
+	client.Views["my_view"] = view
+	changes, err = p.Pull(context.Background(), dir)
+	require.NoError(err)
+	require.Len(changes, 2)
+	require.False(changes[1].Skipped)
+	buf, err = os.ReadFile(viewFile)
+	require.NoError(err)
+	require.Contains(string(buf), "name: My renamed view")
+
+	// Local edits since then are not overwritten.
+	require.NoError(os.WriteFile(viewFile, []byte(viewDef+"description: Edited locally\n"), 0644))
+	view.Name = "My view, renamed again"
+	client.Views["my_view"] = view
+	changes, err = p.Pull(context.Background(), dir)
+	require.NoError(err)
+	require.Len(changes, 2)
+	require.True(changes[1].Skipped)
+	buf, err = os.ReadFile(viewFile)
+	require.NoError(err)
+	require.Contains(string(buf), "description: Edited locally")
+}
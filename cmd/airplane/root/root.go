@@ -2,19 +2,34 @@ package root
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/cmd/airplane/agents"
 	"github.com/airplanedev/cli/cmd/airplane/apikeys"
+	"github.com/airplanedev/cli/cmd/airplane/approvals"
 	"github.com/airplanedev/cli/cmd/airplane/auth"
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
 	"github.com/airplanedev/cli/cmd/airplane/auth/logout"
+	"github.com/airplanedev/cli/cmd/airplane/bench"
+	"github.com/airplanedev/cli/cmd/airplane/builds"
 	"github.com/airplanedev/cli/cmd/airplane/configs"
 	"github.com/airplanedev/cli/cmd/airplane/demo"
+	"github.com/airplanedev/cli/cmd/airplane/deployments"
+	"github.com/airplanedev/cli/cmd/airplane/envs"
+	"github.com/airplanedev/cli/cmd/airplane/examples"
+	"github.com/airplanedev/cli/cmd/airplane/pools"
+	"github.com/airplanedev/cli/cmd/airplane/resources"
 	"github.com/airplanedev/cli/cmd/airplane/root/deploy"
+	"github.com/airplanedev/cli/cmd/airplane/root/fix"
 	"github.com/airplanedev/cli/cmd/airplane/root/initcmd"
+	"github.com/airplanedev/cli/cmd/airplane/root/lint"
+	"github.com/airplanedev/cli/cmd/airplane/root/pull"
+	"github.com/airplanedev/cli/cmd/airplane/root/validate"
 	"github.com/airplanedev/cli/cmd/airplane/runs"
+	"github.com/airplanedev/cli/cmd/airplane/schedules"
 	"github.com/airplanedev/cli/cmd/airplane/tasks"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/dev"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/execute"
@@ -22,6 +37,7 @@ import (
 	"github.com/airplanedev/cli/cmd/airplane/views"
 	"github.com/airplanedev/cli/pkg/analytics"
 	"github.com/airplanedev/cli/pkg/api/cliapi"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/conf"
 	"github.com/airplanedev/cli/pkg/flags"
@@ -36,9 +52,12 @@ import (
 
 // New returns a new root cobra command.
 func New() *cobra.Command {
-	var output string
+	var output, logFormat string
+	var apiRetries int
+	var apiTimeout time.Duration
+	client := api.NewClient(api.ClientOpts{})
 	var cfg = &cli.Config{
-		Client:   api.NewClient(api.ClientOpts{}),
+		Client:   client,
 		Prompter: prompts.Surveyor{},
 	}
 
@@ -51,12 +70,42 @@ func New() *cobra.Command {
 			airplane deploy ./path/to/script
 		`),
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			cfg.Client.SetHost(cfg.Host)
-			cfg.Client.SetSource(conf.GetSource())
-			cfg.Client.SetAPIKey(conf.GetAPIKey())
-			cfg.Client.SetTeamID(conf.GetTeamID())
-
 			c, err := conf.ReadDefaultUserConfig()
+			cfg.Resolver = conf.NewResolver(cmd.Flags(), c, map[string]string{
+				"host":       api.DefaultAPIHost,
+				"log-format": string(logger.FormatText),
+			})
+			// Set the log format first, so that every log of the command uses it.
+			format, ferr := logger.ParseFormat(cfg.Resolver.Get("log-format"))
+			if ferr != nil {
+				return ferr
+			}
+			logger.SetFormat(format)
+			cfg.Host = cfg.Resolver.Get("host")
+			// Only the executing command's flags have been parsed, so this only affects its --env flag.
+			// An env that doesn't come from the flag is printed, since it changes what the command acts on.
+			if env, err := cfg.Resolver.Resolve("env"); err == nil && env.Value != "" {
+				if setUnchangedFlag(cmd.Root(), "env", env.Value) && env.Source != conf.SourceFlag {
+					logger.Log(logger.Gray("Using env %s from %s %s. Pass --env to use another environment.", env.Value, env.Source, env.Origin))
+				}
+			}
+
+			cfg.Client.SetHost(cfg.Host)
+			cfg.Client.SetSource(cfg.Resolver.Get("source"))
+			cfg.Client.SetAPIKey(cfg.Resolver.Get("api-key"))
+			cfg.Client.SetTeamID(cfg.Resolver.Get("team"))
+			if apiRetries < 0 {
+				return errors.New("--api-retries must not be negative")
+			}
+			if apiTimeout <= 0 {
+				return errors.New("--api-timeout must be positive")
+			}
+			retries := apiRetries
+			if retries == 0 {
+				// Retries are disabled with a negative value, since zero means the default.
+				retries = -1
+			}
+			client.SetRetryPolicy(retries, apiTimeout)
 			if err == nil {
 				cfg.Client.SetToken(c.Tokens[cfg.Host])
 			}
@@ -77,7 +126,18 @@ func New() *cobra.Command {
 				return errors.New("--output must be (json|yaml|table)")
 			}
 
-			logger.EnableDebug = cfg.DebugMode
+			level := logger.LevelInfo
+			switch {
+			case cfg.DebugMode || cfg.Verbosity >= 2:
+				level = logger.LevelTrace
+			case cfg.Verbosity == 1:
+				level = logger.LevelDebug
+			}
+			logger.SetLevel(level)
+			if err := logger.SetModuleLevels(cfg.LogModules); err != nil {
+				return fmt.Errorf("parsing --log-module: %w", err)
+			}
+			cfg.DebugMode = level >= logger.LevelDebug
 			trap.Printf = logger.Log
 
 			// Log the version every time the CLI is run with `--debug`. This aligns
@@ -110,7 +170,15 @@ func New() *cobra.Command {
 		defaultFormat = "json"
 	}
 	cmd.PersistentFlags().StringVarP(&output, "output", "o", defaultFormat, "The format to use for output (json|yaml|table).")
-	cmd.PersistentFlags().BoolVar(&cfg.DebugMode, "debug", false, "Whether to produce debugging output.")
+	cmd.PersistentFlags().IntVar(&apiRetries, "api-retries", libhttp.DefaultMaxRetries, "The maximum number of times a failed API request is retried. Rate limited requests are retried after the delay requested by the API.")
+	cmd.PersistentFlags().DurationVar(&apiTimeout, "api-timeout", api.DefaultTimeout, "The timeout of each attempt of an API request.")
+	cmd.PersistentFlags().CountVar(&cfg.Verbosity, "verbose", "Produce debugging output. Pass --verbose twice, or --verbose=2, to also include HTTP request and response bodies.")
+	cmd.PersistentFlags().StringVar(&cfg.LogModules, "log-module", "", `Set the log level of individual modules, e.g. "discover" or "api=info,discover=trace". Modules are api and discover; levels are info, debug, and trace (default debug).`)
+	cmd.PersistentFlags().StringVar(&logFormat, "log-format", string(logger.FormatText), "The format to write logs in (text|json). JSON logs are written one per line. Can also be set with AIRPLANE_LOG_FORMAT.")
+	cmd.PersistentFlags().BoolVar(&cfg.DebugMode, "debug", false, "Whether to produce debugging output. Equivalent to --verbose=2.")
+	if err := cmd.PersistentFlags().MarkDeprecated("debug", "use --verbose or --verbose=2 instead."); err != nil {
+		logger.Debug("error: %s", err)
+	}
 	cmd.PersistentFlags().BoolVar(&cfg.Dev, "dev", false, "Dev mode: warning, not guaranteed to work and subject to change.")
 	if err := cmd.PersistentFlags().MarkHidden("dev"); err != nil {
 		logger.Debug("error: %s", err)
@@ -120,6 +188,10 @@ func New() *cobra.Command {
 	// Root commands:
 	cmd.AddCommand(initcmd.New(cfg))
 	cmd.AddCommand(deploy.New(cfg))
+	cmd.AddCommand(pull.New(cfg))
+	cmd.AddCommand(fix.New(cfg))
+	cmd.AddCommand(lint.New(cfg))
+	cmd.AddCommand(validate.New(cfg))
 
 	// Aliases for popular namespaced commands:
 	cmd.AddCommand(dev.New(cfg))
@@ -130,13 +202,42 @@ func New() *cobra.Command {
 	// Sub-commands:
 	cmd.AddCommand(agents.New(cfg))
 	cmd.AddCommand(apikeys.New(cfg))
+	cmd.AddCommand(approvals.New(cfg))
 	cmd.AddCommand(auth.New(cfg))
+	cmd.AddCommand(bench.New(cfg))
+	cmd.AddCommand(builds.New(cfg))
 	cmd.AddCommand(configs.New(cfg))
 	cmd.AddCommand(demo.New(cfg))
+	cmd.AddCommand(deployments.New(cfg))
+	cmd.AddCommand(envs.New(cfg))
+	cmd.AddCommand(examples.New(cfg))
+	cmd.AddCommand(pools.New(cfg))
+	cmd.AddCommand(resources.New(cfg))
 	cmd.AddCommand(tasks.New(cfg))
 	cmd.AddCommand(views.New(cfg))
 	cmd.AddCommand(runs.New(cfg))
+	cmd.AddCommand(schedules.New(cfg))
 	cmd.AddCommand(version.New(cfg))
 
 	return cmd
 }
+
+// setUnchangedFlag sets the value of the named flag on cmd and all of its descendants, unless it
+// was explicitly passed on the command line. It returns whether the flag of the command being run
+// was set.
+func setUnchangedFlag(cmd *cobra.Command, name, value string) bool {
+	var set bool
+	if f := cmd.Flags().Lookup(name); f != nil && !f.Changed {
+		if err := f.Value.Set(value); err != nil {
+			logger.Debug("unable to set --%s: %v", name, err)
+		} else if cmd.CalledAs() != "" {
+			set = true
+		}
+	}
+	for _, child := range cmd.Commands() {
+		if setUnchangedFlag(child, name, value) {
+			set = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,145 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	files  []string
+	format string
+	schema string
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "validate <file...>",
+		Short: "Validate task and view definition files against their schema",
+		Long: heredoc.Doc(`
+			Validates YAML and JSON task and view definition files against their JSON Schema, and
+			reports each violation with its line and column.
+
+			Use --schema to print the JSON Schema of task or view definitions, e.g. to configure
+			an editor. It's generated from the definitions that this version of the CLI parses.
+		`),
+		Example: heredoc.Doc(`
+			airplane validate my_task.task.yaml my_view.view.yaml
+			airplane validate my_task.task.yaml --format json
+			airplane validate --schema task > task.schema.json
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.files = args
+			return run(cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.format, "format", "text", "The format to report violations in (text|json).")
+	cmd.Flags().StringVar(&cfg.schema, "schema", "", "Print the JSON Schema of task or view definitions (task|view) instead of validating files.")
+
+	return cmd
+}
+
+// fileResult is the outcome of validating a definition file.
+type fileResult struct {
+	File   string                    `json:"file"`
+	Errors []definitions.SchemaError `json:"errors"`
+}
+
+func run(cfg config) error {
+	if cfg.schema != "" {
+		return printSchema(cfg.schema)
+	}
+	if len(cfg.files) == 0 {
+		return errors.New("expected at least one definition file to validate")
+	}
+	if cfg.format != "text" && cfg.format != "json" {
+		return errors.Errorf("unknown format %q: expected text or json", cfg.format)
+	}
+
+	results, err := validateFiles(cfg.files)
+	if err != nil {
+		return err
+	}
+
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+	if cfg.format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return errors.Wrap(err, "encoding results")
+		}
+	} else {
+		report(l, results)
+	}
+
+	var invalid int
+	for _, r := range results {
+		if len(r.Errors) > 0 {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return errors.Errorf("%d of %d definition file(s) are invalid", invalid, len(results))
+	}
+	return nil
+}
+
+func validateFiles(files []string) ([]fileResult, error) {
+	results := make([]fileResult, 0, len(files))
+	for _, file := range files {
+		buf, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", file)
+		}
+		errs, err := definitions.ValidateSchema(file, buf)
+		if err != nil {
+			return nil, errors.Wrap(err, file)
+		}
+		if errs == nil {
+			errs = []definitions.SchemaError{}
+		}
+		results = append(results, fileResult{File: file, Errors: errs})
+	}
+	return results, nil
+}
+
+func report(l logger.Logger, results []fileResult) {
+	for _, r := range results {
+		if len(r.Errors) == 0 {
+			l.Log("%s %s", logger.Green("✓"), r.File)
+			continue
+		}
+		for _, e := range r.Errors {
+			// file:line:column is the format that editors and terminals link to.
+			l.Log("%s:%s", r.File, logger.Red("%s", e))
+		}
+	}
+}
+
+func printSchema(kind string) error {
+	var schema []byte
+	var err error
+	switch kind {
+	case "task":
+		schema, err = definitions.GenerateTaskSchema()
+	case "view":
+		schema, err = definitions.GenerateViewSchema()
+	default:
+		return errors.Errorf("unknown schema %q: expected task or view", kind)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(schema))
+	return nil
+}
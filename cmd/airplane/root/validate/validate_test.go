@@ -0,0 +1,43 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFiles(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.task.yaml")
+	require.NoError(os.WriteFile(valid, []byte(`slug: valid
+shell:
+  entrypoint: valid.sh
+`), 0644))
+	invalid := filepath.Join(dir, "invalid.view.yaml")
+	require.NoError(os.WriteFile(invalid, []byte(`slug: invalid
+entrypoint: views/invalid.tsx
+bundler: webpack
+`), 0644))
+
+	results, err := validateFiles([]string{valid, invalid})
+	require.NoError(err)
+	require.Equal([]fileResult{
+		{File: valid, Errors: []definitions.SchemaError{}},
+		{File: invalid, Errors: []definitions.SchemaError{{
+			Field:   "bundler",
+			Message: `bundler must be one of the following: "airplane", "vite"`,
+			Line:    3,
+			Column:  1,
+		}}},
+	}, results)
+
+	require.EqualError(run(config{files: []string{valid, invalid}, format: "text"}), "1 of 2 definition file(s) are invalid")
+
+	_, err = validateFiles([]string{filepath.Join(dir, "missing.task.yaml")})
+	require.ErrorContains(err, "reading")
+}
@@ -0,0 +1,268 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// IndexFileName is the name of the file in the archive directory that lists every archived run, one
+// JSON object per line.
+const IndexFileName = "index.jsonl"
+
+type config struct {
+	slug      string
+	olderThan utils.DurationValue
+	dest      string
+	envSlug   string
+}
+
+// New returns a new archive command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "archive <slug>",
+		Short: "Archives the logs and outputs of a task's runs locally",
+		Long: heredoc.Doc(`
+			Downloads the logs and outputs of a task's completed runs into a local directory.
+
+			Each run is written to its own gzipped JSON file and recorded in an index.jsonl file
+			that can be searched later. Runs that are already in the index are skipped, so the
+			command can be run repeatedly, e.g. on a schedule, to archive runs before they expire.
+		`),
+		Example: heredoc.Doc(`
+			airplane runs archive my_task --older-than 90d --dest ./archive/
+			airplane runs archive my_task --dest ./archive/
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slug = args[0]
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+
+	cmd.Flags().Var(&cfg.olderThan, "older-than", `Only archive runs created at least this long ago, e.g. "90d" or "12h".`)
+	cmd.Flags().StringVar(&cfg.dest, "dest", "", "The directory to write the archive to.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	if err := cmd.MarkFlagRequired("dest"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+
+	return cmd
+}
+
+// IndexEntry describes an archived run.
+type IndexEntry struct {
+	RunID       string        `json:"runID"`
+	TaskID      string        `json:"taskID"`
+	TaskSlug    string        `json:"taskSlug"`
+	Status      api.RunStatus `json:"status"`
+	EnvSlug     string        `json:"envSlug"`
+	CreatorID   string        `json:"creatorID"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	ParamValues api.Values    `json:"paramValues"`
+	LogCount    int           `json:"logCount"`
+	// File is the path of the archived run, relative to the archive directory.
+	File       string    `json:"file"`
+	ArchivedAt time.Time `json:"archivedAt"`
+}
+
+// Record is the contents of an archived run file.
+type Record struct {
+	Run     api.Run       `json:"run"`
+	Logs    []api.LogItem `json:"logs"`
+	Outputs api.Outputs   `json:"outputs"`
+}
+
+func run(ctx context.Context, c *cli.Config, cfg config) error {
+	task, err := c.Client.GetTask(ctx, libapi.GetTaskRequest{
+		Slug:    cfg.slug,
+		EnvSlug: cfg.envSlug,
+	})
+	if err != nil {
+		return err
+	}
+
+	req := api.ListRunsRequest{
+		TaskID:  task.ID,
+		EnvSlug: cfg.envSlug,
+	}
+	if cfg.olderThan > 0 {
+		req.Until = time.Now().Add(-time.Duration(cfg.olderThan))
+	}
+	resp, err := c.Client.ListRuns(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "listing runs")
+	}
+
+	a, err := openArchive(cfg.dest)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	var archived, skipped int
+	for _, r := range resp.Runs {
+		if !r.Status.IsTerminal() || a.archived[r.RunID] {
+			skipped++
+			continue
+		}
+		if err := a.add(ctx, c.Client, task.Slug, r); err != nil {
+			return errors.Wrapf(err, "archiving run %s", r.RunID)
+		}
+		archived++
+	}
+
+	logger.Log("Archived %d run(s) of %s to %s.", archived, task.Slug, cfg.dest)
+	if skipped > 0 {
+		logger.Log("Skipped %d run(s) that were already archived or have not finished.", skipped)
+	}
+	return nil
+}
+
+type archive struct {
+	dir      string
+	index    *os.File
+	archived map[string]bool
+}
+
+// openArchive opens the archive in dir, creating it if necessary, and reads which runs it contains.
+func openArchive(dir string) (*archive, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "creating %s", dir)
+	}
+	a := &archive{dir: dir, archived: map[string]bool{}}
+
+	indexPath := filepath.Join(dir, IndexFileName)
+	f, err := os.Open(indexPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.Wrapf(err, "opening %s", indexPath)
+	} else if err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1024*1024)
+		for scanner.Scan() {
+			var entry IndexEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				return nil, errors.Wrapf(err, "reading %s", indexPath)
+			}
+			a.archived[entry.RunID] = true
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errors.Wrapf(err, "reading %s", indexPath)
+		}
+	}
+
+	a.index, err = os.OpenFile(indexPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", indexPath)
+	}
+	return a, nil
+}
+
+func (a *archive) Close() error {
+	return a.index.Close()
+}
+
+// add downloads a run and writes it to the archive. The run file is written before the index entry
+// so that an interrupted archive never indexes a missing file.
+func (a *archive) add(ctx context.Context, client api.APIClient, taskSlug string, r api.Run) error {
+	logs, err := getAllLogs(ctx, client, r.RunID)
+	if err != nil {
+		return err
+	}
+	outputs, err := client.GetOutputs(ctx, r.RunID)
+	if err != nil {
+		return errors.Wrap(err, "getting outputs")
+	}
+
+	rel := filepath.Join(taskSlug, r.RunID+".json.gz")
+	if err := writeRecord(filepath.Join(a.dir, rel), Record{
+		Run:     r,
+		Logs:    logs,
+		Outputs: outputs.Outputs,
+	}); err != nil {
+		return err
+	}
+
+	entry, err := json.Marshal(IndexEntry{
+		RunID:       r.RunID,
+		TaskID:      r.TaskID,
+		TaskSlug:    taskSlug,
+		Status:      r.Status,
+		EnvSlug:     r.EnvSlug,
+		CreatorID:   r.CreatorID,
+		CreatedAt:   r.CreatedAt,
+		ParamValues: r.ParamValues,
+		LogCount:    len(logs),
+		File:        filepath.ToSlash(rel),
+		ArchivedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshaling index entry")
+	}
+	if _, err := a.index.Write(append(entry, '\n')); err != nil {
+		return errors.Wrap(err, "writing index entry")
+	}
+	a.archived[r.RunID] = true
+	return nil
+}
+
+// getAllLogs pages through all of a run's logs.
+func getAllLogs(ctx context.Context, client api.APIClient, runID string) ([]api.LogItem, error) {
+	var logs []api.LogItem
+	var prevToken string
+	for {
+		resp, err := client.GetLogs(ctx, runID, prevToken)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting logs")
+		}
+		logs = append(logs, resp.Logs...)
+		if len(resp.Logs) == 0 || resp.PrevPageToken == "" || resp.PrevPageToken == prevToken {
+			break
+		}
+		prevToken = resp.PrevPageToken
+	}
+	api.SortLogs(logs)
+	return logs, nil
+}
+
+func writeRecord(path string, record Record) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "creating %s", filepath.Dir(path))
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return errors.Wrapf(err, "creating %s", tmp)
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	zw := gzip.NewWriter(f)
+	if err := json.NewEncoder(zw).Encode(record); err != nil {
+		return errors.Wrap(err, "encoding run")
+	}
+	if err := zw.Close(); err != nil {
+		return errors.Wrap(err, "compressing run")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "writing %s", tmp)
+	}
+	return errors.Wrapf(os.Rename(tmp, path), "writing %s", path)
+}
@@ -0,0 +1,71 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	now := time.Now()
+	client := &api.MockClient{
+		Tasks: map[string]libapi.Task{
+			"my_task": {ID: "tsk1", Slug: "my_task"},
+		},
+		Runs: []api.Run{
+			{RunID: "run_old", TaskID: "tsk1", Status: api.RunSucceeded, CreatedAt: now.Add(-100 * 24 * time.Hour)},
+			{RunID: "run_old_active", TaskID: "tsk1", Status: api.RunActive, CreatedAt: now.Add(-95 * 24 * time.Hour)},
+			{RunID: "run_new", TaskID: "tsk1", Status: api.RunFailed, CreatedAt: now.Add(-time.Hour)},
+			{RunID: "run_other", TaskID: "tsk2", Status: api.RunSucceeded, CreatedAt: now.Add(-100 * 24 * time.Hour)},
+		},
+		RunLogs: map[string][]api.LogItem{
+			"run_old": {{Text: "hello", Timestamp: now.Add(-100 * 24 * time.Hour)}},
+		},
+	}
+	var olderThan utils.DurationValue
+	require.NoError(olderThan.Set("90d"))
+	cfg := config{
+		slug:      "my_task",
+		olderThan: olderThan,
+		dest:      t.TempDir(),
+	}
+
+	require.NoError(run(ctx, &cli.Config{Client: client}, cfg))
+	// Archiving again is a no-op.
+	require.NoError(run(ctx, &cli.Config{Client: client}, cfg))
+
+	index, err := os.ReadFile(filepath.Join(cfg.dest, IndexFileName))
+	require.NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(index)), "\n")
+	require.Len(lines, 1)
+	var entry IndexEntry
+	require.NoError(json.Unmarshal([]byte(lines[0]), &entry))
+	require.Equal("run_old", entry.RunID)
+	require.Equal("my_task", entry.TaskSlug)
+	require.Equal(1, entry.LogCount)
+	require.Equal("my_task/run_old.json.gz", entry.File)
+
+	f, err := os.Open(filepath.Join(cfg.dest, entry.File))
+	require.NoError(err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(err)
+	var record Record
+	require.NoError(json.NewDecoder(zr).Decode(&record))
+	require.Equal("run_old", record.Run.RunID)
+	require.Len(record.Logs, 1)
+	require.Equal("hello", record.Logs[0].Text)
+}
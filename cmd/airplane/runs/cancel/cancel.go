@@ -0,0 +1,191 @@
+package cancel
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// cancellableStatuses are the statuses of runs that haven't finished, and so can be cancelled.
+var cancellableStatuses = []api.RunStatus{api.RunNotStarted, api.RunQueued, api.RunActive}
+
+// maxRuns is the maximum number of runs that are cancelled by filter at once.
+const maxRuns = 500
+
+type config struct {
+	root *cli.Config
+
+	runID     string
+	slug      string
+	statuses  []string
+	olderThan utils.DurationValue
+	envSlug   string
+	assumeYes bool
+}
+
+// New returns a new cancel command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+	cmd := &cobra.Command{
+		Use:   "cancel [id]",
+		Short: "Cancels a run, or all runs that match filters",
+		Long: heredoc.Doc(`
+			Cancels a run by its ID, or all unfinished runs that match the given filters.
+
+			When cancelling by filter, at least one of --task, --status or --older-than is required,
+			and the matching runs are listed before asking for confirmation.
+		`),
+		Example: heredoc.Doc(`
+			airplane runs cancel <id>
+			airplane runs cancel --task my_task --status queued
+			airplane runs cancel --task my_task --older-than 2h --yes
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				cfg.runID = args[0]
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfg.slug, "task", "t", "", "Only cancel runs of the task with this slug.")
+	cmd.Flags().StringSliceVar(&cfg.statuses, "status", nil, "Only cancel runs with these statuses: notstarted, queued or active.")
+	cmd.Flags().Var(&cfg.olderThan, "older-than", `Only cancel runs created at least this long ago, e.g. "2h" or "1d".`)
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "Cancel runs without asking for confirmation.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	client := cfg.root.Client
+	hasFilters := cfg.slug != "" || len(cfg.statuses) > 0 || cfg.olderThan > 0
+
+	if cfg.runID != "" {
+		if hasFilters {
+			return errors.New("filters can't be combined with a run ID")
+		}
+		if err := client.CancelRun(ctx, api.CancelRunRequest{RunID: cfg.runID}); err != nil {
+			return errors.Wrap(err, "cancelling run")
+		}
+		logger.Log("Cancelled run %s.", cfg.runID)
+		return nil
+	}
+	if !hasFilters {
+		return errors.New("expected a run ID, or at least one of --task, --status or --older-than")
+	}
+
+	statuses, err := parseStatuses(cfg.statuses)
+	if err != nil {
+		return err
+	}
+	runs, err := listRuns(ctx, cfg, statuses)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		logger.Log("No runs to cancel.")
+		return nil
+	}
+	if len(runs) == maxRuns {
+		logger.Warning("More than %d runs match these filters. Only the %d most recent are listed, run the command again to cancel the rest.", maxRuns, maxRuns)
+	}
+
+	for _, r := range runs {
+		logger.Log("  %s (%s, created %s)", r.RunID, r.Status, r.CreatedAt.Local().Format(time.RFC3339))
+	}
+	question := "Cancel these " + pluralize(len(runs)) + "?"
+	if ok, err := cfg.root.Prompter.ConfirmWithAssumptions(question, cfg.assumeYes, false); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	var failed int
+	for _, r := range runs {
+		if err := client.CancelRun(ctx, api.CancelRunRequest{RunID: r.RunID}); err != nil {
+			logger.Warning("Failed to cancel run %s: %s", r.RunID, err)
+			failed++
+		}
+	}
+	logger.Log("Cancelled %s.", pluralize(len(runs)-failed))
+	if failed > 0 {
+		return errors.Errorf("failed to cancel %s", pluralize(failed))
+	}
+	return nil
+}
+
+// listRuns lists up to maxRuns runs that match the filters of cfg and haven't finished.
+func listRuns(ctx context.Context, cfg config, statuses []api.RunStatus) ([]api.Run, error) {
+	client := cfg.root.Client
+	req := api.ListRunsRequest{
+		EnvSlug:  cfg.envSlug,
+		Statuses: statuses,
+		Limit:    maxRuns,
+	}
+	if cfg.slug != "" {
+		task, err := client.GetTask(ctx, libapi.GetTaskRequest{
+			Slug:    cfg.slug,
+			EnvSlug: cfg.envSlug,
+		})
+		if err != nil {
+			return nil, err
+		}
+		req.TaskID = task.ID
+	}
+	if cfg.olderThan > 0 {
+		req.Until = time.Now().Add(-time.Duration(cfg.olderThan))
+	}
+	resp, err := client.ListRuns(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing runs")
+	}
+
+	return resp.Runs, nil
+}
+
+// parseStatuses parses the --status flag, case-insensitively. It defaults to all statuses of runs
+// that can be cancelled.
+func parseStatuses(values []string) ([]api.RunStatus, error) {
+	if len(values) == 0 {
+		return cancellableStatuses, nil
+	}
+	var statuses []api.RunStatus
+	for _, v := range values {
+		status, ok := findStatus(v)
+		if !ok {
+			return nil, errors.Errorf("unknown status %q: expected one of notstarted, queued or active", v)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func findStatus(v string) (api.RunStatus, bool) {
+	for _, s := range cancellableStatuses {
+		if strings.EqualFold(string(s), v) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+func pluralize(n int) string {
+	if n == 1 {
+		return "1 run"
+	}
+	return strconv.Itoa(n) + " runs"
+}
@@ -0,0 +1,79 @@
+package cancel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/prompts"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancel(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	now := time.Now()
+	client := &api.MockClient{
+		Tasks: map[string]libapi.Task{
+			"my_task": {ID: "tsk1", Slug: "my_task"},
+		},
+		Runs: []api.Run{
+			{RunID: "run_queued_old", TaskID: "tsk1", Status: api.RunQueued, CreatedAt: now.Add(-3 * time.Hour)},
+			{RunID: "run_active_old", TaskID: "tsk1", Status: api.RunActive, CreatedAt: now.Add(-3 * time.Hour)},
+			{RunID: "run_queued_new", TaskID: "tsk1", Status: api.RunQueued, CreatedAt: now.Add(-time.Minute)},
+			{RunID: "run_succeeded", TaskID: "tsk1", Status: api.RunSucceeded, CreatedAt: now.Add(-3 * time.Hour)},
+			{RunID: "run_other", TaskID: "tsk2", Status: api.RunQueued, CreatedAt: now.Add(-3 * time.Hour)},
+		},
+	}
+	statuses := func() map[string]api.RunStatus {
+		m := map[string]api.RunStatus{}
+		for _, r := range client.Runs {
+			m[r.RunID] = r.Status
+		}
+		return m
+	}
+
+	var olderThan utils.DurationValue
+	require.NoError(olderThan.Set("2h"))
+	cfg := config{
+		root:      &cli.Config{Client: client, Prompter: prompts.NewMock(false, true)},
+		slug:      "my_task",
+		statuses:  []string{"queued"},
+		olderThan: olderThan,
+	}
+	// Declining the confirmation doesn't cancel anything.
+	require.NoError(run(ctx, cfg))
+	require.Equal(api.RunQueued, statuses()["run_queued_old"])
+
+	require.NoError(run(ctx, cfg))
+	require.Equal(map[string]api.RunStatus{
+		"run_queued_old": api.RunCancelled,
+		"run_active_old": api.RunActive,
+		"run_queued_new": api.RunQueued,
+		"run_succeeded":  api.RunSucceeded,
+		"run_other":      api.RunQueued,
+	}, statuses())
+
+	// Runs can be cancelled by ID.
+	require.NoError(run(ctx, config{root: cfg.root, runID: "run_other"}))
+	require.Equal(api.RunCancelled, statuses()["run_other"])
+
+	// At most maxRuns are cancelled at once.
+	client.Runs = nil
+	for i := 0; i < maxRuns+1; i++ {
+		client.Runs = append(client.Runs, api.Run{RunID: fmt.Sprintf("run_%d", i), TaskID: "tsk1", Status: api.RunActive})
+	}
+	cfg.root.Prompter = prompts.NewMock(true)
+	require.NoError(run(ctx, config{root: cfg.root, slug: "my_task"}))
+	require.Equal(api.RunCancelled, statuses()["run_0"])
+	require.Equal(api.RunActive, statuses()[fmt.Sprintf("run_%d", maxRuns)])
+
+	require.ErrorContains(run(ctx, config{root: cfg.root}), "expected a run ID")
+	require.ErrorContains(run(ctx, config{root: cfg.root, runID: "run_other", slug: "my_task"}), "can't be combined")
+	require.ErrorContains(run(ctx, config{root: cfg.root, statuses: []string{"succeeded"}}), `unknown status "succeeded"`)
+}
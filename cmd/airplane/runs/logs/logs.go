@@ -0,0 +1,309 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/dustin/go-humanize"
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultChunkSize is the number of bytes of logs fetched per request. Interrupted requests
+	// are retried from where they stopped, so a failure never re-fetches more than a chunk.
+	defaultChunkSize = 64 * 1024 * 1024
+	// maxAttempts is the number of consecutive failed requests after which a download gives up.
+	maxAttempts = 5
+)
+
+type config struct {
+	runID     string
+	download  string
+	resume    bool
+	limitRate string
+	chunkSize int64
+	// retryWait is the wait between retries, multiplied by the number of the attempt.
+	retryWait time.Duration
+}
+
+// New returns a new logs command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{chunkSize: defaultChunkSize, retryWait: time.Second}
+
+	cmd := &cobra.Command{
+		Use:   "logs <id>",
+		Short: "Print or download the logs of a run",
+		Long: heredoc.Doc(`
+			Prints the logs of a run, or downloads them to a file with --download.
+
+			Logs are fetched in compressed chunks. If a download is interrupted, e.g. by a
+			dropped connection, run the command again with --resume to continue from the
+			end of the file instead of starting over.
+		`),
+		Example: heredoc.Doc(`
+			airplane runs logs <id>
+			airplane runs logs <id> --download logs.txt
+			airplane runs logs <id> --download logs.txt --resume
+			airplane runs logs <id> --download logs.txt --limit-rate 10MB
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.runID = args[0]
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.download, "download", "", "File to download the logs to. If not set, logs are printed to stdout.")
+	cmd.Flags().BoolVar(&cfg.resume, "resume", false, "Continue downloading to the end of an existing --download file.")
+	cmd.Flags().StringVar(&cfg.limitRate, "limit-rate", "", `Maximum download speed per second, e.g. "500KB" or "10MB".`)
+
+	return cmd
+}
+
+func run(ctx context.Context, c *cli.Config, cfg config) error {
+	if cfg.resume && cfg.download == "" {
+		return errors.New("--resume requires --download")
+	}
+
+	var limiter *rateLimiter
+	if cfg.limitRate != "" {
+		bytesPerSec, err := humanize.ParseBytes(cfg.limitRate)
+		if err != nil || bytesPerSec == 0 {
+			return errors.Errorf("invalid --limit-rate %q: expected a size such as 10MB", cfg.limitRate)
+		}
+		limiter = newRateLimiter(int64(bytesPerSec))
+	}
+
+	if cfg.download == "" {
+		_, err := download(ctx, c.Client, cfg, os.Stdout, 0, limiter, nil)
+		return err
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if cfg.resume {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(cfg.download, flags, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "opening %s", cfg.download)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", cfg.download)
+	}
+	offset := info.Size()
+	if offset > 0 {
+		logger.Log("Resuming download of %s at %s.", cfg.download, humanize.Bytes(uint64(offset)))
+	}
+
+	p := newProgress(offset)
+	stop := p.start()
+	total, err := download(ctx, c.Client, cfg, f, offset, limiter, p)
+	stop()
+	if err != nil {
+		logger.Suggest(
+			"To continue the download where it stopped, run:",
+			"airplane runs logs %s --download %s --resume",
+			cfg.runID, cfg.download,
+		)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "writing %s", cfg.download)
+	}
+
+	logger.Log("Downloaded %s of logs to %s.", humanize.Bytes(uint64(total)), logger.Bold(cfg.download))
+	return nil
+}
+
+// download copies the logs of a run, starting at offset, into w, and returns the offset at which
+// the logs end.
+func download(ctx context.Context, client api.APIClient, cfg config, w io.Writer, offset int64, limiter *rateLimiter, p *progress) (int64, error) {
+	var attempts int
+	for {
+		n, size, err := downloadChunk(ctx, client, cfg, w, offset, limiter)
+		offset += n
+		if p != nil {
+			p.update(offset, size)
+		}
+
+		var errsc libhttp.ErrStatusCode
+		if errors.As(err, &errsc) && errsc.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			// The offset is at (or past) the end of the logs.
+			return offset, nil
+		} else if err != nil {
+			if n > 0 {
+				attempts = 0
+			}
+			attempts++
+			if attempts >= maxAttempts || ctx.Err() != nil {
+				return offset, errors.Wrap(err, "downloading logs")
+			}
+			logger.Debug("Retrying log download at offset %d: %v", offset, err)
+			select {
+			case <-ctx.Done():
+				return offset, ctx.Err()
+			case <-time.After(time.Duration(attempts) * cfg.retryWait):
+			}
+			continue
+		}
+		attempts = 0
+
+		if n == 0 || (size >= 0 && offset >= size) || (size < 0 && n < cfg.chunkSize) {
+			return offset, nil
+		}
+	}
+}
+
+// downloadChunk copies up to a chunk of logs at offset into w. It returns the number of bytes
+// written and the total size of the logs, if known.
+func downloadChunk(ctx context.Context, client api.APIClient, cfg config, w io.Writer, offset int64, limiter *rateLimiter) (int64, int64, error) {
+	resp, err := client.DownloadLogs(ctx, api.DownloadLogsRequest{
+		RunID:  cfg.runID,
+		Offset: offset,
+		Length: cfg.chunkSize,
+	})
+	if err != nil {
+		return 0, -1, err
+	}
+	defer resp.Body.Close()
+
+	var r io.Reader = resp.Body
+	// If the API ignored the requested range, skip the logs that were already downloaded.
+	if resp.Offset < offset {
+		if _, err := io.CopyN(io.Discard, r, offset-resp.Offset); err != nil {
+			return 0, resp.Size, err
+		}
+	}
+	if limiter != nil {
+		r = limiter.reader(r)
+	}
+	n, err := io.Copy(w, io.LimitReader(r, cfg.chunkSize))
+	return n, resp.Size, err
+}
+
+// rateLimiter limits the combined throughput of the readers that it wraps.
+type rateLimiter struct {
+	bytesPerSec int64
+	start       time.Time
+	read        int64
+}
+
+func newRateLimiter(bytesPerSec int64) *rateLimiter {
+	return &rateLimiter{bytesPerSec: bytesPerSec, start: time.Now()}
+}
+
+func (l *rateLimiter) reader(r io.Reader) io.Reader {
+	return &limitedReader{r: r, l: l}
+}
+
+// wait blocks until n more bytes can be read without exceeding the rate limit.
+func (l *rateLimiter) wait(n int) {
+	l.read += int64(n)
+	due := time.Duration(float64(l.read) / float64(l.bytesPerSec) * float64(time.Second))
+	if wait := due - time.Since(l.start); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+type limitedReader struct {
+	r io.Reader
+	l *rateLimiter
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	// Read at most a second's worth of bytes at a time, so that throughput stays smooth.
+	if int64(len(p)) > r.l.bytesPerSec {
+		p = p[:r.l.bytesPerSec]
+	}
+	n, err := r.r.Read(p)
+	r.l.wait(n)
+	return n, err
+}
+
+// progress reports the progress of a download to stderr.
+type progress struct {
+	initial   int64
+	done      atomic.Int64
+	size      atomic.Int64
+	startedAt time.Time
+	tty       bool
+}
+
+func newProgress(initial int64) *progress {
+	p := &progress{
+		initial:   initial,
+		startedAt: time.Now(),
+		tty:       isatty.IsTerminal(os.Stderr.Fd()),
+	}
+	p.done.Store(initial)
+	p.size.Store(-1)
+	return p
+}
+
+func (p *progress) update(done, size int64) {
+	p.done.Store(done)
+	if size >= 0 {
+		p.size.Store(size)
+	}
+}
+
+// start prints the progress periodically until the returned function is called. Progress is
+// updated in place on terminals and printed less often otherwise, e.g. in CI logs.
+func (p *progress) start() func() {
+	interval := 10 * time.Second
+	if p.tty {
+		interval = 500 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	stopped := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for {
+			select {
+			case <-stopped:
+				if p.tty {
+					fmt.Fprintf(os.Stderr, "\r%s\n", p.String())
+				}
+				return
+			case <-ticker.C:
+				if p.tty {
+					fmt.Fprintf(os.Stderr, "\r%s", p.String())
+				} else {
+					fmt.Fprintln(os.Stderr, p.String())
+				}
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(stopped)
+		<-finished
+	}
+}
+
+func (p *progress) String() string {
+	done, size := p.done.Load(), p.size.Load()
+	var rate string
+	if elapsed := time.Since(p.startedAt).Seconds(); elapsed > 0 {
+		rate = fmt.Sprintf(" at %s/s", humanize.Bytes(uint64(float64(done-p.initial)/elapsed)))
+	}
+	if size <= 0 {
+		return fmt.Sprintf("Downloaded %s%s", humanize.Bytes(uint64(done)), rate)
+	}
+	return fmt.Sprintf("Downloaded %s of %s (%.0f%%)%s", humanize.Bytes(uint64(done)), humanize.Bytes(uint64(size)), float64(done)/float64(size)*100, rate)
+}
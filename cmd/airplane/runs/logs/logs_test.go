@@ -0,0 +1,120 @@
+package logs
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const runLogs = "line 1\nline 2\nline 3\nline 4\n"
+
+// flakyClient drops the connection after reading a few bytes of the first few downloads.
+type flakyClient struct {
+	*api.MockClient
+	failures  int
+	readBytes int64
+}
+
+func (c *flakyClient) DownloadLogs(ctx context.Context, req api.DownloadLogsRequest) (api.DownloadLogsResponse, error) {
+	resp, err := c.MockClient.DownloadLogs(ctx, req)
+	if err != nil || c.failures == 0 {
+		return resp, err
+	}
+	c.failures--
+	resp.Body = io.NopCloser(io.MultiReader(io.LimitReader(resp.Body, c.readBytes), errReader{}))
+	return resp, nil
+}
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestDownload(t *testing.T) {
+	ctx := context.Background()
+	newConfig := func(dir string) config {
+		return config{
+			runID:     "run1",
+			download:  filepath.Join(dir, "logs.txt"),
+			chunkSize: 5,
+		}
+	}
+	mock := &api.MockClient{RunLogFiles: map[string]string{"run1": runLogs}}
+
+	t.Run("download", func(t *testing.T) {
+		require := require.New(t)
+		cfg := newConfig(t.TempDir())
+		require.NoError(run(ctx, &cli.Config{Client: mock}, cfg))
+		buf, err := os.ReadFile(cfg.download)
+		require.NoError(err)
+		require.Equal(runLogs, string(buf))
+
+		// Downloading again overwrites the file.
+		require.NoError(run(ctx, &cli.Config{Client: mock}, cfg))
+		buf, err = os.ReadFile(cfg.download)
+		require.NoError(err)
+		require.Equal(runLogs, string(buf))
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		require := require.New(t)
+		cfg := newConfig(t.TempDir())
+		cfg.resume = true
+		require.NoError(os.WriteFile(cfg.download, []byte(runLogs[:10]), 0644))
+		require.NoError(run(ctx, &cli.Config{Client: mock}, cfg))
+		buf, err := os.ReadFile(cfg.download)
+		require.NoError(err)
+		require.Equal(runLogs, string(buf))
+
+		// Resuming a finished download is a no-op.
+		require.NoError(run(ctx, &cli.Config{Client: mock}, cfg))
+		buf, err = os.ReadFile(cfg.download)
+		require.NoError(err)
+		require.Equal(runLogs, string(buf))
+	})
+
+	t.Run("retries interrupted chunks", func(t *testing.T) {
+		require := require.New(t)
+		cfg := newConfig(t.TempDir())
+		client := &flakyClient{MockClient: mock, failures: maxAttempts + 1, readBytes: 3}
+		require.NoError(run(ctx, &cli.Config{Client: client}, cfg))
+		buf, err := os.ReadFile(cfg.download)
+		require.NoError(err)
+		require.Equal(runLogs, string(buf))
+	})
+
+	t.Run("gives up after repeated failures", func(t *testing.T) {
+		require := require.New(t)
+		cfg := newConfig(t.TempDir())
+		client := &flakyClient{MockClient: mock, failures: maxAttempts}
+		err := run(ctx, &cli.Config{Client: client}, cfg)
+		require.ErrorContains(err, "connection reset")
+	})
+
+	t.Run("resume requires download", func(t *testing.T) {
+		require := require.New(t)
+		err := run(ctx, &cli.Config{Client: mock}, config{runID: "run1", resume: true})
+		require.ErrorContains(err, "--resume requires --download")
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	require := require.New(t)
+
+	l := newRateLimiter(1000)
+	start := time.Now()
+	n, err := io.Copy(io.Discard, l.reader(strings.NewReader(strings.Repeat("x", 200))))
+	require.NoError(err)
+	require.Equal(int64(200), n)
+	require.GreaterOrEqual(time.Since(start), 150*time.Millisecond)
+}
@@ -3,8 +3,12 @@ package runs
 import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/runs/archive"
+	"github.com/airplanedev/cli/cmd/airplane/runs/cancel"
 	"github.com/airplanedev/cli/cmd/airplane/runs/get"
 	"github.com/airplanedev/cli/cmd/airplane/runs/list"
+	"github.com/airplanedev/cli/cmd/airplane/runs/logs"
+	"github.com/airplanedev/cli/cmd/airplane/runs/tail"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/spf13/cobra"
@@ -20,6 +24,10 @@ func New(c *cli.Config) *cobra.Command {
 		Example: heredoc.Doc(`
 			airplane runs list --task my-task
 			airplane runs get <id>
+			airplane runs logs <id> --download logs.txt --resume
+			airplane runs tail <id> --follow
+			airplane runs archive my-task --older-than 90d --dest ./archive/
+			airplane runs cancel --task my-task --status queued
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -28,6 +36,10 @@ func New(c *cli.Config) *cobra.Command {
 
 	cmd.AddCommand(list.New(c))
 	cmd.AddCommand(get.New(c))
+	cmd.AddCommand(logs.New(c))
+	cmd.AddCommand(tail.New(c))
+	cmd.AddCommand(archive.New(c))
+	cmd.AddCommand(cancel.New(c))
 
 	return cmd
 }
@@ -0,0 +1,161 @@
+package tail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	runID  string
+	follow bool
+	since  utils.DurationValue
+	json   bool
+	// pollInterval is the wait between requests for new logs with --follow.
+	pollInterval time.Duration
+}
+
+// line is the structured form of a log line, printed with --output json.
+type line struct {
+	RunID     string       `json:"runID"`
+	Timestamp time.Time    `json:"timestamp"`
+	InsertID  string       `json:"insertID"`
+	Level     api.LogLevel `json:"level"`
+	TaskSlug  string       `json:"taskSlug,omitempty"`
+	Text      string       `json:"text"`
+}
+
+// New returns a new tail command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{pollInterval: time.Second}
+
+	cmd := &cobra.Command{
+		Use:   "tail <id>",
+		Short: "Print the logs of a run as they are written",
+		Long: heredoc.Doc(`
+			Prints the logs of a run. With --follow, new logs are printed as they are written until
+			the run finishes.
+
+			With --output json, each log line is printed as a JSON object on its own line, e.g.
+			to pipe it into jq or a log aggregator.
+		`),
+		Example: heredoc.Doc(`
+			airplane runs tail <id>
+			airplane runs tail <id> --follow
+			airplane runs tail <id> --since 10m
+			airplane runs tail <id> --follow --output json | jq -r .text
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.runID = args[0]
+			_, cfg.json = print.DefaultFormatter.(*print.JSON)
+			return run(cmd.Root().Context(), c, cfg, os.Stdout)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&cfg.follow, "follow", "f", false, "Keep printing new logs until the run finishes.")
+	cmd.Flags().Var(&cfg.since, "since", `Only print logs written within this long ago, e.g. "10m" or "2h".`)
+
+	return cmd
+}
+
+func run(ctx context.Context, c *cli.Config, cfg config, w io.Writer) error {
+	var since time.Time
+	if cfg.since > 0 {
+		since = time.Now().Add(-time.Duration(cfg.since))
+	}
+	t := &tailer{client: c.Client, cfg: cfg, w: w, since: since}
+
+	if !cfg.follow {
+		return t.printNew(ctx)
+	}
+
+	for {
+		// The status is checked before fetching logs, so that the logs of a finished run are
+		// complete once they're printed.
+		resp, err := c.Client.GetRun(ctx, cfg.runID)
+		if err != nil {
+			return errors.Wrap(err, "getting run")
+		}
+		if err := t.printNew(ctx); err != nil {
+			return err
+		}
+		if (api.RunState{Status: resp.Run.Status}).Stopped() {
+			logger.Debug("Run %s finished with status %s", cfg.runID, resp.Run.Status)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cfg.pollInterval):
+		}
+	}
+}
+
+type tailer struct {
+	client    api.APIClient
+	cfg       config
+	w         io.Writer
+	since     time.Time
+	prevToken string
+}
+
+// printNew pages through the logs written since the previous call and prints them.
+func (t *tailer) printNew(ctx context.Context) error {
+	var logs []api.LogItem
+	for {
+		resp, err := t.client.GetLogs(ctx, t.cfg.runID, t.prevToken)
+		if err != nil {
+			return errors.Wrap(err, "getting logs")
+		}
+		logs = append(logs, resp.Logs...)
+		if len(resp.Logs) == 0 || resp.PrevPageToken == "" || resp.PrevPageToken == t.prevToken {
+			break
+		}
+		t.prevToken = resp.PrevPageToken
+	}
+	api.SortLogs(logs)
+
+	for _, l := range logs {
+		if !t.since.IsZero() && l.Timestamp.Before(t.since) {
+			continue
+		}
+		if err := t.print(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tailer) print(l api.LogItem) error {
+	if t.cfg.json {
+		buf, err := json.Marshal(line{
+			RunID:     t.cfg.runID,
+			Timestamp: l.Timestamp,
+			InsertID:  l.InsertID,
+			Level:     l.Level,
+			TaskSlug:  l.TaskSlug,
+			Text:      l.Text,
+		})
+		if err != nil {
+			return errors.Wrap(err, "marshaling log")
+		}
+		_, err = fmt.Fprintf(t.w, "%s\n", buf)
+		return errors.Wrap(err, "writing log")
+	}
+	_, err := fmt.Fprintf(t.w, "[%s] %s\n", logger.Gray(l.Timestamp.Local().Format(logger.TimeFormatNoDate)), l.Text)
+	return errors.Wrap(err, "writing log")
+}
@@ -0,0 +1,116 @@
+package tail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// followClient releases one batch of logs per poll of the run, and marks the run as succeeded
+// once every batch has been released.
+type followClient struct {
+	*api.MockClient
+	batches [][]api.LogItem
+	polls   int
+	served  int
+}
+
+func (c *followClient) GetRun(ctx context.Context, id string) (api.GetRunResponse, error) {
+	c.polls++
+	status := api.RunActive
+	if c.polls >= len(c.batches) {
+		status = api.RunSucceeded
+	}
+	return api.GetRunResponse{Run: api.Run{RunID: id, Status: status}}, nil
+}
+
+func (c *followClient) GetLogs(ctx context.Context, runID, prevToken string) (api.GetLogsResponse, error) {
+	if c.served >= c.polls || c.served >= len(c.batches) {
+		return api.GetLogsResponse{RunID: runID, PrevPageToken: prevToken}, nil
+	}
+	logs := c.batches[c.served]
+	c.served++
+	return api.GetLogsResponse{RunID: runID, Logs: logs, PrevPageToken: logs[len(logs)-1].InsertID}, nil
+}
+
+func logItem(insertID string, ts time.Time, text string) api.LogItem {
+	return api.LogItem{Timestamp: ts, InsertID: insertID, Text: text, Level: api.LogLevelInfo}
+}
+
+func TestTail(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	mock := &api.MockClient{
+		RunLogs: map[string][]api.LogItem{
+			"run1": {
+				logItem("2", now.Add(-time.Minute), "line 2"),
+				logItem("1", now.Add(-time.Hour), "line 1"),
+			},
+		},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		require := require.New(t)
+		var buf bytes.Buffer
+		require.NoError(run(ctx, &cli.Config{Client: mock}, config{runID: "run1"}, &buf))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(lines, 2)
+		require.True(strings.HasSuffix(lines[0], "line 1"), lines[0])
+		require.True(strings.HasSuffix(lines[1], "line 2"), lines[1])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		require := require.New(t)
+		var buf bytes.Buffer
+		require.NoError(run(ctx, &cli.Config{Client: mock}, config{runID: "run1", json: true}, &buf))
+		var texts []string
+		for _, s := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var l line
+			require.NoError(json.Unmarshal([]byte(s), &l))
+			require.Equal("run1", l.RunID)
+			texts = append(texts, l.Text)
+		}
+		require.Equal([]string{"line 1", "line 2"}, texts)
+	})
+
+	t.Run("since", func(t *testing.T) {
+		require := require.New(t)
+		var buf bytes.Buffer
+		cfg := config{runID: "run1", since: utils.DurationValue(10 * time.Minute)}
+		require.NoError(run(ctx, &cli.Config{Client: mock}, cfg, &buf))
+		require.NotContains(buf.String(), "line 1")
+		require.Contains(buf.String(), "line 2")
+	})
+
+	t.Run("follow", func(t *testing.T) {
+		require := require.New(t)
+		client := &followClient{
+			MockClient: mock,
+			batches: [][]api.LogItem{
+				{logItem("1", now, "line 1")},
+				{logItem("3", now.Add(2*time.Second), "line 3"), logItem("2", now.Add(time.Second), "line 2")},
+				{logItem("4", now.Add(3*time.Second), "line 4")},
+			},
+		}
+		var buf bytes.Buffer
+		cfg := config{runID: "run1", follow: true, json: true, pollInterval: time.Millisecond}
+		require.NoError(run(ctx, &cli.Config{Client: client}, cfg, &buf))
+
+		var texts []string
+		for _, s := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var l line
+			require.NoError(json.Unmarshal([]byte(s), &l))
+			texts = append(texts, l.Text)
+		}
+		require.Equal([]string{"line 1", "line 2", "line 3", "line 4"}, texts)
+		require.Equal(3, client.polls)
+	})
+}
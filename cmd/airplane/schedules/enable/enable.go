@@ -0,0 +1,111 @@
+package enable
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	taskSlug string
+	slugs    []string
+	all      bool
+	envSlug  string
+}
+
+// New returns a new enable command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:     "enable --task <slug> [--all | <schedule>...]",
+		Aliases: []string{"resume"},
+		Short:   "Enables paused schedules of a task",
+		Long: heredoc.Doc(`
+			Enables paused schedules of a task, e.g. schedules that were deployed with "paused: true" or
+			paused with "airplane schedules pause".
+
+			Enabling a schedule doesn't change its definition: remove "paused: true" from it, or the
+			next deploy will deploy the schedule paused again.
+		`),
+		Example: heredoc.Doc(`
+			airplane schedules enable --task my_task nightly_sync
+			airplane schedules enable --task my_task --all --env prod
+			airplane schedules resume --task my_task nightly_sync
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slugs = args
+			if cfg.all == (len(args) > 0) {
+				return errors.New("expected either --all or the slugs of the schedules to enable")
+			}
+			return run(cmd.Root().Context(), c.Client, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.taskSlug, "task", "", "The slug of the task whose schedules to enable.")
+	cmd.Flags().BoolVar(&cfg.all, "all", false, "Enable all paused schedules of the task.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	if err := cmd.MarkFlagRequired("task"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+
+	return cmd
+}
+
+func run(ctx context.Context, client api.APIClient, cfg config) error {
+	task, err := client.GetTask(ctx, libapi.GetTaskRequest{Slug: cfg.taskSlug, EnvSlug: cfg.envSlug})
+	if err != nil {
+		return err
+	}
+
+	schedules := map[string]libapi.Trigger{}
+	for _, trigger := range task.Triggers {
+		if trigger.Kind != libapi.TriggerKindSchedule || trigger.Slug == nil || trigger.ArchivedAt != nil {
+			continue
+		}
+		schedules[*trigger.Slug] = trigger
+	}
+
+	var toEnable []libapi.Trigger
+	if cfg.all {
+		for _, trigger := range task.Triggers {
+			if _, ok := schedules[pointers.ToString(trigger.Slug)]; ok && trigger.DisabledAt != nil {
+				toEnable = append(toEnable, trigger)
+			}
+		}
+	} else {
+		for _, slug := range cfg.slugs {
+			trigger, ok := schedules[slug]
+			if !ok {
+				return errors.Errorf("task %s has no schedule %s", cfg.taskSlug, slug)
+			}
+			if trigger.DisabledAt == nil {
+				logger.Log("Schedule %s is already enabled.", logger.Bold(slug))
+				continue
+			}
+			toEnable = append(toEnable, trigger)
+		}
+	}
+
+	for _, trigger := range toEnable {
+		slug := pointers.ToString(trigger.Slug)
+		if err := client.EnableTrigger(ctx, api.EnableTriggerRequest{
+			TriggerID: trigger.ID,
+			EnvSlug:   cfg.envSlug,
+		}); err != nil {
+			return errors.Wrapf(err, "enabling schedule %s", slug)
+		}
+		logger.Log("Enabled schedule %s.", logger.Bold(slug))
+	}
+	if cfg.all && len(toEnable) == 0 {
+		logger.Log("Task %s has no paused schedules.", logger.Bold(cfg.taskSlug))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package enable
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient() *api.MockClient {
+	disabledAt := time.Now()
+	schedule := func(id, slug string, disabled bool) libapi.Trigger {
+		t := libapi.Trigger{
+			ID:   id,
+			Slug: pointers.String(slug),
+			Kind: libapi.TriggerKindSchedule,
+		}
+		if disabled {
+			t.DisabledAt = &disabledAt
+		}
+		return t
+	}
+	return &api.MockClient{
+		Tasks: map[string]libapi.Task{
+			"my_task": {
+				Slug: "my_task",
+				Triggers: []libapi.Trigger{
+					{ID: "trg1", Kind: libapi.TriggerKindForm},
+					schedule("trg2", "nightly", true),
+					schedule("trg3", "hourly", true),
+					schedule("trg4", "weekly", false),
+				},
+			},
+		},
+	}
+}
+
+func disabled(client *api.MockClient) []string {
+	var slugs []string
+	for _, trigger := range client.Tasks["my_task"].Triggers {
+		if trigger.DisabledAt != nil {
+			slugs = append(slugs, *trigger.Slug)
+		}
+	}
+	return slugs
+}
+
+func TestEnable(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all", func(t *testing.T) {
+		require := require.New(t)
+		client := newClient()
+		require.NoError(run(ctx, client, config{taskSlug: "my_task", all: true}))
+		require.Empty(disabled(client))
+	})
+
+	t.Run("by slug", func(t *testing.T) {
+		require := require.New(t)
+		client := newClient()
+		require.NoError(run(ctx, client, config{taskSlug: "my_task", slugs: []string{"hourly", "weekly"}}))
+		require.Equal([]string{"nightly"}, disabled(client))
+	})
+
+	t.Run("unknown schedule", func(t *testing.T) {
+		require := require.New(t)
+		client := newClient()
+		err := run(ctx, client, config{taskSlug: "my_task", slugs: []string{"daily"}})
+		require.ErrorContains(err, "task my_task has no schedule daily")
+		require.Equal([]string{"nightly", "hourly"}, disabled(client))
+	})
+}
@@ -0,0 +1,124 @@
+package list
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	taskSlug string
+	envSlug  string
+}
+
+// Schedule is a schedule of a task, as printed by the list command.
+type Schedule struct {
+	Task     string                 `json:"task" yaml:"task"`
+	Slug     string                 `json:"slug" yaml:"slug"`
+	Name     string                 `json:"name" yaml:"name"`
+	CronExpr string                 `json:"cron" yaml:"cron"`
+	Paused   bool                   `json:"paused" yaml:"paused"`
+	Params   map[string]interface{} `json:"paramValues,omitempty" yaml:"paramValues,omitempty"`
+}
+
+// New returns a new list command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists schedules and whether they're paused",
+		Example: heredoc.Doc(`
+			airplane schedules list
+			airplane schedules list --task my_task --env prod
+			airplane schedules list -o json
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c.Client, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.taskSlug, "task", "", "The slug of a task to only list the schedules of.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+func run(ctx context.Context, client api.APIClient, cfg config) error {
+	schedules, err := listSchedules(ctx, client, cfg)
+	if err != nil {
+		return err
+	}
+
+	print.Print(schedules, func() {
+		tw := tablewriter.NewWriter(os.Stdout)
+		tw.SetBorder(false)
+		tw.SetHeader([]string{"task", "schedule", "name", "cron", "status"})
+		for _, s := range schedules {
+			status := "active"
+			if s.Paused {
+				status = "paused"
+			}
+			tw.Append([]string{s.Task, s.Slug, s.Name, s.CronExpr, status})
+		}
+		tw.Render()
+	})
+	return nil
+}
+
+// listSchedules returns the schedules of the task with cfg.taskSlug, or of every task if it's
+// empty, sorted by task and schedule.
+func listSchedules(ctx context.Context, client api.APIClient, cfg config) ([]Schedule, error) {
+	var tasks []libapi.Task
+	if cfg.taskSlug != "" {
+		task, err := client.GetTask(ctx, libapi.GetTaskRequest{Slug: cfg.taskSlug, EnvSlug: cfg.envSlug})
+		if err != nil {
+			return nil, err
+		}
+		tasks = []libapi.Task{task}
+	} else {
+		resp, err := client.ListTasks(ctx, cfg.envSlug)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing tasks")
+		}
+		tasks = resp.Tasks
+	}
+
+	schedules := []Schedule{}
+	for _, task := range tasks {
+		for _, trigger := range task.Triggers {
+			if trigger.Kind != libapi.TriggerKindSchedule || trigger.ArchivedAt != nil {
+				continue
+			}
+			s := Schedule{
+				Task:   task.Slug,
+				Slug:   pointers.ToString(trigger.Slug),
+				Name:   trigger.Name,
+				Paused: trigger.DisabledAt != nil,
+			}
+			if sc := trigger.KindConfig.Schedule; sc != nil {
+				s.CronExpr = sc.CronExpr.String()
+				s.Params = sc.ParamValues
+			}
+			schedules = append(schedules, s)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		if schedules[i].Task != schedules[j].Task {
+			return schedules[i].Task < schedules[j].Task
+		}
+		return schedules[i].Slug < schedules[j].Slug
+	})
+	return schedules, nil
+}
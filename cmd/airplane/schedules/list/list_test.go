@@ -0,0 +1,54 @@
+package list
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSchedules(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	client := &api.MockClient{
+		Tasks: map[string]libapi.Task{
+			"b_task": {
+				Slug: "b_task",
+				Triggers: []libapi.Trigger{
+					{ID: "trg1", Kind: libapi.TriggerKindForm},
+					{
+						ID:   "trg2",
+						Slug: pointers.String("nightly"),
+						Name: "Nightly",
+						Kind: libapi.TriggerKindSchedule,
+						KindConfig: libapi.TriggerKindConfig{Schedule: &libapi.TriggerKindConfigSchedule{
+							CronExpr:    libapi.CronExpr{Minute: "0", Hour: "3", DayOfMonth: "*", Month: "*", DayOfWeek: "*"},
+							ParamValues: map[string]interface{}{"dry_run": false},
+						}},
+						DisabledAt: &now,
+					},
+					{ID: "trg3", Slug: pointers.String("archived"), Kind: libapi.TriggerKindSchedule, ArchivedAt: &now},
+				},
+			},
+			"a_task": {
+				Slug:     "a_task",
+				Triggers: []libapi.Trigger{{ID: "trg4", Slug: pointers.String("hourly"), Kind: libapi.TriggerKindSchedule}},
+			},
+		},
+	}
+
+	schedules, err := listSchedules(context.Background(), client, config{})
+	require.NoError(err)
+	require.Equal([]Schedule{
+		{Task: "a_task", Slug: "hourly"},
+		{Task: "b_task", Slug: "nightly", Name: "Nightly", CronExpr: "0 3 * * *", Paused: true, Params: map[string]interface{}{"dry_run": false}},
+	}, schedules)
+
+	schedules, err = listSchedules(context.Background(), client, config{taskSlug: "a_task"})
+	require.NoError(err)
+	require.Equal([]Schedule{{Task: "a_task", Slug: "hourly"}}, schedules)
+}
@@ -0,0 +1,108 @@
+package pause
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	taskSlug string
+	slugs    []string
+	all      bool
+	envSlug  string
+}
+
+// New returns a new pause command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "pause --task <slug> [--all | <schedule>...]",
+		Short: "Pauses schedules of a task",
+		Long: heredoc.Doc(`
+			Pauses schedules of a task, so that they stop running the task until they're resumed with
+			"airplane schedules resume".
+
+			Schedules stay paused on later deploys, unless they're resumed.
+		`),
+		Example: heredoc.Doc(`
+			airplane schedules pause --task my_task nightly_sync
+			airplane schedules pause --task my_task --all --env prod
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slugs = args
+			if cfg.all == (len(args) > 0) {
+				return errors.New("expected either --all or the slugs of the schedules to pause")
+			}
+			return run(cmd.Root().Context(), c.Client, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.taskSlug, "task", "", "The slug of the task whose schedules to pause.")
+	cmd.Flags().BoolVar(&cfg.all, "all", false, "Pause all schedules of the task.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	if err := cmd.MarkFlagRequired("task"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+
+	return cmd
+}
+
+func run(ctx context.Context, client api.APIClient, cfg config) error {
+	task, err := client.GetTask(ctx, libapi.GetTaskRequest{Slug: cfg.taskSlug, EnvSlug: cfg.envSlug})
+	if err != nil {
+		return err
+	}
+
+	schedules := map[string]libapi.Trigger{}
+	for _, trigger := range task.Triggers {
+		if trigger.Kind != libapi.TriggerKindSchedule || trigger.Slug == nil || trigger.ArchivedAt != nil {
+			continue
+		}
+		schedules[*trigger.Slug] = trigger
+	}
+
+	var toPause []libapi.Trigger
+	if cfg.all {
+		for _, trigger := range task.Triggers {
+			if _, ok := schedules[pointers.ToString(trigger.Slug)]; ok && trigger.DisabledAt == nil {
+				toPause = append(toPause, trigger)
+			}
+		}
+	} else {
+		for _, slug := range cfg.slugs {
+			trigger, ok := schedules[slug]
+			if !ok {
+				return errors.Errorf("task %s has no schedule %s", cfg.taskSlug, slug)
+			}
+			if trigger.DisabledAt != nil {
+				logger.Log("Schedule %s is already paused.", logger.Bold(slug))
+				continue
+			}
+			toPause = append(toPause, trigger)
+		}
+	}
+
+	for _, trigger := range toPause {
+		slug := pointers.ToString(trigger.Slug)
+		if err := client.DisableTrigger(ctx, api.DisableTriggerRequest{
+			TriggerID: trigger.ID,
+			EnvSlug:   cfg.envSlug,
+		}); err != nil {
+			return errors.Wrapf(err, "pausing schedule %s", slug)
+		}
+		logger.Log("Paused schedule %s.", logger.Bold(slug))
+	}
+	if cfg.all && len(toPause) == 0 {
+		logger.Log("Task %s has no active schedules.", logger.Bold(cfg.taskSlug))
+	}
+	return nil
+}
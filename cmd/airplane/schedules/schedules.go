@@ -0,0 +1,40 @@
+package schedules
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/schedules/enable"
+	"github.com/airplanedev/cli/cmd/airplane/schedules/list"
+	"github.com/airplanedev/cli/cmd/airplane/schedules/pause"
+	"github.com/airplanedev/cli/cmd/airplane/schedules/trigger"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schedules",
+		Short:   "Manage schedules",
+		Long:    "Manage schedules.",
+		Aliases: []string{"schedule"},
+		Example: heredoc.Doc(`
+			airplane schedules list
+			airplane schedules pause --task my_task nightly_sync
+			airplane schedules resume --task my_task nightly_sync
+			airplane schedules enable --task my_task --all
+			airplane schedules trigger --task my_task nightly_sync
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+	}
+
+	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(enable.New(c))
+	cmd.AddCommand(pause.New(c))
+	cmd.AddCommand(trigger.New(c))
+
+	return cmd
+}
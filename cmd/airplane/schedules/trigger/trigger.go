@@ -0,0 +1,83 @@
+package trigger
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	taskSlug string
+	slug     string
+	envSlug  string
+}
+
+// New returns a new trigger command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "trigger --task <slug> <schedule>",
+		Short: "Runs a schedule's task now",
+		Long: heredoc.Doc(`
+			Runs a task now with the parameters of one of its schedules, without waiting for the
+			schedule's next run. Paused schedules can be triggered too.
+		`),
+		Example: heredoc.Doc(`
+			airplane schedules trigger --task my_task nightly_sync
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slug = args[0]
+			return run(cmd.Root().Context(), c.Client, cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.taskSlug, "task", "", "The slug of the task whose schedule to trigger.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	if err := cmd.MarkFlagRequired("task"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+
+	return cmd
+}
+
+func run(ctx context.Context, client api.APIClient, cfg config) error {
+	task, err := client.GetTask(ctx, libapi.GetTaskRequest{Slug: cfg.taskSlug, EnvSlug: cfg.envSlug})
+	if err != nil {
+		return err
+	}
+
+	var schedule *libapi.Trigger
+	for i, trigger := range task.Triggers {
+		if trigger.Kind == libapi.TriggerKindSchedule && trigger.ArchivedAt == nil && pointers.ToString(trigger.Slug) == cfg.slug {
+			schedule = &task.Triggers[i]
+			break
+		}
+	}
+	if schedule == nil {
+		return errors.Errorf("task %s has no schedule %s", cfg.taskSlug, cfg.slug)
+	}
+
+	var paramValues api.Values
+	if schedule.KindConfig.Schedule != nil {
+		paramValues = schedule.KindConfig.Schedule.ParamValues
+	}
+	resp, err := client.RunTask(ctx, api.RunTaskRequest{
+		TaskSlug:    &task.Slug,
+		ParamValues: paramValues,
+		EnvSlug:     cfg.envSlug,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "running task %s", cfg.taskSlug)
+	}
+	logger.Log("Triggered schedule %s: %s", logger.Bold(cfg.slug), client.RunURL(resp.RunID, cfg.envSlug))
+	return nil
+}
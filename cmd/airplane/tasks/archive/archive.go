@@ -0,0 +1,213 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root *cli.Config
+
+	slugs     []string
+	slugFile  utils.NewlineFileValue
+	envSlug   string
+	dir       string
+	assumeYes bool
+}
+
+// New returns a new archive command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+	cmd := &cobra.Command{
+		Use:   "archive [slug...]",
+		Short: "Archives tasks",
+		Long: heredoc.Doc(`
+			Archives tasks, which hides them and stops their schedules. Archived tasks can be restored
+			with "airplane tasks unarchive".
+
+			The tasks are listed before asking for confirmation, along with warnings about tasks that
+			have active schedules, and about tasks that are called by the tasks and views in --dir.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks archive my_task
+			airplane tasks archive my_task --dir ./airplane
+			airplane tasks archive --slug-file deprecated_tasks.txt --yes
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slugs = args
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().Var(&cfg.slugFile, "slug-file", "A file with the slugs of tasks to archive, one slug per line.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().StringVar(&cfg.dir, "dir", ".", "The directory to look for tasks and views that call the archived tasks in.")
+	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "Archive tasks without asking for confirmation.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	client := cfg.root.Client
+	slugs := taskSlugs(cfg.slugs, cfg.slugFile)
+	if len(slugs) == 0 {
+		return errors.New("expected the slugs of the tasks to archive, or --slug-file")
+	}
+
+	var tasks []libapi.Task
+	for _, slug := range slugs {
+		task, err := client.GetTask(ctx, libapi.GetTaskRequest{Slug: slug, EnvSlug: cfg.envSlug})
+		if err != nil {
+			return err
+		}
+		if task.IsArchived {
+			logger.Log("Task %s is already archived.", logger.Bold(slug))
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	taskConfigs, viewConfigs, err := discoverCallers(ctx, cfg)
+	if err != nil {
+		logger.Warning("Unable to look for tasks and views that call these tasks in %s: %v", cfg.dir, err)
+	}
+	for _, task := range tasks {
+		callers, err := discover.FindCallers(taskConfigs, viewConfigs, task.Slug)
+		if err != nil {
+			logger.Warning("Unable to look for tasks that call %s: %v", task.Slug, err)
+		}
+		logger.Log("  %s", task.Slug)
+		for _, w := range archiveWarnings(task, callers) {
+			logger.Log("    %s", logger.Yellow("%s", w))
+		}
+	}
+	question := "Archive " + pluralize(len(tasks)) + "?"
+	if ok, err := cfg.root.Prompter.ConfirmWithAssumptions(question, cfg.assumeYes, false); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	var failed int
+	for _, task := range tasks {
+		if err := client.ArchiveTask(ctx, api.ArchiveTaskRequest{TaskID: task.ID, EnvSlug: cfg.envSlug}); err != nil {
+			logger.Warning("Failed to archive task %s: %s", task.Slug, err)
+			failed++
+		}
+	}
+	logger.Log("Archived %s.", pluralize(len(tasks)-failed))
+	if failed > 0 {
+		return errors.Errorf("failed to archive %s", pluralize(failed))
+	}
+	return nil
+}
+
+// discoverCallers discovers the tasks and views in cfg.dir, which may call the tasks being
+// archived. If cfg.dir isn't set, nothing is discovered.
+func discoverCallers(ctx context.Context, cfg config) ([]discover.TaskConfig, []discover.ViewConfig, error) {
+	if cfg.dir == "" {
+		return nil, nil, nil
+	}
+	client := cfg.root.Client
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingTasks: true,
+				EnvSlug:                 cfg.envSlug,
+			},
+		},
+		ViewDiscoverers: []discover.ViewDiscoverer{
+			&discover.ViewDefnDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingViews: true,
+			},
+			&discover.CodeViewDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingViews: true,
+			},
+		},
+		Client:  client,
+		Logger:  l,
+		EnvSlug: cfg.envSlug,
+	}
+	taskConfigs, viewConfigs, err := d.Discover(ctx, cfg.dir)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "discovering tasks and views")
+	}
+	return taskConfigs, viewConfigs, nil
+}
+
+// archiveWarnings returns the reasons that archiving a task may break something: schedules that
+// stop running it, and callers that can no longer run it.
+func archiveWarnings(task libapi.Task, callers []discover.Caller) []string {
+	var warnings []string
+	var schedules []string
+	for _, trigger := range task.Triggers {
+		if trigger.Kind == libapi.TriggerKindSchedule && trigger.ArchivedAt == nil && trigger.DisabledAt == nil {
+			schedules = append(schedules, pointers.ToString(trigger.Slug))
+		}
+	}
+	if len(schedules) > 0 {
+		warnings = append(warnings, fmt.Sprintf("has active schedules that will stop running: %s", strings.Join(schedules, ", ")))
+	}
+	if len(callers) > 0 {
+		var names []string
+		for _, c := range callers {
+			names = append(names, c.Kind+" "+c.Slug)
+		}
+		warnings = append(warnings, fmt.Sprintf("is called by %s, which will fail to run it", strings.Join(names, ", ")))
+	} else if restricted := task.ExecuteRules.RestrictCallers; len(restricted) > 0 {
+		// The task is only run by other tasks or views, but none of the ones that were discovered.
+		warnings = append(warnings, fmt.Sprintf("can only be run by other %ss, which may call it from outside this directory", strings.Join(restricted, "s and ")))
+	}
+	return warnings
+}
+
+// taskSlugs returns the slugs passed as arguments and in the slug file, skipping blank lines and
+// comments of the file.
+func taskSlugs(args []string, file []string) []string {
+	slugs := append([]string{}, args...)
+	for _, line := range file {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		slugs = append(slugs, line)
+	}
+	return slugs
+}
+
+func pluralize(n int) string {
+	if n == 1 {
+		return "1 task"
+	}
+	return strconv.Itoa(n) + " tasks"
+}
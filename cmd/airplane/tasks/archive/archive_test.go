@@ -0,0 +1,115 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/prompts"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	client := &api.MockClient{
+		Tasks: map[string]libapi.Task{
+			"task_a": {ID: "tsk1", Slug: "task_a"},
+			"task_b": {ID: "tsk2", Slug: "task_b"},
+			"task_c": {ID: "tsk3", Slug: "task_c", IsArchived: true},
+			"task_d": {ID: "tsk4", Slug: "task_d"},
+		},
+	}
+	archived := func() map[string]bool {
+		m := map[string]bool{}
+		for slug, task := range client.Tasks {
+			m[slug] = task.IsArchived
+		}
+		return m
+	}
+
+	cfg := config{
+		root:     &cli.Config{Client: client, Prompter: prompts.NewMock(false, true)},
+		slugs:    []string{"task_a"},
+		slugFile: utils.NewlineFileValue{"task_b", "", "# deprecated", "task_c"},
+	}
+	// Declining the confirmation doesn't archive anything.
+	require.NoError(run(ctx, cfg))
+	require.Equal(map[string]bool{"task_a": false, "task_b": false, "task_c": true, "task_d": false}, archived())
+
+	require.NoError(run(ctx, cfg))
+	require.Equal(map[string]bool{"task_a": true, "task_b": true, "task_c": true, "task_d": false}, archived())
+
+	require.EqualError(run(ctx, config{root: cfg.root}), "expected the slugs of the tasks to archive, or --slug-file")
+}
+
+func TestArchiveWarnings(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+
+	require.Empty(archiveWarnings(libapi.Task{Slug: "my_task"}, nil))
+	task := libapi.Task{
+		Slug: "my_task",
+		Triggers: []libapi.Trigger{
+			{Slug: pointers.String("form"), Kind: libapi.TriggerKindForm},
+			{Slug: pointers.String("nightly"), Kind: libapi.TriggerKindSchedule},
+			{Slug: pointers.String("paused"), Kind: libapi.TriggerKindSchedule, DisabledAt: &now},
+			{Slug: pointers.String("old"), Kind: libapi.TriggerKindSchedule, ArchivedAt: &now},
+		},
+		ExecuteRules: libapi.ExecuteRules{RestrictCallers: []string{"task", "view"}},
+	}
+	require.Equal([]string{
+		"has active schedules that will stop running: nightly",
+		"can only be run by other tasks and views, which may call it from outside this directory",
+	}, archiveWarnings(task, nil))
+	require.Equal([]string{
+		"has active schedules that will stop running: nightly",
+		"is called by task refund, view support, which will fail to run it",
+	}, archiveWarnings(task, []discover.Caller{
+		{Kind: "task", Slug: "refund", Reason: discover.DependencyExecute},
+		{Kind: "view", Slug: "support", Reason: discover.DependencyLink},
+	}))
+}
+
+func TestDiscoverCallers(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "list_customers.task.yaml"), []byte(`slug: list_customers
+parameters:
+  - slug: region
+    type: shorttext
+    options:
+      source:
+        task: list_regions
+        valuePath: id
+python:
+  entrypoint: list_customers.py
+`), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "list_customers.py"), []byte("def main(params):\n    pass\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "support.view.yaml"), []byte(`slug: support
+name: Support
+entrypoint: support.tsx
+links:
+  regions:
+    task: list_regions
+`), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "support.tsx"), []byte("export default () => null;\n"), 0644))
+
+	cfg := config{root: &cli.Config{Client: &api.MockClient{}}, dir: dir}
+	taskConfigs, viewConfigs, err := discoverCallers(context.Background(), cfg)
+	require.NoError(err)
+	callers, err := discover.FindCallers(taskConfigs, viewConfigs, "list_regions")
+	require.NoError(err)
+	require.Equal([]discover.Caller{
+		{Kind: "task", Slug: "list_customers", Reason: discover.DependencyOptions},
+		{Kind: "view", Slug: "support", Reason: discover.DependencyLink},
+	}, callers)
+}
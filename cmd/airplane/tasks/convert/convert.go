@@ -0,0 +1,156 @@
+package convert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/runtime/javascript"
+	"github.com/airplanedev/cli/pkg/runtime/python"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	client api.APIClient
+	file   string
+	to     string
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{client: c.Client}
+
+	cmd := &cobra.Command{
+		Use:   "convert <file>",
+		Short: "Convert a task definition between YAML, JSON, and inline code",
+		Long: heredoc.Doc(`
+			Converts the definition of a task to another format and prints it. The file can be a
+			task definition file (.task.yaml or .task.json) or a file with an inline task
+			(e.g. .airplane.ts or _airplane.py).
+
+			Inline definitions only include the task's configuration: the body of the task is
+			generated as a placeholder, and should be replaced with the task's code.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks convert my_task.task.yaml --to ts > my_task.airplane.ts
+			airplane tasks convert my_task.task.yaml --to json
+			airplane tasks convert my_task_airplane.py --to yaml
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.file = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.to, "to", "", "The format to convert to: yaml, json, ts, or py.")
+	if err := cmd.MarkFlagRequired("to"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	def, inline, err := readDefinition(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	out, err := Convert(def, cfg.to)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+
+	switch {
+	case cfg.to == "ts" || cfg.to == "py":
+		logger.Warning("The body of %s was generated as a placeholder: replace it with the code of the task.", logger.Bold(def.GetSlug()))
+	case inline:
+		logger.Warning("Remove the inline config of %s from %s before deploying the converted definition.", logger.Bold(def.GetSlug()), cfg.file)
+	}
+	return nil
+}
+
+// readDefinition reads the definition of the task in file. Inline definitions are returned with
+// their file as their entrypoint.
+func readDefinition(ctx context.Context, cfg config) (definitions.Definition, bool, error) {
+	if definitions.IsTaskDef(cfg.file) {
+		buf, err := os.ReadFile(cfg.file)
+		if err != nil {
+			return definitions.Definition{}, false, errors.Wrap(err, "reading task definition")
+		}
+		var def definitions.Definition
+		if err := def.UnmarshalFile(cfg.file, buf); err != nil {
+			return definitions.Definition{}, false, errors.Wrapf(err, "reading %s", cfg.file)
+		}
+		return def, false, nil
+	}
+
+	absFile, err := filepath.Abs(cfg.file)
+	if err != nil {
+		return definitions.Definition{}, false, errors.Wrap(err, "determining absolute path")
+	}
+	d := &discover.CodeTaskDiscoverer{
+		Client:                  cfg.client,
+		Logger:                  logger.NewStdErrLogger(logger.StdErrLoggerOpts{}),
+		DoNotVerifyMissingTasks: true,
+	}
+	taskConfigs, err := d.GetTaskConfigs(ctx, absFile)
+	if err != nil {
+		return definitions.Definition{}, false, errors.Wrapf(err, "reading %s", cfg.file)
+	}
+	switch len(taskConfigs) {
+	case 0:
+		return definitions.Definition{}, false, errors.Errorf("no task definitions found in %s", cfg.file)
+	case 1:
+	default:
+		return definitions.Definition{}, false, errors.Errorf("%s defines %d tasks: only files with a single task can be converted", cfg.file, len(taskConfigs))
+	}
+
+	def := taskConfigs[0].Def
+	if err := def.SetEntrypoint(filepath.Base(cfg.file)); err != nil {
+		return definitions.Definition{}, false, err
+	}
+	return def, true, nil
+}
+
+// Convert returns def in the given format: yaml, json, ts, or py.
+func Convert(def definitions.Definition, to string) ([]byte, error) {
+	switch strings.ToLower(to) {
+	case "yaml", "yml":
+		return def.Marshal(definitions.DefFormatYAML)
+	case "json":
+		return def.Marshal(definitions.DefFormatJSON)
+	case "ts":
+		if def.Node == nil {
+			return nil, errors.Errorf("only Node tasks can be converted to TypeScript: %s is a %s task", def.GetSlug(), kindName(def))
+		}
+		out, _, err := javascript.Runtime{}.GenerateInline(&def)
+		return out, err
+	case "py":
+		if def.Python == nil {
+			return nil, errors.Errorf("only Python tasks can be converted to Python: %s is a %s task", def.GetSlug(), kindName(def))
+		}
+		out, _, err := python.Runtime{}.GenerateInline(&def)
+		return out, err
+	default:
+		return nil, errors.Errorf("unknown format %q: expected yaml, json, ts, or py", to)
+	}
+}
+
+func kindName(def definitions.Definition) string {
+	kind, err := def.Kind()
+	if err != nil {
+		return "unknown"
+	}
+	return string(kind)
+}
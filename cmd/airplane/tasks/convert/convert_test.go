@@ -0,0 +1,76 @@
+package convert
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+const taskYAML = `slug: sync_users
+name: Sync users
+parameters:
+- slug: dry_run
+  type: boolean
+  default: true
+python:
+  entrypoint: sync_users.py
+schedules:
+  nightly:
+    cron: 0 0 * * *
+    paramValues:
+      dry_run: false
+permissions:
+  viewers:
+    groups:
+    - support
+  executers:
+    users:
+    - ops@example.com
+`
+
+func TestConvert(t *testing.T) {
+	require := require.New(t)
+
+	file := filepath.Join(t.TempDir(), "sync_users.task.yaml")
+	require.NoError(os.WriteFile(file, []byte(taskYAML), 0644))
+	def, inline, err := readDefinition(context.Background(), config{file: file})
+	require.NoError(err)
+	require.False(inline)
+
+	// Definitions round-trip between YAML and JSON.
+	out, err := Convert(def, "json")
+	require.NoError(err)
+	var fromJSON definitions.Definition
+	require.NoError(fromJSON.Unmarshal(definitions.DefFormatJSON, out))
+	require.Equal(def, fromJSON)
+
+	out, err = Convert(def, "yaml")
+	require.NoError(err)
+	require.Equal(taskYAML, string(out))
+
+	out, err = Convert(def, "py")
+	require.NoError(err)
+	require.Contains(string(out), `@airplane.task(
+    slug="sync_users",
+    name="Sync users",`)
+	require.Contains(string(out), `cron="0 0 * * *",`)
+	require.Contains(string(out), `permissions=airplane.ExplicitPermissions(
+        viewers=airplane.PermissionAssignees(
+            groups=["support"],
+        ),
+        executers=airplane.PermissionAssignees(
+            users=["ops@example.com"],
+        ),
+    ),`)
+	require.Contains(string(out), `def sync_users(`)
+
+	_, err = Convert(def, "ts")
+	require.ErrorContains(err, "only Node tasks can be converted to TypeScript")
+
+	_, err = Convert(def, "xml")
+	require.ErrorContains(err, "unknown format")
+}
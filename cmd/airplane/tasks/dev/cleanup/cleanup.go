@@ -0,0 +1,88 @@
+package cleanup
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/build"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	all    bool
+	dryRun bool
+}
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Removes Docker images and containers left behind by local builds",
+		Long: heredoc.Doc(`
+			Removes the Docker images that were built for tasks by local builds, e.g. by
+			"airplane deploy --dry-run", and the containers of local builds whose process has exited.
+
+			Only the image tags that the CLI created are removed: an image that is also tagged by
+			something else is kept, and images that are used by a container are skipped.
+		`),
+		Example: heredoc.Doc(`
+			airplane dev cleanup
+			airplane dev cleanup --dry-run
+			airplane dev cleanup --all
+		`),
+		// Cleaning up only talks to the local Docker daemon, so it doesn't need its parent's login
+		// check. The root command's checks still apply, e.g. parsing --output.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Root().PersistentPreRunE(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cfg.all, "all", false, "Also remove the containers of build processes that are still running.")
+	cmd.Flags().BoolVar(&cfg.dryRun, "dry-run", false, "List the objects that would be removed without removing them.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	cleaned, err := build.CleanupLocal(ctx, build.CleanupOpts{
+		All:    cfg.all,
+		DryRun: cfg.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	print.Print(cleaned, func() {
+		if len(cleaned) == 0 {
+			logger.Log("No Docker objects to clean up.")
+			return
+		}
+		verb := "Removed"
+		if cfg.dryRun {
+			verb = "Would remove"
+		}
+		var size int64
+		for _, o := range cleaned {
+			name := o.Name
+			if name == "" {
+				name = o.ID
+			}
+			logger.Log("%s %s %s", verb, o.Type, logger.Bold(name))
+			size += o.Size
+		}
+		if size > 0 {
+			logger.Log("")
+			logger.Log("%s %s in total.", verb, humanize.Bytes(uint64(size)))
+		}
+	})
+	return nil
+}
@@ -7,10 +7,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/dev/cleanup"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/dev/config"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/dev/runs"
 	"github.com/airplanedev/cli/pkg/analytics"
 	api "github.com/airplanedev/cli/pkg/api/cliapi"
 	"github.com/airplanedev/cli/pkg/cli"
@@ -31,6 +34,7 @@ import (
 type taskDevConfig struct {
 	root          *cli.Config
 	port          int
+	grpcPort      int
 	devConfigPath string
 	devConfig     *devconf.DevConfig
 	envSlug       string
@@ -49,11 +53,26 @@ type taskDevConfig struct {
 	// set, useFallbackEnv should be false & there should be no fallback environment in studio.
 	useFallbackEnv   bool
 	disableWatchMode bool
+	// noDiscoveryCache parses every file on startup, rather than only the files that changed since
+	// the last time the studio ran.
+	noDiscoveryCache bool
 	sandbox          bool
 	tunnel           bool
 	serverHost       string
 	namespace        string
 	key              string
+	// maxConcurrentRuns limits the number of local runs that execute at once.
+	maxConcurrentRuns int
+	// sandboxedRuns runs shell and Python tasks in a sandboxed native process.
+	sandboxedRuns bool
+	// Retention settings for completed runs and their temporary directories.
+	maxRuns    int
+	maxRunAge  time.Duration
+	keepFailed bool
+
+	// waitReady waits for an already running dev server to become ready and exits, instead of starting one.
+	waitReady   bool
+	waitTimeout time.Duration
 }
 
 func New(c *cli.Config) *cobra.Command {
@@ -69,9 +88,17 @@ func New(c *cli.Config) *cobra.Command {
 			airplane dev ./my.task.yaml (developing a single task)
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			// Waiting on a running studio only talks to the local server, so it doesn't require a login.
+			if cfg.waitReady {
+				return nil
+			}
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
 		}),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.waitReady {
+				return waitReady(cmd.Root().Context(), cfg)
+			}
+
 			wd, err := os.Getwd()
 			if err != nil {
 				return errors.Wrap(err, "error determining current working directory")
@@ -146,13 +173,24 @@ func New(c *cli.Config) *cobra.Command {
 	}
 
 	cmd.AddCommand(config.New(c))
+	cmd.AddCommand(cleanup.New(c))
+	cmd.AddCommand(runs.New(c))
 
 	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the fallback environment to query for remote resources and configs. If not set, does not fall back to a remote environment")
 	cmd.Flags().IntVar(&cfg.port, "port", 0, "The port to start the local airplane api server on - defaults to a random open port.")
+	cmd.Flags().IntVar(&cfg.grpcPort, "grpc-port", 0, "The port to serve the gRPC API for editors and agents on. If not set, the gRPC API is disabled.")
 	cmd.Flags().StringVar(&cfg.devConfigPath, "config-path", "", "The path to the dev config file to load into the local dev server.")
 	cmd.Flags().BoolVar(&cfg.studio, "studio", true, "Run the local Studio")
 	cmd.Flags().BoolVar(&cfg.studio, "editor", true, "Run the local Studio")
 	cmd.Flags().BoolVar(&cfg.disableWatchMode, "no-watch", false, "Disable watch mode. Changes require restarting the studio to take effect.")
+	cmd.Flags().BoolVar(&cfg.noDiscoveryCache, "no-discovery-cache", false, "Parse every task and view on startup, instead of reusing what was discovered in files that haven't changed since the studio last ran.")
+	cmd.Flags().IntVar(&cfg.maxRuns, "max-runs", state.DefaultMaxRuns, "The maximum number of completed runs, including their logs and outputs, to keep in memory. Older runs are removed automatically. Pass 0 to keep every run.")
+	cmd.Flags().DurationVar(&cfg.maxRunAge, "max-run-age", state.DefaultMaxRunAge, "How long to keep completed runs and temporary run directories before removing them, e.g. 24h. Pass 0 to keep them regardless of age.")
+	cmd.Flags().BoolVar(&cfg.keepFailed, "keep-failed", false, "Never remove failed runs or their temporary run directories, so they can be inspected.")
+	cmd.Flags().BoolVar(&cfg.sandboxedRuns, "sandboxed-runs", false, "Run shell and Python tasks with a temporary working, home and temp directory, and Python tasks in a generated virtualenv. This isolates files and dependencies but is not a security sandbox.")
+	cmd.Flags().IntVar(&cfg.maxConcurrentRuns, "max-concurrent-runs", 0, "The maximum number of runs to execute at once. Additional runs are queued and started in priority order. Defaults to no limit.")
+	cmd.Flags().BoolVar(&cfg.waitReady, "wait-ready", false, "Wait for the studio running on --port (or --server-host) to finish starting up, then exit. Exits with an error if it is not ready within --wait-timeout.")
+	cmd.Flags().DurationVar(&cfg.waitTimeout, "wait-timeout", 2*time.Minute, "How long --wait-ready waits for the studio to become ready.")
 	cmd.Flags().BoolVar(&cfg.sandbox, "sandbox", false, "Run the Studio in a sandbox context (i.e. non-interactive, remote)")
 	cmd.Flags().BoolVar(&cfg.tunnel, "tunnel", false, "Run the Studio with an ngrok tunnel")
 	cmd.Flags().StringVar(&cfg.serverHost, "server-host", "", "Set the host from which the Studio should be accessed")
@@ -231,8 +269,9 @@ func run(ctx context.Context, cfg taskDevConfig) error {
 				Logger: l,
 			},
 			&discover.CodeTaskDiscoverer{
-				Client: localClient,
-				Logger: l,
+				Client:  localClient,
+				Logger:  l,
+				EnvSlug: cfg.envSlug,
 			},
 		},
 		EnvSlug: cfg.envSlug,
@@ -257,12 +296,21 @@ func run(ctx context.Context, cfg taskDevConfig) error {
 	if err != nil {
 		return err
 	}
-	paramValues, err := parameters.CLI(cfg.args, taskConfig.Def.GetName(), params, cfg.root.Prompter)
+	// Options sources are run through the local dev server so that they can refer to local tasks.
+	paramValues, err := parameters.CLI(ctx, cfg.args, taskConfig.Def.GetName(), params, cfg.root.Prompter, parameters.CLIOpts{
+		OptionsLoader: &parameters.TaskOptionsLoader{Client: localClient, EnvSlug: cfg.envSlug},
+		FileUploader:  &parameters.APIFileUploader{Client: cfg.root.Client},
+	})
 	if errors.Is(err, flag.ErrHelp) {
 		return nil
 	} else if err != nil {
 		return err
 	}
+	// Uploaded files are passed to the task as objects with a URL to download them from.
+	paramValues, err = parameters.StandardizeParamValues(ctx, cfg.root.Client, params, paramValues)
+	if err != nil {
+		return err
+	}
 
 	resourceAttachments, err := taskConfig.Def.GetResourceAttachments()
 	if err != nil {
@@ -0,0 +1,72 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// defaultStudioPort is the first port the studio tries to listen on when --port is not set.
+const defaultStudioPort = 4000
+
+const readyPollInterval = 250 * time.Millisecond
+
+// waitReady polls the /readyz endpoint of a running studio until it reports that it is ready.
+func waitReady(ctx context.Context, cfg taskDevConfig) error {
+	host := strings.TrimSuffix(cfg.serverHost, "/")
+	if host == "" {
+		port := cfg.port
+		if port == 0 {
+			port = defaultStudioPort
+		}
+		host = fmt.Sprintf("http://127.0.0.1:%d", port)
+	}
+
+	if cfg.waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.waitTimeout)
+		defer cancel()
+	}
+
+	if err := pollReady(ctx, http.DefaultClient, host+"/readyz", readyPollInterval); err != nil {
+		return err
+	}
+	logger.Log("Studio at %s is ready.", host)
+	return nil
+}
+
+// pollReady requests url every interval until it responds with a 200, or ctx is done.
+func pollReady(ctx context.Context, client *http.Client, url string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return errors.Wrap(err, "creating readiness request")
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			lastErr = errors.Errorf("%s returned status %d", url, resp.StatusCode)
+		} else {
+			lastErr = err
+		}
+		logger.Debug("Studio is not ready yet: %v", lastErr)
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(lastErr, "waiting for studio to become ready")
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package list
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/dev/history"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	slug  string
+	dir   string
+	page  int
+	limit int
+}
+
+// New returns a new list command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists local runs",
+		Example: heredoc.Doc(`
+			airplane dev runs list
+			airplane dev runs list --task <slug>
+			airplane dev runs list --dir ./my_project --limit 10 --page 1
+			airplane dev runs list -o json
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVarP(&cfg.slug, "task", "t", "", "Filter runs by task slug.")
+	cmd.Flags().StringVar(&cfg.dir, "dir", ".", "The directory of the dev server that executed the runs.")
+	cmd.Flags().IntVar(&cfg.limit, "limit", 100, "If >0, returns at most --limit items.")
+	cmd.Flags().IntVar(&cfg.page, "page", 0, "The zero-indexed page of runs to return, in pages of --limit items.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	if cfg.page < 0 || cfg.limit < 0 {
+		return errors.New("--page and --limit must not be negative")
+	}
+	dir, err := filepath.Abs(cfg.dir)
+	if err != nil {
+		return errors.Wrap(err, "resolving --dir")
+	}
+
+	records, total, err := history.New(history.PathForDir(dir)).List(history.ListOpts{
+		TaskSlug: cfg.slug,
+		Page:     cfg.page,
+		Limit:    cfg.limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	runs := make([]dev.LocalRun, len(records))
+	for i, r := range records {
+		runs[i] = r.Run
+	}
+	print.Print(runs, func() {
+		if total == 0 {
+			logger.Log("No local runs found.")
+			return
+		}
+
+		tw := tablewriter.NewWriter(os.Stdout)
+		tw.SetBorder(false)
+		tw.SetHeader([]string{"id", "task", "status", "created at", "ended at"})
+		for _, r := range records {
+			var endedAt string
+			switch {
+			case r.Run.SucceededAt != nil:
+				endedAt = r.Run.SucceededAt.Format(time.RFC3339)
+			case r.Run.FailedAt != nil:
+				endedAt = r.Run.FailedAt.Format(time.RFC3339)
+			case r.Run.CancelledAt != nil:
+				endedAt = r.Run.CancelledAt.Format(time.RFC3339)
+			}
+			tw.Append([]string{
+				r.Run.RunID,
+				r.Run.TaskSlug,
+				string(r.Run.Status),
+				r.Run.CreatedAt.Format(time.RFC3339),
+				endedAt,
+			})
+		}
+		tw.Render()
+
+		if shown := cfg.page*cfg.limit + len(records); cfg.limit > 0 && shown < total {
+			logger.Log("")
+			logger.Log("Showing %d of %d runs. Use --page %d to see more.", len(records), total, cfg.page+1)
+		}
+	})
+	return nil
+}
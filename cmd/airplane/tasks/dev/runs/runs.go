@@ -0,0 +1,39 @@
+package runs
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/dev/runs/list"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/dev/runs/show"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "Inspect the history of local runs",
+		Long: heredoc.Doc(`
+			Inspects the runs executed by the local dev servers of a directory, including their parameters,
+			logs, and outputs. The values of sensitive parameters aren't kept, and secrets are redacted from
+			logs. Runs are kept until they fall outside of the dev server's --max-runs and --max-run-age
+			limits, which default to 1000 runs and a week, and at most 1000 completed runs are kept if
+			--max-runs is 0.
+		`),
+		Example: heredoc.Doc(`
+			airplane dev runs list
+			airplane dev runs list --task my_task --dir ./my_project
+			airplane dev runs show <id>
+		`),
+		// The run history is read from disk, so this doesn't need its parent's login check. The root
+		// command's checks still apply, e.g. parsing --output.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Root().PersistentPreRunE(cmd, args)
+		},
+	}
+
+	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(show.New(c))
+
+	return cmd
+}
@@ -0,0 +1,107 @@
+package show
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/dev/history"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	runID string
+	dir   string
+}
+
+// New returns a new show command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Shows a local run, including its parameters, logs, and outputs",
+		Example: heredoc.Doc(`
+			airplane dev runs show <id>
+			airplane dev runs show <id> -o json
+			airplane dev runs show <id> --dir ./my_project
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.runID = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.dir, "dir", ".", "The directory of the dev server that executed the run.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	dir, err := filepath.Abs(cfg.dir)
+	if err != nil {
+		return errors.Wrap(err, "resolving --dir")
+	}
+	r, ok, err := history.New(history.PathForDir(dir)).Get(cfg.runID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Errorf("run %s not found in the local run history", cfg.runID)
+	}
+
+	print.Print(r, func() {
+		logger.Log("%s %s", logger.Bold("Run:"), r.Run.RunID)
+		logger.Log("%s %s", logger.Bold("Task:"), r.Run.TaskSlug)
+		logger.Log("%s %s", logger.Bold("Status:"), r.Run.Status)
+		logger.Log("%s %s", logger.Bold("Created at:"), r.Run.CreatedAt.Format(time.RFC3339))
+		logger.Log("%s %s", logger.Bold("Directory:"), r.Dir)
+
+		if len(r.Run.ParamValues) > 0 {
+			logger.Log("")
+			logger.Log("%s", logger.Bold("Parameters"))
+			slugs := make([]string, 0, len(r.Run.ParamValues))
+			for slug := range r.Run.ParamValues {
+				slugs = append(slugs, slug)
+			}
+			sort.Strings(slugs)
+			tw := tablewriter.NewWriter(os.Stdout)
+			tw.SetBorder(false)
+			for _, slug := range slugs {
+				v, err := json.Marshal(r.Run.ParamValues[slug])
+				if err != nil {
+					logger.Debug("Unable to print parameter %s: %v", slug, err)
+					continue
+				}
+				tw.Append([]string{slug, string(v)})
+			}
+			tw.Render()
+		}
+
+		logger.Log("")
+		logger.Log("%s", logger.Bold("Logs"))
+		if len(r.Logs) == 0 {
+			logger.Log("No logs.")
+		}
+		for _, l := range r.Logs {
+			logger.Log("[%s] %s", l.Timestamp.Format(time.RFC3339), l.Text)
+		}
+
+		if r.Run.Outputs.V != nil {
+			logger.Log("")
+			logger.Log("%s", logger.Bold("Outputs"))
+			print.Outputs(r.Run.Outputs)
+		}
+	})
+	return nil
+}
@@ -14,15 +14,19 @@ import (
 	"github.com/airplanedev/cli/pkg/analytics"
 	api "github.com/airplanedev/cli/pkg/api/cliapi"
 	build "github.com/airplanedev/cli/pkg/build/clibuild"
+	deployconfig "github.com/airplanedev/cli/pkg/deploy/config"
 	"github.com/airplanedev/cli/pkg/deploy/discover"
 	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/dev/history"
 	"github.com/airplanedev/cli/pkg/server"
 	"github.com/airplanedev/cli/pkg/server/filewatcher"
+	"github.com/airplanedev/cli/pkg/server/network"
 	"github.com/airplanedev/cli/pkg/server/state"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/airplanedev/cli/pkg/utils/fsx"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/airplanedev/cli/pkg/version"
 	"github.com/pkg/errors"
 )
 
@@ -84,10 +88,12 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 	}
 
 	apiServer, port, err := server.Start(server.Options{
-		Port:     cfg.port,
-		Sandbox:  cfg.sandbox,
-		Listener: ln,
-		Token:    devToken,
+		Port:              cfg.port,
+		Sandbox:           cfg.sandbox,
+		Listener:          ln,
+		Token:             devToken,
+		MaxConcurrentRuns: cfg.maxConcurrentRuns,
+		GRPCPort:          cfg.grpcPort,
 	})
 	if err != nil {
 		return errors.Wrap(err, "starting local dev server")
@@ -106,6 +112,11 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 
 	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
 	discoveryEnvVars := dev.GetDiscoveryEnvVars(cfg.devConfig)
+	// Tasks and views are parsed from the same files, so they share a cache.
+	var parseCache *discover.ParseCache
+	if !cfg.noDiscoveryCache {
+		parseCache = discover.OpenParseCache(dev.DiscoveryCachePath(absoluteDir), version.Get())
+	}
 	// Discover local tasks and views in the directory of the file.
 	d := &discover.Discoverer{
 		TaskDiscoverers: []discover.TaskDiscoverer{
@@ -120,6 +131,8 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 				Logger:                  l,
 				DoNotVerifyMissingTasks: true,
 				Env:                     discoveryEnvVars,
+				EnvSlug:                 cfg.envSlug,
+				ParseCache:              parseCache,
 			},
 		},
 		ViewDiscoverers: []discover.ViewDiscoverer{
@@ -133,6 +146,7 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 				Logger:                  l,
 				DoNotVerifyMissingViews: true,
 				Env:                     discoveryEnvVars,
+				ParseCache:              parseCache,
 			},
 		},
 		EnvSlug: cfg.envSlug,
@@ -159,7 +173,14 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 		DevConfig:            cfg.devConfig,
 		// TODO: can we pass ctx here? This was left as-is during the lib/cli merge.
 		//nolint:contextcheck
-		Executor:         dev.NewLocalExecutor(),
+		Executor:      dev.NewLocalExecutor(),
+		SandboxedRuns: cfg.sandboxedRuns,
+		RetentionPolicy: state.RetentionPolicy{
+			MaxRuns:    cfg.maxRuns,
+			MaxAge:     cfg.maxRunAge,
+			KeepFailed: cfg.keepFailed,
+		},
+		History:          history.New(history.PathForDir(absoluteDir)),
 		Dir:              absoluteDir,
 		AuthInfo:         authInfo,
 		Discoverer:       d,
@@ -168,16 +189,33 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 		SandboxState:     sandboxState,
 		ServerHost:       serverHost,
 	})
+	if err := apiServer.LoadRunHistory(); err != nil {
+		logger.Warning("Unable to load the history of local runs: %v", err)
+	}
+	apiServer.StartRetention(ctx)
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
+	// A workspace is discovered member by member, rather than as a single directory.
+	discoverPaths := []string{cfg.fileOrDir}
+	if fileInfo.IsDir() {
+		if memberPaths, ok, err := deployconfig.WorkspaceMemberPaths(cfg.fileOrDir); err != nil {
+			return err
+		} else if ok {
+			discoverPaths = memberPaths
+		}
+	}
+
 	fmt.Fprint(os.Stderr, "Discovering tasks and views... ")
-	taskConfigs, viewConfigs, err := apiServer.DiscoverTasksAndViews(ctx, cfg.fileOrDir)
+	taskConfigs, viewConfigs, err := apiServer.DiscoverTasksAndViews(ctx, discoverPaths...)
 	if err != nil {
 		logger.Log("")
 		return err
 	}
+	if err := parseCache.Save(); err != nil {
+		logger.Debug("Unable to save the discovery cache: %v", err)
+	}
 	// Print out discovered views and tasks to the user
 	numTasks := 0
 	for range taskConfigs {
@@ -233,6 +271,17 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 		if cfg.devConfigPath != "" && fsx.Exists(cfg.devConfigPath) {
 			toWatch = append(toWatch, cfg.devConfigPath)
 		}
+		// Env files are usually hidden, or outside of the watched directory, so they're watched
+		// explicitly, as are the shared files that definitions extend. Files added to tasks after
+		// the studio starts are watched after a restart.
+		for _, tc := range taskConfigs {
+			for _, path := range append(tc.Def.GetEnvFiles(), tc.Def.GetExtendedFiles()...) {
+				if fsx.Exists(path) {
+					toWatch = append(toWatch, path)
+				}
+			}
+		}
+		toWatch = utils.UniqueStrings(toWatch)
 		err := fileWatcher.Watch(cfg.fileOrDir, toWatch...)
 		if err != nil {
 			return errors.Wrap(err, "starting filewatcher")
@@ -240,6 +289,9 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 		defer fileWatcher.Stop()
 	}
 
+	// Discovery has completed and the file watcher is running, so the studio is ready to use.
+	apiServer.MarkReady()
+
 	logger.Log("")
 	studioHost := serverHost
 	if studioHost == "" {
@@ -248,6 +300,9 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 
 	studioURL := fmt.Sprintf("%s/studio?__airplane_host=%s&__env=%s", appURL, studioHost, remoteEnv.Slug)
 	logger.Log("Started studio session at %s (^C to quit)", logger.Blue(studioURL))
+	if cfg.grpcPort != 0 {
+		logger.Log("Serving the gRPC API on %s", logger.Blue(network.LocalAddress(cfg.grpcPort, cfg.sandbox)))
+	}
 
 	// Execute the flow to open the studio in the browser in a separate goroutine so fmt.Scanln doesn't capture
 	// termination signals.
@@ -265,6 +320,11 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 	// Wait for termination signal (e.g. Ctrl+C)
 	<-stop
 
+	// Files that changed while the studio was running were parsed again.
+	if err := parseCache.Save(); err != nil {
+		logger.Debug("Unable to save the discovery cache: %v", err)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	//nolint: contextcheck
@@ -0,0 +1,137 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	client   api.APIClient
+	slug     string
+	dir      string
+	envSlug  string
+	exitCode bool
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{client: c.Client}
+
+	cmd := &cobra.Command{
+		Use:   "diff <slug>",
+		Short: "Compare a task's local definition against its deployed version",
+		Long: heredoc.Doc(`
+			Shows the differences between the deployed definition of a task and its local
+			definition, as a unified diff of their YAML. Lines prefixed with "+" are what
+			deploying the local definition would change, e.g. overwriting edits that were
+			made to the task in the web UI.
+
+			Use "airplane pull" to update the local definition to match the deployed one.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks diff my_task
+			airplane tasks diff my_task --env staging --dir ./tasks
+			airplane tasks diff my_task --exit-code
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slug = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to compare against. Defaults to your team's default environment.")
+	cmd.Flags().StringVar(&cfg.dir, "dir", "", "The directory to search for the task in. Defaults to the current directory.")
+	cmd.Flags().BoolVar(&cfg.exitCode, "exit-code", false, "Exit with an error if the definitions differ.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	dir := cfg.dir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return errors.Wrap(err, "error determining current working directory")
+		}
+		dir = wd
+	}
+
+	local, err := findLocalTask(ctx, cfg, dir)
+	if err != nil {
+		return err
+	}
+
+	task, err := cfg.client.GetTask(ctx, libapi.GetTaskRequest{Slug: cfg.slug, EnvSlug: cfg.envSlug})
+	if err != nil {
+		return err
+	}
+	resp, err := cfg.client.ListResourceMetadata(ctx)
+	if err != nil {
+		return errors.Wrap(err, "listing resources")
+	}
+	remote, err := definitions.NewDefinitionFromTask(task, resp.Resources)
+	if err != nil {
+		return errors.Wrap(err, "converting deployed task to a definition")
+	}
+
+	diff, err := definitions.Diff(local, remote)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		logger.Log("The local definition of %s matches its deployed version.", logger.Bold(cfg.slug))
+		return nil
+	}
+
+	fmt.Print(diff)
+	if cfg.exitCode {
+		return errors.Errorf("the local definition of %s differs from its deployed version", cfg.slug)
+	}
+	return nil
+}
+
+// findLocalTask discovers the definition of the task in dir, either from a task definition file or
+// from inline config.
+func findLocalTask(ctx context.Context, cfg config, dir string) (definitions.Definition, error) {
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:                  cfg.client,
+				Logger:                  l,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:                  cfg.client,
+				Logger:                  l,
+				DoNotVerifyMissingTasks: true,
+				EnvSlug:                 cfg.envSlug,
+			},
+		},
+		Client:  cfg.client,
+		Logger:  l,
+		EnvSlug: cfg.envSlug,
+	}
+	taskConfigs, _, err := d.Discover(ctx, dir)
+	if err != nil {
+		return definitions.Definition{}, errors.Wrap(err, "discovering tasks")
+	}
+	for _, tc := range taskConfigs {
+		if tc.Def.GetSlug() == cfg.slug {
+			return tc.Def, nil
+		}
+	}
+	return definitions.Definition{}, errors.Errorf("no local definition of task %s found in %s", cfg.slug, dir)
+}
@@ -0,0 +1,57 @@
+package diff
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+const taskDef = `slug: my_task
+name: My task
+python:
+  entrypoint: main.py
+`
+
+func TestDiff(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(dir, "tasks"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(dir, "tasks", "my_task.task.yaml"), []byte(taskDef), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "tasks", "main.py"), []byte("print('hello')\n"), 0644))
+
+	var def definitions.Definition
+	require.NoError(def.Unmarshal(definitions.DefFormatYAML, []byte(taskDef)))
+	task, err := def.GetTask(definitions.GetTaskOpts{})
+	require.NoError(err)
+	// The API stores the entrypoint relative to the deployed bundle.
+	task.KindOptions["entrypoint"] = "tasks/main.py"
+	client := &api.MockClient{Tasks: map[string]libapi.Task{"my_task": task}}
+	cfg := config{client: client, slug: "my_task", dir: dir, exitCode: true}
+
+	// Definitions that only differ in how their entrypoint is stored are the same.
+	require.NoError(run(ctx, cfg))
+
+	task.Name = "My renamed task"
+	task.Description = "Edited in the UI"
+	client.Tasks["my_task"] = task
+	local, err := findLocalTask(ctx, cfg, dir)
+	require.NoError(err)
+	remote, err := definitions.NewDefinitionFromTask(task, nil)
+	require.NoError(err)
+	diff, err := definitions.Diff(local, remote)
+	require.NoError(err)
+	require.Contains(diff, "--- deployed/my_task\n")
+	require.Contains(diff, "-name: My renamed task\n-description: Edited in the UI\n+name: My task\n")
+	require.ErrorContains(run(ctx, cfg), "differs from its deployed version")
+
+	cfg.slug = "other_task"
+	require.ErrorContains(run(ctx, cfg), "no local definition of task other_task")
+}
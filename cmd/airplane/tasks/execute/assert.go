@@ -0,0 +1,107 @@
+package execute
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/outputs"
+	"github.com/airplanedev/ojson"
+	"github.com/airplanedev/path"
+	"github.com/pkg/errors"
+)
+
+// outputAssertion asserts that the output of a run at path equals expected.
+type outputAssertion struct {
+	raw      string
+	path     path.P
+	expected interface{}
+}
+
+// parseOutputAssertion parses an assertion of the form <path>=<value>, e.g. `rows[0].name=Alice`.
+// The path is a JSONPath-style path into the run's output, and may be empty to assert on the
+// whole output. The value is parsed as JSON if possible, and otherwise compared as a string.
+func parseOutputAssertion(s string) (outputAssertion, error) {
+	jsPath := strings.TrimPrefix(strings.TrimPrefix(s, "$"), ".")
+	p, idx, err := path.FromJSPartial(jsPath)
+	if err != nil || idx >= len(jsPath) || jsPath[idx] != '=' {
+		return outputAssertion{}, errors.Errorf("invalid output assertion %q: expected <path>=<value>, e.g. rows[0].name=Alice", s)
+	}
+
+	value := jsPath[idx+1:]
+	var expected interface{}
+	if err := json.Unmarshal([]byte(value), &expected); err != nil {
+		expected = value
+	}
+	return outputAssertion{raw: s, path: p, expected: expected}, nil
+}
+
+// check returns an error if the output o doesn't satisfy the assertion.
+func (a outputAssertion) check(o api.Outputs) error {
+	v, ok := outputs.Get(a.path, ojson.Value(o))
+	if !ok {
+		return errors.Errorf("%s: output has no value at %s", a.raw, a.displayPath())
+	}
+
+	// Values are compared as plain JSON, so that e.g. key order and number types don't matter.
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "%s: marshaling output", a.raw)
+	}
+	var actual interface{}
+	if err := json.Unmarshal(buf, &actual); err != nil {
+		return errors.Wrapf(err, "%s: unmarshaling output", a.raw)
+	}
+	if !reflect.DeepEqual(actual, a.expected) {
+		return errors.Errorf("%s: output at %s is %s", a.raw, a.displayPath(), buf)
+	}
+	return nil
+}
+
+func (a outputAssertion) displayPath() string {
+	if a.path.Len() == 0 {
+		return "the root"
+	}
+	return a.path.ToJS()
+}
+
+// checkOutputAssertions returns an error listing every assertion that the output o doesn't satisfy.
+func checkOutputAssertions(assertions []outputAssertion, o api.Outputs) error {
+	var failed []string
+	for _, a := range assertions {
+		if err := a.check(o); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("%d of %d output assertion(s) failed:\n%s", len(failed), len(assertions), strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+// parseFailOnStatuses parses the run statuses that fail the command. Only terminal statuses can
+// be used, since runs are waited on until they stop.
+func parseFailOnStatuses(statuses []string) ([]api.RunStatus, error) {
+	var parsed []api.RunStatus
+	for _, s := range statuses {
+		var status api.RunStatus
+		for _, terminal := range []api.RunStatus{api.RunSucceeded, api.RunFailed, api.RunCancelled} {
+			if strings.EqualFold(s, string(terminal)) {
+				status = terminal
+			}
+		}
+		if status == "" {
+			return nil, errors.Errorf("invalid status %q for --fail-on-status: expected %s, %s, or %s", s, api.RunSucceeded, api.RunFailed, api.RunCancelled)
+		}
+		parsed = append(parsed, status)
+	}
+	return parsed, nil
+}
+
+func runStatusError(status api.RunStatus) error {
+	if status == api.RunFailed {
+		return errors.New("Run has failed")
+	}
+	return errors.Errorf("Run has status %s", status)
+}
@@ -0,0 +1,63 @@
+package execute
+
+import (
+	"testing"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputAssertions(t *testing.T) {
+	require := require.New(t)
+
+	var o api.Outputs
+	require.NoError(o.UnmarshalJSON([]byte(`{"rows":[{"name":"Alice","tags":["a","b"]}],"count":1,"url":"https://example.com/?a=b"}`)))
+
+	for _, s := range []string{
+		"count=1",
+		"$.count=1.0",
+		"rows[0].name=Alice",
+		`rows[0].name="Alice"`,
+		`rows[0].tags=["a","b"]`,
+		`rows[0]={"tags":["a","b"],"name":"Alice"}`,
+		"url=https://example.com/?a=b",
+		`["count"]=1`,
+	} {
+		a, err := parseOutputAssertion(s)
+		require.NoError(err, s)
+		require.NoError(a.check(o), s)
+	}
+
+	var assertions []outputAssertion
+	for _, s := range []string{"count=2", "rows[1].name=Bob", "count=1"} {
+		a, err := parseOutputAssertion(s)
+		require.NoError(err)
+		assertions = append(assertions, a)
+	}
+	err := checkOutputAssertions(assertions, o)
+	require.ErrorContains(err, "2 of 3 output assertion(s) failed")
+	require.ErrorContains(err, "count=2: output at count is 1")
+	require.ErrorContains(err, "rows[1].name=Bob: output has no value at rows[1].name")
+
+	var root api.Outputs
+	require.NoError(root.UnmarshalJSON([]byte(`5`)))
+	a, err := parseOutputAssertion("=5")
+	require.NoError(err)
+	require.NoError(a.check(root))
+
+	for _, s := range []string{"count", "rows[0", "rows..name=1"} {
+		_, err := parseOutputAssertion(s)
+		require.Error(err, s)
+	}
+}
+
+func TestParseFailOnStatuses(t *testing.T) {
+	require := require.New(t)
+
+	statuses, err := parseFailOnStatuses([]string{"failed", "Cancelled"})
+	require.NoError(err)
+	require.Equal([]api.RunStatus{api.RunFailed, api.RunCancelled}, statuses)
+
+	_, err = parseFailOnStatuses([]string{"Active"})
+	require.ErrorContains(err, "invalid status")
+}
@@ -28,11 +28,18 @@ type config struct {
 	task    string
 	args    []string
 	envSlug string
+	// priority overrides the task's priority for this run, if set.
+	priority *int
+	// assertOutputs are asserted on the output of the run once it stops.
+	assertOutputs []string
+	// failOnStatuses are the statuses of runs that fail the command.
+	failOnStatuses []string
 }
 
 // New returns a new execute cobra command.
 func New(c *cli.Config) *cobra.Command {
 	var cfg = config{root: c}
+	var priority int
 
 	cmd := &cobra.Command{
 		Use:     "execute <slug>",
@@ -43,6 +50,7 @@ func New(c *cli.Config) *cobra.Command {
 			airplane execute ./task.js [-- <parameters...>]
 			airplane execute hello_world [-- <parameters...>]
 			airplane execute ./airplane.yml [-- <parameters...>]
+			airplane execute hello_world --assert-output 'greeting="Hello, World!"' --fail-on-status Failed,Cancelled
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -58,6 +66,9 @@ func New(c *cli.Config) *cobra.Command {
 			} else {
 				return errors.New("expected 1 argument: airplane execute [./path/to/file | task slug]")
 			}
+			if cmd.Flags().Changed("priority") {
+				cfg.priority = &priority
+			}
 
 			return run(cmd.Root().Context(), cfg)
 		},
@@ -68,6 +79,9 @@ func New(c *cli.Config) *cobra.Command {
 
 	// Unhide this flag once we release environments.
 	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().IntVar(&priority, "priority", 0, "Priority of this run. Overrides the task's priority. Runs with a higher priority are started first.")
+	cmd.Flags().StringArrayVar(&cfg.assertOutputs, "assert-output", nil, "Assert that the output of the run at a path equals a value, e.g. rows[0].name=Alice. Values are parsed as JSON if possible. Can be repeated.")
+	cmd.Flags().StringSliceVar(&cfg.failOnStatuses, "fail-on-status", []string{string(api.RunFailed)}, "The statuses of runs that exit with an error: Succeeded, Failed, or Cancelled.")
 
 	return cmd
 }
@@ -76,8 +90,21 @@ func New(c *cli.Config) *cobra.Command {
 func run(ctx context.Context, cfg config) error {
 	var client = cfg.root.Client
 
+	// Flags are validated before running the task, so that typos don't waste a run.
+	var assertions []outputAssertion
+	for _, s := range cfg.assertOutputs {
+		a, err := parseOutputAssertion(s)
+		if err != nil {
+			return err
+		}
+		assertions = append(assertions, a)
+	}
+	failOnStatuses, err := parseFailOnStatuses(cfg.failOnStatuses)
+	if err != nil {
+		return err
+	}
+
 	var slug string
-	var err error
 	if f, err := os.Stat(cfg.task); errors.Is(err, os.ErrNotExist) || f.IsDir() {
 		// Not a file, assume it's a slug.
 		slug = cfg.task
@@ -106,20 +133,27 @@ func run(ctx context.Context, cfg config) error {
 		TaskID:      &task.ID,
 		ParamValues: make(api.Values),
 		EnvSlug:     cfg.envSlug,
+		Priority:    cfg.priority,
 	}
 
 	logger.Log("Executing %s task: %s", logger.Bold(task.Name), logger.Gray(client.TaskURL(task.Slug, cfg.envSlug)))
 
-	req.ParamValues, err = parameters.CLI(cfg.args, task.Name, task.Parameters, cfg.root.Prompter)
+	req.ParamValues, err = parameters.CLI(ctx, cfg.args, task.Name, task.Parameters, cfg.root.Prompter, parameters.CLIOpts{
+		OptionsLoader: &parameters.TaskOptionsLoader{Client: client, EnvSlug: cfg.envSlug},
+		FileUploader:  &parameters.APIFileUploader{Client: client},
+	})
 	if errors.Is(err, flag.ErrHelp) {
 		return nil
 	} else if err != nil {
 		return err
 	}
+	if err := parameters.Validate(task.Parameters, req.ParamValues); err != nil {
+		return printValidationError(err)
+	}
 
 	w, err := client.Watcher(ctx, req)
 	if err != nil {
-		return err
+		return printValidationError(err)
 	}
 
 	logger.Log(logger.Gray("Queued run: %s", client.RunURL(w.RunID(), cfg.envSlug)))
@@ -162,11 +196,23 @@ func run(ctx context.Context, cfg config) error {
 		"env_slug":  cfg.envSlug,
 	})
 
-	switch state.Status {
-	case api.RunFailed:
-		return errors.New("Run has failed")
+	for _, status := range failOnStatuses {
+		if state.Status == status {
+			return runStatusError(status)
+		}
+	}
+	return checkOutputAssertions(assertions, state.Outputs)
+}
+
+// printValidationError prints the issues of a parameter validation error, so that scripts using
+// --output json can tell which parameters are invalid. Other errors are returned as-is.
+func printValidationError(err error) error {
+	verr, ok := parameters.AsValidationError(err)
+	if !ok {
+		return err
 	}
-	return nil
+	print.Print(verr, func() {})
+	return verr
 }
 
 type notDeployedError struct {
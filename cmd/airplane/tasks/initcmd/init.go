@@ -18,9 +18,12 @@ import (
 	"github.com/airplanedev/cli/pkg/initcmd"
 	"github.com/airplanedev/cli/pkg/prompts"
 	"github.com/airplanedev/cli/pkg/runtime"
+	_ "github.com/airplanedev/cli/pkg/runtime/deno"
+	_ "github.com/airplanedev/cli/pkg/runtime/golang"
 	_ "github.com/airplanedev/cli/pkg/runtime/javascript"
 	_ "github.com/airplanedev/cli/pkg/runtime/python"
 	_ "github.com/airplanedev/cli/pkg/runtime/rest"
+	_ "github.com/airplanedev/cli/pkg/runtime/ruby"
 	_ "github.com/airplanedev/cli/pkg/runtime/shell"
 	_ "github.com/airplanedev/cli/pkg/runtime/sql"
 	_ "github.com/airplanedev/cli/pkg/runtime/typescript"
@@ -47,6 +50,8 @@ type config struct {
 	inline   bool
 	workflow bool
 
+	sdkVersion string
+
 	newTaskInfo newTaskInfo
 }
 
@@ -54,6 +59,7 @@ type ConfigOpts struct {
 	Client      api.APIClient
 	Root        *cli.Config
 	FromRunbook string
+	SDKVersion  string
 }
 
 func GetConfig(opts ConfigOpts) config {
@@ -62,6 +68,7 @@ func GetConfig(opts ConfigOpts) config {
 		root:        opts.Root,
 		fromRunbook: opts.FromRunbook,
 		inline:      true,
+		sdkVersion:  opts.SDKVersion,
 	}
 }
 
@@ -114,6 +121,7 @@ func New(c *cli.Config) *cobra.Command {
 	cmd.Flags().BoolVar(&cfg.inline, "inline", true, "If true, the task will be configured with inline configuration. Only applicable for JavaScript & Python tasks.")
 	cmd.Flags().BoolVar(&cfg.workflow, "workflow", false, "Generate a workflow-runtime task.")
 	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment that the `from` task is in. Defaults to your team's default environment.")
+	cmd.Flags().StringVar(&cfg.sdkVersion, "sdk-version", "", "Version of the Airplane SDK to install for JavaScript and Python tasks, e.g. 0.2.40 or latest.")
 	cfg.cmd = cmd
 
 	return cmd
@@ -174,6 +182,7 @@ func Run(ctx context.Context, cfg config) error {
 		TaskKind:       cfg.newTaskInfo.kind,
 		TaskKindName:   cfg.newTaskInfo.kindName,
 		TaskEntrypoint: cfg.newTaskInfo.entrypoint,
+		SDKVersion:     cfg.sdkVersion,
 	})
 
 	return err
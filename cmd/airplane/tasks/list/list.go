@@ -2,8 +2,10 @@ package list
 
 import (
 	"context"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/print"
 	"github.com/airplanedev/cli/pkg/utils/logger"
@@ -15,6 +17,18 @@ type config struct {
 	root *cli.Config
 
 	envSlug string
+	teamID  string
+	filter  filter
+}
+
+// filter selects which tasks are listed.
+type filter struct {
+	// kinds, if set, only includes tasks of these kinds.
+	kinds []string
+	// search, if set, only includes tasks whose slug, name, or description contains it.
+	search string
+	// archived also includes archived tasks, which are otherwise left out.
+	archived bool
 }
 
 // New returns a new list command.
@@ -29,6 +43,8 @@ func New(c *cli.Config) *cobra.Command {
 		Example: heredoc.Doc(`
 			airplane tasks list
 			airplane tasks list -o json
+			airplane tasks list --kind python --kind node --search users -o yaml
+			airplane tasks list --archived
 		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return run(cmd.Root().Context(), cfg)
@@ -37,6 +53,10 @@ func New(c *cli.Config) *cobra.Command {
 
 	// Unhide this flag once we release environments.
 	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().StringVar(&cfg.teamID, "team", "", "The ID of the team to list tasks of, when authenticating with an API key. Overrides AP_TEAM_ID.")
+	cmd.Flags().StringSliceVar(&cfg.filter.kinds, "kind", nil, "Only list tasks of this kind, e.g. python. Can be repeated.")
+	cmd.Flags().StringVar(&cfg.filter.search, "search", "", "Only list tasks whose slug, name, or description contains this text.")
+	cmd.Flags().BoolVar(&cfg.filter.archived, "archived", false, "Also list archived tasks.")
 
 	return cmd
 }
@@ -44,21 +64,65 @@ func New(c *cli.Config) *cobra.Command {
 // Run runs the list command.
 func run(ctx context.Context, cfg config) error {
 	var client = cfg.root.Client
+	if cfg.teamID != "" {
+		// The team of a login is fixed, and only requests authenticated with an API key pick theirs.
+		if client.Token() != "" {
+			return errors.New("--team is only supported with an API key, logins list the tasks of the team you logged in to")
+		}
+		client.SetTeamID(cfg.teamID)
+	}
 
 	res, err := client.ListTasks(ctx, cfg.envSlug)
 	if err != nil {
 		return errors.Wrap(err, "list tasks")
 	}
 
-	if len(res.Tasks) == 0 {
-		logger.Log(heredoc.Doc(`
-			There are no tasks yet.
+	tasks := filterTasks(res.Tasks, cfg.filter)
+	// Structured formats print an empty list instead, so that scripts can parse the output.
+	if _, isTable := print.DefaultFormatter.(print.Table); isTable && len(tasks) == 0 {
+		if len(res.Tasks) == 0 {
+			logger.Log(heredoc.Doc(`
+				There are no tasks yet.
 
-			Check out the getting started guides: https://docs.airplane.dev/getting-started/tasks
-		`))
+				Check out the getting started guides: https://docs.airplane.dev/getting-started/tasks
+			`))
+		} else {
+			logger.Log("No tasks match the given filters.")
+		}
 		return nil
 	}
 
-	print.Tasks(res.Tasks)
+	print.Tasks(tasks)
 	return nil
 }
+
+// filterTasks returns the tasks that match f, in their original order.
+func filterTasks(tasks []libapi.Task, f filter) []libapi.Task {
+	search := strings.ToLower(f.search)
+	filtered := []libapi.Task{}
+	for _, t := range tasks {
+		if t.IsArchived && !f.archived {
+			continue
+		}
+		if len(f.kinds) > 0 && !matchesKind(t, f.kinds) {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(t.Slug), search) &&
+			!strings.Contains(strings.ToLower(t.Name), search) &&
+			!strings.Contains(strings.ToLower(t.Description), search) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered
+}
+
+func matchesKind(t libapi.Task, kinds []string) bool {
+	for _, kind := range kinds {
+		if strings.EqualFold(string(t.Kind), kind) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package list
+
+import (
+	"context"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterTasks(t *testing.T) {
+	tasks := []libapi.Task{
+		{Slug: "sync_users", Name: "Sync users", Kind: buildtypes.TaskKindPython},
+		{Slug: "refund", Name: "Issue refund", Description: "Refunds a user's order.", Kind: buildtypes.TaskKindNode},
+		{Slug: "report", Name: "Weekly report", Kind: buildtypes.TaskKindSQL},
+		{Slug: "old_sync", Name: "Old sync", Kind: buildtypes.TaskKindPython, IsArchived: true},
+	}
+	slugs := func(tasks []libapi.Task) []string {
+		s := []string{}
+		for _, t := range tasks {
+			s = append(s, t.Slug)
+		}
+		return s
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		filter   filter
+		expected []string
+	}{
+		{desc: "no filters", expected: []string{"sync_users", "refund", "report"}},
+		{desc: "kinds", filter: filter{kinds: []string{"Python", "sql"}}, expected: []string{"sync_users", "report"}},
+		{desc: "search", filter: filter{search: "USER"}, expected: []string{"sync_users", "refund"}},
+		{desc: "archived", filter: filter{archived: true}, expected: []string{"sync_users", "refund", "report", "old_sync"}},
+		{desc: "no matches", filter: filter{kinds: []string{"shell"}}, expected: []string{}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.Equal(t, tc.expected, slugs(filterTasks(tasks, tc.filter)))
+		})
+	}
+}
+
+func TestTeamRequiresAPIKey(t *testing.T) {
+	client := &api.MockClient{}
+	client.SetToken("token")
+	err := run(context.Background(), config{root: &cli.Config{Client: client}, teamID: "tea_other"})
+	require.EqualError(t, err, "--team is only supported with an API key, logins list the tasks of the team you logged in to")
+}
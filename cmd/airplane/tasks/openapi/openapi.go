@@ -0,0 +1,120 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/definitions/openapi"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/goccy/go-yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	client    api.APIClient
+	paths     []string
+	format    string
+	title     string
+	serverURL string
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{client: c.Client}
+
+	cmd := &cobra.Command{
+		Use:   "openapi [path...]",
+		Short: "Generate an OpenAPI document for executing tasks",
+		Long: heredoc.Doc(`
+			Generates an OpenAPI 3.1 document that describes executing the tasks discovered in the
+			given paths via the Airplane API, including the parameters of each task. The document
+			can be used to generate typed clients for your tasks.
+
+			The document is written to stdout.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks openapi > openapi.json
+			airplane tasks openapi ./tasks --format yaml
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.format, "format", "json", "The format to write the document in (json|yaml).")
+	cmd.Flags().StringVar(&cfg.title, "title", "", "The title of the document.")
+	cmd.Flags().StringVar(&cfg.serverURL, "server", openapi.DefaultServerURL, "The URL of the Airplane API.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	if cfg.format != "json" && cfg.format != "yaml" {
+		return errors.Errorf("unknown format %q: expected json or yaml", cfg.format)
+	}
+
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:                  cfg.client,
+				Logger:                  l,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:                  cfg.client,
+				Logger:                  l,
+				DoNotVerifyMissingTasks: true,
+			},
+		},
+		Client: cfg.client,
+		Logger: l,
+	}
+	taskConfigs, _, err := d.Discover(ctx, cfg.paths...)
+	if err != nil {
+		return errors.Wrap(err, "discovering tasks")
+	}
+	if len(taskConfigs) == 0 {
+		return errors.Errorf("no tasks found in %v", cfg.paths)
+	}
+
+	defs := make([]definitions.Definition, len(taskConfigs))
+	for i, tc := range taskConfigs {
+		defs[i] = tc.Def
+	}
+	doc, err := openapi.Generate(defs, openapi.Options{
+		Title:     cfg.title,
+		ServerURL: cfg.serverURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshaling document")
+	}
+	if cfg.format == "yaml" {
+		if buf, err = yaml.JSONToYAML(buf); err != nil {
+			return errors.Wrap(err, "converting document to YAML")
+		}
+	} else {
+		buf = append(buf, '\n')
+	}
+	if _, err := os.Stdout.Write(buf); err != nil {
+		return errors.Wrap(err, "writing document")
+	}
+	l.Log("Generated an OpenAPI document for %d task(s).", len(defs))
+	return nil
+}
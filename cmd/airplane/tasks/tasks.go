@@ -4,12 +4,17 @@ import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
 	"github.com/airplanedev/cli/cmd/airplane/root/deploy"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/archive"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/convert"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/dev"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/diff"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/execute"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/get"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/initcmd"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/list"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/open"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/openapi"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/unarchive"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/spf13/cobra"
@@ -26,7 +31,11 @@ func New(c *cli.Config) *cobra.Command {
 			airplane tasks init
 			airplane tasks deploy my_task.airplane.ts
 			airplane tasks get my_task
+			airplane tasks diff my_task
+			airplane tasks convert my_task.task.yaml --to ts
 			airplane tasks execute my_task
+			airplane tasks openapi > openapi.json
+			airplane tasks archive my_task
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -35,11 +44,16 @@ func New(c *cli.Config) *cobra.Command {
 
 	cmd.AddCommand(deploy.New(c))
 	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(convert.New(c))
 	cmd.AddCommand(dev.New(c))
+	cmd.AddCommand(diff.New(c))
 	cmd.AddCommand(execute.New(c))
 	cmd.AddCommand(get.New(c))
 	cmd.AddCommand(initcmd.New(c))
 	cmd.AddCommand(open.New(c))
+	cmd.AddCommand(openapi.New(c))
+	cmd.AddCommand(archive.New(c))
+	cmd.AddCommand(unarchive.New(c))
 
 	return cmd
 }
@@ -0,0 +1,98 @@
+package unarchive
+
+import (
+	"context"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	slugs    []string
+	slugFile utils.NewlineFileValue
+	envSlug  string
+}
+
+// New returns a new unarchive command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+	cmd := &cobra.Command{
+		Use:   "unarchive [slug...]",
+		Short: "Restores archived tasks",
+		Long: heredoc.Doc(`
+			Restores tasks that were archived. Schedules of the restored tasks that are paused are
+			listed, so that they can be resumed with "airplane schedules enable".
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks unarchive my_task
+			airplane tasks unarchive --slug-file tasks.txt
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slugs = args
+			return run(cmd.Root().Context(), c.Client, cfg)
+		},
+	}
+
+	cmd.Flags().Var(&cfg.slugFile, "slug-file", "A file with the slugs of tasks to unarchive, one slug per line.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+func run(ctx context.Context, client api.APIClient, cfg config) error {
+	slugs := append([]string{}, cfg.slugs...)
+	for _, line := range cfg.slugFile {
+		if line != "" && !strings.HasPrefix(line, "#") {
+			slugs = append(slugs, line)
+		}
+	}
+	if len(slugs) == 0 {
+		return errors.New("expected the slugs of the tasks to unarchive, or --slug-file")
+	}
+
+	for _, slug := range slugs {
+		task, err := client.GetTask(ctx, libapi.GetTaskRequest{Slug: slug, EnvSlug: cfg.envSlug})
+		if err != nil {
+			return err
+		}
+		if !task.IsArchived {
+			logger.Log("Task %s isn't archived.", logger.Bold(slug))
+			continue
+		}
+		if err := client.UnarchiveTask(ctx, api.UnarchiveTaskRequest{TaskID: task.ID, EnvSlug: cfg.envSlug}); err != nil {
+			return errors.Wrapf(err, "unarchiving task %s", slug)
+		}
+		logger.Log("Unarchived task %s.", logger.Bold(slug))
+
+		// Report the schedules that are paused now, rather than assuming what unarchiving does
+		// to them.
+		task, err = client.GetTask(ctx, libapi.GetTaskRequest{Slug: slug, EnvSlug: cfg.envSlug})
+		if err != nil {
+			return err
+		}
+		if paused := pausedSchedules(task); len(paused) > 0 {
+			logger.Log("  Paused schedules: %s. Resume them with:", strings.Join(paused, ", "))
+			logger.Log("    airplane schedules enable --task %s %s", slug, strings.Join(paused, " "))
+		}
+	}
+	return nil
+}
+
+// pausedSchedules returns the slugs of the schedules of task that are paused.
+func pausedSchedules(task libapi.Task) []string {
+	var slugs []string
+	for _, trigger := range task.Triggers {
+		if trigger.Kind == libapi.TriggerKindSchedule && trigger.ArchivedAt == nil && trigger.DisabledAt != nil {
+			slugs = append(slugs, pointers.ToString(trigger.Slug))
+		}
+	}
+	return slugs
+}
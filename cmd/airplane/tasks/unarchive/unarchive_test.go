@@ -0,0 +1,33 @@
+package unarchive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnarchive(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	client := &api.MockClient{
+		Tasks: map[string]libapi.Task{
+			"task_a": {ID: "tsk1", Slug: "task_a", IsArchived: true, Triggers: []libapi.Trigger{
+				{Slug: pointers.String("nightly"), Kind: libapi.TriggerKindSchedule, DisabledAt: &now},
+				{Slug: pointers.String("hourly"), Kind: libapi.TriggerKindSchedule},
+				{Slug: pointers.String("old"), Kind: libapi.TriggerKindSchedule, DisabledAt: &now, ArchivedAt: &now},
+			}},
+			"task_b": {ID: "tsk2", Slug: "task_b"},
+		},
+	}
+
+	require.NoError(run(context.Background(), client, config{slugs: []string{"task_a", "task_b"}}))
+	require.False(client.Tasks["task_a"].IsArchived)
+	require.Equal([]string{"nightly"}, pausedSchedules(client.Tasks["task_a"]))
+
+	require.EqualError(run(context.Background(), client, config{}), "expected the slugs of the tasks to unarchive, or --slug-file")
+}
@@ -0,0 +1,120 @@
+package codegen
+
+import (
+	"context"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/views"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root    *cli.Config
+	paths   []string
+	envSlug string
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "codegen [path ...]",
+		Short: "Generate typed helpers for the tasks that views link to",
+		Long: heredoc.Doc(`
+			Generates a <slug>.links.ts file for each view that declares links, next to the
+			view's definition. Each link becomes a typed helper that returns the slug and
+			parameters of the linked task, e.g. to pass to useTaskMutation.
+
+			Linked tasks are looked up in the given paths first, and otherwise in the
+			environment. Links to missing tasks or parameters are reported as errors.
+		`),
+		Example: heredoc.Doc(`
+			airplane views codegen
+			airplane views codegen ./views/my_view.view.yaml
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to look up linked tasks in. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	client := cfg.root.Client
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+
+	discoverer := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingTasks: true,
+				EnvSlug:                 cfg.envSlug,
+			},
+		},
+		ViewDiscoverers: []discover.ViewDiscoverer{
+			&discover.ViewDefnDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingViews: true,
+			},
+			&discover.CodeViewDiscoverer{
+				Client:                  client,
+				Logger:                  l,
+				DoNotVerifyMissingViews: true,
+			},
+		},
+		Client:  client,
+		Logger:  l,
+		EnvSlug: cfg.envSlug,
+	}
+	taskConfigs, viewConfigs, err := discoverer.Discover(ctx, cfg.paths...)
+	if err != nil {
+		return errors.Wrap(err, "discovering views")
+	}
+
+	taskParams, err := discover.ResolveViewLinks(ctx, client, cfg.envSlug, taskConfigs, viewConfigs)
+	if err != nil {
+		return err
+	}
+
+	var generated int
+	for _, vc := range viewConfigs {
+		if len(vc.Def.Links) == 0 {
+			continue
+		}
+		buf, err := views.GenerateLinks(vc.Def, taskParams)
+		if err != nil {
+			return errors.Wrapf(err, "generating links of view %s", vc.Def.Slug)
+		}
+		path := views.LinksPath(vc.Def)
+		if err := os.WriteFile(path, buf, 0644); err != nil {
+			return errors.Wrapf(err, "writing %s", path)
+		}
+		logger.Log("Generated %s", logger.Bold(path))
+		generated++
+	}
+
+	if generated == 0 {
+		logger.Log("No views with links found.")
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package diff
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/build"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/views"
+	"github.com/airplanedev/cli/pkg/views/viewdir"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root         *cli.Config
+	slug         string
+	deploymentID string
+	envSlug      string
+	dir          string
+	buildDir     string
+	maxAddedSize int64
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "diff <slug> --against <deployment-id>",
+		Short: "Compare a view's built assets against a deployment",
+		Long: heredoc.Doc(`
+			Builds a view locally and compares its assets (files, sizes, and hashes) against the
+			assets of a previous deployment of the view. The view is built with Docker, using the
+			same build as deployments.
+
+			Assets that were removed, as well as new assets or assets that grew by more than
+			--max-added-size bytes, are flagged.
+		`),
+		Example: heredoc.Doc(`
+			airplane views diff my_view --against dep_123
+			airplane views diff my_view --against dep_123 --build-dir ./dist
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slug = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.deploymentID, "against", "", "The ID of the deployment to compare against.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().StringVar(&cfg.dir, "dir", "", "The directory to search for the view in. Defaults to the current directory.")
+	cmd.Flags().StringVar(&cfg.buildDir, "build-dir", "", "A directory containing an existing build of the view. If not set, the view is built before comparing.")
+	cmd.Flags().Int64Var(&cfg.maxAddedSize, "max-added-size", 500*1024, "New assets or asset growth larger than this many bytes are flagged. Set to 0 to disable.")
+
+	if err := cmd.MarkFlagRequired("against"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	client := cfg.root.Client
+
+	buildDir := cfg.buildDir
+	if buildDir == "" {
+		dir := cfg.dir
+		if dir == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return errors.Wrap(err, "error determining current working directory")
+			}
+			dir = wd
+		}
+
+		vd, err := viewdir.NewViewDirectoryFromSlug(ctx, client, dir, cfg.slug, cfg.envSlug)
+		if err != nil {
+			return err
+		}
+
+		tmpdir, err := os.MkdirTemp("", "airplane-view-build-*")
+		if err != nil {
+			return errors.Wrap(err, "creating temporary build directory")
+		}
+		defer os.RemoveAll(tmpdir)
+
+		logger.Step("Building %s", cfg.slug)
+		if err := buildView(ctx, vd, tmpdir); err != nil {
+			return err
+		}
+		buildDir = tmpdir
+	}
+
+	local, err := views.BuildAssetManifest(buildDir)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.GetViewAssetManifest(ctx, api.GetViewAssetManifestRequest{
+		ViewSlug:     cfg.slug,
+		DeploymentID: cfg.deploymentID,
+		EnvSlug:      cfg.envSlug,
+	})
+	if err != nil {
+		return errors.Wrap(err, "getting deployed asset manifest")
+	}
+
+	diff := views.DiffAssets(resp.Assets, local)
+	if diff.IsEmpty() {
+		logger.Log("No changes to assets since deployment %s.", cfg.deploymentID)
+		return nil
+	}
+
+	for _, a := range diff.Added {
+		logger.Log("+ %s (%s)", a.Path, views.FormatSize(a.Size))
+	}
+	for _, a := range diff.Removed {
+		logger.Log("- %s (%s)", a.Path, views.FormatSize(a.Size))
+	}
+	for _, c := range diff.Changed {
+		path := c.After.Path
+		if c.Before.Path != c.After.Path {
+			path = c.Before.Path + " -> " + c.After.Path
+		}
+		logger.Log("~ %s (%s -> %s)", path, views.FormatSize(c.Before.Size), views.FormatSize(c.After.Size))
+	}
+
+	if warnings := diff.Warnings(cfg.maxAddedSize); len(warnings) > 0 {
+		logger.Log("")
+		for _, w := range warnings {
+			logger.Warning("%s", w)
+		}
+	}
+
+	return nil
+}
+
+// buildView builds the view in vd with the same Dockerfile that deployments use, and writes its
+// assets to outDir.
+func buildView(ctx context.Context, vd viewdir.ViewDirectory, outDir string) error {
+	root := vd.Root()
+	entrypoint, err := filepath.Rel(root, vd.EntrypointPath())
+	if err != nil {
+		return errors.Wrap(err, "figuring out entrypoint")
+	}
+	buildContext, err := discover.ViewBuildContext(root)
+	if err != nil {
+		return err
+	}
+	buildContext.Type = buildtypes.ViewBuildType
+
+	b, _, err := build.NewBundleBuilder(build.BundleLocalConfig{
+		Root:         root,
+		BuildContext: buildContext,
+		Options: buildtypes.KindOptions{
+			"shim":    "true",
+			"bundler": string(vd.Bundler()),
+		},
+		FilesToBuild: []string{filepath.ToSlash(entrypoint)},
+		OutputDir:    outDir,
+	})
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	if _, err := b.Build(ctx, "diff-"+vd.Slug(), "latest"); err != nil {
+		return errors.Wrap(err, "building view")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package export
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/views/viewexport"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root  *cli.Config
+	slugs []string
+	all   bool
+	out   string
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "export [slugs...]",
+		Short: "Export views to files",
+		Long: heredoc.Doc(`
+			Exports the name, description, environment variables and permissions of views into a
+			directory, with one file per view. The export can be imported into another team with
+			airplane views import.
+
+			Users and groups in permissions are exported by email and name, so that they can be
+			matched in the other team. Environment variables are exported in plain text.
+		`),
+		Example: heredoc.Doc(`
+			airplane views export --all --out ./views-export
+			airplane views export my_view my_other_view
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slugs = args
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cfg.all, "all", false, "Export every view of the team.")
+	cmd.Flags().StringVar(&cfg.out, "out", "views-export", "The directory to export to.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	if cfg.all == (len(cfg.slugs) > 0) {
+		return errors.New("expected either --all or the slugs of the views to export")
+	}
+
+	views, err := viewexport.Export(ctx, cfg.root.Client, cfg.slugs)
+	if err != nil {
+		return err
+	}
+	if err := viewexport.Write(cfg.out, views); err != nil {
+		return err
+	}
+
+	logger.Log("Exported %d views to %s", len(views), cfg.out)
+	return nil
+}
@@ -0,0 +1,63 @@
+package importcmd
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/views/viewexport"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root *cli.Config
+	dir  string
+}
+
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "import <dir>",
+		Short: "Import views exported with airplane views export",
+		Long: heredoc.Doc(`
+			Creates or updates the views exported to a directory by airplane views export.
+
+			The permissions of each view are replaced. Permissions of users or groups that don't
+			exist in this team, matched by email and name, are skipped with a warning.
+		`),
+		Example: heredoc.Doc(`
+			airplane views import ./views-export
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.dir = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	views, err := viewexport.Read(cfg.dir)
+	if err != nil {
+		return err
+	}
+	if len(views) == 0 {
+		return errors.Errorf("no exported views found in %s", cfg.dir)
+	}
+
+	res, err := viewexport.Import(ctx, cfg.root.Client, views)
+	for _, skipped := range res.Skipped {
+		logger.Warning("Skipped permission of %s: not found in this team", skipped)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Log("Imported %d views (%d created, %d updated)", len(res.Created)+len(res.Updated), len(res.Created), len(res.Updated))
+	return nil
+}
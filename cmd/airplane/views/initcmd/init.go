@@ -13,15 +13,16 @@ import (
 )
 
 type config struct {
-	root   *cli.Config
-	dryRun bool
-	name   string
-	from   string
-	cmd    *cobra.Command
+	root       *cli.Config
+	dryRun     bool
+	name       string
+	from       string
+	sdkVersion string
+	cmd        *cobra.Command
 }
 
 func New(c *cli.Config) *cobra.Command {
-	var cfg = GetConfig(c)
+	var cfg = GetConfig(ConfigOpts{Root: c})
 
 	cmd := &cobra.Command{
 		Use:     "init",
@@ -43,13 +44,19 @@ func New(c *cli.Config) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&cfg.from, "from", "", "Path to an existing github URL to initialize from")
+	cmd.Flags().StringVar(&cfg.sdkVersion, "sdk-version", "", "Version of the Airplane SDK to install, e.g. 0.2.40 or latest.")
 	cfg.cmd = cmd
 
 	return cmd
 }
 
-func GetConfig(c *cli.Config) config {
-	return config{root: c}
+type ConfigOpts struct {
+	Root       *cli.Config
+	SDKVersion string
+}
+
+func GetConfig(opts ConfigOpts) config {
+	return config{root: opts.Root, sdkVersion: opts.SDKVersion}
 }
 
 func Run(ctx context.Context, cfg config) error {
@@ -72,10 +79,11 @@ func Run(ctx context.Context, cfg config) error {
 			return err
 		}
 		if _, err := initcmd.InitView(ctx, initcmd.InitViewRequest{
-			Prompter: cfg.root.Prompter,
-			Logger:   l,
-			DryRun:   cfg.dryRun,
-			Name:     cfg.name,
+			Prompter:   cfg.root.Prompter,
+			Logger:     l,
+			DryRun:     cfg.dryRun,
+			Name:       cfg.name,
+			SDKVersion: cfg.sdkVersion,
 		}); err != nil {
 			return err
 		}
@@ -4,7 +4,11 @@ import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
 	"github.com/airplanedev/cli/cmd/airplane/root/deploy"
+	"github.com/airplanedev/cli/cmd/airplane/views/codegen"
 	"github.com/airplanedev/cli/cmd/airplane/views/dev"
+	"github.com/airplanedev/cli/cmd/airplane/views/diff"
+	"github.com/airplanedev/cli/cmd/airplane/views/export"
+	"github.com/airplanedev/cli/cmd/airplane/views/importcmd"
 	"github.com/airplanedev/cli/cmd/airplane/views/initcmd"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/utils"
@@ -22,6 +26,10 @@ func New(c *cli.Config) *cobra.Command {
 			airplane views init
 			airplane views dev
 			airplane views deploy
+			airplane views diff my_view --against dep_123
+			airplane views codegen
+			airplane views export --all --out ./views-export
+			airplane views import ./views-export
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -29,8 +37,12 @@ func New(c *cli.Config) *cobra.Command {
 		Hidden: true,
 	}
 
+	cmd.AddCommand(codegen.New(c))
 	cmd.AddCommand(deploy.New(c))
 	cmd.AddCommand(dev.New(c))
+	cmd.AddCommand(diff.New(c))
+	cmd.AddCommand(export.New(c))
+	cmd.AddCommand(importcmd.New(c))
 	cmd.AddCommand(initcmd.New(c))
 
 	return cmd
@@ -30,7 +30,7 @@ func Init(cfg *cli.Config) error {
 	}
 	if c.EnableTelemetry == nil {
 		// User has not specified one way or the other, ask them to opt-in.
-		if err := telemetryOptIn(c, cfg.Prompter); err != nil {
+		if err := telemetryOptIn(cfg.Prompter); err != nil {
 			return err
 		}
 		// Now try again.
@@ -54,9 +54,10 @@ func Init(cfg *cli.Config) error {
 		return err
 	}
 	if err := sentry.Init(sentry.ClientOptions{
-		Dsn:     sentryDSN,
-		Debug:   cfg.DebugMode,
-		Release: version.Get(),
+		Dsn:        sentryDSN,
+		Debug:      cfg.DebugMode,
+		Release:    version.Get(),
+		BeforeSend: redactEvent,
 	}); err != nil {
 		return err
 	}
@@ -69,7 +70,7 @@ func Init(cfg *cli.Config) error {
 	return nil
 }
 
-func telemetryOptIn(c conf.UserConfig, p prompts.Prompter) error {
+func telemetryOptIn(p prompts.Prompter) error {
 	var allow bool
 	logger.Log("Is it OK for Airplane to collect usage analytics and error reports? This data will solely be used to improve the service.")
 	logger.Log("")
@@ -77,11 +78,10 @@ func telemetryOptIn(c conf.UserConfig, p prompts.Prompter) error {
 	if err != nil {
 		return err
 	}
-	c.EnableTelemetry = &allow
-	if err := conf.WriteDefaultUserConfig(c); err != nil {
-		return err
-	}
-	return nil
+	return conf.UpdateDefaultUserConfig(func(c *conf.UserConfig) error {
+		c.EnableTelemetry = &allow
+		return nil
+	})
 }
 
 func Close() {
@@ -118,6 +118,9 @@ func Track(c api.APIClient, event string, properties map[string]interface{}, opt
 		Set("cli_version", version.Get()).
 		Set("os", runtime.GOOS)
 	for k, v := range properties {
+		if s, ok := v.(string); ok {
+			v = logger.Redact(s)
+		}
 		props = props.Set(k, v)
 	}
 	enqueue(analytics.Track{
@@ -159,6 +162,18 @@ func ReportMessage(msg string) {
 	}
 }
 
+// redactEvent removes secrets from the messages of a Sentry event before it's sent.
+func redactEvent(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	event.Message = logger.Redact(event.Message)
+	for i := range event.Exception {
+		event.Exception[i].Value = logger.Redact(event.Exception[i].Value)
+	}
+	for _, b := range event.Breadcrumbs {
+		b.Message = logger.Redact(b.Message)
+	}
+	return event
+}
+
 func ignoreError(err error) bool {
 	// For now, all this does is handle survey's interrupt error.
 	return errors.Is(err, terminal.InterruptErr)
@@ -57,17 +57,53 @@ type ClientOpts struct {
 	// Alternative to token-based authn.
 	APIKey string
 	TeamID string
+
+	// MaxRetries is the maximum number of times a failed request is retried. A negative value
+	// disables retries. Defaults to libhttp.DefaultMaxRetries.
+	MaxRetries int
+
+	// Timeout is the maximum amount of time spent on a single attempt of a request. Defaults to
+	// DefaultTimeout.
+	Timeout time.Duration
+}
+
+// DefaultTimeout is the default timeout of a single attempt of a request.
+//
+// TODO: revert to the default 10s after optimizing the long-tail of slow API endpoints.
+const DefaultTimeout = 30 * time.Second
+
+// idempotentPaths are the POST endpoints that can be retried after the API has processed them,
+// since they only read state or set it to the value in the request. Other POSTs, e.g. ones that
+// create entities, execute tasks or exchange single-use tokens, are only retried if the API
+// didn't process them, see libhttp.ReqOpts.NonIdempotent.
+var idempotentPaths = map[string]bool{
+	"/agents/drain":                    true,
+	"/autopilot/complete":              true,
+	"/configs/get":                     true,
+	"/configs/set":                     true,
+	"/deployments/cancel":              true,
+	"/oidc/generateStudioIDToken":      true,
+	"/prompts/cancel":                  true,
+	"/registry/getToken":               true,
+	"/runs/cancel":                     true,
+	"/serviceAccounts/validateRunAs":   true,
+	"/studio/tunnelToken/setDevSecret": true,
+	"/tasks/activateRevision":          true,
+	"/tasks/archive":                   true,
+	"/tasks/unarchive":                 true,
+	"/tasks/update":                    true,
+	"/templates/evaluate":              true,
+	"/triggers/disable":                true,
+	"/triggers/enable":                 true,
+	"/views/permissions/update":        true,
+	"/views/update":                    true,
 }
 
 func NewClient(opts ClientOpts) *Client {
-	headers := map[string]string{
-		"X-Airplane-Client-Kind":    "cli",
-		"X-Airplane-Client-Version": version.Get(),
-	}
-	if opts.Source != "" {
-		headers["X-Airplane-Client-Source"] = opts.Source
+	logger.RegisterSecrets(opts.Token, opts.APIKey)
+	if opts.TunnelToken != nil {
+		logger.RegisterSecrets(*opts.TunnelToken)
 	}
-
 	return &Client{
 		host:        opts.Host,
 		token:       opts.Token,
@@ -75,41 +111,68 @@ func NewClient(opts ClientOpts) *Client {
 		source:      opts.Source,
 		apiKey:      opts.APIKey,
 		teamID:      opts.TeamID,
-		http: libhttp.NewClient(libhttp.ClientOpts{
-			Headers:   headers,
-			UserAgent: "airplane/cli/" + version.Get(),
-			// Temporarily bump the default timeout to 30s.
-			// TODO: revert to the default 10s after optimizing the long-tail of slow API endpoints.
-			Timeout: 30 * time.Second,
-			RequestLogHook: func(req *http.Request, attempt int) {
-				msg := "requesting..."
-				if attempt > 1 {
-					msg = fmt.Sprintf("retrying... (attempt #%d)", attempt)
-				}
-				logger.Debug("%s %s: %s", req.Method, req.URL.Path, msg)
-			},
-			ResponseLogHook: func(resp *http.Response) {
-				if !logger.EnableDebug {
-					return
-				}
+		http:        newHTTPClient(opts),
+	}
+}
 
-				// Print out the response body for debugging. Reset resp.Body since we read it to completion.
-				body, err := io.ReadAll(resp.Body)
-				resp.Body.Close()
-				resp.Body = io.NopCloser(bytes.NewReader(body))
-
-				if err != nil {
-					logger.Debug("%s %s (%d): failed to read response body: %v", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, err)
-				} else {
-					b := string(body)
-					if len(b) == 0 {
-						b = "(no response)"
-					}
-					logger.Debug("%s %s (%d): %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, b)
-				}
-			},
-		}),
+// SetRetryPolicy configures how failed requests are retried: each request is retried up to
+// maxRetries times, and each attempt times out after timeout. A negative maxRetries disables
+// retries, and a zero timeout keeps the default.
+func (c *Client) SetRetryPolicy(maxRetries int, timeout time.Duration) {
+	c.http = newHTTPClient(ClientOpts{
+		Source:     c.source,
+		MaxRetries: maxRetries,
+		Timeout:    timeout,
+	})
+}
+
+func newHTTPClient(opts ClientOpts) libhttp.Client {
+	headers := map[string]string{
+		"X-Airplane-Client-Kind":    "cli",
+		"X-Airplane-Client-Version": version.Get(),
+	}
+	if opts.Source != "" {
+		headers["X-Airplane-Client-Source"] = opts.Source
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
 	}
+
+	return libhttp.NewClient(libhttp.ClientOpts{
+		Headers:    headers,
+		UserAgent:  "airplane/cli/" + version.Get(),
+		Timeout:    timeout,
+		MaxRetries: opts.MaxRetries,
+		RequestLogHook: func(req *http.Request, attempt int) {
+			msg := "requesting..."
+			if attempt > 1 {
+				msg = fmt.Sprintf("retrying... (attempt #%d)", attempt)
+			}
+			logger.DebugFor(logger.ModuleAPI, "%s %s: %s", req.Method, req.URL.Path, msg)
+		},
+		ResponseLogHook: func(resp *http.Response) {
+			if !logger.Enabled(logger.ModuleAPI, logger.LevelTrace) {
+				return
+			}
+
+			// Print out the response body for debugging. Reset resp.Body since we read it to completion.
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+
+			if err != nil {
+				logger.TraceFor(logger.ModuleAPI, "%s %s (%d): failed to read response body: %v", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, err)
+			} else {
+				b := string(body)
+				if len(b) == 0 {
+					b = "(no response)"
+				}
+				logger.TraceFor(logger.ModuleAPI, "%s %s (%d): %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, b)
+			}
+		},
+	})
 }
 
 type APIClient interface {
@@ -126,22 +189,38 @@ type APIClient interface {
 	ListTasks(ctx context.Context, envSlug string) (res ListTasksResponse, err error)
 	CreateTask(ctx context.Context, req CreateTaskRequest) (res CreateTaskResponse, err error)
 	UpdateTask(ctx context.Context, req libapi.UpdateTaskRequest) (res UpdateTaskResponse, err error)
+	// EnableTrigger enables a disabled trigger, such as a paused schedule.
+	EnableTrigger(ctx context.Context, req EnableTriggerRequest) (err error)
+	// DisableTrigger disables a trigger, such as pausing a schedule.
+	DisableTrigger(ctx context.Context, req DisableTriggerRequest) (err error)
+	// ArchiveTask archives a task, which stops its schedules and hides it.
+	ArchiveTask(ctx context.Context, req ArchiveTaskRequest) (err error)
+	// UnarchiveTask restores an archived task.
+	UnarchiveTask(ctx context.Context, req UnarchiveTaskRequest) (err error)
 	RunTask(ctx context.Context, req RunTaskRequest) (RunTaskResponse, error)
 	TaskURL(slug string, envSlug string) string
 	ListRuns(ctx context.Context, req ListRunsRequest) (ListRunsResponse, error)
+	CancelRun(ctx context.Context, req CancelRunRequest) error
 
 	GetRun(ctx context.Context, id string) (res GetRunResponse, err error)
+	GetLogs(ctx context.Context, runID, prevToken string) (res GetLogsResponse, err error)
+	DownloadLogs(ctx context.Context, req DownloadLogsRequest) (res DownloadLogsResponse, err error)
 	GetOutputs(ctx context.Context, runID string) (res GetOutputsResponse, err error)
 	GetRunbook(ctx context.Context, runbookSlug string, envSlug string) (res GetRunbookResponse, err error)
+	CreatePrompt(ctx context.Context, req libapi.Prompt) (res CreatePromptResponse, err error)
+	GetPrompt(ctx context.Context, id string) (res GetPromptResponse, err error)
+	CancelPrompt(ctx context.Context, req CancelPromptRequest) error
 	ListSessionBlocks(ctx context.Context, sessionID string) (res ListSessionBlocksResponse, err error)
 
 	ListResources(ctx context.Context, envSlug string) (res libapi.ListResourcesResponse, err error)
 	ListResourceMetadata(ctx context.Context) (res libapi.ListResourceMetadataResponse, err error)
 	GetResource(ctx context.Context, req GetResourceRequest) (res libapi.GetResourceResponse, err error)
+	CreateResource(ctx context.Context, req CreateResourceRequest) (res CreateResourceResponse, err error)
 
 	SetConfig(ctx context.Context, req SetConfigRequest) (err error)
 	GetConfig(ctx context.Context, req GetConfigRequest) (res GetConfigResponse, err error)
 	ListConfigs(ctx context.Context, req ListConfigsRequest) (res ListConfigsResponse, err error)
+	DeleteConfig(ctx context.Context, req DeleteConfigRequest) (err error)
 
 	GetRegistryToken(ctx context.Context) (res RegistryTokenResponse, err error)
 
@@ -153,9 +232,18 @@ type APIClient interface {
 	GetDeployment(ctx context.Context, id string) (res Deployment, err error)
 	CreateDeployment(ctx context.Context, req CreateDeploymentRequest) (CreateDeploymentResponse, error)
 	CancelDeployment(ctx context.Context, req CancelDeploymentRequest) error
+	// ListDeployments lists the deployments to an environment, most recent first.
+	ListDeployments(ctx context.Context, req ListDeploymentsRequest) (ListDeploymentsResponse, error)
+	// ListDeploymentRevisions lists the task revisions that a deployment activated.
+	ListDeploymentRevisions(ctx context.Context, deploymentID string) (ListDeploymentRevisionsResponse, error)
+	// ActivateTaskRevision makes a previous revision of a task its active revision.
+	ActivateTaskRevision(ctx context.Context, req ActivateTaskRevisionRequest) error
 	DeploymentURL(deploymentID string, envSlug string) string
 
 	CreateBuildUpload(ctx context.Context, req libapi.CreateBuildUploadRequest) (res libapi.CreateBuildUploadResponse, err error)
+	GetBuild(ctx context.Context, id string) (res Build, err error)
+	GetBuildContextManifest(ctx context.Context, id string) (res GetBuildContextManifestResponse, err error)
+	GetBuildLogs(ctx context.Context, id string, step int, prevToken string) (res GetBuildLogsResponse, err error)
 	GenerateSignedURLs(ctx context.Context, envSlug string) (res GenerateSignedURLsResponse, err error)
 	CreateUpload(ctx context.Context, req libapi.CreateUploadRequest) (res libapi.CreateUploadResponse, err error)
 	GetUpload(ctx context.Context, uploadID string) (res libapi.GetUploadResponse, err error)
@@ -163,15 +251,32 @@ type APIClient interface {
 	GetView(ctx context.Context, req libapi.GetViewRequest) (libapi.View, error)
 	GetViewMetadata(ctx context.Context, slug string) (libapi.ViewMetadata, error)
 	CreateView(ctx context.Context, req libapi.CreateViewRequest) (libapi.View, error)
+	ListViews(ctx context.Context) (ListViewsResponse, error)
+	UpdateView(ctx context.Context, req UpdateViewRequest) (libapi.View, error)
+	GetViewPermissions(ctx context.Context, slug string) (ViewPermissions, error)
+	UpdateViewPermissions(ctx context.Context, req UpdateViewPermissionsRequest) error
+	GetViewAssetManifest(ctx context.Context, req GetViewAssetManifestRequest) (GetViewAssetManifestResponse, error)
 	CreateDemoDB(ctx context.Context, name string) (string, error)
 	ResetDemoDB(ctx context.Context) (string, error)
 
 	GetEnv(ctx context.Context, envSlug string) (libapi.Env, error)
 	ListEnvs(ctx context.Context) (ListEnvsResponse, error)
+	CreateEnv(ctx context.Context, req CreateEnvRequest) (libapi.Env, error)
+	DeleteEnv(ctx context.Context, req DeleteEnvRequest) error
 
 	EvaluateTemplate(ctx context.Context, req libapi.EvaluateTemplateRequest) (res libapi.EvaluateTemplateResponse, err error)
 
 	GetPermissions(ctx context.Context, taskSlug string, actions []string) (GetPermissionsResponse, error)
+	ValidateRunAs(ctx context.Context, req ValidateRunAsRequest) (ValidateRunAsResponse, error)
+
+	ListAgentPools(ctx context.Context, req ListAgentPoolsRequest) (ListAgentPoolsResponse, error)
+	// ListAgents lists the self-hosted agents in an environment.
+	ListAgents(ctx context.Context, req ListAgentsRequest) (ListAgentsResponse, error)
+	// GetAgent gets a self-hosted agent by ID.
+	GetAgent(ctx context.Context, req GetAgentRequest) (Agent, error)
+	// DrainAgent stops assigning runs to an agent, so that it can be shut down once its active runs
+	// finish.
+	DrainAgent(ctx context.Context, req DrainAgentRequest) error
 
 	GetUniqueSlug(ctx context.Context, name, preferredSlug string) (res GetUniqueSlugResponse, err error)
 
@@ -184,11 +289,22 @@ type APIClient interface {
 	GetWebHost(ctx context.Context) (string, error)
 
 	GetUser(ctx context.Context, userID string) (GetUserResponse, error)
+	ListUsers(ctx context.Context) (ListUsersResponse, error)
+	ListGroups(ctx context.Context) (ListGroupsResponse, error)
 
 	AutopilotComplete(ctx context.Context, req AutopilotCompleteRequest) (AutopilotCompleteResponse, error)
 
 	GenerateStudioIDToken(ctx context.Context, req GenerateStudioIDTokenRequest) (GenerateStudioIDTokenResponse, error)
 
+	// ExchangeSSOCode exchanges the authorization code of an SSO login for a session.
+	ExchangeSSOCode(ctx context.Context, req ExchangeSSOCodeRequest) (SSOSessionResponse, error)
+	// StartSSODeviceAuth starts an SSO login that is completed on another device.
+	StartSSODeviceAuth(ctx context.Context, req StartSSODeviceAuthRequest) (StartSSODeviceAuthResponse, error)
+	// PollSSODeviceAuth returns the session of a device login, once the user has completed it.
+	PollSSODeviceAuth(ctx context.Context, req PollSSODeviceAuthRequest) (PollSSODeviceAuthResponse, error)
+	// RefreshSSOSession exchanges the refresh token of an SSO session for a new session.
+	RefreshSSOSession(ctx context.Context, req RefreshSSOSessionRequest) (SSOSessionResponse, error)
+
 	// All methods below this point represent CLI-specific API operations, and not requests to api.airplane.dev.
 	AuthInfo(ctx context.Context) (res AuthInfoResponse, err error)
 	Token() string
@@ -198,6 +314,7 @@ type APIClient interface {
 	SetHost(host string)
 	TokenURL() string
 	LoginURL(uri string) string
+	SSOLoginURL(orgSlug, uri, state string) string
 	LoginSuccessURL() string
 	APIKey() string
 	SetAPIKey(apiKey string)
@@ -233,6 +350,7 @@ func (c *Client) Token() string {
 
 func (c *Client) SetToken(token string) {
 	c.token = token
+	logger.RegisterSecrets(token)
 }
 
 func (c *Client) TunnelToken() *string {
@@ -245,6 +363,7 @@ func (c *Client) APIKey() string {
 
 func (c *Client) SetAPIKey(apiKey string) {
 	c.apiKey = apiKey
+	logger.RegisterSecrets(apiKey)
 }
 
 func (c *Client) TeamID() string {
@@ -277,6 +396,18 @@ func (c *Client) LoginURL(uri string) string {
 	return u.String()
 }
 
+// SSOLoginURL returns a URL that starts an SSO login with the identity provider of an
+// organization, and redirects to uri with an authorization code once it completes.
+func (c *Client) SSOLoginURL(orgSlug, uri, state string) string {
+	u := c.AppURL()
+	u.Path = "/sso/" + url.PathEscape(orgSlug) + "/cli"
+	u.RawQuery = url.Values{
+		"redirect": []string{uri},
+		"state":    []string{state},
+	}.Encode()
+	return u.String()
+}
+
 // LoginSuccessURL returns a URL showing a message that logging in was successful.
 func (c *Client) LoginSuccessURL() string {
 	u := c.AppURL()
@@ -340,6 +471,36 @@ func (c *Client) UpdateTask(ctx context.Context, req libapi.UpdateTaskRequest) (
 	return
 }
 
+func (c *Client) EnableTrigger(ctx context.Context, req EnableTriggerRequest) (err error) {
+	err = c.post(ctx, "/triggers/enable", req, nil)
+	return
+}
+
+func (c *Client) DisableTrigger(ctx context.Context, req DisableTriggerRequest) (err error) {
+	err = c.post(ctx, "/triggers/disable", req, nil)
+	return
+}
+
+func (c *Client) ArchiveTask(ctx context.Context, req ArchiveTaskRequest) (err error) {
+	err = c.post(ctx, "/tasks/archive", req, nil)
+	return unsupportedError(err, "archiving tasks", "use the task's page in the app instead")
+}
+
+func (c *Client) UnarchiveTask(ctx context.Context, req UnarchiveTaskRequest) (err error) {
+	err = c.post(ctx, "/tasks/unarchive", req, nil)
+	return unsupportedError(err, "unarchiving tasks", "use the task's page in the app instead")
+}
+
+// unsupportedError explains a 404 from an endpoint that is only called for resources that exist,
+// such as the archive endpoints: the API doesn't support it.
+func unsupportedError(err error, action, instead string) error {
+	var errsc libhttp.ErrStatusCode
+	if errors.As(err, &errsc) && errsc.StatusCode == 404 {
+		return errors.Errorf("the Airplane API doesn't support %s from the CLI, %s", action, instead)
+	}
+	return err
+}
+
 // ListTasks lists all tasks.
 func (c *Client) ListTasks(ctx context.Context, envSlug string) (res ListTasksResponse, err error) {
 	err = c.get(ctx, encodeQueryString("/tasks/list", url.Values{
@@ -392,6 +553,9 @@ func (c *Client) ListRuns(ctx context.Context, req ListRunsRequest) (ListRunsRes
 	if !req.Until.IsZero() {
 		q.Set("until", req.Until.Format(time.RFC3339))
 	}
+	for _, s := range req.Statuses {
+		q.Add("statuses", string(s))
+	}
 
 	var resp ListRunsResponse
 	var page ListRunsResponse
@@ -422,6 +586,11 @@ func (c *Client) ListRuns(ctx context.Context, req ListRunsRequest) (ListRunsRes
 	return resp, nil
 }
 
+// CancelRun cancels a run that hasn't finished.
+func (c *Client) CancelRun(ctx context.Context, req CancelRunRequest) error {
+	return c.post(ctx, "/runs/cancel", req, nil)
+}
+
 // RunTask runs a task.
 func (c *Client) RunTask(ctx context.Context, req RunTaskRequest) (RunTaskResponse, error) {
 	var res RunTaskResponse
@@ -473,6 +642,78 @@ func (c *Client) GetLogs(ctx context.Context, runID, prevToken string) (res GetL
 	return
 }
 
+// DownloadLogs streams a byte range of a run's logs. Unlike GetLogs, the logs are returned as text
+// and can be fetched in ranges, so that large logs can be downloaded in parts and resumed.
+func (c *Client) DownloadLogs(ctx context.Context, req DownloadLogsRequest) (res DownloadLogsResponse, err error) {
+	headers, err := c.headers()
+	if err != nil {
+		return DownloadLogsResponse{}, err
+	}
+	if req.Length > 0 {
+		headers["Range"] = fmt.Sprintf("bytes=%d-%d", req.Offset, req.Offset+req.Length-1)
+	} else {
+		headers["Range"] = fmt.Sprintf("bytes=%d-", req.Offset)
+	}
+	// Ranges of a compressed response are ranges of the compressed bytes, which can't be used to
+	// resume a download of the logs, so ask for the logs uncompressed.
+	headers["Accept-Encoding"] = "identity"
+
+	q := url.Values{"runID": []string{req.RunID}}
+	pathname := "/v0/runs/downloadLogs?" + q.Encode()
+	resp, err := c.http.GetStream(ctx, c.scheme()+c.Host()+pathname, libhttp.ReqOpts{
+		Headers: headers,
+	})
+	if err != nil {
+		logger.DebugFor(logger.ModuleAPI, "GET %s: request failed: %v", pathname, err)
+		return DownloadLogsResponse{}, err
+	}
+
+	if enc := resp.Header.Get("Content-Encoding"); enc != "" && !strings.EqualFold(enc, "identity") {
+		resp.Body.Close()
+		return DownloadLogsResponse{}, errors.Errorf("unexpected %s-encoded logs", enc)
+	}
+
+	res = DownloadLogsResponse{Body: resp.Body, Size: -1}
+	if resp.StatusCode == http.StatusPartialContent {
+		res.Offset, res.Size, err = parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			resp.Body.Close()
+			return DownloadLogsResponse{}, err
+		}
+	} else if resp.ContentLength >= 0 {
+		res.Size = resp.ContentLength
+	}
+	return res, nil
+}
+
+// parseContentRange parses the start offset and total size out of a Content-Range header, e.g.
+// "bytes 100-199/1000". The size is -1 if unknown.
+func parseContentRange(header string) (start int64, size int64, err error) {
+	invalid := errors.Errorf("invalid Content-Range %q", header)
+	rng, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, 0, invalid
+	}
+	rng, total, ok := strings.Cut(rng, "/")
+	if !ok {
+		return 0, 0, invalid
+	}
+	startStr, _, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, invalid
+	}
+	if start, err = strconv.ParseInt(startStr, 10, 64); err != nil {
+		return 0, 0, invalid
+	}
+	if total == "*" {
+		return start, -1, nil
+	}
+	if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+		return 0, 0, invalid
+	}
+	return start, size, nil
+}
+
 // GetOutputs returns the outputs by runID.
 func (c *Client) GetOutputs(ctx context.Context, runID string) (res GetOutputsResponse, err error) {
 	q := url.Values{"runID": []string{runID}}
@@ -480,6 +721,24 @@ func (c *Client) GetOutputs(ctx context.Context, runID string) (res GetOutputsRe
 	return
 }
 
+// CreatePrompt creates a prompt, f.e. to request an approval.
+func (c *Client) CreatePrompt(ctx context.Context, req libapi.Prompt) (res CreatePromptResponse, err error) {
+	err = c.post(ctx, "/prompts/create", req, &res)
+	return
+}
+
+// GetPrompt returns a prompt by id.
+func (c *Client) GetPrompt(ctx context.Context, id string) (res GetPromptResponse, err error) {
+	q := url.Values{"id": []string{id}}
+	err = c.get(ctx, "/prompts/get?"+q.Encode(), &res)
+	return
+}
+
+// CancelPrompt cancels a prompt that hasn't been responded to.
+func (c *Client) CancelPrompt(ctx context.Context, req CancelPromptRequest) error {
+	return c.post(ctx, "/prompts/cancel", req, nil)
+}
+
 // GetRunbook returns the details of a runbook by slug.
 func (c *Client) GetRunbook(ctx context.Context, runbookSlug string, envSlug string) (res GetRunbookResponse, err error) {
 	q := url.Values{"runbookSlug": []string{runbookSlug}, "envSlug": []string{envSlug}}
@@ -612,6 +871,61 @@ func (c *Client) CreateView(ctx context.Context, req libapi.CreateViewRequest) (
 	return
 }
 
+// ListViews lists the views of the team.
+func (c *Client) ListViews(ctx context.Context) (res ListViewsResponse, err error) {
+	err = c.get(ctx, "/views/list", &res)
+	return
+}
+
+// UpdateView updates the name, description and environment variables of a view. If the view does
+// not exist, a *ViewMissingError is returned.
+func (c *Client) UpdateView(ctx context.Context, req UpdateViewRequest) (res libapi.View, err error) {
+	err = c.post(ctx, "/views/update", req, &res)
+
+	var errsc libhttp.ErrStatusCode
+	if errors.As(err, &errsc) && errsc.StatusCode == 404 {
+		return res, &libapi.ViewMissingError{
+			AppURL: c.AppURL().String(),
+			Slug:   req.Slug,
+		}
+	}
+
+	return
+}
+
+// GetViewPermissions fetches the permissions of a view.
+func (c *Client) GetViewPermissions(ctx context.Context, slug string) (res ViewPermissions, err error) {
+	err = c.get(ctx, encodeQueryString("/views/permissions/get", url.Values{
+		"slug": []string{slug},
+	}), &res)
+	return
+}
+
+// UpdateViewPermissions replaces the permissions of a view.
+func (c *Client) UpdateViewPermissions(ctx context.Context, req UpdateViewPermissionsRequest) (err error) {
+	err = c.post(ctx, "/views/permissions/update", req, nil)
+	return
+}
+
+// GetViewAssetManifest fetches the manifest of a view's built assets as of a deployment.
+func (c *Client) GetViewAssetManifest(ctx context.Context, req GetViewAssetManifestRequest) (res GetViewAssetManifestResponse, err error) {
+	err = c.get(ctx, encodeQueryString("/views/getAssetManifest", url.Values{
+		"viewSlug":     []string{req.ViewSlug},
+		"deploymentID": []string{req.DeploymentID},
+		"envSlug":      []string{req.EnvSlug},
+	}), &res)
+
+	var errsc libhttp.ErrStatusCode
+	if errors.As(err, &errsc) && errsc.StatusCode == 404 {
+		return res, &libapi.ViewMissingError{
+			AppURL: c.AppURL().String(),
+			Slug:   req.ViewSlug,
+		}
+	}
+
+	return
+}
+
 func (c *Client) CreateDemoDB(ctx context.Context, name string) (string, error) {
 	reply := struct {
 		ResourceID string `json:"resourceID"`
@@ -625,6 +939,14 @@ func (c *Client) CreateDemoDB(ctx context.Context, name string) (string, error)
 	return reply.ResourceID, nil
 }
 
+// CreateResource creates a resource in the given environment.
+func (c *Client) CreateResource(ctx context.Context, req CreateResourceRequest) (res CreateResourceResponse, err error) {
+	err = c.post(ctx, encodeQueryString("/resources/create", url.Values{
+		"envSlug": []string{req.EnvSlug},
+	}), req, &res)
+	return
+}
+
 func (c *Client) ResetDemoDB(ctx context.Context) (string, error) {
 	reply := struct {
 		ResourceID string `json:"resourceID"`
@@ -662,6 +984,14 @@ func (c *Client) ListConfigs(ctx context.Context, req ListConfigsRequest) (res L
 	return
 }
 
+// DeleteConfig deletes a config by ID, see Config.ID.
+func (c *Client) DeleteConfig(ctx context.Context, req DeleteConfigRequest) (err error) {
+	err = c.post(ctx, encodeQueryString("/configs/delete", url.Values{
+		"envSlug": []string{req.EnvSlug},
+	}), req, nil)
+	return
+}
+
 // GetDeployment returns a deployment.
 func (c *Client) GetDeployment(ctx context.Context, id string) (res Deployment, err error) {
 	q := url.Values{"id": []string{id}}
@@ -675,6 +1005,33 @@ func (c *Client) CreateBuildUpload(ctx context.Context, req libapi.CreateBuildUp
 	return
 }
 
+// GetBuild returns a build.
+func (c *Client) GetBuild(ctx context.Context, id string) (res Build, err error) {
+	q := url.Values{"id": []string{id}}
+	err = c.get(ctx, "/builds/get?"+q.Encode(), &res)
+	return
+}
+
+// GetBuildContextManifest lists the files in the context that a build was run with.
+func (c *Client) GetBuildContextManifest(ctx context.Context, id string) (res GetBuildContextManifestResponse, err error) {
+	q := url.Values{"id": []string{id}}
+	err = c.get(ctx, "/builds/getContextManifest?"+q.Encode(), &res)
+	return
+}
+
+// GetBuildLogs returns a page of the logs of a single step of a build.
+func (c *Client) GetBuildLogs(ctx context.Context, id string, step int, prevToken string) (res GetBuildLogsResponse, err error) {
+	q := url.Values{
+		"id":   []string{id},
+		"step": []string{strconv.Itoa(step)},
+	}
+	if prevToken != "" {
+		q.Set("prevToken", prevToken)
+	}
+	err = c.get(ctx, "/builds/getLogs?"+q.Encode(), &res)
+	return
+}
+
 // CreateAPIKey creates a new API key and returns data about it.
 func (c *Client) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (res CreateAPIKeyResponse, err error) {
 	err = c.post(ctx, "/apiKeys/create", req, &res)
@@ -704,6 +1061,35 @@ func (c *Client) CancelDeployment(ctx context.Context, req CancelDeploymentReque
 	return c.post(ctx, "/deployments/cancel", req, nil)
 }
 
+// ListDeployments lists the deployments to an environment, most recent first.
+func (c *Client) ListDeployments(ctx context.Context, req ListDeploymentsRequest) (res ListDeploymentsResponse, err error) {
+	q := url.Values{
+		"envSlug": []string{req.EnvSlug},
+	}
+	if req.Limit > 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	err = c.get(ctx, encodeQueryString("/deployments/list", q), &res)
+	err = unsupportedError(err, "rolling back deployments", "redeploy the previous version instead")
+	return
+}
+
+// ListDeploymentRevisions lists the task revisions that a deployment activated.
+func (c *Client) ListDeploymentRevisions(ctx context.Context, deploymentID string) (res ListDeploymentRevisionsResponse, err error) {
+	q := url.Values{"id": []string{deploymentID}}
+	err = c.get(ctx, "/deployments/listRevisions?"+q.Encode(), &res)
+	err = unsupportedError(err, "rolling back deployments", "redeploy the previous version instead")
+	return
+}
+
+// ActivateTaskRevision makes a previous revision of a task its active revision.
+func (c *Client) ActivateTaskRevision(ctx context.Context, req ActivateTaskRevisionRequest) (err error) {
+	err = c.post(ctx, encodeQueryString("/tasks/activateRevision", url.Values{
+		"envSlug": []string{req.EnvSlug},
+	}), req, nil)
+	return unsupportedError(err, "rolling back deployments", "redeploy the previous version instead")
+}
+
 func (c *Client) GetDeploymentLogs(ctx context.Context, deploymentID string, prevToken string) (res GetDeploymentLogsResponse, err error) {
 	q := url.Values{
 		"id": []string{deploymentID},
@@ -759,6 +1145,16 @@ func (c *Client) ListEnvs(ctx context.Context) (res ListEnvsResponse, err error)
 	return
 }
 
+func (c *Client) CreateEnv(ctx context.Context, req CreateEnvRequest) (res libapi.Env, err error) {
+	err = c.post(ctx, "/envs/create", req, &res)
+	return
+}
+
+func (c *Client) DeleteEnv(ctx context.Context, req DeleteEnvRequest) (err error) {
+	err = c.post(ctx, "/envs/delete", req, nil)
+	return
+}
+
 func (c *Client) EvaluateTemplate(ctx context.Context, req libapi.EvaluateTemplateRequest) (res libapi.EvaluateTemplateResponse, err error) {
 	err = c.post(ctx, "/templates/evaluate", req, &res)
 	return
@@ -772,6 +1168,54 @@ func (c *Client) GetPermissions(ctx context.Context, taskSlug string, actions []
 	return
 }
 
+// ValidateRunAs checks whether the current user is permitted to attach a service account to a task
+// as its execution identity.
+func (c *Client) ValidateRunAs(ctx context.Context, req ValidateRunAsRequest) (res ValidateRunAsResponse, err error) {
+	err = c.post(ctx, "/serviceAccounts/validateRunAs", req, &res)
+	return
+}
+
+// ListAgentPools lists the agent pools in an environment.
+func (c *Client) ListAgentPools(ctx context.Context, req ListAgentPoolsRequest) (res ListAgentPoolsResponse, err error) {
+	err = c.get(ctx, encodeQueryString("/agents/pools/list", url.Values{
+		"envSlug":         []string{req.EnvSlug},
+		"includeCapacity": []string{strconv.FormatBool(req.IncludeCapacity)},
+	}), &res)
+	return
+}
+
+// ListAgents lists the self-hosted agents in an environment.
+func (c *Client) ListAgents(ctx context.Context, req ListAgentsRequest) (res ListAgentsResponse, err error) {
+	err = c.get(ctx, encodeQueryString("/agents/list", url.Values{
+		"envSlug": []string{req.EnvSlug},
+		"pool":    []string{req.Pool},
+	}), &res)
+	err = unsupportedError(err, "listing agents", "manage agents in the app instead")
+	return
+}
+
+// GetAgent gets a self-hosted agent by ID. It lists the agents of the environment, so that a
+// missing agent isn't mistaken for an unsupported endpoint.
+func (c *Client) GetAgent(ctx context.Context, req GetAgentRequest) (res Agent, err error) {
+	agents, err := c.ListAgents(ctx, ListAgentsRequest{EnvSlug: req.EnvSlug})
+	if err != nil {
+		return Agent{}, err
+	}
+	for _, a := range agents.Agents {
+		if a.ID == req.AgentID {
+			return a, nil
+		}
+	}
+	return Agent{}, errors.Errorf("no agent %s", req.AgentID)
+}
+
+// DrainAgent stops assigning runs to an agent. Callers get the agent first, so a 404 means that
+// the API doesn't support draining.
+func (c *Client) DrainAgent(ctx context.Context, req DrainAgentRequest) (err error) {
+	err = c.post(ctx, "/agents/drain", req, nil)
+	return unsupportedError(err, "draining agents", "manage agents in the app instead")
+}
+
 func (c *Client) CreateUpload(ctx context.Context, req libapi.CreateUploadRequest) (res libapi.CreateUploadResponse, err error) {
 	err = c.post(ctx, "/uploads/create", req, &res)
 	return
@@ -803,6 +1247,18 @@ func (c *Client) GetUser(ctx context.Context, userID string) (res GetUserRespons
 	return
 }
 
+// ListUsers lists the members of the team.
+func (c *Client) ListUsers(ctx context.Context) (res ListUsersResponse, err error) {
+	err = c.get(ctx, "/users/list", &res)
+	return
+}
+
+// ListGroups lists the groups of the team.
+func (c *Client) ListGroups(ctx context.Context) (res ListGroupsResponse, err error) {
+	err = c.get(ctx, "/groups/list", &res)
+	return
+}
+
 func (c *Client) GetTunnelToken(ctx context.Context) (res GetTunnelTokenResponse, err error) {
 	err = c.get(ctx, "/studio/tunnelToken/get", &res)
 	return
@@ -829,6 +1285,26 @@ func (c *Client) GenerateStudioIDToken(ctx context.Context, req GenerateStudioID
 	return
 }
 
+func (c *Client) ExchangeSSOCode(ctx context.Context, req ExchangeSSOCodeRequest) (res SSOSessionResponse, err error) {
+	err = c.post(ctx, "/auth/sso/exchange", req, &res)
+	return
+}
+
+func (c *Client) StartSSODeviceAuth(ctx context.Context, req StartSSODeviceAuthRequest) (res StartSSODeviceAuthResponse, err error) {
+	err = c.post(ctx, "/auth/sso/device/start", req, &res)
+	return
+}
+
+func (c *Client) PollSSODeviceAuth(ctx context.Context, req PollSSODeviceAuthRequest) (res PollSSODeviceAuthResponse, err error) {
+	err = c.post(ctx, "/auth/sso/device/poll", req, &res)
+	return
+}
+
+func (c *Client) RefreshSSOSession(ctx context.Context, req RefreshSSOSessionRequest) (res SSOSessionResponse, err error) {
+	err = c.post(ctx, "/auth/sso/refresh", req, &res)
+	return
+}
+
 func (c *Client) headers() (map[string]string, error) {
 	headers := map[string]string{}
 	if c.Token() != "" {
@@ -862,7 +1338,7 @@ func (c *Client) get(ctx context.Context, path string, reply interface{}) error
 		Headers: headers,
 	})
 	if err != nil {
-		logger.Debug("GET %s: request failed: %v", pathname, err)
+		logger.DebugFor(logger.ModuleAPI, "GET %s: request failed: %v", pathname, err)
 		return err
 	}
 
@@ -878,10 +1354,11 @@ func (c *Client) post(ctx context.Context, path string, payload, reply interface
 	pathname := "/v0" + path
 	url := c.scheme() + c.Host() + pathname
 	err = c.http.PostJSON(ctx, url, payload, reply, libhttp.ReqOpts{
-		Headers: headers,
+		Headers:       headers,
+		NonIdempotent: !idempotentPaths[strings.SplitN(path, "?", 2)[0]],
 	})
 	if err != nil {
-		logger.Debug("POST %s: request failed: %v", pathname, err)
+		logger.DebugFor(logger.ModuleAPI, "POST %s: request failed: %v", pathname, err)
 		return err
 	}
 
@@ -3,28 +3,52 @@ package api
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	libapi "github.com/airplanedev/cli/pkg/api"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
 )
 
 type MockClient struct {
+	Builds                map[string]Build
+	BuildContextFiles     map[string][]BuildContextFile
+	BuildLogs             map[string][]LogItem
 	Configs               []Config
 	Deploys               []CreateDeploymentRequest
 	Envs                  map[string]libapi.Env
+	Groups                []Group
 	GetDeploymentResponse *Deployment
+	Deployments           []Deployment
+	DeploymentRevisions   map[string][]DeploymentRevision
+	ActivatedRevisions    []ActivateTaskRevisionRequest
 	Resources             []libapi.Resource
+	ServiceAccounts       []string
+	AgentPools            []AgentPool
+	Agents                []Agent
+	Prompts               map[string]libapi.Prompt
 	Runbooks              map[string]Runbook
+	Runs                  []Run
+	RunLogs               map[string][]LogItem
+	RunLogFiles           map[string]string
+	RunOutputs            map[string]Outputs
 	SessionBlocks         map[string][]SessionBlock
 	Tasks                 map[string]libapi.Task
 	Users                 map[string]User
 	Views                 map[string]libapi.View
+	ViewAssetManifests    map[string][]ViewAsset
+	ViewPermissions       map[string]ViewPermissions
 	Uploads               map[string]libapi.Upload
 
 	AutopilotResponses map[string]string
+	// SSOSessions maps refresh tokens to the sessions they are refreshed into.
+	SSOSessions map[string]SSOSessionResponse
 
 	apiKey      string
 	source      string
@@ -67,8 +91,9 @@ func (mc *MockClient) GetTaskMetadata(ctx context.Context, slug string) (res lib
 		return libapi.TaskMetadata{}, &libapi.TaskMissingError{AppURL: "api/", Slug: slug}
 	}
 	return libapi.TaskMetadata{
-		ID:   task.ID,
-		Slug: task.Slug,
+		ID:         task.ID,
+		Slug:       task.Slug,
+		IsArchived: task.IsArchived,
 	}, nil
 }
 
@@ -97,11 +122,73 @@ func (mc *MockClient) RunTask(ctx context.Context, req RunTaskRequest) (RunTaskR
 }
 
 func (mc *MockClient) GetRun(ctx context.Context, id string) (res GetRunResponse, err error) {
-	panic("not implemented")
+	for _, r := range mc.Runs {
+		if r.RunID == id {
+			return GetRunResponse{Run: r}, nil
+		}
+	}
+	return GetRunResponse{}, libhttp.ErrStatusCode{StatusCode: http.StatusNotFound, Msg: fmt.Sprintf("run %s does not exist", id)}
+}
+
+func (mc *MockClient) GetLogs(ctx context.Context, runID, prevToken string) (res GetLogsResponse, err error) {
+	// All logs are returned in a single page.
+	if prevToken != "" {
+		return GetLogsResponse{RunID: runID, PrevPageToken: prevToken}, nil
+	}
+	return GetLogsResponse{RunID: runID, Logs: mc.RunLogs[runID], PrevPageToken: "end"}, nil
+}
+
+func (mc *MockClient) DownloadLogs(ctx context.Context, req DownloadLogsRequest) (res DownloadLogsResponse, err error) {
+	logs := mc.RunLogFiles[req.RunID]
+	size := int64(len(logs))
+	if req.Offset >= size {
+		return DownloadLogsResponse{}, libhttp.ErrStatusCode{StatusCode: http.StatusRequestedRangeNotSatisfiable}
+	}
+	end := size
+	if req.Length > 0 && req.Offset+req.Length < end {
+		end = req.Offset + req.Length
+	}
+	return DownloadLogsResponse{
+		Body:   io.NopCloser(strings.NewReader(logs[req.Offset:end])),
+		Offset: req.Offset,
+		Size:   size,
+	}, nil
 }
 
 func (mc *MockClient) GetOutputs(ctx context.Context, runID string) (res GetOutputsResponse, err error) {
-	panic("not implemented")
+	return GetOutputsResponse{Outputs: mc.RunOutputs[runID]}, nil
+}
+
+func (mc *MockClient) CreatePrompt(ctx context.Context, req libapi.Prompt) (res CreatePromptResponse, err error) {
+	if mc.Prompts == nil {
+		mc.Prompts = map[string]libapi.Prompt{}
+	}
+	req.ID = utils.GenerateID("pmt")
+	req.CreatedAt = time.Now()
+	mc.Prompts[req.ID] = req
+	return CreatePromptResponse{ID: req.ID}, nil
+}
+
+func (mc *MockClient) GetPrompt(ctx context.Context, id string) (res GetPromptResponse, err error) {
+	prompt, ok := mc.Prompts[id]
+	if !ok {
+		return GetPromptResponse{}, libhttp.ErrStatusCode{StatusCode: http.StatusNotFound, Msg: fmt.Sprintf("prompt %s does not exist", id)}
+	}
+	return GetPromptResponse{Prompt: prompt}, nil
+}
+
+func (mc *MockClient) CancelPrompt(ctx context.Context, req CancelPromptRequest) error {
+	prompt, ok := mc.Prompts[req.ID]
+	if !ok || prompt.RunID != req.RunID {
+		return libhttp.ErrStatusCode{StatusCode: http.StatusNotFound, Msg: fmt.Sprintf("prompt %s does not exist", req.ID)}
+	}
+	if prompt.SubmittedAt != nil || prompt.CancelledAt != nil {
+		return errors.New("prompt has already completed")
+	}
+	now := time.Now()
+	prompt.CancelledAt = &now
+	mc.Prompts[req.ID] = prompt
+	return nil
 }
 
 func (mc *MockClient) GetRunbook(ctx context.Context, runbookSlug string, envSlug string) (res GetRunbookResponse, err error) {
@@ -143,7 +230,21 @@ func (mc *MockClient) ListResourceMetadata(ctx context.Context) (res libapi.List
 }
 
 func (mc *MockClient) SetConfig(ctx context.Context, req SetConfigRequest) (err error) {
-	panic("not implemented") // TODO: Implement
+	for i, c := range mc.Configs {
+		if c.Name == req.Name && c.Tag == req.Tag {
+			mc.Configs[i].Value = req.Value
+			mc.Configs[i].IsSecret = req.IsSecret
+			return nil
+		}
+	}
+	mc.Configs = append(mc.Configs, Config{
+		ID:       utils.GenerateID("cfg"),
+		Name:     req.Name,
+		Tag:      req.Tag,
+		Value:    req.Value,
+		IsSecret: req.IsSecret,
+	})
+	return nil
 }
 
 func (mc *MockClient) GetConfig(ctx context.Context, req GetConfigRequest) (res GetConfigResponse, err error) {
@@ -160,6 +261,16 @@ func (mc *MockClient) ListConfigs(ctx context.Context, req ListConfigsRequest) (
 	return ListConfigsResponse{Configs: mc.Configs}, nil
 }
 
+func (mc *MockClient) DeleteConfig(ctx context.Context, req DeleteConfigRequest) (err error) {
+	for i, c := range mc.Configs {
+		if c.ID == req.ID {
+			mc.Configs = append(mc.Configs[:i], mc.Configs[i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("config %s does not exist", req.ID)
+}
+
 func (mc *MockClient) TaskURL(slug string, envSlug string) string {
 	if envSlug != "" {
 		return fmt.Sprintf("api/t/%s?__env=%s", slug, envSlug)
@@ -167,6 +278,52 @@ func (mc *MockClient) TaskURL(slug string, envSlug string) string {
 	return fmt.Sprintf("api/t/%s", slug)
 }
 
+func (mc *MockClient) EnableTrigger(ctx context.Context, req EnableTriggerRequest) error {
+	for slug, task := range mc.Tasks {
+		for i, trigger := range task.Triggers {
+			if trigger.ID == req.TriggerID {
+				task.Triggers[i].DisabledAt = nil
+				mc.Tasks[slug] = task
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("no trigger %s", req.TriggerID)
+}
+
+func (mc *MockClient) DisableTrigger(ctx context.Context, req DisableTriggerRequest) error {
+	for slug, task := range mc.Tasks {
+		for i, trigger := range task.Triggers {
+			if trigger.ID == req.TriggerID {
+				now := time.Now()
+				task.Triggers[i].DisabledAt = &now
+				mc.Tasks[slug] = task
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("no trigger %s", req.TriggerID)
+}
+
+func (mc *MockClient) ArchiveTask(ctx context.Context, req ArchiveTaskRequest) error {
+	return mc.setTaskArchived(req.TaskID, true)
+}
+
+func (mc *MockClient) UnarchiveTask(ctx context.Context, req UnarchiveTaskRequest) error {
+	return mc.setTaskArchived(req.TaskID, false)
+}
+
+func (mc *MockClient) setTaskArchived(taskID string, archived bool) error {
+	for slug, task := range mc.Tasks {
+		if task.ID == taskID {
+			task.IsArchived = archived
+			mc.Tasks[slug] = task
+			return nil
+		}
+	}
+	return errors.Errorf("no task %s", taskID)
+}
+
 func (mc *MockClient) UpdateTask(ctx context.Context, req libapi.UpdateTaskRequest) (res UpdateTaskResponse, err error) {
 	task, ok := mc.Tasks[req.Slug]
 	if !ok {
@@ -206,7 +363,40 @@ func (mc *MockClient) CreateTask(ctx context.Context, req CreateTaskRequest) (re
 }
 
 func (mc *MockClient) ListRuns(ctx context.Context, req ListRunsRequest) (ListRunsResponse, error) {
-	panic("not implemented")
+	var runs []Run
+	for _, r := range mc.Runs {
+		if req.TaskID != "" && r.TaskID != req.TaskID {
+			continue
+		}
+		if !req.Since.IsZero() && r.CreatedAt.Before(req.Since) {
+			continue
+		}
+		if !req.Until.IsZero() && r.CreatedAt.After(req.Until) {
+			continue
+		}
+		if len(req.Statuses) > 0 && !slices.Contains(req.Statuses, r.Status) {
+			continue
+		}
+		runs = append(runs, r)
+		if req.Limit > 0 && len(runs) == req.Limit {
+			break
+		}
+	}
+	return ListRunsResponse{Runs: runs}, nil
+}
+
+func (mc *MockClient) CancelRun(ctx context.Context, req CancelRunRequest) error {
+	for i, r := range mc.Runs {
+		if r.RunID != req.RunID {
+			continue
+		}
+		if r.Status.IsTerminal() {
+			return errors.Errorf("cannot cancel run %s (state is already terminal)", r.RunID)
+		}
+		mc.Runs[i].Status = RunCancelled
+		return nil
+	}
+	return libhttp.ErrStatusCode{StatusCode: http.StatusNotFound, Msg: fmt.Sprintf("run %s does not exist", req.RunID)}
 }
 
 // TODO add other functions when needed.
@@ -224,6 +414,22 @@ func (mc *MockClient) GetDeploymentLogs(ctx context.Context, id string, prevToke
 	return GetDeploymentLogsResponse{}, nil
 }
 
+func (mc *MockClient) GetBuild(ctx context.Context, id string) (res Build, err error) {
+	build, ok := mc.Builds[id]
+	if !ok {
+		return Build{}, libhttp.ErrStatusCode{StatusCode: http.StatusNotFound}
+	}
+	return build, nil
+}
+
+func (mc *MockClient) GetBuildContextManifest(ctx context.Context, id string) (res GetBuildContextManifestResponse, err error) {
+	return GetBuildContextManifestResponse{Files: mc.BuildContextFiles[id]}, nil
+}
+
+func (mc *MockClient) GetBuildLogs(ctx context.Context, id string, step int, prevToken string) (res GetBuildLogsResponse, err error) {
+	return GetBuildLogsResponse{Logs: mc.BuildLogs[id]}, nil
+}
+
 func (mc *MockClient) GetDeployment(ctx context.Context, id string) (res Deployment, err error) {
 	if mc.GetDeploymentResponse != nil {
 		return *mc.GetDeploymentResponse, nil
@@ -248,6 +454,23 @@ func (mc *MockClient) CancelDeployment(ctx context.Context, req CancelDeployment
 	return nil
 }
 
+func (mc *MockClient) ListDeployments(ctx context.Context, req ListDeploymentsRequest) (res ListDeploymentsResponse, err error) {
+	deployments := mc.Deployments
+	if req.Limit > 0 && len(deployments) > req.Limit {
+		deployments = deployments[:req.Limit]
+	}
+	return ListDeploymentsResponse{Deployments: deployments}, nil
+}
+
+func (mc *MockClient) ListDeploymentRevisions(ctx context.Context, deploymentID string) (res ListDeploymentRevisionsResponse, err error) {
+	return ListDeploymentRevisionsResponse{Revisions: mc.DeploymentRevisions[deploymentID]}, nil
+}
+
+func (mc *MockClient) ActivateTaskRevision(ctx context.Context, req ActivateTaskRevisionRequest) error {
+	mc.ActivatedRevisions = append(mc.ActivatedRevisions, req)
+	return nil
+}
+
 // DeploymentURL returns a URL for a deployment.
 func (mc *MockClient) DeploymentURL(deploymentID string, envSlug string) string {
 	if envSlug != "" {
@@ -277,7 +500,82 @@ func (mc *MockClient) GetViewMetadata(ctx context.Context, slug string) (res lib
 }
 
 func (mc *MockClient) CreateView(ctx context.Context, req libapi.CreateViewRequest) (res libapi.View, err error) {
-	panic("not implemented") // TODO: Implement
+	if _, ok := mc.Views[req.Slug]; ok {
+		return libapi.View{}, errors.Errorf("view with slug %s already exists", req.Slug)
+	}
+	if mc.Views == nil {
+		mc.Views = map[string]libapi.View{}
+	}
+	view := libapi.View{
+		ID:          utils.GenerateID("view"),
+		Slug:        req.Slug,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+		EnvVars:     mockViewEnvVars(req.EnvVars),
+	}
+	mc.Views[req.Slug] = view
+	return view, nil
+}
+
+func (mc *MockClient) ListViews(ctx context.Context) (res ListViewsResponse, err error) {
+	views := make([]libapi.View, 0, len(mc.Views))
+	for _, view := range mc.Views {
+		views = append(views, view)
+	}
+	return ListViewsResponse{Views: views}, nil
+}
+
+func (mc *MockClient) UpdateView(ctx context.Context, req UpdateViewRequest) (res libapi.View, err error) {
+	view, ok := mc.Views[req.Slug]
+	if !ok {
+		return libapi.View{}, &libapi.ViewMissingError{AppURL: "api/", Slug: req.Slug}
+	}
+	view.Name = req.Name
+	view.Description = req.Description
+	view.EnvVars = mockViewEnvVars(req.EnvVars)
+	mc.Views[req.Slug] = view
+	return view, nil
+}
+
+func (mc *MockClient) GetViewPermissions(ctx context.Context, slug string) (res ViewPermissions, err error) {
+	if _, ok := mc.Views[slug]; !ok {
+		return ViewPermissions{}, &libapi.ViewMissingError{AppURL: "api/", Slug: slug}
+	}
+	return mc.ViewPermissions[slug], nil
+}
+
+func (mc *MockClient) UpdateViewPermissions(ctx context.Context, req UpdateViewPermissionsRequest) error {
+	if _, ok := mc.Views[req.Slug]; !ok {
+		return &libapi.ViewMissingError{AppURL: "api/", Slug: req.Slug}
+	}
+	if mc.ViewPermissions == nil {
+		mc.ViewPermissions = map[string]ViewPermissions{}
+	}
+	mc.ViewPermissions[req.Slug] = ViewPermissions{
+		RequireExplicitPermissions: req.RequireExplicitPermissions,
+		Permissions:                req.Permissions,
+	}
+	return nil
+}
+
+// mockViewEnvVars flattens env vars into the format that views are returned with.
+func mockViewEnvVars(envVars libapi.EnvVars) map[string]string {
+	res := map[string]string{}
+	for k, v := range envVars {
+		if v.Value != nil {
+			res[k] = *v.Value
+		}
+	}
+	return res
+}
+
+func (mc *MockClient) GetViewAssetManifest(ctx context.Context, req GetViewAssetManifestRequest) (res GetViewAssetManifestResponse, err error) {
+	assets, ok := mc.ViewAssetManifests[req.ViewSlug]
+	if !ok {
+		return GetViewAssetManifestResponse{}, &libapi.ViewMissingError{AppURL: "api/", Slug: req.ViewSlug}
+	}
+	return GetViewAssetManifestResponse{Assets: assets}, nil
 }
 
 func (mc *MockClient) CreateDemoDB(ctx context.Context, name string) (string, error) {
@@ -310,6 +608,41 @@ func (mc *MockClient) ListEnvs(ctx context.Context) (ListEnvsResponse, error) {
 	}, nil
 }
 
+func (mc *MockClient) CreateEnv(ctx context.Context, req CreateEnvRequest) (libapi.Env, error) {
+	if _, ok := mc.Envs[req.Slug]; ok {
+		return libapi.Env{}, errors.Errorf("environment with slug %s already exists", req.Slug)
+	}
+	if mc.Envs == nil {
+		mc.Envs = map[string]libapi.Env{}
+	}
+	env := libapi.Env{
+		ID:   utils.GenerateID("env"),
+		Slug: req.Slug,
+		Name: req.Name,
+	}
+	mc.Envs[req.Slug] = env
+	return env, nil
+}
+
+func (mc *MockClient) DeleteEnv(ctx context.Context, req DeleteEnvRequest) error {
+	if _, ok := mc.Envs[req.Slug]; !ok {
+		return errors.Errorf("environment with slug %s does not exist", req.Slug)
+	}
+	delete(mc.Envs, req.Slug)
+	return nil
+}
+
+func (mc *MockClient) CreateResource(ctx context.Context, req CreateResourceRequest) (CreateResourceResponse, error) {
+	id := utils.GenerateID("res")
+	mc.Resources = append(mc.Resources, libapi.Resource{
+		ID:   id,
+		Slug: req.Slug,
+		Name: req.Name,
+		Kind: req.Kind,
+	})
+	return CreateResourceResponse{ResourceID: id}, nil
+}
+
 func (mc *MockClient) GetResource(ctx context.Context, req GetResourceRequest) (res libapi.GetResourceResponse, err error) {
 	for _, r := range mc.Resources {
 		if r.Slug != "" && r.Slug == req.Slug {
@@ -384,6 +717,48 @@ func (mc *MockClient) GetPermissions(ctx context.Context, taskSlug string, actio
 	panic("not implemented")
 }
 
+func (mc *MockClient) ValidateRunAs(ctx context.Context, req ValidateRunAsRequest) (res ValidateRunAsResponse, err error) {
+	for _, slug := range mc.ServiceAccounts {
+		if slug == req.ServiceAccountSlug {
+			return ValidateRunAsResponse{Allowed: true}, nil
+		}
+	}
+	return ValidateRunAsResponse{Reason: "service account not found"}, nil
+}
+
+func (mc *MockClient) ListAgentPools(ctx context.Context, req ListAgentPoolsRequest) (res ListAgentPoolsResponse, err error) {
+	return ListAgentPoolsResponse{Pools: mc.AgentPools}, nil
+}
+
+func (mc *MockClient) ListAgents(ctx context.Context, req ListAgentsRequest) (res ListAgentsResponse, err error) {
+	agents := []Agent{}
+	for _, a := range mc.Agents {
+		if req.Pool == "" || a.Pool == req.Pool {
+			agents = append(agents, a)
+		}
+	}
+	return ListAgentsResponse{Agents: agents}, nil
+}
+
+func (mc *MockClient) GetAgent(ctx context.Context, req GetAgentRequest) (res Agent, err error) {
+	for _, a := range mc.Agents {
+		if a.ID == req.AgentID {
+			return a, nil
+		}
+	}
+	return Agent{}, errors.Errorf("no agent %s", req.AgentID)
+}
+
+func (mc *MockClient) DrainAgent(ctx context.Context, req DrainAgentRequest) error {
+	for i, a := range mc.Agents {
+		if a.ID == req.AgentID {
+			mc.Agents[i].Status = AgentStatusDraining
+			return nil
+		}
+	}
+	return errors.Errorf("no agent %s", req.AgentID)
+}
+
 func (mc *MockClient) GenerateSignedURLs(ctx context.Context, envSlug string) (res GenerateSignedURLsResponse, err error) {
 	panic("not implemented")
 }
@@ -400,6 +775,18 @@ func (mc *MockClient) GetUser(ctx context.Context, userID string) (res GetUserRe
 	}
 }
 
+func (mc *MockClient) ListUsers(ctx context.Context) (res ListUsersResponse, err error) {
+	users := make([]User, 0, len(mc.Users))
+	for _, user := range mc.Users {
+		users = append(users, user)
+	}
+	return ListUsersResponse{Users: users}, nil
+}
+
+func (mc *MockClient) ListGroups(ctx context.Context) (res ListGroupsResponse, err error) {
+	return ListGroupsResponse{Groups: mc.Groups}, nil
+}
+
 func (mc *MockClient) CreateUpload(ctx context.Context, req libapi.CreateUploadRequest) (res libapi.CreateUploadResponse, err error) {
 	id := utils.GenerateID("upl")
 	upload := libapi.Upload{
@@ -465,6 +852,10 @@ func (mc *MockClient) LoginURL(uri string) string {
 	panic("not implemented")
 }
 
+func (mc *MockClient) SSOLoginURL(orgSlug, uri, state string) string {
+	panic("not implemented")
+}
+
 func (mc *MockClient) LoginSuccessURL() string {
 	panic("not implemented")
 }
@@ -486,3 +877,23 @@ func (mc *MockClient) AutopilotComplete(ctx context.Context, req AutopilotComple
 func (mc *MockClient) GenerateStudioIDToken(ctx context.Context, req GenerateStudioIDTokenRequest) (GenerateStudioIDTokenResponse, error) {
 	panic("not implemented")
 }
+
+func (mc *MockClient) ExchangeSSOCode(ctx context.Context, req ExchangeSSOCodeRequest) (SSOSessionResponse, error) {
+	panic("not implemented")
+}
+
+func (mc *MockClient) StartSSODeviceAuth(ctx context.Context, req StartSSODeviceAuthRequest) (StartSSODeviceAuthResponse, error) {
+	panic("not implemented")
+}
+
+func (mc *MockClient) PollSSODeviceAuth(ctx context.Context, req PollSSODeviceAuthRequest) (PollSSODeviceAuthResponse, error) {
+	panic("not implemented")
+}
+
+func (mc *MockClient) RefreshSSOSession(ctx context.Context, req RefreshSSOSessionRequest) (SSOSessionResponse, error) {
+	session, ok := mc.SSOSessions[req.RefreshToken]
+	if !ok {
+		return SSOSessionResponse{}, libhttp.ErrStatusCode{StatusCode: 401, Msg: "invalid refresh token"}
+	}
+	return session, nil
+}
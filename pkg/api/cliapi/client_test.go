@@ -1,7 +1,12 @@
 package api
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -48,3 +53,169 @@ func TestEncodeQueryStringest(tt *testing.T) {
 		})
 	}
 }
+
+func TestParseContentRange(tt *testing.T) {
+	tests := []struct {
+		header string
+		start  int64
+		size   int64
+		err    bool
+	}{
+		{header: "bytes 0-99/1000", start: 0, size: 1000},
+		{header: "bytes 100-199/*", start: 100, size: -1},
+		{header: "bytes */1000", err: true},
+		{header: "items 0-99/1000", err: true},
+		{header: "", err: true},
+	}
+	for _, test := range tests {
+		tt.Run(test.header, func(t *testing.T) {
+			start, size, err := parseContentRange(test.header)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.start, start)
+			require.Equal(t, test.size, size)
+		})
+	}
+}
+
+func TestPostRetries(t *testing.T) {
+	require := require.New(t)
+
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts[req.URL.Path]++
+		if attempts[req.URL.Path] == 1 {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = rw.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(ClientOpts{Host: strings.TrimPrefix(server.URL, "http://"), Token: "token"})
+
+	// Endpoints that set state are retried.
+	require.NoError(client.post(context.Background(), "/tasks/update", nil, nil))
+	require.Equal(2, attempts["/v0/tasks/update"])
+
+	// Endpoints that create state aren't, since the API may have processed the request.
+	require.Error(client.post(context.Background(), encodeQueryString("/tasks/execute", url.Values{"env": []string{"prod"}}), nil, nil))
+	require.Equal(1, attempts["/v0/tasks/execute"])
+}
+
+func TestListRuns(t *testing.T) {
+	require := require.New(t)
+
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.Query())
+		// Every page is full, so only the limit stops paging.
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"runs": [` + strings.Repeat(`{"id": "run"},`, 49) + `{"id": "run"}]}`))
+	}))
+	defer server.Close()
+	client := NewClient(ClientOpts{Host: strings.TrimPrefix(server.URL, "http://"), Token: "token"})
+
+	resp, err := client.ListRuns(context.Background(), ListRunsRequest{
+		Statuses: []RunStatus{RunQueued, RunActive},
+		Limit:    120,
+	})
+	require.NoError(err)
+	require.Len(resp.Runs, 120)
+	require.Len(queries, 3)
+	require.Equal([]string{"Queued", "Active"}, queries[0]["statuses"])
+	require.Equal("2", queries[2].Get("page"))
+}
+
+func TestArchiveTask(t *testing.T) {
+	require := require.New(t)
+
+	var paths, bodies []string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		buf, _ := io.ReadAll(req.Body)
+		paths = append(paths, req.URL.Path)
+		bodies = append(bodies, string(buf))
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		_, _ = rw.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(ClientOpts{Host: strings.TrimPrefix(server.URL, "http://"), Token: "token"})
+
+	ctx := context.Background()
+	require.NoError(client.ArchiveTask(ctx, ArchiveTaskRequest{TaskID: "tsk1", EnvSlug: "prod"}))
+	require.NoError(client.UnarchiveTask(ctx, UnarchiveTaskRequest{TaskID: "tsk1", EnvSlug: "prod"}))
+	require.Equal([]string{"/v0/tasks/archive", "/v0/tasks/unarchive"}, paths)
+	require.JSONEq(`{"taskID": "tsk1", "envSlug": "prod"}`, bodies[0])
+
+	status = http.StatusNotFound
+	require.EqualError(client.ArchiveTask(ctx, ArchiveTaskRequest{TaskID: "tsk1"}), "the Airplane API doesn't support archiving tasks from the CLI, use the task's page in the app instead")
+}
+
+func TestAgents(t *testing.T) {
+	require := require.New(t)
+
+	var requests []string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.RequestURI())
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		_, _ = rw.Write([]byte(`{"agents": [{"id": "agt1", "pool": "batch", "status": "healthy"}]}`))
+	}))
+	defer server.Close()
+	client := NewClient(ClientOpts{Host: strings.TrimPrefix(server.URL, "http://"), Token: "token"})
+
+	ctx := context.Background()
+	a, err := client.GetAgent(ctx, GetAgentRequest{AgentID: "agt1", EnvSlug: "prod"})
+	require.NoError(err)
+	require.Equal("batch", a.Pool)
+	_, err = client.GetAgent(ctx, GetAgentRequest{AgentID: "agt2", EnvSlug: "prod"})
+	require.EqualError(err, "no agent agt2")
+	require.NoError(client.DrainAgent(ctx, DrainAgentRequest{AgentID: "agt1", EnvSlug: "prod"}))
+	require.Equal([]string{
+		"GET /v0/agents/list?envSlug=prod",
+		"GET /v0/agents/list?envSlug=prod",
+		"POST /v0/agents/drain",
+	}, requests)
+
+	status = http.StatusNotFound
+	_, err = client.ListAgents(ctx, ListAgentsRequest{})
+	require.EqualError(err, "the Airplane API doesn't support listing agents from the CLI, manage agents in the app instead")
+	require.EqualError(client.DrainAgent(ctx, DrainAgentRequest{AgentID: "agt1"}), "the Airplane API doesn't support draining agents from the CLI, manage agents in the app instead")
+}
+
+func TestRollbackDeployment(t *testing.T) {
+	require := require.New(t)
+
+	var requests []string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		buf, _ := io.ReadAll(req.Body)
+		requests = append(requests, strings.TrimSpace(req.Method+" "+req.URL.RequestURI()+" "+string(buf)))
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		_, _ = rw.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(ClientOpts{Host: strings.TrimPrefix(server.URL, "http://"), Token: "token"})
+
+	ctx := context.Background()
+	_, err := client.ListDeployments(ctx, ListDeploymentsRequest{EnvSlug: "prod", Limit: 50})
+	require.NoError(err)
+	_, err = client.ListDeploymentRevisions(ctx, "dep1")
+	require.NoError(err)
+	require.NoError(client.ActivateTaskRevision(ctx, ActivateTaskRevisionRequest{TaskID: "tsk1", RevisionID: "rev1", EnvSlug: "prod"}))
+	require.Equal([]string{
+		"GET /v0/deployments/list?envSlug=prod&limit=50",
+		"GET /v0/deployments/listRevisions?id=dep1",
+		`POST /v0/tasks/activateRevision?envSlug=prod {"taskID":"tsk1","revisionID":"rev1"}`,
+	}, requests)
+
+	status = http.StatusNotFound
+	_, err = client.ListDeploymentRevisions(ctx, "dep1")
+	require.EqualError(err, "the Airplane API doesn't support rolling back deployments from the CLI, redeploy the previous version instead")
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"io"
 	"time"
 
 	// Some types are imported from lib. Eventually we might want all of these types to live in lib. For now,
@@ -52,6 +53,26 @@ type UpdateTaskResponse struct {
 	TaskRevisionID string `json:"taskRevisionID"`
 }
 
+type EnableTriggerRequest struct {
+	TriggerID string `json:"triggerID"`
+	EnvSlug   string `json:"envSlug"`
+}
+
+type DisableTriggerRequest struct {
+	TriggerID string `json:"triggerID"`
+	EnvSlug   string `json:"envSlug"`
+}
+
+type ArchiveTaskRequest struct {
+	TaskID  string `json:"taskID"`
+	EnvSlug string `json:"envSlug"`
+}
+
+type UnarchiveTaskRequest struct {
+	TaskID  string `json:"taskID"`
+	EnvSlug string `json:"envSlug"`
+}
+
 // GetLogsResponse represents a get logs response.
 type GetLogsResponse struct {
 	RunID         string    `json:"runID"`
@@ -60,6 +81,27 @@ type GetLogsResponse struct {
 	PrevPageToken string    `json:"prev_token"`
 }
 
+// DownloadLogsRequest represents a request to download a range of a run's logs.
+type DownloadLogsRequest struct {
+	RunID string
+	// Offset is the byte offset to start downloading the logs at.
+	Offset int64
+	// Length is the maximum number of bytes to download. If zero, the rest of the logs are
+	// downloaded.
+	Length int64
+}
+
+// DownloadLogsResponse is a range of a run's logs, as text with one log per line.
+type DownloadLogsResponse struct {
+	// Body is the (decompressed) range of logs. It must be closed by the caller.
+	Body io.ReadCloser
+	// Offset is the byte offset of Body within the logs. It is zero if the API ignored the
+	// requested range and returned all logs.
+	Offset int64
+	// Size is the total size of the logs in bytes, or -1 if unknown.
+	Size int64
+}
+
 // GetDeploymentLogsResponse represents a get deploy logs response.
 type GetDeploymentLogsResponse struct {
 	Logs          []LogItem `json:"logs"`
@@ -196,6 +238,47 @@ type AuthInfoResponse struct {
 	Team *TeamInfo `json:"team"`
 }
 
+type ExchangeSSOCodeRequest struct {
+	OrgSlug string `json:"orgSlug"`
+	Code    string `json:"code"`
+}
+
+// SSOSessionResponse is a session issued by an SSO login. The token expires at ExpiresAt, after
+// which the refresh token can be exchanged for a new session.
+type SSOSessionResponse struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+type StartSSODeviceAuthRequest struct {
+	OrgSlug string `json:"orgSlug"`
+}
+
+type StartSSODeviceAuthResponse struct {
+	DeviceCode string `json:"deviceCode"`
+	// UserCode is the code that the user enters at VerificationURL.
+	UserCode        string `json:"userCode"`
+	VerificationURL string `json:"verificationURL"`
+	// IntervalSeconds is how often to poll for the session.
+	IntervalSeconds int       `json:"intervalSeconds"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+}
+
+type PollSSODeviceAuthRequest struct {
+	DeviceCode string `json:"deviceCode"`
+}
+
+type PollSSODeviceAuthResponse struct {
+	// Pending is set while the user has not completed the login yet.
+	Pending bool `json:"pending"`
+	SSOSessionResponse
+}
+
+type RefreshSSOSessionRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
 type UserInfo struct {
 	ID    string `json:"id"`
 	Email string `json:"email"`
@@ -233,6 +316,10 @@ type RunTaskRequest struct {
 	TaskSlug    *string `json:"slug"`
 	ParamValues Values  `json:"paramValues"`
 	EnvSlug     string  `json:"envSlug"`
+	// Resources maps aliases to resource IDs. It is only used to run builtins.
+	Resources map[string]string `json:"resources,omitempty"`
+	// Priority overrides the task's default priority for this run.
+	Priority *int `json:"priority,omitempty"`
 }
 
 // RunTaskResponse represents a run task response.
@@ -245,6 +332,22 @@ type GetRunResponse struct {
 	Run Run `json:"run"`
 }
 
+// CreatePromptResponse represents a create prompt response.
+type CreatePromptResponse struct {
+	ID string `json:"id"`
+}
+
+// GetPromptResponse represents a get prompt response.
+type GetPromptResponse struct {
+	Prompt libapi.Prompt `json:"prompt"`
+}
+
+// CancelPromptRequest represents a cancel prompt request.
+type CancelPromptRequest struct {
+	ID    string `json:"id"`
+	RunID string `json:"runID"`
+}
+
 // RunStatus enumerates run status.
 type RunStatus string
 
@@ -280,6 +383,8 @@ type Run struct {
 	CancelledAt *time.Time         `json:"cancelledAt"`
 	CancelledBy *string            `json:"cancelledBy"`
 	EnvSlug     string             `json:"envSlug"`
+	// RunAs is the slug of the service account the run executed as, if any.
+	RunAs string `json:"runAs,omitempty"`
 }
 
 // ListRunsRequest represents a list runs request.
@@ -290,6 +395,8 @@ type ListRunsRequest struct {
 	Page    int       `json:"page"`
 	Limit   int       `json:"limit"`
 	EnvSlug string    `json:"envSlug"`
+	// Statuses only lists runs with one of these statuses. If empty, runs of any status are listed.
+	Statuses []RunStatus `json:"statuses"`
 }
 
 // ListRunsResponse represents a list runs response.
@@ -297,6 +404,11 @@ type ListRunsResponse struct {
 	Runs []Run `json:"runs"`
 }
 
+// CancelRunRequest represents a cancel run request.
+type CancelRunRequest struct {
+	RunID string `json:"runID"`
+}
+
 // GetConfigRequest represents a get config request
 type GetConfigRequest struct {
 	Name       string `json:"name"`
@@ -314,6 +426,12 @@ type SetConfigRequest struct {
 	EnvSlug  string `json:"envSlug"`
 }
 
+// DeleteConfigRequest represents a delete config request.
+type DeleteConfigRequest struct {
+	ID      string `json:"configID"`
+	EnvSlug string `json:"-"`
+}
+
 // ListConfigsRequest represents a list configs request
 type ListConfigsRequest struct {
 	Names       []string `json:"names"`
@@ -391,12 +509,68 @@ type DeployView struct {
 
 type BuildContext = buildtypes.BuildContext
 
+// LicenseReport lists a bundle's third-party dependencies and their licenses.
+type LicenseReport struct {
+	Dependencies   []LicenseDependency `json:"dependencies"`
+	TotalSizeBytes int64               `json:"totalSizeBytes"`
+}
+
+type LicenseDependency struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	License   string `json:"license"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
 type DeployBundle struct {
 	UploadID     string       `json:"uploadID"`
 	Name         string       `json:"name"`
 	TargetFiles  []string     `json:"targetFiles"`
 	BuildContext BuildContext `json:"buildContext"`
 	GitFilePath  string       `json:"gitFilePath"`
+	// LicenseReport lists the bundle's third-party dependencies and their licenses, if any
+	// were found locally.
+	LicenseReport *LicenseReport `json:"licenseReport,omitempty"`
+	// ImageRegistry is the external registry that the bundle's task images are pushed to, if any.
+	ImageRegistry *ImageRegistry `json:"imageRegistry,omitempty"`
+	// BuildCache is the registry-backed layer cache of the bundle's build, if any.
+	BuildCache *BuildCache `json:"buildCache,omitempty"`
+	// Attestation is the signed provenance of the bundle's archive, if deploys are signed.
+	Attestation *Attestation `json:"attestation,omitempty"`
+}
+
+// Attestation is a DSSE envelope of a signed in-toto statement about a bundle's archive.
+type Attestation struct {
+	// Digest is the hex-encoded SHA-256 digest of the archive that the statement is about.
+	Digest      string                 `json:"digest"`
+	PayloadType string                 `json:"payloadType"`
+	Payload     string                 `json:"payload"`
+	Signatures  []AttestationSignature `json:"signatures"`
+}
+
+type AttestationSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// ImageRegistry configures an external registry that built task images are pushed to. The digest
+// of each pushed image is recorded on the task by the builder, see Task.ImageDigest.
+type ImageRegistry struct {
+	// ImageTemplate is a template for the name of pushed images, rendered by the builder.
+	ImageTemplate string `json:"imageTemplate"`
+	// Exclusive skips pushing to the managed registry, so that tasks run the image pushed to
+	// the external registry.
+	Exclusive bool `json:"exclusive"`
+	// CredentialsConfig is the name of a config variable holding the registry credentials.
+	CredentialsConfig string `json:"credentialsConfig,omitempty"`
+}
+
+// BuildCache configures a layer cache that's stored inline in images in the registry.
+type BuildCache struct {
+	// From are images whose layers are reused by the build.
+	From []string `json:"from,omitempty"`
+	// To is the image that the build's layers are pushed to.
+	To string `json:"to,omitempty"`
 }
 
 type CreateDeploymentRequest struct {
@@ -405,6 +579,9 @@ type CreateDeploymentRequest struct {
 	Bundles     []DeployBundle `json:"bundles"`
 	GitMetadata GitMetadata    `json:"gitMetadata"`
 	EnvSlug     string         `json:"envSlug"`
+	// TemplateVars are the values of the variables that templated definition fields may
+	// reference, e.g. `image: registry/app:{{git.shortSHA}}`, keyed by variable name.
+	TemplateVars map[string]string `json:"templateVars,omitempty"`
 }
 
 type GenerateSignedURLsResponse struct {
@@ -415,6 +592,39 @@ type CancelDeploymentRequest struct {
 	ID string `json:"id"`
 }
 
+type ListDeploymentsRequest struct {
+	EnvSlug string
+	// Limit is the maximum number of deployments to list. The API's default is used if it's unset.
+	Limit int
+}
+
+type ListDeploymentsResponse struct {
+	Deployments []Deployment `json:"deployments"`
+}
+
+type ListDeploymentRevisionsResponse struct {
+	Revisions []DeploymentRevision `json:"revisions"`
+}
+
+// DeploymentRevision is a task revision that a deployment activated.
+type DeploymentRevision struct {
+	TaskID     string `json:"taskID"`
+	TaskSlug   string `json:"taskSlug"`
+	RevisionID string `json:"revisionID"`
+	// PreviousRevisionID is the revision that was active before the deployment. It's empty if the
+	// deployment created the task.
+	PreviousRevisionID string `json:"previousRevisionID"`
+	// ActiveRevisionID is the revision that is active now, which differs from RevisionID if the task
+	// was changed after the deployment.
+	ActiveRevisionID string `json:"activeRevisionID"`
+}
+
+type ActivateTaskRevisionRequest struct {
+	TaskID     string `json:"taskID"`
+	RevisionID string `json:"revisionID"`
+	EnvSlug    string `json:"-"`
+}
+
 type GitMetadata struct {
 	CommitHash          string    `json:"commitHash"`
 	Ref                 string    `json:"ref"`
@@ -450,6 +660,169 @@ type Deployment struct {
 	FailedReason string     `json:"failedReason,omitempty"`
 }
 
+// BuildStatus enumerates build status.
+type BuildStatus string
+
+// All BuildStatus types.
+const (
+	BuildActive    BuildStatus = "Active"
+	BuildSucceeded BuildStatus = "Succeeded"
+	BuildFailed    BuildStatus = "Failed"
+	BuildCancelled BuildStatus = "Cancelled"
+)
+
+// Build represents a remote image build of a task or bundle.
+type Build struct {
+	ID           string      `json:"id"`
+	Status       BuildStatus `json:"status"`
+	CreatedAt    time.Time   `json:"createdAt"`
+	FailedAt     *time.Time  `json:"failedAt,omitempty"`
+	FailedReason string      `json:"failedReason,omitempty"`
+	// Dockerfile is the Dockerfile that was generated for the build.
+	Dockerfile string `json:"dockerfile"`
+	// BaseImage is the image that the Dockerfile builds from.
+	BaseImage string `json:"baseImage"`
+	// FailedStep is the Dockerfile instruction that failed, if any.
+	FailedStep *BuildStep `json:"failedStep,omitempty"`
+}
+
+// BuildStep is a single instruction of a build's Dockerfile.
+type BuildStep struct {
+	// Index is the zero-based index of the instruction in the Dockerfile.
+	Index       int    `json:"index"`
+	Instruction string `json:"instruction"`
+}
+
+// BuildContextFile describes a single file in the context that a build was run with.
+type BuildContextFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type GetBuildContextManifestResponse struct {
+	Files []BuildContextFile `json:"files"`
+}
+
+// GetBuildLogsResponse represents a get build logs response.
+type GetBuildLogsResponse struct {
+	Logs          []LogItem `json:"logs"`
+	PrevPageToken string    `json:"prevToken"`
+}
+
+// ViewAsset describes a single file in a view's built assets.
+type ViewAsset struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type ValidateRunAsRequest struct {
+	TaskSlug           string `json:"taskSlug"`
+	ServiceAccountSlug string `json:"serviceAccountSlug"`
+	EnvSlug            string `json:"envSlug"`
+}
+
+type ValidateRunAsResponse struct {
+	// Allowed is true if the deployer is permitted to attach the service account to the task.
+	Allowed bool `json:"allowed"`
+	// Reason explains why the service account cannot be attached, if it is not allowed.
+	Reason string `json:"reason"`
+}
+
+type ListAgentPoolsRequest struct {
+	EnvSlug string
+	// IncludeCapacity requests the number of running and queued runs in each pool.
+	IncludeCapacity bool
+}
+
+type ListAgentPoolsResponse struct {
+	Pools []AgentPool `json:"pools"`
+}
+
+type AgentPool struct {
+	Name             string `json:"name" yaml:"name"`
+	HealthyAgents    int    `json:"healthyAgents" yaml:"healthyAgents"`
+	UnhealthyAgents  int    `json:"unhealthyAgents" yaml:"unhealthyAgents"`
+	RunningRunsCount int    `json:"runningRunsCount" yaml:"runningRunsCount"`
+	QueuedRunsCount  int    `json:"queuedRunsCount" yaml:"queuedRunsCount"`
+}
+
+type ListAgentsRequest struct {
+	EnvSlug string
+	// Pool only lists the agents in the named agent pool, if set.
+	Pool string
+}
+
+type ListAgentsResponse struct {
+	Agents []Agent `json:"agents"`
+}
+
+type GetAgentRequest struct {
+	AgentID string
+	EnvSlug string
+}
+
+type DrainAgentRequest struct {
+	AgentID string `json:"agentID"`
+	EnvSlug string `json:"envSlug"`
+}
+
+type AgentStatus string
+
+const (
+	AgentStatusHealthy   AgentStatus = "healthy"
+	AgentStatusUnhealthy AgentStatus = "unhealthy"
+	// AgentStatusDraining agents finish their active runs, but aren't assigned new runs.
+	AgentStatusDraining AgentStatus = "draining"
+)
+
+// Agent is a self-hosted agent, which runs the tasks whose run constraints match its labels.
+type Agent struct {
+	ID               string            `json:"id" yaml:"id"`
+	Hostname         string            `json:"hostname" yaml:"hostname"`
+	Pool             string            `json:"pool" yaml:"pool"`
+	Version          string            `json:"version" yaml:"version"`
+	Status           AgentStatus       `json:"status" yaml:"status"`
+	Labels           map[string]string `json:"labels" yaml:"labels"`
+	RunningRunsCount int               `json:"runningRunsCount" yaml:"runningRunsCount"`
+	LastHeartbeatAt  time.Time         `json:"lastHeartbeatAt" yaml:"lastHeartbeatAt"`
+}
+
+type GetViewAssetManifestRequest struct {
+	ViewSlug     string
+	DeploymentID string
+	EnvSlug      string
+}
+
+type GetViewAssetManifestResponse struct {
+	Assets []ViewAsset `json:"assets"`
+}
+
+type ListViewsResponse struct {
+	Views []libapi.View `json:"views"`
+}
+
+type UpdateViewRequest struct {
+	Slug        string         `json:"slug"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	EnvVars     libapi.EnvVars `json:"envVars"`
+}
+
+// ViewPermissions are the permissions of a view. Unless RequireExplicitPermissions is set, the
+// view is accessible to everyone on the team.
+type ViewPermissions struct {
+	RequireExplicitPermissions bool               `json:"requireExplicitPermissions"`
+	Permissions                libapi.Permissions `json:"permissions"`
+}
+
+type UpdateViewPermissionsRequest struct {
+	Slug                       string             `json:"slug"`
+	RequireExplicitPermissions bool               `json:"requireExplicitPermissions"`
+	Permissions                libapi.Permissions `json:"permissions"`
+}
+
 type App struct {
 	ID          string     `json:"id"`
 	Slug        string     `json:"slug"`
@@ -465,6 +838,29 @@ type CreateDemoDBRequest struct {
 	Name string `json:"name"`
 }
 
+type CreateEnvRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type DeleteEnvRequest struct {
+	Slug string `json:"slug"`
+}
+
+// CreateResourceRequest creates a resource in an environment. Resource holds the kind-specific
+// fields of the resource, e.g. baseURL for REST resources.
+type CreateResourceRequest struct {
+	Slug     string                 `json:"slug"`
+	Name     string                 `json:"name"`
+	Kind     libapi.ResourceKind    `json:"kind"`
+	EnvSlug  string                 `json:"envSlug"`
+	Resource map[string]interface{} `json:"resource"`
+}
+
+type CreateResourceResponse struct {
+	ResourceID string `json:"resourceID"`
+}
+
 type GetResourceRequest struct {
 	ID                   string `json:"id"`
 	Slug                 string `json:"slug"`
@@ -493,6 +889,19 @@ type GetUserResponse struct {
 	User User `json:"user"`
 }
 
+type ListUsersResponse struct {
+	Users []User `json:"users"`
+}
+
+type Group struct {
+	ID   string `json:"groupID"`
+	Name string `json:"name"`
+}
+
+type ListGroupsResponse struct {
+	Groups []Group `json:"groups"`
+}
+
 type GetTunnelTokenResponse struct {
 	Token string `json:"token"`
 }
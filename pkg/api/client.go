@@ -16,6 +16,8 @@ import (
 type IAPIClient interface {
 	// GetTask fetches a task by slug. If the slug does not match a task, a *TaskMissingError is returned.
 	GetTask(ctx context.Context, req GetTaskRequest) (res Task, err error)
+	// GetTaskByID fetches a task by ID. If the ID does not match a task, a *TaskMissingError is returned.
+	GetTaskByID(ctx context.Context, id string) (res Task, err error)
 	// GetTaskMetadata fetches a task's metadata by slug. If the slug does not match a task, a *TaskMissingError is returned.
 	GetTaskMetadata(ctx context.Context, slug string) (res TaskMetadata, err error)
 	GetView(ctx context.Context, req GetViewRequest) (res View, err error)
@@ -32,6 +34,7 @@ type Task struct {
 	Slug                       string                 `json:"slug" yaml:"slug"`
 	Description                string                 `json:"description" yaml:"description"`
 	Image                      *string                `json:"image" yaml:"image"`
+	ImageDigest                string                 `json:"imageDigest,omitempty" yaml:"-"`
 	Command                    []string               `json:"command" yaml:"command"`
 	Arguments                  []string               `json:"arguments" yaml:"arguments"`
 	Parameters                 Parameters             `json:"parameters" yaml:"parameters"`
@@ -49,6 +52,8 @@ type Task struct {
 	DefaultRunPermissions      DefaultRunPermissions  `json:"defaultRunPermissions" yaml:"defaultRunPermissions"`
 	ExecuteRules               ExecuteRules           `json:"executeRules" yaml:"-"`
 	Timeout                    int                    `json:"timeout" yaml:"timeout"`
+	Priority                   int                    `json:"priority" yaml:"priority"`
+	RunAs                      string                 `json:"runAs" yaml:"runAs"`
 	IsArchived                 bool                   `json:"isArchived" yaml:"isArchived"`
 	InterpolationMode          string                 `json:"interpolationMode" yaml:"-"`
 	Triggers                   []Trigger              `json:"triggers" yaml:"-"`
@@ -68,6 +73,7 @@ type Task struct {
 // - Repo
 // - ResourceRequests
 // - InterpolationMode
+// - ImageDigest
 func (t Task) AsUpdateTaskRequest() UpdateTaskRequest {
 	req := UpdateTaskRequest{
 		Slug:        t.Slug,
@@ -92,6 +98,8 @@ func (t Task) AsUpdateTaskRequest() UpdateTaskRequest {
 			ConcurrencyLimit:    t.ExecuteRules.ConcurrencyLimit,
 		},
 		Timeout:               t.Timeout,
+		Priority:              t.Priority,
+		RunAs:                 t.RunAs,
 		DefaultRunPermissions: (*DefaultRunPermissions)(pointers.String(string(t.DefaultRunPermissions))),
 	}
 
@@ -256,6 +264,8 @@ type UpdateTaskRequest struct {
 	ExecuteRules               UpdateExecuteRulesRequest `json:"executeRules"`
 	DefaultRunPermissions      *DefaultRunPermissions    `json:"defaultRunPermissions"`
 	Timeout                    int                       `json:"timeout"`
+	Priority                   int                       `json:"priority"`
+	RunAs                      string                    `json:"runAs"`
 	BuildID                    *string                   `json:"buildID"`
 	InterpolationMode          *string                   `json:"interpolationMode"`
 	EnvSlug                    string                    `json:"envSlug"`
@@ -418,6 +428,9 @@ type EnvVars map[string]EnvVarValue
 type EnvVarValue struct {
 	Value  *string `json:"value,omitempty" yaml:"value,omitempty"`
 	Config *string `json:"config,omitempty" yaml:"config,omitempty"`
+	// External references a secret stored outside of Airplane, e.g. `vault:secret/data/foo#key`.
+	// See the secrets package for the supported providers.
+	External *string `json:"external,omitempty" yaml:"external,omitempty"`
 }
 
 var _ yaml.Unmarshaler = &EnvVarValue{}
@@ -525,6 +538,8 @@ type Constraints struct {
 	Optional bool               `json:"optional" yaml:"optional,omitempty"`
 	Regex    string             `json:"regex" yaml:"regex,omitempty"`
 	Options  []ConstraintOption `json:"options,omitempty" yaml:"options,omitempty"`
+	// OptionsSource populates Options dynamically from the output of another task.
+	OptionsSource *OptionsSource `json:"optionsSource,omitempty" yaml:"optionsSource,omitempty"`
 }
 
 type ConstraintOption struct {
@@ -532,6 +547,19 @@ type ConstraintOption struct {
 	Value Value  `json:"value"`
 }
 
+// OptionsSource describes how to populate a parameter's options by running a task or builtin. The
+// output is expected to be a list of objects; ValuePath and LabelPath are dot-separated paths
+// into each object.
+type OptionsSource struct {
+	// Task is the slug of a task or builtin, e.g. airplane:sql_query.
+	Task        string                 `json:"task" yaml:"task"`
+	ParamValues map[string]interface{} `json:"paramValues,omitempty" yaml:"paramValues,omitempty"`
+	// Resources maps aliases to resource slugs. Builtins require exactly one resource.
+	Resources map[string]string `json:"resources,omitempty" yaml:"resources,omitempty"`
+	ValuePath string            `json:"valuePath" yaml:"valuePath"`
+	LabelPath string            `json:"labelPath,omitempty" yaml:"labelPath,omitempty"`
+}
+
 // Value represents a value.
 type Value interface{}
 
@@ -563,10 +591,12 @@ const (
 // RunConstraints represents run constraints.
 type RunConstraints struct {
 	Labels []AgentLabel `json:"labels" yaml:"labels"`
+	// Pool pins runs to agents in the named agent pool.
+	Pool string `json:"pool,omitempty" yaml:"pool,omitempty"`
 }
 
 func (rc RunConstraints) IsEmpty() bool {
-	return len(rc.Labels) == 0
+	return len(rc.Labels) == 0 && rc.Pool == ""
 }
 
 // AgentLabel represents an agent label.
@@ -624,6 +654,7 @@ type Schedule struct {
 	Description string                 `json:"description,omitempty"`
 	CronExpr    string                 `json:"cronExpr"`
 	ParamValues map[string]interface{} `json:"paramValues,omitempty"`
+	Paused      bool                   `json:"paused,omitempty"`
 }
 
 type Display struct {
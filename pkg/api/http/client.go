@@ -9,6 +9,7 @@ import (
 	"math"
 	"math/rand"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,6 +23,9 @@ const (
 	// is the same threshold that is used by the klauspost/compress library in
 	// the API when deciding whether to gzip request responses.
 	compressionSizeThreshold = 1024
+
+	// DefaultMaxRetries is the number of times a failed request is retried by default.
+	DefaultMaxRetries = 9
 )
 
 // RequiredHeaders are HTTP headers that must be set on every HTTP request.
@@ -31,6 +35,9 @@ var RequiredHeaders = []string{"X-Airplane-Client-Kind", "X-Airplane-Client-Vers
 type Client struct {
 	opts ClientOpts
 	http *retryablehttp.Client
+	// stream is used for requests whose bodies are streamed, which are not subject to
+	// ClientOpts.Timeout since reading a large body can take arbitrarily long.
+	stream *retryablehttp.Client
 }
 
 type ClientOpts struct {
@@ -61,6 +68,11 @@ type ClientOpts struct {
 	// from. Some common fields are `team/TEAM_ID`, `run/RUN_ID`, and `build/BUILD_ID`, e.g.
 	// "airplane/cli/v0.1.4 team/tea123".
 	UserAgent string
+	// MaxRetries is the maximum number of times a failed request is retried. A negative value
+	// disables retries.
+	//
+	// Defaults to DefaultMaxRetries.
+	MaxRetries int
 
 	// retryWaitMin overrides the minimum wait time between retries. Used for testing purposes only.
 	retryWaitMin time.Duration
@@ -85,14 +97,27 @@ func NewClient(opts ClientOpts) Client {
 	if opts.Timeout < 0 {
 		opts.Timeout = 0
 	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+
+	return Client{
+		opts:   opts,
+		http:   newRetryableClient(opts, false),
+		stream: newRetryableClient(opts, true),
+	}
+}
 
+func newRetryableClient(opts ClientOpts, stream bool) *retryablehttp.Client {
 	rhc := retryablehttp.NewClient()
 
 	rhc.Backoff = backoffExponential
 	rhc.RetryWaitMin = opts.retryWaitMin
 	rhc.RetryWaitMax = opts.retryWaitMax
-	// Perform up to 9 retries (10 total attempts).
-	rhc.RetryMax = 9
+	rhc.RetryMax = opts.MaxRetries
 
 	// Disable retryablehttp's automatic error wrapping, otherwise we don't get an *http.Response
 	// when all retries fail which prevents us from initializing an ErrStatusCode.
@@ -108,7 +133,9 @@ func NewClient(opts ClientOpts) Client {
 	//
 	// Currently, retryablehttp does not support a per-request timeout. If you need to support multiple timeouts,
 	// create multiple Clients.
-	rhc.HTTPClient.Timeout = opts.Timeout
+	if !stream {
+		rhc.HTTPClient.Timeout = opts.Timeout
+	}
 
 	// Attach optional logging hooks.
 	if opts.RequestLogHook != nil {
@@ -119,14 +146,18 @@ func NewClient(opts ClientOpts) Client {
 	}
 	if opts.ResponseLogHook != nil {
 		rhc.ResponseLogHook = func(l retryablehttp.Logger, r *http.Response) {
+			if stream {
+				// Streamed bodies are read by the caller, so only show the hook the status and
+				// headers of the response.
+				rc := *r
+				rc.Body = http.NoBody
+				r = &rc
+			}
 			opts.ResponseLogHook(r)
 		}
 	}
 
-	return Client{
-		opts: opts,
-		http: rhc,
-	}
+	return rhc
 }
 
 type ReqOpts struct {
@@ -145,8 +176,15 @@ type ReqOpts struct {
 	// from. Some common fields are `team/TEAM_ID`, `run/RUN_ID`, and `build/BUILD_ID`, e.g.
 	// "airplane/cli/v0.1.4 team/tea123".
 	UserAgent string
+	// NonIdempotent marks requests that can't be safely repeated once the API has processed them,
+	// even with an Idempotency-Key, e.g. exchanges of single-use tokens. They are only retried if
+	// they were rate limited, or if the API marks the response as retryable.
+	NonIdempotent bool
 }
 
+// nonIdempotentKey is the context key of requests that were made with ReqOpts.NonIdempotent.
+type nonIdempotentKey struct{}
+
 // Get issues an HTTP GET request to the Airplane API.
 //
 // If the API returns a non-2xx status code, an ErrStatusCode error will be returned.
@@ -166,6 +204,31 @@ func (c Client) Get(ctx context.Context, url string, opts ReqOpts) ([]byte, erro
 	return body, err
 }
 
+// GetStream issues an HTTP GET request to the Airplane API and returns the response without
+// reading its body. The caller must close the body.
+//
+// Unlike other requests, reading the body is not subject to ClientOpts.Timeout, so that large
+// responses can be streamed. Use the context to bound the request instead.
+//
+// If the API returns a non-2xx status code, an ErrStatusCode error will be returned.
+func (c Client) GetStream(ctx context.Context, url string, opts ReqOpts) (*http.Response, error) {
+	httpreq, err := c.newRequest(ctx, http.MethodGet, url, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.stream.Do(httpreq)
+	if err != nil {
+		return nil, errors.Wrap(err, "performing HTTP request")
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	return nil, NewErrStatusCodeFromResponse(resp)
+}
+
 // GetJSON issues an HTTP GET request to the Airplane API and unmarshals a the response body
 // as JSON into `resp`.
 //
@@ -320,6 +383,30 @@ func (c Client) doJSON(ctx context.Context, method string, url string, req []byt
 }
 
 func (c Client) do(ctx context.Context, method string, url string, req []byte, opts ReqOpts) ([]byte, http.Header, error) {
+	httpreq, err := c.newRequest(ctx, method, url, req, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.http.Do(httpreq)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "performing HTTP request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "reading response body")
+		}
+
+		return body, resp.Header, nil
+	}
+
+	return nil, nil, NewErrStatusCodeFromResponse(resp)
+}
+
+func (c Client) newRequest(ctx context.Context, method string, url string, req []byte, opts ReqOpts) (*retryablehttp.Request, error) {
 	contentEncoding := c.opts.Headers["Content-Encoding"]
 	if c := opts.Headers["Content-Encoding"]; c != "" {
 		contentEncoding = c
@@ -334,7 +421,7 @@ func (c Client) do(ctx context.Context, method string, url string, req []byte, o
 		// Gzip compress the body
 		req, err = gzipBytes(req)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		if opts.Headers == nil {
@@ -343,9 +430,12 @@ func (c Client) do(ctx context.Context, method string, url string, req []byte, o
 		opts.Headers["Content-Encoding"] = "gzip"
 	}
 
+	if opts.NonIdempotent {
+		ctx = context.WithValue(ctx, nonIdempotentKey{}, true)
+	}
 	httpreq, err := retryablehttp.NewRequestWithContext(ctx, method, url, req)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "initializing HTTP request")
+		return nil, errors.Wrap(err, "initializing HTTP request")
 	}
 
 	// Attach a GetBody method so that the net/http can automatically retry requests
@@ -376,7 +466,7 @@ func (c Client) do(ctx context.Context, method string, url string, req []byte, o
 	case http.MethodPost, http.MethodPatch:
 		idempotencyKey, err := uuid.NewRandom()
 		if err != nil {
-			return nil, nil, errors.Wrap(err, "generating idempotency key")
+			return nil, errors.Wrap(err, "generating idempotency key")
 		}
 		httpreq.Header.Set("Idempotency-Key", idempotencyKey.String())
 	}
@@ -384,36 +474,30 @@ func (c Client) do(ctx context.Context, method string, url string, req []byte, o
 	// Validate headers
 	for _, h := range RequiredHeaders {
 		if v := httpreq.Header.Get(h); v == "" {
-			return nil, nil, errors.Errorf("required header %q not set", h)
+			return nil, errors.Errorf("required header %q not set", h)
 		}
 	}
 	if err := validateUserAgent(httpreq.Header.Get("User-Agent")); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	resp, err := c.http.Do(httpreq)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "performing HTTP request")
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "reading response body")
-		}
-
-		return body, resp.Header, nil
-	}
-
-	return nil, nil, NewErrStatusCodeFromResponse(resp)
+	return httpreq, nil
 }
 
 // backoffExponential is a retryablehttp.Backoff function that produces an exponential
-// backoff policy with jitter.
+// backoff policy with jitter. If the API asks to be retried after a delay, with a Retry-After
+// header, that delay is used instead, up to max.
 //
 // Inspired by: https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
 func backoffExponential(min, max time.Duration, attempt int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if d > max {
+				return max
+			}
+			return d
+		}
+	}
 	if max <= min {
 		return min
 	}
@@ -427,6 +511,29 @@ func backoffExponential(min, max time.Duration, attempt int, resp *http.Response
 	return time.Duration(jitter * float64(duration))
 }
 
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds
+// or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
 // errorPropagatedRetryPolicy is a modified version of retryablehttp.ErrorPropagatedRetryPolicy.
 func errorPropagatedRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
 	// Certain status codes, e.g. 409, can sometimes be transient and should be retried. This is indicated
@@ -438,6 +545,13 @@ func errorPropagatedRetryPolicy(ctx context.Context, resp *http.Response, err er
 		}
 	}
 
+	// Requests that aren't idempotent are only retried if the API didn't process them.
+	if nonIdempotent, _ := ctx.Value(nonIdempotentKey{}).(bool); nonIdempotent {
+		if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+			return false, nil
+		}
+	}
+
 	retry, err := retryablehttp.ErrorPropagatedRetryPolicy(ctx, resp, err)
 	if err != nil {
 		// ErrorPropagatedRetryPolicy will return an "unexpected HTTP status" error when a 5xx error
@@ -50,6 +50,54 @@ func TestClientGet(t *testing.T) {
 	require.Equal("OK", string(body))
 }
 
+func TestClientGetStream(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal("GET", req.Method)
+		require.Equal("bytes=2-", req.Header.Get("Range"))
+		if req.URL.Path == "/missing" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.WriteHeader(http.StatusPartialContent)
+		_, _ = rw.Write([]byte(`OK`))
+		rw.(http.Flusher).Flush()
+		// Reading the body isn't subject to the client's timeout.
+		time.Sleep(20 * time.Millisecond)
+		_, _ = rw.Write([]byte(`!`))
+	}))
+	defer server.Close()
+
+	var hookBodies []string
+	client := NewClient(ClientOpts{
+		Headers:   requiredHeaderValues,
+		UserAgent: "airplane/test/1",
+		Timeout:   10 * time.Millisecond,
+		ResponseLogHook: func(resp *http.Response) {
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(err)
+			hookBodies = append(hookBodies, string(body))
+		},
+	})
+	opts := ReqOpts{Headers: map[string]string{"Range": "bytes=2-"}}
+	resp, err := client.GetStream(ctx, server.URL+"/foobar", opts)
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusPartialContent, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(err)
+	require.Equal("OK!", string(body))
+	// The log hook doesn't read streamed bodies.
+	require.Equal([]string{""}, hookBodies)
+
+	_, err = client.GetStream(ctx, server.URL+"/missing", opts)
+	var errsc ErrStatusCode
+	require.ErrorAs(err, &errsc)
+	require.Equal(http.StatusNotFound, errsc.StatusCode)
+}
+
 func TestClientGetJSON(t *testing.T) {
 	require := require.New(t)
 	ctx := context.Background()
@@ -443,6 +491,98 @@ func TestClientRetryHeader500(t *testing.T) {
 	require.Equal(1, retries)
 }
 
+func TestClientMaxRetries(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+		rw.WriteHeader(502)
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		maxRetries int
+		attempts   int
+	}{
+		{maxRetries: 2, attempts: 3},
+		{maxRetries: -1, attempts: 1},
+	} {
+		attempts = 0
+		client := NewClient(ClientOpts{
+			Headers:      requiredHeaderValues,
+			UserAgent:    "airplane/test/1",
+			MaxRetries:   test.maxRetries,
+			retryWaitMin: time.Millisecond,
+			retryWaitMax: time.Millisecond,
+		})
+		_, err := client.Get(ctx, server.URL+"/foobar", ReqOpts{})
+		var errsc ErrStatusCode
+		require.ErrorAs(err, &errsc)
+		require.Equal(502, errsc.StatusCode)
+		require.Equal(test.attempts, attempts)
+	}
+}
+
+func TestClientNonIdempotent(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var statuses []int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		status := statuses[0]
+		statuses = statuses[1:]
+		rw.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientOpts{
+		Headers:      requiredHeaderValues,
+		UserAgent:    "airplane/test/1",
+		retryWaitMin: time.Millisecond,
+		retryWaitMax: time.Millisecond,
+	})
+
+	// Server errors are not retried, since the API may have processed the request.
+	statuses = []int{500, 200}
+	_, err := client.Post(ctx, server.URL+"/foobar", nil, ReqOpts{NonIdempotent: true})
+	var errsc ErrStatusCode
+	require.ErrorAs(err, &errsc)
+	require.Equal(500, errsc.StatusCode)
+
+	// Rate limited requests are retried.
+	statuses = []int{429, 200}
+	_, err = client.Post(ctx, server.URL+"/foobar", nil, ReqOpts{NonIdempotent: true})
+	require.NoError(err)
+	require.Empty(statuses)
+}
+
+func TestBackoffRetryAfter(t *testing.T) {
+	require := require.New(t)
+
+	resp := &http.Response{StatusCode: 429, Header: http.Header{"Retry-After": []string{"3"}}}
+	require.Equal(3*time.Second, backoffExponential(time.Millisecond, time.Minute, 0, resp))
+	// Retry-After is capped at the maximum wait.
+	require.Equal(2*time.Second, backoffExponential(time.Millisecond, 2*time.Second, 0, resp))
+
+	// Retry-After is only honored for rate limits and unavailable servers.
+	resp.StatusCode = 500
+	require.LessOrEqual(backoffExponential(time.Millisecond, time.Millisecond, 0, resp), time.Millisecond)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, ok := parseRetryAfter("Sat, 01 Jan 2022 00:00:05 GMT", now)
+	require.True(ok)
+	require.Equal(5*time.Second, d)
+	d, ok = parseRetryAfter("Fri, 31 Dec 2021 00:00:00 GMT", now)
+	require.True(ok)
+	require.Zero(d)
+	_, ok = parseRetryAfter("soon", now)
+	require.False(ok)
+	_, ok = parseRetryAfter("-1", now)
+	require.False(ok)
+}
+
 func TestClientTimeout(t *testing.T) {
 	require := require.New(t)
 	ctx := context.Background()
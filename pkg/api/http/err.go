@@ -17,6 +17,18 @@ type ErrStatusCode struct {
 	// ErrorCode is a unique identifier of this error scenario extracted, if set, from the
 	// API's response
 	ErrorCode string
+	// Issues are the individual problems that caused the error, such as invalid parameter values,
+	// extracted, if set, from the API's response.
+	Issues []Issue
+}
+
+// Issue describes a single constraint that a parameter value violated.
+type Issue struct {
+	// Param is the slug of the parameter.
+	Param string `json:"param"`
+	// Constraint is the violated constraint, e.g. "required" or "regex".
+	Constraint string `json:"constraint"`
+	Message    string `json:"message"`
 }
 
 func (e ErrStatusCode) Error() string {
@@ -24,8 +36,9 @@ func (e ErrStatusCode) Error() string {
 }
 
 type ErrorResponse struct {
-	Error string `json:"error"`
-	Code  string `json:"code"`
+	Error  string  `json:"error"`
+	Code   string  `json:"code"`
+	Issues []Issue `json:"issues,omitempty"`
 }
 
 func NewErrStatusCodeFromResponse(resp *http.Response) error {
@@ -49,6 +62,7 @@ func NewErrStatusCodeFromResponse(resp *http.Response) error {
 		}
 		errsc.Msg = e.Error
 		errsc.ErrorCode = e.Code
+		errsc.Issues = e.Issues
 	} else {
 		errsc.Msg = string(body)
 	}
@@ -67,6 +81,14 @@ func NewErrBadRequest(msg string, args ...any) ErrStatusCode {
 	return newErrStatusCode(http.StatusBadRequest, msg, args...)
 }
 
+// NewErrInvalidParams returns a bad request error for parameter values that violate the given
+// constraints.
+func NewErrInvalidParams(issues []Issue, msg string, args ...any) ErrStatusCode {
+	err := newErrStatusCode(http.StatusBadRequest, msg, args...)
+	err.Issues = issues
+	return err
+}
+
 func NewErrNotFound(msg string, args ...any) ErrStatusCode {
 	return newErrStatusCode(http.StatusNotFound, msg, args...)
 }
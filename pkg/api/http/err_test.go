@@ -1,7 +1,9 @@
 package http
 
 import (
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -15,3 +17,21 @@ func TestErrStatusCode(t *testing.T) {
 		Msg:        "no task found",
 	}.Error())
 }
+
+func TestNewErrStatusCodeFromResponse(t *testing.T) {
+	require := require.New(t)
+
+	resp := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body: io.NopCloser(strings.NewReader(
+			`{"error":"invalid parameters","issues":[{"param":"name","constraint":"required","message":"a value is required"}]}`,
+		)),
+	}
+	err := NewErrStatusCodeFromResponse(resp)
+	require.Equal(ErrStatusCode{
+		StatusCode: http.StatusBadRequest,
+		Msg:        "invalid parameters",
+		Issues:     []Issue{{Param: "name", Constraint: "required", Message: "a value is required"}},
+	}, err)
+}
@@ -22,6 +22,15 @@ func (mc *MockClient) GetTask(ctx context.Context, req api.GetTaskRequest) (res
 	return task, nil
 }
 
+func (mc *MockClient) GetTaskByID(ctx context.Context, id string) (res api.Task, err error) {
+	for _, task := range mc.Tasks {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+	return api.Task{}, &api.TaskMissingError{AppURL: "api/"}
+}
+
 func (mc *MockClient) GetTaskMetadata(ctx context.Context, slug string) (res api.TaskMetadata, err error) {
 	task, ok := mc.Tasks[slug]
 	if !ok {
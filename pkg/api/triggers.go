@@ -9,6 +9,7 @@ import (
 )
 
 type Trigger struct {
+	ID          string            `json:"triggerID"`
 	Name        string            `json:"name"`
 	Description string            `json:"description"`
 	Slug        *string           `json:"slug"`
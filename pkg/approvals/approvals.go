@@ -0,0 +1,152 @@
+// approvals requests approvals from outside of a task, f.e. to gate a shell pipeline on a human
+// approval. Approvals are prompts without parameters: they're approved when the prompt is
+// submitted and denied when it's cancelled.
+package approvals
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrDenied is returned by Wait when an approval request is denied.
+	ErrDenied = errors.New("approval request denied")
+	// ErrTimeout is returned by Wait when an approval request isn't answered in time.
+	ErrTimeout = errors.New("timed out waiting for approval")
+)
+
+// Request describes an approval request.
+type Request struct {
+	// RunID is the run that the approval request belongs to. Prompts can only be created for runs,
+	// so this is the run of the script that requests the approval, f.e. AIRPLANE_RUN_ID.
+	RunID   string
+	Message string
+	// Approvers are the users and groups that can respond to the request, as "user:<email>" or
+	// "group:<slug>". If empty, anyone with access to the team can respond.
+	Approvers         []string
+	AllowSelfApproval bool
+}
+
+// Create creates an approval request and returns its ID.
+func Create(ctx context.Context, client api.APIClient, req Request) (string, error) {
+	if strings.TrimSpace(req.Message) == "" {
+		return "", errors.New("expected a message")
+	}
+	if req.RunID == "" {
+		return "", errors.New("expected a run ID")
+	}
+	reviewers, err := ParseApprovers(req.Approvers)
+	if err != nil {
+		return "", err
+	}
+	reviewers.AllowSelfApprovals = pointers.Bool(req.AllowSelfApproval)
+
+	resp, err := client.CreatePrompt(ctx, libapi.Prompt{
+		RunID:       req.RunID,
+		Schema:      libapi.Parameters{},
+		Values:      map[string]interface{}{},
+		Reviewers:   reviewers,
+		Description: req.Message,
+		ConfirmText: "Approve",
+		CancelText:  "Deny",
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "creating approval request")
+	}
+	return resp.ID, nil
+}
+
+// ParseApprovers parses approvers of the form "user:<email>" or "group:<slug>".
+func ParseApprovers(approvers []string) (*libapi.PromptReviewers, error) {
+	reviewers := &libapi.PromptReviewers{Groups: []string{}, Users: []string{}}
+	for _, a := range approvers {
+		kind, name, ok := strings.Cut(a, ":")
+		if !ok || name == "" {
+			return nil, errors.Errorf("invalid approver %q: expected user:<email> or group:<slug>", a)
+		}
+		switch kind {
+		case "user":
+			reviewers.Users = append(reviewers.Users, name)
+		case "group":
+			reviewers.Groups = append(reviewers.Groups, name)
+		default:
+			return nil, errors.Errorf("invalid approver %q: expected user:<email> or group:<slug>", a)
+		}
+	}
+	return reviewers, nil
+}
+
+// Wait polls an approval request until it's approved or denied. It returns ErrDenied if it was
+// denied, and ErrTimeout if there was no response within timeout. A timeout of zero waits
+// indefinitely.
+//
+// If the request times out or ctx is cancelled, the request is cancelled, so that approvers can't
+// respond to it after nothing waits for the response anymore.
+func Wait(ctx context.Context, client api.APIClient, id string, timeout, pollInterval time.Duration) (libapi.Prompt, error) {
+	parent := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	// timedOut cancels the request and returns why ctx is done.
+	timedOut := func() error {
+		closePrompt(client, id)
+		if parent.Err() == nil && ctx.Err() == context.DeadlineExceeded {
+			return errors.Wrapf(ErrTimeout, "no response after %s", timeout)
+		}
+		return ctx.Err()
+	}
+
+	var prompt libapi.Prompt
+	for {
+		resp, err := client.GetPrompt(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return prompt, timedOut()
+			}
+			return prompt, errors.Wrap(err, "getting approval request")
+		}
+		prompt = resp.Prompt
+		if prompt.SubmittedAt != nil {
+			return prompt, nil
+		}
+		if prompt.CancelledAt != nil {
+			if prompt.CancelledBy != nil {
+				return prompt, errors.Wrapf(ErrDenied, "denied by %s", *prompt.CancelledBy)
+			}
+			return prompt, ErrDenied
+		}
+
+		select {
+		case <-ctx.Done():
+			return prompt, timedOut()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// closePrompt cancels the approval request id, if it hasn't been responded to yet. Wait's context
+// is done by then, so a new one is used.
+func closePrompt(client api.APIClient, id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := client.GetPrompt(ctx, id)
+	if err != nil {
+		logger.Warning("Unable to cancel approval request %s: %v", id, err)
+		return
+	}
+	if resp.Prompt.SubmittedAt != nil || resp.Prompt.CancelledAt != nil {
+		return
+	}
+	if err := client.CancelPrompt(ctx, api.CancelPromptRequest{ID: id, RunID: resp.Prompt.RunID}); err != nil {
+		logger.Warning("Unable to cancel approval request %s: %v", id, err)
+	}
+}
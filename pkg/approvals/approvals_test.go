@@ -0,0 +1,113 @@
+package approvals
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// respondingClient responds to a prompt after it has been polled a few times.
+type respondingClient struct {
+	*api.MockClient
+	polls   int
+	respond func(p *libapi.Prompt)
+}
+
+func (c *respondingClient) GetPrompt(ctx context.Context, id string) (api.GetPromptResponse, error) {
+	c.polls++
+	if c.polls == 3 {
+		p := c.Prompts[id]
+		c.respond(&p)
+		c.Prompts[id] = p
+	}
+	return c.MockClient.GetPrompt(ctx, id)
+}
+
+func TestParseApprovers(t *testing.T) {
+	require := require.New(t)
+
+	reviewers, err := ParseApprovers([]string{"group:oncall", "user:alice@example.com", "group:security"})
+	require.NoError(err)
+	require.Equal([]string{"oncall", "security"}, reviewers.Groups)
+	require.Equal([]string{"alice@example.com"}, reviewers.Users)
+
+	for _, a := range []string{"oncall", "team:oncall", "group:"} {
+		_, err := ParseApprovers([]string{a})
+		require.Error(err, a)
+	}
+}
+
+func TestRequest(t *testing.T) {
+	ctx := context.Background()
+	create := func(t *testing.T, client *respondingClient) string {
+		id, err := Create(ctx, client, Request{
+			RunID:     "run123",
+			Message:   "Deploy to production?",
+			Approvers: []string{"group:oncall"},
+		})
+		require.NoError(t, err)
+		prompt := client.Prompts[id]
+		require.Equal(t, "run123", prompt.RunID)
+		require.Equal(t, "Deploy to production?", prompt.Description)
+		require.Equal(t, []string{"oncall"}, prompt.Reviewers.Groups)
+		require.False(t, *prompt.Reviewers.AllowSelfApprovals)
+		return id
+	}
+
+	t.Run("approved", func(t *testing.T) {
+		require := require.New(t)
+		client := &respondingClient{MockClient: &api.MockClient{}, respond: func(p *libapi.Prompt) {
+			p.SubmittedAt = pointers.Time(time.Now())
+			p.SubmittedBy = pointers.String("usr_alice")
+		}}
+		id := create(t, client)
+		prompt, err := Wait(ctx, client, id, time.Minute, time.Millisecond)
+		require.NoError(err)
+		require.Equal("usr_alice", *prompt.SubmittedBy)
+		require.Equal(3, client.polls)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		require := require.New(t)
+		client := &respondingClient{MockClient: &api.MockClient{}, respond: func(p *libapi.Prompt) {
+			p.CancelledAt = pointers.Time(time.Now())
+			p.CancelledBy = pointers.String("usr_bob")
+		}}
+		id := create(t, client)
+		_, err := Wait(ctx, client, id, time.Minute, time.Millisecond)
+		require.True(errors.Is(err, ErrDenied), "expected a denial, got %v", err)
+		require.ErrorContains(err, "usr_bob")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		require := require.New(t)
+		client := &respondingClient{MockClient: &api.MockClient{}, respond: func(p *libapi.Prompt) {}}
+		id := create(t, client)
+		_, err := Wait(ctx, client, id, 20*time.Millisecond, time.Millisecond)
+		require.True(errors.Is(err, ErrTimeout), "expected a timeout, got %v", err)
+		require.NotNil(client.Prompts[id].CancelledAt, "expected the request to be cancelled")
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		require := require.New(t)
+		client := &respondingClient{MockClient: &api.MockClient{}, respond: func(p *libapi.Prompt) {}}
+		id := create(t, client)
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, err := Wait(ctx, client, id, 0, time.Millisecond)
+		require.True(errors.Is(err, context.DeadlineExceeded), "expected the context's error, got %v", err)
+		require.False(errors.Is(err, ErrTimeout))
+		require.NotNil(client.Prompts[id].CancelledAt, "expected the request to be cancelled")
+	})
+}
+
+func TestCreateRequiresRunID(t *testing.T) {
+	_, err := Create(context.Background(), &api.MockClient{}, Request{Message: "Deploy to production?"})
+	require.EqualError(t, err, "expected a run ID")
+}
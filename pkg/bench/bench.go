@@ -0,0 +1,253 @@
+// Package bench measures the wall time and allocations of CLI operations, and compares them
+// against stored baselines.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// Result summarizes the measurements of an operation. Each metric is the median across
+// iterations, so that a single slow iteration (e.g. a cold cache) doesn't skew the result.
+type Result struct {
+	Name       string        `json:"name"`
+	Iterations int           `json:"iterations"`
+	Duration   time.Duration `json:"durationNs"`
+	// Allocs is the number of heap allocations.
+	Allocs uint64 `json:"allocs"`
+	// Bytes is the number of bytes allocated on the heap.
+	Bytes uint64 `json:"bytes"`
+}
+
+// Measure runs fn iterations times and returns the median of its measurements.
+func Measure(ctx context.Context, name string, iterations int, fn func(ctx context.Context) error) (Result, error) {
+	if iterations < 1 {
+		return Result{}, errors.Errorf("expected at least 1 iteration, got %d", iterations)
+	}
+
+	durations := make([]time.Duration, iterations)
+	allocs := make([]uint64, iterations)
+	bytes := make([]uint64, iterations)
+	for i := 0; i < iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		if err := fn(ctx); err != nil {
+			return Result{}, errors.Wrapf(err, "iteration %d", i+1)
+		}
+		durations[i] = time.Since(start)
+		runtime.ReadMemStats(&after)
+		allocs[i] = after.Mallocs - before.Mallocs
+		bytes[i] = after.TotalAlloc - before.TotalAlloc
+	}
+
+	return Result{
+		Name:       name,
+		Iterations: iterations,
+		Duration:   median(durations),
+		Allocs:     median(allocs),
+		Bytes:      median(bytes),
+	}, nil
+}
+
+func median[T time.Duration | uint64](values []T) T {
+	sorted := append([]T(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[len(sorted)/2]
+}
+
+// Regression is a metric that exceeded its budget.
+type Regression struct {
+	Metric   string  `json:"metric"`
+	Baseline uint64  `json:"baseline"`
+	Current  uint64  `json:"current"`
+	Change   float64 `json:"change"`
+}
+
+type metric struct {
+	name              string
+	baseline, current uint64
+}
+
+func metrics(baseline, current Result) []metric {
+	return []metric{
+		{"duration", uint64(baseline.Duration), uint64(current.Duration)},
+		{"allocs", baseline.Allocs, current.Allocs},
+		{"bytes", baseline.Bytes, current.Bytes},
+	}
+}
+
+// change returns the relative increase of the metric, or false if there is no baseline value
+// to compare against.
+func (m metric) change() (float64, bool) {
+	if m.baseline == 0 {
+		return 0, false
+	}
+	return float64(m.current)/float64(m.baseline) - 1, true
+}
+
+// Compare returns the metrics of current that increased over baseline by more than budget,
+// a fraction (e.g. 0.2 allows a 20% increase).
+func Compare(baseline, current Result, budget float64) []Regression {
+	var regressions []Regression
+	for _, m := range metrics(baseline, current) {
+		if change, ok := m.change(); ok && change > budget {
+			regressions = append(regressions, Regression{
+				Metric:   m.name,
+				Baseline: m.baseline,
+				Current:  m.current,
+				Change:   change,
+			})
+		}
+	}
+	return regressions
+}
+
+// Baselines are stored results, keyed by name.
+type Baselines map[string]Result
+
+// ReadBaselines reads baselines from a JSON file. If the file doesn't exist, no baselines are
+// returned.
+func ReadBaselines(path string) (Baselines, error) {
+	buf, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Baselines{}, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "reading baselines")
+	}
+	baselines := Baselines{}
+	if err := json.Unmarshal(buf, &baselines); err != nil {
+		return nil, errors.Wrapf(err, "parsing baselines in %s", path)
+	}
+	return baselines, nil
+}
+
+// WriteBaselines writes baselines to a JSON file.
+func WriteBaselines(path string, baselines Baselines) error {
+	buf, err := json.MarshalIndent(baselines, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshaling baselines")
+	}
+	if err := os.WriteFile(path, append(buf, '\n'), 0644); err != nil {
+		return errors.Wrap(err, "writing baselines")
+	}
+	return nil
+}
+
+// Options configure how an operation is benchmarked.
+type Options struct {
+	Iterations int
+	// BaselinePath is the file that baselines are read from and saved to.
+	BaselinePath string
+	// SaveBaseline stores the result as the new baseline for the operation.
+	SaveBaseline bool
+	// Budget is the maximum allowed increase of each metric over its baseline, in percent.
+	Budget float64
+	// FailOnRegression returns an error if any metric exceeds its budget.
+	FailOnRegression bool
+}
+
+// DefaultBaselinePath is the file that baselines are stored in by default, relative to the
+// current directory.
+const DefaultBaselinePath = "airplane.bench.json"
+
+// AddFlags registers flags for opts on fs.
+func (opts *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVarP(&opts.Iterations, "iterations", "n", 5, "Number of times to run the command.")
+	fs.StringVar(&opts.BaselinePath, "baseline", DefaultBaselinePath, "File that baselines are read from and saved to.")
+	fs.BoolVar(&opts.SaveBaseline, "save", false, "Save the result as the new baseline.")
+	fs.Float64Var(&opts.Budget, "budget", 20, "Maximum allowed regression over the baseline, in percent.")
+	fs.BoolVar(&opts.FailOnRegression, "fail", false, "Exit with an error if the command regressed beyond the budget.")
+}
+
+// Report is the outcome of benchmarking an operation.
+type Report struct {
+	Result      Result       `json:"result"`
+	Baseline    *Result      `json:"baseline,omitempty"`
+	Regressions []Regression `json:"regressions"`
+}
+
+// Run measures fn, compares its result to the stored baseline for name and, if requested,
+// saves the result as the new baseline.
+func Run(ctx context.Context, name string, opts Options, fn func(ctx context.Context) error) (Report, error) {
+	baselines, err := ReadBaselines(opts.BaselinePath)
+	if err != nil {
+		return Report{}, err
+	}
+
+	result, err := Measure(ctx, name, opts.Iterations, fn)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Result: result, Regressions: []Regression{}}
+	if baseline, ok := baselines[name]; ok {
+		report.Baseline = &baseline
+		if regressions := Compare(baseline, result, opts.Budget/100); len(regressions) > 0 {
+			report.Regressions = regressions
+		}
+	}
+
+	if opts.SaveBaseline {
+		baselines[name] = result
+		if err := WriteBaselines(opts.BaselinePath, baselines); err != nil {
+			return Report{}, err
+		}
+	}
+
+	return report, nil
+}
+
+// Print prints the report, and returns an error if opts.FailOnRegression is set and the
+// report contains regressions.
+func (r Report) Print(opts Options) error {
+	print.Print(r, func() {
+		logger.Log("%s (%d iterations, median)", logger.Bold(r.Result.Name), r.Result.Iterations)
+		logger.Log("  time:   %s%s", r.Result.Duration.Round(time.Microsecond), r.change("duration"))
+		logger.Log("  allocs: %d%s", r.Result.Allocs, r.change("allocs"))
+		logger.Log("  bytes:  %d%s", r.Result.Bytes, r.change("bytes"))
+		if r.Baseline == nil {
+			logger.Log("")
+			logger.Log("No baseline in %s. Run with --save to store one.", opts.BaselinePath)
+		}
+		for _, reg := range r.Regressions {
+			logger.Warning("%s regressed by %.1f%%, over the budget of %.1f%%", reg.Metric, reg.Change*100, opts.Budget)
+		}
+		if opts.SaveBaseline {
+			logger.Log("")
+			logger.Log("Saved baseline to %s.", opts.BaselinePath)
+		}
+	})
+
+	if opts.FailOnRegression && len(r.Regressions) > 0 {
+		return errors.Errorf("%s regressed beyond the budget of %.1f percent", r.Result.Name, opts.Budget)
+	}
+	return nil
+}
+
+func (r Report) change(name string) string {
+	if r.Baseline == nil {
+		return ""
+	}
+	for _, m := range metrics(*r.Baseline, r.Result) {
+		if change, ok := m.change(); ok && m.name == name {
+			return fmt.Sprintf(" (%+.1f%% vs baseline)", change*100)
+		}
+	}
+	return ""
+}
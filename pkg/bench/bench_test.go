@@ -0,0 +1,77 @@
+package bench
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasure(t *testing.T) {
+	require := require.New(t)
+
+	var calls int
+	result, err := Measure(context.Background(), "op", 3, func(ctx context.Context) error {
+		calls++
+		_ = make([]byte, 1<<20)
+		return nil
+	})
+	require.NoError(err)
+	require.Equal(3, calls)
+	require.Equal("op", result.Name)
+	require.Equal(3, result.Iterations)
+	require.GreaterOrEqual(result.Bytes, uint64(1<<20))
+
+	_, err = Measure(context.Background(), "op", 0, func(ctx context.Context) error { return nil })
+	require.Error(err)
+}
+
+func TestCompare(t *testing.T) {
+	require := require.New(t)
+
+	baseline := Result{Duration: 100 * time.Millisecond, Allocs: 1000, Bytes: 0}
+	current := Result{Duration: 150 * time.Millisecond, Allocs: 1100, Bytes: 500}
+
+	regressions := Compare(baseline, current, 0.2)
+	require.Len(regressions, 1)
+	require.Equal("duration", regressions[0].Metric)
+	require.InDelta(0.5, regressions[0].Change, 0.001)
+
+	require.Empty(Compare(baseline, current, 0.5))
+}
+
+func TestRun(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	opts := Options{
+		Iterations:   1,
+		BaselinePath: filepath.Join(t.TempDir(), "bench.json"),
+		SaveBaseline: true,
+		Budget:       20,
+	}
+	noop := func(ctx context.Context) error { return nil }
+
+	report, err := Run(ctx, "op", opts, noop)
+	require.NoError(err)
+	require.Nil(report.Baseline)
+
+	baselines, err := ReadBaselines(opts.BaselinePath)
+	require.NoError(err)
+	require.Equal(report.Result, baselines["op"])
+
+	// Regressions are reported against the saved baseline.
+	baselines["op"] = Result{Name: "op", Iterations: 1, Duration: time.Nanosecond, Allocs: 1}
+	require.NoError(WriteBaselines(opts.BaselinePath, baselines))
+	opts.SaveBaseline = false
+	report, err = Run(ctx, "op", opts, func(ctx context.Context) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	require.NoError(err)
+	require.NotNil(report.Baseline)
+	require.NotEmpty(report.Regressions)
+	require.Equal("duration", report.Regressions[0].Metric)
+}
@@ -7,12 +7,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"unicode"
 
+	"github.com/airplanedev/cli/pkg/build/buildlog"
+	"github.com/airplanedev/cli/pkg/build/deno"
+	"github.com/airplanedev/cli/pkg/build/dockerfile"
+	"github.com/airplanedev/cli/pkg/build/golang"
 	"github.com/airplanedev/cli/pkg/build/ignore"
 	"github.com/airplanedev/cli/pkg/build/node"
 	"github.com/airplanedev/cli/pkg/build/python"
+	"github.com/airplanedev/cli/pkg/build/ruby"
 	"github.com/airplanedev/cli/pkg/build/shell"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/build/utils"
@@ -42,6 +48,13 @@ type Response struct {
 	ImageURL string
 	// Optional, only if applicable
 	BuildID string
+	// CacheImageURL is the image that the layer cache was exported to, if any. It must be pushed
+	// for later builds to use the cache.
+	CacheImageURL string
+	// Timings is how long each step of the build took. It's only recorded by bundle builds.
+	Timings []buildlog.Timing
+	// Pushed is whether the build already pushed its images, as multi-platform builds do.
+	Pushed bool
 }
 
 // Host returns the registry hostname.
@@ -76,6 +89,14 @@ type LocalConfig struct {
 
 	// BuildArgs is a map of build-time environment variables to use.
 	BuildArgs map[string]string
+
+	// BuildSecrets are the values of the build secrets of the task, keyed by ID. See
+	// buildtypes.KindOptionBuildSecrets.
+	BuildSecrets map[string]string
+
+	// OfflineCache is a directory with an npm cache that the dependencies of shims are installed
+	// from, for build environments without registry access. See node.PopulateOfflineCache.
+	OfflineCache string
 }
 
 type DockerfileConfig struct {
@@ -87,12 +108,14 @@ type DockerfileConfig struct {
 
 // Builder implements an image builder.
 type Builder struct {
-	root     string
-	name     string
-	options  buildtypes.KindOptions
-	auth     *RegistryAuth
-	buildEnv map[string]string
-	client   *client.Client
+	root         string
+	name         string
+	options      buildtypes.KindOptions
+	auth         *RegistryAuth
+	buildEnv     map[string]string
+	buildSecrets map[string]string
+	offlineCache string
+	client       *client.Client
 }
 
 // New returns a new local builder with c.
@@ -118,12 +141,14 @@ func New(c LocalConfig) (*Builder, *client.Client, error) {
 	}
 
 	return &Builder{
-		root:     c.Root,
-		name:     c.Builder,
-		options:  c.Options,
-		auth:     c.Auth,
-		buildEnv: c.BuildArgs,
-		client:   client,
+		root:         c.Root,
+		name:         c.Builder,
+		options:      c.Options,
+		auth:         c.Auth,
+		buildEnv:     c.BuildArgs,
+		buildSecrets: c.BuildSecrets,
+		offlineCache: c.OfflineCache,
+		client:       client,
 	}, client, nil
 }
 
@@ -159,13 +184,14 @@ func (b *Builder) Build(ctx context.Context, taskID, version string) (*Response,
 	defer tree.Close()
 
 	var buildEnvKeys []string
-	for k := range b.buildEnv {
+	for k := range b.buildArgs() {
 		buildEnvKeys = append(buildEnvKeys, k)
 	}
+	sort.Strings(buildEnvKeys)
 	dockerfile, err := BuildDockerfile(DockerfileConfig{
 		Builder:      b.name,
 		Root:         b.root,
-		Options:      b.options,
+		Options:      offlineKindOptions(b.options, b.offlineCache),
 		BuildArgKeys: buildEnvKeys,
 	})
 	if err != nil {
@@ -183,26 +209,54 @@ func (b *Builder) Build(ctx context.Context, taskID, version string) (*Response,
 	if err := tree.Copy(b.root); err != nil {
 		return nil, err
 	}
+	if err := addOfflineCache(tree, b.offlineCache); err != nil {
+		return nil, err
+	}
 
-	bc, err := tree.Archive()
+	platforms, err := buildtypes.BuildPlatforms(b.options)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := b.secrets()
 	if err != nil {
 		return nil, err
 	}
-	defer bc.Close()
 
-	buildArgs := make(map[string]*string)
-	for k, v := range b.buildEnv {
-		value := v
-		buildArgs[k] = &value
+	bc, err := tree.Archive()
+	if err != nil {
+		return nil, err
 	}
+	defer bc.Close()
 
 	opts := types.ImageBuildOptions{
 		Dockerfile:  dockerfilePath,
 		Tags:        []string{uri},
-		BuildArgs:   buildArgs,
-		Platform:    "linux/amd64",
+		BuildArgs:   b.buildArgs(),
+		Platform:    platforms[0],
 		AuthConfigs: b.authconfigs(),
 	}
+	if b.offlineCache != "" {
+		// The vendored npm cache is mounted into the build, which requires BuildKit.
+		opts.Version = types.BuilderBuildKit
+	}
+
+	if len(platforms) > 1 || len(secrets) > 0 {
+		push := len(platforms) > 1
+		if err := buildx(ctx, bc, buildxOptions{
+			ImageBuildOptions: opts,
+			Platforms:         platforms,
+			Secrets:           secrets,
+			Push:              push,
+			Auth:              b.auth,
+			Output:            os.Stderr,
+		}); err != nil {
+			return nil, err
+		}
+		return &Response{
+			ImageURL: uri,
+			Pushed:   push,
+		}, nil
+	}
 
 	resp, err := b.client.ImageBuild(ctx, bc, opts)
 	if err != nil {
@@ -230,6 +284,59 @@ func (b *Builder) Build(ctx context.Context, taskID, version string) (*Response,
 	}, nil
 }
 
+// buildArgs returns the build args of the build: the build env, plus the build args of the task.
+func (b *Builder) buildArgs() map[string]*string {
+	buildArgs := make(map[string]*string)
+	for k, v := range b.buildEnv {
+		value := v
+		buildArgs[k] = &value
+	}
+	for k, v := range dockerfile.BuildArgs(b.options) {
+		value := v
+		buildArgs[k] = &value
+	}
+	return buildArgs
+}
+
+// secrets returns the values of the build secrets of the task, which must all be set.
+func (b *Builder) secrets() (map[string]string, error) {
+	ids := buildtypes.BuildSecrets(b.options)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	secrets := make(map[string]string, len(ids))
+	for _, id := range ids {
+		v, ok := b.buildSecrets[id]
+		if !ok {
+			return nil, errors.Errorf("build secret %s is not set", id)
+		}
+		secrets[id] = v
+	}
+	return secrets, nil
+}
+
+// ResolveBuildSecrets reads the values of build secrets from env vars. By default, the value of a
+// secret is read from the env var of the same name; sources maps secret IDs to the env vars to read
+// them from instead.
+func ResolveBuildSecrets(ids []string, sources map[string]string) (map[string]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	secrets := make(map[string]string, len(ids))
+	for _, id := range ids {
+		envVar := id
+		if source, ok := sources[id]; ok {
+			envVar = source
+		}
+		v, ok := os.LookupEnv(envVar)
+		if !ok {
+			return nil, errors.Errorf("build secret %s is not set: expected a value in env var %s", id, envVar)
+		}
+		secrets[id] = v
+	}
+	return secrets, nil
+}
+
 // Push pushes the given image.
 func (b *Builder) Push(ctx context.Context, uri string) error {
 	if b.auth == nil {
@@ -306,7 +413,7 @@ func SanitizeID(s string) string {
 
 func NeedsBuilding(kind buildtypes.TaskKind) (bool, error) {
 	switch buildtypes.Name(kind) {
-	case buildtypes.NamePython, buildtypes.NameNode, buildtypes.NameShell:
+	case buildtypes.NamePython, buildtypes.NameNode, buildtypes.NameShell, buildtypes.NameDeno, buildtypes.NameGo, buildtypes.NameRuby, buildtypes.NameDockerfile:
 		return true, nil
 	case buildtypes.NameImage, buildtypes.NameSQL, buildtypes.NameREST, buildtypes.NameBuiltin:
 		return false, nil
@@ -323,6 +430,14 @@ func BuildDockerfile(c DockerfileConfig) (string, error) {
 		return node.Node(c.Root, c.Options, c.BuildArgKeys)
 	case buildtypes.NameShell:
 		return shell.Shell(c.Root, c.Options)
+	case buildtypes.NameDeno:
+		return deno.Deno(c.Root, c.Options, c.BuildArgKeys)
+	case buildtypes.NameGo:
+		return golang.Go(c.Root, c.Options, c.BuildArgKeys)
+	case buildtypes.NameRuby:
+		return ruby.Ruby(c.Root, c.Options, c.BuildArgKeys)
+	case buildtypes.NameDockerfile:
+		return dockerfile.Dockerfile(c.Root, c.Options)
 	case buildtypes.NameView:
 		return views.View(c.Root, c.Options)
 	default:
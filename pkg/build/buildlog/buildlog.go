@@ -0,0 +1,264 @@
+// Package buildlog turns the BuildKit progress of an image build into readable logs, attributing
+// each vertex (roughly, each Dockerfile instruction) and its output to a named step of the build,
+// and records how long each step took.
+package buildlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+)
+
+// Step is a phase of a build.
+type Step string
+
+const (
+	// StepInstall installs system packages and the dependencies of the code being built.
+	StepInstall Step = "install"
+	// StepEsbuild bundles the code being built, with esbuild or, for views, vite.
+	StepEsbuild Step = "esbuild"
+	// StepDiscovery discovers the tasks and views defined in code.
+	StepDiscovery Step = "discovery"
+	// StepExport exports the built image.
+	StepExport Step = "export"
+	// StepOther is everything else, e.g. copying files into the image.
+	StepOther Step = "other"
+)
+
+// Steps lists every step in the order that they're reported in.
+var Steps = []Step{StepInstall, StepEsbuild, StepDiscovery, StepExport, StepOther}
+
+var installRegexp = regexp.MustCompile(`\b(npm (install|ci)|yarn install|pnpm install|pip3? install|bundle install|apt-get|apk add|go mod download)\b|_(pre|post)install\.sh`)
+
+// Classify returns the step that a vertex belongs to, based on its name, e.g.
+// "[stage-0 5/9] RUN npm ci".
+func Classify(name string) Step {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "inlineparser") || strings.Contains(lower, "discovery.json"):
+		return StepDiscovery
+	case installRegexp.MatchString(lower):
+		return StepInstall
+	case strings.Contains(lower, "esbuild") || strings.Contains(lower, "workflow-bundler") || strings.Contains(lower, "vite build"):
+		return StepEsbuild
+	case strings.HasPrefix(lower, "exporting ") || strings.HasPrefix(lower, "writing image") || strings.HasPrefix(lower, "naming to"):
+		return StepExport
+	default:
+		return StepOther
+	}
+}
+
+// Timing is how long a step of a build took.
+type Timing struct {
+	Step Step `json:"step"`
+	// Duration is the wall time that any vertex of the step was running. Vertexes that ran
+	// concurrently are only counted once.
+	Duration time.Duration `json:"duration"`
+	// Vertexes is the number of vertexes in the step, and Cached how many of them were cached.
+	Vertexes int `json:"vertexes"`
+	Cached   int `json:"cached"`
+}
+
+type vertex struct {
+	index     int
+	name      string
+	step      Step
+	cached    bool
+	started   *time.Time
+	completed *time.Time
+	// printed is set once the vertex's name has been logged, and done once its outcome has.
+	printed bool
+	done    bool
+	// partial is output that hasn't been terminated by a newline yet.
+	partial []byte
+}
+
+// Recorder writes the progress of a build to a writer, in a format similar to
+// `docker build --progress=plain`, and records the timing of each step. Each vertex is
+// numbered, its name is prefixed with its step, and the lines that it writes to stdout or stderr
+// are prefixed with its number:
+//
+//	#3 [install] [stage-0 3/6] RUN npm ci
+//	#3 added 120 packages in 4s
+//	#3 DONE 4.2s
+type Recorder struct {
+	out      io.Writer
+	vertexes map[string]*vertex
+	// order is the order that vertexes were first seen in.
+	order []string
+}
+
+// NewRecorder returns a recorder that writes to out.
+func NewRecorder(out io.Writer) *Recorder {
+	return &Recorder{
+		out:      out,
+		vertexes: map[string]*vertex{},
+	}
+}
+
+// Record handles a progress update of the build.
+func (r *Recorder) Record(resp *controlapi.StatusResponse) {
+	for _, v := range resp.GetVertexes() {
+		vx := r.vertex(string(v.Digest))
+		if v.Name != "" {
+			vx.name = v.Name
+			vx.step = Classify(v.Name)
+		}
+		vx.cached = vx.cached || v.Cached
+		if v.Started != nil {
+			vx.started = v.Started
+		}
+		if v.Completed != nil {
+			vx.completed = v.Completed
+		}
+
+		if !vx.printed && (vx.started != nil || vx.cached) {
+			fmt.Fprintf(r.out, "#%d [%s] %s\n", vx.index, vx.step, vx.name)
+			vx.printed = true
+		}
+		if v.Error != "" {
+			r.flush(vx)
+			fmt.Fprintf(r.out, "#%d ERROR: %s\n", vx.index, v.Error)
+			vx.done = true
+		}
+		if vx.done || !vx.printed {
+			continue
+		}
+		switch {
+		case vx.cached:
+			fmt.Fprintf(r.out, "#%d CACHED\n", vx.index)
+			vx.done = true
+		case vx.completed != nil:
+			r.flush(vx)
+			fmt.Fprintf(r.out, "#%d DONE %s\n", vx.index, formatDuration(vx.duration()))
+			vx.done = true
+		}
+	}
+
+	for _, l := range resp.GetLogs() {
+		vx := r.vertex(string(l.Vertex))
+		buf := append(vx.partial, l.GetMsg()...)
+		for {
+			i := bytes.IndexByte(buf, '\n')
+			if i < 0 {
+				break
+			}
+			fmt.Fprintf(r.out, "#%d %s\n", vx.index, bytes.TrimRight(buf[:i], "\r"))
+			buf = buf[i+1:]
+		}
+		vx.partial = append([]byte(nil), buf...)
+	}
+}
+
+// Close writes any output that hasn't been terminated by a newline.
+func (r *Recorder) Close() {
+	for _, d := range r.order {
+		r.flush(r.vertexes[d])
+	}
+}
+
+// Timings returns how long each step of the build took, in the order of Steps. Steps without any
+// vertexes are omitted.
+func (r *Recorder) Timings() []Timing {
+	intervals := map[Step][][2]time.Time{}
+	timings := map[Step]*Timing{}
+	for _, d := range r.order {
+		vx := r.vertexes[d]
+		if vx.name == "" {
+			continue
+		}
+		t, ok := timings[vx.step]
+		if !ok {
+			t = &Timing{Step: vx.step}
+			timings[vx.step] = t
+		}
+		t.Vertexes++
+		if vx.cached {
+			t.Cached++
+			continue
+		}
+		if vx.started != nil && vx.completed != nil {
+			intervals[vx.step] = append(intervals[vx.step], [2]time.Time{*vx.started, *vx.completed})
+		}
+	}
+
+	var result []Timing
+	for _, step := range Steps {
+		t, ok := timings[step]
+		if !ok {
+			continue
+		}
+		t.Duration = union(intervals[step])
+		result = append(result, *t)
+	}
+	return result
+}
+
+func (r *Recorder) vertex(d string) *vertex {
+	vx, ok := r.vertexes[d]
+	if !ok {
+		vx = &vertex{index: len(r.order) + 1, step: StepOther}
+		r.vertexes[d] = vx
+		r.order = append(r.order, d)
+	}
+	return vx
+}
+
+func (r *Recorder) flush(vx *vertex) {
+	if len(vx.partial) > 0 {
+		fmt.Fprintf(r.out, "#%d %s\n", vx.index, bytes.TrimRight(vx.partial, "\r"))
+		vx.partial = nil
+	}
+}
+
+func (vx *vertex) duration() time.Duration {
+	if vx.started == nil || vx.completed == nil {
+		return 0
+	}
+	return vx.completed.Sub(*vx.started)
+}
+
+// union returns the total length of a set of possibly overlapping intervals.
+func union(intervals [][2]time.Time) time.Duration {
+	if len(intervals) == 0 {
+		return 0
+	}
+	sorted := append([][2]time.Time(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0].Before(sorted[j][0]) })
+
+	var total time.Duration
+	start, end := sorted[0][0], sorted[0][1]
+	for _, in := range sorted[1:] {
+		if in[0].After(end) {
+			total += end.Sub(start)
+			start, end = in[0], in[1]
+		} else if in[1].After(end) {
+			end = in[1]
+		}
+	}
+	return total + end.Sub(start)
+}
+
+// formatDuration formats durations like BuildKit does, e.g. 4.2s.
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// FormatTimings formats timings as a single line, e.g. "install 12.3s, esbuild 1.2s (cached)".
+func FormatTimings(timings []Timing) string {
+	parts := make([]string, 0, len(timings))
+	for _, t := range timings {
+		part := fmt.Sprintf("%s %s", t.Step, formatDuration(t.Duration))
+		if t.Cached == t.Vertexes {
+			part += " (cached)"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,89 @@
+package buildlog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	for name, step := range map[string]Step{
+		"[stage-0 5/9] RUN npm ci && rm -Rf /airplane/node_modules/@swc/core-linux-x64-musl":             StepInstall,
+		"[stage-0 5/9] RUN yarn install --non-interactive --frozen-lockfile && yarn cache clean":         StepInstall,
+		"[stage-0 2/9] RUN apt-get update && export DEBIAN_FRONTEND=noninteractive":                      StepInstall,
+		"[stage-0 3/9] RUN npm install -g esbuild@0.12 --unsafe-perm":                                    StepInstall,
+		"[stage-0 4/9] RUN chmod +x airplane_preinstall.sh && ./airplane_preinstall.sh":                  StepInstall,
+		"[stage-0 3/4] RUN pip install -r requirements.txt":                                              StepInstall,
+		"[stage-0 7/9] RUN node /airplane/.airplane/esbuild.js":                                          StepEsbuild,
+		"[stage-0 7/9] RUN /airplane/node_modules/.bin/vite build --outDir dist":                         StepEsbuild,
+		"[stage-0 8/9] RUN node /airplane/.airplane-build-tools/inlineParser.cjs a.airplane.ts > x.json": StepDiscovery,
+		"[stage-0 9/9] RUN echo \"$AIRPLANE_BUILD_ID\" && cat airplane-discovery.json":                   StepDiscovery,
+		"exporting to image":               StepExport,
+		"[stage-0 6/9] COPY . /airplane":   StepOther,
+		"[internal] load build definition": StepOther,
+	} {
+		require.Equal(t, step, Classify(name), name)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds float64) *time.Time {
+		t := start.Add(time.Duration(seconds * float64(time.Second)))
+		return &t
+	}
+	const copy, install, aptGet, discovery = "sha256:1", "sha256:2", "sha256:3", "sha256:4"
+
+	var out strings.Builder
+	r := NewRecorder(&out)
+	r.Record(&controlapi.StatusResponse{
+		Vertexes: []*controlapi.Vertex{
+			{Digest: copy, Name: "[stage-0 1/4] COPY . /airplane", Cached: true},
+			{Digest: aptGet, Name: "[stage-0 2/4] RUN apt-get update", Started: at(0)},
+			{Digest: install, Name: "[stage-0 3/4] RUN npm ci", Started: at(1)},
+		},
+	})
+	r.Record(&controlapi.StatusResponse{
+		Logs: []*controlapi.VertexLog{
+			{Vertex: install, Stream: 1, Msg: []byte("added 1 package\nadd")},
+			{Vertex: install, Stream: 2, Msg: []byte("ed 2 packages\r\n")},
+			{Vertex: aptGet, Stream: 1, Msg: []byte("Reading package lists...")},
+		},
+	})
+	r.Record(&controlapi.StatusResponse{
+		Vertexes: []*controlapi.Vertex{
+			// Install steps ran concurrently from 0s to 5s.
+			{Digest: aptGet, Name: "[stage-0 2/4] RUN apt-get update", Started: at(0), Completed: at(3)},
+			{Digest: install, Name: "[stage-0 3/4] RUN npm ci", Started: at(1), Completed: at(5)},
+			{Digest: discovery, Name: "[stage-0 4/4] RUN node inlineParser.cjs", Started: at(5), Completed: at(5.5), Error: "exit code: 1"},
+		},
+	})
+	r.Close()
+
+	require.Equal(strings.Join([]string{
+		"#1 [other] [stage-0 1/4] COPY . /airplane",
+		"#1 CACHED",
+		"#2 [install] [stage-0 2/4] RUN apt-get update",
+		"#3 [install] [stage-0 3/4] RUN npm ci",
+		"#3 added 1 package",
+		"#3 added 2 packages",
+		"#2 Reading package lists...",
+		"#2 DONE 3.0s",
+		"#3 DONE 4.0s",
+		"#4 [discovery] [stage-0 4/4] RUN node inlineParser.cjs",
+		"#4 ERROR: exit code: 1",
+		"",
+	}, "\n"), out.String())
+
+	require.Equal([]Timing{
+		{Step: StepInstall, Duration: 5 * time.Second, Vertexes: 2},
+		{Step: StepDiscovery, Duration: 500 * time.Millisecond, Vertexes: 1},
+		{Step: StepOther, Vertexes: 1, Cached: 1},
+	}, r.Timings())
+	require.Equal("install 5.0s, discovery 0.5s, other 0.0s (cached)", FormatTimings(r.Timings()))
+}
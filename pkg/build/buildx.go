@@ -0,0 +1,185 @@
+package build
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+)
+
+// buildxOptions configures a build that's run with the buildx CLI plugin, rather than the engine
+// API, for features that the engine API doesn't support: multi-platform images and secrets.
+type buildxOptions struct {
+	types.ImageBuildOptions
+	Platforms []string
+	// Secrets are the values of build secrets, keyed by ID.
+	Secrets map[string]string
+	// Push pushes the image as part of the build, which multi-platform images require since they
+	// can't be stored locally. Otherwise, the image is loaded into the local image store.
+	Push bool
+	// Auth is the registry that the image is pushed to. It's required if Push is set.
+	Auth *RegistryAuth
+	// OutputDir exports the file system of the built image to a local directory instead of
+	// loading or pushing the image.
+	OutputDir string
+	// CacheOnly only checks that the image builds, without loading or pushing it. Multi-platform
+	// images that aren't pushed can't be stored anywhere else.
+	CacheOnly bool
+	// Output is where the logs of the build are written.
+	Output io.Writer
+}
+
+// buildx builds an image with `docker buildx build`, with the build context read from bc.
+//
+// Multi-platform builds require a buildx builder that supports the platforms, e.g. one created
+// with `docker buildx create --use --driver docker-container`.
+func buildx(ctx context.Context, bc io.Reader, opts buildxOptions) error {
+	cmd := exec.CommandContext(ctx, "docker", buildxArgs(opts)...)
+	cmd.Stdin = bc
+	cmd.Stdout = opts.Output
+	cmd.Stderr = opts.Output
+	// Build args and secrets are passed by name and read from the environment, to keep their
+	// values out of the process list.
+	cmd.Env = os.Environ()
+	if opts.Push {
+		if opts.Auth == nil {
+			return errors.New("multi-platform builds require registry auth")
+		}
+		configDir, cleanup, err := registryConfig(*opts.Auth)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		cmd.Env = append(cmd.Env, "DOCKER_CONFIG="+configDir)
+	}
+	for k, v := range opts.BuildArgs {
+		if v != nil {
+			cmd.Env = append(cmd.Env, k+"="+*v)
+		}
+	}
+	for id, v := range opts.Secrets {
+		cmd.Env = append(cmd.Env, secretEnvVar(id)+"="+v)
+	}
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "docker buildx build")
+	}
+	return nil
+}
+
+// registryConfig creates a temporary docker CLI config directory that authenticates buildx to the
+// registry of auth. Unlike `docker login`, it doesn't store the credentials in the user's config.
+// The other settings of the user's config, and its builders and CLI plugins, are kept. The
+// returned func removes the directory.
+func registryConfig(auth RegistryAuth) (string, func(), error) {
+	userDir := os.Getenv("DOCKER_CONFIG")
+	if userDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", nil, errors.Wrap(err, "getting home directory")
+		}
+		userDir = filepath.Join(home, ".docker")
+	}
+
+	config := map[string]interface{}{}
+	if buf, err := os.ReadFile(filepath.Join(userDir, "config.json")); err == nil {
+		if err := json.Unmarshal(buf, &config); err != nil {
+			return "", nil, errors.Wrap(err, "reading docker config")
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", nil, errors.Wrap(err, "reading docker config")
+	}
+	// Credential helpers would take precedence over the credentials of the registry.
+	delete(config, "credsStore")
+	delete(config, "credHelpers")
+	config["auths"] = map[string]interface{}{
+		auth.host(): map[string]string{
+			"auth": base64.StdEncoding.EncodeToString([]byte("oauth2accesstoken:" + auth.Token)),
+		},
+	}
+	buf, err := json.Marshal(config)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "airplane-docker-config-*")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "creating docker config")
+	}
+	cleanup := func() {
+		_ = os.RemoveAll(dir)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), buf, 0600); err != nil {
+		cleanup()
+		return "", nil, errors.Wrap(err, "writing docker config")
+	}
+	for _, name := range []string{"buildx", "cli-plugins", "contexts"} {
+		if src := filepath.Join(userDir, name); fsx.Exists(src) {
+			if err := os.Symlink(src, filepath.Join(dir, name)); err != nil {
+				cleanup()
+				return "", nil, errors.Wrap(err, "linking docker config")
+			}
+		}
+	}
+	return dir, cleanup, nil
+}
+
+// buildxArgs returns the arguments of a `docker buildx build` of opts, with the build context read
+// from stdin.
+func buildxArgs(opts buildxOptions) []string {
+	args := []string{
+		"buildx", "build",
+		"--platform", strings.Join(opts.Platforms, ","),
+		"--file", opts.Dockerfile,
+	}
+	switch {
+	case opts.OutputDir != "":
+		args = append(args, "--output", "type=local,dest="+opts.OutputDir)
+	case opts.Push:
+		args = append(args, "--push")
+	case opts.CacheOnly:
+		args = append(args, "--output", "type=cacheonly")
+	default:
+		args = append(args, "--load")
+	}
+	for _, tag := range opts.Tags {
+		args = append(args, "--tag", tag)
+	}
+	for _, k := range sortedKeys(opts.BuildArgs) {
+		args = append(args, "--build-arg", k)
+	}
+	for _, id := range sortedKeys(opts.Secrets) {
+		args = append(args, "--secret", fmt.Sprintf("id=%s,env=%s", id, secretEnvVar(id)))
+	}
+	for _, k := range sortedKeys(opts.Labels) {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", k, opts.Labels[k]))
+	}
+	for _, from := range opts.CacheFrom {
+		args = append(args, "--cache-from", from)
+	}
+	return append(args, "-")
+}
+
+// secretEnvVar returns the env var that the value of a build secret is passed to buildx in, which
+// is distinct from build args of the same name.
+func secretEnvVar(id string) string {
+	return "AIRPLANE_BUILD_SECRET_" + id
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,126 @@
+package build
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildxArgs(t *testing.T) {
+	require := require.New(t)
+
+	inline := "1"
+	secret := "secret"
+	opts := types.ImageBuildOptions{
+		Dockerfile: ".airplane/Dockerfile",
+		Tags:       []string{"registry/task-abc:latest", "registry/cache:main"},
+		BuildArgs:  map[string]*string{"BUILDKIT_INLINE_CACHE": &inline, "BUILD_TOKEN": &secret},
+		Labels:     map[string]string{"dev.airplane.source": "cli"},
+		CacheFrom:  []string{"registry/cache:main"},
+	}
+	require.Equal([]string{
+		"buildx", "build",
+		"--platform", "linux/amd64,linux/arm64",
+		"--file", ".airplane/Dockerfile",
+		"--push",
+		"--tag", "registry/task-abc:latest",
+		"--tag", "registry/cache:main",
+		"--build-arg", "BUILDKIT_INLINE_CACHE",
+		"--build-arg", "BUILD_TOKEN",
+		"--label", "dev.airplane.source=cli",
+		"--cache-from", "registry/cache:main",
+		"-",
+	}, buildxArgs(buildxOptions{
+		ImageBuildOptions: opts,
+		Platforms:         []string{"linux/amd64", "linux/arm64"},
+		Push:              true,
+	}))
+
+	// Single-platform builds with secrets are loaded into the local image store.
+	require.Equal([]string{
+		"buildx", "build",
+		"--platform", "linux/amd64",
+		"--file", "Dockerfile",
+		"--load",
+		"--tag", "task-abc:latest",
+		"--secret", "id=NPM_TOKEN,env=AIRPLANE_BUILD_SECRET_NPM_TOKEN",
+		"--secret", "id=PIP_TOKEN,env=AIRPLANE_BUILD_SECRET_PIP_TOKEN",
+		"-",
+	}, buildxArgs(buildxOptions{
+		ImageBuildOptions: types.ImageBuildOptions{Dockerfile: "Dockerfile", Tags: []string{"task-abc:latest"}},
+		Platforms:         []string{"linux/amd64"},
+		Secrets:           map[string]string{"PIP_TOKEN": "b", "NPM_TOKEN": "a"},
+	}))
+
+	// Exported builds write the image's file system to a directory instead.
+	require.Equal([]string{
+		"buildx", "build",
+		"--platform", "linux/amd64",
+		"--file", "Dockerfile",
+		"--output", "type=local,dest=/tmp/out",
+		"--tag", "view-abc:latest",
+		"-",
+	}, buildxArgs(buildxOptions{
+		ImageBuildOptions: types.ImageBuildOptions{Dockerfile: "Dockerfile", Tags: []string{"view-abc:latest"}},
+		Platforms:         []string{"linux/amd64"},
+		OutputDir:         "/tmp/out",
+	}))
+
+	// Multi-platform builds that aren't pushed only check that the image builds.
+	require.Equal([]string{
+		"buildx", "build",
+		"--platform", "linux/amd64,linux/arm64",
+		"--file", "Dockerfile",
+		"--output", "type=cacheonly",
+		"--tag", "task-abc:latest",
+		"-",
+	}, buildxArgs(buildxOptions{
+		ImageBuildOptions: types.ImageBuildOptions{Dockerfile: "Dockerfile", Tags: []string{"task-abc:latest"}},
+		Platforms:         []string{"linux/amd64", "linux/arm64"},
+		CacheOnly:         true,
+	}))
+}
+
+func TestRegistryConfig(t *testing.T) {
+	require := require.New(t)
+	userDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", userDir)
+	userConfig := `{"auths": {"docker.io": {}}, "credsStore": "desktop", "currentContext": "colima"}`
+	require.NoError(os.WriteFile(filepath.Join(userDir, "config.json"), []byte(userConfig), 0600))
+	require.NoError(os.Mkdir(filepath.Join(userDir, "buildx"), 0755))
+
+	dir, cleanup, err := registryConfig(RegistryAuth{Token: "token", Repo: "us-docker.pkg.dev/airplane/tasks"})
+	require.NoError(err)
+	buf, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	require.NoError(err)
+	auth := base64.StdEncoding.EncodeToString([]byte("oauth2accesstoken:token"))
+	require.JSONEq(`{"auths": {"us-docker.pkg.dev": {"auth": "`+auth+`"}}, "currentContext": "colima"}`, string(buf))
+	target, err := os.Readlink(filepath.Join(dir, "buildx"))
+	require.NoError(err)
+	require.Equal(filepath.Join(userDir, "buildx"), target)
+
+	// The user's config is left as is.
+	buf, err = os.ReadFile(filepath.Join(userDir, "config.json"))
+	require.NoError(err)
+	require.Equal(userConfig, string(buf))
+
+	cleanup()
+	require.NoDirExists(dir)
+}
+
+func TestResolveBuildSecrets(t *testing.T) {
+	require := require.New(t)
+	t.Setenv("NPM_TOKEN", "npm")
+	t.Setenv("CI_PIP_TOKEN", "pip")
+
+	secrets, err := ResolveBuildSecrets([]string{"NPM_TOKEN", "PIP_TOKEN"}, map[string]string{"PIP_TOKEN": "CI_PIP_TOKEN"})
+	require.NoError(err)
+	require.Equal(map[string]string{"NPM_TOKEN": "npm", "PIP_TOKEN": "pip"}, secrets)
+
+	_, err = ResolveBuildSecrets([]string{"GO_TOKEN"}, nil)
+	require.EqualError(err, "build secret GO_TOKEN is not set: expected a value in env var GO_TOKEN")
+}
@@ -5,13 +5,19 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/airplanedev/cli/pkg/build/buildlog"
+	"github.com/airplanedev/cli/pkg/build/deno"
+	"github.com/airplanedev/cli/pkg/build/dockerfile"
+	"github.com/airplanedev/cli/pkg/build/golang"
 	"github.com/airplanedev/cli/pkg/build/ignore"
 	"github.com/airplanedev/cli/pkg/build/node"
 	"github.com/airplanedev/cli/pkg/build/python"
+	"github.com/airplanedev/cli/pkg/build/ruby"
 	"github.com/airplanedev/cli/pkg/build/shell"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/build/utils"
@@ -46,6 +52,14 @@ type BundleLocalConfig struct {
 	// FilesToDiscover are the target files to discover (if applicable).
 	FilesToDiscover []string
 
+	// BuildArgs are the build args of the bundle, which its Dockerfile declares with ARG.
+	BuildArgs map[string]string
+
+	// BuildSecrets are the values of the build secrets of the bundle, keyed by ID. They're
+	// mounted into the commands that install the bundle's dependencies. Builds with secrets are run
+	// with buildx, so they don't record timings.
+	BuildSecrets map[string]string
+
 	// Auth represents the registry auth to use.
 	//
 	// If nil, Push will produce an error.
@@ -53,6 +67,22 @@ type BundleLocalConfig struct {
 
 	// Target is the docker target to build.
 	Target string
+
+	// Cache configures the layer cache of the build, if any.
+	Cache CacheOptions
+
+	// OfflineCache is a directory with an npm cache that the dependencies of shims are installed
+	// from, for build environments without registry access. See node.PopulateOfflineCache.
+	OfflineCache string
+
+	// Output is where the logs of the build are written.
+	//
+	// When nil, logs are written to stderr.
+	Output io.Writer
+
+	// OutputDir, if set, is the directory that the file system of the built image is exported to,
+	// instead of storing the image. This is run with buildx, so it doesn't record timings.
+	OutputDir string
 }
 
 type BundleDockerfileConfig struct {
@@ -71,9 +101,15 @@ type BundleBuilder struct {
 	options         buildtypes.KindOptions
 	filesToBuild    []string
 	filesToDiscover []string
+	buildArgs       map[string]string
+	buildSecrets    map[string]string
 	auth            *RegistryAuth
 	client          *client.Client
 	target          string
+	cache           CacheOptions
+	offlineCache    string
+	output          io.Writer
+	outputDir       string
 }
 
 // New returns a new local builder with c.
@@ -90,6 +126,10 @@ func NewBundleBuilder(c BundleLocalConfig) (*BundleBuilder, *client.Client, erro
 		c.Options = buildtypes.KindOptions{}
 	}
 
+	if c.Output == nil {
+		c.Output = os.Stderr
+	}
+
 	client, err := client.NewClientWithOpts(
 		client.FromEnv,
 		client.WithAPIVersionNegotiation(),
@@ -104,12 +144,31 @@ func NewBundleBuilder(c BundleLocalConfig) (*BundleBuilder, *client.Client, erro
 		options:         c.Options,
 		filesToBuild:    c.FilesToBuild,
 		filesToDiscover: c.FilesToDiscover,
+		buildArgs:       c.BuildArgs,
+		buildSecrets:    c.BuildSecrets,
 		auth:            c.Auth,
 		client:          client,
 		target:          c.Target,
+		cache:           c.Cache,
+		offlineCache:    c.OfflineCache,
+		output:          c.Output,
+		outputDir:       c.OutputDir,
 	}, client, nil
 }
 
+// kindOptions returns the kind options of the build, with the IDs of its build secrets.
+func (b *BundleBuilder) kindOptions() buildtypes.KindOptions {
+	if len(b.buildSecrets) == 0 {
+		return b.options
+	}
+	options := buildtypes.KindOptions{}
+	for k, v := range b.options {
+		options[k] = v
+	}
+	options[buildtypes.KindOptionBuildSecrets] = sortedKeys(b.buildSecrets)
+	return options
+}
+
 func (b *BundleBuilder) Close() error {
 	return b.client.Close()
 }
@@ -144,7 +203,8 @@ func (b *BundleBuilder) Build(ctx context.Context, bundleBuildID, version string
 	dockerfile, err := BuildBundleDockerfile(BundleDockerfileConfig{
 		BuildContext:    b.buildContext,
 		Root:            b.root,
-		Options:         b.options,
+		Options:         offlineKindOptions(b.cache.kindOptions(b.kindOptions()), b.offlineCache),
+		BuildArgKeys:    sortedKeys(b.buildArgs),
 		FilesToBuild:    b.filesToBuild,
 		FilesToDiscover: b.filesToDiscover,
 	})
@@ -163,6 +223,9 @@ func (b *BundleBuilder) Build(ctx context.Context, bundleBuildID, version string
 	if err := tree.Copy(b.root); err != nil {
 		return nil, err
 	}
+	if err := addOfflineCache(tree, b.offlineCache); err != nil {
+		return nil, err
+	}
 
 	bc, err := tree.Archive()
 	if err != nil {
@@ -170,11 +233,15 @@ func (b *BundleBuilder) Build(ctx context.Context, bundleBuildID, version string
 	}
 	defer bc.Close()
 
+	platforms := b.buildContext.Platforms
+	if len(platforms) == 0 {
+		platforms = []string{buildtypes.DefaultBuildPlatform}
+	}
 	testBuildID := "test-build-id"
 	opts := types.ImageBuildOptions{
 		Dockerfile:  dockerfilePath,
 		Tags:        []string{uri},
-		Platform:    "linux/amd64",
+		Platform:    platforms[0],
 		AuthConfigs: b.authconfigs(),
 		Version:     types.BuilderBuildKit,
 		Target:      b.target,
@@ -182,6 +249,39 @@ func (b *BundleBuilder) Build(ctx context.Context, bundleBuildID, version string
 			"AIRPLANE_BUILD_ID": &testBuildID,
 		},
 	}
+	for k, v := range b.buildArgs {
+		value := v
+		opts.BuildArgs[k] = &value
+	}
+	if b.buildContext.Dockerfile != nil {
+		for k, v := range b.buildContext.Dockerfile.BuildArgs {
+			value := v
+			opts.BuildArgs[k] = &value
+		}
+	}
+	b.cache.apply(&opts)
+
+	if len(b.buildSecrets) > 0 || b.outputDir != "" || len(platforms) > 1 {
+		// Images of several platforms can't be loaded into the local image store.
+		cacheOnly := len(platforms) > 1 && b.outputDir == ""
+		if err := buildx(ctx, bc, buildxOptions{
+			ImageBuildOptions: opts,
+			Platforms:         platforms,
+			Secrets:           b.buildSecrets,
+			Output:            b.output,
+			OutputDir:         b.outputDir,
+			CacheOnly:         cacheOnly,
+		}); err != nil {
+			return nil, err
+		}
+		if b.outputDir != "" || cacheOnly {
+			return &Response{CacheImageURL: b.cache.To}, nil
+		}
+		return &Response{
+			ImageURL:      uri,
+			CacheImageURL: b.cache.To,
+		}, nil
+	}
 
 	resp, err := b.client.ImageBuild(ctx, bc, opts)
 	if err != nil {
@@ -189,6 +289,8 @@ func (b *BundleBuilder) Build(ctx context.Context, bundleBuildID, version string
 	}
 	defer resp.Body.Close()
 
+	recorder := buildlog.NewRecorder(b.output)
+	defer recorder.Close()
 	scanner := bufiox.NewScanner(resp.Body)
 	for scanner.Scan() {
 		var msg *dockerJSONMessage.JSONMessage
@@ -213,26 +315,16 @@ func (b *BundleBuilder) Build(ctx context.Context, bundleBuildID, version string
 		if err := (&resp).Unmarshal(dt); err != nil {
 			continue
 		}
-
-		for _, v := range resp.GetVertexes() {
-			fmt.Fprintln(os.Stderr, v.Name)
-			if v.Cached {
-				fmt.Fprintln(os.Stderr, "CACHED")
-			}
-			if v.Error != "" {
-				fmt.Fprintln(os.Stderr, v.Error)
-			}
-		}
-		for _, l := range resp.GetLogs() {
-			fmt.Fprintln(os.Stderr, string(l.GetMsg()))
-		}
+		recorder.Record(&resp)
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, errors.Wrap(err, "scanning")
 	}
 
 	return &Response{
-		ImageURL: uri,
+		ImageURL:      uri,
+		CacheImageURL: b.cache.To,
+		Timings:       recorder.Timings(),
 	}, nil
 }
 
@@ -298,6 +390,14 @@ func BuildBundleDockerfile(c BundleDockerfileConfig) (string, error) {
 		return node.NodeBundle(c.Root, c.BuildContext, c.Options, c.BuildArgKeys, c.FilesToBuild, c.FilesToDiscover)
 	case buildtypes.ShellBuildType:
 		return shell.ShellBundle(c.Root)
+	case buildtypes.DenoBuildType:
+		return deno.DenoBundle(c.Root, c.BuildContext, c.Options, c.BuildArgKeys, c.FilesToBuild)
+	case buildtypes.GoBuildType:
+		return golang.GoBundle(c.Root, c.BuildContext, c.Options, c.BuildArgKeys, c.FilesToBuild)
+	case buildtypes.RubyBuildType:
+		return ruby.RubyBundle(c.Root, c.BuildContext, c.BuildArgKeys)
+	case buildtypes.DockerfileBuildType:
+		return dockerfile.DockerfileBundle(c.Root, c.BuildContext)
 	case buildtypes.ViewBuildType:
 		return views.ViewBundle(c.Root, c.BuildContext, c.Options, c.FilesToBuild, c.FilesToDiscover)
 	case buildtypes.PythonBuildType:
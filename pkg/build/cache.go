@@ -0,0 +1,76 @@
+package build
+
+import (
+	"regexp"
+	"strings"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/docker/docker/api/types"
+)
+
+// CacheOptions configures a registry-backed layer cache, so that builds on fresh machines, e.g. in
+// CI, can reuse the layers of previous builds.
+type CacheOptions struct {
+	// From are images whose layers are reused by the build, if they match.
+	From []string
+
+	// To is the image that the build's layers are exported to. The cache is stored inline in the
+	// image, so it must be pushed after the build.
+	To string
+}
+
+// Enabled returns whether the build uses a layer cache.
+func (c CacheOptions) Enabled() bool {
+	return len(c.From) > 0 || c.To != ""
+}
+
+// apply configures a build to import and export the cache. The cache requires BuildKit, which
+// bundle builds already use, so apply doesn't change the builder of opts.
+func (c CacheOptions) apply(opts *types.ImageBuildOptions) {
+	if !c.Enabled() {
+		return
+	}
+	opts.CacheFrom = append(opts.CacheFrom, c.From...)
+	if c.To != "" {
+		opts.Tags = append(opts.Tags, c.To)
+		if opts.BuildArgs == nil {
+			opts.BuildArgs = map[string]*string{}
+		}
+		inline := "1"
+		opts.BuildArgs["BUILDKIT_INLINE_CACHE"] = &inline
+	}
+}
+
+// kindOptions returns the kind options of a build, with cache mounts enabled if the build uses a
+// layer cache.
+func (c CacheOptions) kindOptions(options buildtypes.KindOptions) buildtypes.KindOptions {
+	if !c.Enabled() {
+		return options
+	}
+	withCache := buildtypes.KindOptions{}
+	for k, v := range options {
+		withCache[k] = v
+	}
+	withCache[buildtypes.KindOptionCacheMounts] = "true"
+	return withCache
+}
+
+var invalidTagChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// CacheRef returns the cache image of a build within the image ref, so that separate builds that
+// share a ref don't overwrite each other's cache. If ref already has a tag, it's used as is.
+func CacheRef(ref, key string) string {
+	if ref == "" || strings.Contains(ref[strings.LastIndex(ref, "/")+1:], ":") {
+		return ref
+	}
+	// Tags are at most 128 characters, and can't start with a period or dash.
+	tag := invalidTagChars.ReplaceAllString(key, "-")
+	if len(tag) > 128 {
+		tag = tag[len(tag)-128:]
+	}
+	tag = strings.Trim(tag, "-.")
+	if tag == "" {
+		tag = "cache"
+	}
+	return ref + ":" + tag
+}
@@ -0,0 +1,56 @@
+package build
+
+import (
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheRef(t *testing.T) {
+	for _, test := range []struct {
+		ref, key, expected string
+	}{
+		{ref: "", key: "tasks", expected: ""},
+		{ref: "us-docker.pkg.dev/acme/cache", key: "tasks/python-python", expected: "us-docker.pkg.dev/acme/cache:tasks-python-python"},
+		{ref: "localhost:5000/cache", key: ".hidden", expected: "localhost:5000/cache:hidden"},
+		{ref: "localhost:5000/cache:latest", key: "tasks", expected: "localhost:5000/cache:latest"},
+		{ref: "cache", key: "/", expected: "cache:cache"},
+	} {
+		require.Equal(t, test.expected, CacheRef(test.ref, test.key))
+	}
+}
+
+func TestCacheOptions(t *testing.T) {
+	require := require.New(t)
+
+	var opts types.ImageBuildOptions
+	CacheOptions{}.apply(&opts)
+	require.Equal(types.ImageBuildOptions{}, opts)
+	options := buildtypes.KindOptions{"shim": "true"}
+	require.Equal(options, CacheOptions{}.kindOptions(options))
+
+	c := CacheOptions{From: []string{"registry/cache:a"}, To: "registry/cache:b"}
+	opts = types.ImageBuildOptions{Tags: []string{"registry/task:latest"}}
+	c.apply(&opts)
+	require.Empty(opts.Version)
+	require.Equal([]string{"registry/cache:a"}, opts.CacheFrom)
+	require.Equal([]string{"registry/task:latest", "registry/cache:b"}, opts.Tags)
+	require.Equal("1", *opts.BuildArgs["BUILDKIT_INLINE_CACHE"])
+
+	// The original options are left unchanged.
+	require.Equal(buildtypes.KindOptions{"shim": "true", "cacheMounts": "true"}, c.kindOptions(options))
+	require.Equal(buildtypes.KindOptions{"shim": "true"}, options)
+
+	dockerfile, err := buildtypes.BuildInstructions{
+		InstallInstructions: []buildtypes.InstallInstruction{
+			{Cmd: "pip install -r requirements.txt", CacheDir: "/root/.cache/pip"},
+			{Cmd: "echo done"},
+		},
+		CacheMounts: true,
+	}.DockerfileString()
+	require.NoError(err)
+	require.Contains(dockerfile, "RUN --mount=type=cache,target=/root/.cache/pip pip install -r requirements.txt\n")
+	require.Contains(dockerfile, "RUN echo done\n")
+}
@@ -0,0 +1,193 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LabelManaged marks Docker containers that were created by local build flows.
+	LabelManaged = "dev.airplane.managed"
+	// LabelOwner identifies the process that created a Docker container, as "<hostname>/<pid>".
+	LabelOwner = "dev.airplane.owner"
+)
+
+// Labels returns the labels to set on Docker containers created by this process, so that they
+// can be cleaned up if the process exits without removing them. Images aren't labelled, since
+// their labels are inherited by every image that's built from them.
+func Labels() map[string]string {
+	return map[string]string{
+		LabelManaged: "true",
+		LabelOwner:   owner(os.Getpid()),
+	}
+}
+
+func owner(pid int) string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s/%d", hostname, pid)
+}
+
+// builtImagePrefixes are the prefixes of the names of the images that Builder and BundleBuilder
+// tag, e.g. task-tska:latest or registry/bundle-build-bbla:latest.
+var builtImagePrefixes = []string{"task-", "bundle-build-"}
+
+// isBuiltImageTag returns whether tag was tagged by a local build.
+func isBuiltImageTag(tag string) bool {
+	name, _, ok := strings.Cut(tag, ":")
+	if !ok {
+		return false
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, prefix := range builtImagePrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DockerCleanupClient is the subset of the Docker client used to clean up Docker objects.
+type DockerCleanupClient interface {
+	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error
+	ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
+	ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
+}
+
+// CleanupOpts configure which Docker objects Cleanup removes.
+type CleanupOpts struct {
+	// All removes the containers of processes that are still running too. By default, only the
+	// containers of processes that have exited are removed.
+	All bool
+	// DryRun lists the objects that would be removed without removing them.
+	DryRun bool
+}
+
+// CleanedObject is a Docker object that was (or, in a dry run, would be) removed.
+type CleanedObject struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Size is the size of the object in bytes, if known.
+	Size int64 `json:"size,omitempty"`
+}
+
+// Cleanup removes the containers and image tags that were created by local build flows. Containers
+// are removed first, so that the images they use can be removed after them. Images are only
+// untagged, so an image that was also tagged by someone else is kept, and images that are in use
+// are skipped.
+func Cleanup(ctx context.Context, client DockerCleanupClient, opts CleanupOpts) ([]CleanedObject, error) {
+	cleaned := []CleanedObject{}
+
+	containers, err := client.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", LabelManaged+"=true")),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing containers")
+	}
+	for _, c := range containers {
+		if !opts.All && !isOrphan(c.Labels) {
+			continue
+		}
+		if !opts.DryRun {
+			if err := client.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true, RemoveVolumes: true}); err != nil {
+				return cleaned, errors.Wrapf(err, "removing container %s", c.ID)
+			}
+		}
+		var name string
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		cleaned = append(cleaned, CleanedObject{Type: "container", ID: c.ID, Name: name, Size: c.SizeRw})
+	}
+
+	images, err := client.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return cleaned, errors.Wrap(err, "listing images")
+	}
+	for _, img := range images {
+		var tags []string
+		for _, tag := range img.RepoTags {
+			if isBuiltImageTag(tag) {
+				tags = append(tags, tag)
+			}
+		}
+		for i, tag := range tags {
+			if !opts.DryRun {
+				if _, err := client.ImageRemove(ctx, tag, types.ImageRemoveOptions{PruneChildren: true}); errdefs.IsConflict(err) {
+					// The image is used by a container.
+					continue
+				} else if err != nil {
+					return cleaned, errors.Wrapf(err, "removing image %s", tag)
+				}
+			}
+			var size int64
+			// Removing the image's last tag removes the image.
+			if i == len(tags)-1 && len(tags) == len(img.RepoTags) {
+				size = img.Size
+			}
+			cleaned = append(cleaned, CleanedObject{Type: "image", ID: img.ID, Name: tag, Size: size})
+		}
+	}
+
+	return cleaned, nil
+}
+
+// CleanupLocal runs Cleanup against the Docker daemon configured by the environment.
+func CleanupLocal(ctx context.Context, opts CleanupOpts) ([]CleanedObject, error) {
+	dclient, err := client.NewClientWithOpts(
+		client.FromEnv,
+		client.WithAPIVersionNegotiation(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating docker client")
+	}
+	defer dclient.Close()
+	return Cleanup(ctx, dclient, opts)
+}
+
+// isOrphan returns whether the process that created a Docker container has exited. Containers that
+// were created on another host (e.g. through a shared Docker daemon) are never orphans, since
+// their process can't be checked.
+func isOrphan(labels map[string]string) bool {
+	hostname, pidStr, ok := strings.Cut(labels[LabelOwner], "/")
+	if !ok {
+		// Containers without a valid owner can't belong to a running process.
+		return true
+	}
+	if h, _ := os.Hostname(); h != hostname {
+		return false
+	}
+	pid, err := strconv.Atoi(pidStr)
+	if err != nil {
+		return true
+	}
+	return !processRunning(pid)
+}
+
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Windows, FindProcess fails if the process doesn't exist.
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
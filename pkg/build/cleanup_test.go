@@ -0,0 +1,111 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCleanupClient struct {
+	containers []types.Container
+	images     []types.ImageSummary
+	inUse      map[string]bool
+	removed    []string
+}
+
+var _ DockerCleanupClient = &fakeCleanupClient{}
+
+func (c *fakeCleanupClient) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return c.containers, nil
+}
+
+func (c *fakeCleanupClient) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
+	c.removed = append(c.removed, containerID)
+	return nil
+}
+
+func (c *fakeCleanupClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
+	return c.images, nil
+}
+
+func (c *fakeCleanupClient) ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	if options.Force {
+		return nil, errors.New("unexpected force removal")
+	}
+	if c.inUse[imageID] {
+		return nil, errdefs.Conflict(errors.New("image is being used by a container"))
+	}
+	c.removed = append(c.removed, imageID)
+	return nil, nil
+}
+
+func TestCleanup(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	running := Labels()
+	// PIDs are capped well below this value on every supported platform.
+	exited := map[string]string{LabelManaged: "true", LabelOwner: fmt.Sprintf("%s/%d", hostname, 1<<30)}
+
+	newClient := func() *fakeCleanupClient {
+		return &fakeCleanupClient{
+			containers: []types.Container{
+				{ID: "c_running", Labels: running},
+				{ID: "c_exited", Names: []string{"/builder"}, Labels: exited},
+			},
+			images: []types.ImageSummary{
+				{ID: "i_built", RepoTags: []string{"bundle-build-bbla:latest"}, Size: 1024},
+				{ID: "i_retagged", RepoTags: []string{"registry/task-tska:v1", "myapp:latest"}, Size: 2048},
+				{ID: "i_in_use", RepoTags: []string{"task-tskb:latest"}},
+				{ID: "i_user", RepoTags: []string{"alpine:3", "<none>:<none>"}, Labels: exited},
+			},
+			inUse: map[string]bool{"task-tskb:latest": true},
+		}
+	}
+
+	t.Run("orphans", func(t *testing.T) {
+		require := require.New(t)
+		client := newClient()
+		cleaned, err := Cleanup(context.Background(), client, CleanupOpts{})
+		require.NoError(err)
+		require.Equal([]string{"c_exited", "bundle-build-bbla:latest", "registry/task-tska:v1"}, client.removed)
+		require.Equal([]CleanedObject{
+			{Type: "container", ID: "c_exited", Name: "builder"},
+			{Type: "image", ID: "i_built", Name: "bundle-build-bbla:latest", Size: 1024},
+			{Type: "image", ID: "i_retagged", Name: "registry/task-tska:v1"},
+		}, cleaned)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		require := require.New(t)
+		client := newClient()
+		cleaned, err := Cleanup(context.Background(), client, CleanupOpts{All: true})
+		require.NoError(err)
+		require.Len(cleaned, 4)
+		require.Equal([]string{"c_running", "c_exited", "bundle-build-bbla:latest", "registry/task-tska:v1"}, client.removed)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		require := require.New(t)
+		client := newClient()
+		cleaned, err := Cleanup(context.Background(), client, CleanupOpts{DryRun: true})
+		require.NoError(err)
+		require.Len(cleaned, 4)
+		require.Empty(client.removed)
+	})
+}
+
+func TestIsBuiltImageTag(t *testing.T) {
+	require := require.New(t)
+	require.True(isBuiltImageTag("task-tska:latest"))
+	require.True(isBuiltImageTag("us-docker.pkg.dev/airplane/bundle-build-bbla:v2"))
+	require.False(isBuiltImageTag("task-:latest"))
+	require.False(isBuiltImageTag("mytask-tska:latest"))
+	require.False(isBuiltImageTag("<none>:<none>"))
+}
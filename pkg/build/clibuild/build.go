@@ -21,6 +21,9 @@ type Request struct {
 	TaskID  string
 	TaskEnv libapi.EnvVars
 	Shim    bool
+	// BuildSecretSources maps the IDs of build secrets to the env vars that local builds read
+	// their values from, if not the env var of the same name.
+	BuildSecretSources map[string]string
 }
 
 // Response represents a build response.
@@ -20,8 +20,9 @@ func BundleDiscoverer(client api.APIClient, l logger.Logger, envSlug string) *bu
 				Logger: l,
 			},
 			&discover.CodeTaskDiscoverer{
-				Client: client,
-				Logger: l,
+				Client:  client,
+				Logger:  l,
+				EnvSlug: envSlug,
 			},
 		},
 		ViewDiscoverers: []discover.ViewDiscoverer{
@@ -9,6 +9,7 @@ import (
 	"github.com/airplanedev/cli/pkg/build"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/configs"
+	"github.com/airplanedev/cli/pkg/secrets"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/pkg/errors"
 )
@@ -49,6 +50,11 @@ func (d *localBuildCreator) CreateBuild(ctx context.Context, req Request) (*buil
 	if req.Shim {
 		buildConfig["shim"] = "true"
 	}
+	buildSecrets, err := build.ResolveBuildSecrets(req.Def.BuildSecrets, req.BuildSecretSources)
+	if err != nil {
+		return nil, err
+	}
+
 	b, _, err := build.New(build.LocalConfig{
 		Root:    req.Root,
 		Builder: string(kind),
@@ -58,7 +64,8 @@ func (d *localBuildCreator) CreateBuild(ctx context.Context, req Request) (*buil
 			Token: registry.Token,
 			Repo:  registry.Repo,
 		},
-		BuildArgs: buildEnv,
+		BuildArgs:    buildEnv,
+		BuildSecrets: buildSecrets,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "new build")
@@ -71,6 +78,10 @@ func (d *localBuildCreator) CreateBuild(ctx context.Context, req Request) (*buil
 		return nil, errors.Wrap(err, "build")
 	}
 
+	if resp.Pushed {
+		return resp, nil
+	}
+
 	logger.Log("Pushing...")
 	if err := b.Push(ctx, resp.ImageURL); err != nil {
 		return nil, errors.Wrap(err, "push")
@@ -80,7 +91,7 @@ func (d *localBuildCreator) CreateBuild(ctx context.Context, req Request) (*buil
 }
 
 // Retrieves a build env from def - looks for env vars starting with BUILD_ and either uses the
-// string literal or looks up the config value.
+// string literal or looks up the config value or external secret.
 func getBuildEnv(ctx context.Context, client api.APIClient, taskEnv libapi.EnvVars) (map[string]string, error) {
 	buildEnv := make(map[string]string)
 	for k, v := range taskEnv {
@@ -100,6 +111,12 @@ func getBuildEnv(ctx context.Context, client api.APIClient, taskEnv libapi.EnvVa
 				return nil, err
 			}
 			buildEnv[k] = res.Config.Value
+		} else if v.External != nil {
+			value, err := secrets.Resolve(ctx, *v.External)
+			if err != nil {
+				return nil, errors.Wrapf(err, "env var %s", k)
+			}
+			buildEnv[k] = value
 		}
 	}
 	return buildEnv, nil
@@ -0,0 +1,35 @@
+// This file includes a shim that will execute your task code.
+import airplane from "{{.SDK}}";
+{{ if and (.EntrypointFunc) (ne .EntrypointFunc "default") -}}
+import { {{.EntrypointFunc}} as task } from "{{.Entrypoint}}";
+{{ else -}}
+import task from "{{.Entrypoint}}";
+{{- end }}
+
+async function main() {
+  if (Deno.args.length !== 1) {
+    console.log(`airplane_output_set:error ${JSON.stringify(`Expected to receive a single argument (via {{ "{{JSON}}" }}). Task CLI arguments may be misconfigured.`)}`);
+    Deno.exit(1);
+  }
+
+  try {
+    let ret;
+    if ("__airplane" in task) {
+      ret = await task.__airplane.baseFunc(JSON.parse(Deno.args[0]));
+    } else {
+      ret = await task(JSON.parse(Deno.args[0]));
+    }
+    if (ret !== undefined) {
+      airplane.setOutput(ret);
+    }
+  } catch (err) {
+    console.error(err);
+    // Print the error's message directly when possible. Otherwise, it includes the
+    // error's name (e.g. "RunTerminationError: ...").
+    const message = err instanceof Error ? err.message : String(err);
+    console.log(`airplane_output_set:error ${JSON.stringify(message)}`);
+    Deno.exit(1);
+  }
+}
+
+await main();
@@ -0,0 +1,262 @@
+package deno
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/build/utils"
+	buildversions "github.com/airplanedev/cli/pkg/build/versions"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/pkg/errors"
+	"github.com/tidwall/jsonc"
+)
+
+//go:embed deno-shim.ts
+var denoShim string
+
+//go:embed universal-deno-shim.ts
+var universalDenoShim string
+
+// ConfigFiles are the files that mark the root of a Deno project, in order of precedence.
+var ConfigFiles = []string{
+	"deno.json",
+	"deno.jsonc",
+}
+
+// Deno creates a Dockerfile for a Deno task.
+//
+// Unlike Node tasks, Deno tasks are not compiled ahead of time. Dependencies are resolved from the
+// imports of the task (e.g. `npm:` or `jsr:` specifiers) and cached into the image, using the
+// import map and lockfile of the task's deno.json, if any.
+func Deno(
+	root string,
+	options buildtypes.KindOptions,
+	buildArgs []string,
+) (string, error) {
+	entrypoint, _ := options["entrypoint"].(string)
+	if entrypoint == "" {
+		return "", errors.New("expected an entrypoint")
+	}
+	if err := fsx.AssertExistsAll(filepath.Join(root, entrypoint)); err != nil {
+		return "", err
+	}
+
+	workdir, _ := options["workdir"].(string)
+	workdir = filepath.ToSlash(workdir)
+	if !strings.HasPrefix(workdir, "/") {
+		workdir = "/" + workdir
+	}
+
+	base, err := GetBaseDenoImage(GetDenoVersion(options))
+	if err != nil {
+		return "", err
+	}
+
+	entrypointFunc, _ := options["entrypointFunc"].(string)
+	shim, err := TemplatedDenoShim(DenoShimParams{
+		Entrypoint:     entrypoint,
+		EntrypointFunc: entrypointFunc,
+		SDK:            SDKSpecifier(root, []string{entrypoint}),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return utils.ApplyTemplate(heredoc.Doc(`
+		FROM {{.Base}}
+
+		WORKDIR /airplane{{.Workdir}}
+
+		{{.Args}}
+
+		COPY . /airplane
+
+		RUN mkdir -p /airplane/.airplane && \
+			{{.InlineShim}} > /airplane/.airplane/shim.ts && \
+			deno cache /airplane/.airplane/shim.ts
+
+		ENTRYPOINT ["deno", "run", "--allow-all", "/airplane/.airplane/shim.ts"]
+	`), struct {
+		Base       string
+		Workdir    string
+		Args       string
+		InlineShim string
+	}{
+		Base:       base,
+		Workdir:    workdir,
+		Args:       makeArgsCommand(buildArgs),
+		InlineShim: utils.InlineString(shim),
+	})
+}
+
+// DenoBundle creates a Dockerfile that can run every Deno task in root. Tasks are run through the
+// universal shim, which is passed the entrypoint and export of the task to run.
+func DenoBundle(
+	root string,
+	buildContext buildtypes.BuildContext,
+	options buildtypes.KindOptions,
+	buildArgs []string,
+	filesToBuild []string,
+) (string, error) {
+	base, err := GetBaseDenoImage(string(buildContext.VersionOrDefault()))
+	if err != nil {
+		return "", err
+	}
+
+	workdir, _ := options["workdir"].(string)
+	workdir = filepath.ToSlash(workdir)
+	if !strings.HasPrefix(workdir, "/") {
+		workdir = "/" + workdir
+	}
+
+	shim, err := TemplatedUniversalDenoShim(SDKSpecifier(root, filesToBuild))
+	if err != nil {
+		return "", err
+	}
+
+	// Cache the dependencies of every task, so that runs don't download them.
+	filesToCache := []string{"/airplane/.airplane/universal-shim.ts"}
+	for _, file := range filesToBuild {
+		filesToCache = append(filesToCache, path.Join("/airplane", filepath.ToSlash(file)))
+	}
+
+	return utils.ApplyTemplate(heredoc.Doc(`
+		FROM {{.Base}}
+
+		WORKDIR /airplane{{.Workdir}}
+
+		{{.Args}}
+
+		COPY . /airplane
+
+		RUN mkdir -p /airplane/.airplane && \
+			{{.InlineShim}} > /airplane/.airplane/universal-shim.ts && \
+			deno cache {{.FilesToCache}}
+
+		# Set an empty entrypoint to override any entrypoints that may be set in the base image.
+		ENTRYPOINT []
+	`), struct {
+		Base         string
+		Workdir      string
+		Args         string
+		InlineShim   string
+		FilesToCache string
+	}{
+		Base:         base,
+		Workdir:      workdir,
+		Args:         makeArgsCommand(buildArgs),
+		InlineShim:   utils.InlineString(shim),
+		FilesToCache: strings.Join(filesToCache, " "),
+	})
+}
+
+type DenoShimParams struct {
+	Entrypoint     string
+	EntrypointFunc string
+	// SDK is the specifier that the shim imports the Airplane SDK with, see SDKSpecifier.
+	SDK string
+}
+
+// TemplatedDenoShim returns the shim that runs the task exported by params.EntrypointFunc from
+// params.Entrypoint. The shim is stored under the .airplane directory of the task root.
+func TemplatedDenoShim(params DenoShimParams) (string, error) {
+	// Deno requires import paths to be relative and to keep their extension.
+	entrypoint := path.Join("..", filepath.ToSlash(params.Entrypoint))
+	entrypoint = utils.BackslashEscape(entrypoint, `"`)
+
+	shim, err := utils.ApplyTemplate(denoShim, DenoShimParams{
+		Entrypoint:     entrypoint,
+		EntrypointFunc: params.EntrypointFunc,
+		SDK:            params.SDK,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "templating shim")
+	}
+	return shim, nil
+}
+
+// TemplatedUniversalDenoShim returns the shim that runs any task, given its entrypoint and export
+// as arguments. sdk is the specifier that the shim imports the Airplane SDK with.
+func TemplatedUniversalDenoShim(sdk string) (string, error) {
+	shim, err := utils.ApplyTemplate(universalDenoShim, struct{ SDK string }{SDK: sdk})
+	if err != nil {
+		return "", errors.Wrap(err, "templating shim")
+	}
+	return shim, nil
+}
+
+// sdkImport matches imports of the Airplane SDK from npm, e.g. `from "npm:airplane@0.2.30"`.
+var sdkImport = regexp.MustCompile(`["'](npm:airplane(?:@[^"'/]+)?)["']`)
+
+// SDKSpecifier returns the specifier that shims import the Airplane SDK with, so that they use the
+// same copy of the SDK as the tasks they run: "airplane" if the import map of root's deno.json maps
+// it, or else the npm specifier that the first of entrypoints (relative to root) to import the SDK
+// uses. Tasks that don't use the SDK get its latest version.
+func SDKSpecifier(root string, entrypoints []string) string {
+	for _, name := range ConfigFiles {
+		buf, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		var config struct {
+			Imports map[string]string `json:"imports"`
+		}
+		if err := json.Unmarshal(jsonc.ToJSON(buf), &config); err == nil && config.Imports["airplane"] != "" {
+			return "airplane"
+		}
+		break
+	}
+	for _, entrypoint := range entrypoints {
+		if !filepath.IsAbs(entrypoint) {
+			entrypoint = filepath.Join(root, entrypoint)
+		}
+		buf, err := os.ReadFile(entrypoint)
+		if err != nil {
+			continue
+		}
+		if m := sdkImport.FindSubmatch(buf); m != nil {
+			return string(m[1])
+		}
+	}
+	return "npm:airplane"
+}
+
+// GetDenoVersion returns the Deno version configured by opts, if any.
+func GetDenoVersion(opts buildtypes.KindOptions) string {
+	v, _ := opts["denoVersion"].(string)
+	return v
+}
+
+// GetBaseDenoImage returns the base image for the given Deno version. If version is empty, the
+// default version is used.
+func GetBaseDenoImage(version string) (string, error) {
+	if version == "" {
+		version = string(buildtypes.DefaultDenoVersion)
+	}
+	v, err := buildversions.GetVersion(buildtypes.NameDeno, version, false)
+	if err != nil {
+		return "", err
+	}
+	base := v.Ref()
+	if base == "" {
+		// Assume the version is already a more-specific version - default to just returning it back
+		base = "denoland/deno:debian-" + version
+	}
+	return base, nil
+}
+
+func makeArgsCommand(buildArgs []string) string {
+	args := make([]string, len(buildArgs))
+	for i, a := range buildArgs {
+		args[i] = fmt.Sprintf("ARG %s", a)
+	}
+	return strings.Join(args, "\n")
+}
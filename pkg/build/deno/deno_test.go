@@ -0,0 +1,96 @@
+package deno
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeno(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.ts"), []byte("export default async () => {}"), 0644))
+
+	for _, version := range buildtypes.AllBuildTypeVersions[buildtypes.DenoBuildType] {
+		if version == buildtypes.BuildTypeVersionUnspecified {
+			continue
+		}
+		v := string(version)
+		t.Run("deno"+v, func(t *testing.T) {
+			require := require.New(t)
+
+			base, err := GetBaseDenoImage(v)
+			require.NoError(err)
+			require.Contains(base, "registry.hub.docker.com/denoland/deno:debian-"+v+".")
+
+			dockerfile, err := Deno(root, buildtypes.KindOptions{
+				"entrypoint":     "main.ts",
+				"entrypointFunc": "myTask",
+				"denoVersion":    v,
+			}, []string{"FOO"})
+			require.NoError(err)
+			require.Contains(dockerfile, "FROM "+base)
+			require.Contains(dockerfile, "ARG FOO")
+			require.Contains(dockerfile, "deno cache /airplane/.airplane/shim.ts")
+		})
+	}
+
+	_, err := Deno(root, buildtypes.KindOptions{"entrypoint": "missing.ts"}, nil)
+	require.Error(t, err)
+}
+
+func TestDenoBundle(t *testing.T) {
+	require := require.New(t)
+
+	dockerfile, err := DenoBundle(t.TempDir(), buildtypes.BuildContext{
+		Type: buildtypes.DenoBuildType,
+	}, buildtypes.KindOptions{}, nil, []string{"tasks/a.airplane.ts", "b.airplane.ts"})
+	require.NoError(err)
+	require.Contains(dockerfile, "FROM registry.hub.docker.com/denoland/deno:debian-2.")
+	require.Contains(dockerfile, "deno cache /airplane/.airplane/universal-shim.ts /airplane/tasks/a.airplane.ts /airplane/b.airplane.ts")
+}
+
+func TestTemplatedDenoShim(t *testing.T) {
+	require := require.New(t)
+
+	shim, err := TemplatedDenoShim(DenoShimParams{
+		Entrypoint: "tasks/main.ts",
+	})
+	require.NoError(err)
+	require.Contains(shim, `import task from "../tasks/main.ts";`)
+
+	shim, err = TemplatedDenoShim(DenoShimParams{
+		Entrypoint:     "main.ts",
+		EntrypointFunc: "myTask",
+		SDK:            "npm:airplane",
+	})
+	require.NoError(err)
+	require.Contains(shim, `import { myTask as task } from "../main.ts";`)
+	require.Contains(shim, "(via {{JSON}})")
+	require.Contains(shim, `import airplane from "npm:airplane";`)
+
+	shim, err = TemplatedUniversalDenoShim("npm:airplane@0.2.30")
+	require.NoError(err)
+	require.Contains(shim, `import airplane from "npm:airplane@0.2.30";`)
+	require.Contains(shim, "(via {{JSON}})")
+}
+
+func TestSDKSpecifier(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(root, "a.airplane.ts"), []byte("export default {};\n"), 0644))
+	require.Equal("npm:airplane", SDKSpecifier(root, []string{"a.airplane.ts"}))
+
+	require.NoError(os.WriteFile(filepath.Join(root, "b.airplane.ts"), []byte(`import airplane from "npm:airplane@0.2.30";`), 0644))
+	require.Equal("npm:airplane@0.2.30", SDKSpecifier(root, []string{"a.airplane.ts", "b.airplane.ts"}))
+	require.Equal("npm:airplane@0.2.30", SDKSpecifier(root, []string{filepath.Join(root, "b.airplane.ts")}))
+
+	require.NoError(os.WriteFile(filepath.Join(root, "deno.jsonc"), []byte(`{
+  // Pin the SDK for every task.
+  "imports": {"airplane": "npm:airplane@0.2.31"},
+}`), 0644))
+	require.Equal("airplane", SDKSpecifier(root, []string{"b.airplane.ts"}))
+}
@@ -0,0 +1,39 @@
+// This file includes a shim that will execute your task code.
+import airplane from "{{.SDK}}";
+
+async function main() {
+  if (Deno.args.length !== 3) {
+    console.log(
+      `airplane_output_set:error ${JSON.stringify(
+        `Expected to receive entrypoint, entrypointFunc, and params (via {{ "{{JSON}}" }}). Task CLI arguments may be misconfigured.`
+      )}`
+    );
+    Deno.exit(1);
+  }
+
+  const entrypoint = Deno.args[0];
+  const entrypointFunc = Deno.args[1] || "default";
+  const params = Deno.args[2];
+
+  try {
+    const task = (await import(`file://${entrypoint}`))[entrypointFunc];
+    let ret;
+    if ("__airplane" in task) {
+      ret = await task.__airplane.baseFunc(JSON.parse(params));
+    } else {
+      ret = await task(JSON.parse(params));
+    }
+    if (ret !== undefined) {
+      airplane.setOutput(ret);
+    }
+  } catch (err) {
+    console.error(err);
+    // Print the error's message directly when possible. Otherwise, it includes the
+    // error's name (e.g. "RunTerminationError: ...").
+    const message = err instanceof Error ? err.message : String(err);
+    console.log(`airplane_output_set:error ${JSON.stringify(message)}`);
+    Deno.exit(1);
+  }
+}
+
+await main();
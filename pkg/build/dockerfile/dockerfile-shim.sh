@@ -0,0 +1,27 @@
+# Params are passed in as param_slug_1=value1 param_slug_2=value2 -- command args...
+# Export them as environment variables, PARAM_SLUG_1=value1, PARAM_SLUG_2=value2, and run the command.
+while [ "$#" -gt 0 ]; do
+    if [ "$1" = "--" ]; then
+        shift
+        break
+    fi
+    case $1 in
+        (*=*)
+            param_slug=${1%%=*}
+            param_value=${1#*=}
+            ;;
+        (*)
+            param_slug=$1
+            param_value=
+            ;;
+    esac
+    var_name="$(echo "PARAM_${param_slug}" | tr '[:lower:]' '[:upper:]')"
+    export "${var_name}=${param_value}"
+    shift
+done
+
+if [ "$#" -eq 0 ]; then
+    echo "No command to run: set the command of the task." >&2
+    exit 1
+fi
+exec "$@"
@@ -0,0 +1,147 @@
+package dockerfile
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/build/utils"
+	"github.com/pkg/errors"
+)
+
+// taskStage is the name given to the final stage of a user's Dockerfile if it isn't named.
+const taskStage = "airplane-task"
+
+// Dockerfile creates a Dockerfile for a task that's built from the user's Dockerfile at
+// options["dockerfile"], relative to root.
+func Dockerfile(root string, options buildtypes.KindOptions) (string, error) {
+	path, _ := options["dockerfile"].(string)
+	if path == "" {
+		return "", errors.New("expected a dockerfile")
+	}
+	return dockerfile(root, path)
+}
+
+// DockerfileBundle creates a Dockerfile for the tasks in root that are built from the Dockerfile
+// of buildContext.
+func DockerfileBundle(root string, buildContext buildtypes.BuildContext) (string, error) {
+	if buildContext.Dockerfile == nil || buildContext.Dockerfile.Path == "" {
+		return "", errors.New("expected a dockerfile")
+	}
+	return dockerfile(root, buildContext.Dockerfile.Path)
+}
+
+// dockerfile builds the user's Dockerfile, and then adds a final stage that runs the task's
+// command through the shim. The shim is inlined into the entrypoint, rather than written to a
+// file, so that it works regardless of the user the image runs as.
+func dockerfile(root, path string) (string, error) {
+	buf, err := os.ReadFile(filepath.Join(root, path))
+	if err != nil {
+		return "", errors.Wrapf(err, "reading %s", path)
+	}
+
+	user, stage, err := nameFinalStage(string(buf))
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing %s", path)
+	}
+	var entrypoint bytes.Buffer
+	enc := json.NewEncoder(&entrypoint)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode([]string{"/bin/sh", "-c", Shim(), "airplane-shim"}); err != nil {
+		return "", errors.Wrap(err, "marshaling entrypoint")
+	}
+
+	// The user's Dockerfile is appended as-is, rather than templated, since it may contain
+	// template delimiters of its own.
+	final, err := utils.ApplyTemplate(heredoc.Doc(`
+		FROM {{.Stage}}
+		ENTRYPOINT {{.Entrypoint}}
+	`), struct {
+		Stage      string
+		Entrypoint string
+	}{
+		Stage:      stage,
+		Entrypoint: strings.TrimSpace(entrypoint.String()),
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(user, "\n") + "\n\n" + final, nil
+}
+
+var fromRegexp = regexp.MustCompile(`(?i)^\s*FROM\s+(--\S+\s+)*\S+(\s+AS\s+(\S+))?\s*$`)
+
+// nameFinalStage returns the Dockerfile with its final stage named, and the name of the final
+// stage. Stages that are already named keep their name.
+func nameFinalStage(dockerfile string) (string, string, error) {
+	lines := strings.Split(dockerfile, "\n")
+	// The index of the last physical line of the final FROM instruction, and its logical line.
+	last := -1
+	var from string
+
+	var logical strings.Builder
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if logical.Len() == 0 && strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasSuffix(trimmed, `\`) {
+			logical.WriteString(strings.TrimSuffix(trimmed, `\`) + " ")
+			continue
+		}
+		logical.WriteString(trimmed)
+		instruction := logical.String()
+		logical.Reset()
+
+		if fields := strings.Fields(instruction); len(fields) > 0 && strings.EqualFold(fields[0], "FROM") {
+			last = i
+			from = instruction
+		}
+	}
+	if last == -1 {
+		return "", "", errors.New("no FROM instruction found")
+	}
+
+	m := fromRegexp.FindStringSubmatch(from)
+	if m == nil {
+		return "", "", errors.Errorf("unexpected FROM instruction: %s", from)
+	}
+	if m[3] != "" {
+		return dockerfile, m[3], nil
+	}
+	lines[last] = fmt.Sprintf("%s AS %s", strings.TrimRight(lines[last], " \t\r"), taskStage)
+	return strings.Join(lines, "\n"), taskStage, nil
+}
+
+// BuildArgs returns the build args configured by options["buildArgs"], if any.
+func BuildArgs(options buildtypes.KindOptions) map[string]string {
+	buildArgs := map[string]string{}
+	switch args := options[buildtypes.KindOptionBuildArgs].(type) {
+	case map[string]string:
+		for k, v := range args {
+			buildArgs[k] = v
+		}
+	case map[string]interface{}:
+		for k, v := range args {
+			buildArgs[k] = fmt.Sprint(v)
+		}
+	}
+	return buildArgs
+}
+
+//go:embed dockerfile-shim.sh
+var shim string
+
+// Shim returns the shell script that runs the commands of tasks built from Dockerfiles. It's passed
+// the task's parameters as slug=value arguments, followed by "--" and the command to run, and
+// exports the parameters as PARAM_<SLUG> env vars.
+func Shim() string {
+	return shim
+}
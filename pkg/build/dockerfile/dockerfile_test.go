@@ -0,0 +1,101 @@
+package dockerfile
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameFinalStage(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		dockerfile string
+		expected   string
+		stage      string
+		err        bool
+	}{
+		{
+			name:       "unnamed",
+			dockerfile: "FROM alpine:3\nRUN apk add curl\n",
+			expected:   "FROM alpine:3 AS airplane-task\nRUN apk add curl\n",
+			stage:      "airplane-task",
+		},
+		{
+			name:       "named",
+			dockerfile: "FROM golang:1.23 AS build\nRUN go build\nFROM alpine:3 as runtime\nCOPY --from=build /app /app\n",
+			expected:   "FROM golang:1.23 AS build\nRUN go build\nFROM alpine:3 as runtime\nCOPY --from=build /app /app\n",
+			stage:      "runtime",
+		},
+		{
+			name:       "multi-stage with platform",
+			dockerfile: "# syntax=docker/dockerfile:1\nFROM node:20 AS deps\nRUN npm ci\nfrom --platform=linux/amd64 \\\n  debian:bookworm\n",
+			expected:   "# syntax=docker/dockerfile:1\nFROM node:20 AS deps\nRUN npm ci\nfrom --platform=linux/amd64 \\\n  debian:bookworm AS airplane-task\n",
+			stage:      "airplane-task",
+		},
+		{
+			name:       "no FROM",
+			dockerfile: "# FROM alpine\nRUN echo\n",
+			err:        true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			dockerfile, stage, err := nameFinalStage(test.dockerfile)
+			if test.err {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(test.expected, dockerfile)
+			require.Equal(test.stage, stage)
+		})
+	}
+}
+
+func TestDockerfileBundle(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(root, "docker"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(root, "docker", "Dockerfile"), []byte("FROM alpine:3\nARG VERSION\nRUN echo {{not a template}}"), 0644))
+
+	dockerfile, err := DockerfileBundle(root, buildtypes.BuildContext{
+		Type:       buildtypes.DockerfileBuildType,
+		Dockerfile: &buildtypes.DockerfileBuildContext{Path: "docker/Dockerfile"},
+	})
+	require.NoError(err)
+	require.True(strings.HasPrefix(dockerfile, "FROM alpine:3 AS airplane-task\nARG VERSION\nRUN echo {{not a template}}\n\nFROM airplane-task\nENTRYPOINT [\"/bin/sh\",\"-c\","), dockerfile)
+	require.Contains(dockerfile, `,"airplane-shim"]`)
+
+	_, err = DockerfileBundle(root, buildtypes.BuildContext{Type: buildtypes.DockerfileBuildType})
+	require.Error(err)
+
+	_, err = Dockerfile(root, buildtypes.KindOptions{"dockerfile": "missing/Dockerfile"})
+	require.Error(err)
+}
+
+func TestShim(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+	require := require.New(t)
+
+	out, err := exec.Command("sh", "-c", Shim(), "airplane-shim", "name=World", "empty", "--", "sh", "-c", `echo "$PARAM_NAME:$PARAM_EMPTY"`).Output()
+	require.NoError(err)
+	require.Equal("World:\n", string(out))
+
+	err = exec.Command("sh", "-c", Shim(), "airplane-shim", "name=World", "--").Run()
+	require.Error(err)
+}
+
+func TestBuildArgs(t *testing.T) {
+	require := require.New(t)
+	require.Equal(map[string]string{"VERSION": "1"}, BuildArgs(buildtypes.KindOptions{
+		"buildArgs": map[string]interface{}{"VERSION": "1"},
+	}))
+	require.Equal(map[string]string{}, BuildArgs(nil))
+}
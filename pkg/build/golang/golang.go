@@ -0,0 +1,262 @@
+package golang
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/build/utils"
+	buildversions "github.com/airplanedev/cli/pkg/build/versions"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/pkg/errors"
+)
+
+// BinDir is the directory that the binaries of bundled Go tasks are built into.
+const BinDir = "/airplane/.airplane/bin"
+
+// Go creates a Dockerfile for a Go task.
+//
+// The task is compiled in a builder stage that downloads the dependencies of the module before
+// copying in the rest of the task, so that they're cached until go.mod or go.sum change. The
+// resulting binary is copied into a slim runtime image and is passed the task's parameters as a
+// JSON-encoded argument.
+func Go(
+	root string,
+	options buildtypes.KindOptions,
+	buildArgs []string,
+) (string, error) {
+	entrypoint, _ := options["entrypoint"].(string)
+	if entrypoint == "" {
+		return "", errors.New("expected an entrypoint")
+	}
+	if err := fsx.AssertExistsAll(filepath.Join(root, "go.mod"), filepath.Join(root, entrypoint)); err != nil {
+		return "", err
+	}
+
+	workdir, _ := options["workdir"].(string)
+	workdir = filepath.ToSlash(workdir)
+	if !strings.HasPrefix(workdir, "/") {
+		workdir = "/" + workdir
+	}
+
+	version := GetGoVersion(options)
+	base, err := GetBaseGoImage(version)
+	if err != nil {
+		return "", err
+	}
+	runtimeBase, err := GetRuntimeGoImage(version)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.ApplyTemplate(heredoc.Doc(`
+		FROM {{.Base}} AS builder
+
+		WORKDIR /airplane
+
+		{{.Args}}
+
+		COPY go.mod go.sum* ./
+		RUN {{.SecretMounts}}go mod download
+
+		COPY . /airplane
+		RUN {{.BuildCommand}}
+
+		FROM {{.RuntimeBase}}
+
+		COPY --from=builder /etc/ssl/certs/ca-certificates.crt /etc/ssl/certs/ca-certificates.crt
+		COPY --from=builder /airplane /airplane
+
+		WORKDIR /airplane{{.Workdir}}
+
+		ENTRYPOINT ["/airplane/.airplane/task"]
+	`), struct {
+		Base         string
+		RuntimeBase  string
+		Workdir      string
+		Args         string
+		SecretMounts string
+		BuildCommand string
+	}{
+		Base:         base,
+		RuntimeBase:  runtimeBase,
+		Workdir:      workdir,
+		Args:         makeArgsCommand(buildArgs),
+		SecretMounts: buildtypes.SecretMounts(buildtypes.BuildSecrets(options)),
+		BuildCommand: buildCommand(GetBuildTags(options), "/airplane/.airplane/task", entrypoint),
+	})
+}
+
+// GoBundle creates a Dockerfile that can run every Go task in root. The package of each task is
+// built into its own binary, see BinaryPath.
+func GoBundle(
+	root string,
+	buildContext buildtypes.BuildContext,
+	options buildtypes.KindOptions,
+	buildArgs []string,
+	filesToBuild []string,
+) (string, error) {
+	if err := fsx.AssertExistsAll(filepath.Join(root, "go.mod")); err != nil {
+		return "", err
+	}
+
+	version := string(buildContext.VersionOrDefault())
+	base, err := GetBaseGoImage(version)
+	if err != nil {
+		return "", err
+	}
+	runtimeBase, err := GetRuntimeGoImage(version)
+	if err != nil {
+		return "", err
+	}
+
+	workdir, _ := options["workdir"].(string)
+	workdir = filepath.ToSlash(workdir)
+	if !strings.HasPrefix(workdir, "/") {
+		workdir = "/" + workdir
+	}
+
+	// Tasks in the same package share a binary.
+	buildTags := GetBuildTags(options)
+	binaries := map[string]string{}
+	for _, file := range filesToBuild {
+		binaries[BinaryPath(file)] = file
+	}
+	var buildCommands []string
+	for binary, file := range binaries {
+		buildCommands = append(buildCommands, buildCommand(buildTags, binary, file))
+	}
+	sort.Strings(buildCommands)
+
+	return utils.ApplyTemplate(heredoc.Doc(`
+		FROM {{.Base}} AS builder
+
+		WORKDIR /airplane
+
+		{{.Args}}
+
+		COPY go.mod go.sum* ./
+		RUN {{.SecretMounts}}go mod download
+
+		COPY . /airplane
+		{{- range .BuildCommands}}
+		RUN {{.}}
+		{{- end}}
+
+		FROM {{.RuntimeBase}}
+
+		COPY --from=builder /etc/ssl/certs/ca-certificates.crt /etc/ssl/certs/ca-certificates.crt
+		COPY --from=builder /airplane /airplane
+
+		WORKDIR /airplane{{.Workdir}}
+
+		# Set an empty entrypoint to override any entrypoints that may be set in the base image.
+		ENTRYPOINT []
+	`), struct {
+		Base          string
+		RuntimeBase   string
+		Workdir       string
+		Args          string
+		SecretMounts  string
+		BuildCommands []string
+	}{
+		Base:          base,
+		RuntimeBase:   runtimeBase,
+		Workdir:       workdir,
+		Args:          makeArgsCommand(buildArgs),
+		SecretMounts:  buildtypes.SecretMounts(buildtypes.BuildSecrets(options)),
+		BuildCommands: buildCommands,
+	})
+}
+
+// BinaryPath returns the path that the binary of a bundled Go task with the given entrypoint is
+// built to. Entrypoints are relative to the root of the Go module.
+func BinaryPath(entrypoint string) string {
+	return path.Join(BinDir, path.Dir(filepath.ToSlash(entrypoint)), "task")
+}
+
+// buildCommand returns the command that builds the package containing entrypoint into output.
+// Binaries are statically linked so that they run on the runtime image without a Go toolchain.
+func buildCommand(buildTags []string, output, entrypoint string) string {
+	args := []string{"CGO_ENABLED=0", "go", "build", "-trimpath"}
+	if len(buildTags) > 0 {
+		args = append(args, "-tags", strings.Join(buildTags, ","))
+	}
+	pkg := "."
+	if dir := path.Dir(filepath.ToSlash(entrypoint)); dir != "." {
+		pkg = "./" + dir
+	}
+	args = append(args, "-o", output, pkg)
+	return strings.Join(args, " ")
+}
+
+// GetGoVersion returns the Go version configured by opts, if any.
+func GetGoVersion(opts buildtypes.KindOptions) string {
+	v, _ := opts["goVersion"].(string)
+	return v
+}
+
+// GetBuildTags returns the build tags configured by opts, if any.
+func GetBuildTags(opts buildtypes.KindOptions) []string {
+	switch v := opts["buildTags"].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var tags []string
+		for _, tag := range v {
+			if s, ok := tag.(string); ok {
+				tags = append(tags, s)
+			}
+		}
+		return tags
+	default:
+		return nil
+	}
+}
+
+// GetBaseGoImage returns the image that Go tasks are compiled with for the given Go version. If
+// version is empty, the default version is used.
+func GetBaseGoImage(version string) (string, error) {
+	if version == "" {
+		version = string(buildtypes.DefaultGoVersion)
+	}
+	v, err := buildversions.GetVersion(buildtypes.NameGo, version, false)
+	if err != nil {
+		return "", err
+	}
+	base := v.Ref()
+	if base == "" {
+		// Assume the version is already a more-specific version - default to just returning it back
+		base = "golang:" + version + "-bookworm"
+	}
+	return base, nil
+}
+
+// GetRuntimeGoImage returns the image that compiled Go tasks are run on for the given Go version.
+// If version is empty, the default version is used.
+func GetRuntimeGoImage(version string) (string, error) {
+	if version == "" {
+		version = string(buildtypes.DefaultGoVersion)
+	}
+	v, err := buildversions.GetVersion(buildtypes.NameGo, version, true)
+	if err != nil {
+		return "", err
+	}
+	base := v.Ref()
+	if base == "" {
+		base = "debian:bookworm-slim"
+	}
+	return base, nil
+}
+
+func makeArgsCommand(buildArgs []string) string {
+	args := make([]string, len(buildArgs))
+	for i, a := range buildArgs {
+		args[i] = fmt.Sprintf("ARG %s", a)
+	}
+	return strings.Join(args, "\n")
+}
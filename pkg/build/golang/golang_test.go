@@ -0,0 +1,75 @@
+package golang
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGo(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/tasks\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "hello"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "hello", "hello_airplane.go"), []byte("package main\n"), 0644))
+
+	for _, version := range buildtypes.AllBuildTypeVersions[buildtypes.GoBuildType] {
+		if version == buildtypes.BuildTypeVersionUnspecified {
+			continue
+		}
+		v := string(version)
+		t.Run("go"+v, func(t *testing.T) {
+			require := require.New(t)
+
+			base, err := GetBaseGoImage(v)
+			require.NoError(err)
+			require.Contains(base, "registry.hub.docker.com/library/golang:"+v+".")
+			runtimeBase, err := GetRuntimeGoImage(v)
+			require.NoError(err)
+
+			dockerfile, err := Go(root, buildtypes.KindOptions{
+				"entrypoint": "hello/hello_airplane.go",
+				"goVersion":  v,
+				"buildTags":  []interface{}{"prod", "netgo"},
+			}, []string{"FOO"})
+			require.NoError(err)
+			require.Contains(dockerfile, "FROM "+base+" AS builder")
+			require.Contains(dockerfile, "FROM "+runtimeBase+"\n")
+			require.Contains(dockerfile, "ARG FOO")
+			require.Contains(dockerfile, "COPY go.mod go.sum* ./\nRUN go mod download")
+			require.Contains(dockerfile, "RUN CGO_ENABLED=0 go build -trimpath -tags prod,netgo -o /airplane/.airplane/task ./hello")
+			require.Contains(dockerfile, `ENTRYPOINT ["/airplane/.airplane/task"]`)
+		})
+	}
+
+	_, err := Go(root, buildtypes.KindOptions{"entrypoint": "missing_airplane.go"}, nil)
+	require.Error(t, err)
+	_, err = Go(t.TempDir(), buildtypes.KindOptions{"entrypoint": "main_airplane.go"}, nil)
+	require.Error(t, err, "expected an error without a go.mod")
+}
+
+func TestGoBundle(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/tasks\n"), 0644))
+
+	dockerfile, err := GoBundle(root, buildtypes.BuildContext{
+		Type: buildtypes.GoBuildType,
+	}, buildtypes.KindOptions{}, nil, []string{"tasks/a/a_airplane.go", "b_airplane.go", "tasks/a/other_airplane.go"})
+	require.NoError(err)
+	require.Contains(dockerfile, "FROM registry.hub.docker.com/library/golang:1.23.")
+	require.Contains(dockerfile, "RUN CGO_ENABLED=0 go build -trimpath -o /airplane/.airplane/bin/task .\n")
+	require.Contains(dockerfile, "RUN CGO_ENABLED=0 go build -trimpath -o /airplane/.airplane/bin/tasks/a/task ./tasks/a\n")
+	require.Equal(1, strings.Count(dockerfile, "./tasks/a\n"), "expected tasks in the same package to share a binary")
+}
+
+func TestBinaryPath(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("/airplane/.airplane/bin/task", BinaryPath("main_airplane.go"))
+	require.Equal("/airplane/.airplane/bin/tasks/hello/task", BinaryPath(filepath.Join("tasks", "hello", "hello_airplane.go")))
+}
@@ -1,16 +1,18 @@
 package ignore
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/airplanedev/cli/pkg/utils/fsx"
 	"github.com/pkg/errors"
 	gitignore "github.com/sabhiram/go-gitignore"
 )
 
 // Returns an IgnoreFunc that can be used with airplanedev/archiver to filter
-// out files that match a default list or user-provided .airplaneignore.
+// out files that match a default list or user-provided .airplaneignore files.
 func Func(taskRootPath string) (func(filePath string, info os.FileInfo) (bool, error), error) {
 	excludes, err := Patterns(taskRootPath)
 	if err != nil {
@@ -53,63 +55,212 @@ func Func(taskRootPath string) (func(filePath string, info os.FileInfo) (bool, e
 	}, nil
 }
 
+// FileName is the name of the files that list paths to exclude from discovery and build contexts,
+// in the .gitignore format. Like .gitignore files, a file applies to the directory that contains
+// it and its subdirectories.
+const FileName = ".airplaneignore"
+
+// defaultExcludes are excluded regardless of kind because you might have both JS and PY tasks and
+// want pyc files excluded just the same.
+// For inspiration, see:
+// https://github.com/github/gitignore
+// https://github.com/github/gitignore/blob/master/Go.gitignore
+// https://github.com/github/gitignore/blob/master/Node.gitignore
+// https://vercel.com/docs/build-step#ignored-files-and-folders
+var defaultExcludes = []string{
+	".env.local",
+	".env.*.local",
+	"*.pyc",
+	".git",
+	".gitmodules",
+	".hg",
+	".idea",
+	".next",
+	".now",
+	".npm",
+	".svn",
+	".*.swp",
+	".terraform",
+	".venv",
+	".vercel",
+	"/.yarn",
+	"!/.yarn/patches",
+	"!/.yarn/plugins",
+	"!/.yarn/releases",
+	"!/.yarn/versions",
+	"__pycache__",
+	"node_modules",
+	"npm-debug.log",
+	// Local build artifacts created by `airplane dev`.
+	".airplane",
+	".airplane-view",
+}
+
+// Patterns returns the patterns that exclude files from the build context of path: the default
+// excludes, followed by the patterns of every .airplaneignore file in path and its subdirectories.
+// Patterns of nested files are rewritten to be relative to path.
 func Patterns(path string) ([]string, error) {
-	// Start with default set of excludes.
-	// We exclude the same files regardless of kind because you might have both JS and PY tasks and
-	// want pyc files excluded just the same.
-	// For inspiration, see:
-	// https://github.com/github/gitignore
-	// https://github.com/github/gitignore/blob/master/Go.gitignore
-	// https://github.com/github/gitignore/blob/master/Node.gitignore
-	// https://vercel.com/docs/build-step#ignored-files-and-folders
-	excludes := []string{
-		".env.local",
-		".env.*.local",
-		"*.pyc",
-		".git",
-		".gitmodules",
-		".hg",
-		".idea",
-		".next",
-		".now",
-		".npm",
-		".svn",
-		".*.swp",
-		".terraform",
-		".venv",
-		".vercel",
-		"/.yarn",
-		"!/.yarn/patches",
-		"!/.yarn/plugins",
-		"!/.yarn/releases",
-		"!/.yarn/versions",
-		"__pycache__",
-		"node_modules",
-		"npm-debug.log",
-		// Local build artifacts created by `airplane dev`.
-		".airplane",
-		".airplane-view",
-	}
-
-	// Allow user-specified ignore file. Note that users can re-INCLUDE files using !, so if our
-	// default excludes skip something necessary they can always add it back.
-	const ignorefile = ".airplaneignore"
-	bs, err := os.ReadFile(filepath.Join(path, ignorefile))
+	m, err := NewMatcher(path)
+	if err != nil {
+		return nil, err
+	}
+	err = filepath.WalkDir(path, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() || p == path {
+			return nil
+		}
+		if m.Match(p) {
+			// The .airplaneignore files of ignored directories don't apply.
+			return filepath.SkipDir
+		}
+		return m.AddDir(p)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "finding "+FileName+" files")
+	}
+	return m.patterns, nil
+}
+
+// Matcher matches paths against the default excludes and the .airplaneignore files of a directory
+// tree.
+type Matcher struct {
+	root     string
+	loaded   map[string]bool
+	patterns []string
+	ig       *gitignore.GitIgnore
+}
+
+// NewMatcher returns a Matcher for the directory tree at root, with the default excludes and the
+// patterns of root's .airplaneignore file, if any. Use AddDir to load the files of subdirectories.
+func NewMatcher(root string) (*Matcher, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting absolute path")
+	}
+	m := &Matcher{root: root, loaded: map[string]bool{}}
+	m.add(defaultExcludes)
+	if err := m.AddDir(root); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewMatcherFor returns a Matcher for dir that also applies the .airplaneignore files of its
+// parent directories, up to the root of the git repository that dir is in, if any.
+func NewMatcherFor(dir string) (*Matcher, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting absolute path")
+	}
+	root := dir
+	for d := dir; ; d = filepath.Dir(d) {
+		if fsx.Exists(filepath.Join(d, FileName)) {
+			root = d
+		}
+		if fsx.Exists(filepath.Join(d, ".git")) || filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	m, err := NewMatcher(root)
+	if err != nil {
+		return nil, err
+	}
+	if root != dir {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting relative path")
+		}
+		d := root
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			d = filepath.Join(d, part)
+			if err := m.AddDir(d); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// AddDir loads the .airplaneignore file of dir, a directory in the Matcher's tree, if it has one.
+// Files that have already been loaded are skipped.
+func (m *Matcher) AddDir(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return errors.Wrap(err, "getting absolute path")
+	}
+	if m.loaded[dir] {
+		return nil
+	}
+	m.loaded[dir] = true
+
+	bs, err := os.ReadFile(filepath.Join(dir, FileName))
 	switch {
 	case os.IsNotExist(err):
-		// Nothing additional to append
-		return excludes, nil
+		return nil
 	case err != nil:
-		return nil, errors.Wrap(err, "opening "+ignorefile)
+		return errors.Wrap(err, "opening "+FileName)
+	}
+
+	rel, err := filepath.Rel(m.root, dir)
+	if err != nil {
+		return errors.Wrap(err, "getting relative path")
 	}
-	fileExcludes := []string{}
-	for _, ex := range strings.Split(string(bs), "\n") {
-		if ex != "" {
-			fileExcludes = append(fileExcludes, ex)
+	rel = filepath.ToSlash(rel)
+
+	var patterns []string
+	for _, line := range strings.Split(string(bs), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		if rel != "." {
+			line = rebase(line, rel)
 		}
+		patterns = append(patterns, line)
+	}
+	m.add(patterns)
+	return nil
+}
+
+// Match returns true if path, a path in the Matcher's tree, is ignored.
+func (m *Matcher) Match(path string) bool {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	return m.ig.MatchesPath(rel)
+}
+
+func (m *Matcher) add(patterns []string) {
+	// Note that users can re-INCLUDE files using !, so if our default excludes skip something
+	// necessary they can always add it back.
+	m.patterns = append(m.patterns, patterns...)
+	m.ig = gitignore.CompileIgnoreLines(m.patterns...)
+}
+
+// rebase rewrites a pattern of the .airplaneignore file in dir, relative to the Matcher's root,
+// so that it only matches paths in dir.
+func rebase(pattern, dir string) string {
+	if strings.HasPrefix(pattern, "#") {
+		return pattern
+	}
+	var negate string
+	if strings.HasPrefix(pattern, "!") {
+		negate, pattern = "!", pattern[1:]
+	}
+	// As in .gitignore files, patterns with a slash at the start or in the middle are relative to
+	// the directory of the file. Otherwise, they match at any depth.
+	if strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		return negate + "/" + dir + "/" + strings.TrimPrefix(pattern, "/")
 	}
-	excludes = append(excludes, fileExcludes...)
-	return excludes, nil
+	return negate + "/" + dir + "/**/" + pattern
 }
 
 // DockerignorePatterns returns the ignore patterns formatted according to
@@ -1,6 +1,8 @@
 package ignore
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -25,3 +27,54 @@ func TestDockerignorePatterns(tt *testing.T) {
 		})
 	}
 }
+
+func TestPatterns(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		".airplaneignore":              "fixtures\n",
+		"pkg/.airplaneignore":          "# Generated code.\n/gen\ndata/*.csv\n!keep.csv\n",
+		"node_modules/.airplaneignore": "ignored\n",
+	} {
+		p := filepath.Join(root, name)
+		require.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(os.WriteFile(p, []byte(content), 0644))
+	}
+
+	patterns, err := Patterns(root)
+	require.NoError(err)
+	require.Equal(append(append([]string{}, defaultExcludes...),
+		"fixtures",
+		"# Generated code.",
+		"/pkg/gen",
+		"/pkg/data/*.csv",
+		"!/pkg/**/keep.csv",
+	), patterns, "expected the file in node_modules to be skipped")
+
+	include, err := Func(root)
+	require.NoError(err)
+	for path, included := range map[string]bool{
+		"main.ts":              true,
+		"fixtures/a.json":      false,
+		"pkg/fixtures/a.json":  false,
+		"pkg/gen/a.go":         false,
+		"gen/a.go":             true,
+		"pkg/lib/gen/a.go":     true,
+		"pkg/data/a.csv":       false,
+		"data/a.csv":           true,
+		"pkg/data/keep.csv":    true,
+		"pkg/lib/data/a.csv":   true,
+		"node_modules/a/a.js":  false,
+		"pkg/.airplaneignore":  true,
+		"pkg/__pycache__/a.py": false,
+	} {
+		p := filepath.Join(root, path)
+		require.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(os.WriteFile(p, nil, 0644))
+		info, err := os.Lstat(p)
+		require.NoError(err)
+		ok, err := include(p, info)
+		require.NoError(err)
+		require.Equal(included, ok, path)
+	}
+}
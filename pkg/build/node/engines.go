@@ -0,0 +1,100 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	buildversions "github.com/airplanedev/cli/pkg/build/versions"
+	"github.com/pkg/errors"
+)
+
+// ResolveNodeVersion returns the Node version that a task in root is built with: the nodeVersion
+// option if it's set, or else the version that satisfies `engines.node` in the package.json of
+// root (see NodeVersionFromEngines), or else the default version.
+func ResolveNodeVersion(root string, opts buildtypes.KindOptions) (string, error) {
+	if nv, _ := opts["nodeVersion"].(string); nv != "" {
+		return nv, nil
+	}
+
+	pkg, err := ReadPackageJSON(filepath.Join(root, "package.json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	version, err := NodeVersionFromEngines(pkg)
+	if err != nil {
+		return "", err
+	}
+	if version == buildtypes.BuildTypeVersionUnspecified {
+		version = buildtypes.DefaultNodeVersion
+	}
+	return string(version), nil
+}
+
+// NodeVersionFromEngines returns the Node version that satisfies the `engines.node` range of pkg:
+// the default version if it satisfies the range, or else the newest supported version that does.
+// Versions are checked against the Node release of their base image, so that ranges like
+// ">=18.10" are resolved correctly. If pkg doesn't set a range, or no supported version satisfies
+// it, an unspecified version is returned.
+func NodeVersionFromEngines(pkg PackageJSON) (buildtypes.BuildTypeVersion, error) {
+	if pkg.Engines == nil || pkg.Engines.NodeVersion == "" {
+		return buildtypes.BuildTypeVersionUnspecified, nil
+	}
+	constraint, err := semver.NewConstraint(pkg.Engines.NodeVersion)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing node engine %s", pkg.Engines.NodeVersion)
+	}
+
+	type candidate struct {
+		version buildtypes.BuildTypeVersion
+		release *semver.Version
+	}
+	var candidates []candidate
+	for _, version := range buildtypes.AllBuildTypeVersions[buildtypes.NodeBuildType] {
+		if version == buildtypes.BuildTypeVersionUnspecified {
+			continue
+		}
+		release, err := nodeRelease(version)
+		if err != nil {
+			return "", err
+		}
+		candidates = append(candidates, candidate{version: version, release: release})
+	}
+	// The default version first, then the newest versions.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iDefault := candidates[i].version == buildtypes.DefaultNodeVersion
+		jDefault := candidates[j].version == buildtypes.DefaultNodeVersion
+		if iDefault != jDefault {
+			return iDefault
+		}
+		return candidates[i].release.GreaterThan(candidates[j].release)
+	})
+
+	for _, c := range candidates {
+		if constraint.Check(c.release) {
+			return c.version, nil
+		}
+	}
+	return buildtypes.BuildTypeVersionUnspecified, nil
+}
+
+// nodeRelease returns the Node release of the base image of a major version, e.g. 20.18.0 for
+// the 20.18.0-bookworm image. If the release isn't known, the major version itself is returned.
+func nodeRelease(version buildtypes.BuildTypeVersion) (*semver.Version, error) {
+	major, err := semver.NewVersion(string(version))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing node version %s", version)
+	}
+	v, err := buildversions.GetVersion(buildtypes.NameNode, string(version), false)
+	if err != nil {
+		return nil, err
+	}
+	tag, _, _ := strings.Cut(v.Tag, "-")
+	if release, err := semver.NewVersion(tag); err == nil && release.Major() == major.Major() {
+		return release, nil
+	}
+	return major, nil
+}
@@ -0,0 +1,66 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeVersionFromEngines(t *testing.T) {
+	testCases := []struct {
+		engines string
+		version buildtypes.BuildTypeVersion
+	}{
+		{engines: "", version: buildtypes.BuildTypeVersionUnspecified},
+		{engines: "18", version: buildtypes.BuildTypeVersionNode18},
+		// The default version is preferred when it satisfies the range.
+		{engines: ">=16", version: buildtypes.BuildTypeVersionNode18},
+		{engines: ">15", version: buildtypes.BuildTypeVersionNode18},
+		{engines: "^20", version: buildtypes.BuildTypeVersionNode20},
+		{engines: "20.x || 22.x", version: buildtypes.BuildTypeVersionNode22},
+		{engines: ">=14 <20", version: buildtypes.BuildTypeVersionNode18},
+		// Ranges are checked against the release of each version's base image.
+		{engines: ">=18.10 <19", version: buildtypes.BuildTypeVersionNode18},
+		{engines: ">=30", version: buildtypes.BuildTypeVersionUnspecified},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.engines, func(t *testing.T) {
+			pkg := PackageJSON{Engines: &PackageJSONEngines{NodeVersion: tC.engines}}
+			version, err := NodeVersionFromEngines(pkg)
+			require.NoError(t, err)
+			require.Equal(t, tC.version, version)
+		})
+	}
+
+	_, err := NodeVersionFromEngines(PackageJSON{Engines: &PackageJSONEngines{NodeVersion: "lts/*"}})
+	require.Error(t, err)
+}
+
+func TestResolveNodeVersion(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+
+	// Without a package.json, the default version is used.
+	version, err := ResolveNodeVersion(root, buildtypes.KindOptions{})
+	require.NoError(err)
+	require.Equal(string(buildtypes.DefaultNodeVersion), version)
+
+	require.NoError(os.WriteFile(filepath.Join(root, "package.json"), []byte(`{"engines": {"node": "^22.0.0"}}`), 0644))
+	version, err = ResolveNodeVersion(root, buildtypes.KindOptions{})
+	require.NoError(err)
+	require.Equal("22", version)
+
+	// An explicit nodeVersion takes precedence over engines.
+	version, err = ResolveNodeVersion(root, buildtypes.KindOptions{"nodeVersion": "18"})
+	require.NoError(err)
+	require.Equal("18", version)
+
+	require.NoError(os.WriteFile(filepath.Join(root, "main.ts"), []byte("export default async () => {}"), 0644))
+	dockerfile, err := Node(root, buildtypes.KindOptions{"shim": "true", "entrypoint": "main.ts"}, nil)
+	require.NoError(err)
+	require.Contains(dockerfile, "--target=node22 ")
+	require.Contains(dockerfile, "node:22.")
+}
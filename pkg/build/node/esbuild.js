@@ -47,6 +47,9 @@ const outfile = process.argv[5] || undefined;
 const outdir = process.argv[6] || undefined;
 const outbase = process.argv[7] || undefined;
 const isView = process.argv[8] || false;
+// Optional esbuild options configured by the user in airplane.yaml. Only a subset of options is
+// supported, so we pick them out explicitly rather than spreading the whole object.
+const options = JSON.parse(process.argv[9] || "{}") || {};
 
 const plugins = [jsdomPatch, removeCSS];
 if (!isView) {
@@ -77,6 +80,11 @@ esbuild
     outdir,
     outbase,
     plugins,
+    define: options.define,
+    loader: options.loader,
+    jsxFactory: options.jsxFactory,
+    sourcemap: options.sourcemap || undefined,
+    minify: options.minify,
   })
   .catch((e) => {
     process.exit(1);
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
@@ -18,6 +19,7 @@ import (
 	"github.com/airplanedev/cli/pkg/deploy/config"
 	"github.com/airplanedev/cli/pkg/deploy/discover/parser"
 	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/alessio/shellescape"
 	"github.com/pkg/errors"
 )
 
@@ -44,7 +46,10 @@ type templateParams struct {
 
 	// For bundle builds, External is a stringified JSON array of external dependencies. For non-bundle builds, External
 	// is a string of space-separated external flags (e.g. --external:package).
-	External     string
+	External string
+	// EsbuildFlags is a string of space-separated, shell-quoted flags for the user-configured
+	// esbuild options. It is only used by non-bundle builds, which invoke the esbuild CLI directly.
+	EsbuildFlags string
 	Args         string
 	UseSlimImage bool
 	Esbuild      string
@@ -65,6 +70,14 @@ type templateParams struct {
 	// FilesToDiscover is a string of space-separated built js files to discover entity configs from.
 	// These files are the output of esbuild on FilesToBuild.
 	FilesToDiscover string
+	// EsbuildOptions is a stringified JSON object of user-configured esbuild options that are
+	// applied when building FilesToBuild.
+	EsbuildOptions string
+
+	// OfflineMount and NpmOfflineFlags install the dependencies of shims from the vendored npm
+	// cache of offline builds. Both are empty for other builds.
+	OfflineMount    string
+	NpmOfflineFlags string
 }
 
 func GetNodeBundleBuildInstructions(
@@ -107,9 +120,15 @@ func GetNodeBundleBuildInstructions(
 			"BUILD_NPM_RC",
 			"BUILD_NPM_TOKEN",
 		},
+		CacheMounts: options[buildtypes.KindOptionCacheMounts] == "true",
+		Secrets:     buildtypes.BuildSecrets(options),
 	}, nil
 }
 
+// npmCacheDir is where npm caches downloaded packages. Yarn v1 cleans its cache after installing,
+// and Yarn Berry stores packages in the project, so neither is cached.
+const npmCacheDir = "/root/.npm"
+
 func GetNodeInstallInstructions(
 	root string,
 	sourceCodeDest string,
@@ -139,12 +158,12 @@ func GetNodeInstallInstructions(
 	if hasDotAirplaneDotYarn {
 		instructions = append(instructions, buildtypes.InstallInstruction{
 			SrcPath: "./.airplane.yarn",
-			DstPath: filepath.Join(sourceCodeDest, ".airplane.yarn") + string(filepath.Separator),
+			DstPath: path.Join(sourceCodeDest, ".airplane.yarn") + "/",
 		})
 	} else if hasDotYarn {
 		instructions = append(instructions, buildtypes.InstallInstruction{
 			SrcPath: "./.yarn",
-			DstPath: filepath.Join(sourceCodeDest, ".yarn") + string(filepath.Separator),
+			DstPath: path.Join(sourceCodeDest, ".yarn") + "/",
 		})
 	}
 	if hasYarnRC {
@@ -197,17 +216,13 @@ func GetNodeInstallInstructions(
 	} else {
 		// Just create an empty package.json in the root
 		instructions = append(instructions, buildtypes.InstallInstruction{
-			Cmd: fmt.Sprintf("echo '{}' > %s", filepath.Join(sourceCodeDest, "package.json")),
+			Cmd: fmt.Sprintf("echo '{}' > %s", path.Join(sourceCodeDest, "package.json")),
 		})
 	}
 
-	var airplaneConfig config.AirplaneConfig
-	hasAirplaneConfig := fsx.Exists(filepath.Join(root, config.FileName))
-	if hasAirplaneConfig {
-		airplaneConfig, err = config.NewAirplaneConfigFromFile(root)
-		if err != nil {
-			return nil, err
-		}
+	airplaneConfig, _, err := config.LoadAirplaneConfig(root)
+	if err != nil {
+		return nil, err
 	}
 
 	preinstall := []buildtypes.InstallInstruction{}
@@ -277,9 +292,13 @@ func GetNodeInstallInstructions(
 		IsYarn:            isYarn,
 		HasPackageLock:    hasPackageLock,
 	})
-	instructions = append(instructions, buildtypes.InstallInstruction{
+	installInstruction := buildtypes.InstallInstruction{
 		Cmd: installCmd,
-	})
+	}
+	if install == "" && !isYarn {
+		installInstruction.CacheDir = npmCacheDir
+	}
+	instructions = append(instructions, installInstruction)
 
 	if !installRequiresCode {
 		instructions = append(instructions, buildtypes.InstallInstruction{
@@ -421,13 +440,9 @@ func Node(
 		}
 	}
 
-	var airplaneConfig config.AirplaneConfig
-	hasAirplaneConfig := fsx.Exists(filepath.Join(root, config.FileName))
-	if hasAirplaneConfig {
-		airplaneConfig, err = config.NewAirplaneConfigFromFile(root)
-		if err != nil {
-			return "", err
-		}
+	airplaneConfig, _, err := config.LoadAirplaneConfig(root)
+	if err != nil {
+		return "", err
 	}
 
 	// Install hooks can only exist in the task root for bundle builds
@@ -452,19 +467,26 @@ func Node(
 		installCommand = airplaneConfig.Javascript.Install
 	}
 
+	nodeVersion, err := ResolveNodeVersion(root, options)
+	if err != nil {
+		return "", err
+	}
+
 	cfg := templateParams{
 		Workdir:        workdir,
 		HasPackageJSON: hasPackageJSON,
 		UsesWorkspaces: pkg.Workspaces != nil && len(pkg.Workspaces.Workspaces) > 0,
 		// esbuild is relatively generous in the node versions it supports:
 		// https://esbuild.github.io/api/#target
-		NodeVersion:        GetNodeVersion(options),
+		NodeVersion:        nodeVersion,
 		PreInstallCommand:  preInstallCommand,
 		PostInstallCommand: postInstallCommand,
 		Args:               makeArgsCommand(buildArgs),
 		IsWorkflow:         isWorkflow,
 		PreInstallPath:     installHooks.PreInstallFilePath,
 		PostInstallPath:    installHooks.PostInstallFilePath,
+		OfflineMount:       offlineMount(IsOffline(options)),
+		NpmOfflineFlags:    npmOfflineFlags(IsOffline(options)),
 	}
 
 	packageJSONs, usesWorkspaces, err := GetPackageJSONs(rootPackageJSON)
@@ -519,6 +541,13 @@ func Node(
 		cfg.External = strings.Join(flags, " ")
 	}
 
+	esbuildConfig, err := ReadEsbuildConfig(root)
+	if err != nil {
+		return "", err
+	}
+	cfg.EsbuildFlags = EsbuildFlags(esbuildConfig)
+
+	cfg.Workdir = filepath.ToSlash(cfg.Workdir)
 	if !strings.HasPrefix(cfg.Workdir, "/") {
 		cfg.Workdir = "/" + cfg.Workdir
 	}
@@ -618,16 +647,16 @@ func Node(
 		# postinstall scripts. We run as root with --unsafe-perm instead, skipping
 		# that lookup. Possibly could fix by building for linux/arm on m1 instead
 		# of always building for linux/amd64.
-		RUN npm install -g esbuild@0.12 --unsafe-perm
+		RUN {{.OfflineMount}}npm install -g {{.NpmOfflineFlags}}esbuild@0.12 --unsafe-perm
 
 		# npm >= 7 will automatically install peer dependencies, even if they're satisfied by the root. This is
 		# problematic because we need the @airplane/workflow-runtime package to register the workflow runtime in the
 		# runtime map that is utilized by the user's code, and so we explicitly request legacy behavior in this
 		# instance, which does not install peer dependencies by default.
-		RUN mkdir -p /airplane/.airplane && \
+		RUN {{.OfflineMount}}mkdir -p /airplane/.airplane && \
 			cd /airplane/.airplane && \
 			{{.InlineShimPackageJSON}} > package.json && \
-			npm install --legacy-peer-deps
+			npm install {{.NpmOfflineFlags}}--legacy-peer-deps
 
 		{{range .PackageCopyCmds}}
 		{{.}}
@@ -669,7 +698,7 @@ func Node(
 		RUN {{.InlineTaskShim}} > /airplane/.airplane/shim.js && \
 			esbuild /airplane/.airplane/shim.js \
 				--bundle \
-				--platform=node {{.External}} \
+				--platform=node {{.External}} {{.EsbuildFlags}} \
 				--target=node{{.NodeVersion}} \
 				--outfile=/airplane/.airplane/dist/shim.js
 
@@ -760,6 +789,65 @@ func GetNodeVersion(opts buildtypes.KindOptions) string {
 	return nv
 }
 
+// GetEsbuildOptions returns the esbuild options configured in the airplane.yaml within root as a
+// stringified JSON object. The result is escaped so that it can be wrapped in single quotes in a
+// Dockerfile.
+func GetEsbuildOptions(root string) (string, error) {
+	esbuildConfig, err := ReadEsbuildConfig(root)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(esbuildConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "marshaling esbuild options")
+	}
+	return strings.ReplaceAll(string(b), "'", `'"'"'`), nil
+}
+
+// ReadEsbuildConfig returns the esbuild options configured in the airplane.yaml within root.
+func ReadEsbuildConfig(root string) (config.EsbuildConfig, error) {
+	airplaneConfig, _, err := config.LoadAirplaneConfig(root)
+	if err != nil {
+		return config.EsbuildConfig{}, err
+	}
+	return airplaneConfig.Javascript.Esbuild, nil
+}
+
+// EsbuildFlags converts the given esbuild options into esbuild CLI flags. Each flag is quoted so
+// that the result can be used in a shell command.
+func EsbuildFlags(c config.EsbuildConfig) string {
+	var flags []string
+	for _, k := range sortedKeys(c.Define) {
+		flags = append(flags, fmt.Sprintf("--define:%s=%s", k, c.Define[k]))
+	}
+	for _, ext := range sortedKeys(c.Loader) {
+		flags = append(flags, fmt.Sprintf("--loader:%s=%s", ext, c.Loader[ext]))
+	}
+	if c.JSXFactory != "" {
+		flags = append(flags, "--jsx-factory="+c.JSXFactory)
+	}
+	if c.Sourcemap != "" {
+		flags = append(flags, "--sourcemap="+c.Sourcemap)
+	}
+	if c.Minify {
+		flags = append(flags, "--minify")
+	}
+	for i, f := range flags {
+		flags[i] = shellescape.Quote(f)
+	}
+	return strings.Join(flags, " ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 //go:embed node-shim.js
 var nodeShim string
 
@@ -801,7 +889,7 @@ func TemplateEntrypoint(script string, params NodeShimParams) (string, error) {
 	// import paths with `.ts` endings. `.js` endings are fine.
 	entrypoint := strings.TrimSuffix(params.Entrypoint, ".ts")
 	// The shim is stored under the .airplane directory.
-	entrypoint = filepath.Join("../", entrypoint)
+	entrypoint = path.Join("..", filepath.ToSlash(entrypoint))
 	// Escape for embedding into a string
 	entrypoint = utils.BackslashEscape(entrypoint, `"`)
 
@@ -852,9 +940,13 @@ func nodeLegacyBuilder(root string, options buildtypes.KindOptions) (string, err
 	default:
 		return "", errors.Errorf("build: unknown language %q, expected \"javascript\" or \"typescript\"", lang)
 	}
-	entrypoint = path.Join(buildWorkdir, entrypoint)
+	entrypoint = path.Join(buildWorkdir, filepath.ToSlash(entrypoint))
 
-	baseImage, err := GetBaseNodeImage(GetNodeVersion(options), false)
+	nodeVersion, err := ResolveNodeVersion(root, options)
+	if err != nil {
+		return "", err
+	}
+	baseImage, err := GetBaseNodeImage(nodeVersion, false)
 	if err != nil {
 		return "", err
 	}
@@ -893,7 +985,7 @@ func GetBaseNodeImage(version string, slim bool) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	base := v.String()
+	base := v.Ref()
 	if base == "" {
 		// Assume the version is already a more-specific version - default to just returning it back
 		base = "node:" + version + "-buster"
@@ -1023,9 +1115,13 @@ func NodeBundle(
 		UserFile     string
 	}
 
+	// Paths are embedded in scripts that run in the container, so they're slash-separated.
+	filesToBuild = utils.ToSlashes(filesToBuild)
+	filesToDiscover = utils.ToSlashes(filesToDiscover)
+
 	var taskImports []TaskImport
 	for _, file := range filesToBuild {
-		fileToBuildExt := filepath.Ext(file)
+		fileToBuildExt := path.Ext(file)
 		compiledFile := strings.TrimSuffix(file, fileToBuildExt) + ".js"
 		taskImports = append(taskImports, TaskImport{
 			CompiledFile: compiledFile,
@@ -1063,12 +1159,14 @@ func NodeBundle(
 		InlineWorkflowShim:               utils.InlineString(universalWorkflowShimTemplated),
 		InlineWorkflowBundlerScript:      utils.InlineString(workflowBundlerScript),
 		InlineWorkflowInterceptorsScript: utils.InlineString(workflowInterceptorsScript),
+		OfflineMount:                     offlineMount(IsOffline(options)),
+		NpmOfflineFlags:                  npmOfflineFlags(IsOffline(options)),
 	}
 
 	// Generate a list of all of the files to build
 	var buildEntrypoints []string
 	for _, fileToBuild := range filesToBuild {
-		buildEntrypoints = append(buildEntrypoints, filepath.Join("/airplane", fileToBuild))
+		buildEntrypoints = append(buildEntrypoints, path.Join("/airplane", fileToBuild))
 	}
 	filesToBuildBytes, err := json.Marshal(buildEntrypoints)
 	if err != nil {
@@ -1079,13 +1177,18 @@ func NodeBundle(
 	// Generate a list of all of the files to discover
 	var discoverEntrypoints []string
 	for _, fileToDiscover := range filesToDiscover {
-		fileToDiscoverExt := filepath.Ext(fileToDiscover)
+		fileToDiscoverExt := path.Ext(fileToDiscover)
 		// esbuild will output entrypoint bundles to /airplane/.airplane
 		discoverEntrypoints = append(discoverEntrypoints,
-			filepath.Join("/airplane/.airplane", strings.TrimSuffix(fileToDiscover, fileToDiscoverExt)+".js"))
+			path.Join("/airplane/.airplane", strings.TrimSuffix(fileToDiscover, fileToDiscoverExt)+".js"))
 	}
 	cfg.FilesToDiscover = strings.Join(discoverEntrypoints, " ")
 
+	cfg.EsbuildOptions, err = GetEsbuildOptions(root)
+	if err != nil {
+		return "", err
+	}
+
 	packageJSONs, usesWorkspaces, err := GetPackageJSONs(rootPackageJSON)
 	if err != nil {
 		return "", err
@@ -1110,6 +1213,7 @@ func NodeBundle(
 		cfg.External = string(externalDepsBytes)
 	}
 
+	cfg.Workdir = filepath.ToSlash(cfg.Workdir)
 	if !strings.HasPrefix(cfg.Workdir, "/") {
 		cfg.Workdir = "/" + cfg.Workdir
 	}
@@ -1144,8 +1248,8 @@ func NodeBundle(
 
 	if len(filesToDiscover) > 0 {
 		// Generate parser and store on context
-		parserPath := path.Join(root, ".airplane-build-tools", "inlineParser.cjs")
-		if err := os.MkdirAll(path.Dir(parserPath), 0755); err != nil {
+		parserPath := filepath.Join(root, ".airplane-build-tools", "inlineParser.cjs")
+		if err := os.MkdirAll(filepath.Dir(parserPath), 0755); err != nil {
 			return "", errors.Wrapf(err, "creating parser file")
 		}
 		if err := os.WriteFile(parserPath, []byte(parser.NodeParserScript), 0755); err != nil {
@@ -1185,10 +1289,10 @@ func NodeBundle(
 		ENV NODE_ENV=production
 		WORKDIR /airplane{{.Workdir}}
 
-		RUN mkdir -p /airplane/.airplane && \
+		RUN {{.OfflineMount}}mkdir -p /airplane/.airplane && \
 			cd /airplane/.airplane && \
 			{{.InlineWorkflowShimPackageJSON}} > package.json && \
-			npm install --legacy-peer-deps
+			npm install {{.NpmOfflineFlags}}--legacy-peer-deps
 
 		RUN {{.InlineWorkerShim}} > /airplane/.airplane/universal-shim.js && \
 			node /airplane/.airplane/esbuild.js \
@@ -1211,10 +1315,10 @@ func NodeBundle(
 		# problematic because we need the @airplane/workflow-runtime package to register the workflow runtime in the
 		# runtime map that is utilized by the user's code, and so we explicitly request legacy behavior in this
 		# instance, which does not install peer dependencies by default.
-		RUN mkdir -p /airplane/.airplane && \
+		RUN {{.OfflineMount}}mkdir -p /airplane/.airplane && \
 			cd /airplane/.airplane && \
 			{{.InlineShimPackageJSON}} > package.json && \
-			npm install --legacy-peer-deps
+			npm install {{.NpmOfflineFlags}}--legacy-peer-deps
 
 		RUN {{.InlineTaskShim}} > /airplane/.airplane/universal-shim.js && \
 			node /airplane/.airplane/esbuild.js \
@@ -1229,7 +1333,9 @@ func NodeBundle(
 			'{{.External}}' \
 			"" \
 			/airplane/.airplane \
-			/airplane
+			/airplane \
+			"" \
+			'{{.EsbuildOptions}}'
 
 		# Discover inline tasks now that dependencies are installed and entrypoint files
 		# are built.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -101,24 +102,25 @@ func GetPackageCopyInstructions(baseDir string, pathPackageJSONs []string, dest
 		if err != nil {
 			return nil, errors.Wrap(err, "generating relative path")
 		}
-		srcPaths[relPackageDir] = struct{}{}
+		// Paths in Dockerfiles are slash-separated.
+		srcPaths[filepath.ToSlash(relPackageDir)] = struct{}{}
 	}
 
 	for srcPath := range srcPaths {
-		destPath := filepath.Join(dest, srcPath)
+		destPath := path.Join(dest, srcPath)
 		// Docker requires that the destination ends with a slash
-		if !strings.HasSuffix(destPath, string(filepath.Separator)) {
-			destPath = destPath + string(filepath.Separator)
+		if !strings.HasSuffix(destPath, "/") {
+			destPath = destPath + "/"
 		}
 
 		copyInstructions = append(
 			copyInstructions,
 			buildtypes.InstallInstruction{
 				SrcPath: fmt.Sprintf("%s %s",
-					filepath.Join(srcPath, "package*.json"),
+					path.Join(srcPath, "package*.json"),
 					// As long as there's a match for the previous glob,
 					// Docker won't complain if there aren't any matches for this one.
-					filepath.Join(srcPath, "yarn.*"),
+					path.Join(srcPath, "yarn.*"),
 				),
 				DstPath: destPath,
 			})
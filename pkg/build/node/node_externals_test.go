@@ -1,6 +1,7 @@
 package node
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/airplanedev/cli/pkg/build/fixtures"
@@ -130,6 +131,29 @@ func TestGetPackageCopyCmds(t *testing.T) {
 	)
 }
 
+func TestGetPackageCopyCmdsPaths(t *testing.T) {
+	// Host paths use the OS separator, but Dockerfile paths are always slash-separated.
+	base := filepath.Join(t.TempDir(), "base")
+	result, err := GetPackageCopyCmds(
+		base,
+		[]string{
+			filepath.Join(base, "package.json"),
+			filepath.Join(base, "packages", "tasks", "package.json"),
+		},
+		"/airplane",
+	)
+	require.NoError(t, err)
+
+	require.Equal(
+		t,
+		[]string{
+			"COPY package*.json yarn.* /airplane/",
+			"COPY packages/tasks/package*.json packages/tasks/yarn.* /airplane/packages/tasks/",
+		},
+		result,
+	)
+}
+
 func TestGetYarnLockPackageVersion(t *testing.T) {
 	version, err := getYarnLockPackageVersion(
 		fixtures.Path(t, "node_externals/yarnworkspace"),
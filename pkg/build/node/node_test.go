@@ -0,0 +1,39 @@
+package node
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/deploy/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplatedNodeShimPaths(t *testing.T) {
+	require := require.New(t)
+
+	// Entrypoints are host paths, but imports in the shim are slash-separated.
+	shim, err := TemplatedNodeShim(NodeShimParams{
+		Entrypoint: filepath.Join("tasks", "my_task.ts"),
+	})
+	require.NoError(err)
+	require.Contains(shim, `import task from "./../tasks/my_task";`)
+}
+
+func TestEsbuildFlags(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("", EsbuildFlags(config.EsbuildConfig{}))
+	require.Equal(
+		`'--define:process.env.A="a b"' --define:process.env.B=true --loader:.svg=text --jsx-factory=h --sourcemap=inline --minify`,
+		EsbuildFlags(config.EsbuildConfig{
+			Define: map[string]string{
+				"process.env.B": "true",
+				"process.env.A": `"a b"`,
+			},
+			Loader:     map[string]string{".svg": "text"},
+			JSXFactory: "h",
+			Sourcemap:  "inline",
+			Minify:     true,
+		}),
+	)
+}
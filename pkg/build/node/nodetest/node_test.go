@@ -960,6 +960,54 @@ func TestNodeBundleBuilder(t *testing.T) {
 	build.RunTests(t, ctx, tests)
 }
 
+// TestNodeVersions builds and runs the shims against every supported Node version so that adding a
+// version can't silently break its base image or esbuild target.
+func TestNodeVersions(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []build.Test{
+		{
+			Root: "typescript/simple",
+			Kind: buildtypes.TaskKindNode,
+			Options: buildtypes.KindOptions{
+				"shim":       "true",
+				"entrypoint": "main.ts",
+			},
+		},
+		{
+			Root: "typescript/slim",
+			Kind: buildtypes.TaskKindNode,
+			Options: buildtypes.KindOptions{
+				"shim":       "true",
+				"entrypoint": "main.ts",
+				"base":       buildtypes.BuildBaseSlim,
+			},
+		},
+		{
+			Root: "typescript/simple",
+			Kind: buildtypes.TaskKindNode,
+			Options: buildtypes.KindOptions{
+				"shim": "true",
+			},
+			Bundle: true,
+			BuildContext: buildtypes.BuildContext{
+				Type: buildtypes.NodeBuildType,
+			},
+			FilesToBuild: []string{
+				"main.ts",
+			},
+			BundleRuns: []build.BundleTestRun{
+				{
+					RelEntrypoint: "main.js",
+					ExportName:    "default",
+				},
+			},
+		},
+	}
+
+	build.RunTests(t, ctx, build.NodeVersionMatrix(tests))
+}
+
 func TestNodeWorkflowBuilder(t *testing.T) {
 	ctx := context.Background()
 
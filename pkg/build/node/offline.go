@@ -0,0 +1,93 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/pkg/errors"
+)
+
+// OfflineCacheDir is where the vendored npm cache of offline builds is placed in the build context.
+const OfflineCacheDir = ".airplane/offline-cache"
+
+// offlineCacheMount is where the vendored npm cache is mounted while installing the dependencies
+// of shims. The mount is writable so that npm can update its index, but writes are discarded.
+const offlineCacheMount = "/tmp/airplane-offline-cache"
+
+// legacyEsbuildVersion is the version of esbuild that non-bundle Node builds install globally.
+const legacyEsbuildVersion = "0.12"
+
+// IsOffline returns true if the build installs the dependencies of shims from the vendored npm
+// cache, see OfflineCacheDir.
+func IsOffline(opts buildtypes.KindOptions) bool {
+	return opts[buildtypes.KindOptionOffline] == "true"
+}
+
+// offlineMount returns the flag that mounts the vendored npm cache into a RUN instruction of an
+// offline build, followed by a space. The mount requires BuildKit.
+func offlineMount(offline bool) string {
+	if !offline {
+		return ""
+	}
+	return "--mount=type=bind,source=" + OfflineCacheDir + ",target=" + offlineCacheMount + ",rw "
+}
+
+// npmOfflineFlags returns the flags that make npm install from the vendored npm cache in an
+// offline build, followed by a space.
+func npmOfflineFlags(offline bool) string {
+	if !offline {
+		return ""
+	}
+	return "--offline --cache " + offlineCacheMount + " "
+}
+
+// PopulateOfflineCache downloads the dependencies of shims into an npm cache at dir, so that it
+// can be vendored into the build context of offline builds. It requires npm and registry access,
+// and should be run whenever the CLI is upgraded.
+//
+// Platform-specific esbuild packages are cached for linux/amd64, the platform that images are
+// built for, regardless of the platform that the cache is populated on.
+func PopulateOfflineCache(ctx context.Context, dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return errors.Wrap(err, "determining absolute path")
+	}
+
+	var buildTools PackageJSON
+	if err := json.Unmarshal([]byte(BuildToolsPackageJSON), &buildTools); err != nil {
+		return errors.Wrap(err, "unmarshaling build tools package.json")
+	}
+
+	tmpdir, err := os.MkdirTemp("", "airplane-offline-cache-*")
+	if err != nil {
+		return errors.Wrap(err, "creating temporary directory")
+	}
+	defer os.RemoveAll(tmpdir)
+	if err := os.WriteFile(filepath.Join(tmpdir, "package.json"), []byte(BuildToolsPackageJSON), 0644); err != nil {
+		return errors.Wrap(err, "writing package.json")
+	}
+
+	// Installing the build tools caches every package that the dependencies of shims resolve to.
+	// Scripts are skipped since only the downloaded packages are needed.
+	if err := runNPM(ctx, tmpdir, "install", "--cache", dir, "--ignore-scripts", "--legacy-peer-deps"); err != nil {
+		return err
+	}
+	return runNPM(ctx, tmpdir, "cache", "add", "--cache", dir,
+		"esbuild@"+legacyEsbuildVersion,
+		"esbuild-linux-64@"+legacyEsbuildVersion,
+		"@esbuild/linux-x64@"+buildTools.Dependencies["esbuild"],
+	)
+}
+
+func runNPM(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "npm", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "running npm %s:\n%s", args[0], out)
+	}
+	return nil
+}
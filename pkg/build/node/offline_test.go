@@ -0,0 +1,49 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineDockerfile(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(root, "main.ts"), []byte("export default async () => {}"), 0644))
+
+	mount := "--mount=type=bind,source=.airplane/offline-cache,target=/tmp/airplane-offline-cache,rw "
+	flags := "--offline --cache /tmp/airplane-offline-cache "
+
+	dockerfile, err := Node(root, buildtypes.KindOptions{
+		"shim":       "true",
+		"entrypoint": "main.ts",
+	}, nil)
+	require.NoError(err)
+	require.NotContains(dockerfile, "--offline")
+	require.NotContains(dockerfile, "offline-cache")
+
+	dockerfile, err = Node(root, buildtypes.KindOptions{
+		"shim":       "true",
+		"entrypoint": "main.ts",
+		"offline":    "true",
+	}, nil)
+	require.NoError(err)
+	require.Contains(dockerfile, "RUN "+mount+"npm install -g "+flags+"esbuild@0.12 --unsafe-perm")
+	require.Contains(dockerfile, "RUN "+mount+"mkdir -p /airplane/.airplane")
+	require.Contains(dockerfile, "npm install "+flags+"--legacy-peer-deps")
+
+	dockerfile, err = NodeBundle(root, buildtypes.BuildContext{
+		Type: buildtypes.NodeBuildType,
+	}, buildtypes.KindOptions{
+		"shim":    "true",
+		"offline": "true",
+	}, nil, []string{"main.ts"}, nil)
+	require.NoError(err)
+	require.Equal(2, strings.Count(dockerfile, "npm install "+flags+"--legacy-peer-deps"), "expected both the task and workflow shims to install offline")
+	require.Equal(2, strings.Count(dockerfile, "RUN "+mount))
+}
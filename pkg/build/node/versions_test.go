@@ -0,0 +1,59 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	esbuild "github.com/evanw/esbuild/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSupportedNodeVersions checks, without Docker, that every supported Node version resolves to
+// a base image and produces a Dockerfile whose shim bundles for the matching esbuild target. The
+// Docker-based equivalent is TestNodeVersions in nodetest.
+func TestSupportedNodeVersions(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.ts"), []byte("export default async () => {}"), 0644))
+
+	for _, version := range buildtypes.AllBuildTypeVersions[buildtypes.NodeBuildType] {
+		if version == buildtypes.BuildTypeVersionUnspecified {
+			continue
+		}
+		v := string(version)
+		t.Run("node"+v, func(t *testing.T) {
+			require := require.New(t)
+
+			for _, slim := range []bool{false, true} {
+				base, err := GetBaseNodeImage(v, slim)
+				require.NoError(err)
+				// Every supported version must be in versions.json rather than falling back to a
+				// guessed tag.
+				require.Contains(base, "registry.hub.docker.com/library/node")
+				if !strings.Contains(base, "@sha256:") {
+					require.Contains(base, ":"+v+".")
+					require.Equal(slim, strings.HasSuffix(base, "-slim"))
+				}
+			}
+
+			dockerfile, err := Node(root, buildtypes.KindOptions{
+				"shim":        "true",
+				"entrypoint":  "main.ts",
+				"nodeVersion": v,
+			}, nil)
+			require.NoError(err)
+			require.Contains(dockerfile, "--target=node"+v+" ")
+
+			res := esbuild.Transform(UniversalNodeShim, esbuild.TransformOptions{
+				Platform: esbuild.PlatformNode,
+				Format:   esbuild.FormatCommonJS,
+				Engines: []esbuild.Engine{
+					{Name: esbuild.EngineNode, Version: v},
+				},
+			})
+			require.Empty(res.Errors)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package build
+
+import (
+	"github.com/airplanedev/cli/pkg/build/node"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/build/utils"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/pkg/errors"
+)
+
+// offlineKindOptions returns the kind options of a build, marked as offline if the build has a
+// vendored npm cache.
+func offlineKindOptions(options buildtypes.KindOptions, offlineCache string) buildtypes.KindOptions {
+	if offlineCache == "" {
+		return options
+	}
+	offline := buildtypes.KindOptions{}
+	for k, v := range options {
+		offline[k] = v
+	}
+	offline[buildtypes.KindOptionOffline] = "true"
+	return offline
+}
+
+// addOfflineCache vendors the npm cache at offlineCache into the build context, if set. It's
+// added after the task's files so that they can't replace it.
+func addOfflineCache(tree *utils.Tree, offlineCache string) error {
+	if offlineCache == "" {
+		return nil
+	}
+	if !fsx.Exists(offlineCache) {
+		return errors.Errorf("offline cache %s does not exist", offlineCache)
+	}
+	return errors.Wrap(tree.CopyTo(offlineCache, node.OfflineCacheDir), "vendoring offline cache")
+}
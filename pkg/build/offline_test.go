@@ -0,0 +1,52 @@
+package build
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/build/node"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/build/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineCache(t *testing.T) {
+	require := require.New(t)
+
+	options := buildtypes.KindOptions{"shim": "true"}
+	require.Equal(options, offlineKindOptions(options, ""))
+	require.Equal(buildtypes.KindOptions{"shim": "true", "offline": "true"}, offlineKindOptions(options, "/cache"))
+	require.Equal(buildtypes.KindOptions{"shim": "true"}, options, "expected the options to be copied")
+
+	cache := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(cache, "_cacache"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(cache, "_cacache", "index"), []byte("airplane"), 0644))
+
+	tree, err := utils.NewTree(utils.TreeOptions{ExcludePatterns: []string{"_cacache"}})
+	require.NoError(err)
+	defer tree.Close()
+	require.NoError(addOfflineCache(tree, ""))
+	require.NoError(addOfflineCache(tree, cache))
+	require.ErrorContains(addOfflineCache(tree, filepath.Join(cache, "missing")), "does not exist")
+
+	r, err := tree.Archive()
+	require.NoError(err)
+	defer r.Close()
+	gz, err := gzip.NewReader(r)
+	require.NoError(err)
+	var files []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		files = append(files, hdr.Name)
+	}
+	require.Contains(files, node.OfflineCacheDir+"/_cacache/index", "expected the cache to be vendored regardless of excludes")
+}
@@ -5,7 +5,6 @@ import (
 	_ "embed"
 	"fmt"
 	"os"
-	"path"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -54,6 +53,12 @@ func GetPythonBundleBuildInstructions(
 	return getPythonBuildInstructionsInternal(root, opts, shim, installHooks)
 }
 
+// pipCacheDir is where pip caches downloaded packages.
+const pipCacheDir = "/root/.cache/pip"
+
+// poetryCacheDir is where Poetry caches the packages that it resolves while locking.
+const poetryCacheDir = "/root/.cache/pypoetry"
+
 func getPythonBuildInstructionsInternal(
 	root string,
 	opts buildtypes.KindOptions,
@@ -66,7 +71,11 @@ func getPythonBuildInstructionsInternal(
 
 	instructions := []buildtypes.InstallInstruction{
 		{
-			Cmd: `pip install "airplanesdk>=0.3.0,<0.4.0"`,
+			Cmd: fmt.Sprintf(`pip install "%s%s"`,
+				buildtypes.SDKPackages[buildtypes.PythonBuildType],
+				strings.ReplaceAll(buildtypes.SDKConstraints[buildtypes.PythonBuildType], " ", ""),
+			),
+			CacheDir: pipCacheDir,
 		},
 	}
 	if shim != "" {
@@ -77,14 +86,11 @@ func getPythonBuildInstructionsInternal(
 
 	preinstall := []buildtypes.InstallInstruction{}
 	postinstall := []buildtypes.InstallInstruction{}
-	var airplaneConfig config.AirplaneConfig
-	hasAirplaneConfig := fsx.Exists(filepath.Join(root, config.FileName))
+	airplaneConfig, hasAirplaneConfig, err := config.LoadAirplaneConfig(root)
+	if err != nil {
+		return buildtypes.BuildInstructions{}, err
+	}
 	if hasAirplaneConfig {
-		var err error
-		airplaneConfig, err = config.NewAirplaneConfigFromFile(root)
-		if err != nil {
-			return buildtypes.BuildInstructions{}, err
-		}
 		if airplaneConfig.Python.PreInstall != "" {
 			preinstall = append(preinstall, buildtypes.InstallInstruction{
 				Cmd: airplaneConfig.Python.PreInstall,
@@ -116,10 +122,23 @@ func getPythonBuildInstructionsInternal(
 
 	instructions = append(instructions, preinstall...)
 
+	packageManager, err := getPackageManager(root, opts)
+	if err != nil {
+		return buildtypes.BuildInstructions{}, err
+	}
+	if packageManager == buildtypes.PythonPackageManagerPoetry {
+		instructions = append(instructions, poetryInstructions(root)...)
+		instructions = append(instructions, postinstall...)
+		return buildtypes.BuildInstructions{
+			InstallInstructions: instructions,
+			CacheMounts:         opts[buildtypes.KindOptionCacheMounts] == "true",
+			Secrets:             buildtypes.BuildSecrets(opts),
+		}, nil
+	}
+
 	requirementsPath := filepath.Join(root, "requirements.txt")
 	hasRequirements := fsx.Exists(requirementsPath)
 	var embeddedRequirements []string
-	var err error
 	if hasRequirements {
 		instructions = append(instructions, buildtypes.InstallInstruction{
 			SrcPath: "requirements.txt",
@@ -141,7 +160,8 @@ func getPythonBuildInstructionsInternal(
 		}
 
 		instructions = append(instructions, buildtypes.InstallInstruction{
-			Cmd: `pip install -r requirements.txt`,
+			Cmd:      `pip install -r requirements.txt`,
+			CacheDir: pipCacheDir,
 		})
 	}
 
@@ -149,6 +169,8 @@ func getPythonBuildInstructionsInternal(
 
 	return buildtypes.BuildInstructions{
 		InstallInstructions: instructions,
+		CacheMounts:         opts[buildtypes.KindOptionCacheMounts] == "true",
+		Secrets:             buildtypes.BuildSecrets(opts),
 	}, nil
 }
 
@@ -178,7 +200,7 @@ func Python(
 	entrypointFunc, _ := opts["entrypointFunc"].(string)
 	shim, err := PythonShim(PythonShimParams{
 		TaskRoot:       "/airplane",
-		Entrypoint:     entrypoint,
+		Entrypoint:     filepath.ToSlash(entrypoint),
 		EntrypointFunc: entrypointFunc,
 	})
 	if err != nil {
@@ -237,6 +259,104 @@ func Python(
 	return df, nil
 }
 
+// getPackageManager returns the package manager that installs the dependencies of the task root,
+// either as set by the pythonPackageManager option or as detected from the files in root.
+func getPackageManager(root string, opts buildtypes.KindOptions) (buildtypes.PythonPackageManager, error) {
+	var packageManager buildtypes.PythonPackageManager
+	switch v := opts["pythonPackageManager"].(type) {
+	case buildtypes.PythonPackageManager:
+		packageManager = v
+	case string:
+		packageManager = buildtypes.PythonPackageManager(v)
+	}
+
+	switch packageManager {
+	case buildtypes.PythonPackageManagerPip:
+		return packageManager, nil
+	case buildtypes.PythonPackageManagerPoetry:
+		if !fsx.Exists(filepath.Join(root, "pyproject.toml")) {
+			return "", errors.Errorf("pythonPackageManager is poetry, but %s has no pyproject.toml", root)
+		}
+		return packageManager, nil
+	case buildtypes.PythonPackageManagerDefault:
+		if fsx.Exists(filepath.Join(root, "poetry.lock")) {
+			return buildtypes.PythonPackageManagerPoetry, nil
+		}
+		isPoetry, err := hasPoetryTable(filepath.Join(root, "pyproject.toml"))
+		if err != nil {
+			return "", err
+		}
+		if isPoetry {
+			return buildtypes.PythonPackageManagerPoetry, nil
+		}
+		return buildtypes.PythonPackageManagerPip, nil
+	default:
+		return "", errors.Errorf("unsupported pythonPackageManager %q: expected pip or poetry", packageManager)
+	}
+}
+
+// hasPoetryTable returns true if the pyproject.toml at path configures Poetry. Other tools, e.g.
+// setuptools, also read pyproject.toml.
+func hasPoetryTable(path string) (bool, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrap(err, "opening pyproject.toml")
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "[tool.poetry]" || strings.HasPrefix(line, "[tool.poetry.") {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, errors.Wrap(err, "reading pyproject.toml")
+	}
+	return false, nil
+}
+
+// poetryInstructions installs the dependencies of a Poetry project. Poetry is installed in its own
+// virtual environment, which is removed once the dependencies are exported to a requirements file
+// and installed with pip, so that Poetry's dependencies can't conflict with the task's. Exporting
+// and installing are separate instructions so that each caches its downloads in its own CacheDir.
+func poetryInstructions(root string) []buildtypes.InstallInstruction {
+	instructions := []buildtypes.InstallInstruction{
+		{SrcPath: "pyproject.toml"},
+	}
+	cmds := []string{
+		"python -m venv /tmp/poetry",
+		`/tmp/poetry/bin/pip install --quiet "poetry>=1.8.0,<2.0.0" "poetry-plugin-export>=1.6.0"`,
+	}
+	if fsx.Exists(filepath.Join(root, "poetry.lock")) {
+		instructions = append(instructions, buildtypes.InstallInstruction{SrcPath: "poetry.lock"})
+	} else {
+		// Export requires a lock file.
+		cmds = append(cmds, "/tmp/poetry/bin/poetry lock --no-update")
+	}
+	if fsx.Exists(filepath.Join(root, "pip.conf")) {
+		instructions = append(instructions, buildtypes.InstallInstruction{SrcPath: "pip.conf"})
+	}
+	cmds = append(cmds,
+		"/tmp/poetry/bin/poetry export --without-hashes --format requirements.txt --output /tmp/requirements.txt",
+		"rm -rf /tmp/poetry",
+	)
+	instructions = append(instructions,
+		buildtypes.InstallInstruction{
+			Cmd:      strings.Join(cmds, " && "),
+			CacheDir: poetryCacheDir,
+		},
+		buildtypes.InstallInstruction{
+			Cmd:      "pip install -r /tmp/requirements.txt && rm /tmp/requirements.txt",
+			CacheDir: pipCacheDir,
+		},
+	)
+	return instructions
+}
+
 func collectEmbeddedRequirements(root, requirementsPath string) ([]string, error) {
 	var embeddedRequirements []string
 	file, err := os.Open(requirementsPath)
@@ -306,13 +426,13 @@ func PythonBundle(
 	argsCommand := strings.Join(args, "\n")
 
 	// Add build tools.
-	buildToolsPath := path.Join(root, ".airplane-build-tools")
+	buildToolsPath := filepath.Join(root, ".airplane-build-tools")
 	if err := os.MkdirAll(buildToolsPath, 0755); err != nil {
 		return "", errors.Wrapf(err, "creating build tools path")
 	}
 	if len(filesToDiscover) > 0 {
 		// Generate parser and store on context
-		parserPath := path.Join(buildToolsPath, "inlineParser.py")
+		parserPath := filepath.Join(buildToolsPath, "inlineParser.py")
 		if err := os.WriteFile(parserPath, []byte(parser.PythonParserScript), 0755); err != nil {
 			return "", errors.Wrap(err, "writing parser script")
 		}
@@ -360,7 +480,7 @@ func PythonBundle(
 		Base:            v.String(),
 		Args:            argsCommand,
 		Instructions:    dockerfileInstructions,
-		FilesToDiscover: strings.Join(filesToDiscover, " "),
+		FilesToDiscover: strings.Join(utils.ToSlashes(filesToDiscover), " "),
 	})
 	if err != nil {
 		return "", errors.Wrapf(err, "rendering dockerfile")
@@ -456,7 +576,7 @@ func pythonLegacy(root string, args buildtypes.KindOptions) (string, error) {
 		InstallInstructions []buildtypes.InstallInstruction
 	}{
 		Base:                v.String(),
-		Entrypoint:          entrypoint,
+		Entrypoint:          filepath.ToSlash(entrypoint),
 		InstallInstructions: instructions.InstallInstructions,
 	}); err != nil {
 		return "", err
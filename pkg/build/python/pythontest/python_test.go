@@ -2,10 +2,15 @@ package pythontest
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/airplanedev/cli/pkg/build"
+	"github.com/airplanedev/cli/pkg/build/python"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPythonBuilder(t *testing.T) {
@@ -86,6 +91,15 @@ func TestPythonBuilder(t *testing.T) {
 			},
 			SearchString: "preinstall='hello from preinstall' postinstall='hello from postinstall'",
 		},
+		{
+			Root: "python/poetry",
+			Kind: buildtypes.TaskKindPython,
+			Options: buildtypes.KindOptions{
+				"shim":       "true",
+				"entrypoint": "main.py",
+			},
+			SearchString: "[1]",
+		},
 	}
 
 	build.RunTests(t, ctx, tests)
@@ -312,3 +326,85 @@ func TestPythonBundleBuilder(t *testing.T) {
 
 	build.RunTests(t, ctx, tests)
 }
+
+func TestPythonPackageManager(t *testing.T) {
+	for _, test := range []struct {
+		name           string
+		files          map[string]string
+		packageManager buildtypes.PythonPackageManager
+		poetry         bool
+		err            string
+	}{
+		{
+			name:  "requirements.txt",
+			files: map[string]string{"requirements.txt": "dice == 3.1.2"},
+		},
+		{
+			name:   "poetry.lock",
+			files:  map[string]string{"pyproject.toml": "", "poetry.lock": ""},
+			poetry: true,
+		},
+		{
+			name:   "pyproject.toml",
+			files:  map[string]string{"pyproject.toml": "[tool.poetry.dependencies]\ndice = \"3.1.2\"\n"},
+			poetry: true,
+		},
+		{
+			name:  "setuptools",
+			files: map[string]string{"pyproject.toml": "[project]\nname = \"task\"\n"},
+		},
+		{
+			name:           "explicit pip",
+			files:          map[string]string{"pyproject.toml": "[tool.poetry]\n", "requirements.txt": ""},
+			packageManager: buildtypes.PythonPackageManagerPip,
+		},
+		{
+			name:           "explicit poetry",
+			files:          map[string]string{"pyproject.toml": ""},
+			packageManager: buildtypes.PythonPackageManagerPoetry,
+			poetry:         true,
+		},
+		{
+			name:           "explicit poetry without pyproject.toml",
+			files:          map[string]string{"requirements.txt": ""},
+			packageManager: buildtypes.PythonPackageManagerPoetry,
+			err:            "has no pyproject.toml",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			root := t.TempDir()
+			for name, content := range test.files {
+				require.NoError(os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+			}
+
+			opts := buildtypes.KindOptions{"shim": "true", buildtypes.KindOptionCacheMounts: "true"}
+			if test.packageManager != "" {
+				opts["pythonPackageManager"] = test.packageManager
+			}
+			instructions, err := python.GetPythonBundleBuildInstructions(root, opts, "")
+			if test.err != "" {
+				require.ErrorContains(err, test.err)
+				return
+			}
+			require.NoError(err)
+
+			dockerfile, err := instructions.DockerfileString()
+			require.NoError(err)
+			if test.poetry {
+				require.Contains(dockerfile, "COPY pyproject.toml")
+				require.Contains(dockerfile, "poetry export")
+				require.NotContains(dockerfile, "pip install -r requirements.txt")
+				_, hasLock := test.files["poetry.lock"]
+				require.Equal(hasLock, strings.Contains(dockerfile, "COPY poetry.lock"))
+				require.Equal(!hasLock, strings.Contains(dockerfile, "poetry lock"))
+				require.Contains(dockerfile, "--mount=type=cache,target=/root/.cache/pypoetry")
+				require.Contains(dockerfile, "--mount=type=cache,target=/root/.cache/pip pip install -r /tmp/requirements.txt")
+			} else {
+				require.NotContains(dockerfile, "poetry")
+				_, hasRequirements := test.files["requirements.txt"]
+				require.Equal(hasRequirements, strings.Contains(dockerfile, "pip install -r requirements.txt"))
+			}
+		})
+	}
+}
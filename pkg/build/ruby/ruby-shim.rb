@@ -0,0 +1,57 @@
+# This file includes a shim that will execute your task code.
+
+require "json"
+
+MAIN_EXAMPLE = <<~RUBY
+  ```
+  def main(params)
+    puts params
+  end
+  ```
+RUBY
+
+def set_output(value, path = nil)
+  prefix = path ? "airplane_output_set:#{path}" : "airplane_output_set"
+  $stdout.puts "#{prefix} #{JSON.generate(value)}"
+  $stdout.flush
+end
+
+def run(args)
+  if args.length != 2
+    err_msg = "usage: ruby ./shim.rb <entrypoint> <args>"
+    warn err_msg
+    set_output(err_msg, "error")
+    exit 1
+  end
+
+  Dir.chdir("{{.TaskRoot}}")
+  # Load the gems of the task's Gemfile, as `bundle exec` would.
+  require "bundler/setup" if File.exist?("Gemfile")
+
+  params = JSON.parse(args[1])
+  load File.expand_path(args[0])
+
+  unless Object.private_method_defined?(:main) || Object.method_defined?(:main)
+    raise "Task is missing a `main` method. Add a main method like so and re-deploy:\n#{MAIN_EXAMPLE}"
+  end
+  main_method = method(:main)
+  ret =
+    if main_method.arity == 0 && params.empty?
+      main_method.call
+    elsif main_method.arity == 1 || main_method.arity == -1
+      main_method.call(params)
+    else
+      raise "`main` method must have exactly 1 parameter, found #{main_method.arity}. Update the main method like so and re-deploy:\n#{MAIN_EXAMPLE}"
+    end
+
+  set_output(ret) unless ret.nil?
+end
+
+begin
+  $stdout.sync = true
+  run(ARGV)
+rescue StandardError, ScriptError => e
+  warn e.full_message(highlight: false)
+  set_output(e.message, "error")
+  exit 1
+end
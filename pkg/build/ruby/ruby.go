@@ -0,0 +1,148 @@
+package ruby
+
+import (
+	_ "embed"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/build/utils"
+	buildversions "github.com/airplanedev/cli/pkg/build/versions"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/pkg/errors"
+)
+
+// Ruby creates a Dockerfile for a Ruby task.
+//
+// If the task root has a Gemfile, the task's gems are installed before copying in the rest of the
+// task, so that they're cached until the Gemfile or Gemfile.lock change. The task is run by a shim
+// that passes the task's parameters to its main method.
+func Ruby(
+	root string,
+	options buildtypes.KindOptions,
+	buildArgs []string,
+) (string, error) {
+	entrypoint, _ := options["entrypoint"].(string)
+	if entrypoint == "" {
+		return "", errors.New("expected an entrypoint")
+	}
+	if err := fsx.AssertExistsAll(filepath.Join(root, entrypoint)); err != nil {
+		return "", err
+	}
+
+	base, err := GetBaseRubyImage(GetRubyVersion(options))
+	if err != nil {
+		return "", err
+	}
+
+	return dockerfile(root, base, buildArgs, heredoc.Doc(`
+		ENTRYPOINT ["ruby", ".airplane/shim.rb", "{{.Entrypoint}}"]
+	`), entrypoint)
+}
+
+// RubyBundle creates a Dockerfile that can run every Ruby task in root. Each task is run by
+// passing its entrypoint to the shim, see the Ruby definition's copyToTask.
+func RubyBundle(
+	root string,
+	buildContext buildtypes.BuildContext,
+	buildArgs []string,
+) (string, error) {
+	base, err := GetBaseRubyImage(string(buildContext.VersionOrDefault()))
+	if err != nil {
+		return "", err
+	}
+
+	return dockerfile(root, base, buildArgs, heredoc.Doc(`
+		# Set an empty entrypoint to override any entrypoints that may be set in the base image.
+		ENTRYPOINT []
+	`), "")
+}
+
+func dockerfile(root, base string, buildArgs []string, entrypointInstructions, entrypoint string) (string, error) {
+	shim, err := RubyShim("/airplane")
+	if err != nil {
+		return "", err
+	}
+
+	return utils.ApplyTemplate(heredoc.Doc(`
+		FROM {{.Base}}
+
+		WORKDIR /airplane
+
+		{{.Args}}
+
+		{{- if .HasGemfile}}
+
+		COPY Gemfile Gemfile.lock* ./
+		RUN bundle install
+		{{- end}}
+
+		RUN mkdir -p .airplane && {{.InlineShim}} > .airplane/shim.rb
+
+		COPY . .
+	`)+entrypointInstructions, struct {
+		Base       string
+		Args       string
+		HasGemfile bool
+		InlineShim string
+		Entrypoint string
+	}{
+		Base:       base,
+		Args:       makeArgsCommand(buildArgs),
+		HasGemfile: fsx.Exists(filepath.Join(root, "Gemfile")),
+		InlineShim: utils.InlineString(shim),
+		Entrypoint: utils.BackslashEscape(filepath.ToSlash(entrypoint), `"`),
+	})
+}
+
+//go:embed ruby-shim.rb
+var rubyShim string
+
+// RubyShim generates a shim file for running Ruby tasks from taskRoot. The shim is passed the
+// entrypoint of the task and its JSON-encoded parameters.
+func RubyShim(taskRoot string) (string, error) {
+	shim, err := utils.ApplyTemplate(rubyShim, struct {
+		TaskRoot string
+	}{
+		TaskRoot: utils.BackslashEscape(taskRoot, `"`),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "rendering shim")
+	}
+
+	return shim, nil
+}
+
+// GetRubyVersion returns the Ruby version configured by opts, if any.
+func GetRubyVersion(opts buildtypes.KindOptions) string {
+	v, _ := opts["rubyVersion"].(string)
+	return v
+}
+
+// GetBaseRubyImage returns the image that Ruby tasks are run on for the given Ruby version. If
+// version is empty, the default version is used.
+func GetBaseRubyImage(version string) (string, error) {
+	if version == "" {
+		version = string(buildtypes.DefaultRubyVersion)
+	}
+	v, err := buildversions.GetVersion(buildtypes.NameRuby, version, false)
+	if err != nil {
+		return "", err
+	}
+	base := v.Ref()
+	if base == "" {
+		// Assume the version is already a more-specific version - default to just returning it back
+		base = "ruby:" + version + "-bookworm"
+	}
+	return base, nil
+}
+
+func makeArgsCommand(buildArgs []string) string {
+	args := make([]string, len(buildArgs))
+	for i, a := range buildArgs {
+		args[i] = fmt.Sprintf("ARG %s", a)
+	}
+	return strings.Join(args, "\n")
+}
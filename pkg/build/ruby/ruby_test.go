@@ -0,0 +1,72 @@
+package ruby
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuby(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "tasks"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "tasks", "hello.rb"), []byte("def main(params)\nend\n"), 0644))
+
+	for _, version := range buildtypes.AllBuildTypeVersions[buildtypes.RubyBuildType] {
+		if version == buildtypes.BuildTypeVersionUnspecified {
+			continue
+		}
+		v := string(version)
+		t.Run("ruby"+v, func(t *testing.T) {
+			require := require.New(t)
+
+			base, err := GetBaseRubyImage(v)
+			require.NoError(err)
+			require.Contains(base, "registry.hub.docker.com/library/ruby:"+v+".")
+
+			dockerfile, err := Ruby(root, buildtypes.KindOptions{
+				"entrypoint":  "tasks/hello.rb",
+				"rubyVersion": v,
+			}, []string{"FOO"})
+			require.NoError(err)
+			require.Contains(dockerfile, "FROM "+base+"\n")
+			require.Contains(dockerfile, "ARG FOO")
+			require.NotContains(dockerfile, "bundle install", "expected no gems to be installed without a Gemfile")
+			require.Contains(dockerfile, "> .airplane/shim.rb")
+			require.Contains(dockerfile, `ENTRYPOINT ["ruby", ".airplane/shim.rb", "tasks/hello.rb"]`)
+		})
+	}
+
+	_, err := Ruby(root, buildtypes.KindOptions{"entrypoint": "missing.rb"}, nil)
+	require.Error(t, err)
+}
+
+func TestRubyGemfile(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(root, "Gemfile"), []byte("source \"https://rubygems.org\"\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(root, "hello.rb"), []byte("def main(params)\nend\n"), 0644))
+
+	dockerfile, err := Ruby(root, buildtypes.KindOptions{"entrypoint": "hello.rb"}, nil)
+	require.NoError(err)
+	require.Contains(dockerfile, "FROM registry.hub.docker.com/library/ruby:3.3.")
+	require.Contains(dockerfile, "COPY Gemfile Gemfile.lock* ./\nRUN bundle install\n")
+
+	dockerfile, err = RubyBundle(root, buildtypes.BuildContext{
+		Type: buildtypes.RubyBuildType,
+	}, nil)
+	require.NoError(err)
+	require.Contains(dockerfile, "COPY Gemfile Gemfile.lock* ./\nRUN bundle install\n")
+	require.Contains(dockerfile, "ENTRYPOINT []")
+}
+
+func TestRubyShim(t *testing.T) {
+	require := require.New(t)
+
+	shim, err := RubyShim("/airplane")
+	require.NoError(err)
+	require.Contains(shim, `Dir.chdir("/airplane")`)
+}
@@ -0,0 +1,121 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParamsMode controls how the shim passes parameters to a shell script.
+type ParamsMode string
+
+const (
+	// ParamsModeEnv exports each parameter as a PARAM_{SLUG} environment variable. This is the
+	// default.
+	ParamsModeEnv ParamsMode = "env"
+	// ParamsModeFlags exports parameters like ParamsModeEnv, and also passes each parameter to the
+	// script as a --slug=value flag. The flag is repeated for each item of a list parameter.
+	ParamsModeFlags ParamsMode = "flags"
+)
+
+// ParamsModes are the supported parameter modes.
+var ParamsModes = []ParamsMode{ParamsModeEnv, ParamsModeFlags}
+
+// ShimArgs returns the arguments of the shim (see shell-shim.sh) that precede the script's
+// entrypoint for the given mode.
+func ShimArgs(mode ParamsMode) []string {
+	if mode == "" || mode == ParamsModeEnv {
+		return nil
+	}
+	return []string{"--params=" + string(mode)}
+}
+
+// ParamArgs returns the arguments that pass param values to the shim, ordered by slug: slug=value
+// for each value, or slug[json]=items for a list, where items is a JSON array of the formatted
+// items. Deployed runs pass the same arguments, see ParamArgTemplate.
+func ParamArgs(values map[string]interface{}) ([]string, error) {
+	slugs := make([]string, 0, len(values))
+	for slug := range values {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	var args []string
+	for _, slug := range slugs {
+		list, ok := values[slug].([]interface{})
+		if !ok {
+			v, err := FormatValue(values[slug])
+			if err != nil {
+				return nil, errors.Wrapf(err, "formatting param %s", slug)
+			}
+			args = append(args, slug+"="+v)
+			continue
+		}
+
+		items := make([]string, len(list))
+		for i, item := range list {
+			v, err := FormatValue(item)
+			if err != nil {
+				return nil, errors.Wrapf(err, "formatting param %s", slug)
+			}
+			items[i] = v
+		}
+		// Encode like JSON.stringify, which doesn't escape HTML characters.
+		var buf strings.Builder
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(items); err != nil {
+			return nil, errors.Wrapf(err, "formatting param %s", slug)
+		}
+		args = append(args, slug+"[json]="+strings.TrimSuffix(buf.String(), "\n"))
+	}
+	return args, nil
+}
+
+// ParamArgTemplate returns the JST template of the argument that passes a param to the shim in
+// deployed runs. It renders the same arguments as ParamArgs: slug=value, or slug[json]=items if the
+// value is a list.
+func ParamArgTemplate(slug string, isUpload bool) string {
+	if isUpload {
+		// Uploads are objects, which are passed as JSON rather than "[object Object]".
+		return fmt.Sprintf("%s={{JSON.stringify(params.%s)}}", slug, slug)
+	}
+	return fmt.Sprintf(
+		`%s{{Array.isArray(params.%s) ? "[json]" : ""}}={{Array.isArray(params.%s) ? JSON.stringify(params.%s.map(String)) : params.%s}}`,
+		slug, slug, slug, slug, slug,
+	)
+}
+
+// FormatValue formats a param value as a string for a shell script. Numbers are formatted without
+// exponents, so that integers stay integers, and objects (e.g. uploads) are JSON-encoded.
+func FormatValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case json.Number:
+		return v.String(), nil
+	case time.Time:
+		return v.UTC().Format(time.RFC3339), nil
+	default:
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+}
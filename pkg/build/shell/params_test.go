@@ -0,0 +1,94 @@
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamArgs(t *testing.T) {
+	require := require.New(t)
+
+	args, err := ParamArgs(map[string]interface{}{
+		"name":   "Bob",
+		"count":  float64(12345678901),
+		"ratio":  0.5,
+		"admin":  true,
+		"tags":   []interface{}{"a<b", float64(2)},
+		"empty":  []interface{}{},
+		"upload": map[string]interface{}{"id": "upl123"},
+		"unset":  nil,
+	})
+	require.NoError(err)
+	require.Equal([]string{
+		"admin=true",
+		"count=12345678901",
+		"empty[json]=[]",
+		"name=Bob",
+		"ratio=0.5",
+		`tags[json]=["a<b","2"]`,
+		"unset=",
+		`upload={"id":"upl123"}`,
+	}, args)
+
+	require.Nil(ShimArgs(""))
+	require.Nil(ShimArgs(ParamsModeEnv))
+	require.Equal([]string{"--params=flags"}, ShimArgs(ParamsModeFlags))
+}
+
+func TestShim(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	dir := t.TempDir()
+	shim := filepath.Join(dir, "shim.sh")
+	require.NoError(t, os.WriteFile(shim, []byte(ShellShim()), 0644))
+	script := filepath.Join(dir, "main.sh")
+	require.NoError(t, os.WriteFile(script, []byte(strings.Join([]string{
+		"#!/bin/bash",
+		`echo "name=$PARAM_NAME"`,
+		`echo "tags=$PARAM_TAGS"`,
+		`echo "empty=$PARAM_EMPTY"`,
+		`for arg in "$@"; do echo "arg=$arg"; done`,
+	}, "\n")), 0755))
+
+	params := []string{"name=Bob", `tags[json]=["a \"b\"","cé"]`, "empty[json]=[]"}
+	for _, tC := range []struct {
+		mode     ParamsMode
+		expected []string
+	}{
+		{
+			mode: ParamsModeEnv,
+			expected: []string{
+				"name=Bob",
+				`tags=["a \"b\"","cé"]`,
+				"empty=[]",
+			},
+		},
+		{
+			mode: ParamsModeFlags,
+			expected: []string{
+				"name=Bob",
+				`tags=["a \"b\"","cé"]`,
+				"empty=[]",
+				"arg=--name=Bob",
+				`arg=--tags=a "b"`,
+				"arg=--tags=cé",
+			},
+		},
+	} {
+		t.Run(string(tC.mode), func(t *testing.T) {
+			args := append([]string{shim}, ShimArgs(tC.mode)...)
+			args = append(args, script)
+			args = append(args, params...)
+			out, err := exec.Command("bash", args...).CombinedOutput()
+			require.NoError(t, err, string(out))
+			require.Equal(t, tC.expected, strings.Split(strings.TrimSpace(string(out)), "\n"))
+		})
+	}
+}
@@ -1,9 +1,61 @@
 #!/bin/bash
 
-# Params are passed in as param_slug_1=value1, param_slug_2=value2
-# Export as environment variables, PARAM_SLUG_1=value1, PARAM_SLUG_2=value2
+# Usage: shim.sh [--params=env|flags] <entrypoint> [param_slug=value...]
+#
+# Params are passed in as param_slug_1=value1, param_slug_2=value2, and list params as
+# param_slug[json]=["item1","item2"].
+#
+# Params are exported as environment variables, PARAM_SLUG_1=value1, PARAM_SLUG_2=value2, with
+# lists exported as their JSON array. With --params=flags, they are also passed to the script as
+# --param_slug_1=value1 --param_slug_2=value2, repeated for each item of a list.
+mode=env
+if [[ "$1" == --params=* ]]; then
+    mode=${1#--params=}
+    shift
+fi
+entrypoint=$1
+shift
+
+# Decodes a JSON array of strings into the json_items array.
+json_items=()
+decode_json_items() {
+    local s=$1 i=1 n=${#1} c item
+    json_items=()
+    while (( i < n )); do
+        c=${s:i:1}
+        i=$((i + 1))
+        if [[ "$c" != '"' ]]; then
+            continue
+        fi
+        item=
+        while (( i < n )); do
+            c=${s:i:1}
+            i=$((i + 1))
+            if [[ "$c" == '"' ]]; then
+                break
+            fi
+            if [[ "$c" == '\' ]]; then
+                c=${s:i:1}
+                i=$((i + 1))
+                case $c in
+                    (n) c=$'\n' ;;
+                    (r) c=$'\r' ;;
+                    (t) c=$'\t' ;;
+                    (u)
+                        printf -v c "\\u${s:i:4}"
+                        i=$((i + 4))
+                        ;;
+                esac
+            fi
+            item+=$c
+        done
+        json_items+=("$item")
+    done
+}
+
 sep="="
-for param in "${@:2}"; do
+script_args=()
+for param in "$@"; do
     # Split into slug and value by separator. Taken from https://unix.stackexchange.com/a/53323.
     case $param in
         (*"$sep"*)
@@ -15,10 +67,27 @@ for param in "${@:2}"; do
             param_value=
             ;;
     esac
+    is_list=
+    if [[ "$param_slug" == *"[json]" ]]; then
+        is_list=1
+        param_slug=${param_slug%"[json]"}
+    fi
     # Convert to uppercase
     var_name="$(echo "PARAM_${param_slug}" | tr '[:lower:]' '[:upper:]')"
+
     # Export env var
     export "${var_name}"="${param_value}"
+    if [[ -z "$is_list" ]]; then
+        script_args+=("--${param_slug}=${param_value}")
+        continue
+    fi
+    decode_json_items "$param_value"
+    for item in "${json_items[@]}"; do
+        script_args+=("--${param_slug}=${item}")
+    done
 done
 
-exec "$1"
+if [[ "$mode" == "flags" ]]; then
+    exec "$entrypoint" "${script_args[@]}"
+fi
+exec "$entrypoint"
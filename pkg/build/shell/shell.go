@@ -34,15 +34,18 @@ func Shell(root string, options buildtypes.KindOptions) (string, error) {
 		COPY . .
 		RUN chmod +x {{.Entrypoint}}
 
-		ENTRYPOINT ["bash", ".airplane/shim.sh", "./{{.Entrypoint}}"]
+		ENTRYPOINT ["bash", ".airplane/shim.sh", {{range .ShimArgs}}"{{.}}", {{end}}"./{{.Entrypoint}}"]
 	`)
+	paramsMode, _ := options["paramsMode"].(string)
 	return utils.ApplyTemplate(dockerfileTemplate, struct {
 		InlineShim string
+		ShimArgs   []string
 		Entrypoint string
 		Workdir    string
 	}{
 		InlineShim: utils.InlineString(ShellShim()),
-		Entrypoint: utils.BackslashEscape(entrypoint, `"`),
+		ShimArgs:   ShimArgs(ParamsMode(paramsMode)),
+		Entrypoint: utils.BackslashEscape(filepath.ToSlash(entrypoint), `"`),
 		Workdir:    workDir,
 	})
 }
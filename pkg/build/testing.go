@@ -28,6 +28,8 @@ type BundleTestRun struct {
 }
 
 type Test struct {
+	// Name is the name of the subtest. If not set, defaults to the base of Root.
+	Name string
 	// Root is the task root to perform a build inside of.
 	Root        string
 	Kind        buildtypes.TaskKind
@@ -56,12 +58,46 @@ type Test struct {
 	// TODO: pipe to actual build/container run etc. set increased timeout if times out
 }
 
+// NodeVersionMatrix expands each test into one test per supported Node version, so that every
+// version's base image and esbuild target is exercised. Tests that already pin a version are
+// returned as-is.
+func NodeVersionMatrix(tests []Test) []Test {
+	var matrix []Test
+	for _, test := range tests {
+		if test.BuildContext.Version != buildtypes.BuildTypeVersionUnspecified || test.Options["nodeVersion"] != nil {
+			matrix = append(matrix, test)
+			continue
+		}
+		for _, version := range buildtypes.AllBuildTypeVersions[buildtypes.NodeBuildType] {
+			if version == buildtypes.BuildTypeVersionUnspecified {
+				continue
+			}
+			t := test
+			t.Name = fmt.Sprintf("%s/node%s", filepath.Base(test.Root), version)
+			if test.Bundle {
+				t.BuildContext.Version = version
+			}
+			t.Options = buildtypes.KindOptions{}
+			for k, v := range test.Options {
+				t.Options[k] = v
+			}
+			t.Options["nodeVersion"] = string(version)
+			matrix = append(matrix, t)
+		}
+	}
+	return matrix
+}
+
 // RunTests performs a series of builder tests and looks for a given SearchString
 // in the task's output to validate that the task built + ran correctly.
 func RunTests(tt *testing.T, ctx context.Context, tests []Test) {
 	for _, test := range tests {
 		test := test // loop local reference
-		tt.Run(filepath.Base(test.Root), func(t *testing.T) {
+		name := test.Name
+		if name == "" {
+			name = filepath.Base(test.Root)
+		}
+		tt.Run(name, func(t *testing.T) {
 			// These tests can run in parallel, but it may exhaust all memory
 			// allocated to the Docker daemon on your computer. For that reason,
 			// we don't currently run them in parallel. We could gate parallel
@@ -225,6 +261,7 @@ func runTask(t *testing.T, ctx context.Context, dclient *client.Client, c runTas
 		Tty:        false,
 		Cmd:        cmd,
 		Entrypoint: c.Entrypoint,
+		Labels:     Labels(),
 	}, nil, nil, nil, "")
 	require.NoError(err)
 	containerID := resp.ID
@@ -8,7 +8,12 @@ const (
 	NamePython Name = "python"
 	NameNode   Name = "node"
 	NameShell  Name = "shell"
-	NameView   Name = "view"
+	NameDeno   Name = "deno"
+	NameGo     Name = "go"
+	NameRuby   Name = "ruby"
+	// NameDockerfile builds a user-provided Dockerfile.
+	NameDockerfile Name = "dockerfile"
+	NameView       Name = "view"
 
 	NameSQL     Name = "sql"
 	NameREST    Name = "rest"
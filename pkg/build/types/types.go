@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/airplanedev/cli/pkg/build/utils"
@@ -23,7 +24,13 @@ const (
 	TaskKindNode   TaskKind = "node"
 	TaskKindPython TaskKind = "python"
 	TaskKindShell  TaskKind = "shell"
-	TaskKindApp    TaskKind = "app"
+	TaskKindDeno   TaskKind = "deno"
+	TaskKindGo     TaskKind = "go"
+	TaskKindRuby   TaskKind = "ruby"
+	// TaskKindDockerfile tasks are built from a user-provided Dockerfile. They're declared with
+	// the same definition block as image tasks.
+	TaskKindDockerfile TaskKind = "dockerfile"
+	TaskKindApp        TaskKind = "app"
 
 	TaskKindSQL     TaskKind = "sql"
 	TaskKindREST    TaskKind = "rest"
@@ -37,6 +44,11 @@ const (
 	UserFriendlyTaskKindNode   UserFriendlyTaskKind = "JavaScript"
 	UserFriendlyTaskKindPython UserFriendlyTaskKind = "Python"
 	UserFriendlyTaskKindShell  UserFriendlyTaskKind = "Shell"
+	UserFriendlyTaskKindDeno   UserFriendlyTaskKind = "Deno"
+	UserFriendlyTaskKindGo     UserFriendlyTaskKind = "Go"
+	UserFriendlyTaskKindRuby   UserFriendlyTaskKind = "Ruby"
+
+	UserFriendlyTaskKindDockerfile UserFriendlyTaskKind = "Dockerfile"
 
 	UserFriendlyTaskKindSQL  UserFriendlyTaskKind = "SQL"
 	UserFriendlyTaskKindREST UserFriendlyTaskKind = "REST"
@@ -53,6 +65,14 @@ func (k TaskKind) UserFriendlyTaskKind() UserFriendlyTaskKind {
 		return UserFriendlyTaskKindPython
 	case TaskKindShell:
 		return UserFriendlyTaskKindShell
+	case TaskKindDeno:
+		return UserFriendlyTaskKindDeno
+	case TaskKindGo:
+		return UserFriendlyTaskKindGo
+	case TaskKindRuby:
+		return UserFriendlyTaskKindRuby
+	case TaskKindDockerfile:
+		return UserFriendlyTaskKindDockerfile
 	case TaskKindSQL:
 		return UserFriendlyTaskKindSQL
 	case TaskKindREST:
@@ -69,10 +89,28 @@ const (
 	ViewBuildType   BuildType = "view"
 	PythonBuildType BuildType = "python"
 	ShellBuildType  BuildType = "shell"
+	DenoBuildType   BuildType = "deno"
+	GoBuildType     BuildType = "go"
+	RubyBuildType   BuildType = "ruby"
+	// DockerfileBuildType builds a user-provided Dockerfile, see BuildContext.Dockerfile.
+	DockerfileBuildType BuildType = "dockerfile"
 	// NoneBuildType indicates that the entity should not be built.
 	NoneBuildType BuildType = "none"
 )
 
+// SDKPackages are the names of the SDK package of each build type.
+var SDKPackages = map[BuildType]string{
+	NodeBuildType:   "airplane",
+	PythonBuildType: "airplanesdk",
+}
+
+// SDKConstraints are the versions of each SDK that the builders support, since the shims that
+// they generate call into the SDK.
+var SDKConstraints = map[BuildType]string{
+	NodeBuildType:   ">=0.2.0",
+	PythonBuildType: ">=0.3.0, <0.4.0",
+}
+
 func (b BuildType) Valid() bool {
 	_, ok := AllBuildTypeVersions[b]
 	return ok
@@ -84,6 +122,8 @@ const (
 	BuildTypeVersionNode14 BuildTypeVersion = "14"
 	BuildTypeVersionNode16 BuildTypeVersion = "16"
 	BuildTypeVersionNode18 BuildTypeVersion = "18"
+	BuildTypeVersionNode20 BuildTypeVersion = "20"
+	BuildTypeVersionNode22 BuildTypeVersion = "22"
 
 	BuildTypeVersionPython37  BuildTypeVersion = "3.7"
 	BuildTypeVersionPython38  BuildTypeVersion = "3.8"
@@ -91,12 +131,24 @@ const (
 	BuildTypeVersionPython310 BuildTypeVersion = "3.10"
 	BuildTypeVersionPython311 BuildTypeVersion = "3.11"
 
+	BuildTypeVersionDeno1 BuildTypeVersion = "1"
+	BuildTypeVersionDeno2 BuildTypeVersion = "2"
+
+	BuildTypeVersionGo122 BuildTypeVersion = "1.22"
+	BuildTypeVersionGo123 BuildTypeVersion = "1.23"
+
+	BuildTypeVersionRuby32 BuildTypeVersion = "3.2"
+	BuildTypeVersionRuby33 BuildTypeVersion = "3.3"
+
 	BuildTypeVersionUnspecified BuildTypeVersion = ""
 )
 
 const (
 	DefaultNodeVersion   = BuildTypeVersionNode18
 	DefaultPythonVersion = BuildTypeVersionPython310
+	DefaultDenoVersion   = BuildTypeVersionDeno2
+	DefaultGoVersion     = BuildTypeVersionGo123
+	DefaultRubyVersion   = BuildTypeVersionRuby33
 )
 
 var AllBuildTypeVersions = map[BuildType][]BuildTypeVersion{
@@ -104,12 +156,16 @@ var AllBuildTypeVersions = map[BuildType][]BuildTypeVersion{
 		BuildTypeVersionNode14,
 		BuildTypeVersionNode16,
 		BuildTypeVersionNode18,
+		BuildTypeVersionNode20,
+		BuildTypeVersionNode22,
 		BuildTypeVersionUnspecified,
 	},
 	ViewBuildType: {
 		BuildTypeVersionNode14,
 		BuildTypeVersionNode16,
 		BuildTypeVersionNode18,
+		BuildTypeVersionNode20,
+		BuildTypeVersionNode22,
 		BuildTypeVersionUnspecified,
 	},
 	PythonBuildType: {
@@ -123,6 +179,24 @@ var AllBuildTypeVersions = map[BuildType][]BuildTypeVersion{
 	ShellBuildType: {
 		BuildTypeVersionUnspecified,
 	},
+	DenoBuildType: {
+		BuildTypeVersionDeno1,
+		BuildTypeVersionDeno2,
+		BuildTypeVersionUnspecified,
+	},
+	GoBuildType: {
+		BuildTypeVersionGo122,
+		BuildTypeVersionGo123,
+		BuildTypeVersionUnspecified,
+	},
+	RubyBuildType: {
+		BuildTypeVersionRuby32,
+		BuildTypeVersionRuby33,
+		BuildTypeVersionUnspecified,
+	},
+	DockerfileBuildType: {
+		BuildTypeVersionUnspecified,
+	},
 	NoneBuildType: {
 		BuildTypeVersionUnspecified,
 	},
@@ -133,10 +207,26 @@ type BuildContext struct {
 	Version BuildTypeVersion       `json:"version"`
 	Base    BuildBase              `json:"base"`
 	EnvVars map[string]EnvVarValue `json:"envVars"`
+	// Dockerfile configures builds of the dockerfile build type.
+	Dockerfile *DockerfileBuildContext `json:"dockerfile,omitempty"`
+	// Platforms are the platforms that the bundle's image is built for. If empty, it's built for
+	// DefaultBuildPlatform.
+	Platforms []string `json:"platforms,omitempty"`
 }
+
+// DockerfileBuildContext configures the build of a user-provided Dockerfile. The bundle root is the
+// Docker build context.
+type DockerfileBuildContext struct {
+	// Path is the path of the Dockerfile, relative to the bundle root.
+	Path string `json:"path"`
+	// BuildArgs are passed to the Dockerfile as build args.
+	BuildArgs map[string]string `json:"buildArgs,omitempty"`
+}
+
 type EnvVarValue struct {
-	Value  *string `json:"value,omitempty"`
-	Config *string `json:"config,omitempty"`
+	Value    *string `json:"value,omitempty"`
+	Config   *string `json:"config,omitempty"`
+	External *string `json:"external,omitempty"`
 }
 
 func (b BuildContext) Valid() bool {
@@ -159,6 +249,12 @@ func (b BuildContext) DefaultVersion() BuildTypeVersion {
 		return DefaultNodeVersion
 	case PythonBuildType:
 		return DefaultPythonVersion
+	case DenoBuildType:
+		return DefaultDenoVersion
+	case GoBuildType:
+		return DefaultGoVersion
+	case RubyBuildType:
+		return DefaultRubyVersion
 	default:
 		return BuildTypeVersionUnspecified
 	}
@@ -172,6 +268,29 @@ const (
 	BuildBaseNone BuildBase = ""
 )
 
+// ViewBundler is the tool that bundles the assets of a view.
+type ViewBundler string
+
+const (
+	// ViewBundlerAirplane bundles views with Vite, using a config that's managed by Airplane.
+	ViewBundlerAirplane ViewBundler = "airplane"
+	// ViewBundlerVite bundles views with Vite, merging the project's own Vite config into the one
+	// managed by Airplane so that the project's plugins are used.
+	ViewBundlerVite ViewBundler = "vite"
+)
+
+// PythonPackageManager is the tool that installs the dependencies of a Python task.
+type PythonPackageManager string
+
+const (
+	// PythonPackageManagerDefault detects the package manager from the files in the task root:
+	// Poetry if there's a poetry.lock or a pyproject.toml with a [tool.poetry] table, and pip
+	// otherwise.
+	PythonPackageManagerDefault PythonPackageManager = ""
+	PythonPackageManagerPip     PythonPackageManager = "pip"
+	PythonPackageManagerPoetry  PythonPackageManager = "poetry"
+)
+
 type TaskRuntime string
 
 const (
@@ -193,6 +312,13 @@ type Values = map[string]interface{}
 type BuildInstructions struct {
 	InstallInstructions []InstallInstruction
 	BuildArgs           []string
+	// CacheMounts mounts a BuildKit cache at the CacheDir of each install instruction, so that
+	// downloaded packages are reused across builds. Dockerfiles that use cache mounts can only be
+	// built with BuildKit.
+	CacheMounts bool
+	// Secrets are the IDs of BuildKit secrets that are mounted into each install instruction. See
+	// KindOptionBuildSecrets.
+	Secrets []string
 }
 
 func (i BuildInstructions) DockerfileString() (string, error) {
@@ -207,16 +333,114 @@ func (i BuildInstructions) DockerfileString() (string, error) {
 		RUN chmod +x {{if .DstPath}}{{.DstPath}}{{else}}{{.SrcPath}}{{end}}
 		{{end}}
 		{{end}}
-		{{if .Cmd}}RUN {{.Cmd}}{{end}}
+		{{if .Cmd}}RUN {{if and $.CacheMounts .CacheDir}}--mount=type=cache,target={{.CacheDir}} {{end}}{{$.SecretMounts}}{{.Cmd}}{{end}}
 		{{end}}
 	`), i)
 }
 
+// SecretMounts returns the prefix of RUN instructions that mounts the secrets of i.
+func (i BuildInstructions) SecretMounts() string {
+	return SecretMounts(i.Secrets)
+}
+
 type InstallInstruction struct {
 	Cmd        string
 	SrcPath    string
 	DstPath    string
 	Executable bool
+	// CacheDir is the directory that Cmd caches downloads in, if any. See
+	// BuildInstructions.CacheMounts.
+	CacheDir string
+}
+
+// KindOptionCacheMounts is set to "true" in the kind options of builds that use a layer cache, to
+// enable BuildInstructions.CacheMounts.
+const KindOptionCacheMounts = "cacheMounts"
+
+// KindOptionOffline is set to "true" in the kind options of offline builds, which install the
+// dependencies of shims from a vendored npm cache in the build context instead of the registry.
+const KindOptionOffline = "offline"
+
+// KindOptionBuildArgs are the build args of a task's image, keyed by name. Tasks built from a
+// Dockerfile pass them to it; other tasks declare them with ARG, so that the commands that install
+// dependencies can use them, e.g. PIP_INDEX_URL.
+const KindOptionBuildArgs = "buildArgs"
+
+// KindOptionBuildSecrets lists the IDs of BuildKit secrets that are mounted into the commands that
+// install a task's dependencies, e.g. to authenticate with private package registries. Unlike
+// build args, secrets aren't stored in the image. Each secret is exported to the command as an env
+// var of the same name.
+const KindOptionBuildSecrets = "buildSecrets"
+
+// BuildSecrets returns the IDs of the build secrets of a build, from its kind options.
+func BuildSecrets(options KindOptions) []string {
+	switch v := options[KindOptionBuildSecrets].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var secrets []string
+		for _, s := range v {
+			if id, ok := s.(string); ok {
+				secrets = append(secrets, id)
+			}
+		}
+		return secrets
+	default:
+		return nil
+	}
+}
+
+// SecretMounts returns the prefix of a RUN instruction that mounts the given build secrets and
+// exports them as env vars, or "" if there are none.
+func SecretMounts(secrets []string) string {
+	if len(secrets) == 0 {
+		return ""
+	}
+	var mounts, exports []string
+	for _, id := range secrets {
+		mounts = append(mounts, fmt.Sprintf("--mount=type=secret,id=%s", id))
+		exports = append(exports, fmt.Sprintf(`%s="$(cat /run/secrets/%s)"`, id, id))
+	}
+	return strings.Join(mounts, " ") + " export " + strings.Join(exports, " ") + " && "
+}
+
+// KindOptionBuildPlatforms lists the platforms that a task's image is built for, e.g.
+// ["linux/amd64", "linux/arm64"]. Images of several platforms are pushed as a manifest list.
+const KindOptionBuildPlatforms = "buildPlatforms"
+
+// DefaultBuildPlatform is the platform that images are built for, unless configured otherwise.
+const DefaultBuildPlatform = "linux/amd64"
+
+// SupportedBuildPlatforms are the platforms that images can be built for.
+var SupportedBuildPlatforms = []string{"linux/amd64", "linux/arm64"}
+
+// BuildPlatforms returns the platforms that a build is for, from its kind options.
+func BuildPlatforms(options KindOptions) ([]string, error) {
+	var platforms []string
+	switch v := options[KindOptionBuildPlatforms].(type) {
+	case nil:
+	case []string:
+		platforms = v
+	case []interface{}:
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected %s to be a list of strings, got %T", KindOptionBuildPlatforms, p)
+			}
+			platforms = append(platforms, s)
+		}
+	default:
+		return nil, fmt.Errorf("expected %s to be a list of strings, got %T", KindOptionBuildPlatforms, v)
+	}
+	if len(platforms) == 0 {
+		return []string{DefaultBuildPlatform}, nil
+	}
+	for _, p := range platforms {
+		if !slices.Contains(SupportedBuildPlatforms, p) {
+			return nil, fmt.Errorf("unsupported build platform %q: expected one of %v", p, SupportedBuildPlatforms)
+		}
+	}
+	return platforms, nil
 }
 
 type ErrUnsupportedBuilder struct {
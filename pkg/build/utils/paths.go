@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"path/filepath"
+)
+
+// ToSlashes converts host paths to the slash-separated paths used in Dockerfiles and in scripts
+// that run in the container.
+func ToSlashes(paths []string) []string {
+	if paths == nil {
+		return nil
+	}
+	out := make([]string, len(paths))
+	for i, p := range paths {
+		out[i] = filepath.ToSlash(p)
+	}
+	return out
+}
@@ -50,6 +50,25 @@ func (t *Tree) Copy(src string) error {
 	return nil
 }
 
+// CopyTo copies src into dst, relative to root. Unlike Copy, no files are excluded.
+func (t *Tree) CopyTo(src, dst string) error {
+	r, err := archive.TarWithOptions(src, &archive.TarOptions{
+		Compression: archive.Uncompressed,
+	})
+	if err != nil {
+		return errors.Wrap(err, "tar with options")
+	}
+
+	if err := t.MkdirAll(dst); err != nil {
+		return err
+	}
+	if err := archive.Unpack(r, filepath.Join(t.root, dst), &archive.TarOptions{}); err != nil {
+		return errors.Wrapf(err, "unpacking %s", dst)
+	}
+
+	return nil
+}
+
 // MkdirAll creates dir relative to root
 func (t *Tree) MkdirAll(dir string) error {
 	if err := os.MkdirAll(filepath.Join(t.root, dir), 0777); err != nil {
@@ -49,6 +49,23 @@ func (v Version) String() string {
 	return v.Image + "@" + v.Digest
 }
 
+// Pinned returns whether the version is pinned to a digest.
+func (v Version) Pinned() bool {
+	return v.String() != ""
+}
+
+// Ref returns the image reference to build from. Unlike String, it falls back to the tag for
+// versions that have not been pinned to a digest yet; see TestUnpinnedVersions for which these are.
+func (v Version) Ref() string {
+	if v.Pinned() {
+		return v.String()
+	}
+	if v.Image == "" || v.Tag == "" {
+		return ""
+	}
+	return v.Image + ":" + v.Tag
+}
+
 func GetVersions() (Versions, error) {
 	var versions Versions
 	if err := json.Unmarshal(versionsJSON, &versions); err != nil {
@@ -0,0 +1,56 @@
+package versions
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnpinnedVersions lists the base images that are built from a tag because they have not been
+// pinned to a digest and copied into the public cache yet. Pin a version and remove it from this
+// list rather than adding to it.
+func TestUnpinnedVersions(t *testing.T) {
+	require := require.New(t)
+
+	versions, err := GetVersions()
+	require.NoError(err)
+
+	var unpinned []string
+	for builder, builderVersions := range versions {
+		for key, v := range builderVersions {
+			require.NotEmpty(v.Image, "%s %s", builder, key)
+			require.NotEmpty(v.Ref(), "%s %s", builder, key)
+			if !v.Pinned() {
+				unpinned = append(unpinned, builder+" "+key)
+			}
+		}
+	}
+	sort.Strings(unpinned)
+	require.Equal([]string{
+		"deno 1",
+		"deno 2",
+		"go 1.22",
+		"go 1.22-slim",
+		"go 1.23",
+		"go 1.23-slim",
+		"node 20",
+		"node 20-slim",
+		"node 22",
+		"node 22-slim",
+		"ruby 3.2",
+		"ruby 3.3",
+	}, unpinned)
+}
+
+func TestVersionString(t *testing.T) {
+	require := require.New(t)
+
+	pinned := Version{Image: "node", Tag: "18.12.0-bullseye", Digest: "sha256:abc"}
+	require.Equal("node@sha256:abc", pinned.String())
+	require.Equal("node@sha256:abc", pinned.Ref())
+
+	unpinned := Version{Image: "node", Tag: "20.18.0-bookworm"}
+	require.Equal("", unpinned.String())
+	require.Equal("node:20.18.0-bookworm", unpinned.Ref())
+}
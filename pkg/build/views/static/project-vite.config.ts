@@ -0,0 +1,24 @@
+import { ConfigEnv, mergeConfig, UserConfig, UserConfigExport } from "vite";
+import airplaneConfig from "{{.AirplaneConfig}}";
+import projectConfig from "{{.ProjectConfig}}";
+
+const resolveConfig = async (
+  config: UserConfigExport,
+  env: ConfigEnv
+): Promise<UserConfig> =>
+  typeof config === "function" ? config(env) : config;
+
+// The project's config is merged first, so that Airplane's options (e.g. the base path that the
+// dev server proxies requests through) take precedence over it.
+export default async (env: ConfigEnv) => {
+  const project = await resolveConfig(projectConfig, env);
+  const airplane = await resolveConfig(airplaneConfig, env);
+
+  // The React plugin can only be added once. If the project already uses it, keep its options.
+  const isReactPlugin = (p) => p && typeof p.name === "string" && p.name.startsWith("vite:react");
+  if ((project.plugins ?? []).flat().some(isReactPlugin)) {
+    airplane.plugins = (airplane.plugins ?? []).flat().filter((p) => !isReactPlugin(p));
+  }
+
+  return mergeConfig(project, airplane);
+};
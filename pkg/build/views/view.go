@@ -38,13 +38,12 @@ func View(root string, options buildtypes.KindOptions) (string, error) {
 		apiHost = "https://" + apiHost
 	}
 
-	// TODO: possibly support multiple build tools.
 	base, err := node.GetBaseNodeImage("", false)
 	if err != nil {
 		return "", err
 	}
 
-	mainTsxStr, err := MainTsxString("./src/"+entrypoint, false)
+	mainTsxStr, err := MainTsxString("./src/"+filepath.ToSlash(entrypoint), false)
 	if err != nil {
 		return "", err
 	}
@@ -56,6 +55,17 @@ func View(root string, options buildtypes.KindOptions) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	var projectViteConfigStr string
+	if bundler, _ := options["bundler"].(string); buildtypes.ViewBundler(bundler) == buildtypes.ViewBundlerVite {
+		projectConfig, err := FindProjectViteConfig(root)
+		if err != nil {
+			return "", err
+		}
+		projectViteConfigStr, err = ProjectViteConfigString("./airplane.vite.config", "./src/"+projectConfig)
+		if err != nil {
+			return "", err
+		}
+	}
 
 	packageJSONPath := filepath.Join(root, "package.json")
 	var packageJSON interface{}
@@ -110,14 +120,15 @@ func View(root string, options buildtypes.KindOptions) (string, error) {
 	}
 
 	cfg := struct {
-		Base              string
-		InstallCommand    string
-		OutDir            string
-		InlineMainTsx     string
-		InlineIndexHtml   string
-		InlineViteConfig  string
-		APIHost           string
-		InlinePackageJSON string
+		Base                    string
+		InstallCommand          string
+		OutDir                  string
+		InlineMainTsx           string
+		InlineIndexHtml         string
+		InlineViteConfig        string
+		InlineProjectViteConfig string
+		APIHost                 string
+		InlinePackageJSON       string
 	}{
 		Base: base,
 		// Because the install command is running in the context of a docker build, the yarn cache
@@ -132,6 +143,9 @@ func View(root string, options buildtypes.KindOptions) (string, error) {
 		APIHost:           apiHost,
 		InlinePackageJSON: utils.InlineString(string(packageJSONByte)),
 	}
+	if projectViteConfigStr != "" {
+		cfg.InlineProjectViteConfig = utils.InlineString(projectViteConfigStr)
+	}
 
 	return utils.ApplyTemplate(heredoc.Doc(`
 		FROM {{.Base}} as builder
@@ -144,7 +158,12 @@ func View(root string, options buildtypes.KindOptions) (string, error) {
 		RUN mkdir /airplane/src/
 		RUN {{.InlineIndexHtml}} > /airplane/index.html
 		RUN {{.InlineMainTsx}} > /airplane/main.tsx
+		{{- if .InlineProjectViteConfig}}
+		RUN {{.InlineViteConfig}} > /airplane/airplane.vite.config.ts
+		RUN {{.InlineProjectViteConfig}} > /airplane/vite.config.ts
+		{{- else}}
 		RUN {{.InlineViteConfig}} > /airplane/vite.config.ts
+		{{- end}}
 		ENV AIRPLANE_API_HOST={{.APIHost}}
 
 		COPY . /airplane/src/
@@ -170,12 +189,19 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 	}
 
 	useSlimImage := buildContext.Base == buildtypes.BuildBaseSlim
-	nodeVersion := node.GetNodeVersion(options)
+	nodeVersion, err := node.ResolveNodeVersion(root, options)
+	if err != nil {
+		return "", err
+	}
 	base, err := node.GetBaseNodeImage(nodeVersion, useSlimImage)
 	if err != nil {
 		return "", err
 	}
 
+	// Paths are embedded in scripts that run in the container, so they're slash-separated.
+	filesToBuild = utils.ToSlashes(filesToBuild)
+	filesToDiscover = utils.ToSlashes(filesToDiscover)
+
 	var filesToBuildWithoutExtension []string
 	for _, fileToBuild := range filesToBuild {
 		// We use the files without their extension to generate unique paths to a specific
@@ -196,6 +222,18 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 	if err != nil {
 		return "", err
 	}
+	var projectViteConfigStr string
+	if bundler, _ := options["bundler"].(string); buildtypes.ViewBundler(bundler) == buildtypes.ViewBundlerVite {
+		projectConfig, err := FindProjectViteConfig(root)
+		if err != nil {
+			return "", err
+		}
+		// The project's config is copied into the same directory as the universal config.
+		projectViteConfigStr, err = ProjectViteConfigString("./airplane.vite.config", "./"+projectConfig)
+		if err != nil {
+			return "", err
+		}
+	}
 	postcssConfigStr, err := PostcssConfigString("src/tailwind.config.js")
 	if err != nil {
 		return "", err
@@ -249,10 +287,10 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 	// Generate a list of all of the files to discover
 	var discoverEntrypoints []string
 	for _, fileToDiscover := range filesToDiscover {
-		fileToDiscoverExt := filepath.Ext(fileToDiscover)
+		fileToDiscoverExt := path.Ext(fileToDiscover)
 		// These should point at the location that esbuild will build to.
 		discoverEntrypoints = append(discoverEntrypoints,
-			filepath.Join(directoryToBuildTo, strings.TrimSuffix(fileToDiscover, fileToDiscoverExt)+".js"))
+			path.Join(directoryToBuildTo, strings.TrimSuffix(fileToDiscover, fileToDiscoverExt)+".js"))
 	}
 
 	filesToBuildBytes, err := json.Marshal(filesToBuild)
@@ -261,16 +299,21 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 	}
 	esbuildFilesToBuild := string(filesToBuildBytes)
 
+	esbuildOptions, err := node.GetEsbuildOptions(root)
+	if err != nil {
+		return "", err
+	}
+
 	// Add build tools.
-	buildToolsPath := path.Join(root, ".airplane-build-tools")
+	buildToolsPath := filepath.Join(root, ".airplane-build-tools")
 	if err := os.MkdirAll(buildToolsPath, 0755); err != nil {
 		return "", errors.Wrapf(err, "creating build tools path")
 	}
 
-	if err := os.WriteFile(path.Join(buildToolsPath, "gen_view.sh"), []byte(genViewStr), 0755); err != nil {
+	if err := os.WriteFile(filepath.Join(buildToolsPath, "gen_view.sh"), []byte(genViewStr), 0755); err != nil {
 		return "", errors.Wrap(err, "writing gen view script")
 	}
-	if err := os.WriteFile(path.Join(buildToolsPath, "esbuild.js"), []byte(node.Esbuild), 0755); err != nil {
+	if err := os.WriteFile(filepath.Join(buildToolsPath, "esbuild.js"), []byte(node.Esbuild), 0755); err != nil {
 		return "", errors.Wrap(err, "writing esbuild script")
 	}
 
@@ -281,7 +324,7 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 
 	if len(filesToDiscover) > 0 {
 		// Generate parser and store on context
-		parserPath := path.Join(buildToolsPath, "inlineParser.js")
+		parserPath := filepath.Join(buildToolsPath, "inlineParser.js")
 		if err := os.WriteFile(parserPath, []byte(parser.NodeParserScript), 0755); err != nil {
 			return "", errors.Wrap(err, "writing parser script")
 		}
@@ -294,9 +337,11 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 		InlineMainTsx                string
 		InlineIndexHtml              string
 		InlineViteConfig             string
+		InlineProjectViteConfig      string
 		APIHost                      string
 		InlineShimPackageJSON        string
 		EsbuildFlags                 string
+		EsbuildOptions               string
 		FilesToBuild                 string
 		FilesToBuildWithoutExtension string
 		FilesToDiscover              string
@@ -319,6 +364,7 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 		APIHost:                      apiHost,
 		InlineShimPackageJSON:        utils.InlineString(string(shimPackageJSONBytes)),
 		EsbuildFlags:                 esbuildFlags,
+		EsbuildOptions:               esbuildOptions,
 		FilesToBuild:                 esbuildFilesToBuild,
 		FilesToBuildWithoutExtension: strings.Join(filesToBuildWithoutExtension, " "),
 		FilesToDiscover:              strings.Join(discoverEntrypoints, " "),
@@ -329,6 +375,9 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 		InlinePostcssConfig:          utils.InlineString(postcssConfigStr),
 		InstallInstructions:          installInstructions,
 	}
+	if projectViteConfigStr != "" {
+		cfg.InlineProjectViteConfig = utils.InlineString(projectViteConfigStr)
+	}
 
 	return utils.ApplyTemplate(heredoc.Doc(`
 		FROM {{.Base}} as builder
@@ -368,8 +417,13 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 
 		# Generate index.html and main.tsx for each entrypoint.
 		RUN {{.InlineIndexHtml}} > /airplane/index.html && {{.InlineMainTsx}} > /airplane/main.tsx && /airplane/.airplane-build-tools/gen_view.sh "{{.FilesToBuildWithoutExtension}}" /airplane/index.html /airplane/main.tsx
+		{{- if .InlineProjectViteConfig}}
+		# Copy in universal Vite config, merge the project's Vite config into it, and build view
+		RUN {{.InlineViteConfig}} > airplane.vite.config.ts && {{.InlineProjectViteConfig}} > airplane-project.vite.config.ts && /airplane/node_modules/.bin/vite build --config airplane-project.vite.config.ts --outDir {{.OutDir}}
+		{{- else}}
 		# Copy in universal Vite config and build view
 		RUN {{.InlineViteConfig}} > vite.config.ts && /airplane/node_modules/.bin/vite build --outDir {{.OutDir}}
+		{{- end}}
 		RUN yarn list --pattern @airplane/views | grep @airplane/views | sed "s/^.*@airplane\/views@\(.*\)$/\1/" > {{.OutDir}}/.airplane-views-version
 
 		{{if .FilesToDiscover}}
@@ -381,7 +435,8 @@ func ViewBundle(root string, buildContext buildtypes.BuildContext, options build
 			"" \
 			{{.DirectoryToBuildTo}} \
 			/airplane/src \
-			true
+			true \
+			'{{.EsbuildOptions}}'
 		RUN node /airplane/.airplane-build-tools/inlineParser.js {{.FilesToDiscover}} > /airplane/.airplane-build-tools/discovery.json
 
 		# Bust the Docker cache to ensure discovered entities are logged.
@@ -481,6 +536,35 @@ func UniversalViteConfigString(entrypoints []string) (string, error) {
 	})
 }
 
+//go:embed static/project-vite.config.ts
+var projectViteConfigTemplateStr string
+
+// projectViteConfigFiles are the names of the Vite configs that projects can use with the vite
+// bundler, in order of precedence.
+var projectViteConfigFiles = []string{"vite.config.ts", "vite.config.mts", "vite.config.js", "vite.config.mjs"}
+
+// FindProjectViteConfig returns the name of the project's own Vite config within root.
+func FindProjectViteConfig(root string) (string, error) {
+	for _, name := range projectViteConfigFiles {
+		if fsx.Exists(filepath.Join(root, name)) {
+			return name, nil
+		}
+	}
+	return "", errors.Errorf("the vite bundler requires a Vite config in %s (one of %s)", root, strings.Join(projectViteConfigFiles, ", "))
+}
+
+// ProjectViteConfigString returns a Vite config that merges the Airplane-managed config into the
+// project's own config. Both configs are import paths, relative to the returned config.
+func ProjectViteConfigString(airplaneConfig, projectConfig string) (string, error) {
+	return utils.ApplyTemplate(projectViteConfigTemplateStr, struct {
+		AirplaneConfig string
+		ProjectConfig  string
+	}{
+		AirplaneConfig: airplaneConfig,
+		ProjectConfig:  projectConfig,
+	})
+}
+
 //go:embed static/index.html
 var indexHtmlTemplateStr string
 
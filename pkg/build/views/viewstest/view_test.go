@@ -129,6 +129,23 @@ func TestViewBundleBuilder(t *testing.T) {
 				"myView.airplane.tsx",
 			},
 		},
+		{
+			Root: "view/vite",
+			Kind: "view",
+			Options: buildtypes.KindOptions{
+				"apiHost": "https://api:5000",
+				"bundler": "vite",
+			},
+			SkipRun: true,
+			Bundle:  true,
+			BuildContext: buildtypes.BuildContext{
+				Type:    buildtypes.ViewBuildType,
+				Version: buildtypes.BuildTypeVersionUnspecified,
+			},
+			FilesToBuild: []string{
+				"src/App.tsx",
+			},
+		},
 	}
 
 	build.RunTests(t, ctx, tests)
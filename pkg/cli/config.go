@@ -2,6 +2,7 @@ package cli
 
 import (
 	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/conf"
 	"github.com/airplanedev/cli/pkg/devconf"
 	"github.com/airplanedev/cli/pkg/flags/flagsiface"
 	"github.com/airplanedev/cli/pkg/prompts"
@@ -27,6 +28,13 @@ type Config struct {
 	// debug output to guide end-users through issues.
 	DebugMode bool
 
+	// Verbosity is the number of times --verbose was passed: once for debug logs, twice for trace logs
+	// such as HTTP request and response bodies.
+	Verbosity int
+
+	// LogModules overrides the log level of individual modules, e.g. "api,discover=trace".
+	LogModules string
+
 	// WithTelemetry indicates if the CLI should send usage analytics and errors, even if it's been
 	// previously disabled.
 	WithTelemetry bool
@@ -40,6 +48,12 @@ type Config struct {
 	// The API host to use.
 	Host string
 
+	// Resolver determines the effective value of settings that can be configured through
+	// flags, env vars, the user config file or the project config file.
+	//
+	// It is initialized in the root command.
+	Resolver conf.Resolver
+
 	// Prompter represents the prompter to use to get user input.
 	Prompter prompts.Prompter
 }
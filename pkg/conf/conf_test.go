@@ -26,8 +26,9 @@ func TestUserConfig(t *testing.T) {
 		var homedir = testutils.Tempdir(t)
 		var path = filepath.Join(homedir, ".airplane", "config")
 
-		err := writeUserConfig(path, UserConfig{
-			Tokens: map[string]string{"airplane.dev": "foo"},
+		err := updateUserConfig(path, func(cfg *UserConfig) error {
+			cfg.Tokens = map[string]string{"airplane.dev": "foo"}
+			return nil
 		})
 		assert.NoError(err)
 
@@ -42,8 +43,9 @@ func TestUserConfig(t *testing.T) {
 		var path = filepath.Join(homedir, ".airplane", "config")
 
 		{
-			err := writeUserConfig(path, UserConfig{
-				Tokens: map[string]string{"airplane.dev": "foo"},
+			err := updateUserConfig(path, func(cfg *UserConfig) error {
+				cfg.Tokens = map[string]string{"airplane.dev": "foo"}
+				return nil
 			})
 			assert.NoError(err)
 
@@ -53,8 +55,9 @@ func TestUserConfig(t *testing.T) {
 		}
 
 		{
-			err := writeUserConfig(path, UserConfig{
-				Tokens: map[string]string{"airplane.dev": "baz"},
+			err := updateUserConfig(path, func(cfg *UserConfig) error {
+				cfg.Tokens = map[string]string{"airplane.dev": "baz"}
+				return nil
 			})
 			assert.NoError(err)
 
@@ -63,4 +66,24 @@ func TestUserConfig(t *testing.T) {
 			assert.Equal("baz", cfg.Tokens["airplane.dev"])
 		}
 	})
+
+	t.Run("update keeps other fields", func(t *testing.T) {
+		var assert = require.New(t)
+		var homedir = testutils.Tempdir(t)
+		var path = filepath.Join(homedir, ".airplane", "config")
+
+		assert.NoError(updateUserConfig(path, func(cfg *UserConfig) error {
+			cfg.Tokens = map[string]string{"airplane.dev": "foo"}
+			return nil
+		}))
+		assert.NoError(updateUserConfig(path, func(cfg *UserConfig) error {
+			cfg.Env = "stage"
+			return nil
+		}))
+
+		cfg, err := ReadUserConfig(path)
+		assert.NoError(err)
+		assert.Equal("foo", cfg.Tokens["airplane.dev"])
+		assert.Equal("stage", cfg.Env)
+	})
 }
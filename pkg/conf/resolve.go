@@ -0,0 +1,251 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	deployconfig "github.com/airplanedev/cli/pkg/deploy/config"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// Source identifies where the effective value of a setting came from.
+type Source string
+
+// Sources, in order of precedence.
+const (
+	SourceFlag          Source = "flag"
+	SourceEnv           Source = "env"
+	SourceUserConfig    Source = "user config"
+	SourceProjectConfig Source = "project config"
+	SourceDefault       Source = "default"
+)
+
+// Setting describes a CLI setting and each of the places it can be configured.
+type Setting struct {
+	Key string
+	// Flag is the name of the flag that sets this setting, if any.
+	Flag string
+	// EnvVar is the name of the env var that sets this setting, if any.
+	EnvVar string
+	// User reads this setting from the user config file, if it can be set there.
+	User func(UserConfig) string
+	// Secret settings are redacted when explained.
+	Secret bool
+}
+
+// Settings are the settings known to the resolver.
+var Settings = []Setting{
+	{
+		Key:    "host",
+		Flag:   "host",
+		EnvVar: "AP_HOST",
+		User:   func(c UserConfig) string { return c.Host },
+	},
+	{
+		Key:    "env",
+		Flag:   "env",
+		EnvVar: "AP_ENV",
+		User:   func(c UserConfig) string { return c.Env },
+	},
+	{
+		Key:    "team",
+		EnvVar: "AP_TEAM_ID",
+	},
+	{
+		Key:    "api-key",
+		EnvVar: "AP_API_KEY",
+		Secret: true,
+	},
+	{
+		Key:    "source",
+		EnvVar: "AP_SOURCE",
+	},
+	{
+		Key:    "log-format",
+		Flag:   "log-format",
+		EnvVar: "AIRPLANE_LOG_FORMAT",
+	},
+}
+
+// Value is the effective value of a setting.
+type Value struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value" yaml:"value"`
+	Source Source `json:"source" yaml:"source"`
+	// Origin is the specific flag, env var, or file that provided the value.
+	Origin string `json:"origin,omitempty" yaml:"origin,omitempty"`
+}
+
+// Explanation describes how a setting was resolved. Candidates lists every source that could
+// provide the setting, in order of precedence, including ones that were not set.
+type Explanation struct {
+	Value
+	Candidates []Candidate `json:"candidates" yaml:"candidates"`
+}
+
+type Candidate struct {
+	Source Source `json:"source" yaml:"source"`
+	Origin string `json:"origin" yaml:"origin"`
+	Value  string `json:"value" yaml:"value"`
+	IsSet  bool   `json:"isSet" yaml:"isSet"`
+}
+
+// Resolver determines the effective value of CLI settings. Sources are consulted in the
+// order flag, env var, user config file, project config file and then the built-in default.
+type Resolver struct {
+	// Flags are the parsed flags of the command being run.
+	Flags *pflag.FlagSet
+	// Getenv defaults to os.Getenv.
+	Getenv            func(string) string
+	UserConfig        UserConfig
+	UserConfigPath    string
+	ProjectConfig     map[string]string
+	ProjectConfigPath string
+	Defaults          map[string]string
+}
+
+// NewResolver returns a resolver for the given flags. The project config is read from the nearest
+// airplane.yaml in or above the working directory, if there is one.
+func NewResolver(flags *pflag.FlagSet, userConfig UserConfig, defaults map[string]string) Resolver {
+	r := Resolver{
+		Flags:      flags,
+		UserConfig: userConfig,
+		Defaults:   defaults,
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return r
+	}
+	dir, ok := fsx.Find(wd, deployconfig.FileName)
+	if !ok {
+		return r
+	}
+	c, err := deployconfig.NewAirplaneConfigFromFile(dir)
+	if err != nil {
+		logger.Debug("unable to read project config in %s: %v", dir, err)
+		return r
+	}
+	r.ProjectConfigPath = filepath.Join(dir, deployconfig.FileName)
+	r.ProjectConfig = map[string]string{
+		"host": c.CLI.Host,
+		"env":  c.CLI.Env,
+	}
+	return r
+}
+
+// Get returns the effective value of the setting with the given key, or an empty string.
+func (r Resolver) Get(key string) string {
+	v, err := r.Resolve(key)
+	if err != nil {
+		return ""
+	}
+	return v.Value
+}
+
+// Resolve returns the effective value of the setting with the given key.
+func (r Resolver) Resolve(key string) (Value, error) {
+	e, _, err := r.explain(key)
+	if err != nil {
+		return Value{}, err
+	}
+	return e.Value, nil
+}
+
+// Explain returns the effective value of the setting with the given key along with every source
+// that was considered. Values of secret settings are redacted.
+func (r Resolver) Explain(key string) (Explanation, error) {
+	e, s, err := r.explain(key)
+	if err != nil {
+		return Explanation{}, err
+	}
+	if s.Secret {
+		e.Value.Value = redact(e.Value.Value)
+		for i := range e.Candidates {
+			e.Candidates[i].Value = redact(e.Candidates[i].Value)
+		}
+	}
+	return e, nil
+}
+
+func (r Resolver) explain(key string) (Explanation, Setting, error) {
+	s, ok := lookupSetting(key)
+	if !ok {
+		return Explanation{}, Setting{}, errors.Errorf("unknown setting %q, expected one of: %v", key, SettingKeys())
+	}
+
+	var candidates []Candidate
+	if s.Flag != "" {
+		c := Candidate{Source: SourceFlag, Origin: "--" + s.Flag}
+		if r.Flags != nil {
+			if f := r.Flags.Lookup(s.Flag); f != nil && f.Changed {
+				c.Value, c.IsSet = f.Value.String(), true
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	if s.EnvVar != "" {
+		getenv := r.Getenv
+		if getenv == nil {
+			getenv = os.Getenv
+		}
+		v := getenv(s.EnvVar)
+		candidates = append(candidates, Candidate{Source: SourceEnv, Origin: s.EnvVar, Value: v, IsSet: v != ""})
+	}
+	if s.User != nil {
+		v := s.User(r.UserConfig)
+		candidates = append(candidates, Candidate{Source: SourceUserConfig, Origin: r.userConfigPath(), Value: v, IsSet: v != ""})
+	}
+	if r.ProjectConfigPath != "" {
+		v, isSet := r.ProjectConfig[key]
+		candidates = append(candidates, Candidate{Source: SourceProjectConfig, Origin: r.ProjectConfigPath, Value: v, IsSet: isSet && v != ""})
+	}
+	v, isSet := r.Defaults[key]
+	candidates = append(candidates, Candidate{Source: SourceDefault, Value: v, IsSet: isSet})
+
+	e := Explanation{Candidates: candidates, Value: Value{Key: key}}
+	for _, c := range candidates {
+		if c.IsSet {
+			e.Value = Value{Key: key, Value: c.Value, Source: c.Source, Origin: c.Origin}
+			break
+		}
+	}
+	return e, s, nil
+}
+
+func (r Resolver) userConfigPath() string {
+	if r.UserConfigPath != "" {
+		return r.UserConfigPath
+	}
+	return defaultUserConfigPath()
+}
+
+func lookupSetting(key string) (Setting, bool) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// SettingKeys returns the keys of all known settings, sorted.
+func SettingKeys() []string {
+	keys := make([]string, 0, len(Settings))
+	for _, s := range Settings {
+		keys = append(keys, s.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func redact(v string) string {
+	if v == "" {
+		return ""
+	}
+	return "<redacted>"
+}
@@ -0,0 +1,88 @@
+package conf
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver(t *testing.T) {
+	newResolver := func(t *testing.T, args []string, env map[string]string) Resolver {
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.String("host", "api.airplane.dev", "")
+		require.NoError(t, flags.Parse(args))
+		return Resolver{
+			Flags:             flags,
+			Getenv:            func(k string) string { return env[k] },
+			UserConfig:        UserConfig{Host: "user.airplane.dev"},
+			UserConfigPath:    "/home/config",
+			ProjectConfig:     map[string]string{"host": "project.airplane.dev", "env": "staging"},
+			ProjectConfigPath: "/project/airplane.yaml",
+			Defaults:          map[string]string{"host": "api.airplane.dev"},
+		}
+	}
+
+	t.Run("flag takes precedence", func(t *testing.T) {
+		r := newResolver(t, []string{"--host", "flag.airplane.dev"}, map[string]string{"AP_HOST": "env.airplane.dev"})
+		v, err := r.Resolve("host")
+		require.NoError(t, err)
+		require.Equal(t, Value{Key: "host", Value: "flag.airplane.dev", Source: SourceFlag, Origin: "--host"}, v)
+	})
+
+	t.Run("env over user config", func(t *testing.T) {
+		r := newResolver(t, nil, map[string]string{"AP_HOST": "env.airplane.dev"})
+		v, err := r.Resolve("host")
+		require.NoError(t, err)
+		require.Equal(t, Value{Key: "host", Value: "env.airplane.dev", Source: SourceEnv, Origin: "AP_HOST"}, v)
+	})
+
+	t.Run("user config over project config", func(t *testing.T) {
+		r := newResolver(t, nil, nil)
+		v, err := r.Resolve("host")
+		require.NoError(t, err)
+		require.Equal(t, Value{Key: "host", Value: "user.airplane.dev", Source: SourceUserConfig, Origin: "/home/config"}, v)
+	})
+
+	t.Run("project config", func(t *testing.T) {
+		r := newResolver(t, nil, nil)
+		v, err := r.Resolve("env")
+		require.NoError(t, err)
+		require.Equal(t, Value{Key: "env", Value: "staging", Source: SourceProjectConfig, Origin: "/project/airplane.yaml"}, v)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		r := newResolver(t, nil, nil)
+		r.UserConfig = UserConfig{}
+		r.ProjectConfig = nil
+		v, err := r.Resolve("host")
+		require.NoError(t, err)
+		require.Equal(t, Value{Key: "host", Value: "api.airplane.dev", Source: SourceDefault}, v)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		r := newResolver(t, nil, nil)
+		v, err := r.Resolve("team")
+		require.NoError(t, err)
+		require.Equal(t, Value{Key: "team"}, v)
+	})
+
+	t.Run("explain redacts secrets", func(t *testing.T) {
+		r := newResolver(t, nil, map[string]string{"AP_API_KEY": "secret"})
+		require.Equal(t, "secret", r.Get("api-key"))
+
+		e, err := r.Explain("api-key")
+		require.NoError(t, err)
+		require.Equal(t, "<redacted>", e.Value.Value)
+		require.Equal(t, SourceEnv, e.Source)
+		for _, c := range e.Candidates {
+			require.NotEqual(t, "secret", c.Value)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		r := newResolver(t, nil, nil)
+		_, err := r.Explain("nope")
+		require.Error(t, err)
+	})
+}
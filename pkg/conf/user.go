@@ -6,16 +6,30 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/airplanedev/cli/pkg/utils/filelock"
 	"github.com/pkg/errors"
 )
 
 // UserConfig represents user-specific configuration for the CLI.
 type UserConfig struct {
 	Tokens          map[string]string `json:"tokens,omitempty"`
+	Host            string            `json:"host,omitempty"`
+	Env             string            `json:"env,omitempty"`
 	EnableTelemetry *bool             `json:"enableTelemetry,omitempty"`
 	LatestVersion   VersionUpdate     `json:"latestVersion,omitempty"`
 	Flags           FlagsUpdate       `json:"flags,omitempty"`
+
+	// Sessions are the SSO sessions that issued the tokens of each host, if any.
+	Sessions map[string]Session `json:"sessions,omitempty"`
+}
+
+// Session is an SSO session, whose token is refreshed once it expires.
+type Session struct {
+	OrgSlug      string    `json:"orgSlug"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
 }
+
 type VersionUpdate struct {
 	Version string    `json:"version"`
 	Updated time.Time `json:"updated"`
@@ -52,12 +66,10 @@ func ReadDefaultUserConfig() (UserConfig, error) {
 func ReadUserConfig(path string) (UserConfig, error) {
 	var cfg UserConfig
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	buf, err := filelock.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
 		return cfg, ErrMissing
-	}
-
-	buf, err := os.ReadFile(path)
-	if err != nil {
+	} else if err != nil {
 		return cfg, errors.Wrap(err, "read config")
 	}
 
@@ -68,25 +80,32 @@ func ReadUserConfig(path string) (UserConfig, error) {
 	return cfg, nil
 }
 
-// writeUserConfig writes the configuration to the given defaultUserConfigPath.
-func writeUserConfig(path string, cfg UserConfig) error {
+// updateUserConfig applies update to the configuration at path, creating it if it's missing.
+//
+// The config is locked from reading it until the update is written, since several commands may
+// update it at once (f.e. CI jobs that share a home directory).
+func updateUserConfig(path string, update func(cfg *UserConfig) error) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
 		return errors.Wrap(err, "mkdir")
 	}
 
-	buf, err := json.MarshalIndent(cfg, "", "	")
-	if err != nil {
-		return errors.Wrap(err, "marshal config")
-	}
-
-	if err := os.WriteFile(path, buf, 0600); err != nil {
-		return errors.Wrap(err, "write config")
-	}
-
-	return nil
+	err := filelock.Update(path, 0600, func(buf []byte) ([]byte, error) {
+		var cfg UserConfig
+		if len(buf) > 0 {
+			if err := json.Unmarshal(buf, &cfg); err != nil {
+				return nil, errors.Wrap(err, "unmarshal config")
+			}
+		}
+		if err := update(&cfg); err != nil {
+			return nil, err
+		}
+		buf, err := json.MarshalIndent(cfg, "", "	")
+		return buf, errors.Wrap(err, "marshal config")
+	})
+	return errors.Wrap(err, "update config")
 }
 
-// WriteDefaultUserConfig saves configuration to the default location.
-func WriteDefaultUserConfig(cfg UserConfig) error {
-	return writeUserConfig(defaultUserConfigPath(), cfg)
+// UpdateDefaultUserConfig applies update to the configuration at the default location.
+func UpdateDefaultUserConfig(update func(cfg *UserConfig) error) error {
+	return updateUserConfig(defaultUserConfigPath(), update)
 }
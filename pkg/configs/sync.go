@@ -0,0 +1,208 @@
+package configs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/goccy/go-yaml"
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+)
+
+// FileConfig is a config var declared in a local config file.
+type FileConfig struct {
+	NameTag NameTag
+	Value   string
+	Secret  bool
+}
+
+// ReadConfigFile reads the config vars declared in a dotenv or YAML file. Files with a .yaml or
+// .yml extension are read as YAML, and every other file is read as a dotenv file.
+//
+// YAML files map config names to either a value or an object with a value and whether it's a
+// secret, e.g.:
+//
+//	db_host: localhost
+//	db_password:
+//	  value: hunter2
+//	  secret: true
+//
+// The vars of dotenv files, and the vars of YAML files that don't set secret, are secrets if
+// secret is true.
+func ReadConfigFile(path string, secret bool) ([]FileConfig, error) {
+	var values map[string]FileConfig
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		values, err = readYAMLConfigFile(path, secret)
+	default:
+		values, err = readDotEnvConfigFile(path, secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]FileConfig, 0, len(values))
+	for name, c := range values {
+		nt, err := ParseName(name)
+		if err != nil || nt.Name == "" {
+			return nil, errors.Errorf("invalid config name: %s - expected my_config or my_config:tag", name)
+		}
+		c.NameTag = nt
+		configs = append(configs, c)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return JoinName(configs[i].NameTag) < JoinName(configs[j].NameTag)
+	})
+	return configs, nil
+}
+
+func readDotEnvConfigFile(path string, secret bool) (map[string]FileConfig, error) {
+	env, err := godotenv.Read(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+	values := make(map[string]FileConfig, len(env))
+	for name, value := range env {
+		values[name] = FileConfig{Value: value, Secret: secret}
+	}
+	return values, nil
+}
+
+func readYAMLConfigFile(path string, secret bool) (map[string]FileConfig, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(buf, &raw); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+
+	values := make(map[string]FileConfig, len(raw))
+	for name, v := range raw {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			c := FileConfig{Secret: secret}
+			for k, field := range v {
+				switch k {
+				case "value":
+					c.Value = scalarString(field)
+				case "secret":
+					s, ok := field.(bool)
+					if !ok {
+						return nil, errors.Errorf("config %s: secret must be a boolean", name)
+					}
+					c.Secret = s
+				default:
+					return nil, errors.Errorf("config %s: unknown field %s, expected value or secret", name, k)
+				}
+			}
+			values[name] = c
+		case []interface{}:
+			return nil, errors.Errorf("config %s: expected a value or an object with a value", name)
+		default:
+			values[name] = FileConfig{Value: scalarString(v), Secret: secret}
+		}
+	}
+	return values, nil
+}
+
+// scalarString formats a YAML scalar as a config value.
+func scalarString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// SyncAction is a change that syncing a config file makes to a config var.
+type SyncAction string
+
+const (
+	SyncCreate    SyncAction = "create"
+	SyncUpdate    SyncAction = "update"
+	SyncUnchanged SyncAction = "unchanged"
+	SyncDelete    SyncAction = "delete"
+)
+
+// SyncChange is the change that syncing a config file makes to a single config var.
+type SyncChange struct {
+	Action SyncAction
+	Config FileConfig
+	// ID is the ID of the remote config var, if it exists.
+	ID string
+}
+
+// PlanSync returns the changes that make the remote config vars match the local ones, sorted by
+// name. Remote config vars that aren't declared locally are only deleted if prune is true.
+//
+// The values of remote secrets aren't compared, so that they don't have to be read: secrets that
+// are declared locally are always updated.
+func PlanSync(local []FileConfig, remote []api.Config, prune bool) []SyncChange {
+	remoteByName := make(map[NameTag]api.Config, len(remote))
+	for _, c := range remote {
+		remoteByName[NameTag{Name: c.Name, Tag: c.Tag}] = c
+	}
+
+	var changes []SyncChange
+	declared := make(map[NameTag]bool, len(local))
+	for _, c := range local {
+		declared[c.NameTag] = true
+		r, ok := remoteByName[c.NameTag]
+		switch {
+		case !ok:
+			changes = append(changes, SyncChange{Action: SyncCreate, Config: c})
+		case c.Secret || r.IsSecret || r.Value != c.Value:
+			changes = append(changes, SyncChange{Action: SyncUpdate, Config: c, ID: r.ID})
+		default:
+			changes = append(changes, SyncChange{Action: SyncUnchanged, Config: c, ID: r.ID})
+		}
+	}
+	if prune {
+		for nt, r := range remoteByName {
+			if !declared[nt] {
+				changes = append(changes, SyncChange{
+					Action: SyncDelete,
+					Config: FileConfig{NameTag: nt, Value: r.Value, Secret: r.IsSecret},
+					ID:     r.ID,
+				})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return JoinName(changes[i].Config.NameTag) < JoinName(changes[j].Config.NameTag)
+	})
+	return changes
+}
+
+// ApplySync makes the given changes to the config vars of envSlug.
+func ApplySync(ctx context.Context, client api.APIClient, envSlug string, changes []SyncChange) error {
+	for _, c := range changes {
+		switch c.Action {
+		case SyncCreate, SyncUpdate:
+			if err := SetConfig(ctx, client, SetConfigRequest{
+				NameTag: c.Config.NameTag,
+				Value:   c.Config.Value,
+				Secret:  c.Config.Secret,
+				EnvSlug: envSlug,
+			}); err != nil {
+				return err
+			}
+		case SyncDelete:
+			if err := client.DeleteConfig(ctx, api.DeleteConfigRequest{
+				ID:      c.ID,
+				EnvSlug: envSlug,
+			}); err != nil {
+				return errors.Wrapf(err, "deleting config %s", JoinName(c.Config.NameTag))
+			}
+		}
+	}
+	return nil
+}
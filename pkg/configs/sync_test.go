@@ -0,0 +1,109 @@
+package configs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("dotenv", func(t *testing.T) {
+		require := require.New(t)
+		path := filepath.Join(dir, ".env")
+		require.NoError(os.WriteFile(path, []byte("DB_HOST=localhost\n# comment\nDB_PASSWORD=\"hunter2\"\n"), 0644))
+
+		configs, err := ReadConfigFile(path, true)
+		require.NoError(err)
+		require.Equal([]FileConfig{
+			{NameTag: NameTag{Name: "DB_HOST"}, Value: "localhost", Secret: true},
+			{NameTag: NameTag{Name: "DB_PASSWORD"}, Value: "hunter2", Secret: true},
+		}, configs)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		require := require.New(t)
+		path := filepath.Join(dir, "configs.yaml")
+		require.NoError(os.WriteFile(path, []byte(`
+db_host: localhost
+db_port: 5432
+db_password:
+  value: hunter2
+  secret: true
+empty:
+`), 0644))
+
+		configs, err := ReadConfigFile(path, false)
+		require.NoError(err)
+		require.Equal([]FileConfig{
+			{NameTag: NameTag{Name: "db_host"}, Value: "localhost"},
+			{NameTag: NameTag{Name: "db_password"}, Value: "hunter2", Secret: true},
+			{NameTag: NameTag{Name: "db_port"}, Value: "5432"},
+			{NameTag: NameTag{Name: "empty"}, Value: ""},
+		}, configs)
+	})
+
+	t.Run("yaml with unknown field", func(t *testing.T) {
+		require := require.New(t)
+		path := filepath.Join(dir, "invalid.yml")
+		require.NoError(os.WriteFile(path, []byte("db_host:\n  val: localhost\n"), 0644))
+
+		_, err := ReadConfigFile(path, false)
+		require.EqualError(err, "config db_host: unknown field val, expected value or secret")
+	})
+}
+
+func TestSync(t *testing.T) {
+	require := require.New(t)
+	client := &api.MockClient{
+		Configs: []api.Config{
+			{ID: "cfg1", Name: "unchanged", Value: "a"},
+			{ID: "cfg2", Name: "updated", Value: "old"},
+			{ID: "cfg3", Name: "now_secret", Value: "s"},
+			{ID: "cfg4", Name: "stale", Value: "x"},
+			{ID: "cfg5", Name: "secret", Value: "", IsSecret: true},
+		},
+	}
+	local := []FileConfig{
+		{NameTag: NameTag{Name: "created"}, Value: "new"},
+		{NameTag: NameTag{Name: "now_secret"}, Value: "s", Secret: true},
+		{NameTag: NameTag{Name: "secret"}, Value: "s", Secret: true},
+		{NameTag: NameTag{Name: "unchanged"}, Value: "a"},
+		{NameTag: NameTag{Name: "updated"}, Value: "new"},
+	}
+
+	changes := PlanSync(local, client.Configs, false)
+	actions := map[string]SyncAction{}
+	for _, c := range changes {
+		actions[JoinName(c.Config.NameTag)] = c.Action
+	}
+	require.Equal(map[string]SyncAction{
+		"created":    SyncCreate,
+		"now_secret": SyncUpdate,
+		// Secrets aren't read, so they're always updated.
+		"secret":    SyncUpdate,
+		"unchanged": SyncUnchanged,
+		"updated":   SyncUpdate,
+	}, actions)
+
+	// Pruning deletes configs that aren't declared locally.
+	changes = PlanSync(local, client.Configs, true)
+	require.Len(changes, 6)
+	require.Equal(SyncDelete, changes[3].Action)
+	require.Equal("stale", changes[3].Config.NameTag.Name)
+	require.Equal("cfg4", changes[3].ID)
+
+	require.NoError(ApplySync(context.Background(), client, "", changes))
+	require.ElementsMatch([]api.Config{
+		{ID: "cfg1", Name: "unchanged", Value: "a"},
+		{ID: "cfg2", Name: "updated", Value: "new"},
+		{ID: "cfg3", Name: "now_secret", Value: "s", IsSecret: true},
+		{ID: "cfg5", Name: "secret", Value: "s", IsSecret: true},
+		{ID: client.Configs[4].ID, Name: "created", Value: "new"},
+	}, client.Configs)
+}
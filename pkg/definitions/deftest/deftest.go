@@ -0,0 +1,77 @@
+// Package deftest provides golden-file helpers for testing how definitions are marshaled.
+//
+// Snapshots are stored under testdata, named after the test that takes them, and are updated by
+// running the tests with the -deftest.update flag:
+//
+//	go test ./... -run TestMyDefinitions -deftest.update
+package deftest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+// update is namespaced, since packages that import deftest may define their own -update flag.
+var update = flag.Bool("deftest.update", false, "update the golden files of definition snapshots")
+
+// Dir is the directory that golden files are stored in, relative to the package under test.
+const Dir = "testdata"
+
+// Marshaler is implemented by definitions, and by types that embed them.
+type Marshaler interface {
+	Marshal(format definitions.DefFormat) ([]byte, error)
+}
+
+// Updating returns whether golden files are being updated rather than compared against.
+func Updating() bool {
+	return *update
+}
+
+// Snapshot marshals def in each of the given formats, which default to YAML and JSON, and
+// compares the output with the golden files of the test, e.g. testdata/TestTasks/python.yaml for
+// the subtest "python" of TestTasks.
+func Snapshot(t testing.TB, def Marshaler, formats ...definitions.DefFormat) {
+	t.Helper()
+
+	if len(formats) == 0 {
+		formats = []definitions.DefFormat{definitions.DefFormatYAML, definitions.DefFormatJSON}
+	}
+	for _, format := range formats {
+		buf, err := def.Marshal(format)
+		require.NoError(t, err, "marshaling definition as %s", format)
+		Golden(t, Path(t, string(format)), buf)
+	}
+}
+
+// Path returns the path of the golden file of the test with the given extension.
+func Path(t testing.TB, ext string) string {
+	t.Helper()
+	// Subtest names are separated by slashes, which map to directories.
+	name := filepath.FromSlash(t.Name())
+	return filepath.Join(Dir, name+"."+strings.TrimPrefix(ext, "."))
+}
+
+// Golden compares got with the contents of the golden file at path. If -deftest.update is set, the
+// golden file is written instead.
+func Golden(t testing.TB, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, got, 0644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		require.FailNow(t, "missing golden file", "%s doesn't exist: run the test with -deftest.update to create it", path)
+	}
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got), "%s is out of date: run the test with -deftest.update to update it", path)
+}
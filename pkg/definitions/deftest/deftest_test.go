@@ -0,0 +1,69 @@
+package deftest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		def  Marshaler
+	}{
+		{
+			name: "python",
+			def: definitions.Definition{
+				Slug:   "hello_world",
+				Name:   "Hello world",
+				Python: &definitions.PythonDefinition{Entrypoint: "hello_world.py"},
+				Parameters: []definitions.ParameterDefinition{
+					{Slug: "name", Type: "shorttext", Default: "World"},
+				},
+			},
+		},
+		{
+			name: "view",
+			def: definitions.ViewDefinition{
+				Slug:       "hello_world",
+				Name:       "Hello world",
+				Entrypoint: "HelloWorld.view.tsx",
+			},
+		},
+		{
+			name: "embedded",
+			def: struct{ definitions.Definition }{definitions.Definition{
+				Slug:    "hello_world",
+				Timeout: 60,
+				Shell:   &definitions.ShellDefinition{Entrypoint: "hello_world.sh", EnvVars: api.EnvVars{"NAME": {Value: pointers.String("World")}}},
+			}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			Snapshot(t, test.def)
+		})
+	}
+}
+
+func TestGolden(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "nested", "def.yaml")
+	defer func(u bool) { *update = u }(*update)
+
+	*update = true
+	Golden(t, path, []byte("slug: hello_world\n"))
+	buf, err := os.ReadFile(path)
+	require.NoError(err)
+	require.Equal("slug: hello_world\n", string(buf))
+
+	*update = false
+	Golden(t, path, []byte("slug: hello_world\n"))
+
+	require.Equal(filepath.Join("testdata", "TestGolden.yaml"), Path(t, ".yaml"))
+}
@@ -0,0 +1,154 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/airplanedev/cli/pkg/api"
+)
+
+// Deprecation describes the use of a deprecated field in a task definition.
+type Deprecation struct {
+	// Field is the path of the deprecated field, e.g. "sql.configs".
+	Field string
+	// Message describes how the field is used.
+	Message string
+	// Replacement describes what to use instead.
+	Replacement string
+	// Edit describes how the definition file was rewritten to fix the deprecation. It's only set
+	// by FixDeprecations.
+	Edit DeprecationEdit
+}
+
+// DeprecationEdit describes how to rewrite a definition file to fix a deprecation, so that the file
+// can be updated in place rather than re-marshalled. Exactly one of Value, MoveTo and Remove is set.
+type DeprecationEdit struct {
+	// Path is the path of the deprecated field, e.g. ["sql", "configs"].
+	Path []string
+	// Value replaces the field's value.
+	Value string
+	// MoveTo is the top-level field that the field is moved to.
+	MoveTo string
+	// Remove removes the field.
+	Remove bool
+}
+
+func (d Deprecation) String() string {
+	return fmt.Sprintf("%s: %s. %s", d.Field, d.Message, d.Replacement)
+}
+
+type deprecationRule struct {
+	field       string
+	message     string
+	replacement string
+	// check returns whether d uses the deprecated field. availableResources may be nil, in which
+	// case rules that depend on resources never match.
+	check func(d *Definition, availableResources []api.ResourceMetadata) bool
+	// fix rewrites d to use the replacement, and returns the equivalent edit of d's file. The
+	// result must behave the same as the original.
+	fix func(d *Definition, availableResources []api.ResourceMetadata) DeprecationEdit
+}
+
+// deprecationRules lists the deprecated fields of task definitions. Add a rule here when a field is
+// deprecated so that `airplane fix` can migrate existing definitions.
+var deprecationRules = []deprecationRule{
+	{
+		field:       "sql.configs",
+		message:     "config variables are attached to the SQL task",
+		replacement: "Use top-level `configs` instead.",
+		check: func(d *Definition, _ []api.ResourceMetadata) bool {
+			return d.SQL != nil && len(d.SQL.Configs) > 0
+		},
+		fix: func(d *Definition, _ []api.ResourceMetadata) DeprecationEdit {
+			edit := moveConfigs(d, "sql", d.SQL.Configs)
+			d.SQL.Configs = nil
+			return edit
+		},
+	},
+	{
+		field:       "rest.configs",
+		message:     "config variables are attached to the REST task",
+		replacement: "Use top-level `configs` instead.",
+		check: func(d *Definition, _ []api.ResourceMetadata) bool {
+			return d.REST != nil && len(d.REST.Configs) > 0
+		},
+		fix: func(d *Definition, _ []api.ResourceMetadata) DeprecationEdit {
+			edit := moveConfigs(d, "rest", d.REST.Configs)
+			d.REST.Configs = nil
+			return edit
+		},
+	},
+	{
+		field:       "sql.resource",
+		message:     "the resource is referenced by name",
+		replacement: "Reference the resource by slug instead.",
+		check: func(d *Definition, availableResources []api.ResourceMetadata) bool {
+			return d.SQL != nil && isResourceName(availableResources, d.SQL.Resource)
+		},
+		fix: func(d *Definition, availableResources []api.ResourceMetadata) DeprecationEdit {
+			d.SQL.Resource = getResourceByName(availableResources, d.SQL.Resource).Slug
+			return DeprecationEdit{Path: []string{"sql", "resource"}, Value: d.SQL.Resource}
+		},
+	},
+	{
+		field:       "rest.resource",
+		message:     "the resource is referenced by name",
+		replacement: "Reference the resource by slug instead.",
+		check: func(d *Definition, availableResources []api.ResourceMetadata) bool {
+			return d.REST != nil && isResourceName(availableResources, d.REST.Resource)
+		},
+		fix: func(d *Definition, availableResources []api.ResourceMetadata) DeprecationEdit {
+			d.REST.Resource = getResourceByName(availableResources, d.REST.Resource).Slug
+			return DeprecationEdit{Path: []string{"rest", "resource"}, Value: d.REST.Resource}
+		},
+	},
+}
+
+// Deprecations returns the deprecated fields that d uses. Deprecations that depend on resources,
+// such as resources that are referenced by name, are only detected if availableResources is set.
+func (d Definition) Deprecations(availableResources []api.ResourceMetadata) []Deprecation {
+	var deprecations []Deprecation
+	for _, rule := range deprecationRules {
+		if rule.check(&d, availableResources) {
+			deprecations = append(deprecations, rule.deprecation())
+		}
+	}
+	return deprecations
+}
+
+// FixDeprecations rewrites d to replace the deprecated fields that it uses, and returns the
+// deprecations that were fixed.
+func (d *Definition) FixDeprecations(availableResources []api.ResourceMetadata) []Deprecation {
+	var fixed []Deprecation
+	for _, rule := range deprecationRules {
+		if rule.check(d, availableResources) {
+			deprecation := rule.deprecation()
+			deprecation.Edit = rule.fix(d, availableResources)
+			fixed = append(fixed, deprecation)
+		}
+	}
+	return fixed
+}
+
+func (r deprecationRule) deprecation() Deprecation {
+	return Deprecation{
+		Field:       r.field,
+		Message:     r.message,
+		Replacement: r.replacement,
+	}
+}
+
+// moveConfigs moves the configs of d's kind to the top level. Kind configs are ignored if
+// top-level configs are set, so they are only moved if there are none, and removed otherwise.
+func moveConfigs(d *Definition, kind string, kindConfigs []string) DeprecationEdit {
+	path := []string{kind, "configs"}
+	if len(d.Configs) > 0 {
+		return DeprecationEdit{Path: path, Remove: true}
+	}
+	d.Configs = kindConfigs
+	return DeprecationEdit{Path: path, MoveTo: "configs"}
+}
+
+// isResourceName returns whether ref refers to one of availableResources by name rather than slug.
+func isResourceName(availableResources []api.ResourceMetadata, ref string) bool {
+	return getResourceBySlug(availableResources, ref) == nil && getResourceByName(availableResources, ref) != nil
+}
@@ -0,0 +1,152 @@
+package definitions
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecations(t *testing.T) {
+	resources := []api.ResourceMetadata{
+		{ID: "res1", Slug: "db", DefaultEnvResource: &api.Resource{ID: "res1", Slug: "db", Name: "Database"}},
+		{ID: "res2", Slug: "api", DefaultEnvResource: &api.Resource{ID: "res2", Slug: "api", Name: "API"}},
+	}
+
+	for _, test := range []struct {
+		name      string
+		def       Definition
+		resources []api.ResourceMetadata
+		fields    []string
+		fixed     Definition
+	}{
+		{
+			name: "no deprecations",
+			def: Definition{
+				Slug:    "my_task",
+				SQL:     &SQLDefinition{Resource: "db", Entrypoint: "query.sql"},
+				Configs: []string{"API_KEY"},
+			},
+			resources: resources,
+			fixed: Definition{
+				Slug:    "my_task",
+				SQL:     &SQLDefinition{Resource: "db", Entrypoint: "query.sql"},
+				Configs: []string{"API_KEY"},
+			},
+		},
+		{
+			name: "sql",
+			def: Definition{
+				Slug: "my_task",
+				SQL:  &SQLDefinition{Resource: "Database", Entrypoint: "query.sql", Configs: []string{"API_KEY"}},
+			},
+			resources: resources,
+			fields:    []string{"sql.configs", "sql.resource"},
+			fixed: Definition{
+				Slug:    "my_task",
+				SQL:     &SQLDefinition{Resource: "db", Entrypoint: "query.sql"},
+				Configs: []string{"API_KEY"},
+			},
+		},
+		{
+			name: "rest",
+			def: Definition{
+				Slug: "my_task",
+				REST: &RESTDefinition{Resource: "API", Method: "GET", Path: "/", Configs: []string{"API_KEY"}},
+			},
+			resources: resources,
+			fields:    []string{"rest.configs", "rest.resource"},
+			fixed: Definition{
+				Slug:    "my_task",
+				REST:    &RESTDefinition{Resource: "api", Method: "GET", Path: "/"},
+				Configs: []string{"API_KEY"},
+			},
+		},
+		{
+			// Kind configs are ignored if top-level configs are set.
+			name: "top-level configs take precedence",
+			def: Definition{
+				Slug:    "my_task",
+				SQL:     &SQLDefinition{Resource: "db", Entrypoint: "query.sql", Configs: []string{"OLD"}},
+				Configs: []string{"NEW"},
+			},
+			resources: resources,
+			fields:    []string{"sql.configs"},
+			fixed: Definition{
+				Slug:    "my_task",
+				SQL:     &SQLDefinition{Resource: "db", Entrypoint: "query.sql"},
+				Configs: []string{"NEW"},
+			},
+		},
+		{
+			name: "resource names without resources",
+			def: Definition{
+				Slug: "my_task",
+				SQL:  &SQLDefinition{Resource: "Database", Entrypoint: "query.sql"},
+			},
+			fixed: Definition{
+				Slug: "my_task",
+				SQL:  &SQLDefinition{Resource: "Database", Entrypoint: "query.sql"},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			var fields []string
+			for _, d := range test.def.Deprecations(test.resources) {
+				fields = append(fields, d.Field)
+			}
+			require.Equal(test.fields, fields)
+
+			fixed := test.def.FixDeprecations(test.resources)
+			require.Len(fixed, len(test.fields))
+			require.Equal(test.fixed, test.def)
+			require.Empty(test.def.Deprecations(test.resources))
+		})
+	}
+}
+
+func TestDeprecationRulesCoverSchema(t *testing.T) {
+	require := require.New(t)
+
+	var schema interface{}
+	require.NoError(json.Unmarshal([]byte(GetTaskSchema()), &schema))
+
+	// Every field that the schema marks as deprecated needs a rule, so that it's reported and fixed.
+	var deprecated []string
+	var walk func(node interface{}, path []string)
+	walk = func(node interface{}, path []string) {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if desc, ok := n["description"].(string); ok && strings.Contains(desc, "Deprecated") {
+				deprecated = append(deprecated, strings.Join(path, "."))
+			}
+			for k, v := range n {
+				if k == "properties" {
+					for field, prop := range v.(map[string]interface{}) {
+						walk(prop, append(append([]string{}, path...), field))
+					}
+				} else {
+					walk(v, path)
+				}
+			}
+		case []interface{}:
+			for _, v := range n {
+				walk(v, path)
+			}
+		}
+	}
+	walk(schema, nil)
+	require.NotEmpty(deprecated)
+
+	rules := map[string]bool{}
+	for _, rule := range deprecationRules {
+		rules[rule.field] = true
+	}
+	for _, field := range deprecated {
+		require.True(rules[field], "no deprecation rule for %s", field)
+	}
+}
@@ -0,0 +1,58 @@
+package definitions
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff returns a unified diff from the remote to the local definition of a task, or an empty string
+// if they are the same.
+//
+// The API stores entrypoints relative to the deployed bundle rather than to the definition file,
+// so the local entrypoint is used for both definitions.
+func Diff(local, remote Definition) (string, error) {
+	if entrypoint, err := local.Entrypoint(); err == nil {
+		if err := remote.SetEntrypoint(entrypoint); err != nil && !errors.Is(err, ErrNoEntrypoint) {
+			return "", err
+		}
+	}
+
+	before, err := remote.Marshal(DefFormatYAML)
+	if err != nil {
+		return "", errors.Wrap(err, "marshaling deployed definition")
+	}
+	after, err := local.Marshal(DefFormatYAML)
+	if err != nil {
+		return "", errors.Wrap(err, "marshaling local definition")
+	}
+	if string(before) == string(after) {
+		return "", nil
+	}
+
+	name := local.GetSlug() + ".task.yaml"
+	if file := local.GetDefnFilePath(); file != "" {
+		name = diffPath(file)
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(before)),
+		B:        difflib.SplitLines(string(after)),
+		FromFile: "deployed/" + local.GetSlug(),
+		ToFile:   filepath.ToSlash(name),
+		Context:  3,
+	})
+}
+
+// diffPath returns path relative to the working directory, if possible.
+func diffPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil {
+		return rel
+	}
+	return path
+}
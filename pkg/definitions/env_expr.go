@@ -0,0 +1,336 @@
+package definitions
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// Env var values may contain expressions that reference other env vars, config vars and built-in
+// values, e.g.
+//
+//	envVars:
+//	  BASE_URL: https://api.example.com
+//	  API_URL: "{{env.BASE_URL}}/v2"
+//	  API_KEY: "{{configs.API_KEY}}"
+//	  CALLBACK: "{{env.CALLBACK_URL || airplane.env.slug + '.example.com'}}"
+//
+// Expressions are a small subset of JavaScript: references, string literals, concatenation with +
+// and fallbacks with ||, where a reference that isn't defined or is empty falls back to the next
+// operand. Any other {{...}} is a JS template, which is left as-is for the API to evaluate.
+//
+// Only the dev server resolves expressions. Deployed runs evaluate every {{...}} as a JS template,
+// where env is the environment rather than the env vars, so deploys reject them (see
+// DevOnlyEnvVars).
+
+// EnvExprVars are the values that env var expressions can reference, besides other env vars.
+type EnvExprVars struct {
+	// Config returns the value of a config var, and whether it's defined.
+	Config func(name string) (string, bool, error)
+	// Builtins are the values of the built-ins that the runtime provides, keyed by their name
+	// without the "airplane." prefix, e.g. "env.slug". See EnvExprBuiltins.
+	Builtins map[string]string
+}
+
+// EnvExprBuiltins lists the built-ins that env var expressions can reference, with the "airplane."
+// prefix.
+var EnvExprBuiltins = []string{"airplane.env.slug", "airplane.slug", "airplane.name"}
+
+// jstEnvFields are the fields of the env object of JS templates, e.g. {{env.slug}}. References to
+// them are left for the API to evaluate, unless an env var of the same name is defined.
+var jstEnvFields = map[string]bool{"id": true, "slug": true, "name": true, "isDefault": true}
+
+var envExprRegex = regexp.MustCompile(`{{(.*?)}}`)
+
+// ResolveEnvVars resolves the expressions in the values of env vars.
+func ResolveEnvVars(envVars map[string]string, vars EnvExprVars) (map[string]string, error) {
+	r := &envResolver{
+		envVars:  envVars,
+		vars:     vars,
+		resolved: map[string]string{},
+		visiting: map[string]bool{},
+	}
+	result := make(map[string]string, len(envVars))
+	for _, name := range sortedEnvVarNames(envVars) {
+		value, err := r.resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+// DevOnlyEnvVars returns the names of the env vars whose values use expressions, sorted. These
+// values are only resolved by the dev server, and can't be deployed. Env vars from config vars or
+// external secrets, and values that only use JS templates, aren't returned.
+func DevOnlyEnvVars(envVars api.EnvVars) []string {
+	values := make(map[string]string, len(envVars))
+	for name, v := range envVars {
+		if v.Value != nil {
+			values[name] = *v.Value
+		} else {
+			values[name] = ""
+		}
+	}
+	r := &envResolver{envVars: values}
+
+	var names []string
+	for _, name := range sortedEnvVarNames(values) {
+		for _, m := range envExprRegex.FindAllStringSubmatch(values[name], -1) {
+			if expr, ok := parseEnvExpr(m[1]); ok && r.owns(expr) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+type envResolver struct {
+	envVars  map[string]string
+	vars     EnvExprVars
+	resolved map[string]string
+	// visiting is the set of env vars being resolved, to detect cycles.
+	visiting map[string]bool
+}
+
+func (r *envResolver) resolve(name string) (string, error) {
+	if value, ok := r.resolved[name]; ok {
+		return value, nil
+	}
+	if r.visiting[name] {
+		return "", errors.Errorf("env var %s references itself through other env vars", name)
+	}
+	r.visiting[name] = true
+	defer delete(r.visiting, name)
+
+	value, err := r.expand(r.envVars[name])
+	if err != nil {
+		return "", errors.Wrapf(err, "env var %s", name)
+	}
+	r.resolved[name] = value
+	return value, nil
+}
+
+func (r *envResolver) expand(s string) (string, error) {
+	var err error
+	expanded := envExprRegex.ReplaceAllStringFunc(s, func(match string) string {
+		if err != nil {
+			return match
+		}
+		expr, ok := parseEnvExpr(envExprRegex.FindStringSubmatch(match)[1])
+		if !ok || !r.owns(expr) {
+			// A JS template.
+			return match
+		}
+		var value *string
+		value, err = r.eval(expr)
+		if err == nil && value == nil {
+			err = errors.Errorf("%s is not defined", strings.TrimSpace(match))
+		}
+		if err != nil {
+			return match
+		}
+		return *value
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
+
+// owns returns whether an expression should be evaluated by the resolver, rather than left as a
+// JS template.
+func (r *envResolver) owns(e envExpr) bool {
+	for _, ref := range e.refs() {
+		switch ref.namespace {
+		case "env":
+			if _, ok := r.envVars[ref.name]; !ok && jstEnvFields[ref.name] {
+				return false
+			}
+		case "configs", "airplane":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// eval evaluates an expression. It returns nil if the expression references something that isn't
+// defined.
+func (r *envResolver) eval(e envExpr) (*string, error) {
+	switch e.kind {
+	case envExprLiteral:
+		return &e.value, nil
+	case envExprRef:
+		return r.lookup(e)
+	case envExprConcat:
+		var sb strings.Builder
+		for _, operand := range e.operands {
+			v, err := r.eval(operand)
+			if err != nil || v == nil {
+				return nil, err
+			}
+			sb.WriteString(*v)
+		}
+		s := sb.String()
+		return &s, nil
+	case envExprOr:
+		var last *string
+		for _, operand := range e.operands {
+			v, err := r.eval(operand)
+			if err != nil {
+				return nil, err
+			}
+			if v != nil && *v != "" {
+				return v, nil
+			}
+			last = v
+		}
+		return last, nil
+	default:
+		return nil, errors.Errorf("unknown expression kind %d", e.kind)
+	}
+}
+
+func (r *envResolver) lookup(ref envExpr) (*string, error) {
+	switch ref.namespace {
+	case "env":
+		if _, ok := r.envVars[ref.name]; !ok {
+			return nil, nil
+		}
+		v, err := r.resolve(ref.name)
+		if err != nil {
+			return nil, err
+		}
+		return &v, nil
+	case "configs":
+		if r.vars.Config == nil {
+			return nil, nil
+		}
+		v, ok, err := r.vars.Config(ref.name)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return &v, nil
+	default:
+		v, ok := r.vars.Builtins[ref.name]
+		if !ok {
+			if !isEnvExprBuiltin(ref.name) {
+				return nil, errors.Errorf("unknown built-in airplane.%s: expected one of %s", ref.name, strings.Join(EnvExprBuiltins, ", "))
+			}
+			return nil, nil
+		}
+		return &v, nil
+	}
+}
+
+func isEnvExprBuiltin(name string) bool {
+	for _, b := range EnvExprBuiltins {
+		if b == "airplane."+name {
+			return true
+		}
+	}
+	return false
+}
+
+type envExprKind int
+
+const (
+	envExprLiteral envExprKind = iota
+	envExprRef
+	envExprConcat
+	envExprOr
+)
+
+// envExpr is a node of a parsed expression.
+type envExpr struct {
+	kind envExprKind
+	// value is the value of literals.
+	value string
+	// namespace and name identify the value that references point to, e.g. "configs" and
+	// "API_KEY".
+	namespace string
+	name      string
+	operands  []envExpr
+}
+
+func (e envExpr) refs() []envExpr {
+	if e.kind == envExprRef {
+		return []envExpr{e}
+	}
+	var refs []envExpr
+	for _, operand := range e.operands {
+		refs = append(refs, operand.refs()...)
+	}
+	return refs
+}
+
+var envExprTokenRegex = regexp.MustCompile(`^\s*(\|\||\+|'[^'\\]*'|"[^"\\]*"|[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)+)`)
+
+// parseEnvExpr parses an expression of the form `operand (|| operand)*`, where each operand is of
+// the form `term (+ term)*`, and each term is a reference or a string literal. It returns false if s
+// isn't such an expression.
+func parseEnvExpr(s string) (envExpr, bool) {
+	var tokens []string
+	for rest := s; strings.TrimSpace(rest) != ""; {
+		m := envExprTokenRegex.FindStringSubmatch(rest)
+		if m == nil {
+			return envExpr{}, false
+		}
+		tokens = append(tokens, m[1])
+		rest = rest[len(m[0]):]
+	}
+	if len(tokens) == 0 {
+		return envExpr{}, false
+	}
+
+	or := envExpr{kind: envExprOr}
+	concat := envExpr{kind: envExprConcat}
+	// expectTerm is whether the next token must be a term, rather than an operator.
+	expectTerm := true
+	for _, tok := range tokens {
+		isOperator := tok == "||" || tok == "+"
+		if isOperator == expectTerm {
+			return envExpr{}, false
+		}
+		expectTerm = isOperator
+		switch {
+		case tok == "||":
+			or.operands = append(or.operands, simplify(concat))
+			concat = envExpr{kind: envExprConcat}
+		case tok == "+":
+		case tok[0] == '\'' || tok[0] == '"':
+			concat.operands = append(concat.operands, envExpr{kind: envExprLiteral, value: tok[1 : len(tok)-1]})
+		default:
+			namespace, name, _ := strings.Cut(tok, ".")
+			concat.operands = append(concat.operands, envExpr{kind: envExprRef, namespace: namespace, name: name})
+		}
+	}
+	if expectTerm {
+		return envExpr{}, false
+	}
+	or.operands = append(or.operands, simplify(concat))
+	return simplify(or), true
+}
+
+// simplify unwraps concatenations and fallbacks of a single operand.
+func simplify(e envExpr) envExpr {
+	if len(e.operands) == 1 {
+		return e.operands[0]
+	}
+	return e
+}
+
+func sortedEnvVarNames(envVars map[string]string) []string {
+	names := make([]string, 0, len(envVars))
+	for name := range envVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
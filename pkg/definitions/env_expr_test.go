@@ -0,0 +1,130 @@
+package definitions
+
+import (
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveEnvVars(t *testing.T) {
+	vars := EnvExprVars{
+		Config: func(name string) (string, bool, error) {
+			if name == "API_KEY" {
+				return "secret", true, nil
+			}
+			return "", false, nil
+		},
+		Builtins: map[string]string{"env.slug": "studio", "slug": "my_task"},
+	}
+
+	for _, test := range []struct {
+		name     string
+		in       map[string]string
+		expected map[string]string
+		err      string
+	}{
+		{
+			name: "references",
+			in: map[string]string{
+				"BASE_URL": "https://api.example.com",
+				"API_URL":  "{{env.BASE_URL}}/v2",
+				"API_KEY":  "{{ configs.API_KEY }}",
+				"TAG":      "{{airplane.slug}}-{{airplane.env.slug}}",
+			},
+			expected: map[string]string{
+				"BASE_URL": "https://api.example.com",
+				"API_URL":  "https://api.example.com/v2",
+				"API_KEY":  "secret",
+				"TAG":      "my_task-studio",
+			},
+		},
+		{
+			name: "transitive references",
+			in: map[string]string{
+				"HOST":     "example.com",
+				"BASE_URL": "https://{{env.HOST}}",
+				"API_URL":  "{{env.BASE_URL}}/v2",
+			},
+			expected: map[string]string{
+				"HOST":     "example.com",
+				"BASE_URL": "https://example.com",
+				"API_URL":  "https://example.com/v2",
+			},
+		},
+		{
+			name: "operators",
+			in: map[string]string{
+				"EMPTY":    "",
+				"FALLBACK": "{{env.MISSING || env.EMPTY || 'default'}}",
+				"CONCAT":   `{{"https://" + airplane.env.slug + '.example.com'}}`,
+			},
+			expected: map[string]string{
+				"EMPTY":    "",
+				"FALLBACK": "default",
+				"CONCAT":   "https://studio.example.com",
+			},
+		},
+		{
+			name: "JS templates",
+			in: map[string]string{
+				"PARAM":    "{{params.name}}",
+				"ENV_SLUG": "{{env.slug}}",
+				"JS":       "{{JSON.stringify(params)}}",
+			},
+			expected: map[string]string{
+				"PARAM":    "{{params.name}}",
+				"ENV_SLUG": "{{env.slug}}",
+				"JS":       "{{JSON.stringify(params)}}",
+			},
+		},
+		{
+			name: "undefined env var",
+			in:   map[string]string{"API_URL": "{{env.BASE_URL}}/v2"},
+			err:  "env var API_URL: {{env.BASE_URL}} is not defined",
+		},
+		{
+			name: "undefined config var",
+			in:   map[string]string{"TOKEN": "{{configs.TOKEN}}"},
+			err:  "env var TOKEN: {{configs.TOKEN}} is not defined",
+		},
+		{
+			name: "unknown built-in",
+			in:   map[string]string{"ID": "{{airplane.run.id}}"},
+			err:  "unknown built-in airplane.run.id",
+		},
+		{
+			name: "cycle",
+			in:   map[string]string{"A": "{{env.B}}", "B": "{{env.A}}"},
+			err:  "references itself",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			out, err := ResolveEnvVars(test.in, vars)
+			if test.err != "" {
+				require.ErrorContains(err, test.err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(test.expected, out)
+		})
+	}
+}
+
+func TestDevOnlyEnvVars(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal([]string{"API_KEY", "API_URL", "REGION"}, DevOnlyEnvVars(api.EnvVars{
+		"BASE_URL": {Config: pointers.String("base_url")},
+		"API_URL":  {Value: pointers.String("{{env.BASE_URL}}/v2")},
+		"API_KEY":  {Value: pointers.String("{{configs.API_KEY}}")},
+		"REGION":   {Value: pointers.String("{{env.REGION_OVERRIDE || 'us'}}")},
+		// JS templates are evaluated by the API.
+		"ENV":   {Value: pointers.String("{{env.slug}}")},
+		"PARAM": {Value: pointers.String("{{params.name.toUpperCase()}}")},
+		"PLAIN": {Value: pointers.String("plain")},
+	}))
+	require.Empty(DevOnlyEnvVars(api.EnvVars{"PLAIN": {Value: pointers.String("plain")}}))
+}
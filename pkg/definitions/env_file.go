@@ -0,0 +1,58 @@
+package definitions
+
+import (
+	"path/filepath"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+)
+
+// GetEnvFiles returns the paths of the dotenv files that the task's env vars are read from, in
+// order of increasing precedence. Relative paths are resolved from the directory of the
+// definition file.
+//
+// Env files are only supported by airplane dev. Their variables take precedence over those of the
+// env sets in EnvFrom, and the task's own env vars take precedence over theirs.
+func (d Definition) GetEnvFiles() []string {
+	var files []string
+	switch {
+	case d.Node != nil:
+		files = d.Node.EnvFile
+	case d.Python != nil:
+		files = d.Python.EnvFile
+	case d.Shell != nil:
+		files = d.Shell.EnvFile
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	paths := make([]string, len(files))
+	for i, file := range files {
+		if filepath.IsAbs(file) || d.defnFilePath == "" {
+			paths[i] = file
+		} else {
+			paths[i] = filepath.Join(filepath.Dir(d.defnFilePath), file)
+		}
+	}
+	return paths
+}
+
+// ReadEnvFiles reads the env vars of dotenv files. Variables of later files take precedence over
+// the same variables of earlier files.
+func ReadEnvFiles(paths []string) (api.EnvVars, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	values, err := godotenv.Read(paths...)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading env files")
+	}
+	envVars := make(api.EnvVars, len(values))
+	for k, v := range values {
+		v := v
+		envVars[k] = api.EnvVarValue{Value: &v}
+	}
+	return envVars, nil
+}
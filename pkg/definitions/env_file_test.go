@@ -0,0 +1,43 @@
+package definitions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvFiles(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, ".env"), []byte("LOG_LEVEL=info\nAPI_URL=https://staging.example.com\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, ".env.production"), []byte("API_URL=https://api.example.com\n"), 0644))
+
+	var d Definition
+	require.NoError(d.Unmarshal(DefFormatYAML, []byte(`
+slug: sync_users
+python:
+  entrypoint: sync.py
+  envFile: [.env, .env.production]
+`)))
+	d.SetDefnFilePath(filepath.Join(dir, "sync_users.task.yaml"))
+	files := d.GetEnvFiles()
+	require.Equal([]string{filepath.Join(dir, ".env"), filepath.Join(dir, ".env.production")}, files)
+
+	envVars, err := ReadEnvFiles(files)
+	require.NoError(err)
+	require.Equal(api.EnvVars{
+		"LOG_LEVEL": {Value: pointers.String("info")},
+		"API_URL":   {Value: pointers.String("https://api.example.com")},
+	}, envVars)
+
+	_, err = ReadEnvFiles([]string{filepath.Join(dir, ".env.missing")})
+	require.ErrorContains(err, "reading env files")
+
+	// Kinds without env files have none.
+	require.Nil(Definition{SQL: &SQLDefinition{}}.GetEnvFiles())
+}
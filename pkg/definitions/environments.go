@@ -0,0 +1,139 @@
+package definitions
+
+import (
+	"bytes"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// EnvironmentDefinition overrides fields of a task when it's deployed to an environment.
+type EnvironmentDefinition struct {
+	// EnvVars are merged into the task's env vars.
+	EnvVars api.EnvVars `json:"envVars,omitempty"`
+	// Resources are merged into the task's resources, by alias.
+	Resources ResourcesDefinition `json:"resources,omitempty"`
+	// Schedules are merged into the task's schedules, by slug.
+	Schedules map[string]ScheduleDefinition `json:"schedules,omitempty"`
+	// Timeout replaces the task's timeout, if set.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// ForEnv returns the definition of the task in the given environment, with the overrides of that
+// environment applied. The returned definition has no environments of its own. If envSlug is
+// empty, or the task has no overrides for it, only the environments are removed.
+func (d Definition) ForEnv(envSlug string) (Definition, error) {
+	env, ok := d.Environments[envSlug]
+	d.Environments = nil
+	if envSlug == "" || !ok {
+		return d, nil
+	}
+
+	if len(env.EnvVars) > 0 {
+		if d.SQL != nil || d.REST != nil || d.Builtin != nil {
+			return Definition{}, errors.Errorf("environment %s sets env vars, but this kind of task has no env vars", envSlug)
+		}
+		// The kind is shared with d, so it's copied before its env vars are replaced.
+		d.copyKind()
+		envVars, err := d.GetEnv()
+		if err != nil {
+			return Definition{}, err
+		}
+		merged := api.EnvVars{}
+		for k, v := range envVars {
+			merged[k] = v
+		}
+		for k, v := range env.EnvVars {
+			merged[k] = v
+		}
+		if err := d.SetEnv(merged); err != nil {
+			return Definition{}, err
+		}
+	}
+
+	if len(env.Resources) > 0 {
+		resources := ResourcesDefinition{}
+		for alias, slug := range d.Resources {
+			resources[alias] = slug
+		}
+		for alias, slug := range env.Resources {
+			resources[alias] = slug
+		}
+		d.Resources = resources
+	}
+
+	if len(env.Schedules) > 0 {
+		schedules := map[string]ScheduleDefinition{}
+		for slug, schedule := range d.Schedules {
+			schedules[slug] = schedule
+		}
+		for slug, schedule := range env.Schedules {
+			schedules[slug] = schedule
+		}
+		d.Schedules = schedules
+	}
+
+	if env.Timeout != 0 {
+		d.Timeout = env.Timeout
+	}
+
+	return d, nil
+}
+
+// ResolveDeployedDefinition returns the task definition file at path, whose contents are buf, as
+// it's deployed to envSlug: with the shared files that it extends merged in, and with the overrides
+// of envSlug applied and its environments removed, see ForEnv. Deployed bundles carry the resolved
+// file, since their definitions are read from the bundle, which may not contain the shared files.
+// It returns nil if the definition has neither, in which case the file is deployed as is.
+func ResolveDeployedDefinition(path string, buf []byte, envSlug string) ([]byte, error) {
+	// Only definitions that have environments or extend files are parsed.
+	if !bytes.Contains(buf, []byte("environments")) && !HasExtends(buf) {
+		return nil, nil
+	}
+	var d Definition
+	if err := d.UnmarshalFile(path, buf); err != nil {
+		return nil, err
+	}
+	if len(d.Environments) == 0 && len(d.GetExtendedFiles()) == 0 {
+		return nil, nil
+	}
+	resolved, err := d.ForEnv(envSlug)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.Marshal(GetTaskDefFormat(path))
+}
+
+// copyKind replaces the kind-specific definition of d with a shallow copy, so that it can be
+// modified without modifying definitions that d was copied from.
+func (d *Definition) copyKind() {
+	switch {
+	case d.Image != nil:
+		c := *d.Image
+		d.Image = &c
+	case d.Node != nil:
+		c := *d.Node
+		d.Node = &c
+	case d.Python != nil:
+		c := *d.Python
+		d.Python = &c
+	case d.Shell != nil:
+		c := *d.Shell
+		d.Shell = &c
+	case d.Deno != nil:
+		c := *d.Deno
+		d.Deno = &c
+	case d.Go != nil:
+		c := *d.Go
+		d.Go = &c
+	case d.Ruby != nil:
+		c := *d.Ruby
+		d.Ruby = &c
+	case d.SQL != nil:
+		c := *d.SQL
+		d.SQL = &c
+	case d.REST != nil:
+		c := *d.REST
+		d.REST = &c
+	}
+}
@@ -0,0 +1,112 @@
+package definitions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitionForEnv(t *testing.T) {
+	require := require.New(t)
+
+	var d Definition
+	require.NoError(d.Unmarshal(DefFormatYAML, []byte(`
+slug: sync_users
+python:
+  entrypoint: sync.py
+  envVars:
+    LOG_LEVEL: info
+    API_URL: https://api.example.com
+resources:
+  db: prod_db
+schedules:
+  nightly:
+    cron: 0 0 * * *
+timeout: 600
+environments:
+  staging:
+    envVars:
+      API_URL: https://staging.example.com
+    resources:
+      db: staging_db
+    schedules:
+      nightly:
+        cron: 0 12 * * *
+        paused: true
+    timeout: 60
+`)))
+
+	staging, err := d.ForEnv("staging")
+	require.NoError(err)
+	require.Nil(staging.Environments)
+	require.Equal(api.EnvVars{
+		"LOG_LEVEL": {Value: pointers.String("info")},
+		"API_URL":   {Value: pointers.String("https://staging.example.com")},
+	}, staging.Python.EnvVars)
+	require.Equal(ResourcesDefinition{"db": "staging_db"}, staging.Resources)
+	require.Equal(map[string]ScheduleDefinition{
+		"nightly": {CronExpr: "0 12 * * *", Paused: true},
+	}, staging.Schedules)
+	require.Equal(60, staging.Timeout)
+
+	// The original definition is unchanged.
+	require.Len(d.Environments, 1)
+	require.Equal("https://api.example.com", *d.Python.EnvVars["API_URL"].Value)
+	require.Equal(ResourcesDefinition{"db": "prod_db"}, d.Resources)
+	require.Equal(600, d.Timeout)
+
+	// Environments without overrides use the task's own configuration.
+	prod, err := d.ForEnv("prod")
+	require.NoError(err)
+	require.Nil(prod.Environments)
+	require.Equal(d.Python, prod.Python)
+	require.Equal(600, prod.Timeout)
+
+	sql := Definition{
+		Slug:         "report",
+		SQL:          &SQLDefinition{Resource: "db", Entrypoint: "report.sql"},
+		Environments: map[string]EnvironmentDefinition{"staging": {EnvVars: api.EnvVars{"A": {}}}},
+	}
+	_, err = sql.ForEnv("staging")
+	require.ErrorContains(err, "has no env vars")
+}
+
+func TestResolveDeployedDefinition(t *testing.T) {
+	require := require.New(t)
+
+	// Definitions without environments are deployed as they are.
+	buf, err := ResolveDeployedDefinition("task.task.yaml", []byte("slug: a\nshell:\n  entrypoint: a.sh\n"), "staging")
+	require.NoError(err)
+	require.Nil(buf)
+
+	buf, err = ResolveDeployedDefinition("task.task.yaml", []byte(`
+slug: a
+shell:
+  entrypoint: a.sh
+timeout: 600
+environments:
+  staging:
+    timeout: 60
+`), "staging")
+	require.NoError(err)
+	var d Definition
+	require.NoError(d.Unmarshal(DefFormatYAML, buf))
+	require.Equal(60, d.Timeout)
+	require.Nil(d.Environments)
+	require.Equal("a.sh", d.Shell.Entrypoint)
+
+	// Shared files are merged in, since bundles may not contain them.
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "common.yaml"), []byte("timeout: 300\n"), 0644))
+	buf, err = ResolveDeployedDefinition(filepath.Join(dir, "task.task.yaml"), []byte("include: common.yaml\nslug: a\nshell:\n  entrypoint: a.sh\n"), "staging")
+	require.NoError(err)
+	require.False(HasExtends(buf))
+	d = Definition{}
+	require.NoError(d.Unmarshal(DefFormatYAML, buf))
+	require.Equal(300, d.Timeout)
+	require.Equal("a.sh", d.Shell.Entrypoint)
+}
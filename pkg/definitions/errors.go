@@ -42,6 +42,9 @@ func (err errReadDefinition) ExplainError() string {
 
 type ErrSchemaValidation struct {
 	Errors []gojsonschema.ResultError
+	// ExtendedFiles are the shared files that the definition extends, which may have set the
+	// invalid fields.
+	ExtendedFiles []string
 }
 
 func (err ErrSchemaValidation) Error() string {
@@ -0,0 +1,214 @@
+package definitions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/pkg/errors"
+)
+
+// extendsFields are the fields of task definitions that list the shared files that they extend.
+// include is an alias of extends: the files of both are merged, those of extends first.
+var extendsFields = []string{"extends", "include"}
+
+// UnmarshalFile unmarshals the task definition file at path. If the definition extends shared
+// files, e.g. with common permissions, schedules or env vars, they're merged into it first. See
+// resolveExtends.
+func (d *Definition) UnmarshalFile(path string, buf []byte) error {
+	format := GetTaskDefFormat(path)
+	buf, extended, err := resolveExtends(path, format, buf)
+	if err != nil {
+		return err
+	}
+	if len(extended) > 0 {
+		format = DefFormatJSON
+	}
+	if err := d.Unmarshal(format, buf); err != nil {
+		if verr, ok := errors.Cause(err).(ErrSchemaValidation); ok && len(extended) > 0 {
+			verr.ExtendedFiles = extended
+			return errors.WithStack(verr)
+		}
+		return err
+	}
+	d.extendedFiles = extended
+	return nil
+}
+
+// GetExtendedFiles returns the paths of the shared files that the definition extends, including
+// the files that those extend in turn.
+func (d Definition) GetExtendedFiles() []string {
+	return d.extendedFiles
+}
+
+// HasExtends returns whether the task definition in buf extends shared files. Such definitions
+// can't be rewritten from their unmarshalled form without inlining the shared files.
+func HasExtends(buf []byte) bool {
+	def, err := parseDefinitionObject("", buf)
+	if err != nil {
+		return false
+	}
+	return hasExtends(def)
+}
+
+func hasExtends(def map[string]interface{}) bool {
+	for _, f := range extendsFields {
+		if _, ok := def[f]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveExtends merges the shared files that the definition in buf extends into it, and returns
+// it as JSON along with the paths of those files. Definitions that don't extend any files are
+// returned as they are.
+//
+// The extends field, or its alias include, is a path, or a list of paths, relative to the file
+// that it's in. Shared files
+// are YAML or JSON objects with any fields of a task definition, and may extend other files. Files
+// are merged in order, and the definition itself is merged last: objects are merged by key, and
+// other values, including lists, replace the values of earlier files. Paths in shared files, e.g.
+// entrypoints, are resolved from the definition file, as if they were written there.
+func resolveExtends(path string, format DefFormat, buf []byte) ([]byte, []string, error) {
+	switch format {
+	case DefFormatYAML, DefFormatJSON:
+	default:
+		return nil, nil, errors.Errorf("unknown format: %s", format)
+	}
+	// JSON is YAML, so both formats are parsed as YAML.
+	def, err := parseDefinitionObject(path, buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hasExtends(def) {
+		return buf, nil, nil
+	}
+
+	var extended []string
+	merged, err := mergeExtends(path, def, []string{path}, &extended)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "marshalling definition")
+	}
+	return out, extended, nil
+}
+
+// mergeExtends returns def, which was read from path, merged into the files that it extends.
+// chain is the path of files that extend each other, ending with path, which is used to detect
+// cycles and to explain errors.
+func mergeExtends(path string, def map[string]interface{}, chain []string, extended *[]string) (map[string]interface{}, error) {
+	var paths []string
+	for _, f := range extendsFields {
+		fieldPaths, err := extendsPaths(path, f, def[f])
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, fieldPaths...)
+		delete(def, f)
+	}
+
+	merged := map[string]interface{}{}
+	for _, p := range paths {
+		for _, c := range chain {
+			if sameFile(c, p) {
+				return nil, errors.Errorf("extends cycle: %s", strings.Join(append(chain, p), " -> "))
+			}
+		}
+		buf, err := os.ReadFile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", extendedBy(append(chain, p)))
+		}
+		base, err := parseDefinitionObject(p, buf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", extendedBy(append(chain, p)))
+		}
+		*extended = append(*extended, p)
+		if base, err = mergeExtends(p, base, append(chain, p), extended); err != nil {
+			return nil, err
+		}
+		merged = mergeObjects(merged, base)
+	}
+	return mergeObjects(merged, def), nil
+}
+
+// extendsPaths returns the paths in the given extends field of the file at path.
+func extendsPaths(path, field string, v interface{}) ([]string, error) {
+	var paths []string
+	switch v := v.(type) {
+	case nil:
+	case string:
+		paths = []string{v}
+	case []interface{}:
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return nil, errors.Errorf("%s: %s must be a path or a list of paths", path, field)
+			}
+			paths = append(paths, s)
+		}
+	default:
+		return nil, errors.Errorf("%s: %s must be a path or a list of paths", path, field)
+	}
+
+	for i, p := range paths {
+		if p == "" {
+			return nil, errors.Errorf("%s: %s has an empty path", path, field)
+		}
+		if !filepath.IsAbs(p) {
+			paths[i] = filepath.Join(filepath.Dir(path), p)
+		}
+	}
+	return paths, nil
+}
+
+func parseDefinitionObject(path string, buf []byte) (map[string]interface{}, error) {
+	buf, err := yaml.YAMLToJSON(buf)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(buf, &obj); err != nil {
+		return nil, errors.Errorf("%s: expected an object", path)
+	}
+	if obj == nil {
+		obj = map[string]interface{}{}
+	}
+	return obj, nil
+}
+
+// mergeObjects merges src into dst: objects are merged by key, and other values replace the
+// values of dst.
+func mergeObjects(dst, src map[string]interface{}) map[string]interface{} {
+	for k, v := range src {
+		srcObj, srcOK := v.(map[string]interface{})
+		dstObj, dstOK := dst[k].(map[string]interface{})
+		if srcOK && dstOK {
+			dst[k] = mergeObjects(dstObj, srcObj)
+		} else {
+			dst[k] = v
+		}
+	}
+	return dst
+}
+
+// extendedBy describes the last file of chain along with the files that extend it, e.g.
+// "schedules.yaml (via my_task.task.yaml -> common.yaml)".
+func extendedBy(chain []string) string {
+	return fmt.Sprintf("%s (via %s)", chain[len(chain)-1], strings.Join(chain[:len(chain)-1], " -> "))
+}
+
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
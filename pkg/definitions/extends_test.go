@@ -0,0 +1,118 @@
+package definitions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtends(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	common := writeFile("shared/common.yaml", `
+extends: schedules.yaml
+timeout: 600
+permissions:
+  viewers:
+    groups: [support]
+  executers:
+    groups: [eng]
+python:
+  envVars:
+    LOG_LEVEL: info
+    REGION: us-west-2
+`)
+	schedules := writeFile("shared/schedules.yaml", `
+schedules:
+  nightly:
+    cron: 0 0 * * *
+`)
+	path := writeFile("tasks/sync_users.task.yaml", `
+extends: ../shared/common.yaml
+slug: sync_users
+permissions:
+  executers:
+    groups: [ops]
+python:
+  entrypoint: sync_users.py
+  envVars:
+    LOG_LEVEL: debug
+`)
+	buf, err := os.ReadFile(path)
+	require.NoError(err)
+	require.True(HasExtends(buf))
+
+	var d Definition
+	require.NoError(d.UnmarshalFile(path, buf))
+	require.Equal("sync_users", d.Slug)
+	require.Equal(600, d.Timeout)
+	require.Equal("sync_users.py", d.Python.Entrypoint)
+	require.Equal("debug", *d.Python.EnvVars["LOG_LEVEL"].Value)
+	require.Equal("us-west-2", *d.Python.EnvVars["REGION"].Value)
+	require.Equal("0 0 * * *", d.Schedules["nightly"].CronExpr)
+	// Objects are merged by key, and lists are replaced.
+	require.Equal([]string{"support"}, d.Permissions.Viewers.Groups)
+	require.Equal([]string{"ops"}, d.Permissions.Executers.Groups)
+	require.Equal([]string{common, schedules}, d.GetExtendedFiles())
+
+	// Definitions without extends are unmarshalled as they are.
+	plain := []byte("slug: plain\npython:\n  entrypoint: plain.py\n")
+	require.False(HasExtends(plain))
+	d = Definition{}
+	require.NoError(d.UnmarshalFile(filepath.Join(dir, "tasks/plain.task.yaml"), plain))
+	require.Equal("plain", d.Slug)
+	require.Empty(d.GetExtendedFiles())
+
+	// Errors explain which definition the files are read for.
+	d = Definition{}
+	err = d.UnmarshalFile(filepath.Join(dir, "tasks/broken.task.yaml"), []byte("extends: ../shared/missing.yaml\nslug: broken\n"))
+	require.ErrorContains(err, "reading "+filepath.Join(dir, "shared/missing.yaml")+" (via "+filepath.Join(dir, "tasks/broken.task.yaml")+")")
+
+	// Invalid fields of shared files are reported along with the files.
+	writeFile("shared/invalid.yaml", "timeout: soon\n")
+	d = Definition{}
+	err = d.UnmarshalFile(path, []byte("extends: ../shared/invalid.yaml\nslug: sync_users\npython:\n  entrypoint: sync_users.py\n"))
+	var verr ErrSchemaValidation
+	require.ErrorAs(err, &verr)
+	require.Equal([]string{filepath.Join(dir, "shared/invalid.yaml")}, verr.ExtendedFiles)
+
+	// Cycles are detected.
+	writeFile("shared/a.yaml", "extends: b.yaml\n")
+	writeFile("shared/b.yaml", "extends: [a.yaml]\n")
+	d = Definition{}
+	err = d.UnmarshalFile(path, []byte("extends: ../shared/a.yaml\nslug: sync_users\n"))
+	require.ErrorContains(err, "extends cycle: "+path+" -> "+filepath.Join(dir, "shared/a.yaml")+" -> "+filepath.Join(dir, "shared/b.yaml")+" -> "+filepath.Join(dir, "shared/a.yaml"))
+
+	d = Definition{}
+	err = d.UnmarshalFile(path, []byte("extends: {file: common.yaml}\nslug: sync_users\n"))
+	require.ErrorContains(err, "extends must be a path or a list of paths")
+
+	// include is an alias of extends, whose files are merged after those of extends.
+	writeFile("shared/long.yaml", "timeout: 3600\n")
+	d = Definition{}
+	require.NoError(d.UnmarshalFile(path, []byte("extends: ../shared/common.yaml\ninclude: [../shared/long.yaml]\nslug: sync_users\npython:\n  entrypoint: sync_users.py\n")))
+	require.Equal(3600, d.Timeout)
+	require.Equal([]string{"support"}, d.Permissions.Viewers.Groups)
+	require.True(HasExtends([]byte("include: ../shared/long.yaml\n")))
+}
+
+func TestValidateSchemaExtends(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "common.yaml"), []byte("python:\n  entrypoint: my_task.py\n"), 0644))
+
+	// Fields that are set by shared files aren't missing.
+	errs, err := ValidateSchema(filepath.Join(dir, "my_task.task.yaml"), []byte("extends: common.yaml\nslug: my_task\n"))
+	require.NoError(err)
+	require.Empty(errs)
+}
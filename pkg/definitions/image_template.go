@@ -0,0 +1,65 @@
+package definitions
+
+import (
+	"github.com/airplanedev/cli/pkg/utils/templates"
+	"github.com/pkg/errors"
+)
+
+// TemplateVars are the deploy-time variables that can be referenced in templated definition
+// fields, e.g. `image: registry/app:{{git.shortSHA}}`.
+type TemplateVars struct {
+	Git GitTemplateVars
+}
+
+// GitTemplateVars describe the commit that a task is deployed from.
+type GitTemplateVars struct {
+	// SHA is the full hash of the commit.
+	SHA string
+	// Ref is the branch or ref that was checked out, e.g. main.
+	Ref string
+}
+
+const shortSHALength = 7
+
+// Values returns the values of the variables that are available, keyed by the names that
+// templates reference them by, e.g. git.shortSHA. It returns nil if no variables are available.
+func (v TemplateVars) Values() map[string]string {
+	var values map[string]string
+	if sha := v.Git.SHA; sha != "" {
+		shortSHA := sha
+		if len(shortSHA) > shortSHALength {
+			shortSHA = shortSHA[:shortSHALength]
+		}
+		values = map[string]string{"git.sha": sha, "git.shortSHA": shortSHA}
+	}
+	if v.Git.Ref != "" {
+		if values == nil {
+			values = map[string]string{}
+		}
+		values["git.ref"] = v.Git.Ref
+	}
+	return values
+}
+
+func (v TemplateVars) lookup(name string) (string, error) {
+	switch name {
+	case "git.sha", "git.shortSHA", "git.ref":
+	default:
+		return "", errors.Errorf("unknown template variable %q: expected one of git.sha, git.shortSHA, git.ref", name)
+	}
+	value := v.Values()[name]
+	if value == "" {
+		return "", errors.Errorf("template variable %q is not available: deploy from a git repository to use git variables", name)
+	}
+	return value, nil
+}
+
+// IsTemplated returns whether s references any template variables.
+func IsTemplated(s string) bool {
+	return templates.IsTemplated(s)
+}
+
+// ResolveTemplate replaces the template variables referenced in s with their values.
+func ResolveTemplate(s string, vars TemplateVars) (string, error) {
+	return templates.Expand(s, vars.lookup)
+}
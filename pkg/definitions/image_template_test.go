@@ -0,0 +1,81 @@
+package definitions
+
+import (
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTemplate(t *testing.T) {
+	vars := TemplateVars{Git: GitTemplateVars{
+		SHA: "0123456789abcdef0123456789abcdef01234567",
+		Ref: "main",
+	}}
+
+	for _, test := range []struct {
+		in       string
+		expected string
+		err      string
+	}{
+		{in: "alpine:3", expected: "alpine:3"},
+		{in: "registry/app:{{git.shortSHA}}", expected: "registry/app:0123456"},
+		{in: "registry/app:{{ git.ref }}-{{git.sha}}", expected: "registry/app:main-0123456789abcdef0123456789abcdef01234567"},
+		{in: "registry/app:{{git.tag}}", err: `unknown template variable "git.tag"`},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			require := require.New(t)
+			out, err := ResolveTemplate(test.in, vars)
+			if test.err != "" {
+				require.ErrorContains(err, test.err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(test.expected, out)
+		})
+	}
+
+	_, err := ResolveTemplate("registry/app:{{git.shortSHA}}", TemplateVars{})
+	require.ErrorContains(t, err, "deploy from a git repository")
+}
+
+func TestImageTemplate(t *testing.T) {
+	require := require.New(t)
+
+	def := Definition{
+		Slug:  "my_task",
+		Image: &ImageDefinition{Image: "registry/app:{{git.shortSHA}}"},
+	}
+
+	task, err := def.GetTask(GetTaskOpts{
+		TemplateVars: &TemplateVars{Git: GitTemplateVars{SHA: "abcdef0123456789"}},
+	})
+	require.NoError(err)
+	require.Equal("registry/app:abcdef0", pointers.ToString(task.Image))
+
+	// Without template vars, the image is left as-is.
+	task, err = def.GetTask(GetTaskOpts{})
+	require.NoError(err)
+	require.Equal("registry/app:{{git.shortSHA}}", pointers.ToString(task.Image))
+
+	// Updating from a deployed task keeps the template, unless the image no longer matches it.
+	require.NoError(def.Image.update(api.UpdateTaskRequest{Image: pointers.String("registry/app:abcdef0")}, nil))
+	require.Equal("registry/app:{{git.shortSHA}}", def.Image.Image)
+	require.NoError(def.Image.update(api.UpdateTaskRequest{Image: pointers.String("registry/other:latest")}, nil))
+	require.Equal("registry/other:latest", def.Image.Image)
+}
+
+func TestTemplateVarsValues(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(TemplateVars{}.Values())
+	require.Equal(map[string]string{
+		"git.sha":      "0123456789abcdef0123456789abcdef01234567",
+		"git.shortSHA": "0123456",
+		"git.ref":      "main",
+	}, TemplateVars{Git: GitTemplateVars{
+		SHA: "0123456789abcdef0123456789abcdef01234567",
+		Ref: "main",
+	}}.Values())
+}
@@ -0,0 +1,246 @@
+// lint checks task definitions for likely mistakes that are valid according to the definition
+// schema, e.g. parameters that the task never reads.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cronexpr"
+)
+
+// MaxTimeout is the longest timeout of a task with the standard runtime.
+const MaxTimeout = 12 * time.Hour
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a problem found in a task definition.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Slug     string   `json:"slug"`
+	// File is the file that the task is defined in, if any.
+	File string `json:"file,omitempty"`
+	// Field is the path of the field with the problem, e.g. "schedules.daily.cron".
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.Field == "" {
+		return fmt.Sprintf("%s: %s (%s)", f.Severity, f.Message, f.Rule)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Severity, f.Field, f.Message, f.Rule)
+}
+
+// Options configures the checks of Lint.
+type Options struct {
+	// Resources are the resources of the team. If nil, resources aren't checked.
+	Resources []api.ResourceMetadata
+}
+
+// problem is a finding of a rule, before it's attributed to a definition.
+type problem struct {
+	field   string
+	message string
+}
+
+type rule struct {
+	name     string
+	severity Severity
+	check    func(d *definitions.Definition, opts Options) []problem
+}
+
+// rules lists the checks that Lint runs, in the order that their findings are reported.
+var rules = []rule{
+	{name: "missing-description", severity: SeverityWarning, check: checkDescription},
+	{name: "unused-parameter", severity: SeverityWarning, check: checkUnusedParameters},
+	{name: "invalid-cron", severity: SeverityError, check: checkSchedules},
+	{name: "unknown-resource", severity: SeverityError, check: checkResources},
+	{name: "timeout-limit", severity: SeverityError, check: checkTimeout},
+}
+
+// Rules returns the names of the rules that Lint runs.
+func Rules() []string {
+	names := make([]string, len(rules))
+	for i, r := range rules {
+		names[i] = r.name
+	}
+	return names
+}
+
+// Lint runs every rule against d.
+func Lint(d *definitions.Definition, opts Options) []Finding {
+	var findings []Finding
+	for _, r := range rules {
+		for _, p := range r.check(d, opts) {
+			findings = append(findings, Finding{
+				Rule:     r.name,
+				Severity: r.severity,
+				Slug:     d.GetSlug(),
+				File:     d.GetDefnFilePath(),
+				Field:    p.field,
+				Message:  p.message,
+			})
+		}
+	}
+	return findings
+}
+
+func checkDescription(d *definitions.Definition, _ Options) []problem {
+	if strings.TrimSpace(d.Description) != "" {
+		return nil
+	}
+	return []problem{{field: "description", message: "the task has no description"}}
+}
+
+// checkUnusedParameters reports parameters that the task's source never mentions. Only tasks whose
+// source is separate from their definition are checked, since the definition of inline tasks
+// mentions every parameter.
+func checkUnusedParameters(d *definitions.Definition, _ Options) []problem {
+	if len(d.Parameters) == 0 {
+		return nil
+	}
+	source, ok := taskSource(d)
+	if !ok {
+		return nil
+	}
+
+	var problems []problem
+	for i, p := range d.Parameters {
+		// Slugs are matched case-insensitively, since shell tasks read them from env vars, e.g.
+		// PARAM_USER_ID.
+		re := regexp.MustCompile(`(?i)(?:^|[^a-z0-9])` + regexp.QuoteMeta(p.Slug) + `(?:[^a-z0-9_]|$)`)
+		if !re.MatchString(source) {
+			problems = append(problems, problem{
+				field:   fmt.Sprintf("parameters[%d]", i),
+				message: fmt.Sprintf("parameter %s is never used by the task", p.Slug),
+			})
+		}
+	}
+	return problems
+}
+
+// taskSource returns the source of the task that parameters are referenced from: the entrypoint
+// of the task, and any kind-specific fields that parameters can be templated into.
+func taskSource(d *definitions.Definition) (string, bool) {
+	var fields interface{}
+	switch {
+	case d.REST != nil:
+		fields = d.REST
+	case d.Image != nil:
+		fields = d.Image
+	case d.SQL != nil:
+		fields = d.SQL.QueryArgs
+	}
+	var source strings.Builder
+	if fields != nil {
+		buf, err := json.Marshal(fields)
+		if err != nil {
+			return "", false
+		}
+		source.Write(buf)
+	}
+
+	entrypoint, err := d.GetAbsoluteEntrypoint()
+	switch {
+	case err == definitions.ErrNoEntrypoint:
+		return source.String(), fields != nil
+	case err != nil, entrypoint == d.GetDefnFilePath():
+		return "", false
+	}
+	buf, err := os.ReadFile(entrypoint)
+	if err != nil {
+		return "", false
+	}
+	source.Write(buf)
+	return source.String(), true
+}
+
+func checkSchedules(d *definitions.Definition, _ Options) []problem {
+	var problems []problem
+	for _, slug := range sortedKeys(d.Schedules) {
+		cron := d.Schedules[slug].CronExpr
+		if len(strings.Fields(cron)) != 5 {
+			problems = append(problems, problem{
+				field:   "schedules." + slug + ".cron",
+				message: fmt.Sprintf("%q must have 5 fields: minute, hour, day of month, month and day of week", cron),
+			})
+			continue
+		}
+		if _, err := cronexpr.Parse(cron); err != nil {
+			problems = append(problems, problem{
+				field:   "schedules." + slug + ".cron",
+				message: fmt.Sprintf("%q is not a valid cron expression: %v", cron, err),
+			})
+		}
+	}
+	return problems
+}
+
+func checkResources(d *definitions.Definition, opts Options) []problem {
+	if opts.Resources == nil {
+		return nil
+	}
+	attachments, err := d.GetResourceAttachments()
+	if err != nil {
+		return nil
+	}
+	known := map[string]bool{}
+	for _, r := range opts.Resources {
+		known[r.Slug] = true
+		// Resources that are referenced by name are reported by `airplane fix`.
+		if r.DefaultEnvResource != nil {
+			known[r.DefaultEnvResource.Name] = true
+		}
+	}
+
+	var problems []problem
+	for _, alias := range sortedKeys(attachments) {
+		slug := attachments[alias]
+		if slug == "" || known[slug] {
+			continue
+		}
+		problems = append(problems, problem{
+			field:   "resources." + alias,
+			message: fmt.Sprintf("resource %s does not exist", slug),
+		})
+	}
+	return problems
+}
+
+func checkTimeout(d *definitions.Definition, _ Options) []problem {
+	// Workflows can run for much longer than standard tasks.
+	if d.Runtime != buildtypes.TaskRuntimeStandard {
+		return nil
+	}
+	if time.Duration(d.Timeout)*time.Second <= MaxTimeout {
+		return nil
+	}
+	return []problem{{
+		field:   "timeout",
+		message: fmt.Sprintf("%d seconds is longer than the maximum of %d seconds", d.Timeout, int(MaxTimeout.Seconds())),
+	}}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,76 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	entrypoint := filepath.Join(dir, "hello.sh")
+	require.NoError(os.WriteFile(entrypoint, []byte("echo \"Hello, ${PARAM_USER_NAME}\"\n"), 0644))
+
+	d := definitions.Definition{
+		Slug: "hello",
+		Parameters: []definitions.ParameterDefinition{
+			{Slug: "user_name", Type: "shorttext"},
+			{Slug: "user", Type: "shorttext"},
+		},
+		Resources: definitions.ResourcesDefinition{"db": "prod_db", "api": "missing"},
+		Schedules: map[string]definitions.ScheduleDefinition{
+			"daily":   {CronExpr: "0 0 * * *"},
+			"seconds": {CronExpr: "0 0 0 * * *"},
+			"invalid": {CronExpr: "0 25 * * *"},
+		},
+		Timeout: 86400,
+		Shell:   &definitions.ShellDefinition{Entrypoint: "hello.sh"},
+	}
+	d.SetDefnFilePath(filepath.Join(dir, "hello.task.yaml"))
+	require.NoError(d.SetAbsoluteEntrypoint(entrypoint))
+
+	findings := Lint(&d, Options{
+		Resources: []api.ResourceMetadata{{Slug: "prod_db"}},
+	})
+	var fields []string
+	for _, f := range findings {
+		require.Equal("hello", f.Slug)
+		require.Equal(filepath.Join(dir, "hello.task.yaml"), f.File)
+		fields = append(fields, f.Rule+" "+f.Field)
+	}
+	require.Equal([]string{
+		"missing-description description",
+		"unused-parameter parameters[1]",
+		"invalid-cron schedules.invalid.cron",
+		"invalid-cron schedules.seconds.cron",
+		"unknown-resource resources.api",
+		"timeout-limit timeout",
+	}, fields)
+}
+
+func TestLintClean(t *testing.T) {
+	require := require.New(t)
+
+	d := definitions.Definition{
+		Slug:        "get_user",
+		Description: "Gets a user.",
+		Parameters:  []definitions.ParameterDefinition{{Slug: "id", Type: "integer"}},
+		Runtime:     buildtypes.TaskRuntimeWorkflow,
+		Timeout:     86400,
+		REST: &definitions.RESTDefinition{
+			Resource: "api",
+			Method:   "GET",
+			Path:     "/users/{{params.id}}",
+		},
+	}
+	require.Empty(Lint(&d, Options{}))
+
+	// Resources are only checked if they're known.
+	require.Len(Lint(&d, Options{Resources: []api.ResourceMetadata{}}), 1)
+}
@@ -0,0 +1,322 @@
+// openapi generates OpenAPI documents that describe how to execute tasks via the Airplane API, so
+// that typed clients can be generated for them.
+package openapi
+
+import (
+	"sort"
+
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+)
+
+// Version is the version of the OpenAPI specification that documents conform to.
+const Version = "3.1.0"
+
+// DefaultServerURL is the URL of the Airplane API.
+const DefaultServerURL = "https://api.airplane.dev"
+
+// ExecutePath is the path of the endpoint that executes tasks.
+const ExecutePath = "/v0/tasks/execute"
+
+// Options configures the document generated by Generate.
+type Options struct {
+	// Title is the title of the document. Defaults to "Airplane tasks".
+	Title string
+	// Version is the version of the document. Defaults to "1.0.0".
+	Version string
+	// ServerURL is the URL of the Airplane API. Defaults to DefaultServerURL.
+	ServerURL string
+}
+
+// Document is an OpenAPI document. Only the subset of the specification that Generate uses is
+// modeled.
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type PathItem struct {
+	Post *Operation `json:"post,omitempty"`
+}
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	In          string `json:"in,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema is a JSON schema.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Discriminator        *Discriminator     `json:"discriminator,omitempty"`
+}
+
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// Generate generates an OpenAPI document that describes executing each of defs.
+//
+// The execute endpoint takes the slug of the task to execute in its body, so every task shares a
+// single operation: its request body is one of the `<Task>ExecuteRequest` schemas, discriminated
+// by slug. The parameters of each task are described by a `<Task>Params` schema.
+func Generate(defs []definitions.Definition, opts Options) (Document, error) {
+	if opts.Title == "" {
+		opts.Title = "Airplane tasks"
+	}
+	if opts.Version == "" {
+		opts.Version = "1.0.0"
+	}
+	if opts.ServerURL == "" {
+		opts.ServerURL = DefaultServerURL
+	}
+
+	defs = append([]definitions.Definition{}, defs...)
+	sort.SliceStable(defs, func(i, j int) bool {
+		return defs[i].Slug < defs[j].Slug
+	})
+
+	schemas := map[string]*Schema{
+		"RunTaskResponse": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"runID": {Type: "string", Description: "The ID of the run that was created."},
+			},
+			Required: []string{"runID"},
+		},
+	}
+	body := &Schema{
+		Discriminator: &Discriminator{PropertyName: "slug", Mapping: map[string]string{}},
+	}
+	slugs := map[string]string{}
+	for _, def := range defs {
+		name := strcase.ToCamel(def.Slug)
+		if name == "" {
+			return Document{}, errors.Errorf("task %q has no slug", def.Name)
+		}
+		if other, ok := slugs[name]; ok {
+			return Document{}, errors.Errorf("tasks %s and %s both generate schemas named %s", other, def.Slug, name)
+		}
+		slugs[name] = def.Slug
+
+		params, err := paramsSchema(def)
+		if err != nil {
+			return Document{}, errors.Wrapf(err, "task %s", def.Slug)
+		}
+		schemas[name+"Params"] = params
+
+		ref := "#/components/schemas/" + name + "ExecuteRequest"
+		schemas[name+"ExecuteRequest"] = &Schema{
+			Type:        "object",
+			Title:       def.Name,
+			Description: def.Description,
+			Properties: map[string]*Schema{
+				"slug":        {Type: "string", Const: def.Slug},
+				"paramValues": {Ref: "#/components/schemas/" + name + "Params"},
+			},
+			Required: []string{"slug", "paramValues"},
+		}
+		body.OneOf = append(body.OneOf, &Schema{Ref: ref})
+		body.Discriminator.Mapping[def.Slug] = ref
+	}
+
+	return Document{
+		OpenAPI: Version,
+		Info:    Info{Title: opts.Title, Version: opts.Version},
+		Servers: []Server{{URL: opts.ServerURL}},
+		Paths: map[string]PathItem{
+			ExecutePath: {
+				Post: &Operation{
+					OperationID: "executeTask",
+					Summary:     "Execute a task",
+					Description: "Starts a run of the task with the given slug. The run executes asynchronously: poll the run with the returned ID for its status and outputs.",
+					Parameters: []Parameter{
+						{
+							Name:        "X-Team-ID",
+							In:          "header",
+							Description: "The ID of your team.",
+							Required:    true,
+							Schema:      &Schema{Type: "string"},
+						},
+						{
+							Name:        "envSlug",
+							In:          "query",
+							Description: "The slug of the environment to execute the task in. Defaults to the default environment.",
+							Schema:      &Schema{Type: "string"},
+						},
+					},
+					RequestBody: &RequestBody{
+						Required: true,
+						Content:  map[string]MediaType{"application/json": {Schema: body}},
+					},
+					Responses: map[string]Response{
+						"200": {
+							Description: "The run was created.",
+							Content: map[string]MediaType{
+								"application/json": {Schema: &Schema{Ref: "#/components/schemas/RunTaskResponse"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		Components: Components{
+			Schemas: schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				"apiKey": {
+					Type:        "apiKey",
+					Name:        "X-Airplane-API-Key",
+					In:          "header",
+					Description: "An Airplane API key.",
+				},
+			},
+		},
+		Security: []map[string][]string{{"apiKey": {}}},
+	}, nil
+}
+
+func paramsSchema(def definitions.Definition) (*Schema, error) {
+	additional := false
+	s := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: &additional,
+	}
+	for _, param := range def.Parameters {
+		ps, err := paramSchema(param)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parameter %s", param.Slug)
+		}
+		s.Properties[param.Slug] = ps
+		// Parameters with defaults can be omitted, even if they're required.
+		if param.Required.Value() && param.Default == nil {
+			s.Required = append(s.Required, param.Slug)
+		}
+	}
+	return s, nil
+}
+
+func paramSchema(param definitions.ParameterDefinition) (*Schema, error) {
+	s := &Schema{
+		Title:       param.Name,
+		Description: param.Description,
+		Pattern:     param.Regex,
+	}
+	switch param.Type {
+	case "shorttext", "longtext", "sql":
+		s.Type = "string"
+	case "boolean":
+		s.Type = "boolean"
+	case "integer":
+		s.Type = "integer"
+	case "float":
+		s.Type = "number"
+	case "date":
+		s.Type = "string"
+		s.Format = "date"
+	case "datetime":
+		s.Type = "string"
+		s.Format = "date-time"
+	case "upload", "file":
+		// Files are passed by the ID of an upload.
+		s.Type = "string"
+		if s.Description == "" {
+			s.Description = "The ID of an upload."
+		}
+	case "configvar":
+		// Config variables are passed by name.
+		s.Type = "string"
+		if s.Description == "" {
+			s.Description = "The name of a config variable."
+		}
+	default:
+		return nil, errors.Errorf("unknown parameter type: %q", param.Type)
+	}
+
+	if param.Default != nil {
+		s.Default = param.Default
+		if m, ok := param.Default.(map[string]interface{}); ok && param.Type == "configvar" {
+			s.Default = m["config"]
+		}
+	}
+
+	for _, opt := range param.Options {
+		switch {
+		case opt.Config != nil && param.Type == "configvar":
+			s.Enum = append(s.Enum, *opt.Config)
+		case opt.Config != nil:
+			// The value of the option is only known when the task is run, so the parameter's
+			// values can't be enumerated.
+			s.Enum = nil
+			return s, nil
+		default:
+			s.Enum = append(s.Enum, opt.Value)
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,75 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	require := require.New(t)
+
+	doc, err := Generate([]definitions.Definition{
+		{
+			Slug:        "send_invoice",
+			Name:        "Send invoice",
+			Description: "Sends an invoice to a customer.",
+			Parameters: []definitions.ParameterDefinition{
+				{Slug: "customer_id", Name: "Customer ID", Type: "shorttext", Regex: "^cus_"},
+				{Slug: "amount", Type: "float", Description: "In dollars."},
+				{Slug: "due", Type: "date", Required: definitions.NewDefaultTrueDefinition(false)},
+				{Slug: "currency", Type: "shorttext", Default: "usd", Options: []definitions.OptionDefinition{
+					{Label: "USD", Value: "usd"},
+					{Label: "EUR", Value: "eur"},
+				}},
+				{Slug: "token", Type: "configvar", Options: []definitions.OptionDefinition{
+					{Label: "Prod", Config: pointers.String("stripe_prod")},
+				}},
+			},
+		},
+		{Slug: "hello"},
+	}, Options{})
+	require.NoError(err)
+
+	require.Equal(Version, doc.OpenAPI)
+	require.Equal([]Server{{URL: DefaultServerURL}}, doc.Servers)
+
+	body := doc.Paths[ExecutePath].Post.RequestBody.Content["application/json"].Schema
+	require.Equal([]*Schema{
+		{Ref: "#/components/schemas/HelloExecuteRequest"},
+		{Ref: "#/components/schemas/SendInvoiceExecuteRequest"},
+	}, body.OneOf)
+	require.Equal("#/components/schemas/SendInvoiceExecuteRequest", body.Discriminator.Mapping["send_invoice"])
+
+	req := doc.Components.Schemas["SendInvoiceExecuteRequest"]
+	require.Equal("Send invoice", req.Title)
+	require.Equal("send_invoice", req.Properties["slug"].Const)
+	require.Equal("#/components/schemas/SendInvoiceParams", req.Properties["paramValues"].Ref)
+
+	params := doc.Components.Schemas["SendInvoiceParams"]
+	require.Equal([]string{"customer_id", "amount", "token"}, params.Required)
+	require.Equal(&Schema{Type: "string", Title: "Customer ID", Pattern: "^cus_"}, params.Properties["customer_id"])
+	require.Equal(&Schema{Type: "number", Description: "In dollars."}, params.Properties["amount"])
+	require.Equal(&Schema{Type: "string", Format: "date"}, params.Properties["due"])
+	require.Equal(&Schema{Type: "string", Default: "usd", Enum: []interface{}{"usd", "eur"}}, params.Properties["currency"])
+	require.Equal([]interface{}{"stripe_prod"}, params.Properties["token"].Enum)
+
+	require.Empty(doc.Components.Schemas["HelloParams"].Properties)
+
+	// The document should serialize without errors.
+	_, err = json.Marshal(doc)
+	require.NoError(err)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate([]definitions.Definition{
+		{Slug: "hello", Parameters: []definitions.ParameterDefinition{{Slug: "name", Type: "unknown"}}},
+	}, Options{})
+	require.Error(t, err)
+
+	_, err = Generate([]definitions.Definition{{Slug: "hello_world"}, {Slug: "helloWorld"}}, Options{})
+	require.Error(t, err)
+}
@@ -29,6 +29,9 @@ func convertParameterDefToAPI(param ParameterDefinition) (api.Parameter, error)
 		out.Component = api.ComponentEditorSQL
 	case "boolean", "upload", "integer", "float", "date", "datetime", "configvar":
 		out.Type = api.Type(param.Type)
+	case "file":
+		// file is an alias of upload.
+		out.Type = api.TypeUpload
 	default:
 		return api.Parameter{}, errors.Errorf("unknown parameter type: %q", param.Type)
 	}
@@ -95,6 +98,19 @@ func convertParameterDefToAPI(param ParameterDefinition) (api.Parameter, error)
 		}
 	}
 
+	if param.OptionsSource != nil {
+		if len(param.Options) > 0 {
+			return api.Parameter{}, errors.Errorf("parameter %s cannot set both static options and an options source", param.Slug)
+		}
+		out.Constraints.OptionsSource = &api.OptionsSource{
+			Task:        param.OptionsSource.Task,
+			ParamValues: param.OptionsSource.ParamValues,
+			Resources:   param.OptionsSource.Resources,
+			ValuePath:   param.OptionsSource.ValuePath,
+			LabelPath:   param.OptionsSource.LabelPath,
+		}
+	}
+
 	return out, nil
 }
 
@@ -202,6 +218,16 @@ func convertParameterAPIToDef(param api.Parameter) (ParameterDefinition, error)
 		}
 	}
 
+	if src := param.Constraints.OptionsSource; src != nil {
+		out.OptionsSource = &OptionsSourceDefinition{
+			Task:        src.Task,
+			ParamValues: src.ParamValues,
+			Resources:   src.Resources,
+			ValuePath:   src.ValuePath,
+			LabelPath:   src.LabelPath,
+		}
+	}
+
 	return out, nil
 }
 
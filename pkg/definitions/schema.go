@@ -0,0 +1,174 @@
+package definitions
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// SchemaError is a violation of the schema of a definition, at the position of the field that
+// violates it.
+type SchemaError struct {
+	// Field is the path of the field, e.g. "parameters.0.slug". It's empty for the root of the
+	// definition.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	// Line and Column are the 1-based position of the field in the definition file, or zero if
+	// it's unknown.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+func (e SchemaError) String() string {
+	var pos string
+	if e.Line > 0 {
+		pos = fmt.Sprintf("%d:%d: ", e.Line, e.Column)
+	}
+	if e.Field == "" {
+		return pos + e.Message
+	}
+	return fmt.Sprintf("%s%s: %s", pos, e.Field, e.Message)
+}
+
+// GetSchema returns the schema of the definition file at path: the task schema for task
+// definitions and the view schema for view definitions.
+func GetSchema(path string) (string, DefFormat, error) {
+	if format := GetTaskDefFormat(path); format != DefFormatUnknown {
+		return schemaStr, format, nil
+	}
+	if format := GetViewDefFormat(path); format != DefFormatUnknown {
+		return viewSchemaStr, format, nil
+	}
+	return "", DefFormatUnknown, errors.Errorf("%s is not a task or view definition file", filepath.Base(path))
+}
+
+// ValidateSchema validates the contents of the definition file at path against its schema. Unlike
+// Unmarshal, it reports every violation along with its position in the file, for editors and
+// `airplane validate`.
+func ValidateSchema(path string, buf []byte) ([]SchemaError, error) {
+	schema, format, err := GetSchema(path)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is YAML, so both formats are parsed by the YAML parser to find the positions of fields.
+	file, err := parser.ParseBytes(buf, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing definition")
+	}
+	if schema == schemaStr {
+		// Fields of the shared files that the definition extends are validated along with its own.
+		var extended []string
+		buf, extended, err = resolveExtends(path, format, buf)
+		if err != nil {
+			return nil, err
+		}
+		if len(extended) > 0 {
+			format = DefFormatJSON
+		}
+	}
+	if format == DefFormatYAML {
+		buf, err = yaml.YAMLToJSON(buf)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing definition")
+		}
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(schema), gojsonschema.NewBytesLoader(buf))
+	if err != nil {
+		return nil, errors.Wrap(err, "validating schema")
+	}
+	if result.Valid() {
+		return nil, nil
+	}
+
+	var body ast.Node
+	if len(file.Docs) > 0 {
+		body = file.Docs[0].Body
+	}
+	var errs []SchemaError
+	for _, re := range specificErrors(result.Errors()) {
+		field := re.Field()
+		if field == gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+			field = ""
+		}
+		e := SchemaError{Field: field, Message: re.Description()}
+		if node := lookupField(body, field); node != nil {
+			pos := node.GetToken().Position
+			e.Line, e.Column = pos.Line, pos.Column
+		}
+		errs = append(errs, e)
+	}
+	return errs, nil
+}
+
+// specificErrors drops the errors of combinators, e.g. "Must validate one and only one schema
+// (oneOf)", when the errors of the schema that matched best explain them.
+func specificErrors(errs []gojsonschema.ResultError) []gojsonschema.ResultError {
+	var specific []gojsonschema.ResultError
+	for _, e := range errs {
+		switch e.Type() {
+		case "number_one_of", "number_any_of", "number_all_of":
+		default:
+			specific = append(specific, e)
+		}
+	}
+	if len(specific) == 0 {
+		return errs
+	}
+	return specific
+}
+
+// lookupField returns the node of the field at path, e.g. "parameters.0.slug". If the field isn't
+// in the file, e.g. a missing required field, it returns the closest node that contains it.
+func lookupField(node ast.Node, path string) ast.Node {
+	if node == nil || path == "" {
+		return node
+	}
+	found := node
+	for _, key := range strings.Split(path, ".") {
+		pos, value := childNode(node, key)
+		if pos == nil {
+			break
+		}
+		found, node = pos, value
+	}
+	return found
+}
+
+// childNode returns the node of key in a mapping, or of the index key in a sequence: the node to
+// report its position at, which is the key of mapping entries, and the node of its value.
+func childNode(node ast.Node, key string) (ast.Node, ast.Node) {
+	switch n := node.(type) {
+	case *ast.TagNode:
+		return childNode(n.Value, key)
+	case *ast.AnchorNode:
+		return childNode(n.Value, key)
+	case *ast.MappingValueNode:
+		if mappingKey(n) == key {
+			return n.Key, n.Value
+		}
+	case *ast.MappingNode:
+		for _, v := range n.Values {
+			if mappingKey(v) == key {
+				return v.Key, v.Value
+			}
+		}
+	case *ast.SequenceNode:
+		i, err := strconv.Atoi(key)
+		if err == nil && i >= 0 && i < len(n.Values) {
+			return n.Values[i], n.Values[i]
+		}
+	}
+	return nil, nil
+}
+
+func mappingKey(n *ast.MappingValueNode) string {
+	return n.Key.GetToken().Value
+}
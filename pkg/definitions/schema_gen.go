@@ -0,0 +1,364 @@
+package definitions
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GenerateTaskSchema returns the published JSON Schema of task definitions, e.g. for editors.
+//
+// The schema is generated from the Definition struct: the fields that definitions may set, their
+// types, the task kinds and the defaults of DefaultTrueDefinition and DefaultOneDefinition fields
+// all come from the structs, so that the schema can't drift from what the CLI parses. Constraints
+// that Go types can't express, e.g. descriptions, enums and patterns, and the schemas of types
+// with custom JSON encodings are taken from the schema that definitions are validated against.
+func GenerateTaskSchema() ([]byte, error) {
+	g, err := newSchemaGenerator(schemaStr)
+	if err != nil {
+		return nil, err
+	}
+	root, err := g.object(reflect.TypeOf(Definition{}), nil)
+	if err != nil {
+		return nil, err
+	}
+	props := root["properties"].(map[string]interface{})
+	root["required"] = g.copy(g.resolve("#/$defs/baseDefinition")["required"])
+
+	// extends is resolved before definitions are unmarshaled, so it has no field.
+	props["extends"] = g.copy(g.lookup([]string{"extends"}))
+
+	// Exactly one kind is set. Builtin kinds, e.g. graphql, are registered as plugins rather than
+	// being fields of Definition.
+	var kinds []string
+	for i := 0; i < reflect.TypeOf(Definition{}).NumField(); i++ {
+		field := reflect.TypeOf(Definition{}).Field(i)
+		if field.Type.Kind() == reflect.Pointer && field.Type.Implements(reflect.TypeOf((*taskKind)(nil)).Elem()) {
+			kinds = append(kinds, jsonName(field))
+		}
+	}
+	for key, plugin := range builtinTaskPluginsByDefinitionKey {
+		kind, err := g.schema(reflect.TypeOf(plugin.GetTaskKindDefinition()), []string{key})
+		if err != nil {
+			return nil, err
+		}
+		props[key] = kind
+		kinds = append(kinds, key)
+	}
+	sort.Strings(kinds)
+	var oneOf []interface{}
+	for _, kind := range kinds {
+		oneOf = append(oneOf, map[string]interface{}{"required": []interface{}{kind}})
+	}
+	root["oneOf"] = oneOf
+
+	return marshalSchema("Task", root)
+}
+
+// GenerateViewSchema returns the published JSON Schema of view definitions. See
+// GenerateTaskSchema.
+func GenerateViewSchema() ([]byte, error) {
+	g, err := newSchemaGenerator(viewSchemaStr)
+	if err != nil {
+		return nil, err
+	}
+	root, err := g.object(reflect.TypeOf(ViewDefinition{}), nil)
+	if err != nil {
+		return nil, err
+	}
+	return marshalSchema("View", root)
+}
+
+// unpublishedFields are the fields of definition structs, by struct and JSON name, that aren't
+// written in definition files.
+var unpublishedFields = map[string]bool{
+	// The base of a view's image is set from the build config of its project.
+	"ViewDefinition.base": true,
+}
+
+// annotationKeywords are the keywords that generated schemas take from the validation schema.
+var annotationKeywords = []string{
+	"description", "examples", "default", "enum", "const", "pattern", "format", "maxLength",
+	"minimum", "maximum", "exclusiveMinimum", "uniqueItems", "propertyNames", "required",
+	"oneOf", "allOf",
+}
+
+type schemaGenerator struct {
+	// validation is the schema that definitions are validated against.
+	validation map[string]interface{}
+}
+
+func newSchemaGenerator(validation string) (*schemaGenerator, error) {
+	g := &schemaGenerator{}
+	if err := json.Unmarshal([]byte(validation), &g.validation); err != nil {
+		return nil, errors.Wrap(err, "parsing schema")
+	}
+	return g, nil
+}
+
+// schema generates the schema of values of type t, at path in definitions. Elements of lists are
+// at "[]" and values of maps are at "*".
+func (g *schemaGenerator) schema(t reflect.Type, path []string) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case reflect.TypeOf(DefaultTrueDefinition{}):
+		return g.annotate(map[string]interface{}{"type": "boolean", "default": true}, path), nil
+	case reflect.TypeOf(DefaultOneDefinition{}):
+		return g.annotate(map[string]interface{}{"type": "integer", "default": 1}, path), nil
+	}
+	if hasCustomJSON(t) {
+		// The struct doesn't describe the JSON of these types.
+		s := g.lookup(path)
+		if s == nil {
+			return nil, errors.Errorf("%s has a custom JSON encoding and isn't declared in the schema", strings.Join(path, "."))
+		}
+		return g.copy(s).(map[string]interface{}), nil
+	}
+	if t.Kind() == reflect.Interface {
+		// Any value is allowed, unless the schema declares otherwise.
+		if s := g.lookup(path); s != nil {
+			return g.copy(s).(map[string]interface{}), nil
+		}
+		return map[string]interface{}{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return g.annotate(map[string]interface{}{"type": "string"}, path), nil
+	case reflect.Bool:
+		return g.annotate(map[string]interface{}{"type": "boolean"}, path), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return g.annotate(map[string]interface{}{"type": "integer"}, path), nil
+	case reflect.Float32, reflect.Float64:
+		return g.annotate(map[string]interface{}{"type": "number"}, path), nil
+	case reflect.Slice, reflect.Array:
+		items, err := g.schema(t.Elem(), append(path, "[]"))
+		if err != nil {
+			return nil, err
+		}
+		return g.annotate(map[string]interface{}{"type": "array", "items": items}, path), nil
+	case reflect.Map:
+		values, err := g.schema(t.Elem(), append(path, "*"))
+		if err != nil {
+			return nil, err
+		}
+		s := map[string]interface{}{"type": "object", "additionalProperties": values}
+		if pattern := g.keyPattern(path); pattern != "" {
+			s["propertyNames"] = map[string]interface{}{"pattern": pattern}
+		}
+		return g.annotate(s, path), nil
+	case reflect.Struct:
+		return g.object(t, path)
+	}
+	return nil, errors.Errorf("%s has unsupported type %s", strings.Join(path, "."), t)
+}
+
+// object generates the schema of the struct t, at path in definitions.
+func (g *schemaGenerator) object(t reflect.Type, path []string) (map[string]interface{}, error) {
+	props := map[string]interface{}{}
+	if err := g.fields(t, path, props); err != nil {
+		return nil, err
+	}
+	s := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	return g.annotate(s, path), nil
+}
+
+// fields adds the schemas of the fields of the struct t to props. Fields of embedded structs are
+// promoted, as encoding/json does.
+func (g *schemaGenerator) fields(t reflect.Type, path []string, props map[string]interface{}) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// Builtin kinds are added by GenerateTaskSchema.
+		if !field.IsExported() || field.Type == reflect.TypeOf(&BuiltinTaskContainer{}) {
+			continue
+		}
+		name := jsonName(field)
+		if name == "-" || unpublishedFields[t.Name()+"."+name] {
+			continue
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			if err := g.fields(field.Type, path, props); err != nil {
+				return err
+			}
+			continue
+		}
+		s, err := g.schema(field.Type, append(append([]string(nil), path...), name))
+		if err != nil {
+			return err
+		}
+		props[name] = s
+	}
+	return nil
+}
+
+// annotate adds the annotations that the validation schema declares at path to s.
+func (g *schemaGenerator) annotate(s map[string]interface{}, path []string) map[string]interface{} {
+	v := g.lookup(path)
+	for _, k := range annotationKeywords {
+		if a, ok := v[k]; ok {
+			s[k] = g.copy(a)
+		}
+	}
+	// Keywords that apply to every kind in the validation schema are the generated schema's.
+	if len(path) == 0 {
+		delete(s, "oneOf")
+		delete(s, "allOf")
+	}
+	return s
+}
+
+// keyPattern returns the pattern that the validation schema requires the keys of the map at path
+// to match, if any.
+func (g *schemaGenerator) keyPattern(path []string) string {
+	pp, _ := g.lookup(path)["patternProperties"].(map[string]interface{})
+	if len(pp) != 1 {
+		return ""
+	}
+	for pattern := range pp {
+		if pattern != ".*" {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// lookup returns the schema that the validation schema declares at path, or nil if there's none.
+func (g *schemaGenerator) lookup(path []string) map[string]interface{} {
+	nodes := []map[string]interface{}{g.validation}
+	for _, name := range path {
+		var next []map[string]interface{}
+		for _, node := range nodes {
+			for _, n := range g.expand(node, 0) {
+				switch name {
+				case "[]":
+					next = appendSchema(next, n["items"])
+				case "*":
+					if pp, ok := n["patternProperties"].(map[string]interface{}); ok {
+						for _, p := range pp {
+							next = appendSchema(next, p)
+						}
+					}
+					next = appendSchema(next, n["additionalProperties"])
+				default:
+					if props, ok := n["properties"].(map[string]interface{}); ok {
+						next = appendSchema(next, props[name])
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return g.expand(nodes[0], 0)[0]
+}
+
+// expand returns node, with its $ref resolved, and the schemas that it combines.
+func (g *schemaGenerator) expand(node map[string]interface{}, depth int) []map[string]interface{} {
+	if ref, ok := node["$ref"].(string); ok && depth < 10 {
+		if r := g.resolve(ref); r != nil {
+			return g.expand(r, depth+1)
+		}
+	}
+	nodes := []map[string]interface{}{node}
+	for _, k := range []string{"allOf", "oneOf", "anyOf"} {
+		list, _ := node[k].([]interface{})
+		for _, v := range list {
+			if m, ok := v.(map[string]interface{}); ok && depth < 10 {
+				nodes = append(nodes, g.expand(m, depth+1)...)
+			}
+		}
+	}
+	return nodes
+}
+
+// resolve returns the schema that ref, a JSON pointer within the validation schema, points to.
+func (g *schemaGenerator) resolve(ref string) map[string]interface{} {
+	var v interface{} = g.validation
+	for _, name := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/"), "/") {
+		if name == "" {
+			continue
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[name]
+	}
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+// copy deep copies v, a part of the validation schema, with its $refs replaced by the schemas
+// they point to, since the generated schema has no $defs.
+func (g *schemaGenerator) copy(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			if r := g.resolve(ref); r != nil {
+				return g.copy(r)
+			}
+		}
+		c := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			c[k] = g.copy(e)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, e := range v {
+			c[i] = g.copy(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+func appendSchema(nodes []map[string]interface{}, v interface{}) []map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return append(nodes, m)
+	}
+	return nodes
+}
+
+// hasCustomJSON returns whether t, or a pointer to it, unmarshals or marshals itself.
+func hasCustomJSON(t reflect.Type) bool {
+	for _, typ := range []reflect.Type{t, reflect.PointerTo(t)} {
+		if typ.Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) ||
+			typ.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem()) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonName returns the name of field in JSON.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func marshalSchema(title string, root map[string]interface{}) ([]byte, error) {
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["title"] = title
+	buf, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling schema")
+	}
+	return append(buf, '\n'), nil
+}
@@ -0,0 +1,171 @@
+package definitions
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestValidateSchema(t *testing.T) {
+	require := require.New(t)
+
+	errs, err := ValidateSchema("my_task.task.yaml", []byte(`slug: my_task
+parameters:
+  - slug: name
+    type: shorttext
+  - slug: Count
+    type: integer
+node:
+  entrypoint: my_task.ts
+  nodeVersion: "12"
+`))
+	require.NoError(err)
+	require.Equal([]SchemaError{
+		{Field: "parameters.1.slug", Message: "Does not match pattern '^[a-z0-9_]+$'", Line: 5, Column: 5},
+		{Field: "node.nodeVersion", Message: `node.nodeVersion must be one of the following: "14", "16", "18", "20", "22"`, Line: 9, Column: 3},
+	}, errs)
+
+	// Missing fields are reported at the object that should contain them.
+	errs, err = ValidateSchema("my_view.view.json", []byte(`{
+  "slug": "my_view",
+  "name": "My view"
+}`))
+	require.NoError(err)
+	require.Equal([]SchemaError{{Message: "entrypoint is required", Line: 1, Column: 1}}, errs)
+
+	errs, err = ValidateSchema("my_task.task.json", []byte(`{"slug": "my_task", "python": {"entrypoint": "my_task.py"}}`))
+	require.NoError(err)
+	require.Empty(errs)
+
+	_, err = ValidateSchema("my_task.yaml", nil)
+	require.EqualError(err, "my_task.yaml is not a task or view definition file")
+}
+
+// TestSchemaCoversDefinitions checks that the schemas declare every field of the definition
+// structs, so that fields aren't added to the structs without being documented for editors.
+func TestSchemaCoversDefinitions(t *testing.T) {
+	for _, test := range []struct {
+		schema string
+		def    interface{}
+	}{
+		{schemaStr, Definition{}},
+		{viewSchemaStr, ViewDefinition{}},
+	} {
+		var schema interface{}
+		require.NoError(t, json.Unmarshal([]byte(test.schema), &schema))
+		properties := map[string][]map[string]interface{}{}
+		collectProperties(schema, properties)
+
+		defName := reflect.TypeOf(test.def).Name()
+		forEachField(reflect.TypeOf(test.def), func(name string, typ reflect.Type) {
+			if unpublishedFields[defName+"."+name] {
+				return
+			}
+			props, ok := properties[name]
+			require.True(t, ok, "%s.%s is not declared in the schema", defName, name)
+			switch typ {
+			case reflect.TypeOf(DefaultTrueDefinition{}):
+				requireDefault(t, name, props, "boolean", true)
+			case reflect.TypeOf(DefaultOneDefinition{}):
+				requireDefault(t, name, props, "integer", float64(1))
+			}
+		}, map[reflect.Type]bool{})
+	}
+}
+
+func TestGenerateSchema(t *testing.T) {
+	require := require.New(t)
+
+	buf, err := GenerateTaskSchema()
+	require.NoError(err)
+	var schema map[string]interface{}
+	require.NoError(json.Unmarshal(buf, &schema))
+	props := schema["properties"].(map[string]interface{})
+
+	// Fields, their types and defaults come from the structs, and annotations from the validation
+	// schema.
+	require.Equal("boolean", props["allowSelfApprovals"].(map[string]interface{})["type"])
+	require.Equal(true, props["allowSelfApprovals"].(map[string]interface{})["default"])
+	require.NotEmpty(props["allowSelfApprovals"].(map[string]interface{})["description"])
+	require.Equal("integer", props["timeout"].(map[string]interface{})["type"])
+	require.Equal([]interface{}{"entrypoint"}, props["node"].(map[string]interface{})["required"])
+	require.Contains(props, "extends")
+	require.Contains(props, "graphql")
+	require.Contains(schema["oneOf"], map[string]interface{}{"required": []interface{}{"graphql"}})
+
+	validate := func(schema []byte, def string) []string {
+		result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewStringLoader(def))
+		require.NoError(err)
+		var errs []string
+		for _, e := range result.Errors() {
+			errs = append(errs, e.String())
+		}
+		return errs
+	}
+	require.Empty(validate(buf, `{"slug": "my_task", "allowSelfApprovals": false, "node": {"entrypoint": "my_task.ts", "nodeVersion": "18"}}`))
+	require.NotEmpty(validate(buf, `{"slug": "my_task", "node": {"entrypoint": "my_task.ts"}, "python": {"entrypoint": "my_task.py"}}`))
+	require.NotEmpty(validate(buf, `{"slug": "my_task", "node": {"entrypoint": "my_task.ts", "nodeVersion": "12"}}`))
+	require.NotEmpty(validate(buf, `{"slug": "my_task", "unknown": true, "node": {"entrypoint": "my_task.ts"}}`))
+
+	buf, err = GenerateViewSchema()
+	require.NoError(err)
+	require.Empty(validate(buf, `{"slug": "my_view", "entrypoint": "my_view.tsx", "links": {"run": {"task": "my_task"}}}`))
+	require.NotEmpty(validate(buf, `{"slug": "my_view", "entrypoint": "my_view.tsx", "base": "slim"}`))
+}
+
+// collectProperties collects the schemas of the properties declared anywhere in schema, by name.
+func collectProperties(schema interface{}, properties map[string][]map[string]interface{}) {
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		if props, ok := s["properties"].(map[string]interface{}); ok {
+			for name, prop := range props {
+				if p, ok := prop.(map[string]interface{}); ok {
+					properties[name] = append(properties[name], p)
+				}
+			}
+		}
+		for _, v := range s {
+			collectProperties(v, properties)
+		}
+	case []interface{}:
+		for _, v := range s {
+			collectProperties(v, properties)
+		}
+	}
+}
+
+// forEachField calls f with the JSON name and type of each field of t and of the structs it
+// contains.
+func forEachField(t reflect.Type, f func(name string, typ reflect.Type), seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] || t.PkgPath() != reflect.TypeOf(Definition{}).PkgPath() {
+		return
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name != "" {
+			f(name, field.Type)
+		}
+		forEachField(field.Type, f, seen)
+	}
+}
+
+func requireDefault(t *testing.T, name string, props []map[string]interface{}, typ string, value interface{}) {
+	for _, p := range props {
+		if p["type"] == typ && p["default"] == value {
+			return
+		}
+	}
+	require.Failf(t, "wrong schema", "%s must be a %s that defaults to %v", name, typ, value)
+}
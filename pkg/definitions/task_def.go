@@ -4,10 +4,12 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/json"
+	"path/filepath"
 	"strings"
 	"text/template"
 
 	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/build/dockerfile"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/utils/pointers"
 	"github.com/goccy/go-yaml"
@@ -26,6 +28,10 @@ import (
 // TestDefinitionMarshal to confirm this behavior. This behavior is relied upon when updating task
 // definitions via `definitions/updaters`. You should also add test cases there for the new fields.
 type Definition struct {
+	// ID optionally pins this definition to a specific remote task. When set, the task is matched
+	// by ID rather than by slug, which allows a task's slug to be renamed without creating a
+	// duplicate task.
+	ID          string                 `json:"id,omitempty"`
 	Slug        string                 `json:"slug"`
 	Name        string                 `json:"name,omitempty"`
 	Description string                 `json:"description,omitempty"`
@@ -37,26 +43,48 @@ type Definition struct {
 	Node   *NodeDefinition   `json:"node,omitempty"`
 	Python *PythonDefinition `json:"python,omitempty"`
 	Shell  *ShellDefinition  `json:"shell,omitempty"`
+	Deno   *DenoDefinition   `json:"deno,omitempty"`
+	Go     *GoDefinition     `json:"go,omitempty"`
+	Ruby   *RubyDefinition   `json:"ruby,omitempty"`
 
 	SQL     *SQLDefinition        `json:"sql,omitempty"`
 	REST    *RESTDefinition       `json:"rest,omitempty"`
 	Builtin *BuiltinTaskContainer `json:",inline,omitempty"`
 
 	Configs            []string              `json:"configs,omitempty"`
+	EnvFrom            []string              `json:"envFrom,omitempty"`
 	Timeout            int                   `json:"timeout,omitempty"`
+	Priority           int                   `json:"priority,omitempty"`
+	RunAs              string                `json:"runAs,omitempty"`
 	Constraints        map[string]string     `json:"constraints,omitempty"`
+	AgentPool          string                `json:"agentPool,omitempty"`
 	RequireRequests    bool                  `json:"requireRequests,omitempty"`
 	AllowSelfApprovals DefaultTrueDefinition `json:"allowSelfApprovals,omitempty"`
 	RestrictCallers    []string              `json:"restrictCallers,omitempty"`
 	ConcurrencyKey     string                `json:"concurrencyKey,omitempty"`
 	ConcurrencyLimit   DefaultOneDefinition  `json:"concurrencyLimit,omitempty"`
+	// BuildPlatforms are the platforms that the task's image is built for, e.g. linux/arm64 for
+	// agents on ARM hosts. Defaults to linux/amd64.
+	BuildPlatforms []string `json:"buildPlatforms,omitempty"`
+	// BuildArgs are the build args of the task's image, e.g. a private package index URL. Values
+	// can be overridden when deploying with --build-arg.
+	BuildArgs map[string]string `json:"buildArgs,omitempty"`
+	// BuildSecrets are the IDs of secrets that are available to the commands that install the
+	// task's dependencies, without being stored in its image. Their values are read from env vars
+	// when deploying, see --build-secret.
+	BuildSecrets []string `json:"buildSecrets,omitempty"`
 
 	Schedules             map[string]ScheduleDefinition `json:"schedules,omitempty"`
 	Permissions           *PermissionsDefinition        `json:"permissions,omitempty"`
 	DefaultRunPermissions DefaultTaskViewersDefinition  `json:"defaultRunPermissions,omitempty"`
 
-	buildConfig  buildtypes.BuildConfig
-	defnFilePath string
+	// Environments override fields of the task in individual environments, keyed by environment
+	// slug. See ForEnv.
+	Environments map[string]EnvironmentDefinition `json:"environments,omitempty"`
+
+	buildConfig   buildtypes.BuildConfig
+	defnFilePath  string
+	extendedFiles []string
 }
 
 type taskKind interface {
@@ -84,6 +112,78 @@ type ParameterDefinition struct {
 	Default     interface{}           `json:"default,omitempty"`
 	Regex       string                `json:"regex,omitempty"`
 	Options     []OptionDefinition    `json:"options,omitempty"`
+	// OptionsSource is set instead of Options when the options are populated by running a task or
+	// builtin, e.g. `options: {source: {task: list_customers, valuePath: id, labelPath: name}}`.
+	OptionsSource *OptionsSourceDefinition `json:"-"`
+}
+
+type OptionsSourceDefinition struct {
+	Task        string                 `json:"task"`
+	ParamValues map[string]interface{} `json:"paramValues,omitempty"`
+	Resources   map[string]string      `json:"resources,omitempty"`
+	ValuePath   string                 `json:"valuePath"`
+	LabelPath   string                 `json:"labelPath,omitempty"`
+}
+
+var _ json.Unmarshaler = &ParameterDefinition{}
+var _ json.Marshaler = ParameterDefinition{}
+var _ yaml.InterfaceMarshaler = ParameterDefinition{}
+
+func (p *ParameterDefinition) UnmarshalJSON(b []byte) error {
+	// Note we need a new type, otherwise we recursively call this
+	// method and end up stack overflowing.
+	type parameter ParameterDefinition
+	var raw struct {
+		parameter
+		Options json.RawMessage `json:"options,omitempty"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*p = ParameterDefinition(raw.parameter)
+
+	// Options are either a list of static options or an object with a source.
+	options := bytes.TrimSpace(raw.Options)
+	switch {
+	case len(options) == 0 || bytes.Equal(options, []byte("null")):
+		return nil
+	case options[0] == '{':
+		var o struct {
+			Source *OptionsSourceDefinition `json:"source"`
+		}
+		if err := json.Unmarshal(options, &o); err != nil {
+			return err
+		}
+		p.OptionsSource = o.Source
+		return nil
+	default:
+		return json.Unmarshal(options, &p.Options)
+	}
+}
+
+func (p ParameterDefinition) MarshalJSON() ([]byte, error) {
+	v, err := p.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func (p ParameterDefinition) MarshalYAML() (interface{}, error) {
+	type parameter ParameterDefinition
+	if p.OptionsSource == nil {
+		return parameter(p), nil
+	}
+	type optionsSource struct {
+		Source *OptionsSourceDefinition `json:"source"`
+	}
+	return struct {
+		parameter `yaml:",inline"`
+		Options   optionsSource `json:"options"`
+	}{
+		parameter: parameter(p),
+		Options:   optionsSource{Source: p.OptionsSource},
+	}, nil
 }
 
 type OptionDefinition struct {
@@ -151,6 +251,8 @@ type ScheduleDefinition struct {
 	Description string                 `json:"description,omitempty"`
 	CronExpr    string                 `json:"cron"`
 	ParamValues map[string]interface{} `json:"paramValues,omitempty"`
+	// Paused schedules are deployed disabled.
+	Paused bool `json:"paused,omitempty"`
 }
 
 type PermissionsDefinition struct {
@@ -171,6 +273,28 @@ type PermissionRecipients struct {
 	Users []string `json:"users,omitempty"`
 }
 
+// PermissionRole is the recipients of a single role of a task's explicit permissions.
+type PermissionRole struct {
+	Role string
+	PermissionRecipients
+}
+
+// Roles returns the roles that have recipients, from least to most access.
+func (p PermissionsDefinition) Roles() []PermissionRole {
+	var roles []PermissionRole
+	for _, r := range []PermissionRole{
+		{Role: "viewers", PermissionRecipients: p.Viewers},
+		{Role: "requesters", PermissionRecipients: p.Requesters},
+		{Role: "executers", PermissionRecipients: p.Executers},
+		{Role: "admins", PermissionRecipients: p.Admins},
+	} {
+		if len(r.Groups) > 0 || len(r.Users) > 0 {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
 func (p *PermissionsDefinition) UnmarshalJSON(b []byte) error {
 	// If permissions is a string, it should mean team_access.
 	var s string
@@ -238,6 +362,21 @@ func NewDefinition(name string, slug string, kind buildtypes.TaskKind, entrypoin
 		def.Shell = &ShellDefinition{
 			Entrypoint: entrypoint,
 		}
+	case buildtypes.TaskKindDeno:
+		def.Deno = &DenoDefinition{
+			Entrypoint:  entrypoint,
+			DenoVersion: string(buildtypes.DefaultDenoVersion),
+		}
+	case buildtypes.TaskKindGo:
+		def.Go = &GoDefinition{
+			Entrypoint: entrypoint,
+			GoVersion:  string(buildtypes.DefaultGoVersion),
+		}
+	case buildtypes.TaskKindRuby:
+		def.Ruby = &RubyDefinition{
+			Entrypoint:  entrypoint,
+			RubyVersion: string(buildtypes.DefaultRubyVersion),
+		}
 	case buildtypes.TaskKindSQL:
 		def.SQL = &SQLDefinition{
 			Entrypoint: entrypoint,
@@ -380,6 +519,7 @@ func (d Definition) GenerateCommentedFile(format DefFormat) ([]byte, error) {
 		len(d.Parameters) > 0 ||
 		len(d.Resources) > 0 ||
 		len(d.Constraints) > 0 ||
+		d.AgentPool != "" ||
 		d.RequireRequests ||
 		!d.AllowSelfApprovals.IsZero() ||
 		d.Timeout != 0 ||
@@ -539,6 +679,16 @@ func (d *Definition) Normalize(availableResources []api.ResourceMetadata) error
 	return nil
 }
 
+// Normalized returns a normalized copy of the definition, see Normalize. The definition itself
+// is left unchanged.
+func (d Definition) Normalized(availableResources []api.ResourceMetadata) (Definition, error) {
+	d.copyKind()
+	if err := d.Normalize(availableResources); err != nil {
+		return Definition{}, err
+	}
+	return d, nil
+}
+
 // SetAbsoluteEntrypoint sets the absolute entrypoint for this definition. Does not change the
 // result of calling Entrypoint(). Returns ErrNoEntrypoint if the task kind definition requires
 // no entrypoint.
@@ -565,6 +715,9 @@ func (d *Definition) GetAbsoluteEntrypoint() (string, error) {
 
 func (d Definition) Kind() (buildtypes.TaskKind, error) {
 	if d.Image != nil {
+		if d.Image.Dockerfile != "" {
+			return buildtypes.TaskKindDockerfile, nil
+		}
 		return buildtypes.TaskKindImage, nil
 	} else if d.Node != nil {
 		return buildtypes.TaskKindNode, nil
@@ -572,6 +725,12 @@ func (d Definition) Kind() (buildtypes.TaskKind, error) {
 		return buildtypes.TaskKindPython, nil
 	} else if d.Shell != nil {
 		return buildtypes.TaskKindShell, nil
+	} else if d.Deno != nil {
+		return buildtypes.TaskKindDeno, nil
+	} else if d.Go != nil {
+		return buildtypes.TaskKindGo, nil
+	} else if d.Ruby != nil {
+		return buildtypes.TaskKindRuby, nil
 	} else if d.SQL != nil {
 		return buildtypes.TaskKindSQL, nil
 	} else if d.REST != nil {
@@ -592,6 +751,12 @@ func (d Definition) taskKind() (taskKind, error) {
 		return d.Python, nil
 	} else if d.Shell != nil {
 		return d.Shell, nil
+	} else if d.Deno != nil {
+		return d.Deno, nil
+	} else if d.Go != nil {
+		return d.Go, nil
+	} else if d.Ruby != nil {
+		return d.Ruby, nil
 	} else if d.SQL != nil {
 		return d.SQL, nil
 	} else if d.REST != nil {
@@ -611,6 +776,9 @@ type GetTaskOpts struct {
 	Bundle bool
 	// Set to `true` to silently ignore invalid definition fields.
 	IgnoreInvalid bool
+	// TemplateVars are used to resolve templated fields, such as the image of an image task. If
+	// nil, templated fields are left as-is.
+	TemplateVars *TemplateVars
 }
 
 // GetTask converts a task definition into a Task struct.
@@ -619,10 +787,13 @@ type GetTaskOpts struct {
 // will not be set on the task.
 func (d Definition) GetTask(opts GetTaskOpts) (api.Task, error) {
 	task := api.Task{
+		ID:          d.ID,
 		Slug:        d.Slug,
 		Name:        d.Name,
 		Description: d.Description,
 		Timeout:     d.Timeout,
+		Priority:    d.Priority,
+		RunAs:       d.RunAs,
 		Runtime:     d.Runtime,
 		ExecuteRules: api.ExecuteRules{
 			RequireRequests:     d.RequireRequests,
@@ -635,6 +806,7 @@ func (d Definition) GetTask(opts GetTaskOpts) (api.Task, error) {
 		Configs:   []api.ConfigAttachment{},
 		Constraints: api.RunConstraints{
 			Labels: []api.AgentLabel{},
+			Pool:   d.AgentPool,
 		},
 		DefaultRunPermissions: api.DefaultRunPermissions(d.DefaultRunPermissions.Value()),
 	}
@@ -905,7 +1077,8 @@ func (d *Definition) SetWorkdir(taskroot, workdir string) error {
 		return nil
 	}
 
-	d.SetBuildConfig("workdir", strings.TrimPrefix(workdir, taskroot))
+	// The workdir is a path in the container, so it's slash-separated.
+	d.SetBuildConfig("workdir", filepath.ToSlash(strings.TrimPrefix(workdir, taskroot)))
 
 	return nil
 }
@@ -922,6 +1095,7 @@ func (d *Definition) GetSchedules() map[string]api.Schedule {
 			Description: def.Description,
 			CronExpr:    def.CronExpr,
 			ParamValues: def.ParamValues,
+			Paused:      def.Paused,
 		}
 	}
 	return schedules
@@ -944,6 +1118,24 @@ func (d *Definition) GetBuildConfig() (buildtypes.BuildConfig, error) {
 	// Pass runtime through to builder
 	config["runtime"] = d.Runtime
 
+	if len(d.BuildPlatforms) > 0 {
+		config[buildtypes.KindOptionBuildPlatforms] = d.BuildPlatforms
+	}
+	if len(d.BuildArgs) > 0 {
+		// Build args of the Dockerfile take precedence.
+		buildArgs := map[string]string{}
+		for k, v := range d.BuildArgs {
+			buildArgs[k] = v
+		}
+		for k, v := range dockerfile.BuildArgs(buildtypes.KindOptions(config)) {
+			buildArgs[k] = v
+		}
+		config[buildtypes.KindOptionBuildArgs] = buildArgs
+	}
+	if len(d.BuildSecrets) > 0 {
+		config[buildtypes.KindOptionBuildSecrets] = d.BuildSecrets
+	}
+
 	for key, val := range d.buildConfig {
 		if val == nil { // Nil masks out the value.
 			delete(config, key)
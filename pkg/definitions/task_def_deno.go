@@ -0,0 +1,117 @@
+package definitions
+
+import (
+	"path"
+
+	"github.com/airplanedev/cli/pkg/api"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/pkg/errors"
+)
+
+var _ taskKind = &DenoDefinition{}
+
+type DenoDefinition struct {
+	// Entrypoint is the relative path from the task definition file to the script. It does not
+	// apply for inline configured tasks.
+	Entrypoint  string      `json:"entrypoint"`
+	DenoVersion string      `json:"denoVersion,omitempty"`
+	EnvVars     api.EnvVars `json:"envVars,omitempty"`
+
+	absoluteEntrypoint string `json:"-"`
+}
+
+func (d *DenoDefinition) copyToTask(task *api.Task, bc buildtypes.BuildConfig, opts GetTaskOpts) error {
+	task.Env = d.EnvVars
+	if opts.Bundle {
+		entrypointFunc, _ := bc["entrypointFunc"].(string)
+		entrypoint, _ := bc["entrypoint"].(string)
+		task.Command = []string{"deno"}
+		task.Arguments = []string{
+			"run",
+			"--allow-all",
+			"/airplane/.airplane/universal-shim.ts",
+			path.Join("/airplane/", entrypoint),
+			entrypointFunc,
+			"{{JSON.stringify(params)}}",
+		}
+	}
+	return nil
+}
+
+func (d *DenoDefinition) update(t api.UpdateTaskRequest, availableResources []api.ResourceMetadata) error {
+	if v, ok := t.KindOptions["entrypoint"]; ok {
+		if sv, ok := v.(string); ok {
+			d.Entrypoint = sv
+		} else {
+			return errors.Errorf("expected string entrypoint, got %T instead", v)
+		}
+	}
+	if v, ok := t.KindOptions["denoVersion"]; ok {
+		if sv, ok := v.(string); ok {
+			d.DenoVersion = sv
+		} else {
+			return errors.Errorf("expected string denoVersion, got %T instead", v)
+		}
+	}
+	d.EnvVars = t.Env
+	return nil
+}
+
+func (d *DenoDefinition) setEntrypoint(entrypoint string) error {
+	d.Entrypoint = entrypoint
+	return nil
+}
+
+func (d *DenoDefinition) setAbsoluteEntrypoint(entrypoint string) error {
+	d.absoluteEntrypoint = entrypoint
+	return nil
+}
+
+func (d *DenoDefinition) getAbsoluteEntrypoint() (string, error) {
+	if d.absoluteEntrypoint == "" {
+		return "", ErrNoAbsoluteEntrypoint
+	}
+	return d.absoluteEntrypoint, nil
+}
+
+func (d *DenoDefinition) getKindOptions() (buildtypes.KindOptions, error) {
+	ko := buildtypes.KindOptions{}
+	if d.Entrypoint != "" {
+		ko["entrypoint"] = d.Entrypoint
+	}
+	if d.DenoVersion != "" {
+		ko["denoVersion"] = d.DenoVersion
+	}
+	return ko, nil
+}
+
+func (d *DenoDefinition) getEntrypoint() (string, error) {
+	return d.Entrypoint, nil
+}
+
+func (d *DenoDefinition) getEnv() (api.EnvVars, error) {
+	return d.EnvVars, nil
+}
+
+func (d *DenoDefinition) setEnv(e api.EnvVars) error {
+	d.EnvVars = e
+	return nil
+}
+
+func (d *DenoDefinition) getConfigAttachments() []api.ConfigAttachment {
+	return []api.ConfigAttachment{}
+}
+
+func (d *DenoDefinition) getResourceAttachments() map[string]string {
+	return nil
+}
+
+func (d *DenoDefinition) getBuildType() (buildtypes.BuildType, buildtypes.BuildTypeVersion, buildtypes.BuildBase) {
+	return buildtypes.DenoBuildType, buildtypes.BuildTypeVersion(d.DenoVersion), buildtypes.BuildBaseNone
+}
+
+func (d *DenoDefinition) SetBuildVersionBase(v buildtypes.BuildTypeVersion, b buildtypes.BuildBase) {
+	if d.DenoVersion == "" {
+		d.DenoVersion = string(v)
+	}
+}
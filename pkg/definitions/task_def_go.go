@@ -0,0 +1,136 @@
+package definitions
+
+import (
+	"path"
+
+	"github.com/airplanedev/cli/pkg/api"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/pkg/errors"
+)
+
+var _ taskKind = &GoDefinition{}
+
+type GoDefinition struct {
+	// Entrypoint is the relative path from the task definition file to the .go file with the
+	// task's main function. The package of the entrypoint is built into the task's binary.
+	Entrypoint string      `json:"entrypoint"`
+	GoVersion  string      `json:"goVersion,omitempty"`
+	BuildTags  []string    `json:"buildTags,omitempty"`
+	EnvVars    api.EnvVars `json:"envVars,omitempty"`
+
+	absoluteEntrypoint string `json:"-"`
+}
+
+func (d *GoDefinition) copyToTask(task *api.Task, bc buildtypes.BuildConfig, opts GetTaskOpts) error {
+	task.Env = d.EnvVars
+	if opts.Bundle {
+		// Bundled tasks are built into a binary per package, see golang.BinaryPath.
+		entrypoint, _ := bc["entrypoint"].(string)
+		task.Command = []string{path.Join("/airplane/.airplane/bin", path.Dir(entrypoint), "task")}
+		task.Arguments = []string{"{{JSON.stringify(params)}}"}
+		// Inline tasks share their package's binary, which runs the function named by the first
+		// argument.
+		if entrypointFunc, _ := bc["entrypointFunc"].(string); entrypointFunc != "" {
+			task.Arguments = []string{entrypointFunc, "{{JSON.stringify(params)}}"}
+		}
+	}
+	return nil
+}
+
+func (d *GoDefinition) update(t api.UpdateTaskRequest, availableResources []api.ResourceMetadata) error {
+	if v, ok := t.KindOptions["entrypoint"]; ok {
+		if sv, ok := v.(string); ok {
+			d.Entrypoint = sv
+		} else {
+			return errors.Errorf("expected string entrypoint, got %T instead", v)
+		}
+	}
+	if v, ok := t.KindOptions["goVersion"]; ok {
+		if sv, ok := v.(string); ok {
+			d.GoVersion = sv
+		} else {
+			return errors.Errorf("expected string goVersion, got %T instead", v)
+		}
+	}
+	if v, ok := t.KindOptions["buildTags"]; ok {
+		switch tags := v.(type) {
+		case []string:
+			d.BuildTags = tags
+		case []interface{}:
+			d.BuildTags = nil
+			for _, tag := range tags {
+				if st, ok := tag.(string); ok {
+					d.BuildTags = append(d.BuildTags, st)
+				} else {
+					return errors.Errorf("expected string build tag, got %T instead", tag)
+				}
+			}
+		default:
+			return errors.Errorf("expected array buildTags, got %T instead", v)
+		}
+	}
+	d.EnvVars = t.Env
+	return nil
+}
+
+func (d *GoDefinition) setEntrypoint(entrypoint string) error {
+	d.Entrypoint = entrypoint
+	return nil
+}
+
+func (d *GoDefinition) setAbsoluteEntrypoint(entrypoint string) error {
+	d.absoluteEntrypoint = entrypoint
+	return nil
+}
+
+func (d *GoDefinition) getAbsoluteEntrypoint() (string, error) {
+	if d.absoluteEntrypoint == "" {
+		return "", ErrNoAbsoluteEntrypoint
+	}
+	return d.absoluteEntrypoint, nil
+}
+
+func (d *GoDefinition) getKindOptions() (buildtypes.KindOptions, error) {
+	ko := buildtypes.KindOptions{}
+	if d.Entrypoint != "" {
+		ko["entrypoint"] = d.Entrypoint
+	}
+	if d.GoVersion != "" {
+		ko["goVersion"] = d.GoVersion
+	}
+	if len(d.BuildTags) > 0 {
+		ko["buildTags"] = d.BuildTags
+	}
+	return ko, nil
+}
+
+func (d *GoDefinition) getEntrypoint() (string, error) {
+	return d.Entrypoint, nil
+}
+
+func (d *GoDefinition) getEnv() (api.EnvVars, error) {
+	return d.EnvVars, nil
+}
+
+func (d *GoDefinition) setEnv(e api.EnvVars) error {
+	d.EnvVars = e
+	return nil
+}
+
+func (d *GoDefinition) getConfigAttachments() []api.ConfigAttachment {
+	return []api.ConfigAttachment{}
+}
+
+func (d *GoDefinition) getResourceAttachments() map[string]string {
+	return nil
+}
+
+func (d *GoDefinition) getBuildType() (buildtypes.BuildType, buildtypes.BuildTypeVersion, buildtypes.BuildBase) {
+	return buildtypes.GoBuildType, buildtypes.BuildTypeVersion(d.GoVersion), buildtypes.BuildBaseNone
+}
+
+func (d *GoDefinition) SetBuildVersionBase(v buildtypes.BuildTypeVersion, b buildtypes.BuildBase) {
+	if d.GoVersion == "" {
+		d.GoVersion = string(v)
+	}
+}
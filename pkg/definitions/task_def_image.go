@@ -1,24 +1,52 @@
 package definitions
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
 	"github.com/airplanedev/cli/pkg/api"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/utils/templates"
 	"github.com/alessio/shellescape"
 	"github.com/flynn/go-shlex"
+	"github.com/pkg/errors"
 )
 
 var _ taskKind = &ImageDefinition{}
 
 type ImageDefinition struct {
-	Image      string      `json:"image"`
+	Image      string      `json:"image,omitempty"`
 	Entrypoint string      `json:"entrypoint,omitempty"`
 	Command    string      `json:"command"`
 	EnvVars    api.EnvVars `json:"envVars,omitempty"`
+
+	// Dockerfile is the path from the task definition file to a Dockerfile that the task's image is
+	// built from, instead of using Image. The task's command is run through a shim that's added to
+	// the image as a final stage, see the dockerfile builder.
+	Dockerfile string `json:"dockerfile,omitempty"`
+	// BuildContext is the path from the task definition file to the directory that the Dockerfile
+	// is built in. Defaults to the directory of the Dockerfile.
+	BuildContext string `json:"buildContext,omitempty"`
+	// BuildArgs are passed to the Dockerfile as build args.
+	BuildArgs map[string]string `json:"buildArgs,omitempty"`
+
+	absoluteDockerfile string `json:"-"`
 }
 
 func (d *ImageDefinition) copyToTask(task *api.Task, bc buildtypes.BuildConfig, opts GetTaskOpts) error {
+	if d.Dockerfile != "" {
+		return d.copyDockerfileToTask(task)
+	}
 	if d.Image != "" {
-		task.Image = &d.Image
+		image := d.Image
+		if opts.TemplateVars != nil {
+			var err error
+			if image, err = ResolveTemplate(image, *opts.TemplateVars); err != nil {
+				return errors.Wrap(err, "resolving image")
+			}
+		}
+		task.Image = &image
 	}
 	if args, err := shlex.Split(d.Command); err != nil {
 		return err
@@ -34,8 +62,35 @@ func (d *ImageDefinition) copyToTask(task *api.Task, bc buildtypes.BuildConfig,
 	return nil
 }
 
+// copyDockerfileToTask configures a task that's built from a Dockerfile to run its command through
+// the shim, which is the entrypoint of the built image. The shim is passed the task's parameters
+// as slug={{params.slug}} arguments, followed by "--" and the command.
+func (d *ImageDefinition) copyDockerfileToTask(task *api.Task) error {
+	args := []string{}
+	for _, param := range task.Parameters {
+		args = append(args, fmt.Sprintf("%s={{params.%s}}", param.Slug, param.Slug))
+	}
+	args = append(args, "--")
+	for _, s := range []string{d.Entrypoint, d.Command} {
+		words, err := shlex.Split(s)
+		if err != nil {
+			return err
+		}
+		args = append(args, words...)
+	}
+	task.Command = []string{}
+	task.Arguments = args
+	task.InterpolationMode = "jst"
+	task.Env = d.EnvVars
+	return nil
+}
+
 func (d *ImageDefinition) update(t api.UpdateTaskRequest, availableResources []api.ResourceMetadata) error {
-	if t.Image != nil {
+	if t.Kind == buildtypes.TaskKindDockerfile {
+		return d.updateDockerfile(t)
+	}
+	// Keep templated images, unless the image was changed to one the template can't produce.
+	if t.Image != nil && !(IsTemplated(d.Image) && templates.Matches(d.Image, *t.Image)) {
 		d.Image = *t.Image
 	}
 	d.Command = shellescape.QuoteCommand(t.Arguments)
@@ -44,24 +99,92 @@ func (d *ImageDefinition) update(t api.UpdateTaskRequest, availableResources []a
 	return nil
 }
 
+// updateDockerfile updates a task that's built from a Dockerfile. Its command is kept as-is, since
+// the task's arguments are the shim's.
+func (d *ImageDefinition) updateDockerfile(t api.UpdateTaskRequest) error {
+	if v, ok := t.KindOptions["dockerfile"]; ok {
+		if sv, ok := v.(string); ok {
+			d.Dockerfile = sv
+		} else {
+			return errors.Errorf("expected string dockerfile, got %T instead", v)
+		}
+	}
+	if v, ok := t.KindOptions["buildContext"]; ok {
+		if sv, ok := v.(string); ok {
+			d.BuildContext = sv
+		} else {
+			return errors.Errorf("expected string buildContext, got %T instead", v)
+		}
+	}
+	if v, ok := t.KindOptions["buildArgs"]; ok {
+		switch args := v.(type) {
+		case map[string]string:
+			d.BuildArgs = args
+		case map[string]interface{}:
+			d.BuildArgs = make(map[string]string, len(args))
+			for k, v := range args {
+				sv, ok := v.(string)
+				if !ok {
+					return errors.Errorf("expected string build arg %s, got %T instead", k, v)
+				}
+				d.BuildArgs[k] = sv
+			}
+		default:
+			return errors.Errorf("expected map of buildArgs, got %T instead", v)
+		}
+	}
+	d.EnvVars = t.Env
+	return nil
+}
+
+// setEntrypoint sets the Dockerfile of tasks that are built from one, which is their entrypoint.
 func (d *ImageDefinition) setEntrypoint(entrypoint string) error {
-	return ErrNoEntrypoint
+	if d.Dockerfile == "" {
+		return ErrNoEntrypoint
+	}
+	d.Dockerfile = entrypoint
+	return nil
 }
 
 func (d *ImageDefinition) setAbsoluteEntrypoint(entrypoint string) error {
-	return ErrNoEntrypoint
+	if d.Dockerfile == "" {
+		return ErrNoEntrypoint
+	}
+	d.absoluteDockerfile = entrypoint
+	return nil
 }
 
 func (d *ImageDefinition) getAbsoluteEntrypoint() (string, error) {
-	return "", ErrNoEntrypoint
+	if d.Dockerfile == "" {
+		return "", ErrNoEntrypoint
+	}
+	if d.absoluteDockerfile == "" {
+		return "", ErrNoAbsoluteEntrypoint
+	}
+	return d.absoluteDockerfile, nil
 }
 
 func (d *ImageDefinition) getKindOptions() (buildtypes.KindOptions, error) {
-	return nil, nil
+	if d.Dockerfile == "" {
+		return nil, nil
+	}
+	ko := buildtypes.KindOptions{
+		"dockerfile": d.Dockerfile,
+	}
+	if d.BuildContext != "" {
+		ko["buildContext"] = d.BuildContext
+	}
+	if len(d.BuildArgs) > 0 {
+		ko["buildArgs"] = d.BuildArgs
+	}
+	return ko, nil
 }
 
 func (d *ImageDefinition) getEntrypoint() (string, error) {
-	return "", ErrNoEntrypoint
+	if d.Dockerfile == "" {
+		return "", ErrNoEntrypoint
+	}
+	return d.Dockerfile, nil
 }
 
 func (d *ImageDefinition) getEnv() (api.EnvVars, error) {
@@ -81,8 +204,44 @@ func (d *ImageDefinition) getResourceAttachments() map[string]string {
 }
 
 func (d *ImageDefinition) getBuildType() (buildtypes.BuildType, buildtypes.BuildTypeVersion, buildtypes.BuildBase) {
+	if d.Dockerfile != "" {
+		return buildtypes.DockerfileBuildType, buildtypes.BuildTypeVersionUnspecified, buildtypes.BuildBaseNone
+	}
 	return buildtypes.NoneBuildType, buildtypes.BuildTypeVersionUnspecified, buildtypes.BuildBaseNone
 }
 
 func (d *ImageDefinition) SetBuildVersionBase(v buildtypes.BuildTypeVersion, b buildtypes.BuildBase) {
 }
+
+// DockerfileBuildContext returns the absolute path of the directory that a task built from a
+// Dockerfile is built in, and how to build its Dockerfile there. The Dockerfile must be inside
+// the build context.
+func (d *Definition) DockerfileBuildContext() (string, *buildtypes.DockerfileBuildContext, error) {
+	if d.Image == nil || d.Image.Dockerfile == "" {
+		return "", nil, errors.New("task is not built from a Dockerfile")
+	}
+	dockerfile, err := d.Image.getAbsoluteEntrypoint()
+	if err != nil {
+		return "", nil, err
+	}
+
+	root := filepath.Dir(dockerfile)
+	if d.Image.BuildContext != "" {
+		root = d.Image.BuildContext
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(filepath.Dir(d.GetDefnFilePath()), root)
+		}
+		if root, err = filepath.Abs(root); err != nil {
+			return "", nil, err
+		}
+	}
+
+	rel, err := filepath.Rel(root, dockerfile)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", nil, errors.Errorf("dockerfile %s must be inside its build context %s", d.Image.Dockerfile, d.Image.BuildContext)
+	}
+	return root, &buildtypes.DockerfileBuildContext{
+		Path:      filepath.ToSlash(rel),
+		BuildArgs: d.Image.BuildArgs,
+	}, nil
+}
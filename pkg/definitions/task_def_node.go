@@ -14,10 +14,12 @@ var _ taskKind = &NodeDefinition{}
 type NodeDefinition struct {
 	// Entrypoint is the relative path from the task definition file to the script. It does not
 	// apply for inline configured tasks.
-	Entrypoint  string               `json:"entrypoint"`
-	NodeVersion string               `json:"nodeVersion"`
-	EnvVars     api.EnvVars          `json:"envVars,omitempty"`
-	Base        buildtypes.BuildBase `json:"base,omitempty"`
+	Entrypoint  string      `json:"entrypoint"`
+	NodeVersion string      `json:"nodeVersion"`
+	EnvVars     api.EnvVars `json:"envVars,omitempty"`
+	// EnvFile are dotenv files to add to EnvVars. See Definition.GetEnvFiles.
+	EnvFile []string             `json:"envFile,omitempty"`
+	Base    buildtypes.BuildBase `json:"base,omitempty"`
 
 	absoluteEntrypoint string `json:"-"`
 }
@@ -13,10 +13,15 @@ var _ taskKind = &PythonDefinition{}
 type PythonDefinition struct {
 	// Entrypoint is the relative path from the task definition file to the script. It does not
 	// apply for inline configured tasks.
-	Entrypoint string               `json:"entrypoint"`
-	EnvVars    api.EnvVars          `json:"envVars,omitempty"`
-	Base       buildtypes.BuildBase `json:"base,omitempty"`
-	Version    string               `json:"-"`
+	Entrypoint string      `json:"entrypoint"`
+	EnvVars    api.EnvVars `json:"envVars,omitempty"`
+	// EnvFile are dotenv files to add to EnvVars. See Definition.GetEnvFiles.
+	EnvFile []string             `json:"envFile,omitempty"`
+	Base    buildtypes.BuildBase `json:"base,omitempty"`
+	// PythonPackageManager is the tool that installs the task's dependencies. If not set, it's
+	// detected from the files in the task root.
+	PythonPackageManager buildtypes.PythonPackageManager `json:"pythonPackageManager,omitempty"`
+	Version              string                          `json:"-"`
 
 	absoluteEntrypoint string `json:"-"`
 }
@@ -60,6 +65,15 @@ func (d *PythonDefinition) update(t api.UpdateTaskRequest, availableResources []
 			return errors.Errorf("expected string base, got %T instead", v)
 		}
 	}
+	if v, ok := t.KindOptions["pythonPackageManager"]; ok {
+		if sv, ok := v.(buildtypes.PythonPackageManager); ok {
+			d.PythonPackageManager = sv
+		} else if sv, ok := v.(string); ok {
+			d.PythonPackageManager = buildtypes.PythonPackageManager(sv)
+		} else {
+			return errors.Errorf("expected string pythonPackageManager, got %T instead", v)
+		}
+	}
 	d.EnvVars = t.Env
 	return nil
 }
@@ -92,6 +106,9 @@ func (d *PythonDefinition) getKindOptions() (buildtypes.KindOptions, error) {
 	if d.Version != "" {
 		ko["version"] = d.Version
 	}
+	if d.PythonPackageManager != "" {
+		ko["pythonPackageManager"] = d.PythonPackageManager
+	}
 	return ko, nil
 }
 
@@ -0,0 +1,112 @@
+package definitions
+
+import (
+	"path"
+
+	"github.com/airplanedev/cli/pkg/api"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/pkg/errors"
+)
+
+var _ taskKind = &RubyDefinition{}
+
+type RubyDefinition struct {
+	// Entrypoint is the relative path from the task definition file to the .rb file with the
+	// task's main method. Gems are installed from the Gemfile in the task root, if any.
+	Entrypoint  string      `json:"entrypoint"`
+	RubyVersion string      `json:"rubyVersion,omitempty"`
+	EnvVars     api.EnvVars `json:"envVars,omitempty"`
+
+	absoluteEntrypoint string `json:"-"`
+}
+
+func (d *RubyDefinition) copyToTask(task *api.Task, bc buildtypes.BuildConfig, opts GetTaskOpts) error {
+	task.Env = d.EnvVars
+	if opts.Bundle {
+		task.Command = []string{"ruby"}
+		task.Arguments = []string{
+			"/airplane/.airplane/shim.rb",
+			path.Join("/airplane/", bc["entrypoint"].(string)),
+			"{{JSON.stringify(params)}}",
+		}
+	}
+	return nil
+}
+
+func (d *RubyDefinition) update(t api.UpdateTaskRequest, availableResources []api.ResourceMetadata) error {
+	if v, ok := t.KindOptions["entrypoint"]; ok {
+		if sv, ok := v.(string); ok {
+			d.Entrypoint = sv
+		} else {
+			return errors.Errorf("expected string entrypoint, got %T instead", v)
+		}
+	}
+	if v, ok := t.KindOptions["rubyVersion"]; ok {
+		if sv, ok := v.(string); ok {
+			d.RubyVersion = sv
+		} else {
+			return errors.Errorf("expected string rubyVersion, got %T instead", v)
+		}
+	}
+	d.EnvVars = t.Env
+	return nil
+}
+
+func (d *RubyDefinition) setEntrypoint(entrypoint string) error {
+	d.Entrypoint = entrypoint
+	return nil
+}
+
+func (d *RubyDefinition) setAbsoluteEntrypoint(entrypoint string) error {
+	d.absoluteEntrypoint = entrypoint
+	return nil
+}
+
+func (d *RubyDefinition) getAbsoluteEntrypoint() (string, error) {
+	if d.absoluteEntrypoint == "" {
+		return "", ErrNoAbsoluteEntrypoint
+	}
+	return d.absoluteEntrypoint, nil
+}
+
+func (d *RubyDefinition) getKindOptions() (buildtypes.KindOptions, error) {
+	ko := buildtypes.KindOptions{}
+	if d.Entrypoint != "" {
+		ko["entrypoint"] = d.Entrypoint
+	}
+	if d.RubyVersion != "" {
+		ko["rubyVersion"] = d.RubyVersion
+	}
+	return ko, nil
+}
+
+func (d *RubyDefinition) getEntrypoint() (string, error) {
+	return d.Entrypoint, nil
+}
+
+func (d *RubyDefinition) getEnv() (api.EnvVars, error) {
+	return d.EnvVars, nil
+}
+
+func (d *RubyDefinition) setEnv(e api.EnvVars) error {
+	d.EnvVars = e
+	return nil
+}
+
+func (d *RubyDefinition) getConfigAttachments() []api.ConfigAttachment {
+	return []api.ConfigAttachment{}
+}
+
+func (d *RubyDefinition) getResourceAttachments() map[string]string {
+	return nil
+}
+
+func (d *RubyDefinition) getBuildType() (buildtypes.BuildType, buildtypes.BuildTypeVersion, buildtypes.BuildBase) {
+	return buildtypes.RubyBuildType, buildtypes.BuildTypeVersion(d.RubyVersion), buildtypes.BuildBaseNone
+}
+
+func (d *RubyDefinition) SetBuildVersionBase(v buildtypes.BuildTypeVersion, b buildtypes.BuildBase) {
+	if d.RubyVersion == "" {
+		d.RubyVersion = string(v)
+	}
+}
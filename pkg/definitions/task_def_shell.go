@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/build/shell"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/pkg/errors"
 )
@@ -13,6 +14,11 @@ var _ taskKind = &ShellDefinition{}
 type ShellDefinition struct {
 	Entrypoint string      `json:"entrypoint"`
 	EnvVars    api.EnvVars `json:"envVars,omitempty"`
+	// EnvFile are dotenv files to add to EnvVars. See Definition.GetEnvFiles.
+	EnvFile []string `json:"envFile,omitempty"`
+	// ParamsMode controls how parameters are passed to the script. By default, they are exported as
+	// PARAM_{SLUG} env vars. See shell.ParamsMode.
+	ParamsMode string `json:"paramsMode,omitempty"`
 
 	absoluteEntrypoint string `json:"-"`
 }
@@ -21,13 +27,12 @@ func (d *ShellDefinition) copyToTask(task *api.Task, bc buildtypes.BuildConfig,
 	task.Env = d.EnvVars
 	if opts.Bundle {
 		task.Command = []string{"bash"}
-		task.Arguments = []string{
-			".airplane/shim.sh",
-			fmt.Sprintf("./%s", bc["entrypoint"].(string)),
-		}
+		task.Arguments = []string{".airplane/shim.sh"}
+		task.Arguments = append(task.Arguments, shell.ShimArgs(shell.ParamsMode(d.ParamsMode))...)
+		task.Arguments = append(task.Arguments, fmt.Sprintf("./%s", bc["entrypoint"].(string)))
 		// Pass slug={{slug}} as an array to the shell task
 		for _, param := range task.Parameters {
-			task.Arguments = append(task.Arguments, fmt.Sprintf("%s={{params.%s}}", param.Slug, param.Slug))
+			task.Arguments = append(task.Arguments, shell.ParamArgTemplate(param.Slug, param.Type == api.TypeUpload))
 		}
 		task.InterpolationMode = "jst"
 	}
@@ -43,6 +48,13 @@ func (d *ShellDefinition) update(t api.UpdateTaskRequest, availableResources []a
 			return errors.Errorf("expected string entrypoint, got %T instead", v)
 		}
 	}
+	if v, ok := t.KindOptions["paramsMode"]; ok {
+		if sv, ok := v.(string); ok {
+			d.ParamsMode = sv
+		} else {
+			return errors.Errorf("expected string paramsMode, got %T instead", v)
+		}
+	}
 	d.EnvVars = t.Env
 	return nil
 }
@@ -65,9 +77,13 @@ func (d *ShellDefinition) getAbsoluteEntrypoint() (string, error) {
 }
 
 func (d *ShellDefinition) getKindOptions() (buildtypes.KindOptions, error) {
-	return buildtypes.KindOptions{
+	ko := buildtypes.KindOptions{
 		"entrypoint": d.Entrypoint,
-	}, nil
+	}
+	if d.ParamsMode != "" {
+		ko["paramsMode"] = d.ParamsMode
+	}
+	return ko, nil
 }
 
 func (d *ShellDefinition) getEntrypoint() (string, error) {
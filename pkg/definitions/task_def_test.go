@@ -1,10 +1,12 @@
 package definitions
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/build/shell"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/utils/pointers"
 	"github.com/stretchr/testify/require"
@@ -318,6 +320,56 @@ rest:
 		"body": "{\n  \"name\": \"foo\",\n  \"number\": 30\n}\n"
 	}
 }
+`,
+		},
+		{
+			name: "options source",
+			def: Definition{
+				Slug: "hello_world",
+				Parameters: []ParameterDefinition{
+					{
+						Slug: "customer",
+						Type: "shorttext",
+						OptionsSource: &OptionsSourceDefinition{
+							Task:      "list_customers",
+							ValuePath: "id",
+							LabelPath: "name",
+						},
+					},
+				},
+				Python: &PythonDefinition{},
+			},
+			expectedYAML: `slug: hello_world
+parameters:
+- slug: customer
+  type: shorttext
+  options:
+    source:
+      task: list_customers
+      valuePath: id
+      labelPath: name
+python:
+  entrypoint: ""
+`,
+			expectedJSON: `{
+	"slug": "hello_world",
+	"parameters": [
+		{
+			"slug": "customer",
+			"type": "shorttext",
+			"options": {
+				"source": {
+					"task": "list_customers",
+					"valuePath": "id",
+					"labelPath": "name"
+				}
+			}
+		}
+	],
+	"python": {
+		"entrypoint": ""
+	}
+}
 `,
 		},
 	} {
@@ -1103,6 +1155,12 @@ func TestTaskToDefinition(t *testing.T) {
 					},
 				},
 				Schedules: map[string]ScheduleDefinition{
+					"disabled_trigger": {
+						Name:        "disabled trigger",
+						Description: "disabled trigger",
+						CronExpr:    exampleCron.String(),
+						Paused:      true,
+					},
 					"good_schedule": {
 						Name:        "good schedule",
 						Description: "good schedule",
@@ -1130,8 +1188,9 @@ func TestTaskToDefinition(t *testing.T) {
 				Arguments:   []string{"{{JSON.stringify(params)}}"},
 				Kind:        buildtypes.TaskKindPython,
 				KindOptions: buildtypes.KindOptions{
-					"entrypoint": "main.py",
-					"base":       buildtypes.BuildBaseSlim,
+					"entrypoint":           "main.py",
+					"base":                 buildtypes.BuildBaseSlim,
+					"pythonPackageManager": buildtypes.PythonPackageManagerPoetry,
 				},
 				Env: api.EnvVars{
 					"value": api.EnvVarValue{
@@ -1157,7 +1216,8 @@ func TestTaskToDefinition(t *testing.T) {
 							Config: pointers.String("config"),
 						},
 					},
-					Base: buildtypes.BuildBaseSlim,
+					Base:                 buildtypes.BuildBaseSlim,
+					PythonPackageManager: buildtypes.PythonPackageManagerPoetry,
 				},
 				AllowSelfApprovals:    DefaultTrueDefinition{pointers.Bool(true)},
 				RestrictCallers:       []string{},
@@ -1368,6 +1428,44 @@ func TestDefinitionToUpdateTaskRequest(t *testing.T) {
 				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
 			},
 		},
+		{
+			name: "node task with agent pool",
+			definition: Definition{
+				Name: "Node Task",
+				Slug: "node_task",
+				Node: &NodeDefinition{
+					Entrypoint:  "main.ts",
+					NodeVersion: "14",
+				},
+				AgentPool: "gpu",
+			},
+			request: api.UpdateTaskRequest{
+				Name:       "Node Task",
+				Slug:       "node_task",
+				Parameters: []api.Parameter{},
+				Resources:  map[string]string{},
+				Configs:    &[]api.ConfigAttachment{},
+				Kind:       buildtypes.TaskKindNode,
+				KindOptions: buildtypes.KindOptions{
+					"entrypoint":  "main.ts",
+					"nodeVersion": "14",
+				},
+				ExecuteRules: api.UpdateExecuteRulesRequest{
+					DisallowSelfApprove: pointers.Bool(false),
+					RequireRequests:     pointers.Bool(false),
+					RestrictCallers:     []string{},
+					ConcurrencyKey:      &emptyStr,
+					ConcurrencyLimit:    pointers.Int64(1),
+				},
+				Timeout: 0,
+				Env:     api.EnvVars{},
+				Constraints: api.RunConstraints{
+					Labels: []api.AgentLabel{},
+					Pool:   "gpu",
+				},
+				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
+			},
+		},
 		{
 			name:     "node task from bundle",
 			isBundle: true,
@@ -1416,6 +1514,179 @@ func TestDefinitionToUpdateTaskRequest(t *testing.T) {
 				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
 			},
 		},
+		{
+			name:     "deno task from bundle",
+			isBundle: true,
+			definition: Definition{
+				Name: "Deno Task",
+				Slug: "deno_task",
+				Deno: &DenoDefinition{
+					DenoVersion: "2",
+				},
+				buildConfig: buildtypes.BuildConfig{
+					"entrypointFunc": "myTask",
+					"entrypoint":     "tasks/main.airplane.ts",
+				},
+			},
+			request: api.UpdateTaskRequest{
+				Name:    "Deno Task",
+				Slug:    "deno_task",
+				Command: []string{"deno"},
+				Arguments: []string{
+					"run",
+					"--allow-all",
+					"/airplane/.airplane/universal-shim.ts",
+					"/airplane/tasks/main.airplane.ts",
+					"myTask",
+					"{{JSON.stringify(params)}}",
+				},
+				Parameters: []api.Parameter{},
+				Resources:  map[string]string{},
+				Configs:    &[]api.ConfigAttachment{},
+				Kind:       buildtypes.TaskKindDeno,
+				KindOptions: buildtypes.KindOptions{
+					"denoVersion": "2",
+				},
+				ExecuteRules: api.UpdateExecuteRulesRequest{
+					DisallowSelfApprove: pointers.Bool(false),
+					RequireRequests:     pointers.Bool(false),
+					RestrictCallers:     []string{},
+					ConcurrencyKey:      &emptyStr,
+					ConcurrencyLimit:    pointers.Int64(1),
+				},
+				Timeout: 0,
+				Env:     api.EnvVars{},
+				Constraints: api.RunConstraints{
+					Labels: []api.AgentLabel{},
+				},
+				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
+			},
+		},
+		{
+			name:     "go task from bundle",
+			isBundle: true,
+			definition: Definition{
+				Name: "Go Task",
+				Slug: "go_task",
+				Go: &GoDefinition{
+					GoVersion: "1.23",
+					BuildTags: []string{"prod"},
+				},
+				buildConfig: buildtypes.BuildConfig{
+					"entrypoint": "tasks/hello/hello_airplane.go",
+				},
+			},
+			request: api.UpdateTaskRequest{
+				Name:       "Go Task",
+				Slug:       "go_task",
+				Command:    []string{"/airplane/.airplane/bin/tasks/hello/task"},
+				Arguments:  []string{"{{JSON.stringify(params)}}"},
+				Parameters: []api.Parameter{},
+				Resources:  map[string]string{},
+				Configs:    &[]api.ConfigAttachment{},
+				Kind:       buildtypes.TaskKindGo,
+				KindOptions: buildtypes.KindOptions{
+					"goVersion": "1.23",
+					"buildTags": []string{"prod"},
+				},
+				ExecuteRules: api.UpdateExecuteRulesRequest{
+					DisallowSelfApprove: pointers.Bool(false),
+					RequireRequests:     pointers.Bool(false),
+					RestrictCallers:     []string{},
+					ConcurrencyKey:      &emptyStr,
+					ConcurrencyLimit:    pointers.Int64(1),
+				},
+				Timeout: 0,
+				Env:     api.EnvVars{},
+				Constraints: api.RunConstraints{
+					Labels: []api.AgentLabel{},
+				},
+				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
+			},
+		},
+		{
+			name:     "inline go task from bundle",
+			isBundle: true,
+			definition: Definition{
+				Name: "Go Task",
+				Slug: "go_task",
+				Go: &GoDefinition{
+					GoVersion: "1.23",
+					BuildTags: []string{"prod"},
+				},
+				buildConfig: buildtypes.BuildConfig{
+					"entrypoint":     "tasks/hello/hello_airplane.go",
+					"entrypointFunc": "Hello",
+				},
+			},
+			request: api.UpdateTaskRequest{
+				Name:       "Go Task",
+				Slug:       "go_task",
+				Command:    []string{"/airplane/.airplane/bin/tasks/hello/task"},
+				Arguments:  []string{"Hello", "{{JSON.stringify(params)}}"},
+				Parameters: []api.Parameter{},
+				Resources:  map[string]string{},
+				Configs:    &[]api.ConfigAttachment{},
+				Kind:       buildtypes.TaskKindGo,
+				KindOptions: buildtypes.KindOptions{
+					"goVersion": "1.23",
+					"buildTags": []string{"prod"},
+				},
+				ExecuteRules: api.UpdateExecuteRulesRequest{
+					DisallowSelfApprove: pointers.Bool(false),
+					RequireRequests:     pointers.Bool(false),
+					RestrictCallers:     []string{},
+					ConcurrencyKey:      &emptyStr,
+					ConcurrencyLimit:    pointers.Int64(1),
+				},
+				Timeout: 0,
+				Env:     api.EnvVars{},
+				Constraints: api.RunConstraints{
+					Labels: []api.AgentLabel{},
+				},
+				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
+			},
+		},
+		{
+			name:     "ruby task from bundle",
+			isBundle: true,
+			definition: Definition{
+				Name: "Ruby Task",
+				Slug: "ruby_task",
+				Ruby: &RubyDefinition{
+					RubyVersion: "3.3",
+				},
+				buildConfig: buildtypes.BuildConfig{
+					"entrypoint": "tasks/hello.rb",
+				},
+			},
+			request: api.UpdateTaskRequest{
+				Name:       "Ruby Task",
+				Slug:       "ruby_task",
+				Command:    []string{"ruby"},
+				Arguments:  []string{"/airplane/.airplane/shim.rb", "/airplane/tasks/hello.rb", "{{JSON.stringify(params)}}"},
+				Parameters: []api.Parameter{},
+				Resources:  map[string]string{},
+				Configs:    &[]api.ConfigAttachment{},
+				Kind:       buildtypes.TaskKindRuby,
+				KindOptions: buildtypes.KindOptions{
+					"rubyVersion": "3.3",
+				},
+				ExecuteRules: api.UpdateExecuteRulesRequest{
+					DisallowSelfApprove: pointers.Bool(false),
+					RequireRequests:     pointers.Bool(false),
+					RestrictCallers:     []string{},
+					ConcurrencyKey:      &emptyStr,
+					ConcurrencyLimit:    pointers.Int64(1),
+				},
+				Timeout: 0,
+				Env:     api.EnvVars{},
+				Constraints: api.RunConstraints{
+					Labels: []api.AgentLabel{},
+				},
+				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
+			},
+		},
 		{
 			name: "shell task",
 			definition: Definition{
@@ -1474,18 +1745,77 @@ func TestDefinitionToUpdateTaskRequest(t *testing.T) {
 				Arguments: []string{
 					".airplane/shim.sh",
 					"./main.sh",
-					"one={{params.one}}",
-					"two={{params.two}}",
+					shell.ParamArgTemplate("one", false),
+					shell.ParamArgTemplate("two", false),
+				},
+				Parameters: []api.Parameter{
+					{Slug: "one", Name: "One", Type: "string"},
+					{Slug: "two", Name: "Two", Type: "boolean"},
+				},
+				Resources: map[string]string{},
+				Configs:   &[]api.ConfigAttachment{},
+				Kind:      buildtypes.TaskKindShell,
+				KindOptions: buildtypes.KindOptions{
+					"entrypoint": "main.sh",
+				},
+				ExecuteRules: api.UpdateExecuteRulesRequest{
+					DisallowSelfApprove: pointers.Bool(false),
+					RequireRequests:     pointers.Bool(false),
+					RestrictCallers:     []string{},
+					ConcurrencyKey:      &emptyStr,
+					ConcurrencyLimit:    pointers.Int64(1),
+				},
+				InterpolationMode: pointers.String("jst"),
+				Timeout:           0,
+				Env:               api.EnvVars{},
+				Constraints: api.RunConstraints{
+					Labels: []api.AgentLabel{},
+				},
+				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
+			},
+		},
+		{
+			name:     "shell task from bundle with flags",
+			isBundle: true,
+			definition: Definition{
+				Name: "Shell Task",
+				Slug: "shell_task",
+				Shell: &ShellDefinition{
+					Entrypoint: "main.sh",
+					ParamsMode: "flags",
+				},
+				Parameters: []ParameterDefinition{
+					{Slug: "one", Name: "One", Type: "shorttext"},
+					{Slug: "two", Name: "Two", Type: "boolean"},
+					{Slug: "three", Name: "Three", Type: "upload"},
+				},
+				buildConfig: buildtypes.BuildConfig{
+					"entrypoint": "main.sh",
+				},
+			},
+			request: api.UpdateTaskRequest{
+				Name:    "Shell Task",
+				Slug:    "shell_task",
+				Command: []string{"bash"},
+				Arguments: []string{
+					".airplane/shim.sh",
+					"--params=flags",
+					"./main.sh",
+					shell.ParamArgTemplate("one", false),
+					shell.ParamArgTemplate("two", false),
+					"three={{JSON.stringify(params.three)}}",
 				},
 				Parameters: []api.Parameter{
 					{Slug: "one", Name: "One", Type: "string"},
 					{Slug: "two", Name: "Two", Type: "boolean"},
+					{Slug: "three", Name: "Three", Type: "upload"},
 				},
 				Resources: map[string]string{},
 				Configs:   &[]api.ConfigAttachment{},
 				Kind:      buildtypes.TaskKindShell,
 				KindOptions: buildtypes.KindOptions{
 					"entrypoint": "main.sh",
+					"paramsMode": "flags",
 				},
 				ExecuteRules: api.UpdateExecuteRulesRequest{
 					DisallowSelfApprove: pointers.Bool(false),
@@ -1540,6 +1870,60 @@ func TestDefinitionToUpdateTaskRequest(t *testing.T) {
 				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
 			},
 		},
+		{
+			name:     "dockerfile task",
+			isBundle: true,
+			definition: Definition{
+				Name: "Dockerfile Task",
+				Slug: "dockerfile_task",
+				Image: &ImageDefinition{
+					Dockerfile: "Dockerfile",
+					BuildArgs:  map[string]string{"VERSION": "1"},
+					Entrypoint: "python",
+					Command:    "main.py --name {{params.name}}",
+				},
+				Parameters: []ParameterDefinition{
+					{Slug: "name", Name: "Name", Type: "shorttext"},
+				},
+			},
+			request: api.UpdateTaskRequest{
+				Name:    "Dockerfile Task",
+				Slug:    "dockerfile_task",
+				Command: []string{},
+				Arguments: []string{
+					"name={{params.name}}",
+					"--",
+					"python",
+					"main.py",
+					"--name",
+					"{{params.name}}",
+				},
+				Parameters: []api.Parameter{
+					{Slug: "name", Name: "Name", Type: "string"},
+				},
+				Resources: map[string]string{},
+				Configs:   &[]api.ConfigAttachment{},
+				Kind:      buildtypes.TaskKindDockerfile,
+				KindOptions: buildtypes.KindOptions{
+					"dockerfile": "Dockerfile",
+					"buildArgs":  map[string]string{"VERSION": "1"},
+				},
+				ExecuteRules: api.UpdateExecuteRulesRequest{
+					DisallowSelfApprove: pointers.Bool(false),
+					RequireRequests:     pointers.Bool(false),
+					RestrictCallers:     []string{},
+					ConcurrencyKey:      &emptyStr,
+					ConcurrencyLimit:    pointers.Int64(1),
+				},
+				InterpolationMode: pointers.String("jst"),
+				Timeout:           0,
+				Env:               api.EnvVars{},
+				Constraints: api.RunConstraints{
+					Labels: []api.AgentLabel{},
+				},
+				DefaultRunPermissions: (*api.DefaultRunPermissions)(pointers.String(string(api.DefaultRunPermissionTaskViewers))),
+			},
+		},
 		{
 			name: "rest task",
 			definition: Definition{
@@ -2105,11 +2489,15 @@ func TestDefinitionGetSchedules(t *testing.T) {
 					"param_one": 5.5,
 				},
 			},
+			"bar": {
+				CronExpr: "0 12 * * *",
+				Paused:   true,
+			},
 		},
 	}
 
 	schedules := def.GetSchedules()
-	require.Len(schedules, 1)
+	require.Len(schedules, 2)
 	require.Contains(schedules, "foo")
 
 	scheduleDef := schedules["foo"]
@@ -2119,6 +2507,8 @@ func TestDefinitionGetSchedules(t *testing.T) {
 	require.Len(scheduleDef.ParamValues, 1)
 	require.Contains(scheduleDef.ParamValues, "param_one")
 	require.Equal(scheduleDef.ParamValues["param_one"], 5.5)
+	require.False(scheduleDef.Paused)
+	require.True(schedules["bar"].Paused)
 }
 
 func TestDefinitionGetConfigAttachments(t *testing.T) {
@@ -2150,3 +2540,131 @@ func TestDefinitionGetConfigAttachments(t *testing.T) {
 		},
 	})
 }
+
+func TestConvertFileParameter(t *testing.T) {
+	require := require.New(t)
+
+	param, err := convertParameterDefToAPI(ParameterDefinition{
+		Name: "Invoice",
+		Slug: "invoice",
+		Type: "file",
+	})
+	require.NoError(err)
+	require.Equal(api.TypeUpload, param.Type)
+
+	// Deployed file parameters are upload parameters.
+	def, err := convertParameterAPIToDef(param)
+	require.NoError(err)
+	require.Equal("upload", def.Type)
+}
+
+func TestDockerfileBuildContext(t *testing.T) {
+	require := require.New(t)
+
+	def := Definition{}
+	require.NoError(def.Unmarshal(DefFormatYAML, []byte(`
+slug: dockerfile_task
+docker:
+  dockerfile: docker/Dockerfile
+  buildContext: .
+  buildArgs:
+    VERSION: "1"
+  command: ./run.sh
+`)))
+	kind, err := def.Kind()
+	require.NoError(err)
+	require.Equal(buildtypes.TaskKindDockerfile, kind)
+
+	def.SetDefnFilePath("/repo/tasks/task.yaml")
+	require.NoError(def.SetAbsoluteEntrypoint("/repo/tasks/docker/Dockerfile"))
+	root, bc, err := def.DockerfileBuildContext()
+	require.NoError(err)
+	require.Equal("/repo/tasks", root)
+	require.Equal(&buildtypes.DockerfileBuildContext{
+		Path:      "docker/Dockerfile",
+		BuildArgs: map[string]string{"VERSION": "1"},
+	}, bc)
+
+	// The Dockerfile must be inside its build context.
+	def.Image.BuildContext = "other"
+	_, _, err = def.DockerfileBuildContext()
+	require.Error(err)
+
+	// Tasks use either an image or a Dockerfile.
+	require.Error(def.Unmarshal(DefFormatYAML, []byte(`
+slug: dockerfile_task
+docker:
+  image: alpine:3
+  dockerfile: Dockerfile
+  command: echo
+`)))
+}
+
+func TestSetWorkdirPaths(t *testing.T) {
+	require := require.New(t)
+
+	d := Definition{Node: &NodeDefinition{Entrypoint: "my_task.ts"}}
+	// Host paths use the OS separator, but the workdir is a path in the container.
+	taskroot := filepath.Join(t.TempDir(), "project")
+	require.NoError(d.SetWorkdir(taskroot, filepath.Join(taskroot, "packages", "tasks")))
+
+	config, err := d.GetBuildConfig()
+	require.NoError(err)
+	require.Equal("/packages/tasks", config["workdir"])
+}
+
+func TestBuildPlatforms(t *testing.T) {
+	require := require.New(t)
+
+	var def Definition
+	require.NoError(def.Unmarshal(DefFormatYAML, []byte(`
+slug: my_task
+buildPlatforms: [linux/amd64, linux/arm64]
+node:
+  entrypoint: my_task.ts
+  nodeVersion: "18"
+`)))
+	require.Equal([]string{"linux/amd64", "linux/arm64"}, def.BuildPlatforms)
+
+	config, err := def.GetBuildConfig()
+	require.NoError(err)
+	platforms, err := buildtypes.BuildPlatforms(buildtypes.KindOptions(config))
+	require.NoError(err)
+	require.Equal([]string{"linux/amd64", "linux/arm64"}, platforms)
+
+	require.Error(def.Unmarshal(DefFormatYAML, []byte(`
+slug: my_task
+buildPlatforms: [windows/amd64]
+node:
+  entrypoint: my_task.ts
+  nodeVersion: "18"
+`)))
+}
+
+func TestBuildArgsAndSecrets(t *testing.T) {
+	require := require.New(t)
+
+	var def Definition
+	require.NoError(def.Unmarshal(DefFormatYAML, []byte(`
+slug: my_task
+buildArgs:
+  PIP_INDEX_URL: https://pypi.example.com/simple
+buildSecrets: [PIP_TOKEN]
+python:
+  entrypoint: my_task.py
+`)))
+	require.Equal(map[string]string{"PIP_INDEX_URL": "https://pypi.example.com/simple"}, def.BuildArgs)
+	require.Equal([]string{"PIP_TOKEN"}, def.BuildSecrets)
+
+	config, err := def.GetBuildConfig()
+	require.NoError(err)
+	require.Equal(map[string]string{"PIP_INDEX_URL": "https://pypi.example.com/simple"}, config[buildtypes.KindOptionBuildArgs])
+	require.Equal([]string{"PIP_TOKEN"}, buildtypes.BuildSecrets(buildtypes.KindOptions(config)))
+
+	require.Error(def.Unmarshal(DefFormatYAML, []byte(`
+slug: my_task
+buildSecrets: [pip-token]
+python:
+  entrypoint: my_task.py
+`)))
+}
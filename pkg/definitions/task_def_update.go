@@ -26,6 +26,8 @@ func (d *Definition) Update(req api.UpdateTaskRequest, opts UpdateOptions) error
 	d.Description = req.Description
 	d.Runtime = req.Runtime
 	d.Timeout = req.Timeout
+	d.Priority = req.Priority
+	d.RunAs = req.RunAs
 
 	if err := d.updateKindSpecific(req, opts.AvailableResources); err != nil {
 		return err
@@ -46,6 +48,7 @@ func (d *Definition) Update(req api.UpdateTaskRequest, opts UpdateOptions) error
 		}
 	}
 
+	d.AgentPool = req.Constraints.Pool
 	if req.Constraints.Labels != nil {
 		d.Constraints = map[string]string{}
 		for _, label := range req.Constraints.Labels {
@@ -87,16 +90,18 @@ func (d *Definition) Update(req api.UpdateTaskRequest, opts UpdateOptions) error
 				// This trigger is not a schedule deployed via code.
 				continue
 			}
-			if trigger.ArchivedAt != nil || trigger.DisabledAt != nil {
-				// Trigger is archived or disabled, so don't add it to the definition.
+			if trigger.ArchivedAt != nil {
+				// Trigger is archived, so don't add it to the definition.
 				continue
 			}
 
+			// Disabled schedules are kept as paused, so that the next deploy doesn't re-enable them.
 			d.Schedules[*trigger.Slug] = ScheduleDefinition{
 				Name:        trigger.Name,
 				Description: trigger.Description,
 				CronExpr:    trigger.KindConfig.Schedule.CronExpr.String(),
 				ParamValues: trigger.KindConfig.Schedule.ParamValues,
+				Paused:      trigger.DisabledAt != nil,
 			}
 		}
 	}
@@ -127,7 +132,7 @@ func (d *Definition) updateResources(resources api.Resources, kind buildtypes.Ta
 
 func (d *Definition) updateKindSpecific(t api.UpdateTaskRequest, availableResources []api.ResourceMetadata) error {
 	switch t.Kind {
-	case buildtypes.TaskKindImage:
+	case buildtypes.TaskKindImage, buildtypes.TaskKindDockerfile:
 		if d.Image == nil {
 			d.Image = &ImageDefinition{}
 		}
@@ -147,6 +152,21 @@ func (d *Definition) updateKindSpecific(t api.UpdateTaskRequest, availableResour
 			d.Shell = &ShellDefinition{}
 		}
 		return d.Shell.update(t, availableResources)
+	case buildtypes.TaskKindDeno:
+		if d.Deno == nil {
+			d.Deno = &DenoDefinition{}
+		}
+		return d.Deno.update(t, availableResources)
+	case buildtypes.TaskKindGo:
+		if d.Go == nil {
+			d.Go = &GoDefinition{}
+		}
+		return d.Go.update(t, availableResources)
+	case buildtypes.TaskKindRuby:
+		if d.Ruby == nil {
+			d.Ruby = &RubyDefinition{}
+		}
+		return d.Ruby.update(t, availableResources)
 	case buildtypes.TaskKindSQL:
 		if d.SQL == nil {
 			d.SQL = &SQLDefinition{}
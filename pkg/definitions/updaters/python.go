@@ -37,11 +37,9 @@ func UpdatePythonTask(ctx context.Context, logger logger.Logger, root, path stri
 		}
 
 		pyVersion := ""
-		if config.HasAirplaneConfig(root) {
-			cfg, err := config.NewAirplaneConfigFromFile(root)
-			if err != nil {
-				return errors.Wrap(err, "opening configuration file")
-			}
+		if cfg, ok, err := config.LoadAirplaneConfig(root); err != nil {
+			return errors.Wrap(err, "opening configuration file")
+		} else if ok {
 			pyVersion = cfg.Python.Version
 		}
 		envVars := []string{
@@ -2,11 +2,17 @@ package updaters
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/airplanedev/cli/pkg/definitions"
 	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
 	"github.com/pkg/errors"
 )
 
@@ -39,9 +45,267 @@ func CanUpdateYAMLTask(path string) (bool, error) {
 		return false, errors.Errorf("updating tasks within %q files is not supported", filepath.Base(path))
 	}
 
-	if _, err := os.Stat(path); err != nil {
+	buf, err := os.ReadFile(path)
+	if err != nil {
 		return false, errors.Wrap(err, "opening file")
 	}
+	// Updating the definition would inline the shared files that it extends.
+	if definitions.HasExtends(buf) {
+		return false, nil
+	}
 
 	return true, nil
 }
+
+// PinYAMLTaskID writes an `id` field into the task definition file at path so that future deploys
+// match the task by ID rather than by slug. The field is inserted directly above the slug, and the
+// rest of the file, including its formatting, comments and mode, is preserved.
+func PinYAMLTaskID(path string, id string) error {
+	format := definitions.GetTaskDefFormat(path)
+	if format == definitions.DefFormatUnknown {
+		return errors.Errorf("updating tasks within %q files is not supported", filepath.Base(path))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrap(err, "reading definition file")
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "reading definition file")
+	}
+
+	// JSON is YAML, so both formats are parsed by the YAML parser to find the slug.
+	file, err := parser.ParseBytes(buf, 0)
+	if err != nil {
+		return errors.Wrap(err, "parsing definition file")
+	}
+	var slug *ast.MappingValueNode
+	if len(file.Docs) > 0 {
+		switch n := file.Docs[0].Body.(type) {
+		case *ast.MappingNode:
+			for _, v := range n.Values {
+				if v.Key.GetToken().Value == "slug" {
+					slug = v
+				}
+			}
+		case *ast.MappingValueNode:
+			if n.Key.GetToken().Value == "slug" {
+				slug = n
+			}
+		}
+	}
+	if slug == nil {
+		return errors.Errorf("%s has no slug to pin the task ID next to", filepath.Base(path))
+	}
+
+	pos := slug.Key.GetToken().Position
+	lines := strings.SplitAfter(string(buf), "\n")
+	if pos.Line < 1 || pos.Line > len(lines) {
+		return errors.Errorf("unable to find the slug of %s", filepath.Base(path))
+	}
+	line := []rune(lines[pos.Line-1])
+	col := pos.Column - 1
+	if col < 0 || col > len(line) {
+		return errors.Errorf("unable to find the slug of %s", filepath.Base(path))
+	}
+	indent, rest := string(line[:col]), string(line[col:])
+
+	var field string
+	switch format {
+	case definitions.DefFormatJSON:
+		quoted, err := json.Marshal(id)
+		if err != nil {
+			return errors.Wrap(err, "marshalling task ID")
+		}
+		field = `"id": ` + string(quoted) + ","
+	default:
+		field = "id: " + id
+	}
+	if strings.TrimSpace(indent) == "" {
+		// The slug starts its line, so the ID gets a line of its own.
+		lines[pos.Line-1] = indent + field + "\n" + indent + rest
+	} else {
+		// e.g. {"name": "My task", "slug": "my_task"}
+		lines[pos.Line-1] = indent + field + " " + rest
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "")), info.Mode().Perm()); err != nil {
+		return errors.Wrap(err, "updating task")
+	}
+
+	return nil
+}
+
+// FixYAMLTaskDeprecations applies the edits of the deprecations that were fixed in the YAML task
+// definition at path. Unlike UpdateYAMLTask, only the deprecated fields are rewritten, so that the
+// rest of the file, including its formatting and comments, is preserved.
+func FixYAMLTaskDeprecations(path string, deprecations []definitions.Deprecation) error {
+	if definitions.GetTaskDefFormat(path) != definitions.DefFormatYAML {
+		return errors.Errorf("fixing deprecations within %q files is not supported", filepath.Base(path))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrap(err, "reading definition file")
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "reading definition file")
+	}
+
+	content := string(buf)
+	for _, d := range deprecations {
+		// Each edit moves the lines after it, so the file is parsed again for every edit.
+		if content, err = applyYAMLEdit(content, d.Edit); err != nil {
+			return errors.Wrapf(err, "fixing %s", d.Field)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(content), info.Mode().Perm()); err != nil {
+		return errors.Wrap(err, "updating task")
+	}
+	return nil
+}
+
+func applyYAMLEdit(content string, edit definitions.DeprecationEdit) (string, error) {
+	file, err := parser.ParseBytes([]byte(content), 0)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing definition file")
+	}
+	if len(file.Docs) == 0 || len(edit.Path) == 0 {
+		return "", errors.New("unable to find field")
+	}
+	var parent, field *ast.MappingValueNode
+	node := file.Docs[0].Body
+	for _, key := range edit.Path {
+		parent = field
+		if field = findYAMLField(node, key); field == nil {
+			return "", errors.New("unable to find field")
+		}
+		node = field.Value
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	if strings.HasSuffix(content, "\n") {
+		lines = lines[:len(lines)-1]
+	}
+	switch {
+	case edit.Value != "":
+		tk := field.Value.GetToken()
+		if tk.Position.Line != field.Key.GetToken().Position.Line {
+			return "", errors.New("only single-line values can be replaced")
+		}
+		line := []rune(lines[tk.Position.Line-1])
+		start := tk.Position.Column - 1
+		end := start + len([]rune(tk.Value))
+		value := edit.Value
+		switch tk.Type {
+		case token.DoubleQuoteType:
+			end = quotedEnd(line, start, '"')
+			value = strconv.Quote(value)
+		case token.SingleQuoteType:
+			end = quotedEnd(line, start, '\'')
+			value = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+		if start < 0 || end < 0 || end > len(line) {
+			return "", errors.New("only single-line values can be replaced")
+		}
+		lines[tk.Position.Line-1] = string(line[:start]) + value + string(line[end:])
+	case edit.MoveTo != "" || edit.Remove:
+		if parent == nil {
+			return "", errors.New("only nested fields can be moved")
+		}
+		if m, ok := parent.Value.(*ast.MappingNode); ok && m.IsFlowStyle {
+			return "", errors.New("only block-style fields can be moved")
+		}
+		start, end := yamlFieldLines(lines, field)
+		block := append([]string(nil), lines[start:end]...)
+		lines = append(lines[:start], lines[end:]...)
+		if edit.Remove {
+			break
+		}
+		if findYAMLField(file.Docs[0].Body, edit.MoveTo) != nil {
+			return "", errors.Errorf("%s is already set", edit.MoveTo)
+		}
+		// Move the field below the top-level field that contains it, at the top level's indentation.
+		top := findYAMLField(file.Docs[0].Body, edit.Path[0])
+		if top == nil {
+			return "", errors.New("unable to find field")
+		}
+		topCol := top.Key.GetToken().Position.Column - 1
+		indent := field.Key.GetToken().Position.Column - 1 - topCol
+		for i, l := range block {
+			if strings.HasPrefix(l, strings.Repeat(" ", indent)) {
+				l = l[indent:]
+			}
+			block[i] = l
+		}
+		key := field.Key.GetToken().Value
+		block[0] = block[0][:topCol] + edit.MoveTo + block[0][topCol+len(key):]
+		_, topEnd := yamlFieldLines(lines, top)
+		lines = append(lines[:topEnd], append(block, lines[topEnd:]...)...)
+	default:
+		return "", errors.New("empty edit")
+	}
+
+	out := strings.Join(lines, "")
+	if !strings.HasSuffix(out, "\n") && strings.HasSuffix(content, "\n") {
+		out += "\n"
+	}
+	return out, nil
+}
+
+// quotedEnd returns the index after the quote that closes the quoted scalar that starts at
+// line[start], or -1 if the scalar isn't closed on the line.
+func quotedEnd(line []rune, start int, quote rune) int {
+	for i := start + 1; i < len(line); i++ {
+		switch {
+		case quote == '"' && line[i] == '\\':
+			i++
+		case quote == '\'' && line[i] == '\'' && i+1 < len(line) && line[i+1] == '\'':
+			i++
+		case line[i] == quote:
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// findYAMLField returns the field of the mapping node that has the given key, if any.
+func findYAMLField(node ast.Node, key string) *ast.MappingValueNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		for _, v := range n.Values {
+			if v.Key.GetToken().Value == key {
+				return v
+			}
+		}
+	case *ast.MappingValueNode:
+		if n.Key.GetToken().Value == key {
+			return n
+		}
+	}
+	return nil
+}
+
+// yamlFieldLines returns the range of lines, [start, end), that a block-style field spans: its
+// key's line and the lines below it that are indented further, or that are sequence items at the
+// key's indentation. Blank lines and comments are only included if the field continues after them.
+func yamlFieldLines(lines []string, field *ast.MappingValueNode) (int, int) {
+	col := field.Key.GetToken().Position.Column - 1
+	start := field.Key.GetToken().Position.Line - 1
+	end := start + 1
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if strings.TrimSpace(trimmed) == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(lines[i]) - len(trimmed)
+		if indent < col || (indent == col && !strings.HasPrefix(trimmed, "-")) {
+			break
+		}
+		end = i + 1
+	}
+	return start, end
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/airplanedev/cli/pkg/api"
@@ -488,3 +489,135 @@ func TestUpdateYAMLTask(t *testing.T) {
 		})
 	}
 }
+
+func TestPinYAMLTaskID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ext      string
+		in       string
+		expected string
+	}{
+		{
+			name:     "yaml",
+			ext:      "task.yaml",
+			in:       "# My task.\nslug: my_task\nname: My task\n",
+			expected: "# My task.\nid: tsk123\nslug: my_task\nname: My task\n",
+		},
+		{
+			name:     "json",
+			ext:      "task.json",
+			in:       "{\n\t\"slug\": \"my_task\",\n\t\"node\": {\n\t\t\"entrypoint\": \"my_task.ts\"\n\t}\n}",
+			expected: "{\n\t\"id\": \"tsk123\",\n\t\"slug\": \"my_task\",\n\t\"node\": {\n\t\t\"entrypoint\": \"my_task.ts\"\n\t}\n}",
+		},
+		{
+			name:     "yaml without leading slug",
+			ext:      "task.yaml",
+			in:       "name: My task\n# The slug.\nslug: my_task\n",
+			expected: "name: My task\n# The slug.\nid: tsk123\nslug: my_task\n",
+		},
+		{
+			name:     "inline json",
+			ext:      "task.json",
+			in:       `{"name": "My task", "slug": "my_task"}`,
+			expected: `{"name": "My task", "id": "tsk123", "slug": "my_task"}`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			require := require.New(t)
+
+			f, err := os.CreateTemp("", "runtime-pin-yaml-*."+tC.ext)
+			require.NoError(err)
+			t.Cleanup(func() {
+				require.NoError(os.Remove(f.Name()))
+			})
+			_, err = f.WriteString(tC.in)
+			require.NoError(err)
+			require.NoError(f.Close())
+			require.NoError(os.Chmod(f.Name(), 0640))
+
+			require.NoError(PinYAMLTaskID(f.Name(), "tsk123"))
+
+			actual, err := os.ReadFile(f.Name())
+			require.NoError(err)
+			require.Equal(tC.expected, string(actual))
+			info, err := os.Stat(f.Name())
+			require.NoError(err)
+			require.Equal(os.FileMode(0640), info.Mode().Perm())
+		})
+	}
+}
+
+func TestPinYAMLTaskIDWithoutSlug(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "my_task.task.yaml")
+	require.NoError(os.WriteFile(path, []byte("name: My task\n"), 0644))
+
+	require.ErrorContains(PinYAMLTaskID(path, "tsk123"), "no slug")
+	buf, err := os.ReadFile(path)
+	require.NoError(err)
+	require.Equal("name: My task\n", string(buf))
+}
+
+func TestFixYAMLTaskDeprecations(t *testing.T) {
+	moveConfigs := definitions.Deprecation{Field: "sql.configs", Edit: definitions.DeprecationEdit{Path: []string{"sql", "configs"}, MoveTo: "configs"}}
+	removeConfigs := definitions.Deprecation{Field: "sql.configs", Edit: definitions.DeprecationEdit{Path: []string{"sql", "configs"}, Remove: true}}
+	setResource := definitions.Deprecation{Field: "sql.resource", Edit: definitions.DeprecationEdit{Path: []string{"sql", "resource"}, Value: "db"}}
+
+	testCases := []struct {
+		name         string
+		in           string
+		deprecations []definitions.Deprecation
+		expected     string
+		err          string
+	}{
+		{
+			name:         "keeps comments",
+			in:           "# My task.\nslug: my_task\nsql:\n  # The database.\n  resource: My database # By name.\n  entrypoint: query.sql\n  configs:\n  # The key.\n  - API_KEY\n\n# The timeout.\ntimeout: 60\n",
+			deprecations: []definitions.Deprecation{moveConfigs, setResource},
+			expected:     "# My task.\nslug: my_task\nsql:\n  # The database.\n  resource: db # By name.\n  entrypoint: query.sql\nconfigs:\n# The key.\n- API_KEY\n\n# The timeout.\ntimeout: 60\n",
+		},
+		{
+			name:         "indented sequence",
+			in:           "sql:\n  resource: \"My database\"\n  configs:\n    - API_KEY\n    - OTHER_KEY\n  entrypoint: query.sql\nslug: my_task\n",
+			deprecations: []definitions.Deprecation{moveConfigs, setResource},
+			expected:     "sql:\n  resource: \"db\"\n  entrypoint: query.sql\nconfigs:\n  - API_KEY\n  - OTHER_KEY\nslug: my_task\n",
+		},
+		{
+			name:         "flow sequence",
+			in:           "slug: my_task\nsql:\n  resource: 'It''s a db'\n  configs: [API_KEY]\n",
+			deprecations: []definitions.Deprecation{moveConfigs, setResource},
+			expected:     "slug: my_task\nsql:\n  resource: 'db'\nconfigs: [API_KEY]\n",
+		},
+		{
+			name:         "remove",
+			in:           "slug: my_task\nsql:\n  configs:\n  - OLD\n  entrypoint: query.sql\nconfigs:\n- NEW\n",
+			deprecations: []definitions.Deprecation{removeConfigs},
+			expected:     "slug: my_task\nsql:\n  entrypoint: query.sql\nconfigs:\n- NEW\n",
+		},
+		{
+			name:         "flow mapping",
+			in:           "slug: my_task\nsql: {resource: db, configs: [API_KEY]}\n",
+			deprecations: []definitions.Deprecation{moveConfigs},
+			err:          "only block-style fields can be moved",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.name, func(t *testing.T) {
+			require := require.New(t)
+			path := filepath.Join(t.TempDir(), "my_task.task.yaml")
+			require.NoError(os.WriteFile(path, []byte(tC.in), 0640))
+
+			err := FixYAMLTaskDeprecations(path, tC.deprecations)
+			actual, rerr := os.ReadFile(path)
+			require.NoError(rerr)
+			if tC.err != "" {
+				require.ErrorContains(err, tC.err)
+				require.Equal(tC.in, string(actual))
+				return
+			}
+			require.NoError(err)
+			require.Equal(tC.expected, string(actual))
+		})
+	}
+}
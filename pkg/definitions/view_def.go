@@ -29,9 +29,25 @@ type ViewDefinition struct {
 	Description string      `json:"description,omitempty"`
 	Entrypoint  string      `json:"entrypoint"`
 	EnvVars     api.EnvVars `json:"envVars,omitempty"`
+	// EnvFrom names env var sets from the airplane config to add to EnvVars.
+	EnvFrom []string `json:"envFrom,omitempty"`
+	// Links declare the tasks that the view runs, keyed by the name that the view's code uses to
+	// refer to them.
+	Links map[string]ViewLinkDefinition `json:"links,omitempty"`
 	// DefnFilePath is the absolute path to this View definition, if one exists.
 	DefnFilePath string               `json:"-"`
 	Base         buildtypes.BuildBase `json:"base,omitempty"`
+	// Bundler is the tool that bundles the view. Defaults to the Airplane-managed Vite config.
+	Bundler buildtypes.ViewBundler `json:"bundler,omitempty"`
+}
+
+// ViewLinkDefinition declares a task that a view runs, and how the view passes its parameters.
+type ViewLinkDefinition struct {
+	// Task is the slug of the linked task.
+	Task string `json:"task"`
+	// Params maps the names of the values that the view passes to the slugs of the task's
+	// parameters that they are passed as.
+	Params map[string]string `json:"params,omitempty"`
 }
 
 //go:embed view_schema.json
@@ -87,6 +87,40 @@ envVars:
 		}
 	}
 }
+`,
+		},
+		{
+			name: "links",
+			def: ViewDefinition{
+				Slug:       "hello_world",
+				Entrypoint: "entrypoint.tsx",
+				Links: map[string]ViewLinkDefinition{
+					"refund": {
+						Task:   "issue_refund",
+						Params: map[string]string{"email": "customer_email"},
+					},
+				},
+			},
+			expectedYAML: `slug: hello_world
+entrypoint: entrypoint.tsx
+links:
+  refund:
+    task: issue_refund
+    params:
+      email: customer_email
+`,
+			expectedJSON: `{
+	"slug": "hello_world",
+	"entrypoint": "entrypoint.tsx",
+	"links": {
+		"refund": {
+			"task": "issue_refund",
+			"params": {
+				"email": "customer_email"
+			}
+		}
+	}
+}
 `,
 		},
 		{
@@ -63,7 +63,7 @@ node:
   # can be absolute or relative to the location of the definition file.
   entrypoint: {{.Entrypoint}}
 
-  # The version of Node to use. Valid values: 14, 16, 18.
+  # The version of Node to use. Valid values: 14, 16, 18, 20, 22.
   nodeVersion: "{{.NodeVersion}}"
 
   # A map of environment variables to use when running the task. The value
@@ -0,0 +1,144 @@
+// Package api deploys tasks and views from Go programs, e.g. internal platforms that deploy on
+// behalf of their users, without shelling out to the CLI. Deploys discover, build and deploy the
+// same way as `airplane deploy`.
+package api
+
+import (
+	"context"
+
+	cliapi "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/deploy"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/prompts"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// Deployer deploys the tasks and views in a set of paths.
+type Deployer struct {
+	client cliapi.APIClient
+	opts   options
+}
+
+type options struct {
+	paths     []string
+	env       string
+	dryRun    bool
+	assumeYes bool
+	logger    logger.LoggerWithLoader
+}
+
+// Option configures a Deployer.
+type Option func(o *options)
+
+// WithPaths sets the files and directories to deploy the tasks and views of. Relative paths are
+// resolved against the working directory. Defaults to the working directory.
+func WithPaths(paths ...string) Option {
+	return func(o *options) {
+		o.paths = paths
+	}
+}
+
+// WithEnv sets the slug of the environment to deploy to. Defaults to the team's default
+// environment, which requires WithAssumeYes.
+func WithEnv(envSlug string) Option {
+	return func(o *options) {
+		o.env = envSlug
+	}
+}
+
+// WithDryRun builds the images of the tasks and views locally, without deploying them. It
+// requires a Docker daemon.
+func WithDryRun() Option {
+	return func(o *options) {
+		o.dryRun = true
+	}
+}
+
+// WithAssumeYes answers yes to the questions that `airplane deploy` asks, e.g. whether to deploy
+// to the default environment. Without it, deploys that would ask a question fail instead.
+func WithAssumeYes() Option {
+	return func(o *options) {
+		o.assumeYes = true
+	}
+}
+
+// WithLogger sets the logger that the progress of deploys is logged to. Defaults to stderr.
+func WithLogger(l logger.Logger) Option {
+	return func(o *options) {
+		o.logger = loaderless{l}
+	}
+}
+
+// New returns a Deployer that deploys with client.
+func New(client cliapi.APIClient, opts ...Option) *Deployer {
+	d := &Deployer{client: client}
+	for _, opt := range opts {
+		opt(&d.opts)
+	}
+	return d
+}
+
+// Deploy deploys the tasks and views. Deploys never prompt: questions that `airplane deploy` asks,
+// e.g. whether to deploy to the default environment, fail the deploy unless WithAssumeYes is set.
+func (d *Deployer) Deploy(ctx context.Context) error {
+	return deploy.Deploy(ctx, d.config())
+}
+
+func (d *Deployer) config() deploy.Config {
+	paths := d.opts.paths
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	return deploy.Config{
+		Root: &cli.Config{
+			Client:   d.client,
+			Prompter: confirmer{assumeYes: d.opts.assumeYes},
+		},
+		Client:               d.client,
+		Paths:                paths,
+		EnvSlug:              d.opts.env,
+		DryRun:               d.opts.dryRun,
+		DiscoveryConcurrency: discover.DefaultConcurrency,
+		BuildConcurrency:     1,
+		Logger:               d.opts.logger,
+	}
+}
+
+// confirmer is a Prompter for deploys, which can't prompt. It answers yes to questions if
+// assumeYes is set, and fails otherwise.
+type confirmer struct {
+	assumeYes bool
+}
+
+var _ prompts.Prompter = confirmer{}
+
+func (c confirmer) Confirm(question string, opts ...prompts.Opt) (bool, error) {
+	if !c.assumeYes {
+		return false, errors.Errorf("deploys can't prompt %q: answer yes with WithAssumeYes", question)
+	}
+	return true, nil
+}
+
+func (c confirmer) ConfirmWithAssumptions(question string, assumeYes, assumeNo bool, opts ...prompts.Opt) (bool, error) {
+	if assumeYes || assumeNo {
+		return assumeYes, nil
+	}
+	return c.Confirm(question, opts...)
+}
+
+func (confirmer) Input(question string, p *string, opts ...prompts.Opt) error {
+	return errors.Errorf("deploys can't prompt for input: %s", question)
+}
+
+// loaderless adapts a Logger that has no loader.
+type loaderless struct {
+	logger.Logger
+}
+
+func (loaderless) StopLoader() bool {
+	return false
+}
+
+func (loaderless) StartLoader() {}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	cliapi "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	require := require.New(t)
+	client := &cliapi.MockClient{}
+
+	cfg := New(client).config()
+	require.Equal([]string{"."}, cfg.Paths)
+	require.Equal("", cfg.EnvSlug)
+	require.False(cfg.DryRun)
+	require.Equal(discover.DefaultConcurrency, cfg.DiscoveryConcurrency)
+	require.Nil(cfg.Logger)
+	require.Equal(client, cfg.Client)
+
+	l := &logger.MockLogger{}
+	cfg = New(client,
+		WithPaths("tasks", "views/my_view.airplane.tsx"),
+		WithEnv("staging"),
+		WithDryRun(),
+		WithLogger(l),
+	).config()
+	require.Equal([]string{"tasks", "views/my_view.airplane.tsx"}, cfg.Paths)
+	require.Equal("staging", cfg.EnvSlug)
+	require.True(cfg.DryRun)
+	require.Equal(loaderless{l}, cfg.Logger)
+
+	// Deploys never prompt: questions fail the deploy unless they're answered yes.
+	_, err := cfg.Root.Prompter.ConfirmWithAssumptions("Continue deploying to the default environment?", false, false)
+	require.ErrorContains(err, "WithAssumeYes")
+	_, err = cfg.Root.Prompter.Confirm("Are you sure?")
+	require.Error(err)
+
+	cfg = New(client, WithAssumeYes()).config()
+	ok, err := cfg.Root.Prompter.ConfirmWithAssumptions("Continue deploying to the default environment?", false, false)
+	require.NoError(err)
+	require.True(ok)
+	ok, err = cfg.Root.Prompter.ConfirmWithAssumptions("Continue deploying to the default environment?", false, true)
+	require.NoError(err)
+	require.False(ok)
+}
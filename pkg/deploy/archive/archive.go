@@ -2,26 +2,52 @@ package archive
 
 import (
 	"context"
+	"io"
 	"os"
 	"path"
+	"path/filepath"
 	"sync"
 
 	"github.com/airplanedev/archiver"
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/build/ignore"
+	"github.com/airplanedev/cli/pkg/deploy/config"
 	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/goccy/go-yaml"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/singleflight"
 )
 
 type Archiver interface {
-	Archive(ctx context.Context, root string) (uploadID string, size int, err error)
+	Archive(ctx context.Context, root string) (Result, error)
+}
+
+// Rewriter replaces the contents of files as they're archived.
+type Rewriter interface {
+	// Matches returns whether the file at path may be rewritten. Only matching files are read
+	// into memory.
+	Matches(path string) bool
+	// Rewrite returns the contents that the file at path is archived with instead of buf, or nil
+	// to archive buf as is.
+	Rewrite(path string, buf []byte) ([]byte, error)
+}
+
+// Result describes an archived bundle.
+type Result struct {
+	// UploadID is the ID of the uploaded archive.
+	UploadID string
+	// SizeBytes is the size of the archive, or zero if a previous upload of it was reused.
+	SizeBytes int
+	// Digest is the hex-encoded SHA-256 digest of the archive. Archives are reproducible, so
+	// archiving the same files always produces the same digest.
+	Digest string
 }
 
 type apiArchiver struct {
 	logger   logger.Logger
 	client   api.IAPIClient
 	uploader Uploader
+	rewriter Rewriter
 
 	uploadArchiveSingleFlightGroup singleflight.Group
 	uploadedArchives               sync.Map
@@ -29,35 +55,39 @@ type apiArchiver struct {
 
 var _ Archiver = &apiArchiver{}
 
-func NewAPIArchiver(logger logger.Logger, client api.IAPIClient, uploader Uploader) Archiver {
+// NewAPIArchiver returns an archiver that uploads archives to the API. If rewriter is set, files
+// are rewritten as they're archived.
+func NewAPIArchiver(logger logger.Logger, client api.IAPIClient, uploader Uploader, rewriter Rewriter) Archiver {
 	return &apiArchiver{
 		uploadedArchives: sync.Map{},
 		logger:           logger,
 		client:           client,
 		uploader:         uploader,
+		rewriter:         rewriter,
 	}
 }
 
-func (d *apiArchiver) Archive(ctx context.Context, root string) (string, int, error) {
+func (d *apiArchiver) Archive(ctx context.Context, root string) (Result, error) {
 	tmpdir, err := os.MkdirTemp("", "airplane-builds-")
 	if err != nil {
-		return "", 0, errors.Wrap(err, "creating temporary directory for remote build")
+		return Result{}, errors.Wrap(err, "creating temporary directory for remote build")
 	}
 	defer os.RemoveAll(tmpdir)
 
 	archivePath := path.Join(tmpdir, "archive.tar.gz")
-	if err := archiveTaskDir(root, archivePath); err != nil {
-		return "", 0, err
+	digest, err := archiveTaskDir(root, archivePath, d.rewriter)
+	if err != nil {
+		return Result{}, err
 	}
 
 	uploadIDRes, err, _ := d.uploadArchiveSingleFlightGroup.Do(root, func() (interface{}, error) {
 		return d.uploadArchive(ctx, archivePath, root)
 	})
 	if err != nil {
-		return "", 0, err
+		return Result{}, err
 	}
 	upload := uploadIDRes.(uploadRes)
-	return upload.uploadID, upload.sizeBytes, nil
+	return Result{UploadID: upload.uploadID, SizeBytes: upload.sizeBytes, Digest: digest}, nil
 }
 
 type uploadRes struct {
@@ -104,30 +134,121 @@ func (d *apiArchiver) uploadArchive(ctx context.Context, archivePath, rootPath s
 	return uploadRes{uploadID: uploadID, sizeBytes: sizeBytes}, nil
 }
 
-func archiveTaskDir(root string, archivePath string) error {
+// archiveTaskDir archives the contents of root to archivePath, and returns the hex-encoded SHA-256
+// digest of the archive. If rewriter is set, matching files are archived with their rewritten
+// contents.
+func archiveTaskDir(root string, archivePath string, rewriter Rewriter) (string, error) {
 	// mholt/archiver takes a list of "sources" (files/directories) that will
 	// be included in the root of the archive. In our case, we want the root of
 	// the archive to be the contents of the task directory, rather than the
 	// task directory itself.
+	workspaceConfig, err := workspaceAirplaneConfig(root, path.Dir(archivePath))
+	if err != nil {
+		return "", err
+	}
 	var sources []string
 	if files, err := os.ReadDir(root); err != nil {
-		return errors.Wrap(err, "inspecting files in task root")
+		return "", errors.Wrap(err, "inspecting files in task root")
 	} else {
 		for _, f := range files {
+			if workspaceConfig != "" && f.Name() == config.FileName {
+				continue
+			}
 			sources = append(sources, path.Join(root, f.Name()))
 		}
 	}
+	if workspaceConfig != "" {
+		sources = append(sources, workspaceConfig)
+	}
 
-	var err error
 	arch := archiver.NewTarGz()
 	arch.Tar.IncludeFunc, err = ignore.Func(root)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	if err := arch.Archive(sources, archivePath); err != nil {
-		return errors.Wrap(err, "building archive")
+	// The archive is written to a temporary file first, since it's rewritten to be reproducible.
+	rawPath := archivePath + ".raw.tar.gz"
+	if err := arch.Archive(sources, rawPath); err != nil {
+		return "", errors.Wrap(err, "building archive")
 	}
+	defer os.Remove(rawPath)
+
+	return normalizeArchive(rawPath, archivePath, func(name string, r io.Reader) ([]byte, error) {
+		if rewriter == nil {
+			return nil, nil
+		}
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if !rewriter.Matches(p) {
+			return nil, nil
+		}
+		buf, err := io.ReadAll(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", name)
+		}
+		rewritten, err := rewriter.Rewrite(p, buf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rewriting %s", name)
+		}
+		if rewritten == nil {
+			return buf, nil
+		}
+		return rewritten, nil
+	})
+}
 
-	return nil
+// workspaceAirplaneConfig writes the airplane.yaml of root, merged with the shared build settings
+// of its workspace, to a file in dir, so that builds of the archive use the workspace's settings
+// even though the workspace file isn't archived. It returns an empty path if root isn't a member
+// of a workspace.
+func workspaceAirplaneConfig(root, dir string) (string, error) {
+	ws, ok, err := config.FindWorkspace(root)
+	if err != nil || !ok || !ws.Contains(root) {
+		return "", err
+	}
+	c, _, err := config.LoadAirplaneConfig(root)
+	if err != nil {
+		return "", err
+	}
+	buf, err := yaml.Marshal(c)
+	if err != nil {
+		return "", errors.Wrap(err, "marshaling airplane config")
+	}
+	if err := os.MkdirAll(path.Join(dir, "workspace"), 0755); err != nil {
+		return "", errors.Wrap(err, "creating workspace config directory")
+	}
+	file := path.Join(dir, "workspace", config.FileName)
+	if err := os.WriteFile(file, buf, 0644); err != nil {
+		return "", errors.Wrap(err, "writing workspace config")
+	}
+	return file, nil
+}
+
+type localArchiver struct{}
+
+var _ Archiver = &localArchiver{}
+
+// NewLocalArchiver returns an archiver that builds archives without uploading them, e.g. to
+// measure the cost of packaging a deploy. The returned upload IDs are empty.
+func NewLocalArchiver() Archiver {
+	return &localArchiver{}
+}
+
+func (d *localArchiver) Archive(ctx context.Context, root string) (Result, error) {
+	tmpdir, err := os.MkdirTemp("", "airplane-builds-")
+	if err != nil {
+		return Result{}, errors.Wrap(err, "creating temporary directory for archive")
+	}
+	defer os.RemoveAll(tmpdir)
+
+	archivePath := path.Join(tmpdir, "archive.tar.gz")
+	digest, err := archiveTaskDir(root, archivePath, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "stat on archive file")
+	}
+	return Result{SizeBytes: int(info.Size()), Digest: digest}, nil
 }
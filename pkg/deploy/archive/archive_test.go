@@ -1,11 +1,18 @@
 package archive
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"io"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/airplanedev/cli/pkg/api/mock"
+	"github.com/airplanedev/cli/pkg/deploy/config"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,18 +49,121 @@ func TestArchive(t *testing.T) {
 			l := &logger.MockLogger{}
 			client := &mock.MockClient{}
 			uploader := &MockUploader{}
-			archiver := NewAPIArchiver(l, client, uploader)
+			archiver := NewAPIArchiver(l, client, uploader, nil)
 
 			var numUploaded int
 			for _, root := range tC.roots {
-				_, sizeBytes, err := archiver.Archive(context.Background(), root)
+				res, err := archiver.Archive(context.Background(), root)
 				require.NoError(err)
+				require.Len(res.Digest, 64)
 				numUploaded++
 				if numUploaded > tC.numUploaded {
-					assert.Empty(sizeBytes)
+					assert.Empty(res.SizeBytes)
 				}
 			}
 			assert.Equal(tC.numUploaded, uploader.UploadCount)
 		})
 	}
 }
+
+func TestWorkspaceAirplaneConfig(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, config.WorkspaceFileName), []byte("members: [member]\npython:\n  version: \"3.11\"\n"), 0644))
+	require.NoError(os.MkdirAll(filepath.Join(dir, "member"), 0755))
+	require.NoError(os.MkdirAll(filepath.Join(dir, "other"), 0755))
+
+	// Directories that aren't members keep their own airplane.yaml.
+	file, err := workspaceAirplaneConfig(filepath.Join(dir, "other"), t.TempDir())
+	require.NoError(err)
+	require.Empty(file)
+
+	file, err = workspaceAirplaneConfig(filepath.Join(dir, "member"), t.TempDir())
+	require.NoError(err)
+	require.Equal(config.FileName, filepath.Base(file))
+	c, err := config.NewAirplaneConfigFromFile(file)
+	require.NoError(err)
+	require.Equal("3.11", c.Python.Version)
+}
+
+func TestArchiveReproducible(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(root, "src"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(root, "src", "task.ts"), []byte("export default {}"), 0644))
+
+	digest, err := archiveTaskDir(root, filepath.Join(t.TempDir(), "archive.tar.gz"), nil)
+	require.NoError(err)
+
+	// Modification times don't change the archive, but contents do.
+	later := time.Now().Add(time.Hour)
+	require.NoError(os.Chtimes(filepath.Join(root, "src", "task.ts"), later, later))
+	touched, err := archiveTaskDir(root, filepath.Join(t.TempDir(), "archive.tar.gz"), nil)
+	require.NoError(err)
+	require.Equal(digest, touched)
+
+	require.NoError(os.WriteFile(filepath.Join(root, "src", "task.ts"), []byte("export default { slug: 'a' }"), 0644))
+	edited, err := archiveTaskDir(root, filepath.Join(t.TempDir(), "archive.tar.gz"), nil)
+	require.NoError(err)
+	require.NotEqual(digest, edited)
+
+	t.Setenv("SOURCE_DATE_EPOCH", "not a timestamp")
+	_, err = archiveTaskDir(root, filepath.Join(t.TempDir(), "archive.tar.gz"), nil)
+	require.Error(err)
+}
+
+type upperRewriter struct{}
+
+func (upperRewriter) Matches(path string) bool {
+	return filepath.Ext(path) == ".txt"
+}
+
+func (upperRewriter) Rewrite(path string, buf []byte) ([]byte, error) {
+	if filepath.Base(path) == "keep.txt" {
+		return nil, nil
+	}
+	return bytes.ToUpper(buf), nil
+}
+
+func TestArchiveRewrite(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(root, "src"), 0755))
+	for name, content := range map[string]string{
+		"src/a.txt": "rewritten",
+		"keep.txt":  "kept",
+		"b.md":      "unmatched",
+	} {
+		require.NoError(os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "archive.tar.gz")
+	_, err := archiveTaskDir(root, archivePath, upperRewriter{})
+	require.NoError(err)
+
+	f, err := os.Open(archivePath)
+	require.NoError(err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(err)
+	files := map[string]string{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		buf, err := io.ReadAll(tr)
+		require.NoError(err)
+		files[hdr.Name] = string(buf)
+	}
+	require.Equal(map[string]string{
+		"src/a.txt": "REWRITTEN",
+		"keep.txt":  "kept",
+		"b.md":      "unmatched",
+	}, files)
+}
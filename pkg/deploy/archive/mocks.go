@@ -22,6 +22,6 @@ type MockArchiver struct {
 
 var _ Archiver = &MockArchiver{}
 
-func (d *MockArchiver) Archive(ctx context.Context, root string) (uploadID string, size int, err error) {
-	return "uploadID", 65, nil
+func (d *MockArchiver) Archive(ctx context.Context, root string) (Result, error) {
+	return Result{UploadID: "uploadID", SizeBytes: 65, Digest: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}, nil
 }
@@ -0,0 +1,113 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// normalizeArchive rewrites the tar.gz archive at src to dst with the metadata that varies between
+// machines and checkouts removed, so that archiving the same files always produces the same
+// bytes. It returns the hex-encoded SHA-256 digest of dst.
+//
+// Modification times are set to SOURCE_DATE_EPOCH, if set, or else to the Unix epoch. Owners are
+// cleared, and file modes and names are kept. The contents of regular files are kept, unless
+// rewrite returns new contents for them, in which case it has consumed the file's reader.
+func normalizeArchive(src, dst string, rewrite func(name string, r io.Reader) ([]byte, error)) (string, error) {
+	mtime, err := sourceDateEpoch()
+	if err != nil {
+		return "", err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return "", errors.Wrap(err, "opening archive")
+	}
+	defer in.Close()
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		return "", errors.Wrap(err, "reading archive")
+	}
+	defer gr.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", errors.Wrap(err, "creating archive")
+	}
+	defer out.Close()
+	h := sha256.New()
+	gw := gzip.NewWriter(io.MultiWriter(out, h))
+	tw := tar.NewWriter(gw)
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "reading archive")
+		}
+		hdr.ModTime = mtime
+		hdr.AccessTime = time.Time{}
+		hdr.ChangeTime = time.Time{}
+		hdr.Uid, hdr.Gid = 0, 0
+		hdr.Uname, hdr.Gname = "", ""
+		hdr.PAXRecords = nil
+		hdr.Format = tar.FormatUnknown
+
+		var rewritten []byte
+		if hdr.Typeflag == tar.TypeReg && rewrite != nil {
+			rewritten, err = rewrite(hdr.Name, tr)
+			if err != nil {
+				return "", err
+			}
+		}
+		if rewritten != nil {
+			hdr.Size = int64(len(rewritten))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return "", errors.Wrapf(err, "writing %s", hdr.Name)
+		}
+		var content io.Reader = tr
+		if rewritten != nil {
+			content = bytes.NewReader(rewritten)
+		}
+		if _, err := io.Copy(tw, content); err != nil {
+			return "", errors.Wrapf(err, "writing %s", hdr.Name)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", errors.Wrap(err, "writing archive")
+	}
+	if err := gw.Close(); err != nil {
+		return "", errors.Wrap(err, "writing archive")
+	}
+	if err := out.Close(); err != nil {
+		return "", errors.Wrap(err, "writing archive")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sourceDateEpoch returns the time set by SOURCE_DATE_EPOCH, see
+// https://reproducible-builds.org/specs/source-date-epoch/, or the Unix epoch if it isn't set.
+func sourceDateEpoch() (time.Time, error) {
+	v := os.Getenv("SOURCE_DATE_EPOCH")
+	if v == "" {
+		return time.Unix(0, 0), nil
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid SOURCE_DATE_EPOCH %q: expected a Unix timestamp", v)
+	}
+	return time.Unix(secs, 0), nil
+}
@@ -0,0 +1,118 @@
+package deploy
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/deploy/archive"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/provenance"
+	"github.com/airplanedev/cli/pkg/version"
+	"github.com/pkg/errors"
+)
+
+// loadSigner loads the key that bundle attestations are signed with, if --sign-key is set. Like
+// cosign, the password of encrypted keys is read from COSIGN_PASSWORD.
+func loadSigner(cfg Config) (crypto.Signer, error) {
+	if cfg.SignKey == "" {
+		if cfg.AttestationsDir != "" {
+			return nil, errors.New("--attestations-dir requires --sign-key")
+		}
+		return nil, nil
+	}
+	signer, err := provenance.LoadSigner(cfg.SignKey, []byte(os.Getenv("COSIGN_PASSWORD")))
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading %s", cfg.SignKey)
+	}
+	return signer, nil
+}
+
+// attestBundles signs the provenance of the archive of each bundle and attaches it to the
+// bundle's deployment. The attestations are also written to --attestations-dir, if it's set.
+func (d *deployer) attestBundles(
+	bundles []bundlediscover.Bundle,
+	deployBundles []api.DeployBundle,
+	archives map[string]archive.Result,
+	gitMeta api.GitMetadata,
+	started, finished time.Time,
+) error {
+	if d.cfg.AttestationsDir != "" {
+		if err := os.MkdirAll(d.cfg.AttestationsDir, 0755); err != nil {
+			return errors.Wrap(err, "creating attestations directory")
+		}
+	}
+
+	for i, b := range bundles {
+		a, ok := archives[b.RootPath]
+		if !ok || a.Digest == "" {
+			return errors.Errorf("no archive digest for %s", b.RootPath)
+		}
+		definitionsDigest, err := provenance.DefinitionsDigest(b.RootPath, b.TargetPaths)
+		if err != nil {
+			return errors.Wrapf(err, "hashing definitions of %s", b.RootPath)
+		}
+
+		opts := provenance.Options{
+			ArtifactName:   deployBundles[i].Name + ".tar.gz",
+			ArtifactDigest: a.Digest,
+			BuilderVersion: version.Get(),
+			Parameters: map[string]interface{}{
+				"buildType":    b.BuildContext.Type,
+				"buildVersion": b.BuildContext.Version,
+				"targetFiles":  b.TargetPaths,
+			},
+			DefinitionsDigest: definitionsDigest,
+			StartedOn:         started,
+			FinishedOn:        finished,
+		}
+		if gitMeta.CommitHash != "" {
+			opts.Source = &provenance.Source{
+				URI:    gitSourceURI(gitMeta),
+				Commit: gitMeta.CommitHash,
+				Path:   deployBundles[i].GitFilePath,
+				Dirty:  gitMeta.IsDirty,
+			}
+		}
+
+		envelope, err := provenance.Sign(provenance.NewStatement(opts), d.signer)
+		if err != nil {
+			return errors.Wrapf(err, "attesting %s", b.RootPath)
+		}
+		attestation := &api.Attestation{
+			Digest:      a.Digest,
+			PayloadType: envelope.PayloadType,
+			Payload:     envelope.Payload,
+		}
+		for _, sig := range envelope.Signatures {
+			attestation.Signatures = append(attestation.Signatures, api.AttestationSignature{KeyID: sig.KeyID, Sig: sig.Sig})
+		}
+		deployBundles[i].Attestation = attestation
+
+		if d.cfg.AttestationsDir != "" {
+			buf, err := json.MarshalIndent(envelope, "", "  ")
+			if err != nil {
+				return errors.Wrap(err, "marshaling attestation")
+			}
+			file := filepath.Join(d.cfg.AttestationsDir, fmt.Sprintf("%s-%s.intoto.json", deployBundles[i].Name, a.Digest[:12]))
+			if err := os.WriteFile(file, buf, 0644); err != nil {
+				return errors.Wrap(err, "writing attestation")
+			}
+			d.logger.Debug("Wrote attestation of %s to %s", b.RootPath, file)
+		}
+	}
+	return nil
+}
+
+// gitSourceURI returns the URI of the repository of a deploy's commit, or an empty string if it
+// isn't known.
+func gitSourceURI(meta api.GitMetadata) string {
+	if meta.Vendor != api.GitVendorGitHub || meta.RepositoryOwnerName == "" || meta.RepositoryName == "" {
+		return ""
+	}
+	return fmt.Sprintf("git+https://github.com/%s/%s", meta.RepositoryOwnerName, meta.RepositoryName)
+}
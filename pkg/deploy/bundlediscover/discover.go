@@ -6,8 +6,10 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 
 	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/build/ignore"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/deploy/discover"
 	"github.com/airplanedev/cli/pkg/utils/logger"
@@ -128,21 +130,32 @@ func (d *Discoverer) discoverHelper(ctx context.Context, paths ...string) ([]Bun
 			}
 			bundles = append(bundles, bundlesForFile...)
 		} else {
-			err := filepath.WalkDir(p, func(path string, entry fs.DirEntry, err error) error {
+			m, err := ignore.NewMatcherFor(p)
+			if err != nil {
+				return nil, err
+			}
+			err = filepath.WalkDir(p, func(path string, entry fs.DirEntry, err error) error {
 				if discover.IgnoredDirectories[filepath.Base(path)] {
 					return filepath.SkipDir
 				}
 				if err != nil {
 					return err
 				}
-
-				if !entry.IsDir() {
-					bundlesForFile, err := d.getBundlesForFile(ctx, path)
-					if err != nil {
-						return err
+				if path != p && m.Match(path) {
+					if entry.IsDir() {
+						return filepath.SkipDir
 					}
-					bundles = append(bundles, bundlesForFile...)
+					return nil
+				}
+
+				if entry.IsDir() {
+					return m.AddDir(path)
 				}
+				bundlesForFile, err := d.getBundlesForFile(ctx, path)
+				if err != nil {
+					return err
+				}
+				bundles = append(bundles, bundlesForFile...)
 				return nil
 			})
 			if err != nil {
@@ -229,6 +242,10 @@ func equal(b1, b2 Bundle) bool {
 				if v2.Value == nil || *v1.Value != *v2.Value {
 					return false
 				}
+			} else if v1.External != nil {
+				if v2.External == nil || *v1.External != *v2.External {
+					return false
+				}
 			}
 		}
 	}
@@ -239,5 +256,7 @@ func equalRootTypeVersionBase(b1, b2 Bundle) bool {
 	return b1.RootPath == b2.RootPath &&
 		b2.BuildContext.Type == b1.BuildContext.Type &&
 		b2.BuildContext.Version == b1.BuildContext.Version &&
-		b2.BuildContext.Base == b1.BuildContext.Base
+		b2.BuildContext.Base == b1.BuildContext.Base &&
+		reflect.DeepEqual(b1.BuildContext.Dockerfile, b2.BuildContext.Dockerfile) &&
+		slices.Equal(b1.BuildContext.Platforms, b2.BuildContext.Platforms)
 }
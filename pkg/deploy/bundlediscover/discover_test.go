@@ -417,6 +417,31 @@ func TestDiscover(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:  "multiple tasks same root diff platforms",
+			paths: []string{"./fixtures/multipleTasksSameRootDiffPlatforms"},
+			expectedBundles: []Bundle{
+				{
+					RootPath: fixturesPath,
+					TargetPaths: []string{
+						"multipleTasksSameRootDiffPlatforms/defn.sh",
+						"multipleTasksSameRootDiffPlatforms/defn.task.yaml",
+					},
+					BuildContext: buildtypes.BuildContext{
+						Type: buildtypes.ShellBuildType,
+					}},
+				{
+					RootPath: fixturesPath,
+					TargetPaths: []string{
+						"multipleTasksSameRootDiffPlatforms/defn2.sh",
+						"multipleTasksSameRootDiffPlatforms/defn2.task.yaml",
+					},
+					BuildContext: buildtypes.BuildContext{
+						Type:      buildtypes.ShellBuildType,
+						Platforms: []string{"linux/amd64", "linux/arm64"},
+					}},
+			},
+		},
 		{
 			desc:  "multiple tasks diff root",
 			paths: []string{"./fixtures/multipleTasksDiffRoot"},
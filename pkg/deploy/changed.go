@@ -0,0 +1,280 @@
+package deploy
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// sharedRootFiles are files in a bundle root that affect every entity in the bundle, e.g. because
+// they change the dependencies that are installed.
+var sharedRootFiles = map[string]bool{
+	"airplane.yaml":        true,
+	"package.json":         true,
+	"package-lock.json":    true,
+	"yarn.lock":            true,
+	"pnpm-lock.yaml":       true,
+	".npmrc":               true,
+	"tsconfig.json":        true,
+	"requirements.txt":     true,
+	"airplane_prebuild.sh": true,
+}
+
+// dependencyMode describes how to decide whether an entity is affected by changed files.
+type dependencyMode int
+
+const (
+	// dependsOnFiles entities only depend on their definition and entrypoint files, e.g. SQL tasks.
+	dependsOnFiles dependencyMode = iota
+	// dependsOnImports entities depend on the files their entrypoint transitively imports.
+	dependsOnImports
+	// dependsOnBundle entities may depend on any file in their bundle, since their imports can't
+	// be resolved statically, e.g. Python tasks.
+	dependsOnBundle
+)
+
+// entity is a task or view that may be deployed, reduced to the files it depends on.
+type entity struct {
+	slug string
+	// file is the absolute path of the file that declares the entity: its definition file or,
+	// for entities defined in code, its entrypoint.
+	file       string
+	entrypoint string
+	mode       dependencyMode
+	// buildArgs and buildSecrets are the build args and the IDs of the build secrets of tasks.
+	buildArgs    map[string]string
+	buildSecrets []string
+}
+
+func newEntities(taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig) []entity {
+	var entities []entity
+	for _, tc := range taskConfigs {
+		e := entity{
+			slug:         tc.Def.GetSlug(),
+			file:         tc.Def.GetDefnFilePath(),
+			entrypoint:   tc.TaskEntrypoint,
+			buildArgs:    tc.Def.BuildArgs,
+			buildSecrets: tc.Def.BuildSecrets,
+		}
+		if e.file == "" {
+			e.file = tc.TaskEntrypoint
+		}
+		kind, err := tc.Def.Kind()
+		switch {
+		case err != nil:
+			e.mode = dependsOnBundle
+		case kind == buildtypes.TaskKindNode:
+			e.mode = dependsOnImports
+		case kind == buildtypes.TaskKindSQL || kind == buildtypes.TaskKindREST || kind == buildtypes.TaskKindBuiltin:
+			e.mode = dependsOnFiles
+		default:
+			e.mode = dependsOnBundle
+		}
+		entities = append(entities, e)
+	}
+	for _, vc := range viewConfigs {
+		e := entity{
+			slug:       vc.Def.Slug,
+			file:       vc.Def.DefnFilePath,
+			entrypoint: vc.Def.Entrypoint,
+			mode:       dependsOnImports,
+		}
+		if !filepath.IsAbs(e.entrypoint) {
+			// Views defined in code declare themselves in their entrypoint.
+			e.entrypoint = e.file
+		}
+		entities = append(entities, e)
+	}
+	return entities
+}
+
+// gitChangedFiles returns the absolute paths of the files in the git repository containing dir
+// that changed since the merge base of ref and HEAD. Uncommitted and untracked files are included.
+func gitChangedFiles(dir, ref string) ([]string, error) {
+	top, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	top = strings.TrimSpace(top)
+	base, err := runGit(dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := runGit(top, "diff", "--name-only", strings.TrimSpace(base))
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := runGit(top, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(diff+"\n"+untracked, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, filepath.Join(top, filepath.FromSlash(line)))
+		}
+	}
+	return files, nil
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "running git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+type importsFunc func(root, entrypoint string) ([]string, error)
+
+// filterBundlesByChangedEntities restricts each bundle's target paths to the entities affected by
+// the changed files, and drops bundles with no affected entities. If a shared file in a bundle's
+// root changed, the bundle is deployed unchanged.
+func filterBundlesByChangedEntities(
+	l logger.Logger,
+	bundles []bundlediscover.Bundle,
+	entities []entity,
+	changedFiles []string,
+	imports importsFunc,
+) ([]bundlediscover.Bundle, error) {
+	changed := map[string]bool{}
+	for _, f := range changedFiles {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "calculating absolute path of file %s", f)
+		}
+		changed[abs] = true
+	}
+
+	entitiesByBundle := map[int][]entity{}
+	for _, e := range entities {
+		if i := owningBundle(bundles, e.file); i >= 0 {
+			entitiesByBundle[i] = append(entitiesByBundle[i], e)
+		}
+	}
+
+	var filtered []bundlediscover.Bundle
+	var numEntities, numAffected int
+	for i, b := range bundles {
+		numEntities += len(entitiesByBundle[i])
+		if changedShared(b.RootPath, changed) {
+			filtered = append(filtered, b)
+			numAffected += len(entitiesByBundle[i])
+			continue
+		}
+
+		var targets []string
+		for _, e := range entitiesByBundle[i] {
+			affected, err := isAffected(b.RootPath, e, changed, imports)
+			if err != nil {
+				l.Warning("Unable to determine the dependencies of %s, assuming it changed: %v", e.slug, err)
+				affected = true
+			}
+			if !affected {
+				continue
+			}
+			rel, err := filepath.Rel(b.RootPath, e.file)
+			if err != nil {
+				return nil, errors.Wrapf(err, "calculating relative path of %s", e.file)
+			}
+			targets = append(targets, filepath.ToSlash(rel))
+			numAffected++
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		sort.Strings(targets)
+		b.TargetPaths = targets
+		filtered = append(filtered, b)
+	}
+	l.Log("Deploying %d of %d entities with changes.", numAffected, numEntities)
+	return filtered, nil
+}
+
+// owningBundle returns the index of the bundle with the most specific root that contains file and
+// targets it, or -1 if there is none.
+func owningBundle(bundles []bundlediscover.Bundle, file string) int {
+	best := -1
+	for i, b := range bundles {
+		rel, ok := relativeTo(b.RootPath, file)
+		if !ok || !withinTargets(rel, b.TargetPaths) {
+			continue
+		}
+		if best < 0 || len(b.RootPath) > len(bundles[best].RootPath) {
+			best = i
+		}
+	}
+	return best
+}
+
+func withinTargets(rel string, targets []string) bool {
+	if len(targets) == 0 {
+		return true
+	}
+	for _, t := range targets {
+		t = filepath.ToSlash(filepath.Clean(t))
+		if t == "." || rel == t || strings.HasPrefix(rel, t+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeTo returns the slash-separated path of file relative to dir, if file is inside dir.
+func relativeTo(dir, file string) (string, bool) {
+	rel, err := filepath.Rel(dir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func changedShared(root string, changed map[string]bool) bool {
+	for name := range sharedRootFiles {
+		if changed[filepath.Join(root, name)] {
+			return true
+		}
+	}
+	return false
+}
+
+func isAffected(root string, e entity, changed map[string]bool, imports importsFunc) (bool, error) {
+	if changed[e.file] || (e.entrypoint != "" && changed[e.entrypoint]) {
+		return true, nil
+	}
+	switch e.mode {
+	case dependsOnImports:
+		if e.entrypoint == "" {
+			return false, nil
+		}
+		files, err := imports(root, e.entrypoint)
+		if err != nil {
+			return false, err
+		}
+		for _, f := range files {
+			if changed[f] {
+				return true, nil
+			}
+		}
+	case dependsOnBundle:
+		for f := range changed {
+			if _, ok := relativeTo(root, f); ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,77 @@
+package deploy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterBundlesByChangedEntities(t *testing.T) {
+	root := t.TempDir()
+	nodeRoot := filepath.Join(root, "node")
+	pyRoot := filepath.Join(root, "python")
+	bundles := []bundlediscover.Bundle{
+		{RootPath: nodeRoot, TargetPaths: []string{"."}},
+		{RootPath: pyRoot, TargetPaths: []string{"."}},
+	}
+	entities := []entity{
+		{slug: "a", file: filepath.Join(nodeRoot, "a.airplane.ts"), entrypoint: filepath.Join(nodeRoot, "a.airplane.ts"), mode: dependsOnImports},
+		{slug: "b", file: filepath.Join(nodeRoot, "b.task.yaml"), entrypoint: filepath.Join(nodeRoot, "b.ts"), mode: dependsOnImports},
+		{slug: "sql", file: filepath.Join(nodeRoot, "sql.task.yaml"), entrypoint: filepath.Join(nodeRoot, "query.sql"), mode: dependsOnFiles},
+		{slug: "py", file: filepath.Join(pyRoot, "py_airplane.py"), entrypoint: filepath.Join(pyRoot, "py_airplane.py"), mode: dependsOnBundle},
+	}
+	imports := func(root, entrypoint string) ([]string, error) {
+		if entrypoint == filepath.Join(nodeRoot, "b.ts") {
+			return []string{entrypoint, filepath.Join(nodeRoot, "lib", "util.ts")}, nil
+		}
+		return []string{entrypoint}, nil
+	}
+
+	for _, test := range []struct {
+		name     string
+		changed  []string
+		expected []bundlediscover.Bundle
+	}{
+		{
+			name:    "nothing changed",
+			changed: []string{filepath.Join(root, "README.md")},
+		},
+		{
+			name:    "definition file changed",
+			changed: []string{filepath.Join(nodeRoot, "sql.task.yaml")},
+			expected: []bundlediscover.Bundle{
+				{RootPath: nodeRoot, TargetPaths: []string{"sql.task.yaml"}},
+			},
+		},
+		{
+			name:    "transitive import changed",
+			changed: []string{filepath.Join(nodeRoot, "lib", "util.ts")},
+			expected: []bundlediscover.Bundle{
+				{RootPath: nodeRoot, TargetPaths: []string{"b.task.yaml"}},
+			},
+		},
+		{
+			name:    "shared root file changed",
+			changed: []string{filepath.Join(nodeRoot, "package.json")},
+			expected: []bundlediscover.Bundle{
+				{RootPath: nodeRoot, TargetPaths: []string{"."}},
+			},
+		},
+		{
+			name:    "file in python bundle changed",
+			changed: []string{filepath.Join(pyRoot, "lib", "helpers.py")},
+			expected: []bundlediscover.Bundle{
+				{RootPath: pyRoot, TargetPaths: []string{"py_airplane.py"}},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			filtered, err := filterBundlesByChangedEntities(&logger.MockLogger{}, bundles, entities, test.changed, imports)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, filtered)
+		})
+	}
+}
@@ -21,13 +21,26 @@ const (
 var schemaStr string
 
 type JavaScriptConfig struct {
-	Base           string   `yaml:"base,omitempty" json:"base,omitempty"`
-	NodeVersion    string   `yaml:"nodeVersion,omitempty" json:"nodeVersion,omitempty"`
-	EnvVars        EnvVars  `yaml:"envVars,omitempty" json:"envVars,omitempty"`
-	Install        string   `yaml:"install,omitempty" json:"install,omitempty"`
-	PreInstall     string   `yaml:"preinstall,omitempty" json:"preinstall,omitempty"`
-	PostInstall    string   `yaml:"postinstall,omitempty" json:"postinstall,omitempty"`
-	BuildPlatforms []string `yaml:"buildPlatforms,omitempty" json:"buildPlatforms,omitempty"`
+	Base           string        `yaml:"base,omitempty" json:"base,omitempty"`
+	NodeVersion    string        `yaml:"nodeVersion,omitempty" json:"nodeVersion,omitempty"`
+	EnvVars        EnvVars       `yaml:"envVars,omitempty" json:"envVars,omitempty"`
+	Install        string        `yaml:"install,omitempty" json:"install,omitempty"`
+	PreInstall     string        `yaml:"preinstall,omitempty" json:"preinstall,omitempty"`
+	PostInstall    string        `yaml:"postinstall,omitempty" json:"postinstall,omitempty"`
+	BuildPlatforms []string      `yaml:"buildPlatforms,omitempty" json:"buildPlatforms,omitempty"`
+	Esbuild        EsbuildConfig `yaml:"esbuild,omitempty" json:"esbuild,omitempty"`
+}
+
+// EsbuildConfig is the subset of esbuild options that can be passed through to the esbuild
+// invocation used to bundle JavaScript tasks and views.
+//
+// See https://esbuild.github.io/api/ for details on each option.
+type EsbuildConfig struct {
+	Define     map[string]string `yaml:"define,omitempty" json:"define,omitempty"`
+	Loader     map[string]string `yaml:"loader,omitempty" json:"loader,omitempty"`
+	JSXFactory string            `yaml:"jsxFactory,omitempty" json:"jsxFactory,omitempty"`
+	Sourcemap  string            `yaml:"sourcemap,omitempty" json:"sourcemap,omitempty"`
+	Minify     bool              `yaml:"minify,omitempty" json:"minify,omitempty"`
 }
 
 type PythonConfig struct {
@@ -43,10 +56,39 @@ type ViewConfig struct {
 	EnvVars EnvVars `yaml:"envVars,omitempty" json:"envVars,omitempty"`
 }
 
+// CLIConfig holds project-level defaults for CLI settings. Flags, env vars, and the user config
+// file all take precedence over these values.
+type CLIConfig struct {
+	Host string `yaml:"host,omitempty" json:"host,omitempty"`
+	Env  string `yaml:"env,omitempty" json:"env,omitempty"`
+}
+
+// LicensesConfig configures the dependency license check that runs on deploy.
+type LicensesConfig struct {
+	// Deny is a list of case-insensitive license globs, e.g. "GPL-*", that fail the deploy.
+	Deny []string `yaml:"deny,omitempty" json:"deny,omitempty"`
+}
+
+// ResourceAliasesConfig maps environment slugs to a map of resource aliases to resource slugs. The
+// aliases under AllEnvs apply to every environment, unless overridden by the environment's own aliases.
+type ResourceAliasesConfig map[string]map[string]string
+
+// EnvSetsConfig maps names to sets of env vars that task and view definitions can include with
+// `envFrom`.
+type EnvSetsConfig map[string]EnvVars
+
+// AllEnvs is the ResourceAliasesConfig key for aliases that apply to every environment.
+const AllEnvs = "*"
+
 type AirplaneConfig struct {
-	Javascript JavaScriptConfig `yaml:"javascript,omitempty" json:"javascript,omitempty"`
-	Python     PythonConfig     `yaml:"python,omitempty" json:"python,omitempty"`
-	View       ViewConfig       `yaml:"view,omitempty" json:"view,omitempty"`
+	Javascript      JavaScriptConfig      `yaml:"javascript,omitempty" json:"javascript,omitempty"`
+	Python          PythonConfig          `yaml:"python,omitempty" json:"python,omitempty"`
+	View            ViewConfig            `yaml:"view,omitempty" json:"view,omitempty"`
+	CLI             CLIConfig             `yaml:"cli,omitempty" json:"cli,omitempty"`
+	Licenses        LicensesConfig        `yaml:"licenses,omitempty" json:"licenses,omitempty"`
+	ResourceAliases ResourceAliasesConfig `yaml:"resourceAliases,omitempty" json:"resourceAliases,omitempty"`
+	EnvSets         EnvSetsConfig         `yaml:"envSets,omitempty" json:"envSets,omitempty"`
+	Registry        RegistryConfig        `yaml:"registry,omitempty" json:"registry,omitempty"`
 }
 
 func HasAirplaneConfig(dir string) bool {
@@ -97,8 +139,39 @@ func (c *AirplaneConfig) Unmarshal(buf []byte) error {
 		return errors.WithStack(ErrSchemaValidation{Errors: result.Errors()})
 	}
 
-	if err = json.Unmarshal(buf, &c); err != nil {
+	if err = json.Unmarshal(buf, c); err != nil {
 		return err
 	}
-	return nil
+	return c.Registry.Validate()
+}
+
+// ForEnv returns the resource aliases that apply to the given environment. If envSlug is empty,
+// only the aliases that apply to every environment are returned.
+func (c ResourceAliasesConfig) ForEnv(envSlug string) map[string]string {
+	aliases := map[string]string{}
+	for alias, slug := range c[AllEnvs] {
+		aliases[alias] = slug
+	}
+	if envSlug != "" {
+		for alias, slug := range c[envSlug] {
+			aliases[alias] = slug
+		}
+	}
+	return aliases
+}
+
+// Merge returns the env vars of the named sets, in order, so that later sets take precedence over
+// earlier ones. It returns an error if a set isn't defined.
+func (c EnvSetsConfig) Merge(names []string) (EnvVars, error) {
+	envVars := EnvVars{}
+	for _, name := range names {
+		set, ok := c[name]
+		if !ok {
+			return nil, errors.Errorf("env set %q is not defined under envSets in %s", name, FileName)
+		}
+		for k, v := range set {
+			envVars[k] = v
+		}
+	}
+	return envVars, nil
 }
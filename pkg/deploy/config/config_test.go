@@ -29,6 +29,13 @@ func TestNewAirplaneConfigFromFile(t *testing.T) {
 					Base:        "slim",
 					PreInstall:  "preinstall",
 					PostInstall: "postinstall",
+					Esbuild: EsbuildConfig{
+						Define:     map[string]string{"process.env.FOO": `"bar"`},
+						Loader:     map[string]string{".svg": "text"},
+						JSXFactory: "jsx",
+						Sourcemap:  "inline",
+						Minify:     true,
+					},
 				},
 				Python: PythonConfig{
 					EnvVars: EnvVars{
@@ -46,6 +53,21 @@ func TestNewAirplaneConfigFromFile(t *testing.T) {
 						"fromValue": EnvVarValue{Value: pointers.String("viewValue")},
 					},
 				},
+				ResourceAliases: ResourceAliasesConfig{
+					"*":    {"db": "dev_db"},
+					"prod": {"db": "prod_db"},
+				},
+				EnvSets: EnvSetsConfig{
+					"observability": {
+						"OTEL_EXPORTER_OTLP_ENDPOINT": EnvVarValue{Value: pointers.String("https://otel.example.com")},
+						"OTEL_API_KEY":                EnvVarValue{Config: pointers.String("OTEL_API_KEY")},
+					},
+				},
+				Registry: RegistryConfig{
+					Image:             "us-docker.pkg.dev/acme/airplane/{{task.slug}}:{{deployment.id}}",
+					Mode:              RegistryModeExclusive,
+					CredentialsConfig: "REGISTRY_CREDENTIALS",
+				},
 			},
 		},
 	}
@@ -60,3 +82,57 @@ func TestNewAirplaneConfigFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceAliasesForEnv(t *testing.T) {
+	require := require.New(t)
+	c := ResourceAliasesConfig{
+		AllEnvs: {"db": "dev_db", "api": "api"},
+		"prod":  {"db": "prod_db"},
+	}
+
+	require.Equal(map[string]string{"db": "prod_db", "api": "api"}, c.ForEnv("prod"))
+	require.Equal(map[string]string{"db": "dev_db", "api": "api"}, c.ForEnv("staging"))
+	require.Equal(map[string]string{"db": "dev_db", "api": "api"}, c.ForEnv(""))
+	require.Empty(ResourceAliasesConfig(nil).ForEnv("prod"))
+}
+
+func TestEnvSetsMerge(t *testing.T) {
+	require := require.New(t)
+	c := EnvSetsConfig{
+		"observability": {
+			"OTEL_EXPORTER": EnvVarValue{Value: pointers.String("otlp")},
+			"SERVICE_NS":    EnvVarValue{Value: pointers.String("default")},
+		},
+		"payments": {
+			"SERVICE_NS": EnvVarValue{Value: pointers.String("payments")},
+		},
+	}
+
+	envVars, err := c.Merge([]string{"observability", "payments"})
+	require.NoError(err)
+	require.Equal(EnvVars{
+		"OTEL_EXPORTER": EnvVarValue{Value: pointers.String("otlp")},
+		"SERVICE_NS":    EnvVarValue{Value: pointers.String("payments")},
+	}, envVars)
+
+	_, err = c.Merge([]string{"observability", "missing"})
+	require.ErrorContains(err, `env set "missing" is not defined`)
+}
+
+func TestRegistryImageName(t *testing.T) {
+	require := require.New(t)
+
+	c := RegistryConfig{Image: "ghcr.io/acme/{{task.slug}}:{{ deployment.id }}"}
+	name, err := c.ImageName(map[string]string{"task.slug": "my_task", "deployment.id": "dep123"})
+	require.NoError(err)
+	require.Equal("ghcr.io/acme/my_task:dep123", name)
+	require.NoError(c.Validate())
+	require.Equal(RegistryModeAdditional, c.ModeOrDefault())
+
+	_, err = RegistryConfig{Image: "ghcr.io/acme/{{name}}:{{slug}}"}.ImageName(ImageTemplateVars)
+	require.ErrorContains(err, "unknown variables: name, slug")
+
+	require.ErrorContains(RegistryConfig{Image: "ghcr.io/acme/{{task.slug}}:v1:v2"}.Validate(), "not a valid image name")
+	require.ErrorContains(RegistryConfig{Mode: RegistryModeExclusive}.Validate(), "registry.image is required")
+	require.NoError(RegistryConfig{}.Validate())
+}
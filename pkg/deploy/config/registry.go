@@ -0,0 +1,96 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/utils/templates"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+)
+
+// RegistryMode controls whether images are pushed to the managed registry in addition to the
+// registry configured by RegistryConfig.
+type RegistryMode string
+
+const (
+	// RegistryModeAdditional pushes images to both the managed registry and the external registry.
+	// Tasks run the image in the managed registry.
+	RegistryModeAdditional RegistryMode = "additional"
+	// RegistryModeExclusive only pushes images to the external registry. Tasks run the image in the
+	// external registry, so agents must be able to pull from it.
+	RegistryModeExclusive RegistryMode = "exclusive"
+)
+
+// RegistryConfig configures a customer-owned registry that deploys push built task images to.
+type RegistryConfig struct {
+	// Image is a template for the name of pushed images, f.e.
+	// "us-docker.pkg.dev/acme/airplane/{{task.slug}}:{{git.shortSHA}}". See ImageTemplateVars.
+	Image string       `yaml:"image,omitempty" json:"image,omitempty"`
+	Mode  RegistryMode `yaml:"mode,omitempty" json:"mode,omitempty"`
+	// CredentialsConfig is the name of a config variable holding the credentials used to push to
+	// the registry, as "<username>:<password>". If empty, the registry must accept pushes from
+	// Airplane's builders without credentials.
+	CredentialsConfig string `yaml:"credentialsConfig,omitempty" json:"credentialsConfig,omitempty"`
+}
+
+// ImageTemplateVars are the variables that RegistryConfig.Image may reference, with example
+// values. They use the same syntax as the templated images of image tasks, and the git variables
+// are the template variables of the deployment. Variables are rendered by the builder once the
+// task's image has been built.
+var ImageTemplateVars = map[string]string{
+	"task.slug":     "my_task",
+	"task.id":       "tsk20230101abcdef",
+	"deployment.id": "dep20230101abcdef",
+	"build.id":      "bld20230101abcdef",
+	"env.slug":      "prod",
+	"git.sha":       "0123456789abcdef0123456789abcdef01234567",
+	"git.shortSHA":  "0123456",
+	"git.ref":       "main",
+}
+
+// IsSet returns true if an external registry is configured.
+func (c RegistryConfig) IsSet() bool {
+	return c.Image != ""
+}
+
+// ModeOrDefault returns the registry mode, which defaults to RegistryModeAdditional.
+func (c RegistryConfig) ModeOrDefault() RegistryMode {
+	if c.Mode == "" {
+		return RegistryModeAdditional
+	}
+	return c.Mode
+}
+
+// ImageName renders the image name template with vars. It returns an error if the template
+// references a variable that isn't set, or if the result isn't a valid image reference.
+func (c RegistryConfig) ImageName(vars map[string]string) (string, error) {
+	var missing []string
+	name, _ := templates.Expand(c.Image, func(key string) (string, error) {
+		v, ok := vars[key]
+		if !ok {
+			missing = append(missing, key)
+		}
+		return v, nil
+	})
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", errors.Errorf("registry image %q references unknown variables: %s", c.Image, strings.Join(missing, ", "))
+	}
+	if _, err := reference.ParseNormalizedNamed(name); err != nil {
+		return "", errors.Wrapf(err, "registry image %q is not a valid image name", c.Image)
+	}
+	return name, nil
+}
+
+// Validate checks that the image name template renders to a valid image name.
+func (c RegistryConfig) Validate() error {
+	if !c.IsSet() {
+		if c.Mode != "" || c.CredentialsConfig != "" {
+			return errors.New("registry.image is required when configuring a registry")
+		}
+		return nil
+	}
+	_, err := c.ImageName(ImageTemplateVars)
+	return err
+}
@@ -13,6 +13,9 @@ type EnvVars map[string]EnvVarValue
 type EnvVarValue struct {
 	Value  *string `json:"value,omitempty" yaml:"value,omitempty"`
 	Config *string `json:"config,omitempty" yaml:"config,omitempty"`
+	// External references a secret stored outside of Airplane, e.g. `vault:secret/data/foo#key`.
+	// See the secrets package for the supported providers.
+	External *string `json:"external,omitempty" yaml:"external,omitempty"`
 }
 
 var _ yaml.Unmarshaler = &EnvVarValue{}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/goccy/go-yaml"
+	"github.com/pkg/errors"
+)
+
+const (
+	WorkspaceFileName = "airplane.workspace.yaml"
+)
+
+// WorkspaceConfig configures a workspace: a directory, e.g. a monorepo, whose tasks and views are
+// spread across several roots.
+type WorkspaceConfig struct {
+	// Members are the roots of the workspace's tasks and views, relative to the workspace file.
+	// Globs are supported, e.g. "services/*".
+	Members []string `yaml:"members" json:"members"`
+	// EnvFiles are .env files, relative to the workspace file, that are loaded into every local
+	// run of the workspace's tasks. The .env files of each task take precedence over these.
+	EnvFiles []string `yaml:"envFiles,omitempty" json:"envFiles,omitempty"`
+
+	// Build settings that are shared by every member. Settings in a member's airplane.yaml take
+	// precedence over these.
+	Javascript JavaScriptConfig `yaml:"javascript,omitempty" json:"javascript,omitempty"`
+	Python     PythonConfig     `yaml:"python,omitempty" json:"python,omitempty"`
+	View       ViewConfig       `yaml:"view,omitempty" json:"view,omitempty"`
+}
+
+// Workspace is a workspace file that has been loaded from Dir.
+type Workspace struct {
+	WorkspaceConfig
+	// Dir is the absolute path of the directory that contains the workspace file.
+	Dir string
+}
+
+// FindWorkspace finds the workspace file in dir or the closest of its parents, if any.
+func FindWorkspace(dir string) (Workspace, bool, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Workspace{}, false, errors.Wrap(err, "getting absolute path")
+	}
+	wsDir, ok := fsx.Find(abs, WorkspaceFileName)
+	if !ok {
+		return Workspace{}, false, nil
+	}
+	ws, err := NewWorkspaceFromFile(filepath.Join(wsDir, WorkspaceFileName))
+	if err != nil {
+		return Workspace{}, false, err
+	}
+	return ws, true, nil
+}
+
+// NewWorkspaceFromFile loads the workspace file at file.
+func NewWorkspaceFromFile(file string) (Workspace, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return Workspace{}, errors.Wrap(err, "getting absolute path")
+	}
+	buf, err := os.ReadFile(abs)
+	if err != nil {
+		return Workspace{}, errors.Wrap(err, "reading workspace file")
+	}
+	ws := Workspace{Dir: filepath.Dir(abs)}
+	if err := ws.WorkspaceConfig.Unmarshal(buf); err != nil {
+		return Workspace{}, errors.Wrapf(err, "parsing %s", abs)
+	}
+	return ws, nil
+}
+
+func (c *WorkspaceConfig) Unmarshal(buf []byte) error {
+	buf, err := yaml.YAMLToJSON(buf)
+	if err != nil {
+		return err
+	}
+
+	var raw struct {
+		Members    []string        `json:"members"`
+		EnvFiles   []string        `json:"envFiles"`
+		Javascript json.RawMessage `json:"javascript"`
+		Python     json.RawMessage `json:"python"`
+		View       json.RawMessage `json:"view"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return errors.Wrap(err, "decoding workspace file")
+	}
+	if len(raw.Members) == 0 {
+		return errors.New("members must list at least one task root")
+	}
+
+	// Shared build settings are validated like the settings of an airplane.yaml.
+	shared := map[string]json.RawMessage{}
+	for k, v := range map[string]json.RawMessage{"javascript": raw.Javascript, "python": raw.Python, "view": raw.View} {
+		if len(v) > 0 {
+			shared[k] = v
+		}
+	}
+	sharedBuf, err := json.Marshal(shared)
+	if err != nil {
+		return errors.Wrap(err, "marshaling build settings")
+	}
+	var ac AirplaneConfig
+	if err := ac.Unmarshal(sharedBuf); err != nil {
+		return err
+	}
+
+	*c = WorkspaceConfig{
+		Members:    raw.Members,
+		EnvFiles:   raw.EnvFiles,
+		Javascript: ac.Javascript,
+		Python:     ac.Python,
+		View:       ac.View,
+	}
+	return nil
+}
+
+// MemberPaths returns the absolute paths of the workspace's members, sorted. Members that are
+// globs may match no directories, but other members must exist.
+func (w Workspace) MemberPaths() ([]string, error) {
+	seen := map[string]bool{}
+	var paths []string
+	for _, m := range w.Members {
+		pattern := filepath.Join(w.Dir, filepath.FromSlash(m))
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid member %q", m)
+		}
+		if len(matches) == 0 && !strings.ContainsAny(m, "*?[") {
+			return nil, errors.Errorf("member %s of workspace %s does not exist", m, w.Dir)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || !info.IsDir() || seen[match] {
+				continue
+			}
+			seen[match] = true
+			paths = append(paths, match)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Contains returns true if dir is, or is inside, one of the workspace's members.
+func (w Workspace) Contains(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(w.Dir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for _, m := range w.Members {
+		m = filepath.ToSlash(filepath.Clean(m))
+		if m == "." {
+			return true
+		}
+		pattern := strings.Split(m, "/")
+		if len(pattern) > len(parts) {
+			continue
+		}
+		matched := true
+		for i, p := range pattern {
+			if ok, err := filepath.Match(p, parts[i]); err != nil || !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvFilePaths returns the absolute paths of the workspace's env files that exist.
+func (w Workspace) EnvFilePaths() []string {
+	var paths []string
+	for _, f := range w.EnvFiles {
+		p := filepath.Join(w.Dir, filepath.FromSlash(f))
+		if fsx.Exists(p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// apply returns c with the workspace's shared build settings as defaults.
+func (w Workspace) apply(c AirplaneConfig) (AirplaneConfig, error) {
+	base, err := toMap(AirplaneConfig{Javascript: w.Javascript, Python: w.Python, View: w.View})
+	if err != nil {
+		return AirplaneConfig{}, err
+	}
+	override, err := toMap(c)
+	if err != nil {
+		return AirplaneConfig{}, err
+	}
+	buf, err := json.Marshal(mergeMaps(base, override))
+	if err != nil {
+		return AirplaneConfig{}, errors.Wrap(err, "marshaling merged config")
+	}
+	var merged AirplaneConfig
+	if err := json.Unmarshal(buf, &merged); err != nil {
+		return AirplaneConfig{}, errors.Wrap(err, "unmarshaling merged config")
+	}
+	return merged, nil
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling config")
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling config")
+	}
+	return m, nil
+}
+
+// mergeMaps deeply merges override into base. Values in override take precedence, except that
+// nested objects are merged.
+func mergeMaps(base, override map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		bm, bok := out[k].(map[string]interface{})
+		om, ook := v.(map[string]interface{})
+		if bok && ook {
+			out[k] = mergeMaps(bm, om)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// LoadAirplaneConfig loads the airplane.yaml in dir, with the shared build settings of the
+// workspace that dir is a member of, if any, as defaults. It returns false if dir has neither.
+func LoadAirplaneConfig(dir string) (AirplaneConfig, bool, error) {
+	var c AirplaneConfig
+	hasConfig := HasAirplaneConfig(dir)
+	if hasConfig {
+		var err error
+		if c, err = NewAirplaneConfigFromFile(dir); err != nil {
+			return AirplaneConfig{}, false, err
+		}
+	}
+
+	ws, ok, err := FindWorkspace(dir)
+	if err != nil {
+		return AirplaneConfig{}, false, err
+	}
+	if !ok || !ws.Contains(dir) {
+		return c, hasConfig, nil
+	}
+	c, err = ws.apply(c)
+	if err != nil {
+		return AirplaneConfig{}, false, errors.Wrapf(err, "applying settings of workspace %s", ws.Dir)
+	}
+	return c, true, nil
+}
+
+// WorkspaceMemberPaths returns the paths of the members of the workspace defined in dir, if there
+// is one. Unlike FindWorkspace, parents of dir aren't searched, so that running a command in a
+// member only affects that member.
+func WorkspaceMemberPaths(dir string) ([]string, bool, error) {
+	if !fsx.Exists(filepath.Join(dir, WorkspaceFileName)) {
+		return nil, false, nil
+	}
+	ws, err := NewWorkspaceFromFile(filepath.Join(dir, WorkspaceFileName))
+	if err != nil {
+		return nil, false, err
+	}
+	paths, err := ws.MemberPaths()
+	if err != nil {
+		return nil, false, err
+	}
+	return paths, true, nil
+}
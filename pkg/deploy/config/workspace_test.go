@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		WorkspaceFileName: `
+members:
+  - services/*
+  - tools
+envFiles:
+  - shared.env
+  - missing.env
+javascript:
+  nodeVersion: "18"
+  envVars:
+    SHARED:
+      value: shared
+    OVERRIDDEN:
+      value: workspace
+python:
+  version: "3.11"
+`,
+		"shared.env":                      "FOO=bar\n",
+		"services/api/airplane.yaml":      "javascript:\n  envVars:\n    OVERRIDDEN:\n      value: member\n",
+		"services/web/index.airplane.ts":  "",
+		"services/README.md":              "",
+		"tools/airplane.yaml":             "python:\n  version: \"3.10\"\n",
+		"other/airplane.yaml":             "javascript:\n  nodeVersion: \"16\"\n",
+		"services/api/nested/task.sql":    "",
+		"services/web/nested/airplane.py": "",
+	})
+
+	ws, ok, err := FindWorkspace(filepath.Join(dir, "services", "api", "nested"))
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(dir, ws.Dir)
+
+	paths, err := ws.MemberPaths()
+	require.NoError(err)
+	require.Equal([]string{
+		filepath.Join(dir, "services", "api"),
+		filepath.Join(dir, "services", "web"),
+		filepath.Join(dir, "tools"),
+	}, paths)
+
+	require.True(ws.Contains(filepath.Join(dir, "services", "api")))
+	require.True(ws.Contains(filepath.Join(dir, "services", "web", "nested")))
+	require.True(ws.Contains(filepath.Join(dir, "tools")))
+	require.False(ws.Contains(filepath.Join(dir, "services")))
+	require.False(ws.Contains(filepath.Join(dir, "other")))
+	require.False(ws.Contains(filepath.Dir(dir)))
+
+	require.Equal([]string{filepath.Join(dir, "shared.env")}, ws.EnvFilePaths())
+
+	// Members inherit the shared settings, and their own settings take precedence.
+	c, ok, err := LoadAirplaneConfig(filepath.Join(dir, "services", "api"))
+	require.NoError(err)
+	require.True(ok)
+	require.Equal("18", c.Javascript.NodeVersion)
+	require.Equal("shared", *c.Javascript.EnvVars["SHARED"].Value)
+	require.Equal("member", *c.Javascript.EnvVars["OVERRIDDEN"].Value)
+	require.Equal("3.11", c.Python.Version)
+
+	c, ok, err = LoadAirplaneConfig(filepath.Join(dir, "tools"))
+	require.NoError(err)
+	require.True(ok)
+	require.Equal("3.10", c.Python.Version)
+
+	// Members without an airplane.yaml still get the shared settings.
+	c, ok, err = LoadAirplaneConfig(filepath.Join(dir, "services", "web"))
+	require.NoError(err)
+	require.True(ok)
+	require.Equal("18", c.Javascript.NodeVersion)
+
+	// Directories that aren't members are unaffected.
+	c, ok, err = LoadAirplaneConfig(filepath.Join(dir, "other"))
+	require.NoError(err)
+	require.True(ok)
+	require.Equal("16", c.Javascript.NodeVersion)
+	require.Empty(c.Python.Version)
+
+	memberPaths, ok, err := WorkspaceMemberPaths(dir)
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(paths, memberPaths)
+
+	// Only the directory itself is checked for a workspace file.
+	_, ok, err = WorkspaceMemberPaths(filepath.Join(dir, "tools"))
+	require.NoError(err)
+	require.False(ok)
+}
+
+func TestWorkspaceConfigUnmarshal(t *testing.T) {
+	for _, tc := range []struct {
+		desc string
+		buf  string
+		err  string
+	}{
+		{desc: "missing members", buf: "envFiles: [.env]", err: "members must list at least one task root"},
+		{desc: "unknown field", buf: "members: [.]\nfoo: bar", err: "unknown field"},
+		{desc: "invalid build settings", buf: "members: [.]\njavascript:\n  foo: bar", err: "foo"},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var c WorkspaceConfig
+			err := c.Unmarshal([]byte(tc.buf))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestWorkspaceMissingMember(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		WorkspaceFileName: "members:\n  - missing\n  - globs/*\n",
+	})
+
+	ws, err := NewWorkspaceFromFile(filepath.Join(dir, WorkspaceFileName))
+	require.NoError(err)
+	_, err = ws.MemberPaths()
+	require.Error(err)
+	require.Contains(err.Error(), "member missing")
+}
@@ -0,0 +1,320 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/build/clibuild"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/cli/pkg/version/skew"
+	"github.com/pkg/errors"
+)
+
+type Config struct {
+	Root                 *cli.Config
+	Client               api.APIClient
+	Paths                []string
+	ChangedFiles         utils.NewlineFileValue
+	ChangedSince         string
+	EnvSlug              string
+	PinIDs               bool
+	EventsFD             int
+	EventsFile           string
+	DiscoveryConcurrency int
+	CacheFrom            []string
+	CacheTo              string
+	Plan                 bool
+	Graph                bool
+	DryRun               bool
+	BuildConcurrency     int
+	FailFast             bool
+	Timings              bool
+	BuildArgs            map[string]string
+	BuildSecrets         map[string]string
+	OfflineCache         string
+	SignKey              string
+	AttestationsDir      string
+	StrictVersions       bool
+	// DiscoverInline also discovers the tasks and views that are configured inline in code before
+	// deploying, so that they're checked too. See discoverConfigs.
+	DiscoverInline bool
+	// Logger is where the progress of the deploy is logged. Defaults to stderr.
+	Logger logger.LoggerWithLoader
+	// AssumeYes and AssumeNo answer the questions that deploys ask, e.g. whether to deploy to the
+	// default environment. If neither is set, Root.Prompter is asked.
+	AssumeYes bool
+	AssumeNo  bool
+}
+
+// Deploy discovers, builds and deploys the tasks and views in cfg.Paths, as `airplane deploy` does.
+func Deploy(ctx context.Context, cfg Config) (rerr error) {
+	l := cfg.Logger
+	if l == nil {
+		l = logger.NewStdErrLogger(logger.StdErrLoggerOpts{WithLoader: true})
+	}
+	defer l.StopLoader()
+
+	events, err := newEventWriter(cfg.EventsFD, cfg.EventsFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rerr != nil {
+			events.emit(Event{Type: EventFailed, Error: rerr.Error()})
+		}
+		if err := events.Close(); err != nil {
+			l.Debug("closing events: %v", err)
+		}
+	}()
+
+	if cfg.ChangedSince != "" && len(cfg.ChangedFiles) > 0 {
+		return errors.New("only one of --changed-files and --changed-since may be set")
+	}
+	if cfg.Plan && (cfg.ChangedSince != "" || len(cfg.ChangedFiles) > 0) {
+		return errors.New("--plan can't be combined with --changed-files or --changed-since")
+	}
+	if cfg.DryRun && cfg.Plan {
+		return errors.New("only one of --dry-run and --plan may be set")
+	}
+	if !cfg.DryRun && (cfg.BuildConcurrency > 1 || cfg.FailFast || cfg.Timings || len(cfg.BuildArgs) > 0 || len(cfg.BuildSecrets) > 0 || cfg.OfflineCache != "") {
+		// Deployed images are built by Airplane, so these only configure local builds.
+		return errors.New("--build-concurrency, --fail-fast, --timings, --build-arg, --build-secret and --offline-cache require --dry-run")
+	}
+	if cfg.DryRun && cfg.BuildConcurrency < 1 {
+		return errors.New("--build-concurrency must be at least 1")
+	}
+	// Load the signing key up front, so that a wrong password fails before anything is uploaded.
+	signer, err := loadSigner(cfg)
+	if err != nil {
+		return err
+	}
+
+	d := build.BundleDiscoverer(cfg.Client, l, cfg.EnvSlug)
+	bundles, err := d.Discover(ctx, cfg.Paths...)
+	if err != nil {
+		return err
+	}
+	if err := checkSDKVersions(cfg, l, bundles); err != nil {
+		return err
+	}
+
+	taskConfigs, viewConfigs, err := discoverConfigs(ctx, cfg, l)
+	if err != nil {
+		return err
+	}
+	if err := validateRunAs(ctx, cfg, taskConfigs); err != nil {
+		return err
+	}
+	if err := rejectExternalSecrets(taskConfigs, viewConfigs, bundles); err != nil {
+		return err
+	}
+	if err := rejectEnvVarExpressions(taskConfigs, viewConfigs); err != nil {
+		return err
+	}
+	if err := rejectEnvFiles(taskConfigs); err != nil {
+		return err
+	}
+	if err := validateImageTemplates(taskConfigs, &FileGitRepoGetter{}); err != nil {
+		return err
+	}
+	// Fail the deploy, rather than the view, if a view links to a task that doesn't exist or
+	// doesn't accept the parameters that the view passes it.
+	if _, err := discover.ResolveViewLinks(ctx, cfg.Client, cfg.EnvSlug, taskConfigs, viewConfigs); err != nil {
+		return err
+	}
+	warnUnhealthyAgentPools(ctx, cfg, l, taskConfigs)
+
+	graph, err := discover.NewTaskGraph(taskConfigs)
+	if err != nil {
+		return err
+	}
+	if cfg.Graph {
+		printGraph(l, graph)
+		return nil
+	}
+	// Calls are found by scanning source code, so a cycle can be a false positive, and tasks that
+	// do call each other still deploy: only their order can't be guaranteed.
+	var ranks func([]bundlediscover.Bundle) []int
+	if levels, err := graph.Levels(); err != nil {
+		l.Warning("%v. These tasks may be deployed before the tasks they call.", err)
+	} else {
+		ranks = func(bundles []bundlediscover.Bundle) []int {
+			return bundleRanks(bundles, taskConfigs, levels)
+		}
+	}
+
+	if cfg.Plan {
+		entries, err := Plan(ctx, cfg, taskConfigs, viewConfigs)
+		if err != nil {
+			return err
+		}
+		return printPlan(l, entries)
+	}
+
+	if cfg.ChangedSince != "" {
+		bundles, err = filterBundlesChangedSince(cfg, l, bundles, taskConfigs, viewConfigs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.DryRun {
+		return NewDeployer(cfg, l, DeployerOpts{Events: events}).DryRun(ctx, bundles, newEntities(taskConfigs, viewConfigs))
+	}
+	return NewDeployer(cfg, l, DeployerOpts{Events: events, Signer: signer, BundleRanks: ranks}).Deploy(ctx, bundles)
+}
+
+// checkSDKVersions warns if the bundles depend on versions of an SDK that the builders of this CLI
+// don't support, since those fail in confusing ways once they're built.
+func checkSDKVersions(cfg Config, l logger.Logger, bundles []bundlediscover.Bundle) error {
+	roots := map[string]buildtypes.BuildType{}
+	for _, b := range bundles {
+		roots[b.RootPath] = b.BuildContext.Type
+	}
+	warnings := skew.CheckSDKs(skew.FindSDKVersions(roots))
+	return skew.Report(l, warnings, cfg.StrictVersions)
+}
+
+// discoverInline reports whether discoverConfigs should discover tasks and views configured inline
+// in code. That runs their code locally with esbuild, Node, Python or Deno, so it's only done when
+// asked for, or when the result depends on every entity: plans, graphs, dry runs and deploys of
+// the entities changed since a ref.
+func discoverInline(cfg Config) bool {
+	return cfg.DiscoverInline || cfg.Plan || cfg.Graph || cfg.DryRun || cfg.ChangedSince != ""
+}
+
+// discoverConfigs discovers the tasks and views being deployed so that their definitions can be
+// validated before anything is uploaded. Task definitions have the overrides of cfg.EnvSlug applied.
+//
+// This is the only discovery pass before bundles are deployed: every check shares its results.
+// Unless discoverInline, only tasks and views in definition files are discovered, so the checks
+// don't apply to entities configured inline in code.
+func discoverConfigs(ctx context.Context, cfg Config, l logger.Logger) ([]discover.TaskConfig, []discover.ViewConfig, error) {
+	discoverer := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:                  cfg.Client,
+				Logger:                  l,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+		},
+		ViewDiscoverers: []discover.ViewDiscoverer{
+			&discover.ViewDefnDiscoverer{
+				Client:                  cfg.Client,
+				Logger:                  l,
+				DoNotVerifyMissingViews: true,
+			},
+		},
+		Client:      cfg.Client,
+		Logger:      l,
+		EnvSlug:     cfg.EnvSlug,
+		Concurrency: cfg.DiscoveryConcurrency,
+	}
+	if discoverInline(cfg) {
+		// Checks that silently skip the entities they couldn't discover would pass, so a file that
+		// can't be parsed fails the deploy.
+		discoverer.TaskDiscoverers = append(discoverer.TaskDiscoverers, &discover.CodeTaskDiscoverer{
+			Client:                  cfg.Client,
+			Logger:                  l,
+			DoNotVerifyMissingTasks: true,
+			EnvSlug:                 cfg.EnvSlug,
+			FailOnParseError:        true,
+		})
+		discoverer.ViewDiscoverers = append(discoverer.ViewDiscoverers, &discover.CodeViewDiscoverer{
+			Client:                  cfg.Client,
+			Logger:                  l,
+			DoNotVerifyMissingViews: true,
+			FailOnParseError:        true,
+		})
+	}
+	taskConfigs, viewConfigs, err := discoverer.Discover(ctx, cfg.Paths...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "discovering tasks and views")
+	}
+	// Validate the tasks as they'll be deployed to the environment.
+	for i, tc := range taskConfigs {
+		taskConfigs[i].Def, err = tc.Def.ForEnv(cfg.EnvSlug)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "applying environment overrides of task %s", tc.Def.GetSlug())
+		}
+	}
+	return taskConfigs, viewConfigs, nil
+}
+
+// filterBundlesChangedSince restricts bundles to the entities affected by files changed since
+// cfg.ChangedSince.
+func filterBundlesChangedSince(cfg Config, l logger.Logger, bundles []bundlediscover.Bundle, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig) ([]bundlediscover.Bundle, error) {
+	dir := cfg.Paths[0]
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	changedFiles, err := gitChangedFiles(dir, cfg.ChangedSince)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing files changed since %s", cfg.ChangedSince)
+	}
+	return filterBundlesByChangedEntities(l, bundles, newEntities(taskConfigs, viewConfigs), changedFiles, discover.NodeImports)
+}
+
+// validateRunAs checks that the deployer is permitted to attach each task's service account, if it has
+// one, so that misconfigured execution identities are caught before anything is uploaded.
+func validateRunAs(ctx context.Context, cfg Config, taskConfigs []discover.TaskConfig) error {
+	for _, tc := range taskConfigs {
+		if tc.Def.RunAs == "" {
+			continue
+		}
+		resp, err := cfg.Client.ValidateRunAs(ctx, api.ValidateRunAsRequest{
+			TaskSlug:           tc.Def.GetSlug(),
+			ServiceAccountSlug: tc.Def.RunAs,
+			EnvSlug:            cfg.EnvSlug,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "validating runAs of task %s", tc.Def.GetSlug())
+		}
+		if !resp.Allowed {
+			return errors.Errorf("task %s cannot run as service account %s: %s", tc.Def.GetSlug(), tc.Def.RunAs, resp.Reason)
+		}
+	}
+	return nil
+}
+
+// warnUnhealthyAgentPools warns about tasks pinned to an agent pool that has no healthy agents, since
+// runs of those tasks would queue indefinitely. The deploy itself is not blocked, since the pool
+// may be brought up afterwards.
+func warnUnhealthyAgentPools(ctx context.Context, cfg Config, l logger.Logger, taskConfigs []discover.TaskConfig) {
+	var pinned []discover.TaskConfig
+	for _, tc := range taskConfigs {
+		if tc.Def.AgentPool != "" {
+			pinned = append(pinned, tc)
+		}
+	}
+	if len(pinned) == 0 {
+		return
+	}
+
+	resp, err := cfg.Client.ListAgentPools(ctx, api.ListAgentPoolsRequest{EnvSlug: cfg.EnvSlug})
+	if err != nil {
+		l.Warning("Unable to check the health of agent pools: %v", err)
+		return
+	}
+	healthy := map[string]int{}
+	for _, pool := range resp.Pools {
+		healthy[pool.Name] = pool.HealthyAgents
+	}
+	for _, tc := range pinned {
+		count, ok := healthy[tc.Def.AgentPool]
+		switch {
+		case !ok:
+			l.Warning("Task %s is pinned to agent pool %s, which does not exist.", tc.Def.GetSlug(), tc.Def.AgentPool)
+		case count == 0:
+			l.Warning("Task %s is pinned to agent pool %s, which has no healthy agents. Runs will be queued until an agent is available.", tc.Def.GetSlug(), tc.Def.AgentPool)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverConfigs(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "my_task.task.yaml"), []byte(`slug: my_task
+python:
+  entrypoint: main.py
+timeout: 600
+environments:
+  staging:
+    timeout: 60
+`), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "main.py"), []byte("print('hello')\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "my_view.view.yaml"), []byte("slug: my_view\nname: My view\nentrypoint: view.tsx\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "view.tsx"), []byte("export default () => null;\n"), 0644))
+
+	cfg := Config{Client: &api.MockClient{}, Paths: []string{dir}, EnvSlug: "staging"}
+	taskConfigs, viewConfigs, err := discoverConfigs(ctx, cfg, &logger.MockLogger{})
+	require.NoError(err)
+
+	// Tasks and views are discovered together, and tasks have their environment's overrides applied.
+	require.Len(taskConfigs, 1)
+	require.Equal("my_task", taskConfigs[0].Def.GetSlug())
+	require.Equal(60, taskConfigs[0].Def.Timeout)
+	require.Nil(taskConfigs[0].Def.Environments)
+	require.Len(viewConfigs, 1)
+	require.Equal("my_view", viewConfigs[0].Def.Slug)
+}
+
+func TestDiscoverConfigsInline(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "my_task.task.yaml"), []byte("slug: my_task\npython:\n  entrypoint: main.py\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "main.py"), []byte("print('hello')\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "broken_airplane.py"), []byte("def (\n"), 0644))
+
+	// Code isn't run to discover inline configured tasks unless asked to.
+	cfg := Config{Client: &api.MockClient{}, Paths: []string{dir}}
+	taskConfigs, _, err := discoverConfigs(ctx, cfg, &logger.MockLogger{})
+	require.NoError(err)
+	require.Len(taskConfigs, 1)
+	require.Equal("my_task", taskConfigs[0].Def.GetSlug())
+
+	// When it is, a file that can't be parsed fails discovery instead of being skipped.
+	for _, cfg := range []Config{
+		{Client: &api.MockClient{}, Paths: []string{dir}, DiscoverInline: true},
+		{Client: &api.MockClient{}, Paths: []string{dir}, Plan: true},
+	} {
+		_, _, err = discoverConfigs(ctx, cfg, &logger.MockLogger{})
+		require.Error(err)
+		require.Contains(err.Error(), "discovering inline configured tasks in "+filepath.Join(dir, "broken_airplane.py"))
+	}
+}
+
+func TestValidateRunAs(t *testing.T) {
+	ctx := context.Background()
+	taskConfig := func(slug, runAs string) discover.TaskConfig {
+		return discover.TaskConfig{Def: definitions.Definition{Slug: slug, RunAs: runAs}}
+	}
+	cfg := Config{Client: &api.MockClient{ServiceAccounts: []string{"ci_bot"}}}
+
+	for _, tC := range []struct {
+		desc        string
+		taskConfigs []discover.TaskConfig
+		err         string
+	}{
+		{
+			desc:        "no runAs",
+			taskConfigs: []discover.TaskConfig{taskConfig("my_task", "")},
+		},
+		{
+			desc:        "allowed",
+			taskConfigs: []discover.TaskConfig{taskConfig("my_task", "ci_bot")},
+		},
+		{
+			desc:        "not allowed",
+			taskConfigs: []discover.TaskConfig{taskConfig("my_task", "ci_bot"), taskConfig("other_task", "admin_bot")},
+			err:         "task other_task cannot run as service account admin_bot: service account not found",
+		},
+	} {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := validateRunAs(ctx, cfg, tC.taskConfigs)
+			if tC.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tC.err)
+			}
+		})
+	}
+}
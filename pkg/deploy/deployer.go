@@ -0,0 +1,708 @@
+package deploy
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/analytics"
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/build"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/definitions/updaters"
+	"github.com/airplanedev/cli/pkg/deploy/archive"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/config"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/deploy/licenses"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/dustin/go-humanize"
+	"github.com/go-git/go-git/v5"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+type deployer struct {
+	cfg Config
+	// signer signs the provenance of each bundle's archive, if set.
+	signer     crypto.Signer
+	logger     logger.LoggerWithLoader
+	archiver   archive.Archiver
+	repoGetter GitRepoGetter
+	events     *eventWriter
+	// buildBundle builds bundles locally for dry runs.
+	buildBundle BundleBuildFunc
+	// bundleRanks ranks the bundles being deployed, if set. See DeployerOpts.BundleRanks.
+	bundleRanks func([]bundlediscover.Bundle) []int
+}
+
+type DeployerOpts struct {
+	Archiver   archive.Archiver
+	RepoGetter GitRepoGetter
+	// Events receives deploy lifecycle events, if set.
+	Events *eventWriter
+	// BundleBuilder builds bundles for dry runs. Defaults to building with the local Docker daemon.
+	BundleBuilder BundleBuildFunc
+	// Signer signs the provenance of each bundle's archive, if set. See loadSigner.
+	Signer crypto.Signer
+	// BundleRanks ranks the given bundles, if set. Bundles of each rank are deployed in their own
+	// deployment, after the deployments of lower ranks succeed. See bundleRanks.
+	BundleRanks func([]bundlediscover.Bundle) []int
+}
+
+// defnRewriter resolves the task definition files in uploaded bundles for the deployed
+// environment, since the deployed definitions are read from the bundles. See
+// definitions.ResolveDeployedDefinition.
+type defnRewriter struct {
+	envSlug string
+}
+
+func (r defnRewriter) Matches(path string) bool {
+	return definitions.IsTaskDef(path)
+}
+
+func (r defnRewriter) Rewrite(path string, buf []byte) ([]byte, error) {
+	return definitions.ResolveDeployedDefinition(path, buf, r.envSlug)
+}
+
+func NewDeployer(cfg Config, l logger.LoggerWithLoader, opts DeployerOpts) *deployer {
+	a := archive.NewAPIArchiver(l, cfg.Client, &archive.HttpUploader{}, defnRewriter{envSlug: cfg.EnvSlug})
+	if opts.Archiver != nil {
+		a = opts.Archiver
+	}
+	var rg GitRepoGetter
+	rg = &FileGitRepoGetter{}
+	if opts.RepoGetter != nil {
+		rg = opts.RepoGetter
+	}
+	bb := buildBundleLocally
+	if opts.BundleBuilder != nil {
+		bb = opts.BundleBuilder
+	}
+	return &deployer{
+		cfg:         cfg,
+		signer:      opts.Signer,
+		logger:      l,
+		archiver:    a,
+		repoGetter:  rg,
+		events:      opts.Events,
+		buildBundle: bb,
+		bundleRanks: opts.BundleRanks,
+	}
+}
+
+// Deploy creates a deployment.
+func (d *deployer) Deploy(ctx context.Context, bundles []bundlediscover.Bundle) error {
+	var err error
+	if len(d.cfg.ChangedFiles) > 0 {
+		bundles, err = d.filterBundlesByChangedFiles(ctx, bundles)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(bundles) == 0 {
+		d.logger.Log("Nothing to deploy")
+		return nil
+	}
+
+	for _, b := range bundles {
+		d.events.emit(Event{
+			Type:        EventDiscovered,
+			Bundle:      b.RootPath,
+			BuildType:   b.BuildContext.Type,
+			TargetFiles: b.TargetPaths,
+		})
+	}
+
+	if err := d.printPreDeploySummary(ctx, bundles); err != nil {
+		return err
+	}
+
+	licenseReports, err := d.checkLicenses(ctx, bundles)
+	if err != nil {
+		return err
+	}
+
+	registries, err := d.imageRegistries(ctx, bundles)
+	if err != nil {
+		return err
+	}
+
+	packagingStarted := time.Now()
+	archives, err := d.tarAndUploadBatch(ctx, bundles)
+	if err != nil {
+		return err
+	}
+	packagingFinished := time.Now()
+	d.logger.Debug("Code upload complete")
+
+	var bundlesToDeploy []api.DeployBundle
+	gitRoots := make(map[string]bool)
+	var repo *git.Repository
+	for _, b := range bundles {
+		repo, err = d.repoGetter.GetGitRepo(b.RootPath)
+		if err != nil {
+			d.logger.Debug("failed to get git repo for %s: %v", b.RootPath, err)
+		} else {
+			d.logger.Debug("discovered git repo for %s", b.RootPath)
+		}
+		var gitFilePath string
+		if repo != nil {
+			gitFilePath, err = GetEntrypointRelativeToGitRoot(repo, b.RootPath)
+			if err != nil {
+				d.logger.Debug("failed to get entrypoint relative to git root %s: %v", b.RootPath, err)
+			}
+		}
+		bundleToDeploy := api.DeployBundle{
+			UploadID:      archives[b.RootPath].UploadID,
+			Name:          filepath.Base(b.RootPath),
+			TargetFiles:   b.TargetPaths,
+			BuildContext:  b.BuildContext,
+			GitFilePath:   gitFilePath,
+			LicenseReport: licenseReports[b.RootPath],
+			ImageRegistry: registries[b.RootPath],
+			BuildCache:    d.buildCache(b, gitFilePath),
+		}
+		bundlesToDeploy = append(bundlesToDeploy, bundleToDeploy)
+
+		// Get the root directory of the git repo with which the bundle is associated.
+		var gitRoot string
+		if repo != nil {
+			w, err := repo.Worktree()
+			if err == nil {
+				gitRoot = w.Filesystem.Root()
+			}
+		}
+		gitRoots[gitRoot] = true
+	}
+
+	// If bundles in a single deploy come from different git repos, we do not
+	// include git information with the deploy.
+	mismatchedGitRepos := len(gitRoots) > 1
+	if mismatchedGitRepos {
+		analytics.ReportMessage("deploy created with multiple git repos")
+	}
+
+	var gitMeta api.GitMetadata
+	if repo != nil && !mismatchedGitRepos {
+		start := time.Now()
+		gitMeta, err = GetGitMetadata(repo)
+		if err != nil {
+			analytics.ReportMessage(fmt.Sprintf("failed to gather git metadata: %v", err))
+		}
+		gitMeta.User = conf.GetGitUser()
+		// Use the env variable provided repo if it exists.
+		getGitRepoResp := conf.GetGitRepo()
+		if getGitRepoResp.RepoName != "" {
+			gitMeta.RepositoryName = getGitRepoResp.RepoName
+		}
+		if getGitRepoResp.OwnerName != "" {
+			gitMeta.RepositoryOwnerName = getGitRepoResp.OwnerName
+		}
+		d.logger.Debug("Gathered git metadata for %s in %v: %#v", gitMeta.RepositoryName, time.Since(start), gitMeta)
+	}
+
+	if d.signer != nil {
+		if err := d.attestBundles(bundles, bundlesToDeploy, archives, gitMeta, packagingStarted, packagingFinished); err != nil {
+			return err
+		}
+	}
+
+	templateVars := newTemplateVars(gitMeta).Values()
+	if err := validateRegistryTemplates(registries, templateVars); err != nil {
+		return err
+	}
+
+	stages := [][]int{make([]int, len(bundles))}
+	for i := range bundles {
+		stages[0][i] = i
+	}
+	if d.bundleRanks != nil {
+		stages = deployStages(d.bundleRanks(bundles))
+	}
+	if len(stages) > 1 {
+		d.logger.Log("Some tasks call tasks in other bundles, so they're deployed in %d deployments, one after another.", len(stages))
+	}
+	for _, stage := range stages {
+		req := api.CreateDeploymentRequest{
+			GitMetadata:  gitMeta,
+			EnvSlug:      d.cfg.EnvSlug,
+			TemplateVars: templateVars,
+		}
+		for _, j := range stage {
+			req.Bundles = append(req.Bundles, bundlesToDeploy[j])
+		}
+		if err := d.createDeployment(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	if d.cfg.PinIDs {
+		return d.pinTaskIDs(ctx, bundles)
+	}
+	return nil
+}
+
+// createDeployment creates a deployment and waits for it to finish. The deployment is canceled if
+// ctx is.
+func (d *deployer) createDeployment(ctx context.Context, req api.CreateDeploymentRequest) error {
+	resp, err := d.cfg.Client.CreateDeployment(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	deploymentURL := d.cfg.Client.DeploymentURL(resp.Deployment.ID, d.cfg.EnvSlug)
+	d.events.emit(Event{Type: EventBuilding, DeploymentID: resp.Deployment.ID, URL: deploymentURL})
+	d.deployLog(ctx, api.LogLevelInfo, deployLogReq{msg: logger.Gray("Creating deployment...")})
+	d.logger.Log(logger.Purple(fmt.Sprintf("\nView deployment: %s\n", deploymentURL)))
+
+	err = d.waitForDeploy(ctx, d.cfg.Client, resp.Deployment.ID)
+	if errors.Is(err, context.Canceled) {
+		// Since `ctx` is cancelled, use a fresh context to cancel the deployment.
+		//nolint: contextcheck
+		cerr := d.cfg.Client.CancelDeployment(context.Background(), api.CancelDeploymentRequest{ID: resp.Deployment.ID})
+		if cerr != nil {
+			d.logger.Warning("Failed to cancel deployment: %v", cerr)
+		} else {
+			d.logger.Log("Cancelled deployment")
+		}
+	}
+
+	return err
+}
+
+// tarAndUploadBatch concurrently tars and uploads bundles.
+func (d *deployer) tarAndUploadBatch(
+	ctx context.Context,
+	bundles []bundlediscover.Bundle,
+) (map[string]archive.Result, error) {
+	pathsToUpload := make(map[string]interface{})
+	for _, b := range bundles {
+		pathsToUpload[b.RootPath] = struct{}{}
+	}
+
+	var archives sync.Map
+	g, ctx := errgroup.WithContext(ctx)
+	for p := range pathsToUpload {
+		p := p
+
+		g.Go(func() error {
+			res, err := d.tarAndUpload(ctx, p)
+			if err != nil {
+				return err
+			}
+			_, ok := archives.Load(p)
+			if !ok {
+				archives.Store(p, res)
+			}
+			return nil
+		})
+	}
+
+	groupErr := g.Wait()
+
+	archivesMap := make(map[string]archive.Result)
+	archives.Range(func(key, value interface{}) bool {
+		archivesMap[key.(string)] = value.(archive.Result)
+		return true
+	})
+	return archivesMap, groupErr
+}
+
+func (d *deployer) tarAndUpload(ctx context.Context, root string) (archive.Result, error) {
+	if err := d.confirmBuildRoot(root); err != nil {
+		return archive.Result{}, err
+	}
+
+	d.events.emit(Event{Type: EventUploading, Bundle: root})
+	d.deployLog(ctx, api.LogLevelInfo, deployLogReq{root, logger.Gray("Packaging and uploading %s...", root)})
+
+	res, err := d.archiver.Archive(ctx, root)
+	if err != nil {
+		return archive.Result{}, err
+	}
+	d.events.emit(Event{Type: EventUploaded, Bundle: root, UploadID: res.UploadID, SizeBytes: res.SizeBytes, Digest: res.Digest})
+	if res.SizeBytes > 0 {
+		d.deployLog(ctx, api.LogLevelInfo, deployLogReq{root, logger.Gray("Uploaded %s build archive.",
+			humanize.Bytes(uint64(res.SizeBytes)),
+		)})
+	}
+	return res, nil
+}
+
+// checkLicenses generates a dependency license report for each bundle root. It returns an error if
+// any dependency uses a license that is denied by the root's airplane.yaml, or if the licenses of
+// a root with a denylist cannot be checked because its dependencies are not installed locally.
+func (d *deployer) checkLicenses(ctx context.Context, bundles []bundlediscover.Bundle) (map[string]*api.LicenseReport, error) {
+	reports := make(map[string]*api.LicenseReport)
+	var violations []string
+	for _, b := range bundles {
+		if _, ok := reports[b.RootPath]; ok {
+			continue
+		}
+		reports[b.RootPath] = nil
+
+		var deny []string
+		if config.HasAirplaneConfig(b.RootPath) {
+			c, err := config.NewAirplaneConfigFromFile(b.RootPath)
+			if err != nil {
+				return nil, err
+			}
+			deny = c.Licenses.Deny
+		}
+		if !licenses.Supported(b.BuildContext.Type) {
+			if len(deny) > 0 {
+				d.logger.Warning("The licenses of %s dependencies in %s cannot be checked against the denylist.",
+					b.BuildContext.Type, b.RootPath)
+			}
+			continue
+		}
+
+		report, err := licenses.Generate(b.RootPath, b.BuildContext.Type)
+		if err != nil {
+			return nil, errors.Wrapf(err, "generating license report for %s", b.RootPath)
+		}
+		if len(deny) > 0 && len(report.Missing) > 0 {
+			return nil, errors.Errorf("cannot check the licenses of dependencies of %s that are not installed locally: %s\nInstall them and deploy again.",
+				b.RootPath, strings.Join(report.Missing, ", "))
+		}
+		if len(report.Dependencies) == 0 {
+			continue
+		}
+		reports[b.RootPath] = newLicenseReport(report)
+		d.deployLog(ctx, api.LogLevelInfo, deployLogReq{b.RootPath, logger.Gray("Found %d dependencies (%s).",
+			len(report.Dependencies),
+			humanize.Bytes(uint64(report.TotalSizeBytes)),
+		)})
+
+		for _, v := range report.Check(deny) {
+			violations = append(violations, fmt.Sprintf("%s: %s@%s uses %s (denied by %q)",
+				b.RootPath, v.Dependency.Name, v.Dependency.Version, v.Dependency.License, v.Pattern))
+		}
+	}
+	if len(violations) > 0 {
+		return nil, errors.Errorf("dependencies use denied licenses:\n  %s", strings.Join(violations, "\n  "))
+	}
+	return reports, nil
+}
+
+func newLicenseReport(report licenses.Report) *api.LicenseReport {
+	deps := make([]api.LicenseDependency, len(report.Dependencies))
+	for i, dep := range report.Dependencies {
+		deps[i] = api.LicenseDependency{
+			Name:      dep.Name,
+			Version:   dep.Version,
+			License:   dep.License,
+			SizeBytes: dep.SizeBytes,
+		}
+	}
+	return &api.LicenseReport{
+		Dependencies:   deps,
+		TotalSizeBytes: report.TotalSizeBytes,
+	}
+}
+
+// imageRegistries returns the external registry configured by the airplane.yaml of each bundle
+// root, if any. Bundles without a registry only push to the managed registry.
+func (d *deployer) imageRegistries(ctx context.Context, bundles []bundlediscover.Bundle) (map[string]*api.ImageRegistry, error) {
+	registries := make(map[string]*api.ImageRegistry)
+	for _, b := range bundles {
+		if _, ok := registries[b.RootPath]; ok || !config.HasAirplaneConfig(b.RootPath) {
+			continue
+		}
+		c, err := config.NewAirplaneConfigFromFile(b.RootPath)
+		if err != nil {
+			return nil, err
+		}
+		if !c.Registry.IsSet() {
+			registries[b.RootPath] = nil
+			continue
+		}
+		registries[b.RootPath] = &api.ImageRegistry{
+			ImageTemplate:     c.Registry.Image,
+			Exclusive:         c.Registry.ModeOrDefault() == config.RegistryModeExclusive,
+			CredentialsConfig: c.Registry.CredentialsConfig,
+		}
+		d.deployLog(ctx, api.LogLevelInfo, deployLogReq{b.RootPath, logger.Gray("Images will be pushed to %s (%s).",
+			c.Registry.Image,
+			c.Registry.ModeOrDefault(),
+		)})
+	}
+	return registries, nil
+}
+
+// buildCache returns the layer cache of a bundle's build, if any. Each bundle has its own cache
+// image within the configured images, keyed by its path and build type.
+func (d *deployer) buildCache(b bundlediscover.Bundle, gitFilePath string) *api.BuildCache {
+	if len(d.cfg.CacheFrom) == 0 && d.cfg.CacheTo == "" {
+		return nil
+	}
+	key := gitFilePath
+	if key == "" {
+		key = filepath.Base(b.RootPath)
+	}
+	key += "-" + string(b.BuildContext.Type)
+
+	cache := &api.BuildCache{
+		To: build.CacheRef(d.cfg.CacheTo, key),
+	}
+	for _, ref := range d.cfg.CacheFrom {
+		cache.From = append(cache.From, build.CacheRef(ref, key))
+	}
+	return cache
+}
+
+// gitFilePath returns the path of a bundle relative to the root of its git repo, or an empty
+// string if it isn't in one.
+func (d *deployer) gitFilePath(b bundlediscover.Bundle) string {
+	repo, err := d.repoGetter.GetGitRepo(b.RootPath)
+	if err != nil || repo == nil {
+		return ""
+	}
+	p, err := GetEntrypointRelativeToGitRoot(repo, b.RootPath)
+	if err != nil {
+		d.logger.Debug("failed to get entrypoint relative to git root %s: %v", b.RootPath, err)
+		return ""
+	}
+	return p
+}
+
+// filterBundlesByChangedFiles filters out any bundles that don't have changed files.
+func (d *deployer) filterBundlesByChangedFiles(ctx context.Context, bundles []bundlediscover.Bundle) ([]bundlediscover.Bundle, error) {
+	var filteredBundles []bundlediscover.Bundle
+	for _, b := range bundles {
+		contains, err := containsFile(b.RootPath, d.cfg.ChangedFiles)
+		if err != nil {
+			return nil, err
+		}
+		if contains {
+			filteredBundles = append(filteredBundles, b)
+			continue
+		}
+	}
+	if len(bundles) != len(filteredBundles) {
+		d.logger.Log("Changed files specified. Filtered %d bundle(s) to %d affected bundle(s)", len(bundles), len(filteredBundles))
+	}
+	return filteredBundles, nil
+}
+
+func (d *deployer) printPreDeploySummary(ctx context.Context, bundles []bundlediscover.Bundle) error {
+	projects := make(map[string][]string)
+	for _, b := range bundles {
+		var typeName string
+		if b.BuildContext.Type != "" {
+			buildType := string(b.BuildContext.Type)
+			if b.BuildContext.Type == buildtypes.NoneBuildType {
+				buildType = "non code"
+			}
+			typeName += cases.Title(language.English, cases.Compact).String(buildType)
+			if b.BuildContext.Version != "" && b.BuildContext.Type != buildtypes.ViewBuildType {
+				typeName += " " + string(b.BuildContext.Version)
+			}
+		}
+		if b.BuildContext.Base != buildtypes.BuildBaseNone {
+			typeName += " " + string(b.BuildContext.Base)
+		}
+		projects[b.RootPath] = append(projects[b.RootPath], typeName)
+	}
+
+	noun := "project"
+	if len(projects) > 1 {
+		noun = fmt.Sprintf("%ss", noun)
+	}
+	if len(projects) > 0 {
+		d.logger.Log("Found %v %v:\n", len(projects), noun)
+	}
+
+	for projectName, projectTypes := range projects {
+		d.logger.Log(logger.Bold(projectName))
+		d.logger.Log("Building for: %s", strings.Join(projectTypes, ", "))
+		d.logger.Log("")
+	}
+
+	isDefaultEnv := d.cfg.EnvSlug == ""
+	if isDefaultEnv {
+		wasActive := d.logger.StopLoader()
+		if wasActive {
+			defer d.logger.StartLoader()
+		}
+
+		question := "Continue deploying to the default environment?"
+		if ok, err := d.cfg.Root.Prompter.ConfirmWithAssumptions(question, d.cfg.AssumeYes, d.cfg.AssumeNo); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("Deployment cancelled")
+		}
+	}
+	return nil
+}
+
+func (d *deployer) waitForDeploy(ctx context.Context, client api.APIClient, deploymentID string) error {
+	d.deployLog(ctx, api.LogLevelInfo, deployLogReq{msg: logger.Gray("Waiting for deployer...")})
+
+	t := time.NewTicker(time.Second)
+
+	var prevToken string
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			r, err := client.GetDeploymentLogs(ctx, deploymentID, prevToken)
+			if err != nil {
+				return errors.Wrap(err, "getting build logs")
+			}
+
+			if len(r.Logs) > 0 {
+				prevToken = r.PrevPageToken
+			}
+
+			api.SortLogs(r.Logs)
+			for _, l := range r.Logs {
+				text := l.Text
+				if strings.HasPrefix(l.Text, "[builder] ") {
+					text = logger.Gray(strings.TrimPrefix(text, "[builder] "))
+				}
+
+				d.deployLog(ctx, l.Level, deployLogReq{l.TaskSlug, text})
+			}
+
+			deployment, err := client.GetDeployment(ctx, deploymentID)
+			if err != nil {
+				return errors.Wrap(err, "getting deployment")
+			}
+
+			switch {
+			case deployment.FailedAt != nil:
+				d.deployLog(ctx, api.LogLevelInfo, deployLogReq{msg: logger.Bold(logger.Red("failed: %s", deployment.FailedReason))})
+				d.logger.Log(logger.Purple(fmt.Sprintf("Failed deployment: %s\n", d.cfg.Client.DeploymentURL(deployment.ID, d.cfg.EnvSlug))))
+				d.events.emit(Event{Type: EventFailed, DeploymentID: deployment.ID, Error: deployment.FailedReason})
+				return errors.New("Deploy failed")
+			case deployment.SucceededAt != nil:
+				url := d.cfg.Client.DeploymentURL(deployment.ID, d.cfg.EnvSlug)
+				d.events.emit(Event{Type: EventBuilt, DeploymentID: deployment.ID, URL: url})
+				d.events.emit(Event{Type: EventUpdated, DeploymentID: deployment.ID, URL: url})
+				d.deployLog(ctx, api.LogLevelInfo, deployLogReq{msg: logger.Bold(logger.Green("succeeded"))})
+				d.logger.Log(logger.Purple(fmt.Sprintf("Successful deployment: %s\n", d.cfg.Client.DeploymentURL(deployment.ID, d.cfg.EnvSlug))))
+				return nil
+			case deployment.CancelledAt != nil:
+				d.deployLog(ctx, api.LogLevelInfo, deployLogReq{msg: logger.Bold(logger.Red("cancelled"))})
+				d.logger.Log(logger.Purple(fmt.Sprintf("Cancelled deployment: %s\n", d.cfg.Client.DeploymentURL(deployment.ID, d.cfg.EnvSlug))))
+				d.events.emit(Event{Type: EventFailed, DeploymentID: deployment.ID, Error: "Deploy cancelled"})
+				return errors.New("Deploy cancelled")
+			}
+		}
+	}
+}
+
+type deployLogReq struct {
+	bundleRoot string
+	msg        string
+}
+
+func (d *deployer) deployLog(ctx context.Context, level api.LogLevel, req deployLogReq, args ...interface{}) {
+	buildMsg := fmt.Sprintf("[%s %s] ", logger.Yellow("deploy"), filepath.Base(req.bundleRoot))
+	if req.bundleRoot == "" {
+		buildMsg = fmt.Sprintf("[%s] ", logger.Yellow("deploy"))
+	}
+	if level == api.LogLevelDebug {
+		d.logger.Log(buildMsg+"["+logger.Blue("debug")+"] "+req.msg, args...)
+	} else {
+		d.logger.Log(buildMsg+req.msg, args...)
+	}
+}
+
+func (d *deployer) confirmBuildRoot(root string) error {
+	if home, err := os.UserHomeDir(); err != nil {
+		return errors.Wrap(err, "getting home dir")
+	} else if home != root {
+		return nil
+	}
+	d.logger.Warning("The Airplane project you are deploying contains your entire home directory — deploying will attempt to upload the entire directory.")
+	d.logger.Warning("Consider moving your project's root directory to a subdirectory.")
+	d.logger.Warning("For more information, see https://docs.airplane.dev/dev-lifecycle/code-organization#determing-which-project-a-task-or-view-is-in.")
+	wasActive := d.logger.StopLoader()
+	if wasActive {
+		defer d.logger.StartLoader()
+	}
+	if ok, err := d.cfg.Root.Prompter.Confirm("Are you sure?"); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("aborting deployment")
+	}
+	return nil
+}
+
+// pinTaskIDs writes the ID of each deployed task back into its definition file, if it isn't
+// already pinned. Only tasks configured via definition files are pinned.
+func (d *deployer) pinTaskIDs(ctx context.Context, bundles []bundlediscover.Bundle) error {
+	discoverer := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:                  d.cfg.Client,
+				Logger:                  d.logger,
+				DisableNormalize:        true,
+				DoNotVerifyMissingTasks: true,
+			},
+		},
+		Client:      d.cfg.Client,
+		Logger:      d.logger,
+		EnvSlug:     d.cfg.EnvSlug,
+		Concurrency: d.cfg.DiscoveryConcurrency,
+	}
+
+	var paths []string
+	for _, b := range bundles {
+		for _, target := range b.TargetPaths {
+			paths = append(paths, filepath.Join(b.RootPath, target))
+		}
+	}
+	taskConfigs, _, err := discoverer.Discover(ctx, paths...)
+	if err != nil {
+		return errors.Wrap(err, "discovering tasks to pin")
+	}
+
+	for _, tc := range taskConfigs {
+		if tc.Def.ID != "" || tc.Def.GetDefnFilePath() == "" {
+			continue
+		}
+		metadata, err := d.cfg.Client.GetTaskMetadata(ctx, tc.Def.GetSlug())
+		if err != nil {
+			return errors.Wrapf(err, "getting task %s", tc.Def.GetSlug())
+		}
+		if err := updaters.PinYAMLTaskID(tc.Def.GetDefnFilePath(), metadata.ID); err != nil {
+			return errors.Wrapf(err, "pinning ID of task %s", tc.Def.GetSlug())
+		}
+		d.logger.Log("Pinned task %s to ID %s in %s", tc.Def.GetSlug(), metadata.ID, tc.Def.GetDefnFilePath())
+	}
+	return nil
+}
+
+// containsFile returns true if the directory contains at least one of the files.
+func containsFile(dir string, filePaths []string) (bool, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, errors.Wrapf(err, "calculating absolute path of directory %s", dir)
+	}
+	for _, cf := range filePaths {
+		absCF, err := filepath.Abs(cf)
+		if err != nil {
+			return false, errors.Wrapf(err, "calculating absolute path of file %s", cf)
+		}
+		changedFileDir := filepath.Dir(absCF)
+		if strings.HasPrefix(changedFileDir, absDir) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
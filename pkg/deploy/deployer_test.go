@@ -2,7 +2,12 @@ package deploy
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,6 +17,7 @@ import (
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/deploy/archive"
 	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/provenance"
 	"github.com/airplanedev/cli/pkg/prompts"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/go-git/go-billy/v5/memfs"
@@ -175,6 +181,11 @@ func TestDeploy(t *testing.T) {
 						RepositoryName:      "basic",
 						Vendor:              "GitHub",
 					},
+					TemplateVars: map[string]string{
+						"git.sha":      "6ecf0ef2c2dffb796033e5a02219af86ec6584e5",
+						"git.shortSHA": "6ecf0ef",
+						"git.ref":      "master",
+					},
 				},
 			},
 		},
@@ -197,6 +208,11 @@ func TestDeploy(t *testing.T) {
 						RepositoryName:      "airport",
 						Vendor:              "GitHub",
 					},
+					TemplateVars: map[string]string{
+						"git.sha":      "6ecf0ef2c2dffb796033e5a02219af86ec6584e5",
+						"git.shortSHA": "6ecf0ef",
+						"git.ref":      "master",
+					},
 				},
 			},
 		},
@@ -230,7 +246,7 @@ func TestDeploy(t *testing.T) {
 					Client:   client,
 					Prompter: prompts.NewMock(),
 				},
-				assumeYes: true,
+				AssumeYes: true,
 			}
 			d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{
 				Archiver:   &archive.MockArchiver{},
@@ -250,6 +266,149 @@ func TestDeploy(t *testing.T) {
 	}
 }
 
+func TestDeployImageRegistry(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(root, "airplane.yaml"), []byte(
+		"registry:\n  image: ghcr.io/acme/{{task.slug}}:{{deployment.id}}\n  mode: exclusive\n  credentialsConfig: GHCR_TOKEN\n",
+	), 0644))
+
+	mockClient := &api.MockClient{}
+	cfg := Config{
+		Client:    mockClient,
+		Root:      &cli.Config{Prompter: prompts.NewMock()},
+		AssumeYes: true,
+	}
+	d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{
+		Archiver:   &archive.MockArchiver{},
+		RepoGetter: &MockGitRepoGetter{},
+	})
+	err := d.Deploy(context.Background(), []bundlediscover.Bundle{
+		{RootPath: root, TargetPaths: []string{"a.ts"}},
+		{RootPath: "myRoot", TargetPaths: []string{"b.ts"}},
+	})
+	require.NoError(err)
+
+	require.Len(mockClient.Deploys, 1)
+	bundles := mockClient.Deploys[0].Bundles
+	require.Len(bundles, 2)
+	require.Equal(&api.ImageRegistry{
+		ImageTemplate:     "ghcr.io/acme/{{task.slug}}:{{deployment.id}}",
+		Exclusive:         true,
+		CredentialsConfig: "GHCR_TOKEN",
+	}, bundles[0].ImageRegistry)
+	require.Nil(bundles[1].ImageRegistry)
+}
+
+func TestDeployBuildCache(t *testing.T) {
+	require := require.New(t)
+
+	mockClient := &api.MockClient{}
+	cfg := Config{
+		Client:    mockClient,
+		Root:      &cli.Config{Prompter: prompts.NewMock()},
+		CacheFrom: []string{"us-docker.pkg.dev/acme/cache", "us-docker.pkg.dev/acme/cache:shared"},
+		CacheTo:   "us-docker.pkg.dev/acme/cache",
+		AssumeYes: true,
+	}
+	d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{
+		Archiver:   &archive.MockArchiver{},
+		RepoGetter: &MockGitRepoGetter{},
+	})
+	err := d.Deploy(context.Background(), []bundlediscover.Bundle{
+		{RootPath: "tasks/myRoot", TargetPaths: []string{"a.ts"}, BuildContext: buildtypes.BuildContext{Type: buildtypes.NodeBuildType}},
+	})
+	require.NoError(err)
+
+	require.Len(mockClient.Deploys, 1)
+	require.Equal(&api.BuildCache{
+		From: []string{"us-docker.pkg.dev/acme/cache:myRoot-node", "us-docker.pkg.dev/acme/cache:shared"},
+		To:   "us-docker.pkg.dev/acme/cache:myRoot-node",
+	}, mockClient.Deploys[0].Bundles[0].BuildCache)
+}
+
+func TestDeployInStages(t *testing.T) {
+	require := require.New(t)
+
+	mockClient := &api.MockClient{}
+	cfg := Config{
+		Client:    mockClient,
+		Root:      &cli.Config{Prompter: prompts.NewMock()},
+		AssumeYes: true,
+	}
+	d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{
+		Archiver:   &archive.MockArchiver{},
+		RepoGetter: &MockGitRepoGetter{},
+		BundleRanks: func(bundles []bundlediscover.Bundle) []int {
+			return []int{1, 0, 0}
+		},
+	})
+	err := d.Deploy(context.Background(), []bundlediscover.Bundle{
+		{RootPath: "callers", TargetPaths: []string{"."}},
+		{RootPath: "views", TargetPaths: []string{"."}},
+		{RootPath: "callees", TargetPaths: []string{"."}},
+	})
+	require.NoError(err)
+
+	// The callers are deployed once the tasks they call are.
+	require.Len(mockClient.Deploys, 2)
+	names := func(req api.CreateDeploymentRequest) []string {
+		var names []string
+		for _, b := range req.Bundles {
+			names = append(names, b.Name)
+		}
+		return names
+	}
+	require.Equal([]string{"views", "callees"}, names(mockClient.Deploys[0]))
+	require.Equal([]string{"callers"}, names(mockClient.Deploys[1]))
+}
+
+func TestDeployAttestations(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(root, "a.task.yaml"), []byte("slug: a"), 0644))
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+
+	mockClient := &api.MockClient{}
+	attestationsDir := t.TempDir()
+	cfg := Config{
+		Client:          mockClient,
+		Root:            &cli.Config{Prompter: prompts.NewMock()},
+		AttestationsDir: attestationsDir,
+		AssumeYes:       true,
+	}
+	d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{
+		Archiver:   &archive.MockArchiver{},
+		RepoGetter: &MockGitRepoGetter{},
+		Signer:     key,
+	})
+	err = d.Deploy(context.Background(), []bundlediscover.Bundle{
+		{RootPath: root, TargetPaths: []string{"a.task.yaml"}, BuildContext: buildtypes.BuildContext{Type: buildtypes.NodeBuildType}},
+	})
+	require.NoError(err)
+
+	require.Len(mockClient.Deploys, 1)
+	attestation := mockClient.Deploys[0].Bundles[0].Attestation
+	require.NotNil(attestation)
+	require.Equal("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", attestation.Digest)
+
+	files, err := os.ReadDir(attestationsDir)
+	require.NoError(err)
+	require.Len(files, 1)
+	buf, err := os.ReadFile(filepath.Join(attestationsDir, files[0].Name()))
+	require.NoError(err)
+	var envelope provenance.Envelope
+	require.NoError(json.Unmarshal(buf, &envelope))
+	require.Equal(attestation.Payload, envelope.Payload)
+
+	statement, err := provenance.Verify(envelope, &key.PublicKey)
+	require.NoError(err)
+	require.Equal(filepath.Base(root)+".tar.gz", statement.Subject[0].Name)
+	require.Equal(attestation.Digest, statement.Subject[0].Digest["sha256"])
+	require.Equal(buildtypes.NodeBuildType, buildtypes.BuildType(statement.Predicate.Invocation.Parameters["buildType"].(string)))
+}
+
 func TestParseRemote(t *testing.T) {
 	testCases := []struct {
 		desc      string
@@ -298,3 +457,55 @@ func TestParseRemote(t *testing.T) {
 		})
 	}
 }
+
+func TestDeployEvents(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	events, err := newEventWriter(0, path)
+	require.NoError(err)
+
+	cfg := Config{
+		Client:    &api.MockClient{},
+		Root:      &cli.Config{Prompter: prompts.NewMock()},
+		AssumeYes: true,
+	}
+	d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{
+		Archiver:   &archive.MockArchiver{},
+		RepoGetter: &MockGitRepoGetter{},
+		Events:     events,
+	})
+	err = d.Deploy(context.Background(), []bundlediscover.Bundle{{RootPath: "myRoot", TargetPaths: []string{"a.ts"}}})
+	require.NoError(err)
+	require.NoError(events.Close())
+
+	f, err := os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+	var types []EventType
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var e Event
+		require.NoError(dec.Decode(&e))
+		require.False(e.Time.IsZero())
+		types = append(types, e.Type)
+	}
+	require.Equal([]EventType{
+		EventDiscovered,
+		EventUploading,
+		EventUploaded,
+		EventBuilding,
+		EventBuilt,
+		EventUpdated,
+	}, types)
+}
+
+func TestNewEventWriter(t *testing.T) {
+	w, err := newEventWriter(0, "")
+	require.NoError(t, err)
+	require.Nil(t, w)
+	// Emitting to a nil writer is a no-op.
+	w.emit(Event{Type: EventFailed})
+
+	_, err = newEventWriter(3, "events.jsonl")
+	require.Error(t, err)
+}
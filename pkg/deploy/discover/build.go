@@ -3,15 +3,18 @@ package discover
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/airplanedev/cli/pkg/build/node"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/deploy/discover/parser"
+	golangparser "github.com/airplanedev/cli/pkg/deploy/discover/parser/golang"
 	"github.com/airplanedev/cli/pkg/utils/fsx"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/airplanedev/cli/pkg/utils/pointers"
@@ -23,27 +26,80 @@ var (
 	entityConfigExtractionLine = regexp.MustCompile(`.*EXTRACTED_ENTITY_CONFIGS:(.+)`)
 )
 
-// esbuildUserFiles builds an airplane entity -> root/.airplane/discover/.
-func esbuildUserFiles(log logger.Logger, rootDir, file string) error {
+// externalPackages returns the packages that esbuild should not bundle when building files in rootDir.
+func externalPackages(rootDir string) ([]string, error) {
 	rootPackageJSON := filepath.Join(rootDir, "package.json")
-	hasPackageJSON := fsx.AssertExistsAll(rootPackageJSON) == nil
+	if fsx.AssertExistsAll(rootPackageJSON) != nil {
+		return nil, nil
+	}
 	packageJSONs, usesWorkspaces, err := node.GetPackageJSONs(rootPackageJSON)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	var externals []string
-	if hasPackageJSON {
-		// Workaround to get esbuild to not bundle dependencies.
-		// See build.ExternalPackages for details.
-		externals, err = node.ExternalPackages(packageJSONs, usesWorkspaces)
-		if err != nil {
-			return err
+	// Workaround to get esbuild to not bundle dependencies.
+	// See build.ExternalPackages for details.
+	return node.ExternalPackages(packageJSONs, usesWorkspaces)
+}
+
+// discoverDirMu serializes creating directories under root/.airplane/discover/ with removing it, so
+// that removeDiscoverDir can't delete it between makeDiscoverDir's MkdirAll and MkdirTemp.
+var discoverDirMu sync.Mutex
+
+// makeDiscoverDir creates a directory under root/.airplane/discover/ to build a single file into.
+// Every file gets its own directory, so that files in the same root can be discovered concurrently.
+func makeDiscoverDir(rootDir string) (string, error) {
+	discoverDirMu.Lock()
+	defer discoverDirMu.Unlock()
+
+	parent := filepath.Join(rootDir, ".airplane", "discover")
+	// Another process discovering the same root may remove the parent directory after we create it,
+	// in which case we create it again.
+	for attempt := 0; ; attempt++ {
+		if err := os.MkdirAll(parent, 0755); err != nil {
+			return "", errors.Wrap(err, "creating discover directory")
 		}
+		dir, err := os.MkdirTemp(parent, "build-")
+		if errors.Is(err, fs.ErrNotExist) && attempt < 3 {
+			continue
+		} else if err != nil {
+			return "", errors.Wrap(err, "creating discover directory")
+		}
+		return dir, nil
+	}
+}
+
+// removeDiscoverDir removes a directory created by makeDiscoverDir. root/.airplane/discover/ is
+// removed as well once no other file is being built into it.
+func removeDiscoverDir(log logger.Logger, dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		log.Warning("unable to remove temporary directory %s: %s", dir, err)
+		return
+	}
+
+	discoverDirMu.Lock()
+	defer discoverDirMu.Unlock()
+	// Fails if the directory is not empty, which is expected.
+	_ = os.Remove(filepath.Dir(dir))
+}
+
+// esbuildUserFiles builds an airplane entity -> outDir.
+func esbuildUserFiles(log logger.Logger, rootDir, outDir, file string) error {
+	externals, err := externalPackages(rootDir)
+	if err != nil {
+		return err
+	}
+	esbuildConfig, err := node.ReadEsbuildConfig(rootDir)
+	if err != nil {
+		return err
+	}
+	loaders, err := esbuildLoaders(esbuildConfig.Loader)
+	if err != nil {
+		return err
 	}
 
 	res := esbuild.Build(esbuild.BuildOptions{
 		EntryPoints: []string{file},
-		Outdir:      filepath.Join(rootDir, ".airplane", "discover"),
+		Outdir:      outDir,
 		Outbase:     rootDir,
 		Write:       true,
 
@@ -54,6 +110,11 @@ func esbuildUserFiles(log logger.Logger, rootDir, file string) error {
 		Format:   esbuild.FormatCommonJS,
 		Bundle:   true,
 		External: externals,
+		// Only the options that affect how user code compiles are applied here: discovery only
+		// needs to run the output, so sourcemaps and minification are irrelevant.
+		Define:     esbuildConfig.Define,
+		Loader:     loaders,
+		JSXFactory: esbuildConfig.JSXFactory,
 		Plugins: []esbuild.Plugin{
 			{
 				Name:  "Remove css",
@@ -80,8 +141,93 @@ func esbuildUserFiles(log logger.Logger, rootDir, file string) error {
 	return nil
 }
 
+var esbuildLoaderNames = map[string]esbuild.Loader{
+	"base64":  esbuild.LoaderBase64,
+	"binary":  esbuild.LoaderBinary,
+	"copy":    esbuild.LoaderCopy,
+	"css":     esbuild.LoaderCSS,
+	"dataurl": esbuild.LoaderDataURL,
+	"default": esbuild.LoaderDefault,
+	"empty":   esbuild.LoaderEmpty,
+	"file":    esbuild.LoaderFile,
+	"js":      esbuild.LoaderJS,
+	"json":    esbuild.LoaderJSON,
+	"jsx":     esbuild.LoaderJSX,
+	"text":    esbuild.LoaderText,
+	"ts":      esbuild.LoaderTS,
+	"tsx":     esbuild.LoaderTSX,
+}
+
+// esbuildLoaders converts loaders configured in airplane.yaml (e.g. {".svg": "text"}) into the
+// loaders expected by esbuild's Go API.
+func esbuildLoaders(loaders map[string]string) (map[string]esbuild.Loader, error) {
+	if len(loaders) == 0 {
+		return nil, nil
+	}
+	res := make(map[string]esbuild.Loader, len(loaders))
+	for ext, name := range loaders {
+		l, ok := esbuildLoaderNames[name]
+		if !ok {
+			return nil, errors.Errorf("unknown esbuild loader %q for %s", name, ext)
+		}
+		res[ext] = l
+	}
+	return res, nil
+}
+
+// NodeImports returns the absolute paths of the local source files that file transitively imports,
+// including file itself. Dependencies in node_modules are not included.
+func NodeImports(rootDir, file string) ([]string, error) {
+	externals, err := externalPackages(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	res := esbuild.Build(esbuild.BuildOptions{
+		EntryPoints:   []string{file},
+		AbsWorkingDir: rootDir,
+		Outdir:        filepath.Join(rootDir, ".airplane", "discover"),
+		Write:         false,
+		Metafile:      true,
+		LogLevel:      esbuild.LogLevelSilent,
+
+		Platform: esbuild.PlatformNode,
+		Format:   esbuild.FormatCommonJS,
+		Bundle:   true,
+		External: externals,
+		Plugins: []esbuild.Plugin{
+			{
+				Name:  "Remove css",
+				Setup: removeCSSEsbuildPlugin,
+			},
+		},
+	})
+	if len(res.Errors) > 0 {
+		return nil, errors.Errorf("resolving imports of %s: %s", file, res.Errors[0].Text)
+	}
+
+	var metafile struct {
+		Inputs map[string]json.RawMessage `json:"inputs"`
+	}
+	if err := json.Unmarshal([]byte(res.Metafile), &metafile); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling esbuild metafile")
+	}
+	var imports []string
+	for input := range metafile.Inputs {
+		// Inputs from plugins or other namespaces are prefixed, e.g. "css:foo.css".
+		if strings.Contains(input, ":") {
+			continue
+		}
+		if strings.Contains(input, "node_modules/") {
+			continue
+		}
+		imports = append(imports, filepath.Join(rootDir, filepath.FromSlash(input)))
+	}
+	return imports, nil
+}
+
 // Gets the path of the compiled user file.
-func compiledFilePath(rootDir, file string) (string, error) {
+func compiledFilePath(rootDir, outDir, file string) (string, error) {
 	fileAbs, err := filepath.Abs(file)
 	if err != nil {
 		return "", err
@@ -90,7 +236,7 @@ func compiledFilePath(rootDir, file string) (string, error) {
 	if err != nil {
 		return "", errors.New("unable to determine relative path of view from root")
 	}
-	compiledJSPath := filepath.Join(rootDir, ".airplane", "discover", relPathFromRoot)
+	compiledJSPath := filepath.Join(outDir, relPathFromRoot)
 	compiledJSPath = strings.TrimSuffix(compiledJSPath, filepath.Ext(compiledJSPath))
 	compiledJSPath = compiledJSPath + ".js"
 	return compiledJSPath, nil
@@ -163,6 +309,43 @@ func extractPythonConfigs(file string, env []string) ([]map[string]interface{},
 	return parsedTasks, nil
 }
 
+// extractDenoConfigs extracts task configs from a Deno file. Deno runs TypeScript natively, so
+// the file is imported by the parser as-is rather than being compiled first.
+func extractDenoConfigs(file string, env []string) ([]map[string]interface{}, error) {
+	tempFile, err := os.CreateTemp("", "airplane.parser.deno.*.ts")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temporary file")
+	}
+	defer os.Remove(tempFile.Name())
+	_, err = tempFile.Write([]byte(parser.DenoParserScript))
+	if err != nil {
+		return nil, errors.Wrap(err, "writing parser script")
+	}
+
+	parserCmd := exec.Command("deno", "run", "--allow-all", tempFile.Name(), file)
+	parserCmd.Env = append(os.Environ(), env...)
+	out, err := parserCmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return nil, errors.Wrapf(err, "parsing file=%q: %s", file, ee.Stderr)
+		}
+		return nil, errors.Wrapf(err, "parsing file=%q", file)
+	}
+
+	// Parser output is EXTRACTED_ENTITY_CONFIGS:[...]
+	match := entityConfigExtractionLine.FindStringSubmatch(string(out))
+	if len(match) != 2 {
+		return nil, errors.Errorf("could not find EXTRACTED_ENTITY_CONFIGS in parser output: %s", string(out))
+	}
+	configs := match[1]
+
+	var parsedTasks []map[string]interface{}
+	if err := json.Unmarshal([]byte(configs), &parsedTasks); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling parser output %s", configs)
+	}
+	return parsedTasks, nil
+}
+
 // removeCSSEsbuildPlugin is an esbuild plugin that replaces all CSS imports with an empty file.
 // Without this plugin, we cannot execute a built file that contains CSS because Node.JS does not
 // know how to import and execute CSS files.
@@ -186,3 +369,17 @@ func removeCSSEsbuildPlugin(pb esbuild.PluginBuild) {
 		}, nil
 	})
 }
+
+// extractGoConfigs extracts task configs from a Go file. Go files are parsed rather than executed,
+// since running them would require compiling the task's module.
+func extractGoConfigs(file string) ([]map[string]interface{}, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading file=%q", file)
+	}
+	configs, err := golangparser.Parse(file, buf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing file=%q", file)
+	}
+	return configs, nil
+}
@@ -1,13 +1,11 @@
 package discover
 
 import (
-	"path/filepath"
-
+	"github.com/airplanedev/cli/pkg/api"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/deploy/config"
 	"github.com/airplanedev/cli/pkg/runtime"
 	"github.com/airplanedev/cli/pkg/runtime/javascript"
-	"github.com/airplanedev/cli/pkg/utils/fsx"
 )
 
 // TaskBuildContext gets the build context for a task.
@@ -17,13 +15,9 @@ func TaskBuildContext(taskroot string, taskRuntime runtime.Interface) (buildtype
 		return buildtypes.BuildContext{}, err
 	}
 
-	var c config.AirplaneConfig
-	hasAirplaneConfig := fsx.Exists(filepath.Join(taskroot, config.FileName))
-	if hasAirplaneConfig {
-		c, err = config.NewAirplaneConfigFromFile(taskroot)
-		if err != nil {
-			return buildtypes.BuildContext{}, err
-		}
+	c, _, err := config.LoadAirplaneConfig(taskroot)
+	if err != nil {
+		return buildtypes.BuildContext{}, err
 	}
 
 	envVars := make(map[string]buildtypes.EnvVarValue)
@@ -43,17 +37,24 @@ func TaskBuildContext(taskroot string, taskRuntime runtime.Interface) (buildtype
 	}
 
 	var base buildtypes.BuildBase
+	var platforms []string
 	switch taskRuntime.Kind() {
 	case buildtypes.TaskKindNode:
 		base = buildtypes.BuildBase(c.Javascript.Base)
+		platforms = c.Javascript.BuildPlatforms
 	case buildtypes.TaskKindPython:
 		base = buildtypes.BuildBase(c.Python.Base)
+		platforms = c.Python.BuildPlatforms
+	}
+	if err := validateBuildPlatforms(platforms); err != nil {
+		return buildtypes.BuildContext{}, err
 	}
 
 	return buildtypes.BuildContext{
-		Version: buildVersion,
-		Base:    base,
-		EnvVars: envVars,
+		Version:   buildVersion,
+		Base:      base,
+		EnvVars:   envVars,
+		Platforms: platforms,
 	}, nil
 }
 
@@ -64,13 +65,9 @@ func ViewBuildContext(viewroot string) (buildtypes.BuildContext, error) {
 		return buildtypes.BuildContext{}, err
 	}
 
-	var c config.AirplaneConfig
-	hasAirplaneConfig := fsx.Exists(filepath.Join(viewroot, config.FileName))
-	if hasAirplaneConfig {
-		c, err = config.NewAirplaneConfigFromFile(viewroot)
-		if err != nil {
-			return buildtypes.BuildContext{}, err
-		}
+	c, _, err := config.LoadAirplaneConfig(viewroot)
+	if err != nil {
+		return buildtypes.BuildContext{}, err
 	}
 
 	envVars := make(map[string]buildtypes.EnvVarValue)
@@ -87,3 +84,37 @@ func ViewBuildContext(viewroot string) (buildtypes.BuildContext, error) {
 		EnvVars: envVars,
 	}, nil
 }
+
+// validateBuildPlatforms checks that images can be built for each of the platforms.
+func validateBuildPlatforms(platforms []string) error {
+	_, err := buildtypes.BuildPlatforms(buildtypes.KindOptions{buildtypes.KindOptionBuildPlatforms: platforms})
+	return err
+}
+
+// envSetVars returns the env vars of the env sets named by envFrom, as defined in the airplane
+// config in root. These take precedence over the env vars of the build context, but not over the
+// env vars of the definition that includes them.
+func envSetVars(root string, envFrom []string) (api.EnvVars, error) {
+	if len(envFrom) == 0 {
+		return nil, nil
+	}
+
+	var c config.AirplaneConfig
+	if config.HasAirplaneConfig(root) {
+		var err error
+		c, err = config.NewAirplaneConfigFromFile(root)
+		if err != nil {
+			return nil, err
+		}
+	}
+	merged, err := c.EnvSets.Merge(envFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	envVars := make(api.EnvVars, len(merged))
+	for k, v := range merged {
+		envVars[k] = api.EnvVarValue(v)
+	}
+	return envVars, nil
+}
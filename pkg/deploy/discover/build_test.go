@@ -0,0 +1,79 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	esbuild "github.com/evanw/esbuild/pkg/api"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestEsbuildUserFilesOptions(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(root, "package.json"), []byte("{}"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(root, "airplane.yaml"), []byte(`javascript:
+  esbuild:
+    define:
+      __VERSION__: '"1.2.3"'
+    loader:
+      .svg: text
+`), 0644))
+	require.NoError(os.WriteFile(filepath.Join(root, "logo.svg"), []byte("<svg></svg>"), 0644))
+	file := filepath.Join(root, "task.ts")
+	require.NoError(os.WriteFile(file, []byte(`import logo from "./logo.svg";
+export const version = __VERSION__;
+export const icon = logo;
+`), 0644))
+
+	outDir := filepath.Join(root, "out")
+	require.NoError(esbuildUserFiles(logger.NewNoopLogger(), root, outDir, file))
+
+	out, err := os.ReadFile(filepath.Join(outDir, "task.js"))
+	require.NoError(err)
+	require.Contains(string(out), `"1.2.3"`)
+	require.Contains(string(out), "<svg></svg>")
+}
+
+func TestEsbuildLoaders(t *testing.T) {
+	require := require.New(t)
+
+	loaders, err := esbuildLoaders(nil)
+	require.NoError(err)
+	require.Nil(loaders)
+
+	loaders, err = esbuildLoaders(map[string]string{".svg": "text", ".png": "dataurl"})
+	require.NoError(err)
+	require.Equal(map[string]esbuild.Loader{
+		".svg": esbuild.LoaderText,
+		".png": esbuild.LoaderDataURL,
+	}, loaders)
+
+	_, err = esbuildLoaders(map[string]string{".svg": "svgr"})
+	require.ErrorContains(err, `unknown esbuild loader "svgr" for .svg`)
+}
+
+func TestDiscoverDirConcurrent(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	var g errgroup.Group
+	for i := 0; i < 50; i++ {
+		g.Go(func() error {
+			dir, err := makeDiscoverDir(root)
+			if err != nil {
+				return err
+			}
+			removeDiscoverDir(&logger.MockLogger{}, dir)
+			return nil
+		})
+	}
+	require.NoError(g.Wait())
+
+	_, err := os.Stat(filepath.Join(root, ".airplane", "discover"))
+	require.True(os.IsNotExist(err))
+}
@@ -5,12 +5,11 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path"
 
 	"github.com/airplanedev/cli/pkg/api"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/config"
 	deployutils "github.com/airplanedev/cli/pkg/deploy/utils"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/pkg/errors"
@@ -32,10 +31,30 @@ type CodeTaskDiscoverer struct {
 
 	// Optional key=value pairs to pass to the parser.
 	Env []string
+
+	// EnvSlug is the slug of the environment that resource aliases from the airplane config are
+	// resolved for. If empty, only aliases that apply to every environment are resolved.
+	EnvSlug string
+
+	// ParseCache, if set, caches the configs that are parsed from files.
+	ParseCache *ParseCache
+
+	// FailOnParseError returns an error if the configs of a file can't be parsed, e.g. because
+	// its runtime isn't installed, rather than warning and discovering no tasks in it.
+	FailOnParseError bool
 }
 
 var _ TaskDiscoverer = &CodeTaskDiscoverer{}
 
+// parseFailed handles a file whose inline configs couldn't be parsed.
+func (c *CodeTaskDiscoverer) parseFailed(file string, err error) error {
+	if c.FailOnParseError {
+		return errors.Wrapf(err, "discovering inline configured tasks in %s", file)
+	}
+	c.Logger.Warning(`Unable to discover inline configured tasks: %s`, err.Error())
+	return nil
+}
+
 func (c *CodeTaskDiscoverer) GetAirplaneTasks(ctx context.Context, file string) ([]string, error) {
 	taskConfigs, err := c.GetTaskConfigs(ctx, file)
 	if err != nil {
@@ -62,7 +81,7 @@ func (c *CodeTaskDiscoverer) GetTaskConfigs(ctx context.Context, file string) ([
 	for _, def := range defs {
 		var metadata api.TaskMetadata
 		if !c.DoNotVerifyMissingTasks {
-			metadata, err = c.Client.GetTaskMetadata(ctx, def.Def.GetSlug())
+			metadata, err = getTaskMetadata(ctx, c.Client, def.Def)
 			if err != nil {
 				var merr *api.TaskMissingError
 				if !errors.As(err, &merr) {
@@ -74,7 +93,9 @@ func (c *CodeTaskDiscoverer) GetTaskConfigs(ctx context.Context, file string) ([
 					continue
 				}
 
+				missingEntityHandlerMu.Lock()
 				mptr, err := c.MissingTaskHandler(ctx, def.Def)
+				missingEntityHandlerMu.Unlock()
 				if err != nil {
 					return nil, err
 				} else if mptr == nil {
@@ -110,9 +131,15 @@ func (c *CodeTaskDiscoverer) GetTaskRoot(ctx context.Context, file string) (stri
 	if deployutils.IsNodeInlineAirplaneEntity(file) {
 		kind = buildtypes.TaskKindNode
 		buildType = buildtypes.NodeBuildType
+	} else if deployutils.IsDenoInlineAirplaneEntity(file) {
+		kind = buildtypes.TaskKindDeno
+		buildType = buildtypes.DenoBuildType
 	} else if deployutils.IsPythonInlineAirplaneEntity(file) {
 		kind = buildtypes.TaskKindPython
 		buildType = buildtypes.PythonBuildType
+	} else if deployutils.IsGoInlineAirplaneEntity(file) {
+		kind = buildtypes.TaskKindGo
+		buildType = buildtypes.GoBuildType
 	}
 	if kind == "" {
 		return "", buildtypes.BuildContext{}, nil
@@ -151,8 +178,12 @@ type ParsedDefinition struct {
 func (c *CodeTaskDiscoverer) parseDefinitions(ctx context.Context, file string) ([]ParsedDefinition, error) {
 	if deployutils.IsNodeInlineAirplaneEntity(file) {
 		return c.parseNodeDefinitions(ctx, file)
+	} else if deployutils.IsDenoInlineAirplaneEntity(file) {
+		return c.parseDenoDefinitions(ctx, file)
 	} else if deployutils.IsPythonInlineAirplaneEntity(file) {
 		return c.parsePythonDefinitions(ctx, file)
+	} else if deployutils.IsGoInlineAirplaneEntity(file) {
+		return c.parseGoDefinitions(ctx, file)
 	}
 	return nil, nil
 }
@@ -167,23 +198,33 @@ func (c *CodeTaskDiscoverer) parseNodeDefinitions(ctx context.Context, file stri
 		return nil, err
 	}
 
-	if err := esbuildUserFiles(c.Logger, pm.RootDir, file); err != nil {
+	outDir, err := makeDiscoverDir(pm.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	defer removeDiscoverDir(c.Logger, outDir)
+	if err := esbuildUserFiles(c.Logger, pm.RootDir, outDir, file); err != nil {
 		return nil, errors.Wrap(err, "unable to build task")
 	}
-	defer func() {
-		if err := os.RemoveAll(path.Join(pm.RootDir, ".airplane", "discover")); err != nil {
-			c.Logger.Warning("unable to remove temporary directory: %s")
-		}
-	}()
 
-	compiledJSPath, err := compiledFilePath(pm.RootDir, file)
+	compiledJSPath, err := compiledFilePath(pm.RootDir, outDir, file)
 	if err != nil {
 		return nil, err
 	}
 
-	parsedConfigs, err := extractJSConfigs(compiledJSPath, c.Env)
-	if err != nil {
-		c.Logger.Warning(`Unable to discover inline configured tasks: %s`, err.Error())
+	// The compiled file includes the local files that the task imports, so changes to those are
+	// picked up as well.
+	key := c.ParseCache.Key(file, compiledJSPath, c.Env)
+	var parsedConfigs ParsedJSConfigs
+	if !c.ParseCache.Get(key, &parsedConfigs) {
+		parsedConfigs, err = extractJSConfigs(compiledJSPath, c.Env)
+		if err != nil {
+			if err := c.parseFailed(file, err); err != nil {
+				return nil, err
+			}
+		} else {
+			c.ParseCache.Put(key, parsedConfigs)
+		}
 	}
 
 	var parsedDefinitions []ParsedDefinition
@@ -197,6 +238,9 @@ func (c *CodeTaskDiscoverer) parseNodeDefinitions(ctx context.Context, file stri
 		if err != nil {
 			return nil, err
 		}
+		if err := resolveResourceAliases(&def, pm.RootDir, c.EnvSlug); err != nil {
+			return nil, err
+		}
 
 		parsedDefinitions = append(parsedDefinitions, ParsedDefinition{
 			Def:          def,
@@ -208,9 +252,13 @@ func (c *CodeTaskDiscoverer) parseNodeDefinitions(ctx context.Context, file stri
 }
 
 func (c *CodeTaskDiscoverer) parsePythonDefinitions(ctx context.Context, file string) ([]ParsedDefinition, error) {
+	// Python files aren't cached: the parser runs the modules that the file imports, and those
+	// aren't known without running it.
 	parsedConfigs, err := extractPythonConfigs(file, c.Env)
 	if err != nil {
-		c.Logger.Warning(`Unable to discover inline configured tasks: %s`, err.Error())
+		if err := c.parseFailed(file, err); err != nil {
+			return nil, err
+		}
 	}
 
 	pathMetadata, err := taskPathMetadata(file, buildtypes.TaskKindPython)
@@ -233,6 +281,92 @@ func (c *CodeTaskDiscoverer) parsePythonDefinitions(ctx context.Context, file st
 		if err != nil {
 			return nil, err
 		}
+		if err := resolveResourceAliases(&def, pathMetadata.RootDir, c.EnvSlug); err != nil {
+			return nil, err
+		}
+
+		parsedDefinitions = append(parsedDefinitions, ParsedDefinition{
+			Def:          def,
+			PathMetadata: pathMetadata,
+		})
+	}
+
+	return parsedDefinitions, nil
+}
+
+func (c *CodeTaskDiscoverer) parseDenoDefinitions(ctx context.Context, file string) ([]ParsedDefinition, error) {
+	pathMetadata, err := taskPathMetadata(file, buildtypes.TaskKindDeno)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to interpret task path metadata")
+	}
+	bc, err := TaskBuildContext(pathMetadata.RootDir, pathMetadata.Runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deno files aren't cached, for the same reason as Python files.
+	parsedConfigs, err := extractDenoConfigs(pathMetadata.AbsEntrypoint, c.Env)
+	if err != nil {
+		if err := c.parseFailed(file, err); err != nil {
+			return nil, err
+		}
+	}
+
+	var parsedDefinitions []ParsedDefinition
+	for _, parsedTask := range parsedConfigs {
+		// Add the entrypoint to the json definition before validation
+		// since it is unknown to the parser.
+		denoConfig := parsedTask["deno"].(map[string]interface{})
+		denoConfig["entrypoint"] = pathMetadata.RelEntrypoint
+
+		def, err := ConstructDefinition(parsedTask, pathMetadata, bc)
+		if err != nil {
+			return nil, err
+		}
+		if err := resolveResourceAliases(&def, pathMetadata.RootDir, c.EnvSlug); err != nil {
+			return nil, err
+		}
+
+		parsedDefinitions = append(parsedDefinitions, ParsedDefinition{
+			Def:          def,
+			PathMetadata: pathMetadata,
+		})
+	}
+
+	return parsedDefinitions, nil
+}
+
+func (c *CodeTaskDiscoverer) parseGoDefinitions(ctx context.Context, file string) ([]ParsedDefinition, error) {
+	pathMetadata, err := taskPathMetadata(file, buildtypes.TaskKindGo)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to interpret task path metadata")
+	}
+	bc, err := TaskBuildContext(pathMetadata.RootDir, pathMetadata.Runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedConfigs, err := extractGoConfigs(pathMetadata.AbsEntrypoint)
+	if err != nil {
+		if err := c.parseFailed(file, err); err != nil {
+			return nil, err
+		}
+	}
+
+	var parsedDefinitions []ParsedDefinition
+	for _, parsedTask := range parsedConfigs {
+		// Add the entrypoint to the json definition before validation
+		// since it is unknown to the parser.
+		goConfig := parsedTask["go"].(map[string]interface{})
+		goConfig["entrypoint"] = pathMetadata.RelEntrypoint
+
+		def, err := ConstructDefinition(parsedTask, pathMetadata, bc)
+		if err != nil {
+			return nil, err
+		}
+		if err := resolveResourceAliases(&def, pathMetadata.RootDir, c.EnvSlug); err != nil {
+			return nil, err
+		}
 
 		parsedDefinitions = append(parsedDefinitions, ParsedDefinition{
 			Def:          def,
@@ -243,6 +377,26 @@ func (c *CodeTaskDiscoverer) parsePythonDefinitions(ctx context.Context, file st
 	return parsedDefinitions, nil
 }
 
+// resolveResourceAliases replaces resource slugs attached by def with the slugs they are aliased to
+// in the airplane config for the given environment, so that code can reference a resource by the
+// same name in every environment.
+func resolveResourceAliases(def *definitions.Definition, root string, envSlug string) error {
+	if len(def.Resources) == 0 || !config.HasAirplaneConfig(root) {
+		return nil
+	}
+	c, err := config.NewAirplaneConfigFromFile(root)
+	if err != nil {
+		return err
+	}
+	aliases := c.ResourceAliases.ForEnv(envSlug)
+	for alias, slug := range def.Resources {
+		if resolved, ok := aliases[slug]; ok {
+			def.Resources[alias] = resolved
+		}
+	}
+	return nil
+}
+
 func ConstructDefinition(parsedTask map[string]interface{}, pathMetadata TaskPathMetadata, buildContext buildtypes.BuildContext) (definitions.Definition, error) {
 	entrypointFunc, ok := parsedTask["entrypointFunc"].(string)
 	if !ok {
@@ -291,11 +445,18 @@ func ConstructDefinition(parsedTask map[string]interface{}, pathMetadata TaskPat
 	if err != nil {
 		return definitions.Definition{}, err
 	}
+	envVarsFromSets, err := envSetVars(pathMetadata.RootDir, def.EnvFrom)
+	if err != nil {
+		return definitions.Definition{}, err
+	}
 	// Calculate the full list of env vars. This is the env vars (from airplane config)
-	// plus the env vars from the task. Set this new list on the task def.
+	// plus the env vars from the included env sets and the task. Set this new list on the task def.
 	for k, v := range buildContext.EnvVars {
 		envVars[k] = api.EnvVarValue(v)
 	}
+	for k, v := range envVarsFromSets {
+		envVars[k] = v
+	}
 	for k, v := range envVarsFromDefn {
 		envVars[k] = v
 	}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path"
 
 	"github.com/airplanedev/cli/pkg/api"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
@@ -27,6 +25,14 @@ type CodeViewDiscoverer struct {
 
 	// Optional key=value pairs to pass to the parser.
 	Env []string
+
+	// ParseCache, if set, caches the configs that are parsed from files. It can be shared with a
+	// CodeTaskDiscoverer, since tasks and views are parsed from files at once.
+	ParseCache *ParseCache
+
+	// FailOnParseError returns an error if the configs of a file can't be parsed, rather than
+	// warning and discovering no view in it.
+	FailOnParseError bool
 }
 
 var _ ViewDiscoverer = &CodeViewDiscoverer{}
@@ -45,23 +51,32 @@ func (dd *CodeViewDiscoverer) GetViewConfig(ctx context.Context, file string) (*
 		return nil, err
 	}
 
-	if err := esbuildUserFiles(dd.Logger, pm.RootDir, file); err != nil {
+	outDir, err := makeDiscoverDir(pm.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	defer removeDiscoverDir(dd.Logger, outDir)
+	if err := esbuildUserFiles(dd.Logger, pm.RootDir, outDir, file); err != nil {
 		return nil, errors.Wrap(err, "unable to build view")
 	}
-	defer func() {
-		if err := os.RemoveAll(path.Join(pm.RootDir, ".airplane", "discover")); err != nil {
-			dd.Logger.Warning("unable to remove temporary directory: %s")
-		}
-	}()
 
-	compiledJSPath, err := compiledFilePath(pm.RootDir, file)
+	compiledJSPath, err := compiledFilePath(pm.RootDir, outDir, file)
 	if err != nil {
 		return nil, err
 	}
 
-	parsedConfigs, err := extractJSConfigs(compiledJSPath, dd.Env)
-	if err != nil {
-		dd.Logger.Warning(`Unable to discover inline configured views: %s`, err.Error())
+	key := dd.ParseCache.Key(file, compiledJSPath, dd.Env)
+	var parsedConfigs ParsedJSConfigs
+	if !dd.ParseCache.Get(key, &parsedConfigs) {
+		parsedConfigs, err = extractJSConfigs(compiledJSPath, dd.Env)
+		if err != nil {
+			if dd.FailOnParseError {
+				return nil, errors.Wrapf(err, "discovering inline configured views in %s", file)
+			}
+			dd.Logger.Warning(`Unable to discover inline configured views: %s`, err.Error())
+		} else {
+			dd.ParseCache.Put(key, parsedConfigs)
+		}
 	}
 
 	if len(parsedConfigs.ViewConfigs) == 0 {
@@ -93,11 +108,18 @@ func (dd *CodeViewDiscoverer) GetViewConfig(ctx context.Context, file string) (*
 
 	envVars := make(api.EnvVars)
 	envVarsFromDefn := d.EnvVars
+	envVarsFromSets, err := envSetVars(pm.RootDir, d.EnvFrom)
+	if err != nil {
+		return nil, err
+	}
 	// Calculate the full list of env vars. This is the env vars (from airplane config)
-	// plus the env vars from the view. Set this new list on the def.
+	// plus the env vars from the included env sets and the view. Set this new list on the def.
 	for k, v := range bc.EnvVars {
 		envVars[k] = api.EnvVarValue(v)
 	}
+	for k, v := range envVarsFromSets {
+		envVars[k] = v
+	}
 	for k, v := range envVarsFromDefn {
 		envVars[k] = v
 	}
@@ -117,7 +139,9 @@ func (dd *CodeViewDiscoverer) GetViewConfig(ctx context.Context, file string) (*
 				return nil, nil
 			}
 
+			missingEntityHandlerMu.Lock()
 			vptr, err := dd.MissingViewHandler(ctx, d)
+			missingEntityHandlerMu.Unlock()
 			if err != nil {
 				return nil, err
 			} else if vptr == nil {
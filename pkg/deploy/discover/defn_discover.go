@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/airplanedev/cli/pkg/api"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
@@ -37,6 +38,8 @@ type DefnDiscoverer struct {
 
 var _ TaskDiscoverer = &DefnDiscoverer{}
 
+var warnDeprecationsOnce sync.Once
+
 func (dd *DefnDiscoverer) GetAirplaneTasks(ctx context.Context, file string) ([]string, error) {
 	if !definitions.IsTaskDef(file) {
 		return nil, nil
@@ -76,13 +79,30 @@ func (dd *DefnDiscoverer) GetTaskConfigs(ctx context.Context, file string) ([]Ta
 		return nil, err
 	}
 
+	var resources []api.ResourceMetadata
 	if !dd.DisableNormalize {
 		resp, err := dd.Client.ListResourceMetadata(ctx)
 		if err != nil {
 			return nil, err
 		}
+		resources = resp.Resources
+	}
 
-		if err := def.Normalize(resp.Resources); err != nil {
+	// Deprecations have to be checked before normalizing, which rewrites some of them.
+	if dd.Logger != nil {
+		if deprecations := def.Deprecations(resources); len(deprecations) > 0 {
+			for _, d := range deprecations {
+				dd.Logger.Debug("Task %s in %s uses deprecated %s", def.GetSlug(), file, d)
+			}
+			// Definitions are discovered repeatedly, e.g. by the dev server, so only warn once.
+			warnDeprecationsOnce.Do(func() {
+				dd.Logger.Warning("Some task definitions use deprecated fields. Run `airplane fix` to list them, or `airplane fix --apply` to update them.")
+			})
+		}
+	}
+
+	if !dd.DisableNormalize {
+		if err := def.Normalize(resources); err != nil {
 			return nil, err
 		}
 	}
@@ -94,7 +114,7 @@ func (dd *DefnDiscoverer) GetTaskConfigs(ctx context.Context, file string) ([]Ta
 
 	var metadata api.TaskMetadata
 	if !dd.DoNotVerifyMissingTasks {
-		metadata, err = dd.Client.GetTaskMetadata(ctx, tc.Def.GetSlug())
+		metadata, err = getTaskMetadata(ctx, dd.Client, tc.Def)
 		if err != nil {
 			var merr *api.TaskMissingError
 			if !errors.As(err, &merr) {
@@ -105,7 +125,9 @@ func (dd *DefnDiscoverer) GetTaskConfigs(ctx context.Context, file string) ([]Ta
 				return nil, nil
 			}
 
+			missingEntityHandlerMu.Lock()
 			mptr, err := dd.MissingTaskHandler(ctx, tc.Def)
+			missingEntityHandlerMu.Unlock()
 			if err != nil {
 				return nil, err
 			} else if mptr == nil {
@@ -157,7 +179,10 @@ func (dd *DefnDiscoverer) GetTaskConfigs(ctx context.Context, file string) ([]Ta
 		if err != nil {
 			return nil, err
 		}
-		tc.Def.SetBuildConfig("entrypoint", ep)
+		tc.Def.SetBuildConfig("entrypoint", filepath.ToSlash(ep))
+		if kind, _ := tc.Def.Kind(); kind == buildtypes.TaskKindDockerfile {
+			tc.Def.SetBuildConfig("dockerfile", filepath.ToSlash(ep))
+		}
 	}
 
 	return []TaskConfig{tc}, nil
@@ -221,6 +246,14 @@ func setBuildVersionAndWorkingDir(file string, def *definitions.Definition) (str
 	if err != nil {
 		return "", buildtypes.BuildContext{}, err
 	}
+	var dockerfile *buildtypes.DockerfileBuildContext
+	if kind == buildtypes.TaskKindDockerfile {
+		// Tasks that are built from a Dockerfile are built in their build context, which may be
+		// above the Dockerfile.
+		if taskPathMetadata.RootDir, dockerfile, err = def.DockerfileBuildContext(); err != nil {
+			return "", buildtypes.BuildContext{}, err
+		}
+	}
 	bc, err := TaskBuildContext(taskPathMetadata.RootDir, taskPathMetadata.Runtime)
 	if err != nil {
 		return "", buildtypes.BuildContext{}, err
@@ -238,16 +271,30 @@ func setBuildVersionAndWorkingDir(file string, def *definitions.Definition) (str
 	}
 
 	// Calculate the full list of env vars. This is the env vars (from airplane config)
-	// plus the env vars from the task. Set this new list on the task def
-	// and on the build context.
+	// plus the env vars from the included env sets, the task's env files and the task. Set this new
+	// list on the task def and on the build context.
 	envVars := make(map[string]buildtypes.EnvVarValue)
 	envVarsFromDefn, err := def.GetEnv()
 	if err != nil {
 		return "", buildtypes.BuildContext{}, err
 	}
+	envVarsFromSets, err := envSetVars(taskPathMetadata.RootDir, def.EnvFrom)
+	if err != nil {
+		return "", buildtypes.BuildContext{}, err
+	}
+	envVarsFromFiles, err := definitions.ReadEnvFiles(def.GetEnvFiles())
+	if err != nil {
+		return "", buildtypes.BuildContext{}, err
+	}
 	for k, v := range bc.EnvVars {
 		envVars[k] = v
 	}
+	for k, v := range envVarsFromSets {
+		envVars[k] = buildtypes.EnvVarValue(v)
+	}
+	for k, v := range envVarsFromFiles {
+		envVars[k] = buildtypes.EnvVarValue(v)
+	}
 	for k, v := range envVarsFromDefn {
 		envVars[k] = buildtypes.EnvVarValue(v)
 	}
@@ -263,11 +310,22 @@ func setBuildVersionAndWorkingDir(file string, def *definitions.Definition) (str
 		}
 	}
 
+	// The platforms of the definition take precedence over those of the airplane config.
+	platforms := bc.Platforms
+	if len(def.BuildPlatforms) > 0 {
+		if err := validateBuildPlatforms(def.BuildPlatforms); err != nil {
+			return "", buildtypes.BuildContext{}, err
+		}
+		platforms = def.BuildPlatforms
+	}
+
 	return taskPathMetadata.RootDir, buildtypes.BuildContext{
-		Type:    buildType,
-		Version: buildTypeVersion,
-		Base:    buildBase,
-		EnvVars: envVars,
+		Type:       buildType,
+		Version:    buildTypeVersion,
+		Base:       buildBase,
+		EnvVars:    envVars,
+		Dockerfile: dockerfile,
+		Platforms:  platforms,
 	}, nil
 }
 
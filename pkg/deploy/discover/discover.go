@@ -2,16 +2,20 @@ package discover
 
 import (
 	"context"
+	"io/fs"
 	"os"
-	"path"
 	"path/filepath"
+	"runtime"
 	"sort"
+	"sync"
 
 	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/build/ignore"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/definitions"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 )
 
 var IgnoredDirectories = map[string]bool{
@@ -108,93 +112,182 @@ type Discoverer struct {
 	// If a task is discovered, but doesn't exist in this environment, then the task
 	// is treated as missing.
 	EnvSlug string
+
+	// Concurrency is the maximum number of files that are inspected at once. If unset, files are
+	// inspected one at a time.
+	Concurrency int
+}
+
+// DefaultConcurrency is the default value of the --discovery-concurrency flag.
+var DefaultConcurrency = runtime.NumCPU()
+
+// discoveredFile holds the configs discovered in a single file.
+type discoveredFile struct {
+	taskConfigs []TaskConfig
+	viewConfigs []ViewConfig
 }
 
 // Discover recursively discovers Airplane tasks & views. Only one config per slug is returned.
 // If there are multiple configs discovered with the same slug, the order of the discoverers takes
 // precedence; if a single discoverer discovers multiple configs with the same slug, the first config
 // discovered takes precedence. Configs are returned in alphabetical order of their slugs.
+//
+// Files are inspected concurrently, up to d.Concurrency at a time, but "first discovered" always
+// refers to the order in which files are walked, so the result does not depend on scheduling.
 func (d *Discoverer) Discover(ctx context.Context, paths ...string) ([]TaskConfig, []ViewConfig, error) {
+	files, err := walkFiles(paths...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	concurrency := d.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	discovered := make([]discoveredFile, len(files))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for i, file := range files {
+		i, file := i, file
+		g.Go(func() error {
+			df, err := d.discoverFile(gctx, file)
+			if err != nil {
+				return err
+			}
+			discovered[i] = df
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
 	taskConfigsBySlug := map[string][]TaskConfig{}
 	viewConfigsBySlug := map[string][]ViewConfig{}
+	for _, df := range discovered {
+		for _, tc := range df.taskConfigs {
+			slug := tc.Def.GetSlug()
+			taskConfigsBySlug[slug] = append(taskConfigsBySlug[slug], tc)
+		}
+		for _, vc := range df.viewConfigs {
+			slug := vc.Def.Slug
+			viewConfigsBySlug[slug] = append(viewConfigsBySlug[slug], vc)
+		}
+	}
+
+	return deduplicateConfigs(taskConfigsBySlug, d.TaskDiscoverers), deduplicateConfigs(viewConfigsBySlug, d.ViewDiscoverers), nil
+}
+
+// walkFiles recursively lists the files in paths, in the order that they should be discovered.
+// Ignored directories are skipped, as are files and directories excluded by .airplaneignore files.
+// Files that are passed in paths are never excluded.
+func walkFiles(paths ...string) ([]string, error) {
+	var files []string
 	for _, p := range paths {
 		if IgnoredDirectories[filepath.Base(p)] {
+			logger.TraceFor(logger.ModuleDiscover, "%s: skipping ignored directory", p)
 			continue
 		}
 		fileInfo, err := os.Stat(p)
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "determining if %s is file or directory", p)
+			return nil, errors.Wrapf(err, "determining if %s is file or directory", p)
 		}
 
-		if fileInfo.IsDir() {
-			// We found a directory. Recursively explore all of the files and directories in it.
-			nestedFiles, err := os.ReadDir(p)
-			if err != nil {
-				return nil, nil, errors.Wrapf(err, "reading directory %s", p)
-			}
-			var nestedPaths []string
-			for _, nestedFile := range nestedFiles {
-				nestedPaths = append(nestedPaths, path.Join(p, nestedFile.Name()))
-			}
-			nestedTaskConfigs, nestedViewConfigs, err := d.Discover(ctx, nestedPaths...)
+		if !fileInfo.IsDir() {
+			files = append(files, p)
+			continue
+		}
+
+		m, err := ignore.NewMatcherFor(p)
+		if err != nil {
+			return nil, err
+		}
+		nested, err := walkDir(m, p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, nested...)
+	}
+	return files, nil
+}
+
+// walkDir recursively lists the files in dir that aren't excluded by m.
+func walkDir(m *ignore.Matcher, dir string) ([]string, error) {
+	if err := m.AddDir(dir); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading directory %s", dir)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		p := filepath.Join(dir, entry.Name())
+		isDir := entry.IsDir()
+		if entry.Type()&fs.ModeSymlink != 0 {
+			// Follow symlinks, so that symlinked directories are discovered.
+			info, err := os.Stat(p)
 			if err != nil {
-				return nil, nil, err
-			}
-			for _, tc := range nestedTaskConfigs {
-				slug := tc.Def.GetSlug()
-				if _, ok := taskConfigsBySlug[slug]; !ok {
-					taskConfigsBySlug[slug] = []TaskConfig{}
-				}
-				taskConfigsBySlug[slug] = append(taskConfigsBySlug[slug], tc)
+				return nil, errors.Wrapf(err, "determining if %s is file or directory", p)
 			}
-			for _, vc := range nestedViewConfigs {
-				slug := vc.Def.Slug
-				if _, ok := viewConfigsBySlug[slug]; !ok {
-					viewConfigsBySlug[slug] = []ViewConfig{}
-				}
-				viewConfigsBySlug[slug] = append(viewConfigsBySlug[slug], vc)
-			}
-		} else {
-			// We found a file.
-			for _, td := range d.TaskDiscoverers {
-				taskConfigs, err := td.GetTaskConfigs(ctx, p)
-				if err != nil {
-					return nil, nil, err
-				}
-				if len(taskConfigs) == 0 {
-					// This file is not an Airplane task.
-					continue
-				}
+			isDir = info.IsDir()
+		}
+		if isDir && IgnoredDirectories[entry.Name()] {
+			logger.TraceFor(logger.ModuleDiscover, "%s: skipping ignored directory", p)
+			continue
+		}
+		if m.Match(p) {
+			logger.TraceFor(logger.ModuleDiscover, "%s: skipping path excluded by %s", p, ignore.FileName)
+			continue
+		}
 
-				for _, taskConfig := range taskConfigs {
-					slug := taskConfig.Def.GetSlug()
-					if _, ok := taskConfigsBySlug[slug]; !ok {
-						taskConfigsBySlug[slug] = []TaskConfig{}
-					}
-					taskConfigsBySlug[slug] = append(taskConfigsBySlug[slug], taskConfig)
-				}
-			}
-			for _, vd := range d.ViewDiscoverers {
-				viewConfig, err := vd.GetViewConfig(ctx, p)
-				if err != nil {
-					return nil, nil, err
-				}
-				if viewConfig == nil {
-					// This file is not an Airplane view.
-					continue
-				}
-				slug := viewConfig.Def.Slug
-				if _, ok := viewConfigsBySlug[slug]; !ok {
-					viewConfigsBySlug[slug] = []ViewConfig{}
-				}
-				viewConfigsBySlug[slug] = append(viewConfigsBySlug[slug], *viewConfig)
-			}
+		if !isDir {
+			files = append(files, p)
+			continue
+		}
+		nested, err := walkDir(m, p)
+		if err != nil {
+			return nil, err
 		}
+		files = append(files, nested...)
 	}
+	return files, nil
+}
 
-	return deduplicateConfigs(taskConfigsBySlug, d.TaskDiscoverers), deduplicateConfigs(viewConfigsBySlug, d.ViewDiscoverers), nil
+// discoverFile runs every discoverer against a single file.
+func (d *Discoverer) discoverFile(ctx context.Context, p string) (discoveredFile, error) {
+	var df discoveredFile
+	for _, td := range d.TaskDiscoverers {
+		taskConfigs, err := td.GetTaskConfigs(ctx, p)
+		if err != nil {
+			return discoveredFile{}, err
+		}
+		// An empty list means that this file is not an Airplane task.
+		for _, taskConfig := range taskConfigs {
+			logger.DebugFor(logger.ModuleDiscover, "%s: discovered task %s (source: %s)", p, taskConfig.Def.GetSlug(), td.ConfigSource())
+			df.taskConfigs = append(df.taskConfigs, taskConfig)
+		}
+	}
+	for _, vd := range d.ViewDiscoverers {
+		viewConfig, err := vd.GetViewConfig(ctx, p)
+		if err != nil {
+			return discoveredFile{}, err
+		}
+		if viewConfig == nil {
+			// This file is not an Airplane view.
+			continue
+		}
+		logger.DebugFor(logger.ModuleDiscover, "%s: discovered view %s (source: %s)", p, viewConfig.Def.Slug, vd.ConfigSource())
+		df.viewConfigs = append(df.viewConfigs, *viewConfig)
+	}
+	return df, nil
 }
 
+// missingEntityHandlerMu serializes calls to MissingTaskHandler and MissingViewHandler, which may
+// prompt the user, across files that are discovered concurrently.
+var missingEntityHandlerMu sync.Mutex
+
 // deduplicateConfigs returns a list of configs unique by slug, sorted by slug
 // from a map of slug -> [task config, ...]. Configs are chosen based on order of Discoverers & order of discovery.
 func deduplicateConfigs[C interface{ GetSource() ConfigSource }, D ConfigDiscoverer](taskConfigsBySlug map[string][]C, configDiscoverers []D) []C {
@@ -222,6 +315,7 @@ func deduplicateConfigs[C interface{ GetSource() ConfigSource }, D ConfigDiscove
 
 		// Otherwise, loop through the Discoverers. Take the first task config that matches the
 		// discoverer in this order.
+		logger.DebugFor(logger.ModuleDiscover, "%s was discovered %d times, using the config with the highest precedence", slug, len(tcs))
 		found := false
 		for _, td := range configDiscoverers {
 			for _, tc := range tcs {
@@ -238,3 +332,28 @@ func deduplicateConfigs[C interface{ GetSource() ConfigSource }, D ConfigDiscove
 	}
 	return taskConfigs
 }
+
+// getTaskMetadata looks up the remote task that a definition refers to. If the definition is
+// pinned to an ID, the task is matched by ID so that renaming the slug updates the existing task
+// instead of creating a new one. Otherwise, the task is matched by slug.
+func getTaskMetadata(ctx context.Context, client api.IAPIClient, def definitions.Definition) (api.TaskMetadata, error) {
+	if def.ID == "" {
+		return client.GetTaskMetadata(ctx, def.GetSlug())
+	}
+
+	task, err := client.GetTaskByID(ctx, def.ID)
+	if err != nil {
+		var merr *api.TaskMissingError
+		if errors.As(err, &merr) {
+			// Do not treat the task as missing: that would create a new task and orphan the
+			// task that this definition is pinned to.
+			return api.TaskMetadata{}, errors.Errorf("task %s is pinned to ID %s, but no task with that ID exists", def.GetSlug(), def.ID)
+		}
+		return api.TaskMetadata{}, err
+	}
+	return api.TaskMetadata{
+		ID:         task.ID,
+		Slug:       task.Slug,
+		IsArchived: task.IsArchived,
+	}, nil
+}
@@ -2,9 +2,12 @@ package discover
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/api/mock"
@@ -221,6 +224,47 @@ func TestDiscover(t *testing.T) {
 				fixturesPath + "/single_task.js",
 			},
 		},
+		{
+			name:  "task definition pinned to an ID",
+			paths: []string{"./fixtures/pinnedID/defn.task.yaml"},
+			existingTasks: map[string]api.Task{
+				"my_task": {ID: "tsk123", Slug: "my_task", Kind: buildtypes.TaskKindNode, InterpolationMode: "jst"},
+			},
+			expectedTaskConfigs: []TaskConfig{
+				{
+					TaskID:         "tsk123",
+					TaskRoot:       fixturesPath,
+					TaskEntrypoint: fixturesPath + "/single_task.js",
+					Def: definitions.Definition{
+						ID:   "tsk123",
+						Name: "sunt in tempor eu",
+						Slug: "my_renamed_task",
+						Node: &definitions.NodeDefinition{
+							Entrypoint: "../single_task.js",
+						},
+					},
+					Source: ConfigSourceDefn,
+				},
+			},
+			buildConfigs: []buildtypes.BuildConfig{
+				{
+					"workdir":    "",
+					"entrypoint": "single_task.js",
+				},
+			},
+			defnFilePaths: []string{fixturesPath + "/pinnedID/defn.task.yaml"},
+			absEntrypoints: []string{
+				fixturesPath + "/single_task.js",
+			},
+		},
+		{
+			name:  "task definition pinned to a missing ID",
+			paths: []string{"./fixtures/pinnedID/defn.task.yaml"},
+			existingTasks: map[string]api.Task{
+				"my_renamed_task": {ID: "tsk456", Slug: "my_renamed_task", Kind: buildtypes.TaskKindNode, InterpolationMode: "jst"},
+			},
+			expectedErr: true,
+		},
 		{
 			name:  "task definitions with version in bundle",
 			paths: []string{"./fixtures/tasksWithVersion"},
@@ -1207,3 +1251,313 @@ func TestDiscover(t *testing.T) {
 		})
 	}
 }
+
+// slugTaskDiscoverer discovers a task in every file, whose slug is the contents of the file.
+// Files are inspected slowest-first, so that concurrent discovery finishes out of order.
+type slugTaskDiscoverer struct{}
+
+func (slugTaskDiscoverer) GetAirplaneTasks(ctx context.Context, file string) ([]string, error) {
+	return nil, nil
+}
+
+func (slugTaskDiscoverer) GetTaskConfigs(ctx context.Context, file string) ([]TaskConfig, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(file)
+	time.Sleep(time.Duration(10-int(name[0]-'0')) * time.Millisecond)
+	return []TaskConfig{{
+		TaskEntrypoint: file,
+		Def:            definitions.Definition{Slug: string(buf)},
+		Source:         ConfigSourceDefn,
+	}}, nil
+}
+
+func (slugTaskDiscoverer) ConfigSource() ConfigSource {
+	return ConfigSourceDefn
+}
+
+func (slugTaskDiscoverer) GetTaskRoot(ctx context.Context, file string) (string, buildtypes.BuildContext, error) {
+	return "", buildtypes.BuildContext{}, nil
+}
+
+func TestDiscoverConcurrency(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"0.yaml":          "a",
+		"1.yaml":          "b",
+		"2/0.yaml":        "a",
+		"2/1.yaml":        "c",
+		"3.yaml":          "b",
+		"node_modules/4":  "d",
+		"5.yaml":          "c",
+		".airplane/6.sql": "e",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+
+	for _, concurrency := range []int{0, 1, 4, 16} {
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			require := require.New(t)
+
+			d := &Discoverer{
+				TaskDiscoverers: []TaskDiscoverer{slugTaskDiscoverer{}},
+				Logger:          &logger.MockLogger{},
+				Concurrency:     concurrency,
+			}
+			taskConfigs, _, err := d.Discover(context.Background(), root)
+			require.NoError(err)
+
+			// When a slug is discovered more than once, the file that is walked first wins,
+			// regardless of which file finished first.
+			var entrypoints []string
+			for _, tc := range taskConfigs {
+				entrypoints = append(entrypoints, tc.Def.GetSlug()+"="+filepath.Base(filepath.Dir(tc.TaskEntrypoint))+"/"+filepath.Base(tc.TaskEntrypoint))
+			}
+			base := filepath.Base(root)
+			require.Equal([]string{
+				"a=" + base + "/0.yaml",
+				"b=" + base + "/1.yaml",
+				"c=2/1.yaml",
+			}, entrypoints)
+		})
+	}
+
+	d := &Discoverer{
+		TaskDiscoverers: []TaskDiscoverer{slugTaskDiscoverer{}},
+		Logger:          &logger.MockLogger{},
+		Concurrency:     4,
+	}
+	_, _, err := d.Discover(context.Background(), filepath.Join(root, "missing"))
+	require.Error(t, err)
+}
+
+func TestWalkFilesAirplaneignore(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		".git/HEAD":                      "",
+		".airplaneignore":                "fixtures\n/generated\n",
+		"task.yaml":                      "",
+		"fixtures/task.yaml":             "",
+		"generated/task.yaml":            "",
+		"pkg/generated/task.yaml":        "",
+		"pkg/.airplaneignore":            "*.tmp.yaml\n!keep.tmp.yaml\n",
+		"pkg/a.tmp.yaml":                 "",
+		"pkg/keep.tmp.yaml":              "",
+		"pkg/nested/fixtures/task.yaml":  "",
+		"other/a.tmp.yaml":               "",
+		"other/node_modules/task.yaml":   "",
+		"other/.airplane/build/task.sql": "",
+	} {
+		p := filepath.Join(root, name)
+		require.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(os.WriteFile(p, []byte(content), 0644))
+	}
+
+	rel := func(files []string) []string {
+		var out []string
+		for _, f := range files {
+			r, err := filepath.Rel(root, f)
+			require.NoError(err)
+			out = append(out, filepath.ToSlash(r))
+		}
+		return out
+	}
+
+	files, err := walkFiles(root)
+	require.NoError(err)
+	require.Equal([]string{
+		".airplaneignore",
+		"other/a.tmp.yaml",
+		"pkg/.airplaneignore",
+		"pkg/generated/task.yaml",
+		"pkg/keep.tmp.yaml",
+		"task.yaml",
+	}, rel(files))
+
+	// The .airplaneignore files of parent directories apply when discovering a subdirectory, and
+	// files that are passed explicitly are never excluded.
+	files, err = walkFiles(filepath.Join(root, "pkg"), filepath.Join(root, "fixtures", "task.yaml"))
+	require.NoError(err)
+	require.Equal([]string{
+		"pkg/.airplaneignore",
+		"pkg/generated/task.yaml",
+		"pkg/keep.tmp.yaml",
+		"fixtures/task.yaml",
+	}, rel(files))
+}
+
+func TestCodeTaskDiscovererGo(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod": "module example.com/tasks\n\ngo 1.22\n",
+		"tasks/hello_airplane.go": `package main
+
+import "github.com/airplanedev/go-sdk/airplane"
+
+var _ = airplane.RegisterTask(airplane.TaskConfig{
+	Slug: "hello",
+	Name: "Hello",
+	Parameters: []airplane.Param{
+		{Slug: "name", Name: "Name", Type: "shorttext", Optional: true},
+	},
+	EnvVars: map[string]airplane.EnvVar{"LOG_LEVEL": {Value: "debug"}},
+}, Hello)
+`,
+		// Entrypoints of tasks with definition files aren't inline tasks.
+		"cmd/main_airplane.go": "package main\n\nfunc main() {}\n",
+	} {
+		p := filepath.Join(root, name)
+		require.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(os.WriteFile(p, []byte(content), 0644))
+	}
+
+	d := &CodeTaskDiscoverer{Logger: &logger.MockLogger{}, DoNotVerifyMissingTasks: true}
+	configs, err := d.GetTaskConfigs(ctx, filepath.Join(root, "cmd", "main_airplane.go"))
+	require.NoError(err)
+	require.Empty(configs)
+
+	file := filepath.Join(root, "tasks", "hello_airplane.go")
+	configs, err = d.GetTaskConfigs(ctx, file)
+	require.NoError(err)
+	require.Len(configs, 1)
+	def := configs[0].Def
+	require.Equal("hello", def.GetSlug())
+	require.Equal(root, configs[0].TaskRoot)
+	require.Equal(file, configs[0].TaskEntrypoint)
+	require.Equal(ConfigSourceCode, configs[0].Source)
+	require.Len(def.Parameters, 1)
+	require.False(def.Parameters[0].Required.Value())
+	require.NotNil(def.Go)
+	require.Equal("debug", *def.Go.EnvVars["LOG_LEVEL"].Value)
+
+	bc, err := def.GetBuildConfig()
+	require.NoError(err)
+	require.Equal(filepath.Join("tasks", "hello_airplane.go"), bc["entrypoint"])
+	require.Equal("Hello", bc["entrypointFunc"])
+
+	taskRoot, buildContext, err := d.GetTaskRoot(ctx, file)
+	require.NoError(err)
+	require.Equal(root, taskRoot)
+	require.Equal(buildtypes.GoBuildType, buildContext.Type)
+}
+
+func TestNodeImports(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	files := map[string]string{
+		"package.json":     `{"dependencies": {"airplane": "*"}}`,
+		"task.airplane.ts": `import airplane from "airplane"; import { util } from "./lib/util"; export default util;`,
+		"lib/util.ts":      `import { other } from "./other"; export const util = other;`,
+		"lib/other.ts":     `export const other = 1;`,
+		"unused.ts":        `export const unused = 1;`,
+	}
+	for name, contents := range files {
+		require.NoError(os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0755))
+		require.NoError(os.WriteFile(filepath.Join(root, name), []byte(contents), 0644))
+	}
+
+	imports, err := NodeImports(root, filepath.Join(root, "task.airplane.ts"))
+	require.NoError(err)
+	require.ElementsMatch([]string{
+		filepath.Join(root, "task.airplane.ts"),
+		filepath.Join(root, "lib", "util.ts"),
+		filepath.Join(root, "lib", "other.ts"),
+	}, imports)
+}
+
+func TestResolveResourceAliases(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(root, "airplane.yaml"), []byte(`resourceAliases:
+  "*":
+    db: dev_db
+  prod:
+    db: prod_db
+`), 0644))
+
+	def := definitions.Definition{
+		Slug:      "my_task",
+		Resources: definitions.ResourcesDefinition{"db": "db", "warehouse": "warehouse", "api": "db"},
+	}
+	require.NoError(resolveResourceAliases(&def, root, "prod"))
+	require.Equal(definitions.ResourcesDefinition{"db": "prod_db", "warehouse": "warehouse", "api": "prod_db"}, def.Resources)
+
+	def.Resources = definitions.ResourcesDefinition{"db": "db"}
+	require.NoError(resolveResourceAliases(&def, root, "staging"))
+	require.Equal(definitions.ResourcesDefinition{"db": "dev_db"}, def.Resources)
+
+	// Without an airplane config, resources are left as-is.
+	def.Resources = definitions.ResourcesDefinition{"db": "db"}
+	require.NoError(resolveResourceAliases(&def, t.TempDir(), "prod"))
+	require.Equal(definitions.ResourcesDefinition{"db": "db"}, def.Resources)
+}
+
+func TestEnvSets(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	files := map[string]string{
+		"airplane.yaml": `python:
+  envVars:
+    LOG_LEVEL: info
+envSets:
+  observability:
+    OTEL_EXPORTER: otlp
+    SERVICE_NS: default
+`,
+		"my_task.task.yaml": `slug: my_task
+envFrom: [observability]
+python:
+  entrypoint: main.py
+  envVars:
+    SERVICE_NS: payments
+`,
+		"main.py":           "print('hello')\n",
+		"my_view.view.yaml": "slug: my_view\nentrypoint: main.tsx\nenvFrom: [observability]\n",
+		"main.tsx":          "export default () => null;\n",
+	}
+	for name, content := range files {
+		require.NoError(os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+
+	client := &mock.MockClient{}
+	d := &Discoverer{
+		TaskDiscoverers: []TaskDiscoverer{
+			&DefnDiscoverer{Client: client, DisableNormalize: true, DoNotVerifyMissingTasks: true},
+		},
+		ViewDiscoverers: []ViewDiscoverer{
+			&ViewDefnDiscoverer{Client: client, DoNotVerifyMissingViews: true},
+		},
+		Client: client,
+		Logger: &logger.MockLogger{},
+	}
+	taskConfigs, viewConfigs, err := d.Discover(context.Background(), root)
+	require.NoError(err)
+
+	require.Len(taskConfigs, 1)
+	env, err := taskConfigs[0].Def.GetEnv()
+	require.NoError(err)
+	require.Equal(api.EnvVars{
+		"LOG_LEVEL":     api.EnvVarValue{Value: pointers.String("info")},
+		"OTEL_EXPORTER": api.EnvVarValue{Value: pointers.String("otlp")},
+		"SERVICE_NS":    api.EnvVarValue{Value: pointers.String("payments")},
+	}, env)
+
+	require.Len(viewConfigs, 1)
+	require.Equal(api.EnvVars{
+		"OTEL_EXPORTER": api.EnvVarValue{Value: pointers.String("otlp")},
+		"SERVICE_NS":    api.EnvVarValue{Value: pointers.String("default")},
+	}, viewConfigs[0].Def.EnvVars)
+
+	// Including an undefined set fails discovery.
+	require.NoError(os.WriteFile(filepath.Join(root, "my_view.view.yaml"), []byte("slug: my_view\nentrypoint: main.tsx\nenvFrom: [missing]\n"), 0644))
+	_, _, err = d.Discover(context.Background(), root)
+	require.ErrorContains(err, `env set "missing" is not defined`)
+}
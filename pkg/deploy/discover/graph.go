@@ -0,0 +1,267 @@
+package discover
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DependencyReason is why a task depends on another task.
+type DependencyReason string
+
+const (
+	// DependencyExecute is a call to airplane.execute in the task's code.
+	DependencyExecute DependencyReason = "execute"
+	// DependencyOptions is a parameter whose options are populated by running the other task.
+	DependencyOptions DependencyReason = "options"
+	// DependencyLink is a link in a view's definition.
+	DependencyLink DependencyReason = "link"
+)
+
+// Dependency is a task that another task calls.
+type Dependency struct {
+	Slug   string
+	Reason DependencyReason
+}
+
+// ErrTaskCycle is returned when tasks depend on each other in a cycle, so there's no order in
+// which each task is deployed after the tasks it calls.
+type ErrTaskCycle struct {
+	// Cycle is the slugs of the tasks in the cycle, starting and ending with the same task.
+	Cycle []string
+}
+
+func (e ErrTaskCycle) Error() string {
+	return "tasks depend on each other in a cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// TaskGraph is the graph of dependencies between the tasks being deployed. Dependencies on tasks
+// that aren't being deployed are omitted, since they're deployed already or not at all.
+type TaskGraph struct {
+	// slugs is the slug of every task in the graph, sorted.
+	slugs []string
+	// deps is the dependencies of each task, sorted by slug.
+	deps map[string][]Dependency
+}
+
+// executeCallRegexp matches calls to airplane.execute with a literal slug, in both the JavaScript
+// and Python SDKs, e.g. `airplane.execute<Output>("my_task", {...})`.
+var executeCallRegexp = regexp.MustCompile("\\bairplane\\.execute(?:<[^>()]*>)?\\(\\s*[\"'`]([A-Za-z0-9_-]+)[\"'`]")
+
+// NewTaskGraph builds the dependency graph of taskConfigs. Calls to airplane.execute are found by
+// scanning each task's entrypoint, so calls with a slug that isn't a string literal are missed.
+//
+// Calls between tasks with the same entrypoint aren't dependencies, since the tasks are deployed
+// together. This also keeps calls in a file that defines several tasks from being attributed to
+// each of them.
+func NewTaskGraph(taskConfigs []TaskConfig) (TaskGraph, error) {
+	g := TaskGraph{deps: map[string][]Dependency{}}
+	entrypoints := map[string]string{}
+	for _, tc := range taskConfigs {
+		g.slugs = append(g.slugs, tc.Def.GetSlug())
+		g.deps[tc.Def.GetSlug()] = nil
+		entrypoints[tc.Def.GetSlug()] = tc.TaskEntrypoint
+	}
+	sort.Strings(g.slugs)
+
+	contents := map[string]string{}
+	for _, tc := range taskConfigs {
+		slug := tc.Def.GetSlug()
+		calls, err := taskCalls(tc, contents)
+		if err != nil {
+			return TaskGraph{}, err
+		}
+		var deps []Dependency
+		for _, dep := range calls {
+			if _, ok := g.deps[dep.Slug]; !ok {
+				continue
+			}
+			if dep.Reason == DependencyExecute && entrypoints[dep.Slug] == tc.TaskEntrypoint {
+				continue
+			}
+			deps = append(deps, dep)
+		}
+		if deps == nil {
+			deps = []Dependency{}
+		}
+		g.deps[slug] = deps
+	}
+	return g, nil
+}
+
+// taskCalls returns the tasks that tc calls, other than itself, sorted by slug. contents caches
+// the contents of entrypoints, since tasks can share them.
+func taskCalls(tc TaskConfig, contents map[string]string) ([]Dependency, error) {
+	slug := tc.Def.GetSlug()
+	reasons := map[string]DependencyReason{}
+	add := func(callee string, reason DependencyReason) {
+		if callee == slug {
+			return
+		}
+		if _, ok := reasons[callee]; !ok {
+			reasons[callee] = reason
+		}
+	}
+
+	for _, p := range tc.Def.Parameters {
+		if p.OptionsSource != nil {
+			add(p.OptionsSource.Task, DependencyOptions)
+		}
+	}
+
+	if tc.TaskEntrypoint != "" {
+		content, ok := contents[tc.TaskEntrypoint]
+		if !ok {
+			buf, err := os.ReadFile(tc.TaskEntrypoint)
+			if err != nil && !os.IsNotExist(err) {
+				return nil, errors.Wrapf(err, "reading entrypoint of task %s", slug)
+			}
+			content = string(buf)
+			contents[tc.TaskEntrypoint] = content
+		}
+		for _, m := range executeCallRegexp.FindAllStringSubmatch(content, -1) {
+			add(m[1], DependencyExecute)
+		}
+	}
+
+	deps := make([]Dependency, 0, len(reasons))
+	for callee, reason := range reasons {
+		deps = append(deps, Dependency{Slug: callee, Reason: reason})
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Slug < deps[j].Slug
+	})
+	return deps, nil
+}
+
+// Caller is a task or view that calls a task.
+type Caller struct {
+	// Kind is "task" or "view".
+	Kind   string
+	Slug   string
+	Reason DependencyReason
+}
+
+// FindCallers returns the tasks and views that call the task with the given slug, tasks first and
+// each sorted by slug. Unlike the dependencies of a TaskGraph, the task doesn't need to be one of
+// taskConfigs. Calls by tasks are found as NewTaskGraph finds them, and calls by views are the
+// links of their definitions.
+func FindCallers(taskConfigs []TaskConfig, viewConfigs []ViewConfig, slug string) ([]Caller, error) {
+	var callers []Caller
+	contents := map[string]string{}
+	for _, tc := range taskConfigs {
+		calls, err := taskCalls(tc, contents)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range calls {
+			if dep.Slug == slug {
+				callers = append(callers, Caller{Kind: "task", Slug: tc.Def.GetSlug(), Reason: dep.Reason})
+			}
+		}
+	}
+	for _, vc := range viewConfigs {
+		for _, link := range vc.Def.Links {
+			if link.Task == slug {
+				callers = append(callers, Caller{Kind: "view", Slug: vc.Def.Slug, Reason: DependencyLink})
+				break
+			}
+		}
+	}
+	sort.SliceStable(callers, func(i, j int) bool {
+		if callers[i].Kind != callers[j].Kind {
+			return callers[i].Kind == "task"
+		}
+		return callers[i].Slug < callers[j].Slug
+	})
+	return callers, nil
+}
+
+// Dependencies returns the tasks that the task with the given slug calls.
+func (g TaskGraph) Dependencies(slug string) []Dependency {
+	return g.deps[slug]
+}
+
+// Order returns the slugs of the tasks in the graph, ordered so that each task comes after the
+// tasks it calls. The order only depends on the graph. If the tasks depend on each other in a cycle, an
+// ErrTaskCycle is returned.
+func (g TaskGraph) Order() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var order, stack []string
+	var visit func(slug string) error
+	visit = func(slug string) error {
+		switch state[slug] {
+		case visited:
+			return nil
+		case visiting:
+			for i, s := range stack {
+				if s == slug {
+					return ErrTaskCycle{Cycle: append(append([]string{}, stack[i:]...), slug)}
+				}
+			}
+		}
+		state[slug] = visiting
+		stack = append(stack, slug)
+		for _, dep := range g.deps[slug] {
+			if err := visit(dep.Slug); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[slug] = visited
+		order = append(order, slug)
+		return nil
+	}
+
+	for _, slug := range g.slugs {
+		if err := visit(slug); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Levels returns the level of each task in the graph: tasks that don't call other tasks have
+// level 0, and every other task has a level one higher than the highest level of the tasks it
+// calls. The graph must not have cycles, see Order.
+func (g TaskGraph) Levels() (map[string]int, error) {
+	order, err := g.Order()
+	if err != nil {
+		return nil, err
+	}
+	levels := map[string]int{}
+	for _, slug := range order {
+		for _, dep := range g.deps[slug] {
+			if l := levels[dep.Slug] + 1; l > levels[slug] {
+				levels[slug] = l
+			}
+		}
+	}
+	return levels, nil
+}
+
+// String formats the graph with one line per task, in deploy order, followed by an indented line
+// per task that it calls.
+func (g TaskGraph) String() string {
+	order, err := g.Order()
+	if err != nil {
+		order = g.slugs
+	}
+	var b strings.Builder
+	for _, slug := range order {
+		fmt.Fprintln(&b, slug)
+		for _, dep := range g.deps[slug] {
+			fmt.Fprintf(&b, "  -> %s (%s)\n", dep.Slug, dep.Reason)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskGraph(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		require.NoError(os.WriteFile(p, []byte(content), 0644))
+		return p
+	}
+
+	taskConfigs := []TaskConfig{
+		{
+			Def: definitions.Definition{Slug: "notify"},
+			TaskEntrypoint: write("notify.airplane.ts", `
+				export default airplane.task({slug: "notify"}, async () => {
+					await airplane.execute("notify", {});
+				});
+			`),
+		},
+		{
+			Def: definitions.Definition{Slug: "refund"},
+			TaskEntrypoint: write("refund.airplane.ts", `
+				export default airplane.task({slug: "refund"}, async () => {
+					await airplane.execute<Output>("lookup_customer", {});
+					await airplane.execute('notify', {});
+					await airplane.execute("external_task", {});
+				});
+			`),
+		},
+		{
+			Def:            definitions.Definition{Slug: "lookup_customer"},
+			TaskEntrypoint: write("lookup_customer.py", `airplane.execute("list_customers")`),
+		},
+		{
+			Def: definitions.Definition{
+				Slug: "list_customers",
+				Parameters: []definitions.ParameterDefinition{
+					{Slug: "region", Type: "shorttext", OptionsSource: &definitions.OptionsSourceDefinition{Task: "list_regions"}},
+				},
+			},
+		},
+		{Def: definitions.Definition{Slug: "list_regions"}},
+	}
+
+	g, err := NewTaskGraph(taskConfigs)
+	require.NoError(err)
+	require.Equal([]Dependency{
+		{Slug: "lookup_customer", Reason: DependencyExecute},
+		{Slug: "notify", Reason: DependencyExecute},
+	}, g.Dependencies("refund"))
+	require.Equal([]Dependency{{Slug: "list_regions", Reason: DependencyOptions}}, g.Dependencies("list_customers"))
+	require.Empty(g.Dependencies("notify"))
+
+	order, err := g.Order()
+	require.NoError(err)
+	require.Equal([]string{"list_regions", "list_customers", "lookup_customer", "notify", "refund"}, order)
+
+	levels, err := g.Levels()
+	require.NoError(err)
+	require.Equal(map[string]int{"list_customers": 1, "lookup_customer": 2, "refund": 3}, levels)
+
+	require.Contains(g.String(), "refund\n  -> lookup_customer (execute)\n  -> notify (execute)\n")
+}
+
+func TestTaskGraphCycle(t *testing.T) {
+	require := require.New(t)
+
+	g, err := NewTaskGraph([]TaskConfig{
+		{Def: definitions.Definition{Slug: "a", Parameters: []definitions.ParameterDefinition{
+			{Slug: "p", Type: "shorttext", OptionsSource: &definitions.OptionsSourceDefinition{Task: "b"}},
+		}}},
+		{Def: definitions.Definition{Slug: "b", Parameters: []definitions.ParameterDefinition{
+			{Slug: "p", Type: "shorttext", OptionsSource: &definitions.OptionsSourceDefinition{Task: "a"}},
+		}}},
+	})
+	require.NoError(err)
+
+	_, err = g.Order()
+	var cerr ErrTaskCycle
+	require.ErrorAs(err, &cerr)
+	require.Equal([]string{"a", "b", "a"}, cerr.Cycle)
+
+	_, err = g.Levels()
+	require.Error(err)
+}
+
+func TestFindCallers(t *testing.T) {
+	require := require.New(t)
+	entrypoint := filepath.Join(t.TempDir(), "refund.airplane.ts")
+	require.NoError(os.WriteFile(entrypoint, []byte(`await airplane.execute("notify", {});`), 0644))
+
+	taskConfigs := []TaskConfig{
+		{Def: definitions.Definition{Slug: "refund"}, TaskEntrypoint: entrypoint},
+		{Def: definitions.Definition{Slug: "list_customers", Parameters: []definitions.ParameterDefinition{
+			{Slug: "region", Type: "shorttext", OptionsSource: &definitions.OptionsSourceDefinition{Task: "notify"}},
+		}}},
+		{Def: definitions.Definition{Slug: "lookup_customer"}},
+	}
+	viewConfigs := []ViewConfig{
+		{Def: definitions.ViewDefinition{Slug: "support", Links: map[string]definitions.ViewLinkDefinition{
+			"notify":  {Task: "notify"},
+			"refund2": {Task: "refund"},
+		}}},
+		{Def: definitions.ViewDefinition{Slug: "dashboard"}},
+	}
+
+	// notify isn't one of the tasks, e.g. because it's deployed from another project.
+	callers, err := FindCallers(taskConfigs, viewConfigs, "notify")
+	require.NoError(err)
+	require.Equal([]Caller{
+		{Kind: "task", Slug: "list_customers", Reason: DependencyOptions},
+		{Kind: "task", Slug: "refund", Reason: DependencyExecute},
+		{Kind: "view", Slug: "support", Reason: DependencyLink},
+	}, callers)
+
+	callers, err = FindCallers(taskConfigs, viewConfigs, "lookup_customer")
+	require.NoError(err)
+	require.Empty(callers)
+}
@@ -0,0 +1,116 @@
+package discover
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidViewLinks is returned when the links of discovered views don't match the tasks they
+// refer to.
+type ErrInvalidViewLinks struct {
+	Issues []string
+}
+
+func (e ErrInvalidViewLinks) Error() string {
+	return "invalid view links:\n  " + strings.Join(e.Issues, "\n  ")
+}
+
+// ResolveViewLinks checks that the tasks linked by viewConfigs exist and accept the parameters
+// that the links pass. Linked tasks are looked up in taskConfigs first, and otherwise in envSlug
+// via client, if set.
+//
+// The parameters of each linked task are returned, keyed by task slug.
+func ResolveViewLinks(ctx context.Context, client api.IAPIClient, envSlug string, taskConfigs []TaskConfig, viewConfigs []ViewConfig) (map[string]api.Parameters, error) {
+	local := map[string]TaskConfig{}
+	for _, tc := range taskConfigs {
+		local[tc.Def.GetSlug()] = tc
+	}
+
+	params := map[string]api.Parameters{}
+	var issues []string
+	for _, vc := range viewConfigs {
+		names := make([]string, 0, len(vc.Def.Links))
+		for name := range vc.Def.Links {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			link := vc.Def.Links[name]
+			issuef := func(format string, args ...interface{}) {
+				issues = append(issues, fmt.Sprintf("view %s, link %s: ", vc.Def.Slug, name)+fmt.Sprintf(format, args...))
+			}
+
+			taskParams, ok := params[link.Task]
+			if !ok {
+				var err error
+				taskParams, ok, err = lookupTaskParameters(ctx, client, envSlug, local, link.Task)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					issuef("task %s does not exist", link.Task)
+					continue
+				}
+				params[link.Task] = taskParams
+			}
+
+			passed := map[string]bool{}
+			for arg, paramSlug := range link.Params {
+				if !hasParameter(taskParams, paramSlug) {
+					issuef("task %s has no parameter %s (passed as %s)", link.Task, paramSlug, arg)
+				}
+				passed[paramSlug] = true
+			}
+			for _, p := range taskParams {
+				// Booleans always have a value, since unset booleans are false.
+				if !passed[p.Slug] && !p.Constraints.Optional && p.Default == nil && p.Type != api.TypeBoolean {
+					issuef("required parameter %s of task %s is not passed", p.Slug, link.Task)
+				}
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		sort.Strings(issues)
+		return nil, ErrInvalidViewLinks{Issues: issues}
+	}
+	return params, nil
+}
+
+func lookupTaskParameters(ctx context.Context, client api.IAPIClient, envSlug string, local map[string]TaskConfig, slug string) (api.Parameters, bool, error) {
+	if tc, ok := local[slug]; ok {
+		params, err := tc.Def.GetParameters()
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "getting parameters of task %s", slug)
+		}
+		return params, true, nil
+	}
+
+	if client == nil {
+		return nil, false, nil
+	}
+	task, err := client.GetTask(ctx, api.GetTaskRequest{Slug: slug, EnvSlug: envSlug})
+	if err != nil {
+		var merr *api.TaskMissingError
+		if errors.As(err, &merr) {
+			return nil, false, nil
+		}
+		return nil, false, errors.Wrapf(err, "getting task %s", slug)
+	}
+	return task.Parameters, true, nil
+}
+
+func hasParameter(params api.Parameters, slug string) bool {
+	for _, p := range params {
+		if p.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package discover
+
+import (
+	"context"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/api/mock"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveViewLinks(t *testing.T) {
+	taskConfigs := []TaskConfig{
+		{
+			Def: definitions.Definition{
+				Slug: "issue_refund",
+				Parameters: []definitions.ParameterDefinition{
+					{Slug: "customer_email", Type: "shorttext"},
+					{Slug: "amount", Type: "float", Default: 10},
+					{Slug: "notify", Type: "boolean"},
+				},
+			},
+		},
+	}
+	client := &mock.MockClient{
+		Tasks: map[string]api.Task{
+			"list_orders": {
+				Slug: "list_orders",
+				Parameters: api.Parameters{
+					{Slug: "limit", Type: api.TypeInteger, Constraints: api.Constraints{Optional: true}},
+				},
+			},
+		},
+	}
+	view := func(links map[string]definitions.ViewLinkDefinition) []ViewConfig {
+		return []ViewConfig{{Def: definitions.ViewDefinition{Slug: "support", Links: links}}}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require := require.New(t)
+		params, err := ResolveViewLinks(context.Background(), client, "", taskConfigs, view(map[string]definitions.ViewLinkDefinition{
+			"refund": {Task: "issue_refund", Params: map[string]string{"email": "customer_email"}},
+			"orders": {Task: "list_orders"},
+		}))
+		require.NoError(err)
+		require.Len(params["issue_refund"], 3)
+		require.Len(params["list_orders"], 1)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require := require.New(t)
+		_, err := ResolveViewLinks(context.Background(), client, "", taskConfigs, view(map[string]definitions.ViewLinkDefinition{
+			"refund":  {Task: "issue_refund", Params: map[string]string{"email": "email"}},
+			"archive": {Task: "archive_orders"},
+		}))
+		var lerr ErrInvalidViewLinks
+		require.ErrorAs(err, &lerr)
+		require.Equal([]string{
+			"view support, link archive: task archive_orders does not exist",
+			"view support, link refund: required parameter customer_email of task issue_refund is not passed",
+			"view support, link refund: task issue_refund has no parameter email (passed as email)",
+		}, lerr.Issues)
+	})
+}
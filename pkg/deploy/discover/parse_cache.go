@@ -0,0 +1,158 @@
+package discover
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/airplanedev/cli/pkg/deploy/discover/parser"
+	"github.com/pkg/errors"
+)
+
+// ParseCache persists the configs that parsers extract from files, so that files that haven't
+// changed aren't parsed again, e.g. when `airplane dev` restarts. Parsing a file spawns node,
+// python or deno, which dominates the time that discovery takes.
+//
+// Entries are keyed by the contents of the parsed file, so a file is parsed again whenever it
+// changes. Only JS files are cached, since they are parsed after being compiled together with
+// the local files they import; Python and Deno parsers run imports that the key wouldn't cover.
+// The cache is discarded when the CLI version or the parser scripts change.
+//
+// A nil *ParseCache is valid and caches nothing.
+type ParseCache struct {
+	path    string
+	version string
+
+	mu      sync.Mutex
+	entries map[string]json.RawMessage
+	// used are the keys of entries that were read or written since the cache was opened. Only
+	// these are saved, so that entries of old versions of files don't accumulate.
+	used map[string]bool
+}
+
+type parseCacheFile struct {
+	Version string                     `json:"version"`
+	Entries map[string]json.RawMessage `json:"entries"`
+}
+
+// OpenParseCache opens the cache at path for the given CLI version. The cache is empty if path
+// doesn't exist, can't be read or is of another version.
+func OpenParseCache(path, cliVersion string) *ParseCache {
+	c := &ParseCache{
+		path:    path,
+		version: parseCacheVersion(cliVersion),
+		entries: map[string]json.RawMessage{},
+		used:    map[string]bool{},
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var f parseCacheFile
+	if err := json.Unmarshal(buf, &f); err != nil || f.Version != c.version {
+		return c
+	}
+	if f.Entries != nil {
+		c.entries = f.Entries
+	}
+	return c
+}
+
+// parseCacheVersion returns the version of cache entries. The parser scripts are part of the
+// version, since development builds of the CLI don't have a version.
+func parseCacheVersion(cliVersion string) string {
+	h := sha256.New()
+	for _, s := range []string{cliVersion, parser.NodeParserScript, parser.PythonParserScript, parser.DenoParserScript} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Key returns the key of the configs that are discovered in src, by running a parser on parsed
+// with the given environment. parsed may differ from src, e.g. if it's src compiled with its
+// imports. It returns "" if c is nil or parsed can't be read, in which case nothing is cached.
+func (c *ParseCache) Key(src, parsed string, env []string) string {
+	if c == nil {
+		return ""
+	}
+	buf, err := os.ReadFile(parsed)
+	if err != nil {
+		return ""
+	}
+	// The order of env vars doesn't affect parsing.
+	env = append([]string(nil), env...)
+	sort.Strings(env)
+	h := sha256.New()
+	h.Write([]byte(src))
+	h.Write([]byte{0})
+	for _, e := range env {
+		h.Write([]byte(e))
+		h.Write([]byte{0})
+	}
+	h.Write(buf)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get unmarshals the entry for key into v, and returns whether there was one.
+func (c *ParseCache) Get(key string, v interface{}) bool {
+	if c == nil || key == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	buf, ok := c.entries[key]
+	if !ok || json.Unmarshal(buf, v) != nil {
+		return false
+	}
+	c.used[key] = true
+	return true
+}
+
+// Put sets the entry for key to v. v is marshaled immediately, so it can be modified afterwards.
+func (c *ParseCache) Put(key string, v interface{}) {
+	if c == nil || key == "" {
+		return
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = buf
+	c.used[key] = true
+}
+
+// Save writes the entries that were used since the cache was opened to its path.
+func (c *ParseCache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	f := parseCacheFile{
+		Version: c.version,
+		Entries: make(map[string]json.RawMessage, len(c.used)),
+	}
+	for key := range c.used {
+		f.Entries[key] = c.entries[key]
+	}
+	c.mu.Unlock()
+
+	buf, err := json.Marshal(f)
+	if err != nil {
+		return errors.Wrap(err, "marshaling discovery cache")
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return errors.Wrap(err, "creating discovery cache directory")
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0644); err != nil {
+		return errors.Wrap(err, "writing discovery cache")
+	}
+	return errors.Wrap(os.Rename(tmp, c.path), "writing discovery cache")
+}
@@ -0,0 +1,51 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCache(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "my_task_airplane.py")
+	require.NoError(os.WriteFile(file, []byte("print('v1')"), 0644))
+	path := filepath.Join(dir, "cache", "discover.json")
+
+	c := OpenParseCache(path, "1.0.0")
+	key := c.Key(file, file, []string{"A=1", "B=2"})
+	require.NotEmpty(key)
+	// The order of env vars doesn't matter, but their values do.
+	require.Equal(key, c.Key(file, file, []string{"B=2", "A=1"}))
+	require.NotEqual(key, c.Key(file, file, []string{"A=1", "B=3"}))
+
+	var configs []map[string]interface{}
+	require.False(c.Get(key, &configs))
+	c.Put(key, []map[string]interface{}{{"slug": "my_task"}})
+	c.Put(c.Key(file, file, nil), []map[string]interface{}{{"slug": "unused"}})
+	require.NoError(c.Save())
+
+	// Entries outlive the cache, as long as they're used.
+	c = OpenParseCache(path, "1.0.0")
+	require.True(c.Get(key, &configs))
+	require.Equal([]map[string]interface{}{{"slug": "my_task"}}, configs)
+	require.NoError(c.Save())
+	c = OpenParseCache(path, "1.0.0")
+	require.False(c.Get(c.Key(file, file, nil), &configs))
+
+	// Entries are invalidated by changes to the file or the CLI version.
+	require.NoError(os.WriteFile(file, []byte("print('v2')"), 0644))
+	require.NotEqual(key, c.Key(file, file, []string{"A=1", "B=2"}))
+	require.False(OpenParseCache(path, "1.1.0").Get(key, &configs))
+
+	// A nil cache caches nothing.
+	var nilCache *ParseCache
+	require.Empty(nilCache.Key(file, file, nil))
+	nilCache.Put(key, configs)
+	require.False(nilCache.Get(key, &configs))
+	require.NoError(nilCache.Save())
+}
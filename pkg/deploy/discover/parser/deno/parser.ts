@@ -0,0 +1,111 @@
+// Extracts the task configs of an inline Deno task file. Unlike the Node parser, this file is run
+// directly by Deno and does not need to be built.
+
+type TaskParam = {
+  slug: string;
+  name: string;
+  type: string;
+  description?: string;
+  default?: any;
+  required?: boolean;
+  options?: any[];
+  regex?: string;
+};
+
+type DenoDef = {
+  envVars?: Record<string, string | { config: string } | { value: string }>;
+  entrypoint: string;
+};
+
+type TaskDef = {
+  slug: string;
+  deno: DenoDef;
+  name?: string;
+  description?: string;
+  parameters?: TaskParam[];
+  requireRequests?: boolean;
+  allowSelfApprovals?: boolean;
+  restrictCallers?: string[];
+  timeout?: number;
+  constraints?: Record<string, string>;
+  resources: Record<string, string> | string[];
+  schedules: Record<string, any>;
+  defaultRunPermissions?: "task-viewers" | "task-participants";
+  concurrencyKey?: string;
+  concurrencyLimit?: number;
+};
+
+type TaskDefWithBuildArgs = TaskDef & {
+  entrypointFunc: string;
+};
+
+const extractTaskConfigs = async (
+  files: string[]
+): Promise<TaskDefWithBuildArgs[]> => {
+  const taskConfigs: TaskDefWithBuildArgs[] = [];
+  for (const file of files) {
+    const exports = await import(`file://${file}`);
+
+    for (const exportName in exports) {
+      const item = exports[exportName];
+      if (
+        (typeof item === "object" || typeof item === "function") &&
+        item !== null &&
+        "__airplane" in item &&
+        item.__airplane.type !== "view"
+      ) {
+        const config = item.__airplane.config;
+        const params: TaskParam[] = [];
+        for (const uParamSlug in config.parameters) {
+          const uParamConfig = config.parameters[uParamSlug];
+
+          if (typeof uParamConfig === "string") {
+            params.push({
+              slug: uParamSlug,
+              name: uParamSlug,
+              type: uParamConfig,
+            });
+          } else {
+            params.push({
+              slug: uParamSlug,
+              // Default to slug if name is not provided.
+              name: uParamConfig["name"] || uParamSlug,
+              type: uParamConfig["type"],
+              description: uParamConfig["description"],
+              default: uParamConfig["default"],
+              required: uParamConfig["required"],
+              options: uParamConfig["options"],
+              regex: uParamConfig["regex"],
+            });
+          }
+        }
+
+        taskConfigs.push({
+          slug: config.slug,
+          name: config.name ?? config.slug,
+          description: config.description,
+          requireRequests: config.requireRequests,
+          allowSelfApprovals: config.allowSelfApprovals,
+          restrictCallers: config.restrictCallers,
+          timeout: config.timeout,
+          constraints: config.constraints,
+          defaultRunPermissions: config.defaultRunPermissions,
+          concurrencyKey: config.concurrencyKey,
+          concurrencyLimit: config.concurrencyLimit,
+          resources: config.resources,
+          schedules: config.schedules,
+          parameters: params,
+          entrypointFunc: exportName,
+          deno: {
+            envVars: config.envVars,
+            entrypoint: file,
+          },
+        });
+      }
+    }
+  }
+  return taskConfigs;
+};
+
+const taskConfigs = await extractTaskConfigs(Deno.args);
+console.log("EXTRACTED_ENTITY_CONFIGS:" + JSON.stringify(taskConfigs));
@@ -0,0 +1,317 @@
+// golang extracts the tasks declared in Go files with airplane.RegisterTask, e.g.
+//
+//	var _ = airplane.RegisterTask(airplane.TaskConfig{
+//		Slug: "hello",
+//		Parameters: []airplane.Param{
+//			{Slug: "name", Type: "shorttext", Optional: true},
+//		},
+//	}, Hello)
+//
+// Unlike the Node and Python parsers, files are parsed rather than executed, so the config must be
+// a literal: strings, numbers, booleans, constants declared in the same file, and composite
+// literals of those.
+//
+// Every task in a package runs the package's binary, with the name of the task's function and its
+// JSON-encoded parameters as arguments. Tasks must therefore be registered in a main package whose
+// main function hands over to the SDK, which runs the registered function of that name.
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// PackageName is the name of the package that declares RegisterTask. It's matched against the
+	// last element of import paths, so that the SDK can be imported from any module path.
+	PackageName = "airplane"
+	// RegisterFunc is the name of the function that declares a task.
+	RegisterFunc = "RegisterTask"
+)
+
+// Parse returns the task configs declared in the Go file with the given source. Configs are in the
+// same format as the output of the Node and Python parsers: task definitions with an additional
+// "entrypointFunc" key, the name of the function that implements the task.
+func Parse(filename string, src []byte) ([]map[string]interface{}, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing file")
+	}
+
+	pkg := importName(f)
+	if pkg == "" {
+		return nil, nil
+	}
+	p := &fileParser{fset: fset, consts: constants(f), main: f.Name.Name == "main"}
+
+	var configs []map[string]interface{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !isRegisterCall(call, pkg) {
+			return true
+		}
+		if !p.main {
+			err = p.errorf(call, "expected tasks to be registered in a main package, got package %s", f.Name.Name)
+			return false
+		}
+		var config map[string]interface{}
+		config, err = p.taskConfig(call)
+		if err != nil {
+			return false
+		}
+		configs = append(configs, config)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// importName returns the name that the file refers to the airplane package by, or an empty string
+// if it isn't imported.
+func importName(f *ast.File) string {
+	for _, imp := range f.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || path[strings.LastIndex(path, "/")+1:] != PackageName {
+			continue
+		}
+		if imp.Name != nil {
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				continue
+			}
+			return imp.Name.Name
+		}
+		return PackageName
+	}
+	return ""
+}
+
+func isRegisterCall(call *ast.CallExpr, pkg string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != RegisterFunc {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == pkg
+}
+
+// constants returns the package-level constants declared in the file, by name.
+func constants(f *ast.File) map[string]ast.Expr {
+	consts := map[string]ast.Expr{}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Values) != len(vs.Names) {
+				// Constants that are implicitly repeated, e.g. with iota, aren't supported.
+				continue
+			}
+			for i, name := range vs.Names {
+				consts[name.Name] = vs.Values[i]
+			}
+		}
+	}
+	return consts
+}
+
+type fileParser struct {
+	fset   *token.FileSet
+	consts map[string]ast.Expr
+	// main is whether the file is in a main package.
+	main bool
+}
+
+func (p *fileParser) errorf(n ast.Node, format string, args ...interface{}) error {
+	return errors.Errorf("%s: %s", p.fset.Position(n.Pos()), fmt.Sprintf(format, args...))
+}
+
+// taskConfig converts a call to RegisterTask into a task config.
+func (p *fileParser) taskConfig(call *ast.CallExpr) (map[string]interface{}, error) {
+	if len(call.Args) != 2 {
+		return nil, p.errorf(call, "expected %s to be called with a config and a function", RegisterFunc)
+	}
+
+	var entrypointFunc string
+	switch fn := call.Args[1].(type) {
+	case *ast.Ident:
+		entrypointFunc = fn.Name
+	case *ast.SelectorExpr:
+		entrypointFunc = fn.Sel.Name
+	default:
+		return nil, p.errorf(fn, "expected the function of a task to be a named function")
+	}
+
+	v, err := p.value(call.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	config, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, p.errorf(call.Args[0], "expected the config of a task to be a struct literal")
+	}
+
+	if params, ok := config["parameters"].([]interface{}); ok {
+		for _, param := range params {
+			if param, ok := param.(map[string]interface{}); ok {
+				// Parameters are required unless they're marked as optional, so that the zero
+				// value of the field matches the default of task definitions.
+				if optional, ok := param["optional"].(bool); ok {
+					delete(param, "optional")
+					if optional {
+						param["required"] = false
+					}
+				}
+			}
+		}
+	}
+
+	goConfig := map[string]interface{}{}
+	if envVars, ok := config["envVars"]; ok {
+		delete(config, "envVars")
+		goConfig["envVars"] = envVars
+	}
+	config["go"] = goConfig
+	config["entrypointFunc"] = entrypointFunc
+	return config, nil
+}
+
+// value evaluates a literal expression. Struct literals are converted into maps whose keys are the
+// names of the fields, starting with a lowercase letter.
+func (p *fileParser) value(expr ast.Expr) (interface{}, error) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return p.value(e.X)
+
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			return strconv.Unquote(e.Value)
+		case token.INT:
+			return strconv.ParseInt(e.Value, 0, 64)
+		case token.FLOAT:
+			return strconv.ParseFloat(e.Value, 64)
+		}
+
+	case *ast.UnaryExpr:
+		if e.Op == token.SUB {
+			v, err := p.value(e.X)
+			if err != nil {
+				return nil, err
+			}
+			switch v := v.(type) {
+			case int64:
+				return -v, nil
+			case float64:
+				return -v, nil
+			}
+		}
+
+	case *ast.Ident:
+		switch e.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "nil":
+			return nil, nil
+		}
+		if c, ok := p.consts[e.Name]; ok {
+			// Remove the constant while it's evaluated, in case it refers to itself.
+			delete(p.consts, e.Name)
+			defer func() { p.consts[e.Name] = c }()
+			return p.value(c)
+		}
+		return nil, p.errorf(e, "%s is not a constant declared in this file", e.Name)
+
+	case *ast.CompositeLit:
+		return p.compositeValue(e)
+	}
+	return nil, p.errorf(expr, "unsupported expression: expected a literal")
+}
+
+func (p *fileParser) compositeValue(lit *ast.CompositeLit) (interface{}, error) {
+	isMap := false
+	switch t := lit.Type.(type) {
+	case *ast.ArrayType:
+		values := make([]interface{}, 0, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			v, err := p.value(elt)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	case *ast.MapType:
+		isMap = true
+	case nil:
+		// The type of nested literals may be elided, in which case it's inferred from the keys.
+		isMap = len(lit.Elts) > 0
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return p.compositeValue(&ast.CompositeLit{Type: &ast.ArrayType{}, Elts: lit.Elts})
+			}
+			if _, ok := kv.Key.(*ast.Ident); ok {
+				isMap = false
+			}
+		}
+	case *ast.Ident, *ast.SelectorExpr:
+		// A struct, e.g. airplane.Param.
+	default:
+		return nil, p.errorf(t, "unsupported type: expected a struct, slice or map")
+	}
+
+	values := map[string]interface{}{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, p.errorf(elt, "expected fields to be set by name")
+		}
+		var key string
+		if isMap {
+			k, err := p.value(kv.Key)
+			if err != nil {
+				return nil, err
+			}
+			if key, ok = k.(string); !ok {
+				return nil, p.errorf(kv.Key, "expected a string key")
+			}
+		} else {
+			ident, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				return nil, p.errorf(kv.Key, "expected a field name")
+			}
+			key = lowerFirst(ident.Name)
+		}
+		v, err := p.value(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = v
+	}
+	return values, nil
+}
+
+// lowerFirst converts the name of an exported field into the corresponding key of a task
+// definition, e.g. RequireRequests to requireRequests.
+func lowerFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
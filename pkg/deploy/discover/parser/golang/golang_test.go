@@ -0,0 +1,156 @@
+package golang
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+
+	configs, err := Parse("tasks_airplane.go", []byte(`package main
+
+import (
+	"context"
+
+	ap "github.com/airplanedev/go-sdk/airplane"
+)
+
+const defaultName = "World"
+
+var _ = ap.RegisterTask(ap.TaskConfig{
+	Slug:        "hello",
+	Name:        "Hello",
+	Description: "Says hello.",
+	Parameters: []ap.Param{
+		{Slug: "name", Type: "shorttext", Default: defaultName},
+		{Slug: "count", Type: "integer", Optional: true, Options: []interface{}{1, 2, -3}},
+	},
+	Resources:       map[string]string{"db": "prod_db"},
+	RequireRequests: true,
+	Timeout:         60,
+	EnvVars: map[string]ap.EnvVar{
+		"LOG_LEVEL": {Value: "debug"},
+	},
+	Schedules: map[string]ap.Schedule{
+		"daily": {Cron: "0 0 * * *", ParamValues: map[string]interface{}{"name": "Bot"}},
+	},
+}, Hello)
+
+func init() {
+	ap.RegisterTask(ap.TaskConfig{Slug: "bye"}, tasks.Bye)
+}
+
+func Hello(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	return nil, nil
+}
+`))
+	require.NoError(err)
+	require.Equal([]map[string]interface{}{
+		{
+			"entrypointFunc": "Hello",
+			"slug":           "hello",
+			"name":           "Hello",
+			"description":    "Says hello.",
+			"parameters": []interface{}{
+				map[string]interface{}{"slug": "name", "type": "shorttext", "default": "World"},
+				map[string]interface{}{
+					"slug":     "count",
+					"type":     "integer",
+					"required": false,
+					"options":  []interface{}{int64(1), int64(2), int64(-3)},
+				},
+			},
+			"resources":       map[string]interface{}{"db": "prod_db"},
+			"requireRequests": true,
+			"timeout":         int64(60),
+			"go": map[string]interface{}{
+				"envVars": map[string]interface{}{
+					"LOG_LEVEL": map[string]interface{}{"value": "debug"},
+				},
+			},
+			"schedules": map[string]interface{}{
+				"daily": map[string]interface{}{
+					"cron":        "0 0 * * *",
+					"paramValues": map[string]interface{}{"name": "Bot"},
+				},
+			},
+		},
+		{
+			"entrypointFunc": "Bye",
+			"slug":           "bye",
+			"go":             map[string]interface{}{},
+		},
+	}, configs)
+}
+
+func TestParseNoImport(t *testing.T) {
+	require := require.New(t)
+
+	// RegisterTask of another package is ignored.
+	configs, err := Parse("main_airplane.go", []byte(`package main
+
+import "example.com/registry"
+
+func main() {
+	registry.RegisterTask(registry.TaskConfig{Slug: "hello"}, Hello)
+}
+`))
+	require.NoError(err)
+	require.Empty(configs)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		pkg  string
+		call string
+		err  string
+	}{
+		{
+			name: "func literal",
+			call: `airplane.RegisterTask(airplane.TaskConfig{Slug: "hello"}, func() {})`,
+			err:  "expected the function of a task to be a named function",
+		},
+		{
+			name: "variable",
+			call: `airplane.RegisterTask(airplane.TaskConfig{Slug: slug}, Hello)`,
+			err:  "slug is not a constant declared in this file",
+		},
+		{
+			name: "function call",
+			call: `airplane.RegisterTask(airplane.TaskConfig{Slug: strings.ToLower("Hello")}, Hello)`,
+			err:  "unsupported expression",
+		},
+		{
+			name: "config",
+			call: `airplane.RegisterTask(config, Hello)`,
+			err:  "config is not a constant declared in this file",
+		},
+		{
+			name: "library package",
+			pkg:  "tasks",
+			call: `airplane.RegisterTask(airplane.TaskConfig{Slug: "hello"}, Hello)`,
+			err:  "expected tasks to be registered in a main package, got package tasks",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			pkg := test.pkg
+			if pkg == "" {
+				pkg = "main"
+			}
+			_, err := Parse("tasks_airplane.go", []byte(`package `+pkg+`
+
+import "github.com/airplanedev/go-sdk/airplane"
+
+func init() {
+	`+test.call+`
+}
+`))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+			require.Contains(t, err.Error(), "tasks_airplane.go:6:")
+		})
+	}
+}
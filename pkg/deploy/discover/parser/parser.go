@@ -7,3 +7,6 @@ var NodeParserScript string
 
 //go:embed python/parser.py
 var PythonParserScript string
+
+//go:embed deno/parser.ts
+var DenoParserScript string
@@ -31,6 +31,7 @@ class Schedule:
     description: Optional[str]
     cron: str
     paramValues: Dict[str, Any]
+    paused: bool
 
 
 @dataclasses.dataclass
@@ -143,6 +144,7 @@ def extract_task_configs(files: List[str]) -> List[Def]:
                                 description=s.description,
                                 cron=s.cron,
                                 paramValues=s.param_values,
+                                paused=getattr(s, "paused", False),
                             )
                             for s in conf.schedules or []
                         },
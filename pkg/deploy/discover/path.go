@@ -11,6 +11,8 @@ import (
 
 type TaskPathMetadata struct {
 	AbsEntrypoint string
+	// RelEntrypoint is the slash-separated path of the entrypoint relative to RootDir, since it's
+	// used in build configs.
 	RelEntrypoint string
 	RootDir       string
 	WorkDir       string
@@ -51,7 +53,7 @@ func taskPathMetadata(file string, kind buildtypes.TaskKind) (TaskPathMetadata,
 
 	return TaskPathMetadata{
 		AbsEntrypoint: absFile,
-		RelEntrypoint: ep,
+		RelEntrypoint: filepath.ToSlash(ep),
 		RootDir:       taskroot,
 		WorkDir:       wd,
 		Runtime:       r,
@@ -0,0 +1,26 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskPathMetadataPaths(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(root, "package.json"), []byte("{}"), 0644))
+	file := filepath.Join(root, "tasks", "my_task.airplane.ts")
+	require.NoError(os.MkdirAll(filepath.Dir(file), 0755))
+	require.NoError(os.WriteFile(file, []byte(""), 0644))
+
+	pm, err := taskPathMetadata(file, buildtypes.TaskKindNode)
+	require.NoError(err)
+	// The entrypoint is used in build configs, so it's slash-separated.
+	require.Equal("tasks/my_task.airplane.ts", pm.RelEntrypoint)
+	require.Equal(file, pm.AbsEntrypoint)
+}
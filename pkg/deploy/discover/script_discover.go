@@ -2,16 +2,20 @@ package discover
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/airplanedev/cli/pkg/api"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/definitions"
 	"github.com/airplanedev/cli/pkg/runtime"
 	_ "github.com/airplanedev/cli/pkg/runtime/builtin"
+	_ "github.com/airplanedev/cli/pkg/runtime/deno"
+	"github.com/airplanedev/cli/pkg/runtime/golang"
 	_ "github.com/airplanedev/cli/pkg/runtime/image"
 	_ "github.com/airplanedev/cli/pkg/runtime/javascript"
 	_ "github.com/airplanedev/cli/pkg/runtime/python"
 	_ "github.com/airplanedev/cli/pkg/runtime/rest"
+	_ "github.com/airplanedev/cli/pkg/runtime/ruby"
 	_ "github.com/airplanedev/cli/pkg/runtime/shell"
 	_ "github.com/airplanedev/cli/pkg/runtime/sql"
 	_ "github.com/airplanedev/cli/pkg/runtime/typescript"
@@ -27,8 +31,17 @@ type ScriptDiscoverer struct {
 
 var _ TaskDiscoverer = &ScriptDiscoverer{}
 
+// scriptSlug returns the slug of the task that file is linked to, if any. Go tasks may only be
+// linked from their *_airplane.go entrypoint, so that the other files of large modules aren't read.
+func scriptSlug(file string) string {
+	if filepath.Ext(file) == ".go" && !golang.IsEntrypoint(file) {
+		return ""
+	}
+	return runtime.Slug(file)
+}
+
 func (sd *ScriptDiscoverer) GetAirplaneTasks(ctx context.Context, file string) ([]string, error) {
-	slug := runtime.Slug(file)
+	slug := scriptSlug(file)
 	if slug != "" {
 		return []string{slug}, nil
 	}
@@ -36,7 +49,7 @@ func (sd *ScriptDiscoverer) GetAirplaneTasks(ctx context.Context, file string) (
 }
 
 func (sd *ScriptDiscoverer) GetTaskConfigs(ctx context.Context, file string) ([]TaskConfig, error) {
-	slug := runtime.Slug(file)
+	slug := scriptSlug(file)
 	if slug == "" {
 		return nil, nil
 	}
@@ -90,7 +103,7 @@ func (sd *ScriptDiscoverer) GetTaskConfigs(ctx context.Context, file string) ([]
 }
 
 func (sd *ScriptDiscoverer) GetTaskRoot(ctx context.Context, file string) (string, buildtypes.BuildContext, error) {
-	slug := runtime.Slug(file)
+	slug := scriptSlug(file)
 	if slug == "" {
 		return "", buildtypes.BuildContext{}, nil
 	}
@@ -48,11 +48,18 @@ func (dd *ViewDefnDiscoverer) GetViewConfig(ctx context.Context, file string) (*
 
 	envVars := make(api.EnvVars)
 	envVarsFromDefn := d.EnvVars
+	envVarsFromSets, err := envSetVars(root, d.EnvFrom)
+	if err != nil {
+		return nil, err
+	}
 	// Calculate the full list of env vars. This is the env vars (from airplane config)
-	// plus the env vars from the view. Set this new list on the def.
+	// plus the env vars from the included env sets and the view. Set this new list on the def.
 	for k, v := range bc.EnvVars {
 		envVars[k] = api.EnvVarValue(v)
 	}
+	for k, v := range envVarsFromSets {
+		envVars[k] = v
+	}
 	for k, v := range envVarsFromDefn {
 		envVars[k] = v
 	}
@@ -72,7 +79,9 @@ func (dd *ViewDefnDiscoverer) GetViewConfig(ctx context.Context, file string) (*
 				return nil, nil
 			}
 
+			missingEntityHandlerMu.Lock()
 			vptr, err := dd.MissingViewHandler(ctx, d)
+			missingEntityHandlerMu.Unlock()
 			if err != nil {
 				return nil, err
 			} else if vptr == nil {
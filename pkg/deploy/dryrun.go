@@ -0,0 +1,335 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/build"
+	"github.com/airplanedev/cli/pkg/build/buildlog"
+	"github.com/airplanedev/cli/pkg/build/node"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// BundleBuildFunc builds the image of a bundle, see build.NewBundleBuilder.
+type BundleBuildFunc func(ctx context.Context, c build.BundleLocalConfig) (*build.Response, error)
+
+// buildBundleLocally builds the image of a bundle with the local Docker daemon. The image is
+// removed once it's built, since it's never pushed.
+func buildBundleLocally(ctx context.Context, c build.BundleLocalConfig) (*build.Response, error) {
+	b, client, err := build.NewBundleBuilder(c)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	resp, err := b.Build(ctx, "dry-run-"+filepath.Base(c.Root), "latest")
+	if err != nil {
+		return nil, err
+	}
+	// Multi-platform builds aren't loaded into the local image store.
+	if resp.ImageURL != "" {
+		if _, err := client.ImageRemove(ctx, resp.ImageURL, types.ImageRemoveOptions{}); err != nil {
+			logger.Debug("removing image %s: %v", resp.ImageURL, err)
+		}
+	}
+	return resp, nil
+}
+
+// dryRunResult is the outcome of building a single bundle.
+type dryRunResult struct {
+	bundle  bundlediscover.Bundle
+	slugs   []string
+	skipped bool
+	// canceled is set if the bundle wasn't built, or its build was interrupted, because another
+	// bundle failed to build with --fail-fast.
+	canceled bool
+	duration time.Duration
+	// timings is how long each step of a successful build took.
+	timings []buildlog.Timing
+	err     error
+}
+
+// DryRun builds the image of each bundle locally, without uploading code or creating a
+// deployment, and reports whether the tasks and views in each bundle built. Up to
+// Config.BuildConcurrency bundles are built at once; it defaults to one, since concurrent builds
+// can exhaust the memory of the Docker daemon. When bundles are built concurrently, each line of
+// their build logs is prefixed with the slugs of the bundle's tasks and views.
+//
+// Every bundle is built even if some fail, unless Config.FailFast is set, in which case the first
+// failure cancels the remaining builds.
+func (d *deployer) DryRun(ctx context.Context, bundles []bundlediscover.Bundle, entities []entity) error {
+	var err error
+	if len(d.cfg.ChangedFiles) > 0 {
+		bundles, err = d.filterBundlesByChangedFiles(ctx, bundles)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(bundles) == 0 {
+		d.logger.Log("Nothing to build")
+		return nil
+	}
+
+	results := make([]dryRunResult, len(bundles))
+	for i, b := range bundles {
+		results[i].bundle = b
+	}
+	for _, e := range entities {
+		if i := owningBundle(bundles, e.file); i >= 0 {
+			results[i].slugs = append(results[i].slugs, e.slug)
+		}
+	}
+
+	if d.cfg.OfflineCache != "" && !fsx.Exists(d.cfg.OfflineCache) {
+		d.logger.Log("Populating offline cache %s...", d.cfg.OfflineCache)
+		if err := node.PopulateOfflineCache(ctx, d.cfg.OfflineCache); err != nil {
+			return errors.Wrap(err, "populating offline cache")
+		}
+	}
+
+	concurrency := d.cfg.BuildConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	var outputMu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for i, b := range bundles {
+		i, b := i, b
+		d.events.emit(Event{
+			Type:        EventDiscovered,
+			Bundle:      b.RootPath,
+			BuildType:   b.BuildContext.Type,
+			TargetFiles: b.TargetPaths,
+		})
+
+		if b.BuildContext.Type == buildtypes.NoneBuildType {
+			results[i].skipped = true
+			continue
+		}
+
+		g.Go(func() error {
+			if gctx.Err() != nil {
+				results[i].canceled = true
+				return nil
+			}
+
+			files := bundleEntrypoints(b, entities)
+			d.logger.Log("Building %s...", logger.Bold(relativeBundlePath(b.RootPath)))
+			buildArgs, secretIDs := bundleBuildArgs(b, entities)
+			for k, v := range d.cfg.BuildArgs {
+				buildArgs[k] = v
+			}
+			buildSecrets, err := build.ResolveBuildSecrets(secretIDs, d.cfg.BuildSecrets)
+			if err != nil {
+				results[i].err = err
+				return d.dryRunError(err)
+			}
+
+			config := build.BundleLocalConfig{
+				Root:            b.RootPath,
+				BuildContext:    b.BuildContext,
+				Options:         buildtypes.KindOptions{"shim": "true"},
+				FilesToBuild:    files,
+				FilesToDiscover: files,
+				BuildArgs:       buildArgs,
+				BuildSecrets:    buildSecrets,
+				OfflineCache:    d.cfg.OfflineCache,
+			}
+			// Dry runs don't push anything, so only the layers of previous deploys are reused.
+			if cache := d.buildCache(b, d.gitFilePath(b)); cache != nil {
+				config.Cache = build.CacheOptions{From: cache.From}
+			}
+			var output *prefixWriter
+			if concurrency > 1 {
+				output = &prefixWriter{mu: &outputMu, w: os.Stderr, prefix: logger.Gray("[" + dryRunPrefix(results[i]) + "] ")}
+				config.Output = output
+			}
+
+			start := time.Now()
+			resp, err := d.buildBundle(gctx, config)
+			results[i].duration = time.Since(start)
+			if output != nil {
+				if ferr := output.Flush(); ferr != nil {
+					logger.Debug("flushing build logs: %v", ferr)
+				}
+			}
+			if err != nil {
+				if gctx.Err() != nil {
+					results[i].canceled = true
+					return nil
+				}
+				results[i].err = err
+				return d.dryRunError(err)
+			}
+			if resp != nil {
+				results[i].timings = resp.Timings
+			}
+			d.events.emit(Event{Type: EventBuilt, Bundle: b.RootPath, BuildType: b.BuildContext.Type, Timings: results[i].timings})
+			return nil
+		})
+	}
+	// Failures are reported per bundle by printDryRun.
+	_ = g.Wait()
+
+	return printDryRun(d.logger, results, d.cfg.Timings)
+}
+
+// dryRunError returns the error of a failed build to the errgroup, which cancels the remaining
+// builds, if the dry run should fail fast. Otherwise, it returns nil so the remaining bundles are
+// still built.
+func (d *deployer) dryRunError(err error) error {
+	if d.cfg.FailFast {
+		return err
+	}
+	return nil
+}
+
+// dryRunPrefix returns the prefix of the build logs of a bundle: the slugs of its tasks and views,
+// or its path if it has none.
+func dryRunPrefix(r dryRunResult) string {
+	if len(r.slugs) == 0 {
+		return relativeBundlePath(r.bundle.RootPath)
+	}
+	slugs := append([]string(nil), r.slugs...)
+	sort.Strings(slugs)
+	return strings.Join(slugs, ",")
+}
+
+// bundleEntrypoints returns the entrypoints of the entities in b, relative to its root.
+func bundleEntrypoints(b bundlediscover.Bundle, entities []entity) []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, e := range entities {
+		if e.entrypoint == "" || owningBundle([]bundlediscover.Bundle{b}, e.file) < 0 {
+			continue
+		}
+		rel, ok := relativeTo(b.RootPath, e.entrypoint)
+		if !ok || seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		files = append(files, rel)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// bundleBuildArgs returns the build args and the IDs of the build secrets of the tasks in b. Since
+// the tasks share an image, their build args and secrets are merged.
+func bundleBuildArgs(b bundlediscover.Bundle, entities []entity) (map[string]string, []string) {
+	args := map[string]string{}
+	seen := map[string]bool{}
+	var secrets []string
+	for _, e := range entities {
+		if owningBundle([]bundlediscover.Bundle{b}, e.file) < 0 {
+			continue
+		}
+		for k, v := range e.buildArgs {
+			args[k] = v
+		}
+		for _, id := range e.buildSecrets {
+			if !seen[id] {
+				seen[id] = true
+				secrets = append(secrets, id)
+			}
+		}
+	}
+	sort.Strings(secrets)
+	return args, secrets
+}
+
+// relativeBundlePath returns root relative to the working directory, if it's inside of it.
+func relativeBundlePath(root string) string {
+	if wd, err := filepath.Abs("."); err == nil {
+		if rel, ok := relativeTo(wd, root); ok {
+			return rel
+		}
+	}
+	return root
+}
+
+func printDryRun(l logger.Logger, results []dryRunResult, timings bool) error {
+	var built, failed, skipped, canceled int
+	l.Log("")
+	for _, r := range results {
+		slugs := logger.Gray("(no tasks or views)")
+		if len(r.slugs) > 0 {
+			sort.Strings(r.slugs)
+			slugs = strings.Join(r.slugs, ", ")
+		}
+		path := relativeBundlePath(r.bundle.RootPath)
+		switch {
+		case r.skipped:
+			skipped++
+			l.Log("  %s %s: %s", logger.Gray("-"), path, slugs)
+		case r.canceled:
+			canceled++
+			l.Log("  %s %s: %s %s", logger.Gray("-"), path, slugs, logger.Gray("(canceled)"))
+		case r.err != nil:
+			failed++
+			l.Log("%s %s: %s", logger.Red("✗"), logger.Bold(path), slugs)
+			l.Log("%s", indent(r.err.Error()))
+		default:
+			built++
+			l.Log("%s %s: %s %s", logger.Green("✓"), logger.Bold(path), slugs, logger.Gray("("+r.duration.Round(time.Second).String()+")"))
+			if timings && len(r.timings) > 0 {
+				l.Log("    %s", logger.Gray(buildlog.FormatTimings(r.timings)))
+			}
+		}
+	}
+	if timings {
+		if total := totalTimings(results); len(total) > 0 {
+			l.Log("")
+			l.Log("Time per step across all bundles: %s", buildlog.FormatTimings(total))
+		}
+	}
+
+	l.Log("")
+	summary := fmt.Sprintf("%d built, %d failed, %d skipped (no build needed)", built, failed, skipped)
+	if canceled > 0 {
+		summary += fmt.Sprintf(", %d canceled", canceled)
+	}
+	l.Log("Dry run: %s. Nothing was deployed.", summary)
+	if failed > 0 {
+		return errors.Errorf("%d bundle(s) failed to build", failed)
+	}
+	return nil
+}
+
+// totalTimings sums the time of each step across every bundle that was built.
+func totalTimings(results []dryRunResult) []buildlog.Timing {
+	byStep := map[buildlog.Step]*buildlog.Timing{}
+	for _, r := range results {
+		for _, t := range r.timings {
+			total, ok := byStep[t.Step]
+			if !ok {
+				total = &buildlog.Timing{Step: t.Step}
+				byStep[t.Step] = total
+			}
+			total.Duration += t.Duration
+			total.Vertexes += t.Vertexes
+			total.Cached += t.Cached
+		}
+	}
+	var totals []buildlog.Timing
+	for _, step := range buildlog.Steps {
+		if t, ok := byStep[step]; ok {
+			totals = append(totals, *t)
+		}
+	}
+	return totals
+}
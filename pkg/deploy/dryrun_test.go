@@ -0,0 +1,161 @@
+package deploy
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/build"
+	"github.com/airplanedev/cli/pkg/build/buildlog"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/deploy/archive"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	bundles := []bundlediscover.Bundle{
+		{RootPath: filepath.Join(root, "node"), TargetPaths: []string{"."}, BuildContext: buildtypes.BuildContext{Type: buildtypes.NodeBuildType}},
+		{RootPath: filepath.Join(root, "python"), TargetPaths: []string{"."}, BuildContext: buildtypes.BuildContext{Type: buildtypes.PythonBuildType}},
+		{RootPath: filepath.Join(root, "sql"), TargetPaths: []string{"."}, BuildContext: buildtypes.BuildContext{Type: buildtypes.NoneBuildType}},
+	}
+	entities := []entity{
+		{slug: "b", file: filepath.Join(root, "node", "b.airplane.ts"), entrypoint: filepath.Join(root, "node", "b.airplane.ts")},
+		{slug: "a", file: filepath.Join(root, "node", "a.task.yaml"), entrypoint: filepath.Join(root, "node", "src", "a.ts")},
+		{slug: "py", file: filepath.Join(root, "python", "py_airplane.py"), entrypoint: filepath.Join(root, "python", "py_airplane.py")},
+		{slug: "query", file: filepath.Join(root, "sql", "query.task.yaml"), entrypoint: filepath.Join(root, "sql", "query.sql")},
+	}
+
+	mockClient := &api.MockClient{}
+	var built []build.BundleLocalConfig
+	offlineCache := t.TempDir()
+	cfg := Config{
+		Client:       mockClient,
+		CacheFrom:    []string{"registry/cache"},
+		CacheTo:      "registry/cache",
+		OfflineCache: offlineCache,
+	}
+	d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{
+		Archiver: &archive.MockArchiver{},
+		BundleBuilder: func(ctx context.Context, c build.BundleLocalConfig) (*build.Response, error) {
+			built = append(built, c)
+			if filepath.Base(c.Root) == "python" {
+				return nil, errors.New("pip install failed")
+			}
+			return &build.Response{}, nil
+		},
+	})
+
+	err := d.DryRun(context.Background(), bundles, entities)
+	require.EqualError(err, "1 bundle(s) failed to build")
+
+	// Bundles that don't need building are skipped.
+	require.Len(built, 2)
+	require.Equal(bundles[0].RootPath, built[0].Root)
+	require.Equal([]string{"b.airplane.ts", "src/a.ts"}, built[0].FilesToBuild)
+	require.Equal(buildtypes.KindOptions{"shim": "true"}, built[0].Options)
+	require.Equal([]string{"py_airplane.py"}, built[1].FilesToBuild)
+	// Layers are reused, but not exported, since nothing is pushed.
+	require.Equal(build.CacheOptions{From: []string{"registry/cache:node-node"}}, built[0].Cache)
+	// An existing offline cache is vendored as it is.
+	require.Equal(offlineCache, built[0].OfflineCache)
+
+	// Nothing is uploaded or deployed.
+	require.Empty(mockClient.Deploys)
+}
+
+func TestDryRunConcurrency(t *testing.T) {
+	root := t.TempDir()
+	var bundles []bundlediscover.Bundle
+	for _, name := range []string{"a", "b", "c", "d"} {
+		bundles = append(bundles, bundlediscover.Bundle{
+			RootPath:     filepath.Join(root, name),
+			TargetPaths:  []string{"."},
+			BuildContext: buildtypes.BuildContext{Type: buildtypes.NodeBuildType},
+		})
+	}
+
+	for _, test := range []struct {
+		name     string
+		failFast bool
+		built    int
+	}{
+		{name: "continue on error", built: 4},
+		{name: "fail fast", failFast: true, built: 2},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			var mu sync.Mutex
+			var built []string
+			// Builds of "b" fail once "a" has started, so that "a" is in flight when "b" fails.
+			started := make(chan struct{})
+			d := NewDeployer(Config{Client: &api.MockClient{}, BuildConcurrency: 2, FailFast: test.failFast}, &logger.MockLogger{}, DeployerOpts{
+				Archiver: &archive.MockArchiver{},
+				BundleBuilder: func(ctx context.Context, c build.BundleLocalConfig) (*build.Response, error) {
+					mu.Lock()
+					built = append(built, filepath.Base(c.Root))
+					mu.Unlock()
+					require.NotNil(c.Output)
+
+					switch filepath.Base(c.Root) {
+					case "a":
+						close(started)
+						if test.failFast {
+							<-ctx.Done()
+							return nil, ctx.Err()
+						}
+					case "b":
+						<-started
+						return nil, errors.New("npm install failed")
+					}
+					return &build.Response{}, nil
+				},
+			})
+
+			err := d.DryRun(context.Background(), bundles, nil)
+			require.EqualError(err, "1 bundle(s) failed to build")
+			require.Len(built, test.built)
+		})
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	require := require.New(t)
+	var out strings.Builder
+	w := &prefixWriter{mu: &sync.Mutex{}, w: &out, prefix: "[a] "}
+
+	_, err := w.Write([]byte("one\ntw"))
+	require.NoError(err)
+	require.Equal("[a] one\n", out.String())
+	_, err = w.Write([]byte("o\nthree"))
+	require.NoError(err)
+	require.NoError(w.Flush())
+	require.Equal("[a] one\n[a] two\n[a] three\n", out.String())
+}
+
+func TestTotalTimings(t *testing.T) {
+	require := require.New(t)
+	results := []dryRunResult{
+		{timings: []buildlog.Timing{
+			{Step: buildlog.StepInstall, Duration: 10 * time.Second, Vertexes: 2},
+			{Step: buildlog.StepOther, Duration: time.Second, Vertexes: 3, Cached: 1},
+		}},
+		{err: errors.New("failed")},
+		{timings: []buildlog.Timing{
+			{Step: buildlog.StepOther, Duration: 2 * time.Second, Vertexes: 1},
+			{Step: buildlog.StepInstall, Duration: 5 * time.Second, Vertexes: 1, Cached: 1},
+		}},
+	}
+	require.Equal([]buildlog.Timing{
+		{Step: buildlog.StepInstall, Duration: 15 * time.Second, Vertexes: 3, Cached: 1},
+		{Step: buildlog.StepOther, Duration: 3 * time.Second, Vertexes: 4, Cached: 1},
+	}, totalTimings(results))
+}
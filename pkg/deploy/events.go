@@ -0,0 +1,112 @@
+package deploy
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/build/buildlog"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/pkg/errors"
+)
+
+// EventType is a stage of the deploy lifecycle.
+type EventType string
+
+const (
+	// EventDiscovered is emitted once per bundle that will be deployed.
+	EventDiscovered EventType = "discovered"
+	// EventUploading and EventUploaded are emitted per bundle root while its code is archived and
+	// uploaded.
+	EventUploading EventType = "uploading"
+	EventUploaded  EventType = "uploaded"
+	// EventBuilding is emitted once the deployment has been created and is building remotely.
+	EventBuilding EventType = "building"
+	// EventBuilt and EventUpdated are emitted when the deployment succeeds. The API only reports
+	// completion of the deployment as a whole, so both are emitted together. Dry runs instead emit
+	// EventBuilt once per bundle that built locally.
+	EventBuilt   EventType = "built"
+	EventUpdated EventType = "updated"
+	// EventFailed is emitted if the deploy fails or is cancelled. It is always the last event.
+	EventFailed EventType = "failed"
+)
+
+// Event is a single line of the newline-delimited JSON event stream written by `airplane deploy`
+// when --events-fd or --events-file is set.
+type Event struct {
+	Type         EventType            `json:"type"`
+	Time         time.Time            `json:"time"`
+	Bundle       string               `json:"bundle,omitempty"`
+	BuildType    buildtypes.BuildType `json:"buildType,omitempty"`
+	TargetFiles  []string             `json:"targetFiles,omitempty"`
+	UploadID     string               `json:"uploadID,omitempty"`
+	SizeBytes    int                  `json:"sizeBytes,omitempty"`
+	Digest       string               `json:"digest,omitempty"`
+	DeploymentID string               `json:"deploymentID,omitempty"`
+	URL          string               `json:"url,omitempty"`
+	Error        string               `json:"error,omitempty"`
+	// Timings is how long each step of a bundle's build took, for built events of dry runs.
+	Timings []buildlog.Timing `json:"timings,omitempty"`
+}
+
+// eventWriter writes events as newline-delimited JSON. A nil eventWriter discards events.
+type eventWriter struct {
+	mu  sync.Mutex
+	w   io.WriteCloser
+	enc *json.Encoder
+	now func() time.Time
+	// failed is set once a failed event has been written, so that it is only written once.
+	failed bool
+}
+
+// newEventWriter opens the event stream for the given file descriptor or file path. If neither is
+// set, it returns a nil writer.
+func newEventWriter(fd int, path string) (*eventWriter, error) {
+	var w io.WriteCloser
+	switch {
+	case fd > 0 && path != "":
+		return nil, errors.New("only one of --events-fd and --events-file may be set")
+	case fd > 0:
+		w = os.NewFile(uintptr(fd), "events")
+		if w == nil {
+			return nil, errors.Errorf("invalid events file descriptor: %d", fd)
+		}
+	case path != "":
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating events file")
+		}
+		w = f
+	default:
+		return nil, nil
+	}
+	return &eventWriter{w: w, enc: json.NewEncoder(w), now: time.Now}, nil
+}
+
+func (e *eventWriter) emit(event Event) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if event.Type == EventFailed {
+		if e.failed {
+			return
+		}
+		e.failed = true
+	}
+	if event.Time.IsZero() {
+		event.Time = e.now()
+	}
+	// Events are best-effort: a consumer that stops reading shouldn't fail the deploy.
+	_ = e.enc.Encode(event)
+}
+
+func (e *eventWriter) Close() error {
+	if e == nil {
+		return nil
+	}
+	return e.w.Close()
+}
@@ -0,0 +1,59 @@
+package deploy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+)
+
+// printGraph prints the order that tasks would be deployed in, and warns if the tasks call each
+// other in a cycle.
+func printGraph(l logger.Logger, graph discover.TaskGraph) {
+	if _, err := graph.Order(); err != nil {
+		l.Warning("%v", err)
+	}
+	if s := strings.TrimRight(graph.String(), "\n"); s != "" {
+		l.Log("%s", s)
+	} else {
+		l.Log("No tasks found.")
+	}
+}
+
+// bundleRanks ranks each bundle by the highest level of the tasks it contains, see
+// discover.TaskGraph.Levels, so that it can be deployed after the bundles of the tasks it calls.
+//
+// Tasks in the same bundle are deployed together, so a bundle that contains both a task and a task
+// that calls one in a later bundle can't be ordered correctly. Its highest level wins.
+func bundleRanks(bundles []bundlediscover.Bundle, taskConfigs []discover.TaskConfig, levels map[string]int) []int {
+	ranks := make([]int, len(bundles))
+	for _, e := range newEntities(taskConfigs, nil) {
+		if i := owningBundle(bundles, e.file); i >= 0 && levels[e.slug] > ranks[i] {
+			ranks[i] = levels[e.slug]
+		}
+	}
+	return ranks
+}
+
+// deployStages groups the indexes of bundles by their rank, in increasing order of rank. The
+// bundles of a deployment are deployed in no particular order, so each group needs a deployment of
+// its own that's created once the previous one succeeds.
+func deployStages(ranks []int) [][]int {
+	byRank := map[int][]int{}
+	var distinct []int
+	for i, rank := range ranks {
+		if _, ok := byRank[rank]; !ok {
+			distinct = append(distinct, rank)
+		}
+		byRank[rank] = append(byRank[rank], i)
+	}
+	sort.Ints(distinct)
+
+	stages := make([][]int, 0, len(distinct))
+	for _, rank := range distinct {
+		stages = append(stages, byRank[rank])
+	}
+	return stages
+}
@@ -0,0 +1,34 @@
+package deploy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleRanks(t *testing.T) {
+	root := t.TempDir()
+	bundles := []bundlediscover.Bundle{
+		{RootPath: filepath.Join(root, "callers"), TargetPaths: []string{"."}},
+		{RootPath: filepath.Join(root, "views"), TargetPaths: []string{"."}},
+		{RootPath: filepath.Join(root, "callees"), TargetPaths: []string{"."}},
+	}
+	taskConfigs := []discover.TaskConfig{
+		{Def: definitions.Definition{Slug: "caller"}, TaskEntrypoint: filepath.Join(root, "callers", "caller.airplane.ts")},
+		{Def: definitions.Definition{Slug: "callee"}, TaskEntrypoint: filepath.Join(root, "callees", "callee.airplane.ts")},
+	}
+
+	ranks := bundleRanks(bundles, taskConfigs, map[string]int{"caller": 1})
+	require.Equal(t, []int{1, 0, 0}, ranks)
+	require.Equal(t, [][]int{{1, 2}, {0}}, deployStages(ranks))
+}
+
+func TestDeployStages(t *testing.T) {
+	require.Equal(t, [][]int{}, deployStages(nil))
+	require.Equal(t, [][]int{{0, 1, 2}}, deployStages([]int{0, 0, 0}))
+	require.Equal(t, [][]int{{1}, {2}, {0, 3}}, deployStages([]int{3, 0, 1, 3}))
+}
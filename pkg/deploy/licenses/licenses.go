@@ -0,0 +1,412 @@
+// Package licenses reports on the third-party dependencies of a bundle, using the package
+// metadata that is installed alongside the bundle's source.
+package licenses
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/pkg/errors"
+)
+
+const UnknownLicense = "UNKNOWN"
+
+type Dependency struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	License   string `json:"license"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
+type Report struct {
+	Dependencies   []Dependency `json:"dependencies"`
+	TotalSizeBytes int64        `json:"totalSizeBytes"`
+	// Missing lists the declared dependencies that are not installed locally, and so could not be
+	// reported on.
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Violation is a dependency whose license matched the denylist.
+type Violation struct {
+	Dependency Dependency
+	Pattern    string
+}
+
+// Supported returns whether reports can be generated for bundles of the given build type.
+func Supported(buildType buildtypes.BuildType) bool {
+	switch buildType {
+	case buildtypes.NodeBuildType, buildtypes.ViewBuildType, buildtypes.PythonBuildType:
+		return true
+	default:
+		return false
+	}
+}
+
+// Generate builds a report for the bundle rooted at root. Dependencies that are not installed
+// locally are listed in Missing, since their metadata is unavailable.
+func Generate(root string, buildType buildtypes.BuildType) (Report, error) {
+	var deps []Dependency
+	var missing []string
+	var err error
+	switch buildType {
+	case buildtypes.NodeBuildType, buildtypes.ViewBuildType:
+		deps, missing, err = nodeDependencies(root)
+	case buildtypes.PythonBuildType:
+		deps, missing, err = pythonDependencies(root)
+	}
+	if err != nil {
+		return Report{}, err
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Name < deps[j].Name
+	})
+	sort.Strings(missing)
+	r := Report{Dependencies: deps, Missing: missing}
+	for _, d := range deps {
+		r.TotalSizeBytes += d.SizeBytes
+	}
+	return r, nil
+}
+
+// Check returns the dependencies whose license is denied by one of the given patterns. Patterns are
+// case-insensitive globs, e.g. "GPL-*". For SPDX expressions such as "MIT OR GPL-3.0", a dependency
+// is only denied if every alternative is denied.
+func (r Report) Check(deny []string) []Violation {
+	if len(deny) == 0 {
+		return nil
+	}
+	var violations []Violation
+	for _, d := range r.Dependencies {
+		if pattern, ok := deniedBy(d.License, deny); ok {
+			violations = append(violations, Violation{Dependency: d, Pattern: pattern})
+		}
+	}
+	return violations
+}
+
+func deniedBy(expr string, deny []string) (string, bool) {
+	expr = strings.NewReplacer("(", "", ")", "").Replace(expr)
+	var pattern string
+	for _, alt := range splitOperator(expr, "OR") {
+		altDenied := false
+		// All licenses in an AND apply, so any denied license denies the alternative.
+		for _, license := range splitOperator(alt, "AND") {
+			if p, ok := matchAny(license, deny); ok {
+				pattern, altDenied = p, true
+				break
+			}
+		}
+		if !altDenied {
+			return "", false
+		}
+	}
+	return pattern, pattern != ""
+}
+
+func splitOperator(expr, op string) []string {
+	var parts []string
+	for _, p := range strings.Split(expr, " "+op+" ") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+func matchAny(license string, patterns []string) (string, bool) {
+	license = strings.ToLower(license)
+	for _, p := range patterns {
+		if ok, err := path.Match(strings.ToLower(p), license); err == nil && ok {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+type packageJSON struct {
+	Version        string            `json:"version"`
+	Dependencies   map[string]string `json:"dependencies"`
+	License        json.RawMessage   `json:"license"`
+	LegacyLicenses []licenseObject   `json:"licenses"`
+}
+
+type licenseObject struct {
+	Type string `json:"type"`
+}
+
+func (p packageJSON) license() string {
+	var s string
+	if err := json.Unmarshal(p.License, &s); err == nil && s != "" {
+		return s
+	}
+	var o licenseObject
+	if err := json.Unmarshal(p.License, &o); err == nil && o.Type != "" {
+		return o.Type
+	}
+	var types []string
+	for _, l := range p.LegacyLicenses {
+		if l.Type != "" {
+			types = append(types, l.Type)
+		}
+	}
+	if len(types) > 0 {
+		return strings.Join(types, " OR ")
+	}
+	return UnknownLicense
+}
+
+func readPackageJSON(file string) (packageJSON, error) {
+	var p packageJSON
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(buf, &p); err != nil {
+		return p, errors.Wrapf(err, "parsing %s", file)
+	}
+	return p, nil
+}
+
+// nodeDependencies walks the production dependency tree starting at the root package.json,
+// resolving each package the same way Node does: from the nearest node_modules upwards.
+func nodeDependencies(root string) ([]Dependency, []string, error) {
+	pkg, err := readPackageJSON(filepath.Join(root, "package.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	seen := map[string]bool{}
+	var deps []Dependency
+	var missing []string
+	var visit func(dir string, names map[string]string) error
+	visit = func(dir string, names map[string]string) error {
+		for name := range names {
+			pkgDir, ok := resolveNodeModule(root, dir, name)
+			if !ok {
+				if !seen[name] {
+					seen[name] = true
+					missing = append(missing, name)
+				}
+				continue
+			}
+			if seen[pkgDir] {
+				continue
+			}
+			seen[pkgDir] = true
+
+			dep, err := readPackageJSON(filepath.Join(pkgDir, "package.json"))
+			if err != nil {
+				return err
+			}
+			size, err := dirSize(pkgDir)
+			if err != nil {
+				return err
+			}
+			deps = append(deps, Dependency{
+				Name:      name,
+				Version:   dep.Version,
+				License:   dep.license(),
+				SizeBytes: size,
+			})
+			if err := visit(pkgDir, dep.Dependencies); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(root, pkg.Dependencies); err != nil {
+		return nil, nil, err
+	}
+	return deps, missing, nil
+}
+
+func resolveNodeModule(root, dir, name string) (string, bool) {
+	for {
+		candidate := filepath.Join(dir, "node_modules", filepath.FromSlash(name))
+		if _, err := os.Stat(filepath.Join(candidate, "package.json")); err == nil {
+			return candidate, true
+		}
+		if dir == root || dir == filepath.Dir(dir) {
+			return "", false
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// pythonDependencies reads the metadata of packages installed into a virtualenv within root. The
+// requirements in requirements.txt that are not installed are returned as missing.
+func pythonDependencies(root string) ([]Dependency, []string, error) {
+	var sitePackages []string
+	for _, venv := range []string{".venv", "venv"} {
+		matches, err := filepath.Glob(filepath.Join(root, venv, "lib", "python*", "site-packages"))
+		if err != nil {
+			return nil, nil, err
+		}
+		sitePackages = append(sitePackages, matches...)
+	}
+
+	var deps []Dependency
+	installed := map[string]bool{}
+	for _, dir := range sitePackages {
+		distInfos, err := filepath.Glob(filepath.Join(dir, "*.dist-info"))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, distInfo := range distInfos {
+			dep, err := readPythonMetadata(filepath.Join(distInfo, "METADATA"))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			} else if err != nil {
+				return nil, nil, err
+			}
+			if dep.SizeBytes, err = pythonPackageSize(dir, distInfo); err != nil {
+				return nil, nil, err
+			}
+			deps = append(deps, dep)
+			installed[normalizePythonName(dep.Name)] = true
+		}
+	}
+
+	requirements, err := readRequirements(filepath.Join(root, "requirements.txt"))
+	if err != nil {
+		return nil, nil, err
+	}
+	var missing []string
+	for _, name := range requirements {
+		if !installed[normalizePythonName(name)] {
+			missing = append(missing, name)
+		}
+	}
+	return deps, missing, nil
+}
+
+// readRequirements returns the names of the packages required by a requirements.txt file. Options,
+// such as -r and -e, are skipped.
+func readRequirements(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if i := strings.IndexAny(line, "<>=!~;[@ "); i >= 0 {
+			line = line[:i]
+		}
+		if line != "" {
+			names = append(names, line)
+		}
+	}
+	return names, scanner.Err()
+}
+
+// normalizePythonName normalizes a package name as described by PEP 503.
+func normalizePythonName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+}
+
+func readPythonMetadata(file string) (Dependency, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return Dependency{}, err
+	}
+	defer f.Close()
+
+	dep := Dependency{License: UnknownLicense}
+	var classifiers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// The headers end at the first blank line; the rest is the description.
+			break
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Name":
+			dep.Name = value
+		case "Version":
+			dep.Version = value
+		case "License":
+			if value != "" && value != UnknownLicense {
+				dep.License = value
+			}
+		case "Classifier":
+			if l, ok := strings.CutPrefix(value, "License :: OSI Approved :: "); ok {
+				classifiers = append(classifiers, l)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Dependency{}, errors.Wrapf(err, "reading %s", file)
+	}
+	if dep.License == UnknownLicense && len(classifiers) > 0 {
+		dep.License = strings.Join(classifiers, " OR ")
+	}
+	return dep, nil
+}
+
+// pythonPackageSize sums the size of the files listed in the package's RECORD.
+func pythonPackageSize(sitePackages, distInfo string) (int64, error) {
+	f, err := os.Open(filepath.Join(distInfo, "RECORD"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var size int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		file, _, _ := strings.Cut(scanner.Text(), ",")
+		if info, err := os.Stat(filepath.Join(sitePackages, filepath.FromSlash(file))); err == nil {
+			size += info.Size()
+		}
+	}
+	return size, scanner.Err()
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Nested dependencies are reported separately.
+		if d.IsDir() && d.Name() == "node_modules" && p != dir {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,106 @@
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+}
+
+func TestGenerateNode(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "package.json"), `{"dependencies": {"left-pad": "^1.0.0", "@scope/pkg": "2.0.0"}}`)
+	writeFile(t, filepath.Join(root, "node_modules", "left-pad", "package.json"), `{"version": "1.3.0", "license": "WTFPL", "dependencies": {"nested": "*"}}`)
+	writeFile(t, filepath.Join(root, "node_modules", "left-pad", "node_modules", "nested", "package.json"), `{"version": "0.1.0", "licenses": [{"type": "MIT"}, {"type": "Apache-2.0"}]}`)
+	writeFile(t, filepath.Join(root, "node_modules", "@scope", "pkg", "package.json"), `{"version": "2.0.0", "license": {"type": "GPL-3.0"}}`)
+	// Dev dependencies aren't declared as dependencies, so they are not reported.
+	writeFile(t, filepath.Join(root, "node_modules", "dev-only", "package.json"), `{"version": "1.0.0", "license": "GPL-2.0"}`)
+
+	report, err := Generate(root, buildtypes.NodeBuildType)
+	require.NoError(err)
+	require.Len(report.Dependencies, 3)
+	for i := range report.Dependencies {
+		require.Positive(report.Dependencies[i].SizeBytes)
+		report.Dependencies[i].SizeBytes = 0
+	}
+	require.Equal([]Dependency{
+		{Name: "@scope/pkg", Version: "2.0.0", License: "GPL-3.0"},
+		{Name: "left-pad", Version: "1.3.0", License: "WTFPL"},
+		{Name: "nested", Version: "0.1.0", License: "MIT OR Apache-2.0"},
+	}, report.Dependencies)
+}
+
+func TestGeneratePython(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	sitePackages := filepath.Join(root, ".venv", "lib", "python3.11", "site-packages")
+	writeFile(t, filepath.Join(sitePackages, "requests-2.31.0.dist-info", "METADATA"), "Metadata-Version: 2.1\nName: requests\nVersion: 2.31.0\nLicense: Apache 2.0\n\nLicense: not a header\n")
+	writeFile(t, filepath.Join(sitePackages, "requests-2.31.0.dist-info", "RECORD"), "requests/__init__.py,sha256=x,5\n")
+	writeFile(t, filepath.Join(sitePackages, "requests", "__init__.py"), "hello")
+	writeFile(t, filepath.Join(sitePackages, "six-1.16.0.dist-info", "METADATA"), "Name: six\nVersion: 1.16.0\nLicense: UNKNOWN\nClassifier: License :: OSI Approved :: MIT License\n")
+
+	report, err := Generate(root, buildtypes.PythonBuildType)
+	require.NoError(err)
+	require.Equal(Report{
+		Dependencies: []Dependency{
+			{Name: "requests", Version: "2.31.0", License: "Apache 2.0", SizeBytes: 5},
+			{Name: "six", Version: "1.16.0", License: "MIT License"},
+		},
+		TotalSizeBytes: 5,
+	}, report)
+}
+
+func TestGenerateMissing(t *testing.T) {
+	report, err := Generate(filepath.Join(t.TempDir(), "missing"), buildtypes.NodeBuildType)
+	require.NoError(t, err)
+	require.Empty(t, report.Dependencies)
+	require.Empty(t, report.Missing)
+}
+
+func TestGenerateNotInstalled(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "package.json"), `{"dependencies": {"left-pad": "^1.0.0", "lodash": "^4.0.0"}}`)
+	writeFile(t, filepath.Join(root, "node_modules", "left-pad", "package.json"), `{"version": "1.3.0", "license": "WTFPL"}`)
+	report, err := Generate(root, buildtypes.NodeBuildType)
+	require.NoError(err)
+	require.Len(report.Dependencies, 1)
+	require.Equal([]string{"lodash"}, report.Missing)
+
+	root = t.TempDir()
+	writeFile(t, filepath.Join(root, "requirements.txt"), "# comment\nRequests==2.31.0\nsix>=1.0 ; python_version > '3'\n-r other.txt\n")
+	sitePackages := filepath.Join(root, ".venv", "lib", "python3.11", "site-packages")
+	writeFile(t, filepath.Join(sitePackages, "requests-2.31.0.dist-info", "METADATA"), "Name: requests\nVersion: 2.31.0\nLicense: Apache 2.0\n")
+	report, err = Generate(root, buildtypes.PythonBuildType)
+	require.NoError(err)
+	require.Len(report.Dependencies, 1)
+	require.Equal([]string{"six"}, report.Missing)
+}
+
+func TestCheck(t *testing.T) {
+	report := Report{
+		Dependencies: []Dependency{
+			{Name: "a", License: "MIT"},
+			{Name: "b", License: "gpl-3.0"},
+			{Name: "c", License: "(MIT OR GPL-3.0)"},
+			{Name: "d", License: "MIT AND GPL-2.0"},
+			{Name: "e", License: "AGPL-3.0 OR GPL-2.0"},
+		},
+	}
+
+	require.Empty(t, report.Check(nil))
+	require.Equal(t, []Violation{
+		{Dependency: report.Dependencies[1], Pattern: "GPL-*"},
+		{Dependency: report.Dependencies[3], Pattern: "GPL-*"},
+		{Dependency: report.Dependencies[4], Pattern: "GPL-*"},
+	}, report.Check([]string{"AGPL-*", "GPL-*"}))
+}
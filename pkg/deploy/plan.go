@@ -0,0 +1,184 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// PlanAction is what deploying would do to a task or view.
+type PlanAction string
+
+const (
+	PlanCreated   PlanAction = "created"
+	PlanUpdated   PlanAction = "updated"
+	PlanUnchanged PlanAction = "unchanged"
+	// PlanArchived entities exist, but are archived. They aren't compared against the local
+	// definitions.
+	PlanArchived PlanAction = "archived"
+)
+
+// PlanEntry is the planned change to a task or view.
+type PlanEntry struct {
+	// Kind is either "task" or "view".
+	Kind   string
+	Slug   string
+	Action PlanAction
+	// Diff is a unified diff of the changes to an updated task.
+	Diff string
+}
+
+// Plan compares the discovered tasks and views against their deployed versions, without deploying
+// anything.
+func Plan(ctx context.Context, cfg Config, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig) ([]PlanEntry, error) {
+	var entries []PlanEntry
+	if len(taskConfigs) > 0 {
+		resp, err := cfg.Client.ListResourceMetadata(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing resources")
+		}
+		for _, tc := range taskConfigs {
+			entry, err := planTask(ctx, cfg, tc.Def, resp.Resources)
+			if err != nil {
+				return nil, errors.Wrapf(err, "planning task %s", tc.Def.GetSlug())
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, vc := range viewConfigs {
+		entry, err := planView(ctx, cfg, vc.Def)
+		if err != nil {
+			return nil, errors.Wrapf(err, "planning view %s", vc.Def.Slug)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Slug < entries[j].Slug
+	})
+	return entries, nil
+}
+
+// planTask compares a task against its deployed version. Both definitions are normalized, so that
+// e.g. a resource referenced by name matches the same resource referenced by slug.
+func planTask(ctx context.Context, cfg Config, local definitions.Definition, resources []libapi.ResourceMetadata) (PlanEntry, error) {
+	entry := PlanEntry{Kind: "task", Slug: local.GetSlug()}
+	// Discovery checks whether tasks are archived with their metadata, so the plan does too.
+	metadata, err := cfg.Client.GetTaskMetadata(ctx, local.GetSlug())
+	if err != nil {
+		var merr *libapi.TaskMissingError
+		if errors.As(err, &merr) {
+			entry.Action = PlanCreated
+			return entry, nil
+		}
+		return PlanEntry{}, errors.Wrap(err, "getting task metadata")
+	}
+	if metadata.IsArchived {
+		entry.Action = PlanArchived
+		return entry, nil
+	}
+
+	task, err := cfg.Client.GetTask(ctx, libapi.GetTaskRequest{Slug: local.GetSlug(), EnvSlug: cfg.EnvSlug})
+	if err != nil {
+		return PlanEntry{}, errors.Wrap(err, "getting task")
+	}
+	remote, err := definitions.NewDefinitionFromTask(task, resources)
+	if err != nil {
+		return PlanEntry{}, errors.Wrap(err, "converting deployed task to a definition")
+	}
+	local, err = local.Normalized(resources)
+	if err != nil {
+		return PlanEntry{}, errors.Wrap(err, "normalizing local definition")
+	}
+	entry.Diff, err = definitions.Diff(local, remote)
+	if err != nil {
+		return PlanEntry{}, err
+	}
+	if entry.Diff == "" {
+		entry.Action = PlanUnchanged
+	} else {
+		entry.Action = PlanUpdated
+	}
+	return entry, nil
+}
+
+// planView compares the fields of a view that are stored in the API. The view's code is always
+// rebuilt, so changes to it aren't detected.
+func planView(ctx context.Context, cfg Config, local definitions.ViewDefinition) (PlanEntry, error) {
+	entry := PlanEntry{Kind: "view", Slug: local.Slug}
+	view, err := cfg.Client.GetView(ctx, libapi.GetViewRequest{Slug: local.Slug})
+	if err != nil {
+		var merr *libapi.ViewMissingError
+		if errors.As(err, &merr) {
+			entry.Action = PlanCreated
+			return entry, nil
+		}
+		return PlanEntry{}, errors.Wrap(err, "getting view")
+	}
+	if view.ArchivedAt != nil {
+		entry.Action = PlanArchived
+		return entry, nil
+	}
+
+	entry.Action = PlanUnchanged
+	if local.Name != view.Name || local.Description != view.Description || len(local.EnvVars) != len(view.EnvVars) {
+		entry.Action = PlanUpdated
+		return entry, nil
+	}
+	for k, v := range local.EnvVars {
+		remote, ok := view.EnvVars[k]
+		if !ok || (v.Value != nil && *v.Value != remote) {
+			entry.Action = PlanUpdated
+			break
+		}
+	}
+	return entry, nil
+}
+
+// printPlan prints the planned changes, and returns an error if deploying would change anything.
+func printPlan(l logger.Logger, entries []PlanEntry) error {
+	counts := map[PlanAction]int{}
+	for _, e := range entries {
+		counts[e.Action]++
+		switch e.Action {
+		case PlanCreated:
+			l.Log("%s %s %s", logger.Green("+"), e.Kind, logger.Bold(e.Slug))
+		case PlanUpdated:
+			l.Log("%s %s %s", logger.Yellow("~"), e.Kind, logger.Bold(e.Slug))
+			if e.Diff != "" {
+				l.Log("%s", indent(e.Diff))
+			}
+		case PlanUnchanged:
+			l.Log("  %s %s", e.Kind, e.Slug)
+		case PlanArchived:
+			l.Log("%s %s %s %s", logger.Gray("-"), e.Kind, logger.Bold(e.Slug), logger.Gray("(archived)"))
+		}
+	}
+
+	l.Log("")
+	l.Log("Plan: %d created, %d updated, %d unchanged, %d archived.",
+		counts[PlanCreated], counts[PlanUpdated], counts[PlanUnchanged], counts[PlanArchived])
+	if changes := counts[PlanCreated] + counts[PlanUpdated]; changes > 0 {
+		return errors.Errorf("deploying would change %d task(s) or view(s)", changes)
+	}
+	return nil
+}
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = fmt.Sprintf("    %s", line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,130 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	tasks := map[string]libapi.Task{}
+	for _, slug := range []string{"created", "updated", "unchanged", "archived"} {
+		def := "slug: " + slug + "\nname: My task\npython:\n  entrypoint: main.py\n"
+		require.NoError(os.WriteFile(filepath.Join(dir, slug+".task.yaml"), []byte(def), 0644))
+
+		var d definitions.Definition
+		require.NoError(d.Unmarshal(definitions.DefFormatYAML, []byte(def)))
+		task, err := d.GetTask(definitions.GetTaskOpts{})
+		require.NoError(err)
+		task.ID = "tsk_" + slug
+		task.KindOptions["entrypoint"] = "main.py"
+		tasks[slug] = task
+	}
+	require.NoError(os.WriteFile(filepath.Join(dir, "main.py"), []byte("print('hello')\n"), 0644))
+	delete(tasks, "created")
+	updated := tasks["updated"]
+	updated.Name = "Renamed in the UI"
+	tasks["updated"] = updated
+	archived := tasks["archived"]
+	archived.IsArchived = true
+	tasks["archived"] = archived
+
+	client := &api.MockClient{Tasks: tasks}
+	cfg := Config{Client: client, Paths: []string{dir}}
+	l := &logger.MockLogger{}
+	taskConfigs, viewConfigs, err := discoverConfigs(ctx, cfg, l)
+	require.NoError(err)
+	entries, err := Plan(ctx, cfg, taskConfigs, viewConfigs)
+	require.NoError(err)
+
+	actions := map[string]PlanAction{}
+	for _, e := range entries {
+		require.Equal("task", e.Kind)
+		actions[e.Slug] = e.Action
+		if e.Action == PlanUpdated {
+			require.Contains(e.Diff, "-name: Renamed in the UI\n+name: My task\n")
+		} else {
+			require.Empty(e.Diff)
+		}
+	}
+	require.Equal(map[string]PlanAction{
+		"created":   PlanCreated,
+		"updated":   PlanUpdated,
+		"unchanged": PlanUnchanged,
+		"archived":  PlanArchived,
+	}, actions)
+	require.EqualError(printPlan(l, entries), "deploying would change 2 task(s) or view(s)")
+}
+
+func TestPlanView(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	value := "1"
+	local := definitions.ViewDefinition{
+		Slug:    "my_view",
+		Name:    "My view",
+		EnvVars: libapi.EnvVars{"FLAG": {Value: &value}},
+	}
+	client := &api.MockClient{Views: map[string]libapi.View{}}
+	cfg := Config{Client: client}
+
+	entry, err := planView(ctx, cfg, local)
+	require.NoError(err)
+	require.Equal(PlanCreated, entry.Action)
+
+	client.Views["my_view"] = libapi.View{Slug: "my_view", Name: "My view", EnvVars: map[string]string{"FLAG": "1"}}
+	entry, err = planView(ctx, cfg, local)
+	require.NoError(err)
+	require.Equal(PlanUnchanged, entry.Action)
+	require.NoError(printPlan(&logger.MockLogger{}, []PlanEntry{entry}))
+
+	client.Views["my_view"] = libapi.View{Slug: "my_view", Name: "My view", EnvVars: map[string]string{"FLAG": "2"}}
+	entry, err = planView(ctx, cfg, local)
+	require.NoError(err)
+	require.Equal(PlanUpdated, entry.Action)
+
+	now := time.Now()
+	client.Views["my_view"] = libapi.View{Slug: "my_view", ArchivedAt: &now}
+	entry, err = planView(ctx, cfg, local)
+	require.NoError(err)
+	require.Equal(PlanArchived, entry.Action)
+}
+
+func TestPlanNormalizesLocal(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	resources := []libapi.ResourceMetadata{
+		{ID: "res_api", Slug: "my_api", DefaultEnvResource: &libapi.Resource{ID: "res_api", Slug: "my_api", Name: "My API"}},
+	}
+	deployed := definitions.Definition{
+		Slug: "my_task",
+		Name: "My task",
+		REST: &definitions.RESTDefinition{Resource: "my_api", Method: "GET", Path: "/"},
+	}
+	task, err := deployed.GetTask(definitions.GetTaskOpts{AvailableResources: resources})
+	require.NoError(err)
+	client := &api.MockClient{Tasks: map[string]libapi.Task{"my_task": task}}
+
+	// The local definition references the resource by name, and the deployed one by slug.
+	local := deployed
+	local.REST = &definitions.RESTDefinition{Resource: "My API", Method: "GET", Path: "/"}
+	entry, err := planTask(ctx, Config{Client: client}, local, resources)
+	require.NoError(err)
+	require.Equal(PlanUnchanged, entry.Action)
+	require.Empty(entry.Diff)
+	require.Equal("My API", local.REST.Resource)
+}
@@ -0,0 +1,107 @@
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// PayloadType is the DSSE payload type of in-toto statements.
+const PayloadType = "application/vnd.in-toto+json"
+
+// Envelope is a DSSE envelope, see https://github.com/secure-systems-lab/dsse.
+type Envelope struct {
+	PayloadType string `json:"payloadType"`
+	// Payload is the base64-encoded statement.
+	Payload    string      `json:"payload"`
+	Signatures []Signature `json:"signatures"`
+}
+
+type Signature struct {
+	KeyID string `json:"keyid"`
+	// Sig is the base64-encoded signature of the envelope's PAE.
+	Sig string `json:"sig"`
+}
+
+// Sign returns a DSSE envelope of the statement, signed by signer.
+func Sign(s Statement, signer crypto.Signer) (Envelope, error) {
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return Envelope{}, errors.Wrap(err, "marshaling statement")
+	}
+
+	var sig []byte
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		sig, err = signer.Sign(rand.Reader, pae(PayloadType, payload), crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(pae(PayloadType, payload))
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return Envelope{}, errors.Wrap(err, "signing statement")
+	}
+
+	return Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		// Key IDs are left empty, like cosign does for key pairs.
+		Signatures: []Signature{{Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// Verify checks that the envelope was signed by the private key of pub, and returns its
+// statement.
+func Verify(e Envelope, pub crypto.PublicKey) (Statement, error) {
+	if e.PayloadType != PayloadType {
+		return Statement{}, errors.Errorf("unexpected payload type %q", e.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return Statement{}, errors.Wrap(err, "decoding payload")
+	}
+
+	msg := pae(e.PayloadType, payload)
+	digest := sha256.Sum256(msg)
+	verified := false
+	for _, s := range e.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		switch pub := pub.(type) {
+		case *ecdsa.PublicKey:
+			verified = ecdsa.VerifyASN1(pub, digest[:], sig)
+		case *rsa.PublicKey:
+			verified = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+		case ed25519.PublicKey:
+			verified = ed25519.Verify(pub, msg, sig)
+		default:
+			return Statement{}, errors.Errorf("unsupported public key type %T", pub)
+		}
+		if verified {
+			break
+		}
+	}
+	if !verified {
+		return Statement{}, errors.New("no valid signature")
+	}
+
+	var s Statement
+	if err := json.Unmarshal(payload, &s); err != nil {
+		return Statement{}, errors.Wrap(err, "unmarshaling statement")
+	}
+	return s, nil
+}
+
+// pae returns the pre-authentication encoding of a payload, which is what DSSE signs.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
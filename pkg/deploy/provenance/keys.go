@@ -0,0 +1,127 @@
+package provenance
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PEM block types of private keys generated by `cosign generate-key-pair`.
+const (
+	pemTypeSigstore = "ENCRYPTED SIGSTORE PRIVATE KEY"
+	pemTypeCosign   = "ENCRYPTED COSIGN PRIVATE KEY"
+)
+
+// encryptedKey is the format that cosign encrypts private keys in: the PKCS #8 encoding of the
+// key, sealed with NaCl secretbox using a key derived from the password with scrypt.
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// LoadSigner loads a private key from a PEM file. Keys generated by cosign are decrypted with
+// password; unencrypted PKCS #8 and EC keys are also supported.
+func LoadSigner(path string, password []byte) (crypto.Signer, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading signing key")
+	}
+	block, _ := pem.Decode(buf)
+	if block == nil {
+		return nil, errors.Errorf("%s is not a PEM-encoded key", path)
+	}
+
+	var key interface{}
+	switch block.Type {
+	case pemTypeSigstore, pemTypeCosign:
+		der, err := decrypt(block.Bytes, password)
+		if err != nil {
+			return nil, err
+		}
+		key, err = x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing signing key")
+		}
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing signing key")
+		}
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing signing key")
+		}
+	default:
+		return nil, errors.Errorf("unsupported signing key type %q", block.Type)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported signing key %T", key)
+	}
+	return signer, nil
+}
+
+// LoadPublicKey loads a PEM-encoded public key, e.g. the cosign.pub of a cosign key pair.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading public key")
+	}
+	block, _ := pem.Decode(buf)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.Errorf("%s is not a PEM-encoded public key", path)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing public key")
+	}
+	return pub, nil
+}
+
+func decrypt(data, password []byte) ([]byte, error) {
+	var k encryptedKey
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, errors.Wrap(err, "parsing encrypted signing key")
+	}
+	if k.KDF.Name != "scrypt" || k.Cipher.Name != "nacl/secretbox" {
+		return nil, errors.Errorf("unsupported key encryption %s with %s", k.KDF.Name, k.Cipher.Name)
+	}
+	if len(k.Cipher.Nonce) != 24 {
+		return nil, errors.New("invalid nonce in encrypted signing key")
+	}
+
+	derived, err := scrypt.Key(password, k.KDF.Salt, k.KDF.Params.N, k.KDF.Params.R, k.KDF.Params.P, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "deriving key")
+	}
+	var key [32]byte
+	copy(key[:], derived)
+	var nonce [24]byte
+	copy(nonce[:], k.Cipher.Nonce)
+
+	der, ok := secretbox.Open(nil, k.Ciphertext, &nonce, &key)
+	if !ok {
+		return nil, errors.New("decrypting signing key: wrong password")
+	}
+	return der, nil
+}
@@ -0,0 +1,198 @@
+// Package provenance generates signed provenance attestations of deployed bundles, so that the
+// code that a deployment runs can be traced back to the commit and definitions it was built from.
+//
+// Attestations are in-toto statements with a SLSA provenance predicate, wrapped in a DSSE envelope
+// and signed with a cosign-compatible key, so they can be verified with e.g.
+//
+//	cosign verify-blob-attestation --key cosign.pub --type slsaprovenance \
+//	  --signature bundle.intoto.json bundle.tar.gz
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// StatementType is the type of in-toto statements.
+	StatementType = "https://in-toto.io/Statement/v0.1"
+	// PredicateType is the type of SLSA provenance predicates.
+	PredicateType = "https://slsa.dev/provenance/v0.2"
+	// BuildType identifies how bundles are packaged by the CLI.
+	BuildType = "https://airplane.dev/deploy/bundle@v1"
+)
+
+// Statement is an in-toto statement about a set of artifacts.
+type Statement struct {
+	Type          string    `json:"_type"`
+	PredicateType string    `json:"predicateType"`
+	Subject       []Subject `json:"subject"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is an artifact that a statement is about.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Predicate is a SLSA v0.2 provenance predicate.
+type Predicate struct {
+	Builder     Builder                `json:"builder"`
+	BuildType   string                 `json:"buildType"`
+	Invocation  Invocation             `json:"invocation"`
+	BuildConfig map[string]interface{} `json:"buildConfig,omitempty"`
+	Metadata    Metadata               `json:"metadata"`
+	Materials   []Material             `json:"materials,omitempty"`
+}
+
+type Builder struct {
+	ID string `json:"id"`
+}
+
+type Invocation struct {
+	ConfigSource ConfigSource           `json:"configSource"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`
+}
+
+type ConfigSource struct {
+	URI        string            `json:"uri,omitempty"`
+	Digest     map[string]string `json:"digest,omitempty"`
+	EntryPoint string            `json:"entryPoint,omitempty"`
+}
+
+type Metadata struct {
+	BuildStartedOn  *time.Time   `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn *time.Time   `json:"buildFinishedOn,omitempty"`
+	Completeness    Completeness `json:"completeness"`
+	Reproducible    bool         `json:"reproducible"`
+}
+
+type Completeness struct {
+	Parameters  bool `json:"parameters"`
+	Environment bool `json:"environment"`
+	Materials   bool `json:"materials"`
+}
+
+type Material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// Source is the git commit that a bundle was packaged from.
+type Source struct {
+	// URI identifies the repository, e.g. git+https://github.com/org/repo.
+	URI string
+	// Commit is the SHA-1 hash of the commit.
+	Commit string
+	// Path is the path of the bundle's root within the repository.
+	Path string
+	// Dirty is set if the working tree had uncommitted changes, in which case the bundle may not
+	// match the commit.
+	Dirty bool
+}
+
+// Options describe a packaged bundle.
+type Options struct {
+	// ArtifactName is the name of the bundle's archive.
+	ArtifactName string
+	// ArtifactDigest is the hex-encoded SHA-256 digest of the bundle's archive.
+	ArtifactDigest string
+	// BuilderVersion is the version of the CLI that packaged the bundle.
+	BuilderVersion string
+	// Parameters are the build settings of the bundle, e.g. its build type and target files.
+	Parameters map[string]interface{}
+	// DefinitionsDigest is the hex-encoded SHA-256 digest of the bundle's definition files, see
+	// DefinitionsDigest.
+	DefinitionsDigest string
+	// Source is the commit the bundle was packaged from, if it's in a git repository.
+	Source *Source
+	// StartedOn and FinishedOn are when packaging the bundle started and finished.
+	StartedOn, FinishedOn time.Time
+}
+
+// NewStatement returns the provenance of a bundle's archive.
+func NewStatement(opts Options) Statement {
+	started := opts.StartedOn.UTC()
+	finished := opts.FinishedOn.UTC()
+	s := Statement{
+		Type:          StatementType,
+		PredicateType: PredicateType,
+		Subject: []Subject{{
+			Name:   opts.ArtifactName,
+			Digest: map[string]string{"sha256": opts.ArtifactDigest},
+		}},
+		Predicate: Predicate{
+			Builder:   Builder{ID: fmt.Sprintf("https://airplane.dev/cli@%s", opts.BuilderVersion)},
+			BuildType: BuildType,
+			Invocation: Invocation{
+				Parameters: opts.Parameters,
+			},
+			Metadata: Metadata{
+				BuildStartedOn:  &started,
+				BuildFinishedOn: &finished,
+				Completeness:    Completeness{Parameters: true},
+				// Archives are packaged with normalized file metadata, so packaging the same
+				// files always produces the same digest.
+				Reproducible: true,
+			},
+		},
+	}
+	if opts.DefinitionsDigest != "" {
+		s.Predicate.BuildConfig = map[string]interface{}{
+			"definitionsDigest": map[string]string{"sha256": opts.DefinitionsDigest},
+		}
+	}
+	if src := opts.Source; src != nil {
+		digest := map[string]string{"sha1": src.Commit}
+		s.Predicate.Invocation.ConfigSource = ConfigSource{
+			URI:        src.URI,
+			Digest:     digest,
+			EntryPoint: src.Path,
+		}
+		s.Predicate.Materials = []Material{{URI: src.URI, Digest: digest}}
+		// Uncommitted changes aren't captured by the commit.
+		s.Predicate.Metadata.Completeness.Materials = !src.Dirty
+	}
+	return s
+}
+
+// DefinitionsDigest returns the hex-encoded SHA-256 digest of the given files, relative to root.
+// The digest covers each file's path and contents, so it changes if a definition is added,
+// removed or edited. Files that aren't regular files, e.g. directories, are skipped.
+func DefinitionsDigest(root string, files []string) (string, error) {
+	files = append([]string(nil), files...)
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, file := range files {
+		path := filepath.Join(root, file)
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "inspecting %s", file)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		fh := sha256.New()
+		f, err := os.Open(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "opening %s", file)
+		}
+		_, err = io.Copy(fh, f)
+		f.Close()
+		if err != nil {
+			return "", errors.Wrapf(err, "reading %s", file)
+		}
+		fmt.Fprintf(h, "%s  %s\n", hex.EncodeToString(fh.Sum(nil)), filepath.ToSlash(file))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,130 @@
+package provenance
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// writeCosignKey writes key to a file encrypted like `cosign generate-key-pair`, and its public
+// key to another file.
+func writeCosignKey(t *testing.T, key *ecdsa.PrivateKey, password []byte) (string, string) {
+	require := require.New(t)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(err)
+
+	var k encryptedKey
+	k.KDF.Name = "scrypt"
+	// Cosign uses N=32768; a smaller cost keeps the test fast.
+	k.KDF.Params.N, k.KDF.Params.R, k.KDF.Params.P = 1024, 8, 1
+	k.KDF.Salt = []byte("0123456789abcdef0123456789abcdef")
+	k.Cipher.Name = "nacl/secretbox"
+	k.Cipher.Nonce = []byte("0123456789abcdef01234567")
+	derived, err := scrypt.Key(password, k.KDF.Salt, 1024, 8, 1, 32)
+	require.NoError(err)
+	var secret [32]byte
+	copy(secret[:], derived)
+	var nonce [24]byte
+	copy(nonce[:], k.Cipher.Nonce)
+	k.Ciphertext = secretbox.Seal(nil, der, &nonce, &secret)
+	buf, err := json.Marshal(k)
+	require.NoError(err)
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "cosign.key")
+	require.NoError(os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: pemTypeSigstore, Bytes: buf}), 0600))
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(err)
+	pubPath := filepath.Join(dir, "cosign.pub")
+	require.NoError(os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0644))
+	return keyPath, pubPath
+}
+
+func TestSignAndVerify(t *testing.T) {
+	require := require.New(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	keyPath, pubPath := writeCosignKey(t, key, []byte("hunter2"))
+
+	_, err = LoadSigner(keyPath, []byte("wrong"))
+	require.EqualError(err, "decrypting signing key: wrong password")
+	signer, err := LoadSigner(keyPath, []byte("hunter2"))
+	require.NoError(err)
+	pub, err := LoadPublicKey(pubPath)
+	require.NoError(err)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	statement := NewStatement(Options{
+		ArtifactName:      "tasks.tar.gz",
+		ArtifactDigest:    "abc123",
+		BuilderVersion:    "0.3.100",
+		Parameters:        map[string]interface{}{"buildType": "node"},
+		DefinitionsDigest: "def456",
+		Source: &Source{
+			URI:    "git+https://github.com/org/repo",
+			Commit: "0123456789abcdef0123456789abcdef01234567",
+			Path:   "tasks",
+		},
+		StartedOn:  now,
+		FinishedOn: now.Add(time.Second),
+	})
+	require.Equal([]Subject{{Name: "tasks.tar.gz", Digest: map[string]string{"sha256": "abc123"}}}, statement.Subject)
+	require.Equal("https://airplane.dev/cli@0.3.100", statement.Predicate.Builder.ID)
+	require.Equal("tasks", statement.Predicate.Invocation.ConfigSource.EntryPoint)
+	require.True(statement.Predicate.Metadata.Completeness.Materials)
+
+	envelope, err := Sign(statement, signer)
+	require.NoError(err)
+	verified, err := Verify(envelope, pub)
+	require.NoError(err)
+	require.Equal(statement.Subject, verified.Subject)
+	require.Equal(map[string]interface{}{"definitionsDigest": map[string]interface{}{"sha256": "def456"}}, verified.Predicate.BuildConfig)
+
+	// Tampering with the statement invalidates the signature.
+	tampered := envelope
+	tampered.Payload = envelope.Payload[:len(envelope.Payload)-4] + "AAAA"
+	_, err = Verify(tampered, pub)
+	require.Error(err)
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	_, err = Verify(envelope, &other.PublicKey)
+	require.EqualError(err, "no valid signature")
+}
+
+func TestPAE(t *testing.T) {
+	require.Equal(t, "DSSEv1 29 http://example.com/HelloWorld 11 hello world", string(pae("http://example.com/HelloWorld", []byte("hello world"))))
+}
+
+func TestDefinitionsDigest(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(root, "dir"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(root, "a.task.yaml"), []byte("slug: a"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(root, "b.airplane.ts"), []byte("export default {}"), 0644))
+
+	digest, err := DefinitionsDigest(root, []string{"b.airplane.ts", "a.task.yaml", "dir"})
+	require.NoError(err)
+	reordered, err := DefinitionsDigest(root, []string{"dir", "a.task.yaml", "b.airplane.ts"})
+	require.NoError(err)
+	require.Equal(digest, reordered)
+
+	require.NoError(os.WriteFile(filepath.Join(root, "a.task.yaml"), []byte("slug: a2"), 0644))
+	edited, err := DefinitionsDigest(root, []string{"a.task.yaml", "b.airplane.ts"})
+	require.NoError(err)
+	require.NotEqual(digest, edited)
+
+	_, err = DefinitionsDigest(root, []string{"missing.task.yaml"})
+	require.Error(err)
+}
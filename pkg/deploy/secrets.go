@@ -0,0 +1,84 @@
+package deploy
+
+import (
+	"sort"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/pkg/errors"
+)
+
+// rejectExternalSecrets fails the deploy if an env var of a task or view, or a build env var of a
+// bundle, references an external secret. Only the dev server resolves them, and resolving them
+// here would send their values to Airplane, which external secrets are meant to avoid.
+func rejectExternalSecrets(taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, bundles []bundlediscover.Bundle) error {
+	for _, tc := range taskConfigs {
+		env, err := tc.Def.GetEnv()
+		if err != nil {
+			return errors.Wrapf(err, "getting env vars of task %s", tc.Def.GetSlug())
+		}
+		for _, k := range sortedEnvVarNames(env) {
+			if env[k].External != nil {
+				return errors.Errorf("env var %s of task %s references an external secret: external secrets are only supported by airplane dev, use a config var instead", k, tc.Def.GetSlug())
+			}
+		}
+	}
+	for _, vc := range viewConfigs {
+		for _, k := range sortedEnvVarNames(vc.Def.EnvVars) {
+			if vc.Def.EnvVars[k].External != nil {
+				return errors.Errorf("env var %s of view %s references an external secret: external secrets are only supported by airplane dev, use a config var instead", k, vc.Def.Slug)
+			}
+		}
+	}
+	for _, b := range bundles {
+		for k, v := range b.BuildContext.EnvVars {
+			if v.External != nil {
+				return errors.Errorf("build env var %s of %s references an external secret: external secrets are only supported by airplane dev, use a config var instead", k, relativeBundlePath(b.RootPath))
+			}
+		}
+	}
+	return nil
+}
+
+func sortedEnvVarNames(env libapi.EnvVars) []string {
+	names := make([]string, 0, len(env))
+	for k := range env {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rejectEnvFiles fails the deploy if a task reads env vars from dotenv files. These files usually
+// hold secrets, which would otherwise be deployed as plaintext env vars.
+func rejectEnvFiles(taskConfigs []discover.TaskConfig) error {
+	for _, tc := range taskConfigs {
+		if files := tc.Def.GetEnvFiles(); len(files) > 0 {
+			return errors.Errorf("task %s reads env vars from %s: envFile is only supported by airplane dev, use config vars for deployed tasks instead", tc.Def.GetSlug(), relativeBundlePath(files[0]))
+		}
+	}
+	return nil
+}
+
+// rejectEnvVarExpressions fails the deploy if an env var of a task or view uses an expression to
+// reference other env vars, config vars or built-ins, e.g. "{{env.BASE_URL}}/v2". Only the dev
+// server resolves them: deployed runs evaluate them as JS templates, where env is the environment.
+func rejectEnvVarExpressions(taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig) error {
+	for _, tc := range taskConfigs {
+		env, err := tc.Def.GetEnv()
+		if err != nil {
+			return errors.Wrapf(err, "getting env vars of task %s", tc.Def.GetSlug())
+		}
+		if names := definitions.DevOnlyEnvVars(env); len(names) > 0 {
+			return errors.Errorf("env var %s of task %s uses an expression: expressions in env vars are only resolved by airplane dev, set the value directly or use a config var instead", names[0], tc.Def.GetSlug())
+		}
+	}
+	for _, vc := range viewConfigs {
+		if names := definitions.DevOnlyEnvVars(vc.Def.EnvVars); len(names) > 0 {
+			return errors.Errorf("env var %s of view %s uses an expression: expressions in env vars are only resolved by airplane dev, set the value directly or use a config var instead", names[0], vc.Def.Slug)
+		}
+	}
+	return nil
+}
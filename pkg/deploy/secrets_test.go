@@ -0,0 +1,72 @@
+package deploy
+
+import (
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectExternalSecrets(t *testing.T) {
+	external := pointers.String("vault:secret/data/db#password")
+	task := func(env libapi.EnvVars) discover.TaskConfig {
+		return discover.TaskConfig{Def: definitions.Definition{
+			Slug:  "my_task",
+			Shell: &definitions.ShellDefinition{Entrypoint: "my_task.sh", EnvVars: env},
+		}}
+	}
+
+	require.NoError(t, rejectExternalSecrets([]discover.TaskConfig{
+		task(libapi.EnvVars{"HOST": {Value: pointers.String("db")}, "PASSWORD": {Config: pointers.String("db_password")}}),
+	}, nil, nil))
+
+	err := rejectExternalSecrets([]discover.TaskConfig{task(libapi.EnvVars{"PASSWORD": {External: external}})}, nil, nil)
+	require.EqualError(t, err, "env var PASSWORD of task my_task references an external secret: external secrets are only supported by airplane dev, use a config var instead")
+
+	err = rejectExternalSecrets(nil, []discover.ViewConfig{
+		{Def: definitions.ViewDefinition{Slug: "my_view", EnvVars: libapi.EnvVars{"TOKEN": {External: external}}}},
+	}, nil)
+	require.EqualError(t, err, "env var TOKEN of view my_view references an external secret: external secrets are only supported by airplane dev, use a config var instead")
+
+	err = rejectExternalSecrets(nil, nil, []bundlediscover.Bundle{
+		{RootPath: "tasks", BuildContext: buildtypes.BuildContext{EnvVars: map[string]buildtypes.EnvVarValue{"NPM_TOKEN": {External: external}}}},
+	})
+	require.ErrorContains(t, err, "build env var NPM_TOKEN of")
+}
+
+func TestRejectEnvVarExpressions(t *testing.T) {
+	task := discover.TaskConfig{Def: definitions.Definition{
+		Slug: "my_task",
+		Shell: &definitions.ShellDefinition{Entrypoint: "my_task.sh", EnvVars: libapi.EnvVars{
+			"BASE_URL": {Value: pointers.String("https://api.example.com")},
+			"ENV":      {Value: pointers.String("{{env.slug}}")},
+		}},
+	}}
+	require.NoError(t, rejectEnvVarExpressions([]discover.TaskConfig{task}, nil))
+
+	task.Def.Shell.EnvVars["API_URL"] = libapi.EnvVarValue{Value: pointers.String("{{env.BASE_URL}}/v2")}
+	err := rejectEnvVarExpressions([]discover.TaskConfig{task}, nil)
+	require.EqualError(t, err, "env var API_URL of task my_task uses an expression: expressions in env vars are only resolved by airplane dev, set the value directly or use a config var instead")
+
+	err = rejectEnvVarExpressions(nil, []discover.ViewConfig{
+		{Def: definitions.ViewDefinition{Slug: "my_view", EnvVars: libapi.EnvVars{"KEY": {Value: pointers.String("{{configs.API_KEY}}")}}}},
+	})
+	require.EqualError(t, err, "env var KEY of view my_view uses an expression: expressions in env vars are only resolved by airplane dev, set the value directly or use a config var instead")
+}
+
+func TestRejectEnvFiles(t *testing.T) {
+	task := discover.TaskConfig{Def: definitions.Definition{
+		Slug:  "my_task",
+		Shell: &definitions.ShellDefinition{Entrypoint: "my_task.sh"},
+	}}
+	require.NoError(t, rejectEnvFiles([]discover.TaskConfig{task}))
+
+	task.Def.Shell.EnvFile = []string{".env"}
+	err := rejectEnvFiles([]discover.TaskConfig{task})
+	require.EqualError(t, err, "task my_task reads env vars from .env: envFile is only supported by airplane dev, use config vars for deployed tasks instead")
+}
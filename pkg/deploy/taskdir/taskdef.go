@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/airplanedev/cli/pkg/definitions"
 	"github.com/pkg/errors"
@@ -24,13 +25,16 @@ func (td TaskDirectory) ReadDefinition() (definitions.Definition, error) {
 	}
 
 	def := definitions.Definition{}
-	if err := def.Unmarshal(definitions.GetTaskDefFormat(defPath), buf); err != nil {
+	if err := def.UnmarshalFile(td.defPath, buf); err != nil {
 		switch err := errors.Cause(err).(type) {
 		case definitions.ErrSchemaValidation:
 			errorMsgs := []string{}
 			for _, verr := range err.Errors {
 				errorMsgs = append(errorMsgs, fmt.Sprintf("%s: %s", verr.Field(), verr.Description()))
 			}
+			if len(err.ExtendedFiles) > 0 {
+				errorMsgs = append(errorMsgs, fmt.Sprintf("The definition extends %s, which may set these fields.", strings.Join(err.ExtendedFiles, ", ")))
+			}
 			return definitions.Definition{}, definitions.NewErrReadDefinition(fmt.Sprintf("Error reading %s", defPath), errorMsgs...)
 		default:
 			return definitions.Definition{}, errors.Wrap(err, "unmarshalling task definition")
@@ -0,0 +1,64 @@
+package deploy
+
+import (
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/utils/templates"
+	"github.com/pkg/errors"
+)
+
+// newTemplateVars returns the template variables of a deploy from the commit described by meta.
+func newTemplateVars(meta api.GitMetadata) definitions.TemplateVars {
+	return definitions.TemplateVars{Git: definitions.GitTemplateVars{
+		SHA: meta.CommitHash,
+		Ref: meta.Ref,
+	}}
+}
+
+// validateImageTemplates checks that the templated images of image tasks can be resolved from
+// the git repositories that the tasks are deployed from, so that a task outside of a repository
+// fails the deploy instead of being deployed with an unresolved image.
+func validateImageTemplates(taskConfigs []discover.TaskConfig, rg GitRepoGetter) error {
+	for _, tc := range taskConfigs {
+		if tc.Def.Image == nil || !definitions.IsTemplated(tc.Def.Image.Image) {
+			continue
+		}
+		var meta api.GitMetadata
+		repo, err := rg.GetGitRepo(tc.TaskRoot)
+		if err != nil {
+			return errors.Wrapf(err, "getting git repo of task %s", tc.Def.GetSlug())
+		}
+		if repo != nil {
+			if meta, err = GetGitMetadata(repo); err != nil {
+				return errors.Wrapf(err, "getting git metadata of task %s", tc.Def.GetSlug())
+			}
+		}
+		if _, err := definitions.ResolveTemplate(tc.Def.Image.Image, newTemplateVars(meta)); err != nil {
+			return errors.Wrapf(err, "resolving image of task %s", tc.Def.GetSlug())
+		}
+	}
+	return nil
+}
+
+// validateRegistryTemplates checks that the image templates of external registries only reference
+// git variables that the deployment's template variables include, since the builder renders
+// them from those.
+func validateRegistryTemplates(registries map[string]*api.ImageRegistry, vars map[string]string) error {
+	for root, r := range registries {
+		if r == nil {
+			continue
+		}
+		if _, err := templates.Expand(r.ImageTemplate, func(name string) (string, error) {
+			if strings.HasPrefix(name, "git.") && vars[name] == "" {
+				return "", errors.Errorf("registry image %q references %s, which is not available: deploy from a git repository to use git variables", r.ImageTemplate, name)
+			}
+			return "", nil
+		}); err != nil {
+			return errors.Wrapf(err, "validating registry of %s", root)
+		}
+	}
+	return nil
+}
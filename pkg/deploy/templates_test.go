@@ -0,0 +1,58 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/go-git/go-billy/v5/memfs"
+	fixtures "github.com/go-git/go-git-fixtures/v4"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateImageTemplates(t *testing.T) {
+	require := require.New(t)
+
+	imageTask := func(image string) discover.TaskConfig {
+		return discover.TaskConfig{
+			TaskRoot: t.TempDir(),
+			Def: definitions.Definition{
+				Slug:  "my_task",
+				Image: &definitions.ImageDefinition{Image: image},
+			},
+		}
+	}
+
+	// The mock getter returns no repo, as if the tasks weren't in a git repository.
+	rg := &MockGitRepoGetter{}
+	require.NoError(validateImageTemplates([]discover.TaskConfig{imageTask("alpine:3")}, rg))
+	err := validateImageTemplates([]discover.TaskConfig{imageTask("registry/app:{{git.shortSHA}}")}, rg)
+	require.ErrorContains(err, "resolving image of task my_task")
+	require.ErrorContains(err, "deploy from a git repository")
+
+	st := filesystem.NewStorage(fixtures.Basic().One().DotGit(), cache.NewObjectLRUDefault())
+	repo, err := git.Open(st, memfs.New())
+	require.NoError(err)
+	rg = &MockGitRepoGetter{Repo: repo}
+	require.NoError(validateImageTemplates([]discover.TaskConfig{imageTask("registry/app:{{git.shortSHA}}")}, rg))
+	err = validateImageTemplates([]discover.TaskConfig{imageTask("registry/app:{{git.tag}}")}, rg)
+	require.ErrorContains(err, `unknown template variable "git.tag"`)
+}
+
+func TestValidateRegistryTemplates(t *testing.T) {
+	require := require.New(t)
+
+	registries := map[string]*api.ImageRegistry{
+		"a": {ImageTemplate: "ghcr.io/acme/{{task.slug}}:{{git.shortSHA}}"},
+		"b": nil,
+	}
+	require.NoError(validateRegistryTemplates(registries, map[string]string{"git.shortSHA": "6ecf0ef"}))
+
+	err := validateRegistryTemplates(registries, nil)
+	require.ErrorContains(err, "validating registry of a")
+	require.ErrorContains(err, "deploy from a git repository")
+}
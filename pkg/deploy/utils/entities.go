@@ -1,6 +1,8 @@
 package deployutils
 
 import (
+	"os"
+	"regexp"
 	"strings"
 
 	"github.com/airplanedev/cli/pkg/definitions"
@@ -17,19 +19,55 @@ func IsDefinitionFileAirplaneEntity(filepath string) bool {
 
 func IsInlineAirplaneEntity(filepath string) bool {
 	return IsNodeInlineAirplaneEntity(filepath) ||
+		IsDenoInlineAirplaneEntity(filepath) ||
 		IsPythonInlineAirplaneEntity(filepath) ||
+		IsGoInlineAirplaneEntity(filepath) ||
 		IsViewInlineAirplaneEntity(filepath)
 }
 
 func IsNodeInlineAirplaneEntity(filepath string) bool {
-	return strings.HasSuffix(filepath, ".airplane.ts") || strings.HasSuffix(filepath, ".airplane.js") ||
+	return (strings.HasSuffix(filepath, ".airplane.ts") && !IsDenoInlineAirplaneEntity(filepath)) ||
+		strings.HasSuffix(filepath, ".airplane.js") ||
 		IsViewInlineAirplaneEntity(filepath)
 }
 
+// denoImport matches imports that use a Deno-style specifier, e.g.
+// `import airplane from "npm:airplane"` or `import { z } from "jsr:@zod/zod"`.
+var denoImport = regexp.MustCompile(`(?m)(?:^|\s)(?:import|from)\s*\(?\s*["'](?:npm:|jsr:|https?://)`)
+
+// IsDenoInlineAirplaneEntity returns whether filepath is a .airplane.ts file that is written for
+// Deno rather than Node, as determined by its use of Deno-style imports.
+func IsDenoInlineAirplaneEntity(filepath string) bool {
+	if !strings.HasSuffix(filepath, ".airplane.ts") {
+		return false
+	}
+	buf, err := os.ReadFile(filepath)
+	if err != nil {
+		return false
+	}
+	return denoImport.Match(buf)
+}
+
 func IsPythonInlineAirplaneEntity(filepath string) bool {
 	return strings.HasSuffix(filepath, "_airplane.py")
 }
 
+// goRegisterTask matches calls that declare a Go task, e.g. `airplane.RegisterTask(...)`.
+var goRegisterTask = regexp.MustCompile(`\.RegisterTask\s*\(`)
+
+// IsGoInlineAirplaneEntity returns whether filepath is a _airplane.go file that declares tasks with
+// RegisterTask. Other _airplane.go files are the entrypoints of tasks with definition files.
+func IsGoInlineAirplaneEntity(filepath string) bool {
+	if !strings.HasSuffix(filepath, "_airplane.go") {
+		return false
+	}
+	buf, err := os.ReadFile(filepath)
+	if err != nil {
+		return false
+	}
+	return goRegisterTask.Match(buf)
+}
+
 func IsViewInlineAirplaneEntity(filepath string) bool {
 	return strings.HasSuffix(filepath, ".airplane.tsx") || strings.HasSuffix(filepath, ".airplane.jsx") ||
 		strings.HasSuffix(filepath, ".view.tsx") || strings.HasSuffix(filepath, ".view.jsx")
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"os"
 	"os/exec"
 	"path/filepath"
 	goruntime "runtime"
@@ -25,6 +26,7 @@ import (
 	"github.com/airplanedev/cli/pkg/print"
 	"github.com/airplanedev/cli/pkg/resources"
 	"github.com/airplanedev/cli/pkg/runtime"
+	"github.com/airplanedev/cli/pkg/utils/airplane_directory"
 	"github.com/airplanedev/cli/pkg/utils/bufiox"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/airplanedev/ojson"
@@ -96,6 +98,13 @@ type LocalRunConfig struct {
 	WorkingDir string
 
 	StudioURL url.URL
+
+	// Sandboxed runs the task in a native process with a temporary workspace. See
+	// SupportsSandboxedExecution for what this does and doesn't isolate.
+	Sandboxed bool
+	// KeepFailedArtifacts skips removing the run's temporary directories if the run fails, so that
+	// they can be inspected.
+	KeepFailedArtifacts bool
 }
 
 type CmdConfig struct {
@@ -103,6 +112,8 @@ type CmdConfig struct {
 	closer     io.Closer
 	entrypoint string
 	runtime    runtime.Interface
+	// workspace is the temporary directory that sandboxed runs use as their working and home directory.
+	workspace string
 }
 
 var LogIDGen IDGenerator
@@ -139,6 +150,7 @@ func (l *LocalExecutor) Cmd(ctx context.Context, runConfig LocalRunConfig) (CmdC
 		return CmdConfig{}, nil
 	}
 
+	sandboxed := runConfig.Sandboxed && SupportsSandboxedExecution(runConfig.Kind)
 	cmds, closer, err := r.PrepareRun(ctx, logger.NewStdErrLogger(logger.StdErrLoggerOpts{}), runtime.PrepareRunOptions{
 		Path:           entrypoint,
 		ParamValues:    runConfig.ParamValues,
@@ -147,21 +159,44 @@ func (l *LocalExecutor) Cmd(ctx context.Context, runConfig LocalRunConfig) (CmdC
 		WorkingDir:     runConfig.WorkingDir,
 		BuiltinsClient: l.BuiltinsClient,
 		RunID:          runConfig.ID,
+		Sandboxed:      sandboxed,
 	})
 	if err != nil {
 		return CmdConfig{}, err
 	}
 
 	cmd := exec.CommandContext(ctx, cmds[0], cmds[1:]...)
-	return CmdConfig{
+	cmdConfig := CmdConfig{
 		cmd:        cmd,
 		closer:     closer,
 		entrypoint: entrypoint,
 		runtime:    r,
-	}, nil
+	}
+	if sandboxed {
+		workspace, err := os.MkdirTemp("", RunWorkspacePrefix)
+		if err != nil {
+			if closer != nil {
+				closer.Close()
+			}
+			return CmdConfig{}, errors.Wrap(err, "creating sandbox workspace")
+		}
+		cmd.Dir = workspace
+		cmdConfig.workspace = workspace
+		cmdConfig.closer = airplane_directory.CloseFunc(func() error {
+			var closeErr error
+			if closer != nil {
+				closeErr = closer.Close()
+			}
+			if err := os.RemoveAll(workspace); err != nil {
+				return errors.Wrap(err, "removing sandbox workspace")
+			}
+			return closeErr
+		})
+	}
+	return cmdConfig, nil
 }
 
-func (l *LocalExecutor) Execute(ctx context.Context, config LocalRunConfig) (api.Outputs, error) {
+func (l *LocalExecutor) Execute(ctx context.Context, config LocalRunConfig) (_ api.Outputs, rerr error) {
 	configVars, err := materializeConfigAttachments(
 		ctx,
 		config.RemoteClient,
@@ -192,9 +227,16 @@ func (l *LocalExecutor) Execute(ctx context.Context, config LocalRunConfig) (api
 	}
 
 	cmdConfig, err := l.Cmd(ctx, config)
-	if cmdConfig.closer != nil {
-		defer cmdConfig.closer.Close()
-	}
+	defer func() {
+		if cmdConfig.closer == nil {
+			return
+		}
+		if rerr != nil && config.KeepFailedArtifacts {
+			logger.Log("Keeping temporary files for failed run %s.", logger.Gray(config.ID))
+			return
+		}
+		cmdConfig.closer.Close()
+	}()
 	if err != nil {
 		return api.Outputs{}, err
 	}
@@ -216,6 +258,11 @@ func (l *LocalExecutor) Execute(ctx context.Context, config LocalRunConfig) (api
 	if cmd.Env, err = getEnvVars(ctx, config, r, entrypoint, baseInterpolateRequest); err != nil {
 		return api.Outputs{}, err
 	}
+	if cmdConfig.workspace != "" {
+		// Later values take precedence, so this overrides the user's home and temp directories.
+		cmd.Env = append(cmd.Env, SandboxEnv(cmdConfig.workspace)...)
+		logger.Log("Running task %s in a sandboxed native process. Results may differ from a deployed run.", logger.Bold(config.Slug))
+	}
 
 	if err := cmd.Start(); err != nil {
 		return api.Outputs{}, errors.Wrap(err, "starting")
@@ -343,9 +390,14 @@ func getConfigValue(ctx context.Context, remoteClient api.APIClient, config deve
 			return "", errors.Wrapf(err, "decrypting remote config %s", config.Name)
 		}
 
+		// Secrets are passed to runs as env vars, which tasks or debug logs may print.
+		logger.RegisterSecrets(resp.Config.Value)
 		return resp.Config.Value, nil
 	}
 
+	if config.IsSecret {
+		logger.RegisterSecrets(config.Value)
+	}
 	return config.Value, nil
 }
 
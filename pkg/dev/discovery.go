@@ -0,0 +1,17 @@
+package dev
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+
+	"github.com/airplanedev/cli/pkg/conf"
+)
+
+// DiscoveryCachePath returns the path of the discovery cache of a dev server rooted at dir. Each
+// directory has its own cache, so that dev servers of different projects don't evict each other's
+// entries.
+func DiscoveryCachePath(dir string) string {
+	sum := sha256.Sum256([]byte(dir))
+	return filepath.Join(conf.Dir(), "dev", "discover", hex.EncodeToString(sum[:8])+".json")
+}
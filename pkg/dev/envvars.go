@@ -10,9 +10,12 @@ import (
 
 	libapi "github.com/airplanedev/cli/pkg/api"
 	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	deployconfig "github.com/airplanedev/cli/pkg/deploy/config"
 	devenv "github.com/airplanedev/cli/pkg/dev/env"
 	"github.com/airplanedev/cli/pkg/devconf"
 	"github.com/airplanedev/cli/pkg/runtime"
+	"github.com/airplanedev/cli/pkg/secrets"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/airplanedev/cli/pkg/utils/fsx"
 	"github.com/airplanedev/cli/pkg/utils/logger"
@@ -55,6 +58,10 @@ func GetEnvVarsForView(
 	if err != nil {
 		return nil, err
 	}
+	materializedEnvVars, err = resolveEnvVarExpressions(ctx, remoteClient, materializedEnvVars, config.ConfigVars, config.Slug, config.Name)
+	if err != nil {
+		return nil, err
+	}
 	maps.Copy(envVars, materializedEnvVars)
 
 	// We skip interpolating JSTs. We may add this in the future, but this
@@ -95,6 +102,10 @@ func getEnvVars(
 		if err != nil {
 			return nil, err
 		}
+		materializedEnvVars, err = resolveEnvVarExpressions(ctx, config.RemoteClient, materializedEnvVars, config.ConfigVars, config.Slug, config.Name)
+		if err != nil {
+			return nil, err
+		}
 
 		// Interpolate any JSTs in environment variables.
 		if len(materializedEnvVars) > 0 {
@@ -124,7 +135,7 @@ func getEnvVars(
 }
 
 // materializeEnvVars materializes any environment variables that derive their values from local or remote config
-// variables.
+// variables, or from secrets stored outside of Airplane.
 func materializeEnvVars(
 	ctx context.Context,
 	remoteClient api.APIClient,
@@ -150,11 +161,47 @@ func materializeEnvVars(
 					return nil, err
 				}
 			}
+		} else if envVar.External != nil {
+			value, err := secrets.Resolve(ctx, *envVar.External)
+			if err != nil {
+				return nil, errors.Wrapf(err, "env var %s", key)
+			}
+			envVars[key] = value
 		}
 	}
 	return envVars, nil
 }
 
+// resolveEnvVarExpressions resolves the expressions in env vars that reference other env vars, config vars
+// and built-ins, e.g. "{{env.BASE_URL}}/v2". Other JSTs are left as-is.
+func resolveEnvVarExpressions(
+	ctx context.Context,
+	remoteClient api.APIClient,
+	envVars map[string]string,
+	configVars map[string]devenv.ConfigWithEnv,
+	slug string,
+	name string,
+) (map[string]string, error) {
+	return definitions.ResolveEnvVars(envVars, definitions.EnvExprVars{
+		Config: func(configName string) (string, bool, error) {
+			configVar, ok := configVars[configName]
+			if !ok {
+				return "", false, nil
+			}
+			value, err := getConfigValue(ctx, remoteClient, configVar)
+			if err != nil {
+				return "", false, err
+			}
+			return value, true, nil
+		},
+		Builtins: map[string]string{
+			"env.slug": devenv.NewLocalEnv().Slug,
+			"slug":     slug,
+			"name":     name,
+		},
+	})
+}
+
 // applyEnvVarFileOverrides applies any overrides from dotenv or dev config files to the entity's environment variables.
 func applyEnvVarFileOverrides(entityEnvVars map[string]libapi.EnvVarValue, devConfigEnvVars map[string]string,
 	r runtime.Interface, entrypoint string) libapi.EnvVars {
@@ -207,6 +254,18 @@ func getDotEnvEnvVars(r runtime.Interface, path string) (map[string]string, erro
 	// from earlier .env files.
 	dotenvs := []string{}
 
+	// The env files of the workspace that the task is a member of have the lowest precedence.
+	ws, ok, err := deployconfig.FindWorkspace(root)
+	if err != nil {
+		return nil, err
+	}
+	if ok && ws.Contains(root) {
+		for _, fp := range ws.EnvFilePaths() {
+			logger.Debug("Loading env vars from %s", logger.Bold(fp))
+			dotenvs = append(dotenvs, fp)
+		}
+	}
+
 	// Loop through directories from [workdir, root] inclusive, in reverse
 	// order.
 	dirs := []string{}
@@ -185,6 +185,36 @@ func TestGetEnvVarsForView(t *testing.T) {
 				"ENV_VAR_FROM_VALUE": "bar",
 			},
 		},
+		{
+			desc: "Resolves expressions in env vars",
+			config: GetEnvVarsForViewConfig{
+				Slug: "my_view",
+				DevConfigEnvVars: map[string]string{
+					"BASE_URL": "https://api.example.com",
+				},
+				ViewEnvVars: libapi.EnvVars{
+					"API_URL": {
+						Value: pointers.String("{{env.BASE_URL}}/v2?view={{airplane.slug}}"),
+					},
+					"API_KEY": {
+						Value: pointers.String("{{configs.my_config}}"),
+					},
+				},
+				ConfigVars: map[string]env.ConfigWithEnv{
+					"my_config": {
+						Config: api.Config{
+							Name:  "my_config",
+							Value: "foo",
+						},
+					},
+				},
+			},
+			expectedEnvVars: map[string]string{
+				"BASE_URL": "https://api.example.com",
+				"API_URL":  "https://api.example.com/v2?view=my_view",
+				"API_KEY":  "foo",
+			},
+		},
 		{
 			desc: "Adds built in env vars",
 			config: GetEnvVarsForViewConfig{
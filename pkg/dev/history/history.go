@@ -0,0 +1,287 @@
+// Package history persists local dev runs so that they outlive the dev server that executed them.
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// runsBucket maps run IDs to records.
+	runsBucket = []byte("runs")
+	// indexBucket maps keys ordered by creation time to run IDs.
+	indexBucket = []byte("index")
+)
+
+// lockTimeout is how long to wait for another process (e.g. another dev server) to release the
+// store.
+const lockTimeout = 5 * time.Second
+
+// DefaultMaxRuns is the number of completed runs that a store keeps when the retention policy of
+// its dev server doesn't limit them, so that the store doesn't grow without bound.
+const DefaultMaxRuns = 1000
+
+// Record is a run as it is persisted.
+type Record struct {
+	Run dev.LocalRun `json:"run"`
+	// Dir is the directory of the dev server that executed the run.
+	Dir  string        `json:"dir"`
+	Logs []api.LogItem `json:"logs"`
+}
+
+// Store persists runs in a bolt database. The database is only held open for the duration of
+// each operation, so that `airplane dev runs` can read it while dev servers are running.
+type Store struct {
+	path string
+}
+
+// PathForDir returns the path of the run history of the dev servers of dir, which must be
+// absolute. Each directory has a database of its own, so that each is pruned by its own dev
+// servers.
+func PathForDir(dir string) string {
+	sum := sha256.Sum256([]byte(dir))
+	return filepath.Join(conf.Dir(), "dev", "runs", hex.EncodeToString(sum[:8])+".db")
+}
+
+// New returns a store backed by the database at path. The database is created once a run is
+// saved.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the path of the store's database.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Save adds or replaces a run.
+func (s *Store) Save(r Record) error {
+	if r.Run.RunID == "" {
+		return errors.New("run ID is required")
+	}
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrap(err, "marshaling run")
+	}
+
+	return s.update(func(tx *bolt.Tx) error {
+		runs, err := tx.CreateBucketIfNotExists(runsBucket)
+		if err != nil {
+			return err
+		}
+		index, err := tx.CreateBucketIfNotExists(indexBucket)
+		if err != nil {
+			return err
+		}
+		if err := runs.Put([]byte(r.Run.RunID), buf); err != nil {
+			return err
+		}
+		return index.Put(indexKey(r.Run), []byte(r.Run.RunID))
+	})
+}
+
+// Get returns the run with the given ID. If there is no such run, false is returned.
+func (s *Store) Get(runID string) (Record, bool, error) {
+	var r Record
+	var ok bool
+	err := s.view(func(tx *bolt.Tx) error {
+		runs := tx.Bucket(runsBucket)
+		if runs == nil {
+			return nil
+		}
+		buf := runs.Get([]byte(runID))
+		if buf == nil {
+			return nil
+		}
+		ok = true
+		return json.Unmarshal(buf, &r)
+	})
+	if err != nil {
+		return Record{}, false, errors.Wrapf(err, "reading run %s", runID)
+	}
+	return r, ok, nil
+}
+
+// ListOpts filters and pages the runs returned by List.
+type ListOpts struct {
+	// TaskSlug, if set, only includes runs of this task.
+	TaskSlug string
+	// Page is the zero-indexed page of runs to return.
+	Page int
+	// Limit is the number of runs per page. If zero, all runs are returned.
+	Limit int
+}
+
+// List returns the runs that match opts, most recent first, and the total number of matching runs.
+func (s *Store) List(opts ListOpts) ([]Record, int, error) {
+	records := []Record{}
+	total := 0
+	start := opts.Page * opts.Limit
+	err := s.view(func(tx *bolt.Tx) error {
+		runs, index := tx.Bucket(runsBucket), tx.Bucket(indexBucket)
+		if runs == nil || index == nil {
+			return nil
+		}
+		c := index.Cursor()
+		for k, runID := c.Last(); k != nil; k, runID = c.Prev() {
+			buf := runs.Get(runID)
+			if buf == nil {
+				continue
+			}
+			var r Record
+			if err := json.Unmarshal(buf, &r); err != nil {
+				return errors.Wrapf(err, "reading run %s", runID)
+			}
+			if opts.TaskSlug != "" && r.Run.TaskSlug != opts.TaskSlug {
+				continue
+			}
+			if total >= start && (opts.Limit == 0 || len(records) < opts.Limit) {
+				records = append(records, r)
+			}
+			total++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// Delete removes the runs with the given IDs. Unknown IDs are ignored.
+func (s *Store) Delete(runIDs ...string) error {
+	if len(runIDs) == 0 {
+		return nil
+	}
+	return s.update(func(tx *bolt.Tx) error {
+		runs, index := tx.Bucket(runsBucket), tx.Bucket(indexBucket)
+		if runs == nil || index == nil {
+			return nil
+		}
+		for _, runID := range runIDs {
+			buf := runs.Get([]byte(runID))
+			if buf == nil {
+				continue
+			}
+			var r Record
+			if err := json.Unmarshal(buf, &r); err != nil {
+				return errors.Wrapf(err, "reading run %s", runID)
+			}
+			if err := index.Delete(indexKey(r.Run)); err != nil {
+				return err
+			}
+			if err := runs.Delete([]byte(runID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PruneOpts selects the runs that Prune removes.
+type PruneOpts struct {
+	// MaxRuns is the number of completed runs to keep. If zero, DefaultMaxRuns are kept.
+	MaxRuns int
+	// MaxAge, if set, removes completed runs that were created longer ago.
+	MaxAge time.Duration
+	// KeepFailed exempts failed runs from being removed.
+	KeepFailed bool
+}
+
+// Prune removes completed runs that fall outside of opts, including runs that the current dev
+// server never loaded. Runs that are still in progress are never removed. It returns the number of
+// runs that were removed.
+func (s *Store) Prune(opts PruneOpts, now time.Time) (int, error) {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return 0, nil
+	}
+	maxRuns := opts.MaxRuns
+	if maxRuns == 0 {
+		maxRuns = DefaultMaxRuns
+	}
+
+	pruned := 0
+	err := s.update(func(tx *bolt.Tx) error {
+		runs, index := tx.Bucket(runsBucket), tx.Bucket(indexBucket)
+		if runs == nil || index == nil {
+			return nil
+		}
+		var keys [][]byte
+		kept := 0
+		c := index.Cursor()
+		for k, runID := c.Last(); k != nil; k, runID = c.Prev() {
+			buf := runs.Get(runID)
+			if buf == nil {
+				continue
+			}
+			var r Record
+			if err := json.Unmarshal(buf, &r); err != nil {
+				return errors.Wrapf(err, "reading run %s", runID)
+			}
+			if !r.Run.Status.IsTerminal() || (opts.KeepFailed && r.Run.Status == api.RunFailed) {
+				continue
+			}
+			tooOld := opts.MaxAge > 0 && now.Sub(r.Run.CreatedAt) > opts.MaxAge
+			if kept >= maxRuns || tooOld {
+				keys = append(keys, append([]byte{}, k...))
+				continue
+			}
+			kept++
+		}
+		// Keys can't be deleted while iterating over them.
+		for _, k := range keys {
+			if err := runs.Delete(index.Get(k)); err != nil {
+				return err
+			}
+			if err := index.Delete(k); err != nil {
+				return err
+			}
+		}
+		pruned = len(keys)
+		return nil
+	})
+	return pruned, err
+}
+
+// indexKey orders runs by creation time, breaking ties by ID.
+func indexKey(run dev.LocalRun) []byte {
+	key := make([]byte, 8, 8+len(run.RunID))
+	binary.BigEndian.PutUint64(key, uint64(run.CreatedAt.UnixNano()))
+	return append(key, run.RunID...)
+}
+
+func (s *Store) update(f func(tx *bolt.Tx) error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return errors.Wrap(err, "creating run history directory")
+	}
+	db, err := bolt.Open(s.path, 0600, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return errors.Wrap(err, "opening run history")
+	}
+	defer db.Close()
+	return db.Update(f)
+}
+
+func (s *Store) view(f func(tx *bolt.Tx) error) error {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		// Nothing has been saved yet.
+		return nil
+	}
+	db, err := bolt.Open(s.path, 0600, &bolt.Options{Timeout: lockTimeout, ReadOnly: true})
+	if err != nil {
+		return errors.Wrap(err, "opening run history")
+	}
+	defer db.Close()
+	return db.View(f)
+}
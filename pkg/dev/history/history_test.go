@@ -0,0 +1,120 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	require := require.New(t)
+	s := New(filepath.Join(t.TempDir(), "dev", "runs.db"))
+
+	// Reading a store that hasn't been written to yet doesn't create it.
+	records, total, err := s.List(ListOpts{})
+	require.NoError(err)
+	require.Empty(records)
+	require.Equal(0, total)
+	_, ok, err := s.Get("run_0")
+	require.NoError(err)
+	require.False(ok)
+
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, r := range []Record{
+		{Run: dev.LocalRun{RunID: "run_0", TaskSlug: "task1", CreatedAt: now}, Dir: "/a"},
+		{Run: dev.LocalRun{RunID: "run_1", TaskSlug: "task2", CreatedAt: now.Add(time.Minute)}, Dir: "/a"},
+		{Run: dev.LocalRun{RunID: "run_2", TaskSlug: "task1", CreatedAt: now.Add(2 * time.Minute)}, Dir: "/b"},
+		{Run: dev.LocalRun{RunID: "run_3", TaskSlug: "task1", CreatedAt: now.Add(3 * time.Minute)}, Dir: "/a"},
+	} {
+		r.Logs = []api.LogItem{{Text: r.Run.RunID}}
+		require.NoError(s.Save(r), i)
+	}
+	require.Error(s.Save(Record{}))
+
+	// Saving a run again replaces it.
+	require.NoError(s.Save(Record{
+		Run:  dev.LocalRun{RunID: "run_0", TaskSlug: "task1", CreatedAt: now, Status: api.RunSucceeded},
+		Dir:  "/a",
+		Logs: []api.LogItem{{Text: "hello"}},
+	}))
+	r, ok, err := s.Get("run_0")
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(api.RunSucceeded, r.Run.Status)
+	require.Equal("hello", r.Logs[0].Text)
+
+	runIDs := func(records []Record) []string {
+		ids := []string{}
+		for _, r := range records {
+			ids = append(ids, r.Run.RunID)
+		}
+		return ids
+	}
+
+	records, total, err = s.List(ListOpts{})
+	require.NoError(err)
+	require.Equal([]string{"run_3", "run_2", "run_1", "run_0"}, runIDs(records))
+	require.Equal(4, total)
+
+	records, total, err = s.List(ListOpts{TaskSlug: "task1"})
+	require.NoError(err)
+	require.Equal([]string{"run_3", "run_2", "run_0"}, runIDs(records))
+	require.Equal(3, total)
+
+	records, total, err = s.List(ListOpts{Page: 1, Limit: 3})
+	require.NoError(err)
+	require.Equal([]string{"run_0"}, runIDs(records))
+	require.Equal(4, total)
+
+	require.NoError(s.Delete("run_1", "run_missing"))
+	records, total, err = s.List(ListOpts{})
+	require.NoError(err)
+	require.Equal([]string{"run_3", "run_2", "run_0"}, runIDs(records))
+	require.Equal(3, total)
+}
+
+func TestPrune(t *testing.T) {
+	require := require.New(t)
+	s := New(filepath.Join(t.TempDir(), "runs.db"))
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Pruning a store that hasn't been written to yet doesn't create it.
+	pruned, err := s.Prune(PruneOpts{MaxRuns: 1}, now)
+	require.NoError(err)
+	require.Equal(0, pruned)
+	require.NoFileExists(s.Path())
+
+	for _, run := range []dev.LocalRun{
+		{RunID: "old", Status: api.RunSucceeded, CreatedAt: now.Add(-48 * time.Hour)},
+		{RunID: "failed", Status: api.RunFailed, CreatedAt: now.Add(-3 * time.Minute)},
+		{RunID: "succeeded", Status: api.RunSucceeded, CreatedAt: now.Add(-2 * time.Minute)},
+		{RunID: "active", Status: api.RunActive, CreatedAt: now.Add(-time.Minute)},
+		{RunID: "latest", Status: api.RunSucceeded, CreatedAt: now},
+	} {
+		require.NoError(s.Save(Record{Run: run}))
+	}
+	remaining := func() []string {
+		records, _, err := s.List(ListOpts{})
+		require.NoError(err)
+		ids := []string{}
+		for _, r := range records {
+			ids = append(ids, r.Run.RunID)
+		}
+		return ids
+	}
+
+	pruned, err = s.Prune(PruneOpts{MaxAge: 24 * time.Hour}, now)
+	require.NoError(err)
+	require.Equal(1, pruned)
+	require.Equal([]string{"latest", "active", "succeeded", "failed"}, remaining())
+
+	// Runs in progress are never pruned, and failed runs can be kept.
+	pruned, err = s.Prune(PruneOpts{MaxRuns: 1, KeepFailed: true}, now)
+	require.NoError(err)
+	require.Equal(1, pruned)
+	require.Equal([]string{"latest", "active", "failed"}, remaining())
+}
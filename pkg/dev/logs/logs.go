@@ -12,6 +12,8 @@ type LogBroker interface {
 	Record(log api.LogItem)
 	Close()
 	NewWatcher() LogWatcher
+	// Logs returns the logs recorded so far.
+	Logs() []api.LogItem
 }
 
 // DevLogBroker implements the LogBroker interface for local dev.
@@ -34,6 +36,14 @@ func NewDevLogBroker() *DevLogBroker {
 	}
 }
 
+// NewClosedDevLogBroker initializes a DevLogBroker of a completed run, which replays logs to its watchers.
+func NewClosedDevLogBroker(logs []api.LogItem) *DevLogBroker {
+	l := NewDevLogBroker()
+	l.logs = append(l.logs, logs...)
+	l.closed = true
+	return l
+}
+
 // Record sends a log to all watchers, replaying past logs if necessary. It also appends the log to the log broker.
 func (l *DevLogBroker) Record(log api.LogItem) {
 	l.mu.Lock()
@@ -70,6 +80,13 @@ func (l *DevLogBroker) Close() {
 	l.closed = true
 }
 
+// Logs returns a copy of the logs recorded so far.
+func (l *DevLogBroker) Logs() []api.LogItem {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]api.LogItem{}, l.logs...)
+}
+
 // NewWatcher instantiates a new log watcher.
 func (l *DevLogBroker) NewWatcher() LogWatcher {
 	l.mu.Lock()
@@ -21,3 +21,7 @@ func (l *MockLogBroker) Close() {}
 func (l *MockLogBroker) NewWatcher() LogWatcher {
 	return MockLogWatcher{}
 }
+
+func (l *MockLogBroker) Logs() []api.LogItem {
+	return nil
+}
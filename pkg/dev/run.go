@@ -38,6 +38,15 @@ type LocalRun struct {
 	Sleeps           []libapi.Sleep         `json:"sleeps"`
 	IsWaitingForUser bool                   `json:"isWaitingForUser"`
 	EnvSlug          string                 `json:"envSlug"`
+	// RunAs is the slug of the service account the run is attributed to, if the task has one.
+	RunAs string `json:"runAs,omitempty"`
+	// Sandboxed is true if the run was executed in a sandboxed native process. FidelityNotes
+	// describes how such a run may differ from a run of the deployed task.
+	Sandboxed     bool     `json:"sandboxed"`
+	FidelityNotes []string `json:"fidelityNotes,omitempty"`
+	// Mocked is true if the run's outputs were read from a fixture file of the dev config
+	// instead of executing the task.
+	Mocked bool `json:"mocked"`
 
 	// The version of the task at the time of the run execution
 	TaskRevision discover.TaskConfig `json:"-"`
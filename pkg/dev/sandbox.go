@@ -0,0 +1,55 @@
+package dev
+
+import (
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+)
+
+// RunWorkspacePrefix is the prefix of the temporary directories created for sandboxed runs.
+const RunWorkspacePrefix = "airplane-run-"
+
+// SupportsSandboxedExecution returns whether tasks of the given kind can be executed in a sandboxed
+// native process rather than directly against the user's local environment.
+//
+// A sandboxed run only isolates a task's files and dependencies: it runs in a temporary workspace
+// that is used as its working, home and temporary directory, and Python tasks use a generated
+// virtualenv. It is not a security boundary. The process still runs as the current user with the
+// same network and file system access as any other local run.
+func SupportsSandboxedExecution(kind buildtypes.TaskKind) bool {
+	return kind == buildtypes.TaskKindShell || kind == buildtypes.TaskKindPython
+}
+
+// SandboxFidelityNotes describes how a sandboxed run of a task of the given kind can differ from a
+// run of the same task in its deployed image. These are surfaced in run metadata so it is clear
+// which results may not be representative.
+func SandboxFidelityNotes(kind buildtypes.TaskKind) []string {
+	if !SupportsSandboxedExecution(kind) {
+		return nil
+	}
+
+	notes := []string{
+		"Ran in a sandboxed native process instead of the task's Docker image.",
+		"The run used a temporary workspace as its working, home and temporary directory.",
+		"Operating system packages, Dockerfile instructions, and install hooks from the deployed image are not available.",
+	}
+	switch kind {
+	case buildtypes.TaskKindPython:
+		notes = append(notes, "Dependencies were installed into a generated virtualenv using the locally installed version of Python, which may differ from the task's configured version.")
+	case buildtypes.TaskKindShell:
+		notes = append(notes, "Commands were resolved from the local PATH, so tool versions may differ from the deployed image.")
+	}
+	return notes
+}
+
+// SandboxEnv returns the environment variables that point a sandboxed run at its workspace. They
+// must be appended after the run's other environment variables so that they take precedence.
+func SandboxEnv(workspace string) []string {
+	return []string{
+		"HOME=" + workspace,
+		"USERPROFILE=" + workspace,
+		"TMPDIR=" + workspace,
+		"TMP=" + workspace,
+		"TEMP=" + workspace,
+		// Don't let packages installed into the user's site-packages leak into the virtualenv.
+		"PYTHONNOUSERSITE=1",
+	}
+}
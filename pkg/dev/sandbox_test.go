@@ -0,0 +1,80 @@
+package dev
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	_ "github.com/airplanedev/cli/pkg/runtime/shell"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxedCmd(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	script := filepath.Join(root, "task.sh")
+	require.NoError(os.WriteFile(script, []byte("#!/bin/bash\necho hello\n"), 0755))
+
+	cmdConfig, err := (&LocalExecutor{}).Cmd(context.Background(), LocalRunConfig{
+		ID:        "run123",
+		Slug:      "my_task",
+		Kind:      buildtypes.TaskKindShell,
+		File:      script,
+		Sandboxed: true,
+	})
+	require.NoError(err)
+
+	// The run happens in a fresh workspace rather than the task root.
+	workspace := cmdConfig.workspace
+	require.NotEmpty(workspace)
+	require.Equal(workspace, cmdConfig.cmd.Dir)
+	require.True(strings.HasPrefix(filepath.Base(workspace), RunWorkspacePrefix))
+	require.DirExists(workspace)
+
+	require.NoError(cmdConfig.closer.Close())
+	require.NoDirExists(workspace)
+}
+
+func TestUnsandboxedCmd(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	script := filepath.Join(root, "task.sh")
+	require.NoError(os.WriteFile(script, []byte("#!/bin/bash\necho hello\n"), 0755))
+
+	cmdConfig, err := (&LocalExecutor{}).Cmd(context.Background(), LocalRunConfig{
+		ID:   "run123",
+		Slug: "my_task",
+		Kind: buildtypes.TaskKindShell,
+		File: script,
+	})
+	require.NoError(err)
+	defer cmdConfig.closer.Close()
+	require.Empty(cmdConfig.workspace)
+	require.Empty(cmdConfig.cmd.Dir)
+}
+
+func TestSandboxEnv(t *testing.T) {
+	require := require.New(t)
+
+	env := SandboxEnv("/tmp/airplane-run-123")
+	require.Contains(env, "HOME=/tmp/airplane-run-123")
+	require.Contains(env, "TMPDIR=/tmp/airplane-run-123")
+	require.Contains(env, "PYTHONNOUSERSITE=1")
+}
+
+func TestSandboxFidelityNotes(t *testing.T) {
+	require := require.New(t)
+
+	require.True(SupportsSandboxedExecution(buildtypes.TaskKindPython))
+	require.True(SupportsSandboxedExecution(buildtypes.TaskKindShell))
+	require.False(SupportsSandboxedExecution(buildtypes.TaskKindNode))
+
+	require.Nil(SandboxFidelityNotes(buildtypes.TaskKindNode))
+	require.Contains(SandboxFidelityNotes(buildtypes.TaskKindPython)[3], "virtualenv")
+	require.Contains(SandboxFidelityNotes(buildtypes.TaskKindShell)[3], "PATH")
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/airplanedev/cli/pkg/api/cliapi"
 	"github.com/airplanedev/cli/pkg/dev/env"
 	libresources "github.com/airplanedev/cli/pkg/resources"
+	"github.com/airplanedev/cli/pkg/resources/conversion"
 	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/filelock"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
@@ -30,6 +32,9 @@ type DevConfig struct {
 	// Configs is a map of config variables in the format that the user sees in the dev config file.
 	RawConfigVars map[string]string `json:"configVars" yaml:"configVars"`
 	EnvVars       map[string]string `json:"envVars" yaml:"envVars"`
+	// Mocks configures canned outputs for runs of tasks, e.g. for views that call tasks that can't
+	// be executed locally.
+	Mocks TaskMocks `json:"mocks" yaml:"mocks,omitempty"`
 
 	// Resources is a mapping from slug to external resource.
 	Resources  map[string]env.ResourceWithEnv `json:"-" yaml:"-"`
@@ -69,9 +74,34 @@ func (d *DevConfig) Update() error {
 	d.RawResources = config.RawResources
 	d.Resources = config.Resources
 	d.EnvVars = config.EnvVars
+	d.Mocks = config.Mocks
 	return nil
 }
 
+// GetTaskMock returns the path of the fixture file with the outputs of runs of the task with the
+// given slug, if the task is mocked. Relative paths are resolved from the directory of the dev
+// config file.
+func (d *DevConfig) GetTaskMock(slug string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fixture, ok := d.Mocks.Tasks[slug]
+	if !ok {
+		return "", false
+	}
+	if !filepath.IsAbs(fixture) && d.Path != "" {
+		fixture = filepath.Join(filepath.Dir(d.Path), fixture)
+	}
+	return fixture, true
+}
+
+// PassthroughUnmocked returns whether tasks that aren't mocked and aren't registered locally are
+// executed in the fallback environment.
+func (d *DevConfig) PassthroughUnmocked() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.Mocks.Fallback != MockFallbackNone
+}
+
 // updateRawResources needs to be called whenever Resources is mutated, to keep RawResources in sync
 // the caller of updateRawResources should have the lock on the DevConfig
 func (d *DevConfig) updateRawResources() error {
@@ -252,12 +282,10 @@ func (d *DevConfig) DeleteEnvVar(key string) error {
 func readDevConfig(path string) (*DevConfig, error) {
 	cfg := &DevConfig{}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	buf, err := filelock.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
 		return nil, ErrMissing
-	}
-
-	buf, err := os.ReadFile(path)
-	if err != nil {
+	} else if err != nil {
 		return nil, errors.Wrap(err, "read config")
 	}
 
@@ -288,6 +316,10 @@ func readDevConfig(path string) (*DevConfig, error) {
 			return nil, errors.Errorf("expected slug type to be string, got %T", slug)
 		}
 
+		if conversion.IsKindConfig(r) {
+			logger.Warning("Resource %s in %s uses the deprecated kindConfig format and may be missing fields. Convert it with `airplane resources migrate-configs`.", slugStr, path)
+		}
+
 		res, err := libresources.GetResource(libresources.ResourceKind(kindStr), r)
 		if err != nil {
 			return nil, errors.Wrap(err, "getting resource from raw resource")
@@ -332,6 +364,12 @@ func readDevConfig(path string) (*DevConfig, error) {
 		cfg.EnvVars = map[string]string{}
 	}
 
+	switch cfg.Mocks.Fallback {
+	case "", MockFallbackRemote, MockFallbackNone:
+	default:
+		return nil, errors.Errorf("invalid mocks fallback %q: expected %q or %q", cfg.Mocks.Fallback, MockFallbackRemote, MockFallbackNone)
+	}
+
 	cfg.Path = path
 
 	return cfg, nil
@@ -346,22 +384,18 @@ func writeDevConfig(config *DevConfig) error {
 		return err
 	}
 
-	if _, err := os.Stat(config.Path); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			f, createErr := os.Create(config.Path)
-			if createErr != nil {
-				return errors.Wrap(createErr, "creating dev config file")
-			}
-			f.Close()
-			logger.Log("Created dev config file at %s", config.Path)
-		} else {
-			return errors.Wrap(err, "checking if dev config file exists")
-		}
+	_, err = os.Stat(config.Path)
+	created := errors.Is(err, os.ErrNotExist)
+	if err != nil && !created {
+		return errors.Wrap(err, "checking if dev config file exists")
 	}
 
-	if err := os.WriteFile(config.Path, buf, 0644); err != nil {
+	if err := filelock.WriteFile(config.Path, buf, 0644); err != nil {
 		return errors.Wrap(err, "write config")
 	}
+	if created {
+		logger.Log("Created dev config file at %s", config.Path)
+	}
 
 	return nil
 }
@@ -393,3 +427,84 @@ func LoadDevConfigFile(devConfigPath string) (*DevConfig, error) {
 
 	return devConfig, nil
 }
+
+// ResourceMigration describes a resource that was converted from the deprecated kind config format.
+type ResourceMigration struct {
+	Slug string `json:"slug"`
+	Kind string `json:"kind"`
+	// Unconvertible are the fields that the exported format doesn't support, and that were dropped.
+	Unconvertible []string `json:"unconvertible"`
+}
+
+// MigrateKindConfigs converts the resources in the dev config file at path that use the deprecated
+// kind config format into the exported format. Unless dryRun is set, the file is rewritten.
+func MigrateKindConfigs(path string, dryRun bool) ([]ResourceMigration, error) {
+	if dryRun {
+		buf, err := filelock.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrMissing
+		} else if err != nil {
+			return nil, errors.Wrap(err, "read config")
+		}
+		_, migrations, err := migrateKindConfigs(buf)
+		return migrations, err
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, ErrMissing
+	}
+	var migrations []ResourceMigration
+	// Hold the lock until the migrated config is written, so that concurrent changes aren't lost.
+	err := filelock.Update(path, 0644, func(buf []byte) ([]byte, error) {
+		var cfg *DevConfig
+		var err error
+		cfg, migrations, err = migrateKindConfigs(buf)
+		if err != nil {
+			return nil, err
+		}
+		if len(migrations) == 0 {
+			return buf, nil
+		}
+		return yaml.Marshal(cfg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
+
+// migrateKindConfigs converts the resources of the dev config file buf that use the deprecated
+// kind config format, see MigrateKindConfigs.
+func migrateKindConfigs(buf []byte) (*DevConfig, []ResourceMigration, error) {
+	cfg := &DevConfig{}
+	if err := yaml.Unmarshal(buf, cfg); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal config")
+	}
+
+	migrations := []ResourceMigration{}
+	for i, r := range cfg.RawResources {
+		if !conversion.IsKindConfig(r) {
+			continue
+		}
+		slug, _ := r["slug"].(string)
+		converted, unconvertible, err := conversion.FromKindConfig(r)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "converting resource %s", slug)
+		}
+		// The dev config file doesn't store resource IDs.
+		delete(converted, "id")
+		cfg.RawResources[i] = converted
+
+		kind, _ := r["kind"].(string)
+		if unconvertible == nil {
+			unconvertible = []string{}
+		}
+		migrations = append(migrations, ResourceMigration{
+			Slug:          slug,
+			Kind:          kind,
+			Unconvertible: unconvertible,
+		})
+	}
+
+	return cfg, migrations, nil
+}
@@ -1,6 +1,8 @@
 package devconf
 
 import (
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -149,3 +151,94 @@ func TestDevConfigEnvVars(t *testing.T) {
 		"ENV_VAR_3": "value_3",
 	}, cfg.EnvVars)
 }
+
+func TestMigrateKindConfigs(t *testing.T) {
+	require := require.New(t)
+	var dir = testutils.Tempdir(t)
+	var path = filepath.Join(dir, DefaultDevConfigFileName)
+
+	current := map[string]interface{}{
+		"kind": "rest",
+		"slug": "api",
+	}
+	err := writeDevConfig(&DevConfig{
+		Path: path,
+		RawResources: []map[string]interface{}{
+			{
+				"kind": "postgres",
+				"slug": "db",
+				"kindConfig": map[string]interface{}{
+					"postgres": map[string]interface{}{
+						"host":   "localhost",
+						"tunnel": "legacy",
+					},
+				},
+			},
+			current,
+		},
+		EnvVars: map[string]string{"FOO": "bar"},
+	})
+	require.NoError(err)
+
+	migrations, err := MigrateKindConfigs(path, true)
+	require.NoError(err)
+	require.Equal([]ResourceMigration{
+		{Slug: "db", Kind: "postgres", Unconvertible: []string{"kindConfig.postgres.tunnel"}},
+	}, migrations)
+	cfg, err := readDevConfig(path)
+	require.NoError(err)
+	require.Contains(cfg.RawResources[0], "kindConfig")
+
+	_, err = MigrateKindConfigs(path, false)
+	require.NoError(err)
+	cfg, err = readDevConfig(path)
+	require.NoError(err)
+	require.NotContains(cfg.RawResources[0], "kindConfig")
+	require.NotContains(cfg.RawResources[0], "id")
+	require.Equal("localhost", cfg.RawResources[0]["host"])
+	require.Equal(current, cfg.RawResources[1])
+	require.Equal(map[string]string{"FOO": "bar"}, cfg.EnvVars)
+	require.Equal("localhost", cfg.Resources["db"].Resource.(*kinds.PostgresResource).Host)
+}
+
+func TestDevConfigMocks(t *testing.T) {
+	require := require.New(t)
+	var dir = testutils.Tempdir(t)
+	var path = filepath.Join(dir, DefaultDevConfigFileName)
+
+	err := writeDevConfig(&DevConfig{
+		Path: path,
+		Mocks: TaskMocks{
+			Tasks: map[string]string{"list_users": "fixtures/list_users.json"},
+		},
+	})
+	require.NoError(err)
+	require.NoError(os.MkdirAll(filepath.Join(dir, "fixtures"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(dir, "fixtures", "list_users.json"), []byte(`{"users": [{"name": "Alice"}]}`), 0644))
+
+	cfg, err := readDevConfig(path)
+	require.NoError(err)
+	require.True(cfg.PassthroughUnmocked())
+	_, ok := cfg.GetTaskMock("delete_user")
+	require.False(ok)
+	fixture, ok := cfg.GetTaskMock("list_users")
+	require.True(ok)
+	require.Equal(filepath.Join(dir, "fixtures", "list_users.json"), fixture)
+
+	outputs, err := ReadFixture(fixture)
+	require.NoError(err)
+	buf, err := json.Marshal(outputs)
+	require.NoError(err)
+	require.JSONEq(`{"users": [{"name": "Alice"}]}`, string(buf))
+
+	_, err = ReadFixture(filepath.Join(dir, "fixtures", "missing.json"))
+	require.ErrorContains(err, "reading fixture")
+
+	err = writeDevConfig(&DevConfig{
+		Path:  path,
+		Mocks: TaskMocks{Fallback: "never"},
+	})
+	require.NoError(err)
+	_, err = readDevConfig(path)
+	require.ErrorContains(err, "invalid mocks fallback")
+}
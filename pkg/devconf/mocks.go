@@ -0,0 +1,54 @@
+package devconf
+
+import (
+	"encoding/json"
+	"os"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/goccy/go-yaml"
+	"github.com/pkg/errors"
+)
+
+// MockFallback determines how tasks that aren't mocked are executed when they aren't registered
+// locally.
+type MockFallback string
+
+const (
+	// MockFallbackRemote executes such tasks in the fallback environment, if there is one.
+	MockFallbackRemote MockFallback = "remote"
+	// MockFallbackNone fails runs of such tasks.
+	MockFallbackNone MockFallback = "none"
+)
+
+// TaskMocks is a registry of canned task outputs, e.g.
+//
+//	mocks:
+//	  fallback: remote
+//	  tasks:
+//	    list_users: fixtures/list_users.json
+type TaskMocks struct {
+	// Tasks maps the slugs of mocked tasks to the fixture files with the outputs of their runs.
+	// Mocked tasks aren't executed, even if they're registered locally.
+	Tasks map[string]string `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+	// Fallback defaults to MockFallbackRemote.
+	Fallback MockFallback `json:"fallback,omitempty" yaml:"fallback,omitempty"`
+}
+
+// ReadFixture reads the outputs in a JSON or YAML fixture file. The file is read on every run, so
+// that edits to it don't require a restart.
+func ReadFixture(path string) (api.Outputs, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return api.Outputs{}, errors.Wrap(err, "reading fixture")
+	}
+	// YAML is a superset of JSON, so JSON fixtures are unchanged.
+	buf, err = yaml.YAMLToJSON(buf)
+	if err != nil {
+		return api.Outputs{}, errors.Wrapf(err, "parsing fixture %s", path)
+	}
+	var outputs api.Outputs
+	if err := json.Unmarshal(buf, &outputs); err != nil {
+		return api.Outputs{}, errors.Wrapf(err, "parsing fixture %s", path)
+	}
+	return outputs, nil
+}
@@ -0,0 +1,7 @@
+import React from "react";
+
+function App() {
+  return <div>Hello world</div>;
+}
+
+export default App;
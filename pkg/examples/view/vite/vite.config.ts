@@ -0,0 +1,12 @@
+import { defineConfig } from "vite";
+
+// A plugin that's only available through the project's own Vite config.
+export default defineConfig({
+  plugins: [
+    {
+      name: "project-banner",
+      transformIndexHtml: (html) =>
+        html.replace("<head>", "<head><!-- Built with the project's Vite config -->"),
+    },
+  ],
+});
@@ -40,12 +40,13 @@ func (c *APIClient) Bool(ctx context.Context, l logger.Logger, flag string, opts
 		}
 		flags = resp.Flags
 
-		userConf.Flags = conf.FlagsUpdate{
-			Flags:   flags,
-			Updated: time.Now().UTC(),
-		}
-
-		if err := conf.WriteDefaultUserConfig(userConf); err != nil {
+		if err := conf.UpdateDefaultUserConfig(func(cfg *conf.UserConfig) error {
+			cfg.Flags = conf.FlagsUpdate{
+				Flags:   flags,
+				Updated: time.Now().UTC(),
+			}
+			return nil
+		}); err != nil {
 			l.Debug("Error writing flags to user config %s", err)
 		}
 	}
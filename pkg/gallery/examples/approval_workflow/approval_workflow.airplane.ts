@@ -0,0 +1,39 @@
+import airplane from "airplane";
+
+export default airplane.task(
+  {
+    slug: "approval_workflow",
+    name: "Approval workflow",
+    description: "Issues a refund after a teammate approves it.",
+    parameters: {
+      customer_email: { type: "shorttext", name: "Customer email" },
+      amount: { type: "float", name: "Amount" },
+      reason: { type: "longtext", name: "Reason" },
+    },
+    // The workflow runtime lets the task wait for the approval for as long as
+    // it takes. See: https://docs.airplane.dev/tasks/runtimes
+    runtime: "workflow",
+  },
+  async (params) => {
+    // Pause the run until someone other than the requester approves it.
+    // Prompt documentation: https://docs.airplane.dev/platform/prompts
+    const { approved } = await airplane.prompt(
+      {
+        approved: {
+          type: "boolean",
+          name: `Refund $${params.amount} to ${params.customer_email}?`,
+          description: params.reason,
+        },
+      },
+      { reviewers: { allowSelfApprovals: false } }
+    );
+
+    if (!approved) {
+      return { refunded: false };
+    }
+
+    // Replace with a call to your payments provider.
+    airplane.appendOutput({ email: params.customer_email, amount: params.amount }, "refunds");
+    return { refunded: true };
+  }
+);
@@ -0,0 +1,5 @@
+-- Users that signed up in the last :days days, newest first.
+SELECT id, name, email, created_at
+FROM users
+WHERE created_at > NOW() - (:days || ' days')::interval
+ORDER BY created_at DESC;
@@ -0,0 +1,210 @@
+// Package gallery provides a gallery of runnable example tasks and views that can be scaffolded
+// into a project. A gallery is embedded in the CLI, and can be extended with a remote gallery that
+// uses the same layout.
+package gallery
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/pkg/errors"
+)
+
+// Example is a set of files that make up one or more runnable tasks or views.
+type Example struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Kind is the kind of the example's entities, e.g. sql or node.
+	Kind string `json:"kind"`
+	// Files maps paths, relative to the directory the example is scaffolded into, to their
+	// contents.
+	Files map[string]string `json:"files,omitempty"`
+}
+
+//go:embed examples
+var embedded embed.FS
+
+// Embedded returns the examples that are embedded in the CLI.
+func Embedded() ([]Example, error) {
+	fsys, err := fs.Sub(embedded, "examples")
+	if err != nil {
+		return nil, errors.Wrap(err, "reading embedded gallery")
+	}
+	examples, err := load(fsys)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading embedded gallery")
+	}
+	return examples, nil
+}
+
+// load reads a gallery from fsys. A gallery is a gallery.json file that lists the examples,
+// without their files, and a directory per example that contains its files.
+func load(fsys fs.FS) ([]Example, error) {
+	buf, err := fs.ReadFile(fsys, "gallery.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "reading gallery.json")
+	}
+	var examples []Example
+	if err := json.Unmarshal(buf, &examples); err != nil {
+		return nil, errors.Wrap(err, "parsing gallery.json")
+	}
+
+	for i, ex := range examples {
+		// Files are written relative to the user's directory, so an example's name must not
+		// reach outside of the gallery.
+		if ex.Name == "" || !fs.ValidPath(ex.Name) || strings.Contains(ex.Name, "/") {
+			return nil, errors.Errorf("gallery contains an example with an invalid name %q", ex.Name)
+		}
+		ex.Files = map[string]string{}
+		if err := fs.WalkDir(fsys, ex.Name, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return errors.Errorf("%s is not a regular file", p)
+			}
+			content, err := fs.ReadFile(fsys, p)
+			if err != nil {
+				return err
+			}
+			ex.Files[p[len(ex.Name)+1:]] = string(content)
+			return nil
+		}); err != nil {
+			return nil, errors.Wrapf(err, "reading files of example %s", ex.Name)
+		}
+		if len(ex.Files) == 0 {
+			return nil, errors.Errorf("example %s has no files", ex.Name)
+		}
+		examples[i] = ex
+	}
+	return examples, nil
+}
+
+// CacheDir returns the directory that the remote gallery is cached in, within configDir.
+func CacheDir(configDir string) string {
+	return filepath.Join(configDir, "examples")
+}
+
+// List returns the embedded examples, along with any examples in the cached remote gallery in
+// cacheDir. Cached examples replace embedded examples with the same name.
+func List(cacheDir string) ([]Example, error) {
+	examples, err := Embedded()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]Example{}
+	for _, ex := range examples {
+		byName[ex.Name] = ex
+	}
+	if fsx.Exists(cacheDir) {
+		cached, err := load(os.DirFS(cacheDir))
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading cached gallery %s", cacheDir)
+		}
+		for _, ex := range cached {
+			byName[ex.Name] = ex
+		}
+	}
+
+	examples = make([]Example, 0, len(byName))
+	for _, ex := range byName {
+		examples = append(examples, ex)
+	}
+	sort.Slice(examples, func(i, j int) bool {
+		return examples[i].Name < examples[j].Name
+	})
+	return examples, nil
+}
+
+// Refresh downloads a remote gallery from url and caches it in cacheDir, replacing any gallery
+// that was cached before. The remote gallery is a gzipped tarball of a directory with the same
+// layout as the embedded gallery: a gallery.json file and a directory per example.
+func Refresh(ctx context.Context, url, cacheDir string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "creating gallery request")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "getting gallery")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("getting gallery: unexpected status %s", resp.Status)
+	}
+
+	// Extract the gallery next to the cache, so that it can be validated before it replaces it.
+	if err := os.MkdirAll(filepath.Dir(cacheDir), 0755); err != nil {
+		return errors.Wrap(err, "creating gallery cache directory")
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(cacheDir), ".examples-*")
+	if err != nil {
+		return errors.Wrap(err, "creating gallery cache directory")
+	}
+	defer os.RemoveAll(tmp)
+	if err := utils.Untar(tmp, resp.Body); err != nil {
+		return errors.Wrap(err, "extracting gallery")
+	}
+	if _, err := load(os.DirFS(tmp)); err != nil {
+		return errors.Wrap(err, "loading gallery")
+	}
+
+	if err := os.RemoveAll(cacheDir); err != nil {
+		return errors.Wrap(err, "removing cached gallery")
+	}
+	if err := os.Rename(tmp, cacheDir); err != nil {
+		return errors.Wrap(err, "caching gallery")
+	}
+	return nil
+}
+
+// Find returns the example named name.
+func Find(examples []Example, name string) (Example, error) {
+	for _, ex := range examples {
+		if ex.Name == name {
+			return ex, nil
+		}
+	}
+	return Example{}, errors.Errorf("unknown example %q: run `airplane examples list` to see all examples", name)
+}
+
+// Init writes the files of ex into dir, and returns the paths of the written files. Existing
+// files are only overwritten if force is set.
+func Init(ex Example, dir string, force bool) ([]string, error) {
+	paths := make([]string, 0, len(ex.Files))
+	for p := range ex.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	if !force {
+		for _, p := range paths {
+			if fsx.Exists(filepath.Join(dir, filepath.FromSlash(p))) {
+				return nil, errors.Errorf("%s already exists: pass --force to overwrite it", filepath.Join(dir, p))
+			}
+		}
+	}
+
+	written := make([]string, 0, len(paths))
+	for _, p := range paths {
+		dest := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return written, errors.Wrapf(err, "creating directory for %s", dest)
+		}
+		if err := os.WriteFile(dest, []byte(ex.Files[p]), 0644); err != nil {
+			return written, errors.Wrapf(err, "writing %s", dest)
+		}
+		written = append(written, dest)
+	}
+	return written, nil
+}
@@ -0,0 +1,146 @@
+package gallery
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedded(t *testing.T) {
+	require := require.New(t)
+
+	examples, err := Embedded()
+	require.NoError(err)
+	require.NotEmpty(examples)
+
+	for _, ex := range examples {
+		require.NotEmpty(ex.Description, ex.Name)
+		require.NotEmpty(ex.Files, ex.Name)
+		for p, content := range ex.Files {
+			if !strings.HasSuffix(p, ".task.yaml") {
+				continue
+			}
+			// Scaffolded definitions must be valid, so that the examples can be deployed as-is.
+			var def definitions.Definition
+			require.NoError(def.Unmarshal(definitions.DefFormatYAML, []byte(content)), p)
+			require.Equal(ex.Name, def.Slug)
+		}
+	}
+}
+
+// galleryTarball returns a gzipped tarball of a gallery with the given files.
+func galleryTarball(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	dirs := map[string]bool{}
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		for dir := path.Dir(p); dir != "." && !dirs[dir]; dir = path.Dir(dir) {
+			dirs[dir] = true
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: dir + "/", Typeflag: tar.TypeDir, Mode: 0755}))
+		}
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: p, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(files[p]))}))
+		_, err := tw.Write([]byte(files[p]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func TestRefresh(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	tarball := galleryTarball(t, map[string]string{
+		"gallery.json": `[
+			{"name": "sql_report", "description": "Updated", "kind": "sql"},
+			{"name": "hello", "description": "Says hello", "kind": "shell"}
+		]`,
+		"sql_report/report.sql": "SELECT 1;",
+		"hello/hello/hello.sh":  "echo hello",
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(tarball)
+	}))
+	defer srv.Close()
+
+	cacheDir := CacheDir(t.TempDir())
+	require.NoError(Refresh(ctx, srv.URL, cacheDir))
+
+	examples, err := List(cacheDir)
+	require.NoError(err)
+	ex, err := Find(examples, "sql_report")
+	require.NoError(err)
+	require.Equal("Updated", ex.Description)
+	require.Equal(map[string]string{"report.sql": "SELECT 1;"}, ex.Files)
+	_, err = Find(examples, "rest_poller")
+	require.NoError(err)
+
+	ex, err = Find(examples, "hello")
+	require.NoError(err)
+	dir := t.TempDir()
+	written, err := Init(ex, dir, false)
+	require.NoError(err)
+	require.Equal([]string{filepath.Join(dir, "hello", "hello.sh")}, written)
+	content, err := os.ReadFile(written[0])
+	require.NoError(err)
+	require.Equal("echo hello", string(content))
+
+	_, err = Init(ex, dir, false)
+	require.ErrorContains(err, "already exists")
+	_, err = Init(ex, dir, true)
+	require.NoError(err)
+}
+
+func TestRefreshKeepsCacheOnInvalidGallery(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	tarball := galleryTarball(t, map[string]string{
+		"gallery.json":   `[{"name": "hello", "description": "Says hello", "kind": "shell"}]`,
+		"hello/hello.sh": "echo hello",
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(tarball)
+	}))
+	defer srv.Close()
+	cacheDir := CacheDir(t.TempDir())
+	require.NoError(Refresh(ctx, srv.URL, cacheDir))
+
+	// An example without a directory is rejected, and the previous gallery stays cached.
+	tarball = galleryTarball(t, map[string]string{
+		"gallery.json":  `[{"name": "empty", "description": "Nothing", "kind": "shell"}]`,
+		"other/main.sh": "echo other",
+	})
+	require.ErrorContains(Refresh(ctx, srv.URL, cacheDir), "reading files of example empty")
+	examples, err := List(cacheDir)
+	require.NoError(err)
+	_, err = Find(examples, "hello")
+	require.NoError(err)
+}
+
+func TestLoadRejectsEscapingNames(t *testing.T) {
+	_, err := load(fstest.MapFS{
+		"gallery.json": &fstest.MapFile{Data: []byte(`[{"name": "../outside"}]`)},
+	})
+	require.ErrorContains(t, err, "invalid name")
+}
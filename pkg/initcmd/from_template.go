@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/airplanedev/cli/pkg/analytics"
@@ -23,6 +25,8 @@ type InitFromTemplateRequest struct {
 
 	TemplateSlug string
 	Templates    []Template
+	// TemplateDirs are local directories to look for templates in, besides the registry.
+	TemplateDirs []string
 }
 
 type Template struct {
@@ -32,6 +36,18 @@ type Template struct {
 	DemoResources []string `json:"demoResources"`
 	ViewSlugs     []string `json:"viewSlugs"`
 	TaskSlugs     []string `json:"taskSlugs"`
+
+	// LocalPath is the directory of templates from local template directories. It's empty for
+	// templates from the registry.
+	LocalPath string `json:"-"`
+}
+
+// Slug returns the short path that a template is selected by, e.g. with `airplane init --template`.
+func (t Template) Slug() string {
+	if t.LocalPath != "" {
+		return filepath.Base(t.LocalPath)
+	}
+	return strings.TrimPrefix(t.GitHubPath, defaultGitPrefix+"/")
 }
 
 func InitFromTemplate(ctx context.Context, req InitFromTemplateRequest) error {
@@ -44,7 +60,7 @@ func InitFromTemplate(ctx context.Context, req InitFromTemplateRequest) error {
 		templatePath = req.TemplateSlug
 	} else {
 		if len(req.Templates) == 0 {
-			templates, err := ListTemplates(ctx)
+			templates, err := ListTemplates(ctx, ListTemplatesRequest{LocalDirs: req.TemplateDirs})
 			if err != nil {
 				return err
 			}
@@ -54,35 +70,124 @@ func InitFromTemplate(ctx context.Context, req InitFromTemplateRequest) error {
 		if err != nil {
 			return err
 		}
+		if template.LocalPath != "" {
+			return utils.CopyFromPath(req.Prompter, req.Logger, template.LocalPath)
+		}
 		templatePath = template.GitHubPath
 	}
 
 	return utils.CopyFromGithubPath(req.Prompter, req.Logger, templatePath)
 }
 
-const docsUrl = "http://docs.airplane.dev/templates/templates.json"
+const DefaultRegistryURL = "http://docs.airplane.dev/templates/templates.json"
 const defaultGitPrefix = "github.com/airplanedev/templates"
 
-func ListTemplates(ctx context.Context) ([]Template, error) {
-	//nolint: noctx
-	resp, err := http.Get(docsUrl)
+// templateManifest is the file that marks a directory of a local template directory as a template.
+// It has the same format as the entries of the registry.
+const templateManifest = "template.json"
+
+type ListTemplatesRequest struct {
+	// RegistryURL is the URL of the registry to list templates from. Defaults to
+	// DefaultRegistryURL.
+	RegistryURL string
+	// LocalDirs are directories whose subdirectories with a template.json are templates.
+	LocalDirs []string
+}
+
+// ListTemplates lists the templates of local template directories, followed by the templates of
+// the registry. If the registry can't be reached, only local templates are listed, if any.
+func ListTemplates(ctx context.Context, req ListTemplatesRequest) ([]Template, error) {
+	var templates []Template
+	for _, dir := range req.LocalDirs {
+		t, err := listLocalTemplates(dir)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t...)
+	}
+
+	registryURL := req.RegistryURL
+	if registryURL == "" {
+		registryURL = DefaultRegistryURL
+	}
+	t, err := listRegistryTemplates(ctx, registryURL)
 	if err != nil {
-		return []Template{}, errors.Wrap(err, "getting templates json")
+		if len(templates) > 0 {
+			logger.Debug("Listing templates from %s: %s", registryURL, err)
+			return templates, nil
+		}
+		return nil, err
+	}
+	return append(templates, t...), nil
+}
+
+func listRegistryTemplates(ctx context.Context, registryURL string) ([]Template, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, registryURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating templates request")
+	}
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting templates json")
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("getting templates json: %s returned status %d", registryURL, resp.StatusCode)
+	}
 	buf, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return []Template{}, errors.Wrap(err, "reading templates")
+		return nil, errors.Wrap(err, "reading templates")
 	}
 
 	var t []Template
 	if err = json.Unmarshal(buf, &t); err != nil {
-		return []Template{}, errors.Wrap(err, "unmarshalling templates")
+		return nil, errors.Wrap(err, "unmarshalling templates")
 	}
 	return t, nil
 }
 
+func listLocalTemplates(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading template directory %s", dir)
+	}
+	var templates []Template
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		buf, err := os.ReadFile(filepath.Join(path, templateManifest))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", templateManifest)
+		}
+		var t Template
+		if err := json.Unmarshal(buf, &t); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling %s", filepath.Join(path, templateManifest))
+		}
+		if t.Name == "" {
+			t.Name = entry.Name()
+		}
+		t.LocalPath, err = filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// FindTemplate finds a template by its GitHub path or slug. Local templates take precedence over
+// templates of the registry with the same slug.
 func FindTemplate(templates []Template, gitPath string) (Template, error) {
+	for _, t := range templates {
+		if t.LocalPath != "" && t.Slug() == gitPath {
+			return t, nil
+		}
+	}
+
 	if !strings.HasPrefix(gitPath, "github.com/") {
 		if strings.HasPrefix(gitPath, "https://github.com/") {
 			gitPath = strings.TrimPrefix(gitPath, "https://")
@@ -1,6 +1,11 @@
 package initcmd
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -46,3 +51,42 @@ func TestFindTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestListTemplates(t *testing.T) {
+	require := require.New(t)
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"name": "Getting started", "githubPath": "github.com/airplanedev/templates/getting_started"}]`))
+	}))
+	defer registry.Close()
+
+	dir := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(dir, "getting_started"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(dir, "getting_started", "template.json"), []byte(`{"name": "Our getting started"}`), 0644))
+	// Directories without a template.json aren't templates.
+	require.NoError(os.MkdirAll(filepath.Join(dir, "scratch"), 0755))
+
+	templates, err := ListTemplates(context.Background(), ListTemplatesRequest{
+		RegistryURL: registry.URL,
+		LocalDirs:   []string{dir},
+	})
+	require.NoError(err)
+	require.Equal([]Template{
+		{Name: "Our getting started", LocalPath: filepath.Join(dir, "getting_started")},
+		{Name: "Getting started", GitHubPath: "github.com/airplanedev/templates/getting_started"},
+	}, templates)
+
+	// Local templates take precedence.
+	template, err := FindTemplate(templates, "getting_started")
+	require.NoError(err)
+	require.Equal("Our getting started", template.Name)
+
+	// Local templates are listed if the registry can't be reached.
+	registry.Close()
+	templates, err = ListTemplates(context.Background(), ListTemplatesRequest{
+		RegistryURL: registry.URL,
+		LocalDirs:   []string{dir},
+	})
+	require.NoError(err)
+	require.Len(templates, 1)
+}
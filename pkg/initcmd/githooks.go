@@ -0,0 +1,66 @@
+package initcmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/pkg/errors"
+)
+
+const lintHookCommand = "airplane lint"
+
+const lintHook = `#!/bin/sh
+# Checks task definitions for likely mistakes before committing. Installed by airplane init.
+exec ` + lintHookCommand + `
+`
+
+// ErrNotGitRepository is returned when installing git hooks outside of a git repository.
+var ErrNotGitRepository = errors.New("not in a git repository")
+
+// LintHookPath returns the path of the pre-commit hook of the git repository that dir is in.
+func LintHookPath(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "hooks/pre-commit")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", ErrNotGitRepository
+		}
+		return "", errors.Wrap(err, "finding git hooks")
+	}
+	path := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
+
+// HasLintHook returns whether the pre-commit hook at path already runs `airplane lint`.
+func HasLintHook(path string) (bool, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrap(err, "reading pre-commit hook")
+	}
+	return strings.Contains(string(b), lintHookCommand), nil
+}
+
+// InstallLintHook installs a pre-commit hook at path that runs `airplane lint`. Existing hooks
+// aren't overwritten.
+func InstallLintHook(path string) error {
+	if fsx.Exists(path) {
+		return errors.Errorf("a pre-commit hook already exists at %s: add `%s` to it to lint before committing", path, lintHookCommand)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "creating hooks directory")
+	}
+	if err := os.WriteFile(path, []byte(lintHook), 0755); err != nil {
+		return errors.Wrap(err, "writing pre-commit hook")
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package initcmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallLintHook(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	_, err := LintHookPath(dir)
+	require.ErrorIs(err, ErrNotGitRepository)
+
+	require.NoError(exec.Command("git", "init", dir).Run())
+	path, err := LintHookPath(dir)
+	require.NoError(err)
+	require.Equal(filepath.Join(dir, ".git", "hooks", "pre-commit"), path)
+
+	ok, err := HasLintHook(path)
+	require.NoError(err)
+	require.False(ok)
+
+	require.NoError(InstallLintHook(path))
+	ok, err = HasLintHook(path)
+	require.NoError(err)
+	require.True(ok)
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.NotZero(info.Mode() & 0100)
+
+	// Existing hooks aren't overwritten.
+	require.ErrorContains(InstallLintHook(path), "a pre-commit hook already exists")
+}
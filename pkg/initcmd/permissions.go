@@ -0,0 +1,75 @@
+package initcmd
+
+import (
+	"context"
+	"sort"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/pkg/errors"
+)
+
+// permissionsDefinition converts the explicit permissions of a task into a definition, which
+// identifies users by their emails and groups by their names rather than their IDs.
+func permissionsDefinition(ctx context.Context, client api.APIClient, permissions libapi.Permissions) (*definitions.PermissionsDefinition, error) {
+	def := &definitions.PermissionsDefinition{RequireExplicitPermissions: true}
+	if len(permissions) == 0 {
+		return def, nil
+	}
+
+	users, err := client.ListUsers(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing users")
+	}
+	userEmails := map[string]string{}
+	for _, u := range users.Users {
+		userEmails[u.ID] = u.Email
+	}
+	groups, err := client.ListGroups(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing groups")
+	}
+	groupNames := map[string]string{}
+	for _, g := range groups.Groups {
+		groupNames[g.ID] = g.Name
+	}
+
+	for _, p := range permissions {
+		var recipients *definitions.PermissionRecipients
+		switch p.RoleID {
+		case libapi.RoleTaskViewer:
+			recipients = &def.Viewers
+		case libapi.RoleTaskRequester:
+			recipients = &def.Requesters
+		case libapi.RoleTaskExecuter:
+			recipients = &def.Executers
+		case libapi.RoleTaskAdmin:
+			recipients = &def.Admins
+		default:
+			// Permissions granted by action rather than by role can't be expressed in a definition.
+			return nil, errors.Errorf("unsupported permission: action=%q role=%q", p.Action, p.RoleID)
+		}
+
+		if p.SubUserID != nil {
+			email, ok := userEmails[*p.SubUserID]
+			if !ok {
+				return nil, errors.Errorf("unknown user %s", *p.SubUserID)
+			}
+			recipients.Users = append(recipients.Users, email)
+		}
+		if p.SubGroupID != nil {
+			name, ok := groupNames[*p.SubGroupID]
+			if !ok {
+				return nil, errors.Errorf("unknown group %s", *p.SubGroupID)
+			}
+			recipients.Groups = append(recipients.Groups, name)
+		}
+	}
+
+	for _, recipients := range []*definitions.PermissionRecipients{&def.Viewers, &def.Requesters, &def.Executers, &def.Admins} {
+		sort.Strings(recipients.Users)
+		sort.Strings(recipients.Groups)
+	}
+	return def, nil
+}
@@ -0,0 +1,40 @@
+package initcmd
+
+import (
+	"context"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionsDefinition(t *testing.T) {
+	require := require.New(t)
+	client := &api.MockClient{
+		Users: map[string]api.User{
+			"usr_1": {ID: "usr_1", Email: "alice@example.com"},
+			"usr_2": {ID: "usr_2", Email: "bob@example.com"},
+		},
+		Groups: []api.Group{{ID: "grp_1", Name: "oncall"}},
+	}
+
+	def, err := permissionsDefinition(context.Background(), client, libapi.Permissions{
+		{RoleID: libapi.RoleTaskExecuter, SubUserID: pointers.String("usr_2")},
+		{RoleID: libapi.RoleTaskExecuter, SubUserID: pointers.String("usr_1")},
+		{RoleID: libapi.RoleTaskAdmin, SubGroupID: pointers.String("grp_1")},
+	})
+	require.NoError(err)
+	require.Equal(&definitions.PermissionsDefinition{
+		Executers:                  definitions.PermissionRecipients{Users: []string{"alice@example.com", "bob@example.com"}},
+		Admins:                     definitions.PermissionRecipients{Groups: []string{"oncall"}},
+		RequireExplicitPermissions: true,
+	}, def)
+
+	_, err = permissionsDefinition(context.Background(), client, libapi.Permissions{
+		{RoleID: libapi.RoleTaskViewer, SubUserID: pointers.String("usr_missing")},
+	})
+	require.EqualError(err, "unknown user usr_missing")
+}
@@ -0,0 +1,35 @@
+package initcmd
+
+import (
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/python"
+)
+
+// LatestSDKVersion selects the latest version of the SDK.
+const LatestSDKVersion = "latest"
+
+const defaultPythonSDKVersion = "~=0.3.26"
+
+// nodeSDKPackage returns the package spec of the JavaScript SDK to install, e.g. airplane@0.2.40.
+func nodeSDKPackage(version string) string {
+	if version == "" || version == LatestSDKVersion {
+		return "airplane"
+	}
+	return "airplane@" + version
+}
+
+// pythonSDKDependency returns the requirement of the Python SDK to install. Versions without a
+// version specifier are pinned, e.g. airplanesdk==0.3.30.
+func pythonSDKDependency(version string) python.PythonDependency {
+	switch {
+	case version == "":
+		return python.PythonDependency{Name: "airplanesdk", Version: defaultPythonSDKVersion}
+	case version == LatestSDKVersion:
+		return python.PythonDependency{Name: "airplanesdk"}
+	case strings.ContainsAny(version[:1], "=<>~!"):
+		return python.PythonDependency{Name: "airplanesdk", Version: version}
+	default:
+		return python.PythonDependency{Name: "airplanesdk", Version: "==" + version}
+	}
+}
@@ -50,6 +50,10 @@ type InitTaskRequest struct {
 	TaskDescription string
 	TaskNodeFlavor  NodeFlavor
 
+	// SDKVersion is the version of the SDK to install for JavaScript and Python tasks, or "latest".
+	// Defaults to the latest JavaScript SDK and a recent Python SDK for inline tasks.
+	SDKVersion string
+
 	// ease of testing
 	suffixCharset string
 }
@@ -112,6 +116,12 @@ func InitTask(ctx context.Context, req InitTaskRequest) (InitResponse, error) {
 		if err != nil {
 			return InitResponse{}, err
 		}
+		if task.RequireExplicitPermissions {
+			def.Permissions, err = permissionsDefinition(ctx, client, task.Permissions)
+			if err != nil {
+				return InitResponse{}, err
+			}
+		}
 	} else {
 		if req.TaskName == "" {
 			return InitResponse{}, errors.New("missing new task name")
@@ -348,6 +358,7 @@ func InitTask(ctx context.Context, req InitTaskRequest) (InitResponse, error) {
 		Inline:       req.Inline,
 		Kind:         kind,
 		Def:          def,
+		SDKVersion:   req.SDKVersion,
 		InitResponse: &ret,
 	}); err != nil {
 		return InitResponse{}, err
@@ -741,6 +752,7 @@ type runKindSpecificInstallationRequest struct {
 	DryRun       bool
 	Inline       bool
 	Kind         buildtypes.TaskKind
+	SDKVersion   string
 	Def          definitions.Definition
 	InitResponse *InitResponse
 }
@@ -754,7 +766,7 @@ func runKindSpecificInstallation(ctx context.Context, req runKindSpecificInstall
 		}
 		packageJSONDir, packageJSONCreated, err := node.CreatePackageJSON(filepath.Dir(entrypoint), node.PackageJSONOptions{
 			Dependencies: node.NodeDependencies{
-				Dependencies:    []string{"airplane"},
+				Dependencies:    []string{nodeSDKPackage(req.SDKVersion)},
 				DevDependencies: []string{"@types/node"},
 			},
 		}, req.Prompter, req.Logger, req.DryRun)
@@ -805,10 +817,8 @@ func runKindSpecificInstallation(ctx context.Context, req runKindSpecificInstall
 			return err
 		}
 		var deps []python.PythonDependency
-		if req.Inline {
-			deps = []python.PythonDependency{
-				{Name: "airplanesdk", Version: "~=0.3.26"},
-			}
+		if req.Inline || req.SDKVersion != "" {
+			deps = []python.PythonDependency{pythonSDKDependency(req.SDKVersion)}
 		}
 		requirementsTxtDir, requirementsTxtCreated, err := python.CreateRequirementsTxt(filepath.Dir(entrypoint), python.RequirementsTxtOptions{
 			Dependencies: deps,
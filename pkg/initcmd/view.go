@@ -29,6 +29,8 @@ type InitViewRequest struct {
 	Name        string
 	Slug        string
 	Description string
+	// SDKVersion is the version of the JavaScript SDK to install, or "latest" (the default).
+	SDKVersion string
 
 	// ease of testing
 	suffixCharset string
@@ -102,7 +104,7 @@ func InitView(ctx context.Context, req InitViewRequest) (InitResponse, error) {
 		packageJSONDir = filepath.Join(req.WorkingDirectory, viewDir)
 	}
 	deps := []string{"@airplane/views", "react", "react-dom"}
-	deps = append(deps, "airplane")
+	deps = append(deps, nodeSDKPackage(req.SDKVersion))
 	packageJSONDir, packageJSONCreated, err := node.CreatePackageJSON(packageJSONDir, node.PackageJSONOptions{
 		Dependencies: node.NodeDependencies{
 			Dependencies:    deps,
@@ -0,0 +1,33 @@
+package outputs
+
+import (
+	"github.com/airplanedev/ojson"
+	"github.com/airplanedev/path"
+)
+
+// Get returns the value at path p of the outputs o. Unlike getLocation, o is never modified. ok
+// is false if o has no value at p.
+func Get(p path.P, o ojson.Value) (v interface{}, ok bool) {
+	cur := o.V
+	for _, component := range p.Components() {
+		switch c := component.(type) {
+		case string:
+			obj, isObj := cur.(*ojson.Object)
+			if !isObj {
+				return nil, false
+			}
+			if cur, ok = obj.Get(c); !ok {
+				return nil, false
+			}
+		case int:
+			arr, isArr := cur.([]interface{})
+			if !isArr || c >= len(arr) {
+				return nil, false
+			}
+			cur = arr[c]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
@@ -0,0 +1,46 @@
+package outputs
+
+import (
+	"testing"
+
+	"github.com/airplanedev/ojson"
+	"github.com/airplanedev/path"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	require := require.New(t)
+
+	var o ojson.Value
+	require.NoError(o.UnmarshalJSON([]byte(`{"rows":[{"name":"Alice"},{"name":null}],"count":2}`)))
+
+	v, ok := Get(path.Str("count"), o)
+	require.True(ok)
+	require.EqualValues(2, v)
+
+	v, ok = Get(path.Path(path.Str("rows"), path.Int(0), path.Str("name")), o)
+	require.True(ok)
+	require.Equal("Alice", v)
+
+	v, ok = Get(path.Path(path.Str("rows"), path.Int(1), path.Str("name")), o)
+	require.True(ok, "expected null values to be found")
+	require.Nil(v)
+
+	_, ok = Get(path.Path(path.Str("rows"), path.Int(2)), o)
+	require.False(ok)
+	_, ok = Get(path.Str("missing", "name"), o)
+	require.False(ok)
+	_, ok = Get(path.Str("count", "name"), o)
+	require.False(ok)
+
+	v, ok = Get(path.P{}, o)
+	require.True(ok)
+	require.Equal(o.V, v)
+	require.Equal(`{"rows":[{"name":"Alice"},{"name":null}],"count":2}`, mustMarshal(t, o), "expected o to be unchanged")
+}
+
+func mustMarshal(t *testing.T, o ojson.Value) string {
+	buf, err := o.MarshalJSON()
+	require.NoError(t, err)
+	return string(buf)
+}
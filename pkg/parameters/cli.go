@@ -1,6 +1,7 @@
 package parameters
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"reflect"
@@ -18,7 +19,7 @@ import (
 //
 // A flag.ErrHelp error will be returned if a -h or --help was provided, in which case
 // this function will print out help text on how to pass this task's parameters as flags.
-func CLI(args []string, taskName string, parameters libapi.Parameters, p prompts.Prompter) (api.Values, error) {
+func CLI(ctx context.Context, args []string, taskName string, parameters libapi.Parameters, p prompts.Prompter, opts CLIOpts) (api.Values, error) {
 	values := api.Values{}
 
 	if len(args) > 0 {
@@ -29,14 +30,27 @@ func CLI(args []string, taskName string, parameters libapi.Parameters, p prompts
 		}
 	} else {
 		// Otherwise, try to prompt for parameters
-		if err := promptForParamValues(parameters, values, p); err != nil {
+		if err := promptForParamValues(ctx, parameters, values, p, opts); err != nil {
 			return nil, err
 		}
 	}
 
+	if err := uploadFiles(ctx, parameters, values, opts.FileUploader); err != nil {
+		return nil, err
+	}
+
 	return values, nil
 }
 
+type CLIOpts struct {
+	// OptionsLoader loads options for parameters with an options source. Options are only loaded
+	// when prompting. If nil, such parameters are prompted for as free-form input.
+	OptionsLoader OptionsLoader
+	// FileUploader uploads the local files passed to upload parameters. If nil, upload parameters
+	// are not supported.
+	FileUploader FileUploader
+}
+
 // Flagset returns a new flagset from the given task parameters.
 func flagset(taskName string, parameters libapi.Parameters, args api.Values) *flag.FlagSet {
 	var set = flag.NewFlagSet(taskName, flag.ContinueOnError)
@@ -70,9 +84,11 @@ func flagset(taskName string, parameters libapi.Parameters, args api.Values) *fl
 // If TTY, prompts for parameters and then asks user to confirm.
 // If no TTY, errors.
 func promptForParamValues(
+	ctx context.Context,
 	parameters libapi.Parameters,
 	paramValues map[string]interface{},
 	p prompts.Prompter,
+	opts CLIOpts,
 ) error {
 	if len(parameters) == 0 {
 		return nil
@@ -94,41 +110,57 @@ func promptForParamValues(
 	}
 
 	for _, param := range parameters {
-		if param.Type == libapi.TypeUpload {
+		if param.Type == libapi.TypeUpload && opts.FileUploader == nil {
 			logger.Log(logger.Yellow("Skipping %s - uploads are not supported in CLI", param.Name))
 			continue
 		}
 
 		message := fmt.Sprintf("%s %s:", param.Name, logger.Gray("(--%s)", param.Slug))
+
+		if param.Constraints.OptionsSource != nil && opts.OptionsLoader != nil {
+			options, err := opts.OptionsLoader.LoadOptions(ctx, *param.Constraints.OptionsSource)
+			if err != nil {
+				return errors.Wrapf(err, "loading options for %s", param.Slug)
+			}
+			value, err := promptForOption(param, options, message, p)
+			if err != nil {
+				return err
+			}
+			if value != nil {
+				paramValues[param.Slug] = value
+			}
+			continue
+		}
+
 		defaultValue, err := APIValueToInput(param, param.Default)
 		if err != nil {
 			return err
 		}
 
-		opts := []prompts.Opt{
+		promptOpts := []prompts.Opt{
 			prompts.WithValidator(validateParam(param)),
 			prompts.WithHelp(param.Desc),
 		}
 		if !param.Constraints.Optional {
-			opts = append(opts, prompts.WithRequired())
+			promptOpts = append(promptOpts, prompts.WithRequired())
 		}
 		if param.Constraints.Regex != "" {
-			opts = append(opts, prompts.WithValidator(validateRegex(param.Constraints.Regex, param.Constraints.Optional)))
+			promptOpts = append(promptOpts, prompts.WithValidator(validateRegex(param.Constraints.Regex, param.Constraints.Optional)))
 		}
 		var inputValue string
 
 		switch param.Type {
 		case libapi.TypeBoolean:
 			if defaultValue != "" {
-				opts = append(opts, prompts.WithDefault(defaultValue))
+				promptOpts = append(promptOpts, prompts.WithDefault(defaultValue))
 			}
-			opts = append(opts, prompts.WithSelectOptions([]string{YesString, NoString}))
-			if err := p.Input(message, &inputValue, opts...); err != nil {
+			promptOpts = append(promptOpts, prompts.WithSelectOptions([]string{YesString, NoString}))
+			if err := p.Input(message, &inputValue, promptOpts...); err != nil {
 				return err
 			}
 		default:
-			opts = append(opts, prompts.WithDefault(defaultValue))
-			if err := p.Input(message, &inputValue, opts...); err != nil {
+			promptOpts = append(promptOpts, prompts.WithDefault(defaultValue))
+			if err := p.Input(message, &inputValue, promptOpts...); err != nil {
 				return err
 			}
 		}
@@ -151,6 +183,54 @@ func promptForParamValues(
 	return nil
 }
 
+// promptForOption prompts the user to select one of the given options and returns its value.
+func promptForOption(param libapi.Parameter, options []libapi.ConstraintOption, message string, p prompts.Prompter) (interface{}, error) {
+	if len(options) == 0 {
+		if !param.Constraints.Optional {
+			return nil, errors.Errorf("no options are available for %s", param.Slug)
+		}
+		return nil, nil
+	}
+
+	// Choices are keyed on their value: options that share a label are shown with their value so
+	// that each can still be selected.
+	labelCounts := make(map[string]int, len(options))
+	for _, opt := range options {
+		labelCounts[opt.Label]++
+	}
+	labels := make([]string, 0, len(options))
+	values := make(map[string]interface{}, len(options))
+	var defaultLabel interface{}
+	for _, opt := range options {
+		label := opt.Label
+		if labelCounts[label] > 1 {
+			label = fmt.Sprintf("%s (%v)", opt.Label, opt.Value)
+		}
+		if _, ok := values[label]; ok {
+			// Skip options that are duplicates of an earlier option.
+			continue
+		}
+		labels = append(labels, label)
+		values[label] = opt.Value
+		if defaultLabel == nil && param.Default != nil && reflect.DeepEqual(opt.Value, param.Default) {
+			defaultLabel = label
+		}
+	}
+
+	opts := []prompts.Opt{
+		prompts.WithHelp(param.Desc),
+		prompts.WithSelectOptions(labels),
+	}
+	if defaultLabel != nil {
+		opts = append(opts, prompts.WithDefault(defaultLabel))
+	}
+	var label string
+	if err := p.Input(message, &label, opts...); err != nil {
+		return nil, err
+	}
+	return values[label], nil
+}
+
 // validateParam returns a survey.Validator to perform rudimentary checks on CLI input
 func validateParam(param libapi.Parameter) func(interface{}) error {
 	return func(ans interface{}) error {
@@ -0,0 +1,37 @@
+package parameters
+
+import (
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/prompts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptForOptionDuplicateLabels(t *testing.T) {
+	require := require.New(t)
+
+	param := libapi.Parameter{Slug: "customer", Default: "c2"}
+	options := []libapi.ConstraintOption{
+		{Label: "Acme", Value: "c1"},
+		{Label: "Acme", Value: "c2"},
+		{Label: "Globex", Value: "c3"},
+		{Label: "Globex", Value: "c3"},
+	}
+
+	// Options that share a label can each be selected.
+	value, err := promptForOption(param, options, "Customer:", prompts.NewMock("Acme (c2)"))
+	require.NoError(err)
+	require.Equal("c2", value)
+
+	value, err = promptForOption(param, options, "Customer:", prompts.NewMock("Acme (c1)"))
+	require.NoError(err)
+	require.Equal("c1", value)
+
+	value, err = promptForOption(param, options, "Customer:", prompts.NewMock("Globex (c3)"))
+	require.NoError(err)
+	require.Equal("c3", value)
+
+	_, err = promptForOption(param, nil, "Customer:", prompts.NewMock())
+	require.Error(err)
+}
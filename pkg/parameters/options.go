@@ -0,0 +1,155 @@
+package parameters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/builtins"
+	"github.com/pkg/errors"
+)
+
+// OptionsLoader loads the options of a parameter whose options are populated by a source task or
+// builtin.
+type OptionsLoader interface {
+	LoadOptions(ctx context.Context, source libapi.OptionsSource) ([]libapi.ConstraintOption, error)
+}
+
+// TaskOptionsLoader loads options by running the source task or builtin through Client. Results
+// are cached so that a source shared by several parameters is only run once.
+type TaskOptionsLoader struct {
+	Client  api.APIClient
+	EnvSlug string
+
+	mu    sync.Mutex
+	cache map[string][]libapi.ConstraintOption
+}
+
+var _ OptionsLoader = &TaskOptionsLoader{}
+
+func (l *TaskOptionsLoader) LoadOptions(ctx context.Context, source libapi.OptionsSource) ([]libapi.ConstraintOption, error) {
+	key, err := json.Marshal(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling options source")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if options, ok := l.cache[string(key)]; ok {
+		return options, nil
+	}
+
+	paramValues := source.ParamValues
+	if paramValues == nil {
+		paramValues = api.Values{}
+	}
+	req := api.RunTaskRequest{
+		TaskSlug:    &source.Task,
+		ParamValues: paramValues,
+		EnvSlug:     l.EnvSlug,
+	}
+	if builtins.IsBuiltinTaskSlug(source.Task) {
+		// Builtins are run with resource IDs, rather than slugs.
+		if len(source.Resources) != 1 {
+			return nil, errors.Errorf("options source builtin %s requires exactly one resource", source.Task)
+		}
+		req.Resources = map[string]string{}
+		for alias, slug := range source.Resources {
+			res, err := l.Client.GetResource(ctx, api.GetResourceRequest{
+				Slug:    slug,
+				EnvSlug: l.EnvSlug,
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "getting resource %s", slug)
+			}
+			req.Resources[alias] = res.Resource.ID
+		}
+	}
+	w, err := l.Client.Watcher(ctx, req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "running options source task %s", source.Task)
+	}
+	var state api.RunState
+	for {
+		if state = w.Next(); state.Err() != nil || state.Stopped() {
+			break
+		}
+	}
+	if err := state.Err(); err != nil {
+		return nil, errors.Wrapf(err, "running options source task %s", source.Task)
+	}
+	if state.Status != api.RunSucceeded {
+		return nil, errors.Errorf("options source task %s did not succeed: %s", source.Task, state.Status)
+	}
+
+	// Round-trip the outputs through JSON so that nested objects are plain maps.
+	buf, err := json.Marshal(state.Outputs)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling outputs")
+	}
+	var output interface{}
+	if err := json.Unmarshal(buf, &output); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling outputs")
+	}
+	options, err := OptionsFromOutput(output, source.ValuePath, source.LabelPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading options from output of %s", source.Task)
+	}
+
+	if l.cache == nil {
+		l.cache = map[string][]libapi.ConstraintOption{}
+	}
+	l.cache[string(key)] = options
+	return options, nil
+}
+
+// OptionsFromOutput converts the output of an options source task into options. The output must be
+// a list of objects; valuePath and labelPath are dot-separated paths into each object. If labelPath
+// is empty, the value is used as the label.
+func OptionsFromOutput(output interface{}, valuePath, labelPath string) ([]libapi.ConstraintOption, error) {
+	items, ok := output.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected output to be a list but got %T", output)
+	}
+
+	options := make([]libapi.ConstraintOption, 0, len(items))
+	for i, item := range items {
+		value, ok := lookupPath(item, valuePath)
+		if !ok {
+			return nil, errors.Errorf("item %d is missing value path %q", i, valuePath)
+		}
+		label := fmt.Sprint(value)
+		if labelPath != "" {
+			l, ok := lookupPath(item, labelPath)
+			if !ok {
+				return nil, errors.Errorf("item %d is missing label path %q", i, labelPath)
+			}
+			label = fmt.Sprint(l)
+		}
+		options = append(options, libapi.ConstraintOption{
+			Label: label,
+			Value: value,
+		})
+	}
+	return options, nil
+}
+
+func lookupPath(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return v, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
@@ -4,6 +4,7 @@ package parameters
 
 import (
 	"context"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -48,10 +49,13 @@ func ValidateInput(param libapi.Parameter, in string) error {
 		}
 
 	case libapi.TypeUpload:
-		if in != "" {
-			// TODO(amir): we need to support them with some special
-			// character perhaps `@` like curl?
-			return errors.New("uploads are not supported from the CLI")
+		// Uploads are passed as the path of a local file.
+		info, err := os.Stat(in)
+		if err != nil {
+			return errors.Errorf("file %s does not exist", in)
+		}
+		if info.IsDir() {
+			return errors.Errorf("%s is a directory", in)
 		}
 
 	case libapi.TypeDate:
@@ -95,11 +99,11 @@ func ParseInput(param libapi.Parameter, in string) (interface{}, error) {
 		return v, nil
 
 	case libapi.TypeUpload:
-		// TODO: ideally we read the file input here for API
-		if in != "" {
-			return nil, errors.New("uploads are not supported from the CLI")
+		// The file is uploaded once every parameter has been parsed, see uploadFiles.
+		if err := ValidateInput(param, in); err != nil {
+			return nil, err
 		}
-		return nil, nil
+		return in, nil
 
 	case libapi.TypeConfigVar:
 		return map[string]interface{}{
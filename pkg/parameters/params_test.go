@@ -2,10 +2,14 @@ package parameters_test
 
 import (
 	"context"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	libapi "github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/deploy/archive"
 	"github.com/airplanedev/cli/pkg/parameters"
 	"github.com/stretchr/testify/require"
 )
@@ -80,3 +84,86 @@ func TestStandardizeParams(t *testing.T) {
 	}, standardizedValues["file"])
 	require.Equal("2006-01-02", standardizedValues["date"]) // should be converted into a date string
 }
+
+type fakeUploader struct {
+	contents map[string]string
+}
+
+var _ archive.Uploader = &fakeUploader{}
+
+func (u *fakeUploader) Upload(ctx context.Context, url string, f *os.File) error {
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	u.contents[filepath.Base(f.Name())] = string(buf)
+	return nil
+}
+
+func TestCLIUploads(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "invoice.csv")
+	require.NoError(os.WriteFile(path, []byte("id,amount\n1,10\n"), 0644))
+
+	client := &api.MockClient{Uploads: map[string]libapi.Upload{}}
+	uploader := &fakeUploader{contents: map[string]string{}}
+	params := libapi.Parameters{
+		{Name: "Invoice", Slug: "invoice", Type: libapi.TypeUpload},
+		{Name: "Note", Slug: "note", Type: libapi.TypeString},
+	}
+
+	values, err := parameters.CLI(ctx, []string{"--invoice", path, "--note", "hi"}, "Import", params, nil, parameters.CLIOpts{
+		FileUploader: &parameters.APIFileUploader{Client: client, Uploader: uploader},
+	})
+	require.NoError(err)
+	require.Equal("hi", values["note"])
+	require.Len(client.Uploads, 1)
+	for id, upload := range client.Uploads {
+		require.Equal(id, values["invoice"])
+		require.Equal("invoice.csv", upload.FileName)
+		require.Equal(15, upload.SizeBytes)
+	}
+	require.Equal(map[string]string{"invoice.csv": "id,amount\n1,10\n"}, uploader.contents)
+
+	// Uploads require an uploader.
+	_, err = parameters.CLI(ctx, []string{"--invoice", path}, "Import", params, nil, parameters.CLIOpts{})
+	require.Error(err)
+
+	// Uploaded files must exist.
+	_, err = parameters.CLI(ctx, []string{"--invoice", path + ".missing"}, "Import", params, nil, parameters.CLIOpts{
+		FileUploader: &parameters.APIFileUploader{Client: client, Uploader: uploader},
+	})
+	require.Error(err)
+}
+
+func TestOptionsFromOutput(t *testing.T) {
+	require := require.New(t)
+
+	output := []interface{}{
+		map[string]interface{}{"id": float64(1), "profile": map[string]interface{}{"name": "Acme"}},
+		map[string]interface{}{"id": float64(2), "profile": map[string]interface{}{"name": "Globex"}},
+	}
+
+	options, err := parameters.OptionsFromOutput(output, "id", "profile.name")
+	require.NoError(err)
+	require.Equal([]libapi.ConstraintOption{
+		{Label: "Acme", Value: float64(1)},
+		{Label: "Globex", Value: float64(2)},
+	}, options)
+
+	// The value is used as the label if no label path is set.
+	options, err = parameters.OptionsFromOutput(output, "profile.name", "")
+	require.NoError(err)
+	require.Equal([]libapi.ConstraintOption{
+		{Label: "Acme", Value: "Acme"},
+		{Label: "Globex", Value: "Globex"},
+	}, options)
+
+	_, err = parameters.OptionsFromOutput(output, "missing", "")
+	require.Error(err)
+
+	_, err = parameters.OptionsFromOutput(map[string]interface{}{"id": 1}, "id", "")
+	require.Error(err)
+}
@@ -0,0 +1,79 @@
+package parameters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/deploy/archive"
+	"github.com/pkg/errors"
+)
+
+// FileUploader uploads the local files passed to upload parameters.
+type FileUploader interface {
+	// Upload uploads the file at path and returns the ID of the upload.
+	Upload(ctx context.Context, path string) (string, error)
+}
+
+// APIFileUploader uploads files with the uploads API.
+type APIFileUploader struct {
+	Client api.APIClient
+	// Uploader writes files to the URLs returned by the API. Defaults to an HTTP uploader.
+	Uploader archive.Uploader
+}
+
+var _ FileUploader = &APIFileUploader{}
+
+func (u *APIFileUploader) Upload(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrap(err, "opening file")
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", errors.Wrap(err, "stat on file")
+	}
+
+	resp, err := u.Client.CreateUpload(ctx, libapi.CreateUploadRequest{
+		FileName:  filepath.Base(path),
+		SizeBytes: int(info.Size()),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "creating upload")
+	}
+
+	uploader := u.Uploader
+	if uploader == nil {
+		uploader = &archive.HttpUploader{}
+	}
+	if err := uploader.Upload(ctx, resp.WriteOnlyURL, f); err != nil {
+		return "", errors.Wrapf(err, "uploading %s", path)
+	}
+	return resp.Upload.ID, nil
+}
+
+// uploadFiles replaces the file paths of upload parameters in values with the IDs of the uploaded
+// files.
+func uploadFiles(ctx context.Context, parameters libapi.Parameters, values api.Values, uploader FileUploader) error {
+	for _, param := range parameters {
+		if param.Type != libapi.TypeUpload {
+			continue
+		}
+		path, ok := values[param.Slug].(string)
+		if !ok || path == "" {
+			continue
+		}
+		if uploader == nil {
+			return errors.Errorf("uploads are not supported for %s", param.Slug)
+		}
+		id, err := uploader.Upload(ctx, path)
+		if err != nil {
+			return errors.Wrapf(err, "uploading %s", param.Slug)
+		}
+		values[param.Slug] = id
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package parameters
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
+	"github.com/pkg/errors"
+)
+
+// Constraints that parameter values are validated against.
+const (
+	ConstraintRequired = "required"
+	ConstraintType     = "type"
+	ConstraintRegex    = "regex"
+	ConstraintOptions  = "options"
+)
+
+// ValidationError is returned when parameter values violate their parameters' constraints. It
+// lists every violation, so that callers can point at the specific parameters that are invalid.
+type ValidationError struct {
+	Issues []libhttp.Issue `json:"issues"`
+}
+
+// Error implementation.
+func (err ValidationError) Error() string {
+	msgs := make([]string, len(err.Issues))
+	for i, issue := range err.Issues {
+		msgs[i] = fmt.Sprintf("%s: %s", issue.Param, issue.Message)
+	}
+	return "invalid parameters: " + strings.Join(msgs, "; ")
+}
+
+// ExplainError implementation.
+func (err ValidationError) ExplainError() string {
+	lines := make([]string, len(err.Issues))
+	for i, issue := range err.Issues {
+		lines[i] = fmt.Sprintf("  --%s: %s (%s)", issue.Param, issue.Message, issue.Constraint)
+	}
+	return "The following parameters are invalid:\n" + strings.Join(lines, "\n")
+}
+
+// StatusError returns the error as a bad request that includes the individual issues.
+func (err ValidationError) StatusError() libhttp.ErrStatusCode {
+	return libhttp.NewErrInvalidParams(err.Issues, "%s", err.Error())
+}
+
+// AsValidationError returns the issues of err if it is a ValidationError, or a bad request
+// returned by the API that lists issues.
+func AsValidationError(err error) (ValidationError, bool) {
+	var verr ValidationError
+	if errors.As(err, &verr) {
+		return verr, true
+	}
+	var errsc libhttp.ErrStatusCode
+	if errors.As(err, &errsc) && len(errsc.Issues) > 0 {
+		return ValidationError{Issues: errsc.Issues}, true
+	}
+	return ValidationError{}, false
+}
+
+// Validate checks values against the constraints of parameters. If any values are invalid, a
+// ValidationError is returned that lists each violation, in the order of parameters.
+//
+// Parameters with a default value are not required, since the default is applied when the run
+// is created.
+func Validate(parameters libapi.Parameters, values api.Values) error {
+	var issues []libhttp.Issue
+	for _, param := range parameters {
+		if issue := validateValue(param, values[param.Slug]); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+	if len(issues) > 0 {
+		return ValidationError{Issues: issues}
+	}
+	return nil
+}
+
+func validateValue(param libapi.Parameter, value interface{}) *libhttp.Issue {
+	newIssue := func(constraint, msg string, args ...interface{}) *libhttp.Issue {
+		return &libhttp.Issue{
+			Param:      param.Slug,
+			Constraint: constraint,
+			Message:    fmt.Sprintf(msg, args...),
+		}
+	}
+
+	if value == nil || value == "" {
+		if !param.Constraints.Optional && param.Default == nil && param.Type != libapi.TypeBoolean {
+			return newIssue(ConstraintRequired, "a value is required")
+		}
+		return nil
+	}
+
+	if !hasType(param.Type, value) {
+		return newIssue(ConstraintType, "expected a value of type %s", param.Type)
+	}
+
+	if param.Constraints.Regex != "" {
+		if s, ok := value.(string); ok {
+			matched, err := regexp.MatchString(param.Constraints.Regex, s)
+			if err != nil {
+				return newIssue(ConstraintRegex, "invalid regex pattern %s: %s", param.Constraints.Regex, err)
+			}
+			if !matched {
+				return newIssue(ConstraintRegex, "must match regex pattern: %s", param.Constraints.Regex)
+			}
+		}
+	}
+
+	if len(param.Constraints.Options) > 0 {
+		var labels []string
+		for _, opt := range param.Constraints.Options {
+			if equalValues(opt.Value, value) {
+				return nil
+			}
+			labels = append(labels, opt.Label)
+		}
+		return newIssue(ConstraintOptions, "must be one of: %s", strings.Join(labels, ", "))
+	}
+
+	return nil
+}
+
+// hasType returns whether value can be passed to a parameter of type t. Unknown types, and
+// types whose values are objects (uploads, config vars), are not checked.
+func hasType(t libapi.Type, value interface{}) bool {
+	switch t {
+	case libapi.TypeString, libapi.TypeDate, libapi.TypeDatetime:
+		_, ok := value.(string)
+		return ok
+	case libapi.TypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case libapi.TypeInteger:
+		switch v := value.(type) {
+		case int, int32, int64:
+			return true
+		case float64:
+			return v == float64(int64(v))
+		}
+		return false
+	case libapi.TypeFloat:
+		switch value.(type) {
+		case int, int32, int64, float32, float64:
+			return true
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// equalValues compares values by their JSON encoding, since values parsed from the CLI and
+// values decoded from JSON use different Go types for the same number.
+func equalValues(a, b interface{}) bool {
+	ab, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ab) == string(bb)
+}
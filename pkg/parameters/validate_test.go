@@ -0,0 +1,68 @@
+package parameters_test
+
+import (
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/api/cliapi"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
+	"github.com/airplanedev/cli/pkg/parameters"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	params := libapi.Parameters{
+		{Slug: "name", Type: libapi.TypeString, Constraints: libapi.Constraints{Regex: "^[a-z]+$"}},
+		{Slug: "count", Type: libapi.TypeInteger, Constraints: libapi.Constraints{Optional: true}},
+		{Slug: "size", Type: libapi.TypeInteger, Constraints: libapi.Constraints{
+			Options: []libapi.ConstraintOption{
+				{Label: "Small", Value: float64(1)},
+				{Label: "Large", Value: float64(2)},
+			},
+		}},
+		{Slug: "region", Type: libapi.TypeString, Default: "us"},
+		{Slug: "dry_run", Type: libapi.TypeBoolean},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require := require.New(t)
+		require.NoError(parameters.Validate(params, api.Values{
+			"name": "abc",
+			"size": 2,
+		}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		require := require.New(t)
+		err := parameters.Validate(params, api.Values{
+			"name":  "ABC",
+			"count": "three",
+		})
+		var verr parameters.ValidationError
+		require.True(errors.As(err, &verr))
+		require.Equal([]libhttp.Issue{
+			{Param: "name", Constraint: parameters.ConstraintRegex, Message: "must match regex pattern: ^[a-z]+$"},
+			{Param: "count", Constraint: parameters.ConstraintType, Message: "expected a value of type integer"},
+			{Param: "size", Constraint: parameters.ConstraintRequired, Message: "a value is required"},
+		}, verr.Issues)
+		require.Equal("invalid parameters: name: must match regex pattern: ^[a-z]+$; count: expected a value of type integer; size: a value is required", err.Error())
+
+		err = parameters.Validate(params, api.Values{"name": "abc", "size": 3})
+		require.ErrorContains(err, "size: must be one of: Small, Large")
+	})
+}
+
+func TestAsValidationError(t *testing.T) {
+	require := require.New(t)
+
+	issues := []libhttp.Issue{{Param: "name", Constraint: parameters.ConstraintRequired, Message: "a value is required"}}
+	verr, ok := parameters.AsValidationError(errors.Wrap(libhttp.NewErrInvalidParams(issues, "invalid"), "running task"))
+	require.True(ok)
+	require.Equal(issues, verr.Issues)
+
+	_, ok = parameters.AsValidationError(libhttp.NewErrBadRequest("invalid"))
+	require.False(ok)
+	_, ok = parameters.AsValidationError(nil)
+	require.False(ok)
+}
@@ -60,3 +60,8 @@ func (j *JSON) outputs(outputs api.Outputs) {
 func (j *JSON) config(config api.Config) {
 	handleErr(j.enc.Encode(config))
 }
+
+// Configs implementation.
+func (j *JSON) configs(configs []api.Config) {
+	handleErr(j.enc.Encode(configs))
+}
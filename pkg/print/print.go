@@ -25,6 +25,7 @@ type Formatter interface {
 	run(api.Run)
 	outputs(api.Outputs)
 	config(api.Config)
+	configs([]api.Config)
 }
 
 // APIKeys prints one or more API keys.
@@ -62,6 +63,11 @@ func Config(config api.Config) {
 	DefaultFormatter.config(config)
 }
 
+// Configs prints one or more config vars.
+func Configs(configs []api.Config) {
+	DefaultFormatter.configs(configs)
+}
+
 // Print outputs obj based on DefaultFormatter
 // If JSON or YAML, uses that formatter to encode obj
 // Otherwise, calls defaultPrintFunc to render the obj
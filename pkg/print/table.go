@@ -50,6 +50,10 @@ func (t Table) tasks(tasks []libapi.Task) {
 
 	for _, t := range tasks {
 		builder := string(t.Kind)
+		name := t.Name
+		if t.IsArchived {
+			name += " (archived)"
+		}
 
 		var parametersStr string
 		if len(t.Parameters) > 0 {
@@ -75,7 +79,7 @@ func (t Table) tasks(tasks []libapi.Task) {
 		}
 
 		tw.Append([]string{
-			t.Name,
+			name,
 			t.Slug,
 			builder,
 			parametersStr,
@@ -287,3 +291,25 @@ func (t Table) config(config api.Config) {
 	}
 	fmt.Fprintln(os.Stdout, valueStr)
 }
+
+// Configs implementation.
+func (t Table) configs(configs []api.Config) {
+	tw := tablewriter.NewWriter(os.Stdout)
+	tw.SetBorder(false)
+	tw.SetAutoWrapText(false)
+	tw.SetHeader([]string{"name", "value"})
+
+	for _, c := range configs {
+		name := c.Name
+		if c.Tag != "" {
+			name += ":" + c.Tag
+		}
+		value := c.Value
+		if c.IsSecret {
+			value = "<secret value hidden>"
+		}
+		tw.Append([]string{name, value})
+	}
+
+	tw.Render()
+}
@@ -16,6 +16,7 @@ type printTask struct {
 	Slug                       string                       `json:"slug" yaml:"slug"`
 	Description                string                       `json:"description" yaml:"description"`
 	Image                      *string                      `json:"image" yaml:"image"`
+	ImageDigest                string                       `json:"imageDigest,omitempty" yaml:"imageDigest,omitempty"`
 	Command                    []string                     `json:"command" yaml:"command"`
 	Arguments                  []string                     `json:"arguments" yaml:"arguments"`
 	Parameters                 libapi.Parameters            `json:"parameters" yaml:"parameters"`
@@ -33,6 +34,8 @@ type printTask struct {
 	DefaultRunPermissions      libapi.DefaultRunPermissions `json:"defaultRunPermissions" yaml:"defaultRunPermissions"`
 	ExecuteRules               libapi.ExecuteRules          `json:"executeRules" yaml:"executeRules"`
 	Timeout                    int                          `json:"timeout" yaml:"timeout"`
+	Priority                   int                          `json:"priority" yaml:"priority"`
+	RunAs                      string                       `json:"runAs" yaml:"runAs"`
 	IsArchived                 bool                         `json:"isArchived" yaml:"isArchived"`
 	InterpolationMode          string                       `json:"-" yaml:"-"`
 	Triggers                   []libapi.Trigger             `json:"-" yaml:"-"`
@@ -66,6 +69,7 @@ type printRun struct {
 	CancelledAt *time.Time         `json:"cancelledAt" yaml:"cancelledAt"`
 	CancelledBy *string            `json:"cancelledBy" yaml:"cancelledBy"`
 	EnvSlug     string             `json:"envSlug" yaml:"envSlug"`
+	RunAs       string             `json:"runAs,omitempty" yaml:"runAs,omitempty"`
 }
 
 func printRuns(runs []api.Run) []printRun {
@@ -53,3 +53,8 @@ func (YAML) outputs(outputs api.Outputs) {
 func (YAML) config(config api.Config) {
 	handleErr(yaml.NewEncoder(os.Stdout).Encode(config))
 }
+
+// Configs implementation.
+func (YAML) configs(configs []api.Config) {
+	handleErr(yaml.NewEncoder(os.Stdout).Encode(configs))
+}
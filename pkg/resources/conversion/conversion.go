@@ -1,49 +1,116 @@
 package conversion
 
 import (
+	"encoding/json"
+	"sort"
+
 	"github.com/airplanedev/cli/pkg/resources"
 	"github.com/airplanedev/cli/pkg/resources/kind_configs"
-	"github.com/airplanedev/cli/pkg/resources/kinds"
+	_ "github.com/airplanedev/cli/pkg/resources/kinds"
+	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 )
 
+// ConvertToInternalResource wraps an exported resource in the deprecated kind_configs format,
+// so that endpoints that still return that format are served from the exported kinds. Any
+// registered kind is supported. Unlike kinds.New, the resource isn't validated, so that stored
+// resources are returned as-is.
 func ConvertToInternalResource(r resources.Resource) (kind_configs.InternalResource, error) {
-	var base resources.BaseResource
-	switch resource := r.(type) {
-	case *kinds.BigQueryResource:
-		base = resource.BaseResource
-	case *kinds.GraphQLResource:
-		base = resource.BaseResource
-	case *kinds.MailgunResource:
-		base = resource.BaseResource
-	case *kinds.MongoDBResource:
-		base = resource.BaseResource
-	case *kinds.MySQLResource:
-		base = resource.BaseResource
-	case *kinds.PostgresResource:
-		base = resource.BaseResource
-	case *kinds.RedshiftResource:
-		base = resource.BaseResource
-	case *kinds.RESTResource:
-		base = resource.BaseResource
-	case *kinds.SendGridResource:
-		base = resource.BaseResource
-	case *kinds.SlackResource:
-		base = resource.BaseResource
-	case *kinds.SMTPResource:
-		base = resource.BaseResource
-	case *kinds.SnowflakeResource:
-		base = resource.BaseResource
-	case *kinds.SQLServerResource:
-		base = resource.BaseResource
-	default:
-		return kind_configs.InternalResource{}, errors.Errorf("Unknown resource type %T", resource)
+	if r == nil {
+		return kind_configs.InternalResource{}, errors.New("resource is nil")
+	}
+	if _, ok := resources.ResourceFactories[r.GetKind()]; !ok {
+		return kind_configs.InternalResource{}, errors.Errorf("Unknown resource type %T", r)
 	}
 	return kind_configs.InternalResource{
-		ID:             base.ID,
-		Slug:           base.Slug,
-		Name:           base.Name,
-		Kind:           base.Kind,
+		ID:             r.GetID(),
+		Slug:           r.GetSlug(),
+		Name:           r.GetName(),
+		Kind:           r.GetKind(),
 		ExportResource: r,
 	}, nil
 }
+
+// KindConfigKey is the field under which resources in the deprecated kind config format store
+// their kind-specific fields, e.g. {"kind": "postgres", "kindConfig": {"postgres": {"host": ...}}}.
+const KindConfigKey = "kindConfig"
+
+// IsKindConfig returns whether a serialized resource is in the deprecated kind config format.
+func IsKindConfig(raw map[string]interface{}) bool {
+	_, ok := raw[KindConfigKey]
+	return ok
+}
+
+// FromKindConfig converts a serialized resource in the deprecated kind config format into the
+// exported format. Fields that the exported kind doesn't support are dropped and returned as
+// unconvertible, e.g. "kindConfig.postgres.tunnel".
+func FromKindConfig(raw map[string]interface{}) (_ map[string]interface{}, unconvertible []string, _ error) {
+	kind, ok := raw["kind"].(string)
+	if !ok || kind == "" {
+		return nil, nil, errors.New("missing kind property in resource")
+	}
+
+	// Fields outside of the kind config take precedence, since they are already in the exported
+	// format.
+	fields := map[string]interface{}{}
+	prefixes := map[string]string{}
+	if kindConfig, ok := raw[KindConfigKey].(map[string]interface{}); ok {
+		config := kindConfig
+		prefix := KindConfigKey + "."
+		if nested, ok := kindConfig[kind].(map[string]interface{}); ok {
+			config = nested
+			prefix = KindConfigKey + "." + kind + "."
+			for k := range kindConfig {
+				if k != kind {
+					unconvertible = append(unconvertible, KindConfigKey+"."+k)
+				}
+			}
+		}
+		for k, v := range config {
+			fields[k] = v
+			prefixes[k] = prefix
+		}
+	} else if raw[KindConfigKey] != nil {
+		return nil, nil, errors.Errorf("expected %s to be an object, got %T", KindConfigKey, raw[KindConfigKey])
+	}
+	for k, v := range raw {
+		if k != KindConfigKey {
+			fields[k] = v
+			delete(prefixes, k)
+		}
+	}
+
+	resource, err := resources.GetResource(resources.ResourceKind(kind), map[string]interface{}{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var md mapstructure.Metadata
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:  "mapstructure",
+		Result:   &resource,
+		Metadata: &md,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "creating decoder")
+	}
+	if err := decoder.Decode(fields); err != nil {
+		return nil, nil, errors.Wrapf(err, "decoding %s resource", kind)
+	}
+	for _, k := range md.Unused {
+		unconvertible = append(unconvertible, prefixes[k]+k)
+	}
+	sort.Strings(unconvertible)
+
+	// Round-trip through the exported kind, so that the result has exactly the fields the kind
+	// serializes.
+	resource.ScrubCalculatedFields()
+	buf, err := json.Marshal(resource)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "marshaling resource")
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshaling resource")
+	}
+	return out, unconvertible, nil
+}
@@ -0,0 +1,64 @@
+package conversion
+
+import (
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/resources"
+	"github.com/airplanedev/cli/pkg/resources/kinds"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToInternalResource(t *testing.T) {
+	require := require.New(t)
+
+	r := &kinds.PostgresResource{
+		BaseResource: resources.BaseResource{
+			Kind: kinds.ResourceKindPostgres,
+			ID:   "res123",
+			Slug: "db",
+			Name: "DB",
+		},
+		Host: "localhost",
+	}
+	internal, err := ConvertToInternalResource(r)
+	require.NoError(err)
+	require.Equal("res123", internal.ID)
+	require.Equal("db", internal.Slug)
+	require.Equal(kinds.ResourceKindPostgres, internal.Kind)
+	require.Equal(r, internal.ExportResource)
+}
+
+func TestFromKindConfig(t *testing.T) {
+	require := require.New(t)
+
+	raw := map[string]interface{}{
+		"kind": "postgres",
+		"slug": "db",
+		"name": "DB",
+		"kindConfig": map[string]interface{}{
+			"postgres": map[string]interface{}{
+				"host":     "localhost",
+				"port":     "5432",
+				"username": "postgres",
+				"tunnel":   "legacy",
+			},
+			"mysql": map[string]interface{}{},
+		},
+	}
+	require.True(IsKindConfig(raw))
+
+	out, unconvertible, err := FromKindConfig(raw)
+	require.NoError(err)
+	require.False(IsKindConfig(out))
+	require.Equal("postgres", out["kind"])
+	require.Equal("db", out["slug"])
+	require.Equal("DB", out["name"])
+	require.Equal("localhost", out["host"])
+	require.Equal("5432", out["port"])
+	require.Equal("postgres", out["username"])
+	require.NotContains(out, "tunnel")
+	require.Equal([]string{"kindConfig.mysql", "kindConfig.postgres.tunnel"}, unconvertible)
+
+	_, _, err = FromKindConfig(map[string]interface{}{"kindConfig": map[string]interface{}{}})
+	require.ErrorContains(err, "missing kind")
+}
@@ -0,0 +1,188 @@
+package kinds
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/airplanedev/cli/pkg/resources"
+	"github.com/pkg/errors"
+)
+
+// ExportedResource pairs a resource's identity with its typed, kind-specific config, e.g. a
+// *PostgresResource. It's the supported way to create, update and validate resources when
+// embedding the CLI as a library: see New, Update and Validate.
+type ExportedResource struct {
+	ID   string                 `json:"id" db:"id"`
+	Slug string                 `json:"slug" db:"slug"`
+	Name string                 `json:"name" db:"name"`
+	Kind resources.ResourceKind `json:"kind" db:"kind"`
+	// ExportResource is the kind-specific config of the resource, e.g. *PostgresResource.
+	ExportResource resources.Resource `json:"resource"`
+}
+
+func (r *ExportedResource) UnmarshalJSON(buf []byte) error {
+	var raw struct {
+		ID             string                 `json:"id"`
+		Slug           string                 `json:"slug"`
+		Name           string                 `json:"name"`
+		Kind           resources.ResourceKind `json:"kind"`
+		ExportResource map[string]interface{} `json:"resource"`
+	}
+	if err := json.Unmarshal(buf, &raw); err != nil {
+		return err
+	}
+
+	var export resources.Resource
+	var err error
+	if raw.ExportResource != nil {
+		export, err = resources.GetResource(resources.ResourceKind(raw.Kind), raw.ExportResource)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.ID = raw.ID
+	r.Slug = raw.Slug
+	r.Name = raw.Name
+	r.Kind = raw.Kind
+	r.ExportResource = export
+
+	return nil
+}
+
+func (r ExportedResource) ToExternalResource() (resources.Resource, error) {
+	return r.ExportResource, nil
+}
+
+// New wraps a typed resource, e.g. a *RESTResource, in an ExportedResource. The resource's
+// kind is filled in if it isn't set, its calculated fields are computed and it is validated.
+func New(r resources.Resource) (ExportedResource, error) {
+	if r == nil || (reflect.ValueOf(r).Kind() == reflect.Pointer && reflect.ValueOf(r).IsNil()) {
+		return ExportedResource{}, errors.New("resource is nil")
+	}
+	kind, err := KindOf(r)
+	if err != nil {
+		return ExportedResource{}, err
+	}
+	if r.GetKind() == "" {
+		if err := r.UpdateBaseResource(resources.BaseResource{Kind: kind}); err != nil {
+			return ExportedResource{}, errors.Wrap(err, "setting resource kind")
+		}
+	}
+	if err := r.Calculate(); err != nil {
+		return ExportedResource{}, errors.Wrap(err, "computing calculated fields")
+	}
+
+	e := ExportedResource{
+		ID:             r.GetID(),
+		Slug:           r.GetSlug(),
+		Name:           r.GetName(),
+		Kind:           kind,
+		ExportResource: r,
+	}
+	if err := Validate(e); err != nil {
+		return ExportedResource{}, err
+	}
+	return e, nil
+}
+
+// FromMap decodes a serialized resource of the given kind, e.g. the "resource" field of an API
+// response.
+func FromMap(kind resources.ResourceKind, serialized map[string]interface{}) (ExportedResource, error) {
+	r, err := resources.GetResource(kind, serialized)
+	if err != nil {
+		return ExportedResource{}, err
+	}
+	return New(r)
+}
+
+// ToMap serializes the kind-specific config of a resource, the inverse of FromMap.
+func ToMap(e ExportedResource) (map[string]interface{}, error) {
+	if e.ExportResource == nil {
+		return nil, errors.New("resource is nil")
+	}
+	buf, err := json.Marshal(e.ExportResource)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling resource")
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling resource")
+	}
+	return m, nil
+}
+
+// As returns the typed config of a resource, e.g. As[*PostgresResource](e). It returns an
+// error if the resource is of a different kind.
+func As[T resources.Resource](e ExportedResource) (T, error) {
+	r, ok := e.ExportResource.(T)
+	if !ok {
+		var zero T
+		return zero, errors.Errorf("expected resource %s to be %T, got %T", e.Slug, zero, e.ExportResource)
+	}
+	return r, nil
+}
+
+// Update applies update, which must be of the same kind, to the resource and validates the result.
+// Secrets that are empty in update, such as passwords, are left unchanged.
+func Update(e *ExportedResource, update resources.Resource) error {
+	if e.ExportResource == nil {
+		return errors.New("resource is nil")
+	}
+	if err := e.ExportResource.Update(update); err != nil {
+		return errors.Wrapf(err, "updating resource %s", e.Slug)
+	}
+	if update.GetName() != "" {
+		e.Name = update.GetName()
+	}
+	if update.GetSlug() != "" {
+		e.Slug = update.GetSlug()
+	}
+	if err := e.ExportResource.UpdateBaseResource(resources.BaseResource{Name: e.Name, Slug: e.Slug}); err != nil {
+		return errors.Wrapf(err, "updating resource %s", e.Slug)
+	}
+	return Validate(*e)
+}
+
+// Validate returns an error if the resource's config is invalid or doesn't match its kind.
+func Validate(e ExportedResource) error {
+	if e.ExportResource == nil {
+		return errors.New("resource is nil")
+	}
+	kind, err := KindOf(e.ExportResource)
+	if err != nil {
+		return err
+	}
+	if e.Kind != kind {
+		return errors.Errorf("resource %s has kind %s, but its config is for %s", e.Slug, e.Kind, kind)
+	}
+	if err := e.ExportResource.Validate(); err != nil {
+		return errors.Wrapf(err, "invalid %s resource %s", kind, e.Slug)
+	}
+	return nil
+}
+
+// KindOf returns the registered kind of a typed resource, e.g. "postgres" for a
+// *PostgresResource.
+func KindOf(r resources.Resource) (resources.ResourceKind, error) {
+	kindsByTypeOnce.Do(func() {
+		kindsByType = make(map[reflect.Type]resources.ResourceKind, len(resources.ResourceFactories))
+		for kind, factory := range resources.ResourceFactories {
+			if zero, err := factory(map[string]interface{}{}); err == nil {
+				kindsByType[reflect.TypeOf(zero)] = kind
+			}
+		}
+	})
+	if kind, ok := kindsByType[reflect.TypeOf(r)]; ok {
+		return kind, nil
+	}
+	return "", errors.Errorf("unknown resource type %T", r)
+}
+
+var (
+	// kindsByType maps the type of each registered kind's resources to the kind. Kinds are
+	// registered by init functions, so it's built on first use.
+	kindsByType     map[reflect.Type]resources.ResourceKind
+	kindsByTypeOnce sync.Once
+)
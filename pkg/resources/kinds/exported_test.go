@@ -0,0 +1,75 @@
+package kinds
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportedResource(t *testing.T) {
+	require := require.New(t)
+
+	e, err := New(&PostgresResource{
+		BaseResource: resources.BaseResource{Slug: "db", Name: "DB"},
+		Username:     "postgres",
+		Password:     "secret",
+		Host:         "localhost",
+		Port:         "5432",
+		Database:     "app",
+		SSLMode:      "disable",
+	})
+	require.NoError(err)
+	require.Equal(ResourceKindPostgres, e.Kind)
+	require.Equal("db", e.Slug)
+
+	pg, err := As[*PostgresResource](e)
+	require.NoError(err)
+	require.Equal(ResourceKindPostgres, pg.GetKind())
+	require.NotEmpty(pg.DSN, "expected calculated fields to be computed")
+	_, err = As[*RESTResource](e)
+	require.ErrorContains(err, "expected resource db to be *kinds.RESTResource")
+
+	// Empty secrets in the update are left unchanged.
+	require.NoError(Update(&e, &PostgresResource{
+		BaseResource: resources.BaseResource{Name: "App DB"},
+		Username:     "postgres",
+		Host:         "db.example.com",
+		Port:         "5432",
+		Database:     "app",
+		SSLMode:      "require",
+	}))
+	require.Equal("App DB", e.Name)
+	require.Equal("db.example.com", pg.Host)
+	require.Equal("secret", pg.Password)
+	require.ErrorContains(Update(&e, &RESTResource{BaseURL: "https://example.com"}), "expected *PostgresResource")
+
+	// Round-trip through the serialized form.
+	m, err := ToMap(e)
+	require.NoError(err)
+	e2, err := FromMap(ResourceKindPostgres, m)
+	require.NoError(err)
+	require.Equal(e, e2)
+
+	buf, err := json.Marshal(e)
+	require.NoError(err)
+	var e3 ExportedResource
+	require.NoError(json.Unmarshal(buf, &e3))
+	require.Equal(e, e3)
+}
+
+func TestValidate(t *testing.T) {
+	require := require.New(t)
+
+	_, err := New(&RESTResource{BaseResource: resources.BaseResource{Slug: "api"}})
+	require.ErrorContains(err, "invalid rest resource api")
+
+	_, err = New(nil)
+	require.ErrorContains(err, "resource is nil")
+
+	e, err := New(&RESTResource{BaseURL: "https://example.com"})
+	require.NoError(err)
+	e.Kind = ResourceKindPostgres
+	require.ErrorContains(Validate(e), "has kind postgres, but its config is for rest")
+}
@@ -0,0 +1,203 @@
+// Package resourcedef reads and writes resource definition files, which declare a resource as
+// code so that it can be created without going through the web UI.
+//
+// A definition file has the same format as a resource in the dev config file: the resource's slug,
+// name and kind followed by its kind-specific fields, e.g.
+//
+//	slug: db
+//	name: DB
+//	kind: postgres
+//	host: db.example.com
+//	...
+package resourcedef
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/resources"
+	"github.com/airplanedev/cli/pkg/resources/kinds"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// FileSuffix is the suffix of resource definition files.
+const FileSuffix = ".resource.yaml"
+
+//go:embed templates/*.resource.yaml
+var templates embed.FS
+
+// Definition is a parsed resource definition file.
+type Definition struct {
+	Slug string
+	Name string
+	Kind resources.ResourceKind
+	// Config holds the kind-specific fields of the resource, e.g. host for postgres resources.
+	Config map[string]interface{}
+}
+
+// Kinds returns the resource kinds that definitions can be generated for, sorted by name.
+func Kinds() []resources.ResourceKind {
+	entries, err := templates.ReadDir("templates")
+	if err != nil {
+		// The templates are embedded, so this can't happen.
+		panic(err)
+	}
+	kinds := make([]resources.ResourceKind, 0, len(entries))
+	for _, e := range entries {
+		kinds = append(kinds, resources.ResourceKind(strings.TrimSuffix(e.Name(), FileSuffix)))
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	return kinds
+}
+
+// Generate returns a definition file for a new resource of the given kind. Every field of the kind
+// is listed with its documentation, and required fields are left empty for the user to fill in.
+func Generate(kind resources.ResourceKind, slug, name string) ([]byte, error) {
+	if !utils.IsSlug(slug) {
+		return nil, errors.Errorf("invalid slug %q: slugs may only contain lowercase letters, numbers and underscores", slug)
+	}
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	tmpl, err := templates.ReadFile("templates/" + string(kind) + FileSuffix)
+	if err != nil {
+		return nil, errors.Errorf("unsupported resource kind %q, expected one of: %s", kind, joinKinds(Kinds()))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Definition of the %s resource %s. Once its fields are filled in, create it with\n", kind, slug)
+	b.WriteString("# `airplane resources create <path to this file>`.\n")
+	fmt.Fprintf(&b, "slug: %s\n", slug)
+	fmt.Fprintf(&b, "name: %q\n", name)
+	fmt.Fprintf(&b, "kind: %s\n\n", kind)
+	b.Write(tmpl)
+	return []byte(b.String()), nil
+}
+
+// Read parses the definition file at path.
+func Read(path string) (Definition, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return Definition{}, errors.Wrapf(err, "reading %s", path)
+	}
+	d, err := Parse(buf)
+	if err != nil {
+		return Definition{}, errors.Wrapf(err, "parsing %s", path)
+	}
+	return d, nil
+}
+
+// Parse parses a definition file. The resource's config isn't validated, see Resource.
+func Parse(buf []byte) (Definition, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(buf, &raw); err != nil {
+		return Definition{}, errors.Wrap(err, "unmarshaling definition")
+	}
+
+	d := Definition{Config: map[string]interface{}{}}
+	for k, v := range raw {
+		switch k {
+		case "slug", "name", "kind":
+			s, ok := v.(string)
+			if !ok {
+				return Definition{}, errors.Errorf("expected %s to be a string, got %T", k, v)
+			}
+			switch k {
+			case "slug":
+				d.Slug = s
+			case "name":
+				d.Name = s
+			case "kind":
+				d.Kind = resources.ResourceKind(s)
+			}
+		default:
+			d.Config[k] = v
+		}
+	}
+
+	if d.Slug == "" {
+		return Definition{}, errors.New("missing slug")
+	}
+	if !utils.IsSlug(d.Slug) {
+		return Definition{}, errors.Errorf("invalid slug %q: slugs may only contain lowercase letters, numbers and underscores", d.Slug)
+	}
+	if d.Name == "" {
+		d.Name = d.Slug
+	}
+	if d.Kind == "" {
+		return Definition{}, errors.New("missing kind")
+	}
+	return d, nil
+}
+
+// Resource converts the definition into a typed resource, validating its config against the
+// schema of its kind. Fields that the kind doesn't have are rejected, so that typos aren't
+// silently ignored.
+func (d Definition) Resource() (kinds.ExportedResource, error) {
+	if _, ok := resources.ResourceFactories[d.Kind]; !ok {
+		return kinds.ExportedResource{}, errors.Errorf("unsupported resource kind %q, expected one of: %s", d.Kind, joinKinds(Kinds()))
+	}
+
+	serialized := make(map[string]interface{}, len(d.Config)+3)
+	for k, v := range d.Config {
+		serialized[k] = v
+	}
+	serialized["slug"] = d.Slug
+	serialized["name"] = d.Name
+	serialized["kind"] = string(d.Kind)
+
+	r, err := resources.GetResource(d.Kind, serialized)
+	if err != nil {
+		return kinds.ExportedResource{}, errors.Wrapf(err, "decoding %s resource %s", d.Kind, d.Slug)
+	}
+	known := fieldNames(reflect.TypeOf(r))
+	var unknown []string
+	for k := range d.Config {
+		if !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return kinds.ExportedResource{}, errors.Errorf("%s resources have no field(s): %s", d.Kind, strings.Join(unknown, ", "))
+	}
+
+	return kinds.New(r)
+}
+
+// fieldNames returns the JSON names of the fields of a resource struct, including the fields of
+// embedded structs.
+func fieldNames(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for name := range fieldNames(f.Type) {
+				names[name] = true
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func joinKinds(kinds []resources.ResourceKind) string {
+	s := make([]string, len(kinds))
+	for i, k := range kinds {
+		s[i] = string(k)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,118 @@
+package resourcedef
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/resources"
+	"github.com/airplanedev/cli/pkg/resources/kinds"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKinds(t *testing.T) {
+	require := require.New(t)
+
+	// Every registered kind has a template.
+	var registered []resources.ResourceKind
+	for kind := range resources.ResourceFactories {
+		registered = append(registered, kind)
+	}
+	require.ElementsMatch(registered, Kinds())
+}
+
+func TestGenerate(t *testing.T) {
+	for _, kind := range Kinds() {
+		t.Run(string(kind), func(t *testing.T) {
+			require := require.New(t)
+
+			buf, err := Generate(kind, "my_resource", "My: resource")
+			require.NoError(err)
+
+			d, err := Parse(buf)
+			require.NoError(err)
+			require.Equal("my_resource", d.Slug)
+			require.Equal("My: resource", d.Name)
+			require.Equal(kind, d.Kind)
+
+			// Every field of the template is a field of the kind.
+			known := fieldNames(reflectType(t, kind))
+			for k := range d.Config {
+				require.True(known[k], "unknown field %s", k)
+			}
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	require := require.New(t)
+
+	_, err := Generate("s3", "bucket", "Bucket")
+	require.ErrorContains(err, `unsupported resource kind "s3"`)
+
+	_, err = Generate(kinds.ResourceKindPostgres, "My DB", "My DB")
+	require.ErrorContains(err, "invalid slug")
+}
+
+func TestResource(t *testing.T) {
+	require := require.New(t)
+
+	// A freshly generated definition is missing required fields.
+	buf, err := Generate(kinds.ResourceKindPostgres, "db", "DB")
+	require.NoError(err)
+	path := filepath.Join(t.TempDir(), "db"+FileSuffix)
+	require.NoError(os.WriteFile(path, buf, 0644))
+	d, err := Read(path)
+	require.NoError(err)
+	_, err = d.Resource()
+	require.ErrorContains(err, "username")
+
+	d.Config["host"] = "db.example.com"
+	d.Config["database"] = "app"
+	d.Config["username"] = "postgres"
+	d.Config["password"] = "hunter2"
+	e, err := d.Resource()
+	require.NoError(err)
+	require.Equal("db", e.Slug)
+	require.Equal(kinds.ResourceKindPostgres, e.Kind)
+	pg, err := kinds.As[*kinds.PostgresResource](e)
+	require.NoError(err)
+	require.Equal("db.example.com", pg.Host)
+	require.NotEmpty(pg.DSN)
+
+	// Typos are rejected.
+	d.Config["hostname"] = "db.example.com"
+	_, err = d.Resource()
+	require.ErrorContains(err, "postgres resources have no field(s): hostname")
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		def  string
+		err  string
+	}{
+		{name: "missing slug", def: "kind: slack\n", err: "missing slug"},
+		{name: "invalid slug", def: "slug: My Slack\nkind: slack\n", err: "invalid slug"},
+		{name: "missing kind", def: "slug: slack\n", err: "missing kind"},
+		{name: "non-string kind", def: "slug: slack\nkind: 1\n", err: "expected kind to be a string"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.def))
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+
+	d, err := Parse([]byte("slug: slack\nkind: slack\naccessToken: xoxb-1\n"))
+	require.NoError(t, err)
+	// The name defaults to the slug.
+	require.Equal(t, "slack", d.Name)
+	require.Equal(t, map[string]interface{}{"accessToken": "xoxb-1"}, d.Config)
+}
+
+func reflectType(t *testing.T, kind resources.ResourceKind) reflect.Type {
+	r, err := resources.GetResource(kind, map[string]interface{}{})
+	require.NoError(t, err)
+	return reflect.TypeOf(r)
+}
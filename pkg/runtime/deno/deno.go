@@ -0,0 +1,180 @@
+package deno
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/airplanedev/cli/pkg/build/deno"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/definitions/updaters"
+	"github.com/airplanedev/cli/pkg/runtime"
+	"github.com/airplanedev/cli/pkg/utils/airplane_directory"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// Init register the runtime.
+//
+// Deno tasks share the .ts extension with Node tasks, so the runtime is registered under a
+// pseudo-extension and looked up by task kind instead.
+func init() {
+	runtime.Register(".deno", Runtime{})
+}
+
+// Code template.
+var code = template.Must(template.New("deno").Parse(`{{with .Comment -}}
+{{.}}
+
+{{end -}}
+type Params = {
+  id: string;
+};
+
+// Put the main logic of the task in the main function.
+export default async function (params: Params) {
+  console.log("parameters:", params);
+
+  // You can return data to show outputs to users.
+  // Outputs documentation: https://docs.airplane.dev/tasks/outputs
+  return [
+    { element: "hello", count: 1 },
+    { element: "world", count: 2 },
+  ];
+}
+`))
+
+// Data represents the data template.
+type data struct {
+	Comment string
+}
+
+// Runtime implementation.
+type Runtime struct{}
+
+// PrepareRun implementation.
+func (r Runtime) PrepareRun(ctx context.Context, logger logger.Logger, opts runtime.PrepareRunOptions) (rexprs []string, rcloser io.Closer, rerr error) {
+	root, err := r.Root(opts.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, taskDir, closer, err := airplane_directory.CreateTaskDir(root, opts.TaskSlug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		// If we encountered an error before returning, then we're responsible
+		// for performing our own cleanup.
+		if rerr != nil {
+			closer.Close()
+		}
+	}()
+
+	shim, err := deno.TemplatedUniversalDenoShim(deno.SDKSpecifier(root, []string{opts.Path}))
+	if err != nil {
+		return nil, nil, err
+	}
+	shimPath := filepath.Join(taskDir, "shim.ts")
+	if err := os.WriteFile(shimPath, []byte(shim), 0644); err != nil {
+		return nil, nil, errors.Wrap(err, "writing shim file")
+	}
+
+	pv, err := json.Marshal(opts.ParamValues)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "serializing param values")
+	}
+
+	entrypointFunc, _ := opts.KindOptions["entrypointFunc"].(string)
+
+	return []string{"deno", "run", "--allow-all", shimPath, opts.Path, entrypointFunc, string(pv)}, closer, nil
+}
+
+// Generate implementation.
+func (r Runtime) Generate(t *runtime.Task) ([]byte, os.FileMode, error) {
+	d := data{}
+	if t != nil {
+		d.Comment = runtime.Comment(r, t.URL)
+	}
+
+	var buf bytes.Buffer
+	if err := code.Execute(&buf, d); err != nil {
+		return nil, 0, errors.Wrap(err, "deno: template execute")
+	}
+
+	return buf.Bytes(), 0644, nil
+}
+
+// GenerateInline implementation.
+func (r Runtime) GenerateInline(def *definitions.Definition) ([]byte, fs.FileMode, error) {
+	return nil, 0, errors.New("cannot generate inline deno task configuration")
+}
+
+// Workdir implementation.
+func (r Runtime) Workdir(path string) (string, error) {
+	return r.Root(path)
+}
+
+// Root implementation.
+//
+// The root is the nearest directory with a deno.json, if any.
+func (r Runtime) Root(path string) (string, error) {
+	for _, filePath := range deno.ConfigFiles {
+		if root, ok := fsx.Find(path, filePath); ok {
+			return root, nil
+		}
+	}
+	return runtime.RootForNonBuiltRuntime(path)
+}
+
+func (r Runtime) Version(rootPath string) (buildVersion buildtypes.BuildTypeVersion, err error) {
+	return "", nil
+}
+
+// Kind implementation.
+func (r Runtime) Kind() buildtypes.TaskKind {
+	return buildtypes.TaskKindDeno
+}
+
+// FormatComment implementation.
+func (r Runtime) FormatComment(s string) string {
+	var lines []string
+
+	for _, line := range strings.Split(s, "\n") {
+		lines = append(lines, "// "+line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SupportsLocalExecution implementation.
+func (r Runtime) SupportsLocalExecution() bool {
+	return true
+}
+
+// Update implementation.
+//
+// Only tasks with definition files can be updated: inline tasks are declared in .airplane.ts files,
+// which the YAML updater can't edit.
+func (r Runtime) Update(ctx context.Context, logger logger.Logger, path string, slug string, def definitions.Definition) error {
+	if !definitions.IsTaskDef(path) {
+		return errors.Errorf("updating inline Deno tasks is not supported: update %s by hand", filepath.Base(path))
+	}
+	return updaters.UpdateYAMLTask(ctx, logger, path, slug, def)
+}
+
+func (r Runtime) CanUpdate(ctx context.Context, logger logger.Logger, path string, slug string) (bool, error) {
+	if !definitions.IsTaskDef(path) {
+		return false, nil
+	}
+	return updaters.CanUpdateYAMLTask(path)
+}
@@ -0,0 +1,194 @@
+package golang
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/airplanedev/cli/pkg/build/golang"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/definitions/updaters"
+	"github.com/airplanedev/cli/pkg/runtime"
+	"github.com/airplanedev/cli/pkg/utils/airplane_directory"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// EntrypointSuffix is the suffix of the files that contain the main function of a Go task.
+// Other .go files are helpers of a task's package and are never linked to a task.
+const EntrypointSuffix = "_airplane.go"
+
+// Init register the runtime.
+func init() {
+	runtime.Register(".go", Runtime{})
+}
+
+// IsEntrypoint returns true if the file at path may be the entrypoint of a Go task.
+func IsEntrypoint(path string) bool {
+	return strings.HasSuffix(filepath.Base(path), EntrypointSuffix)
+}
+
+// Code template.
+var code = template.Must(template.New("go").Parse(`{{with .Comment -}}
+{{.}}
+
+{{end -}}
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type Params struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+// Params are passed to the task as a JSON-encoded argument.
+func main() {
+	var params Params
+	if err := json.Unmarshal([]byte(os.Args[1]), &params); err != nil {
+		fmt.Fprintln(os.Stderr, "parsing parameters:", err)
+		os.Exit(1)
+	}
+	fmt.Println("parameters:", params)
+
+	// Show output to users. Documentation: https://docs.airplane.dev/tasks/output#log-output-protocol
+	fmt.Println(` + "`airplane_output_set [{\"element\": \"hello\", \"count\": 1}, {\"element\": \"world\", \"count\": 2}]`" + `)
+}
+`))
+
+// Data represents the data template.
+type data struct {
+	Comment string
+}
+
+// Runtime implementation.
+type Runtime struct{}
+
+// PrepareRun implementation.
+//
+// The package of the task is compiled into the task directory, since `go run` requires being run
+// from within the task's module.
+func (r Runtime) PrepareRun(ctx context.Context, logger logger.Logger, opts runtime.PrepareRunOptions) (rexprs []string, rcloser io.Closer, rerr error) {
+	root, err := r.Root(opts.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, taskDir, closer, err := airplane_directory.CreateTaskDir(root, opts.TaskSlug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		// If we encountered an error before returning, then we're responsible
+		// for performing our own cleanup.
+		if rerr != nil {
+			closer.Close()
+		}
+	}()
+
+	binPath := filepath.Join(taskDir, "task")
+	args := []string{"build"}
+	if tags := golang.GetBuildTags(opts.KindOptions); len(tags) > 0 {
+		args = append(args, "-tags", strings.Join(tags, ","))
+	}
+	args = append(args, "-o", binPath, ".")
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = filepath.Dir(opts.Path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, nil, errors.Wrapf(err, "building task:\n%s", out)
+	}
+
+	pv, err := json.Marshal(opts.ParamValues)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "serializing param values")
+	}
+
+	// Inline tasks share their package's binary, which runs the function named by the first
+	// argument.
+	if entrypointFunc, _ := opts.KindOptions["entrypointFunc"].(string); entrypointFunc != "" {
+		return []string{binPath, entrypointFunc, string(pv)}, closer, nil
+	}
+	return []string{binPath, string(pv)}, closer, nil
+}
+
+// Generate implementation.
+func (r Runtime) Generate(t *runtime.Task) ([]byte, os.FileMode, error) {
+	d := data{}
+	if t != nil {
+		d.Comment = runtime.Comment(r, t.URL)
+	}
+
+	var buf bytes.Buffer
+	if err := code.Execute(&buf, d); err != nil {
+		return nil, 0, errors.Wrap(err, "go: template execute")
+	}
+
+	return buf.Bytes(), 0644, nil
+}
+
+// GenerateInline implementation.
+func (r Runtime) GenerateInline(def *definitions.Definition) ([]byte, fs.FileMode, error) {
+	return nil, 0, errors.New("cannot generate inline go task configuration")
+}
+
+// Workdir implementation.
+func (r Runtime) Workdir(path string) (string, error) {
+	return r.Root(path)
+}
+
+// Root implementation.
+//
+// The root is the nearest directory with a go.mod.
+func (r Runtime) Root(path string) (string, error) {
+	root, ok := fsx.Find(path, "go.mod")
+	if !ok {
+		return "", errors.Errorf("unable to find go.mod for %s", path)
+	}
+	return root, nil
+}
+
+func (r Runtime) Version(rootPath string) (buildVersion buildtypes.BuildTypeVersion, err error) {
+	return "", nil
+}
+
+// Kind implementation.
+func (r Runtime) Kind() buildtypes.TaskKind {
+	return buildtypes.TaskKindGo
+}
+
+// FormatComment implementation.
+func (r Runtime) FormatComment(s string) string {
+	var lines []string
+
+	for _, line := range strings.Split(s, "\n") {
+		lines = append(lines, "// "+line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SupportsLocalExecution implementation.
+func (r Runtime) SupportsLocalExecution() bool {
+	return true
+}
+
+func (r Runtime) Update(ctx context.Context, logger logger.Logger, path string, slug string, def definitions.Definition) error {
+	return updaters.UpdateYAMLTask(ctx, logger, path, slug, def)
+}
+
+func (r Runtime) CanUpdate(ctx context.Context, logger logger.Logger, path string, slug string) (bool, error) {
+	return updaters.CanUpdateYAMLTask(path)
+}
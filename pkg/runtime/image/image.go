@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
 
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/definitions"
@@ -18,6 +19,7 @@ import (
 func init() {
 	// will fallback to the task kind
 	runtime.Register(".image", Runtime{})
+	runtime.Register(".dockerfile", DockerfileRuntime{})
 }
 
 // Runtime implementation.
@@ -75,3 +77,26 @@ func (r Runtime) Update(ctx context.Context, logger logger.Logger, path string,
 func (r Runtime) CanUpdate(ctx context.Context, logger logger.Logger, path string, slug string) (bool, error) {
 	return updaters.CanUpdateYAMLTask(path)
 }
+
+// DockerfileRuntime is the runtime of tasks that are built from a Dockerfile, which is their
+// entrypoint. They're declared like image tasks, so they share the rest of the implementation.
+type DockerfileRuntime struct {
+	Runtime
+}
+
+// Root implementation.
+//
+// Tasks are built in the directory of their Dockerfile, unless they set a build context.
+func (r DockerfileRuntime) Root(path string) (string, error) {
+	return filepath.Dir(path), nil
+}
+
+// Workdir implementation.
+func (r DockerfileRuntime) Workdir(path string) (string, error) {
+	return r.Root(path)
+}
+
+// Kind implementation.
+func (r DockerfileRuntime) Kind() buildtypes.TaskKind {
+	return buildtypes.TaskKindDockerfile
+}
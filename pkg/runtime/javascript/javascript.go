@@ -19,7 +19,6 @@ import (
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/build/node"
 	buildtypes "github.com/airplanedev/cli/pkg/build/types"
-	buildversions "github.com/airplanedev/cli/pkg/build/versions"
 	"github.com/airplanedev/cli/pkg/definitions"
 	"github.com/airplanedev/cli/pkg/definitions/updaters"
 	"github.com/airplanedev/cli/pkg/deploy/config"
@@ -29,7 +28,6 @@ import (
 	"github.com/airplanedev/cli/pkg/utils/fsx"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/pkg/errors"
-	"golang.org/x/exp/slices"
 )
 
 // Init register the runtime.
@@ -183,6 +181,9 @@ export default airplane.task(
         {{- if $value.Description}}
         description: "{{escape $value.Description}}",
         {{- end}}
+        {{- if $value.Paused}}
+        paused: true,
+        {{- end}}
         {{- if $value.ParamValues}}
         paramValues: {
           {{range $pSlug, $pValue := $value.ParamValues}}
@@ -197,6 +198,24 @@ export default airplane.task(
     {{- if and (ne .DefaultRunPermissions "task-viewers") (.DefaultRunPermissions) }}
     defaultRunPermissions: "{{.DefaultRunPermissions}}",
     {{- end}}
+    {{- with .Permissions}}
+    {{- if .RequireExplicitPermissions}}
+    permissions: {
+    {{- range .Roles}}
+      {{.Role}}: {
+        {{- if .Groups}}
+        groups: [{{range $i, $g := .Groups}}{{if $i}}, {{end}}"{{escape $g}}"{{end}}],
+        {{- end}}
+        {{- if .Users}}
+        users: [{{range $i, $u := .Users}}{{if $i}}, {{end}}"{{escape $u}}"{{end}}],
+        {{- end}}
+      },
+    {{- end}}
+    },
+    {{- else}}
+    permissions: "team_access",
+    {{- end}}
+    {{- end}}
     {{- if .Node.EnvVars }}
     envVars: {
     {{- range $key, $value := .Node.EnvVars}}
@@ -336,44 +355,16 @@ func (r Runtime) Version(rootPath string) (buildVersion buildtypes.BuildTypeVers
 		return "", err
 	}
 
-	if pkg.Engines != nil && pkg.Engines.NodeVersion != "" {
-		// Look for version in package.json
-		nodeConstraint, err := semver.NewConstraint(pkg.Engines.NodeVersion)
-		if err != nil {
-			return "", errors.Wrapf(err, "parsing node engine %s", pkg.Engines.NodeVersion)
-		}
-
-		v, err := buildversions.GetVersions()
-		if err != nil {
-			return "", err
-		}
-		supportedVersionsMap := v[string(buildtypes.NameNode)]
-		var supportedVersions []string
-		for supportedVersion := range supportedVersionsMap {
-			supportedVersions = append(supportedVersions, supportedVersion)
-		}
-		slices.SortFunc(supportedVersions, func(a, b string) bool {
-			return b < a
-		})
-
-		for _, supportedVersion := range supportedVersions {
-			sv, err := semver.NewVersion(supportedVersion)
-			if err != nil {
-				return "", err
-			}
-			if nodeConstraint.Check(sv) {
-				return buildtypes.BuildTypeVersion(supportedVersion), nil
-			}
-		}
+	// Look for version in package.json
+	if v, err := node.NodeVersionFromEngines(pkg); err != nil {
+		return "", err
+	} else if v != buildtypes.BuildTypeVersionUnspecified {
+		return v, nil
 	}
 
 	// Look for version in airplane.config
-	hasAirplaneConfig := config.HasAirplaneConfig(rootPath)
-	if hasAirplaneConfig {
-		c, err := config.NewAirplaneConfigFromFile(rootPath)
-		if err == nil && c.Javascript.NodeVersion != "" {
-			return buildtypes.BuildTypeVersion(c.Javascript.NodeVersion), nil
-		}
+	if c, _, err := config.LoadAirplaneConfig(rootPath); err == nil && c.Javascript.NodeVersion != "" {
+		return buildtypes.BuildTypeVersion(c.Javascript.NodeVersion), nil
 	}
 
 	return "", nil
@@ -539,6 +530,15 @@ func (r Runtime) PrepareRun(
 		}
 	}()
 
+	esbuildConfig, err := node.ReadEsbuildConfig(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	esbuildOptions, err := json.Marshal(esbuildConfig)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "marshaling esbuild options")
+	}
+
 	entrypointBuildStart := time.Now()
 	cmd := exec.CommandContext(ctx,
 		"node",
@@ -549,6 +549,8 @@ func (r Runtime) PrepareRun(
 		"",
 		filepath.Join(runDir, "dist"),
 		root,
+		"",
+		string(esbuildOptions),
 	)
 	cmd.Dir = airplaneDir
 	logger.Debug("Running %s (in %s)", strings.Join(cmd.Args, " "), airplaneDir)
@@ -179,6 +179,20 @@ func TestGenerateInline(t *testing.T) {
 			},
 			expected: "simple.ts",
 		},
+		{
+			desc: "explicit permissions",
+			def: definitions.Definition{
+				Slug: "my_task",
+				Name: "My Task",
+				Permissions: &definitions.PermissionsDefinition{
+					Viewers:                    definitions.PermissionRecipients{Groups: []string{"support"}},
+					Executers:                  definitions.PermissionRecipients{Groups: []string{"eng"}, Users: []string{"ops@example.com"}},
+					RequireExplicitPermissions: true,
+				},
+				Node: &definitions.NodeDefinition{},
+			},
+			expected: "permissions.ts",
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
@@ -80,7 +80,7 @@ func (r Runtime) PrepareRun(ctx context.Context, logger logger.Logger, opts runt
 		return nil, nil, err
 	}
 
-	_, taskDir, closer, err := airplane_directory.CreateTaskDir(root, opts.TaskSlug)
+	airplaneDir, taskDir, closer, err := airplane_directory.CreateTaskDir(root, opts.TaskSlug)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -97,6 +97,13 @@ func (r Runtime) PrepareRun(ctx context.Context, logger logger.Logger, opts runt
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "entrypoint is not within the task root")
 	}
+	if opts.Sandboxed {
+		bin, err = prepareVirtualenv(ctx, logger, bin, root, airplaneDir)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	entrypointFunc, _ := opts.KindOptions["entrypointFunc"].(string)
 	shim, err := python.PythonShim(python.PythonShimParams{
 		TaskRoot:       root,
@@ -286,6 +293,9 @@ import airplane
             {{- if $value.Description}}
             description={{quote $value.Description}},
             {{- end}}
+            {{- if $value.Paused}}
+            paused=True,
+            {{- end}}
             {{- if $value.ParamValues}}
             param_values={
                 {{- range $pSlug, $pValue := $value.ParamValues}}
@@ -300,6 +310,24 @@ import airplane
 	{{- if and (ne .DefaultRunPermissions "task-viewers") (.DefaultRunPermissions) }}
     default_run_permissions="{{.DefaultRunPermissions}}",
     {{- end}}
+    {{- with .Permissions}}
+    {{- if .RequireExplicitPermissions}}
+    permissions=airplane.ExplicitPermissions(
+    {{- range .Roles}}
+        {{.Role}}=airplane.PermissionAssignees(
+            {{- if .Groups}}
+            groups=[{{range $i, $g := .Groups}}{{if $i}}, {{end}}{{quote $g}}{{end}}],
+            {{- end}}
+            {{- if .Users}}
+            users=[{{range $i, $u := .Users}}{{if $i}}, {{end}}{{quote $u}}{{end}}],
+            {{- end}}
+        ),
+    {{- end}}
+    ),
+    {{- else}}
+    permissions="team_access",
+    {{- end}}
+    {{- end}}
     {{- if .Python.EnvVars }}
     env_vars=[
     {{- range $key, $value := .Python.EnvVars}}
@@ -433,12 +461,8 @@ func (r Runtime) Root(path string) (string, error) {
 
 func (r Runtime) Version(rootPath string) (buildVersion buildtypes.BuildTypeVersion, err error) {
 	// Look for version in airplane.config
-	hasAirplaneConfig := config.HasAirplaneConfig(rootPath)
-	if hasAirplaneConfig {
-		c, err := config.NewAirplaneConfigFromFile(rootPath)
-		if err == nil && c.Python.Version != "" {
-			return buildtypes.BuildTypeVersion(c.Python.Version), nil
-		}
+	if c, _, err := config.LoadAirplaneConfig(rootPath); err == nil && c.Python.Version != "" {
+		return buildtypes.BuildTypeVersion(c.Python.Version), nil
 	}
 
 	return "", nil
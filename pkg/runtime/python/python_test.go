@@ -402,6 +402,26 @@ func TestGenerateInline(t *testing.T) {
         }`,
 			expectedFixture: "every_type.py",
 		},
+		{
+			desc: "permissions",
+			defJSON: `{
+        "name": "Inline python permissions",
+        "slug": "inline_python_permissions",
+        "python": {
+          "entrypoint": "test_airplane.py"
+        },
+        "permissions": {
+          "viewers": {
+            "groups": ["support"]
+          },
+          "executers": {
+            "groups": ["eng"],
+            "users": ["ops@example.com"]
+          }
+        }
+      }`,
+			expectedFixture: "permissions.py",
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, func(t *testing.T) {
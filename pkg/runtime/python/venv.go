@@ -0,0 +1,58 @@
+package python
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// prepareVirtualenv creates a virtualenv inside of the .airplane directory, if one doesn't already
+// exist, and installs the task root's requirements.txt into it. It returns the path to the
+// virtualenv's Python binary.
+//
+// The virtualenv is kept around between runs so that dependencies are only installed once.
+func prepareVirtualenv(ctx context.Context, l logger.Logger, bin, root, airplaneDir string) (string, error) {
+	venvDir := filepath.Join(airplaneDir, "venv")
+	venvBin := virtualenvBinary(venvDir)
+
+	if !fsx.Exists(venvBin) {
+		l.Debug("Creating virtualenv at %s", venvDir)
+		if err := runCommand(ctx, l, bin, "-m", "venv", venvDir); err != nil {
+			return "", errors.Wrap(err, "creating virtualenv")
+		}
+	}
+
+	requirementsPath := filepath.Join(root, "requirements.txt")
+	if fsx.Exists(requirementsPath) {
+		l.Debug("Installing %s into virtualenv", requirementsPath)
+		if err := runCommand(ctx, l, venvBin, "-m", "pip", "install", "--quiet", "--disable-pip-version-check", "-r", requirementsPath); err != nil {
+			return "", errors.Wrap(err, "installing requirements into virtualenv")
+		}
+	}
+
+	return venvBin, nil
+}
+
+// virtualenvBinary returns the path to the Python binary of the virtualenv at venvDir.
+func virtualenvBinary(venvDir string) string {
+	if goruntime.GOOS == "windows" {
+		return filepath.Join(venvDir, "Scripts", "python.exe")
+	}
+	return filepath.Join(venvDir, "bin", "python")
+}
+
+func runCommand(ctx context.Context, l logger.Logger, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	l.Debug("Running %s", strings.Join(cmd.Args, " "))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "running %s: %s", strings.Join(cmd.Args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
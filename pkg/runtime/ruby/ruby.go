@@ -0,0 +1,173 @@
+package ruby
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/airplanedev/cli/pkg/build/ruby"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/definitions/updaters"
+	"github.com/airplanedev/cli/pkg/runtime"
+	"github.com/airplanedev/cli/pkg/utils/airplane_directory"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// Init register the runtime.
+func init() {
+	runtime.Register(".rb", Runtime{})
+}
+
+// Code template.
+var code = template.Must(template.New("rb").Parse(`{{with .Comment -}}
+{{.}}
+
+{{end -}}
+# This is your task's entrypoint. When your task is executed, this
+# method will be called.
+def main(params)
+  data = [
+    { "id" => 1, "name" => "Gabriel Davis", "role" => "Dentist" },
+    { "id" => 2, "name" => "Carolyn Garcia", "role" => "Sales" },
+    { "id" => 3, "name" => "Frances Hernandez", "role" => "Astronaut" },
+    { "id" => 4, "name" => "Melissa Rodriguez", "role" => "Engineer" },
+    { "id" => 5, "name" => "Jacob Hall", "role" => "Engineer" },
+    { "id" => 6, "name" => "Andrea Lopez", "role" => "Astronaut" },
+  ]
+
+  # Sort the data in ascending order by name.
+  data = data.sort_by { |u| u["name"] }
+
+  # You can return data to show output to users.
+  # Output documentation: https://docs.airplane.dev/tasks/output
+  data
+end
+`))
+
+// Data represents the data template.
+type data struct {
+	Comment string
+}
+
+// Runtime implementation.
+type Runtime struct{}
+
+// PrepareRun implementation.
+func (r Runtime) PrepareRun(ctx context.Context, logger logger.Logger, opts runtime.PrepareRunOptions) (rexprs []string, rcloser io.Closer, rerr error) {
+	// Confirm a Ruby binary is installed before preparing the run.
+	bin, err := exec.LookPath("ruby")
+	if err != nil {
+		return nil, nil, errors.New("could not find ruby: install Ruby to run Ruby tasks locally")
+	}
+
+	root, err := r.Root(opts.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, taskDir, closer, err := airplane_directory.CreateTaskDir(root, opts.TaskSlug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		// If we encountered an error before returning, then we're responsible
+		// for performing our own cleanup.
+		if rerr != nil {
+			closer.Close()
+		}
+	}()
+
+	shim, err := ruby.RubyShim(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(filepath.Join(taskDir, "shim.rb"), []byte(shim), 0644); err != nil {
+		return nil, nil, errors.Wrap(err, "writing shim file")
+	}
+
+	pv, err := json.Marshal(opts.ParamValues)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "serializing param values")
+	}
+
+	return []string{bin, filepath.Join(taskDir, "shim.rb"), opts.Path, string(pv)}, closer, nil
+}
+
+// Generate implementation.
+func (r Runtime) Generate(t *runtime.Task) ([]byte, fs.FileMode, error) {
+	d := data{}
+	if t != nil {
+		d.Comment = runtime.Comment(r, t.URL)
+	}
+
+	var buf bytes.Buffer
+	if err := code.Execute(&buf, d); err != nil {
+		return nil, 0, errors.Wrap(err, "ruby: template execute")
+	}
+
+	return buf.Bytes(), 0644, nil
+}
+
+// GenerateInline implementation.
+func (r Runtime) GenerateInline(def *definitions.Definition) ([]byte, fs.FileMode, error) {
+	return nil, 0, errors.New("cannot generate inline ruby task configuration")
+}
+
+// Workdir implementation.
+func (r Runtime) Workdir(path string) (string, error) {
+	return r.Root(path)
+}
+
+// Root implementation.
+//
+// The root is the nearest directory with a Gemfile, if any.
+func (r Runtime) Root(path string) (string, error) {
+	if root, ok := fsx.Find(path, "Gemfile"); ok {
+		return root, nil
+	}
+	return runtime.RootForNonBuiltRuntime(path)
+}
+
+func (r Runtime) Version(rootPath string) (buildVersion buildtypes.BuildTypeVersion, err error) {
+	return "", nil
+}
+
+// Kind implementation.
+func (r Runtime) Kind() buildtypes.TaskKind {
+	return buildtypes.TaskKindRuby
+}
+
+// FormatComment implementation.
+func (r Runtime) FormatComment(s string) string {
+	var lines []string
+
+	for _, line := range strings.Split(s, "\n") {
+		lines = append(lines, "# "+line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SupportsLocalExecution implementation.
+func (r Runtime) SupportsLocalExecution() bool {
+	return true
+}
+
+func (r Runtime) Update(ctx context.Context, logger logger.Logger, path string, slug string, def definitions.Definition) error {
+	return updaters.UpdateYAMLTask(ctx, logger, path, slug, def)
+}
+
+func (r Runtime) CanUpdate(ctx context.Context, logger logger.Logger, path string, slug string) (bool, error) {
+	return updaters.CanUpdateYAMLTask(path)
+}
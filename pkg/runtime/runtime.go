@@ -143,6 +143,11 @@ type PrepareRunOptions struct {
 
 	// Optional builtin client for runtimes that need it (SQL, Rest, builtin).
 	BuiltinsClient *builtins.LocalBuiltinClient
+
+	// Sandboxed is set when the run should not depend on packages installed in the user's local
+	// environment. Runtimes that install dependencies should install them into a generated
+	// environment (e.g. a virtualenv) rather than relying on globally installed packages.
+	Sandboxed bool
 }
 
 // Runtimes is a collection of registered runtimes.
@@ -162,11 +167,12 @@ func Register(ext string, r Interface) {
 // If an extension match is found, use that runtime. Otherwise rely on the task kind.
 func Lookup(path string, kind buildtypes.TaskKind) (Interface, error) {
 	ext := filepath.Ext(path)
-	if runtime, ok := runtimes[ext]; ok {
+	if runtime, ok := runtimes[ext]; ok && (kind == "" || runtime.Kind() == kind) {
 		return runtime, nil
 	}
 
-	// There was no exact match on the extension. Fallback to checking if there
+	// There was no exact match on the extension, or the extension is shared by
+	// several task kinds (f.e. .ts for Node and Deno). Fallback to checking if there
 	// is exactly one match on the task kind, which can occur for task kinds that
 	// support arbitrary extensions (f.e. shell).
 	possible := []Interface{}
@@ -19,7 +19,6 @@ import (
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/airplanedev/cli/pkg/utils/airplane_directory"
 	"github.com/airplanedev/cli/pkg/utils/fsx"
-	"github.com/airplanedev/cli/pkg/utils/handlebars"
 	"github.com/airplanedev/cli/pkg/utils/logger"
 	"github.com/pkg/errors"
 )
@@ -92,20 +91,15 @@ func (r Runtime) PrepareRun(ctx context.Context, logger logger.Logger, opts runt
 		return nil, nil, errors.Wrap(err, "entrypoint is not within the task root")
 	}
 
-	cmd := []string{
-		"bash", filepath.Join(taskDir, "shim.sh"),
-		filepath.Join(root, entrypoint),
-	}
-	// TODO: this is a rough approximation of how interpolateParameters works in prod
-	for slug := range opts.ParamValues {
-		tmpl := fmt.Sprintf("%s={{%s}}", slug, slug)
-		val, err := handlebars.Render(tmpl, opts.ParamValues)
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "rendering shell command")
-		}
-		cmd = append(cmd, val)
+	paramsMode, _ := opts.KindOptions["paramsMode"].(string)
+	cmd := []string{"bash", filepath.Join(taskDir, "shim.sh")}
+	cmd = append(cmd, shell.ShimArgs(shell.ParamsMode(paramsMode))...)
+	cmd = append(cmd, filepath.Join(root, entrypoint))
+	paramArgs, err := shell.ParamArgs(opts.ParamValues)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "rendering shell command")
 	}
-	return cmd, closer, nil
+	return append(cmd, paramArgs...), closer, nil
 }
 
 // Generate implementation.
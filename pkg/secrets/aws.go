@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/pkg/errors"
+)
+
+// AWSSecretsManagerProvider resolves `aws-sm:<secret ID or ARN>[#<key>]` references with AWS
+// Secrets Manager, e.g. `aws-sm:prod/db#password`. If a key is set, the secret must be a JSON
+// object.
+//
+// Credentials and the region are loaded like the AWS CLI, except that the region of an ARN takes
+// precedence.
+type AWSSecretsManagerProvider struct {
+	// GetSecretValue overrides the call to Secrets Manager, for testing.
+	GetSecretValue func(ctx context.Context, region, secretID string) (string, error)
+}
+
+var _ Provider = &AWSSecretsManagerProvider{}
+
+func (p *AWSSecretsManagerProvider) Scheme() string {
+	return "aws-sm"
+}
+
+func (p *AWSSecretsManagerProvider) Resolve(ctx context.Context, ref Reference) (string, error) {
+	get := p.GetSecretValue
+	if get == nil {
+		get = getSecretValue
+	}
+	value, err := get(ctx, arnRegion(ref.Path), ref.Path)
+	if err != nil {
+		return "", err
+	}
+	if ref.Key == "" {
+		return value, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", errors.Errorf("secret is not a JSON object, so key %s can't be selected", ref.Key)
+	}
+	return selectKey(ref, fields)
+}
+
+// arnRegion returns the region of a secret's ARN, e.g.
+// arn:aws:secretsmanager:us-west-2:123456789012:secret:prod/db, or an empty string if secretID
+// isn't an ARN.
+func arnRegion(secretID string) string {
+	parts := strings.Split(secretID, ":")
+	if len(parts) < 6 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[3]
+}
+
+func getSecretValue(ctx context.Context, region, secretID string) (string, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return "", errors.Wrap(err, "loading AWS config")
+	}
+	out, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "getting secret value")
+	}
+	if out.SecretString == nil {
+		return "", errors.New("secret has no string value")
+	}
+	return *out.SecretString, nil
+}
@@ -0,0 +1,166 @@
+// Package secrets resolves references to secrets that are stored outside of Airplane, e.g. in
+// Vault or AWS Secrets Manager, so that env vars can use them without copying them into configs.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// Reference is a reference to an external secret, formatted as `<scheme>:<path>[#<key>]`, e.g.
+// `vault:secret/data/foo#password`.
+type Reference struct {
+	// Scheme selects the provider that resolves the reference.
+	Scheme string
+	// Path identifies the secret within the provider.
+	Path string
+	// Key selects a field of a secret with several fields, if set.
+	Key string
+}
+
+func (r Reference) String() string {
+	s := r.Scheme + ":" + r.Path
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+// ParseReference parses a reference to an external secret.
+func ParseReference(ref string) (Reference, error) {
+	scheme, rest, ok := strings.Cut(ref, ":")
+	if !ok || scheme == "" || rest == "" {
+		return Reference{}, errors.Errorf("invalid secret reference %q: expected <provider>:<path>[#<key>]", ref)
+	}
+	path, key, _ := strings.Cut(rest, "#")
+	if path == "" {
+		return Reference{}, errors.Errorf("invalid secret reference %q: missing path", ref)
+	}
+	return Reference{Scheme: scheme, Path: path, Key: key}, nil
+}
+
+// Provider resolves references to the secrets of an external secret store.
+type Provider interface {
+	// Scheme is the prefix of the references that the provider resolves, e.g. "vault".
+	Scheme() string
+	// Resolve returns the value of the referenced secret.
+	Resolve(ctx context.Context, ref Reference) (string, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{}
+)
+
+// Register makes a provider available to Resolve, replacing any provider with the same scheme.
+func Register(p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[p.Scheme()] = p
+}
+
+// Schemes returns the schemes of the registered providers, sorted.
+func Schemes() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	schemes := make([]string, 0, len(providers))
+	for s := range providers {
+		schemes = append(schemes, s)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+func init() {
+	Register(&VaultProvider{})
+	Register(&AWSSecretsManagerProvider{})
+}
+
+// Resolve returns the value of the secret that ref references.
+func Resolve(ctx context.Context, ref string) (string, error) {
+	r, err := ParseReference(ref)
+	if err != nil {
+		return "", err
+	}
+	providersMu.RLock()
+	p, ok := providers[r.Scheme]
+	providersMu.RUnlock()
+	if !ok {
+		return "", errors.Errorf("unknown secret provider %q in %q, expected one of: %s", r.Scheme, ref, strings.Join(Schemes(), ", "))
+	}
+	value, err := p.Resolve(ctx, r)
+	return value, errors.Wrapf(err, "resolving secret %s", ref)
+}
+
+// ResolveEnvVars returns envVars with the values of their external secrets. Env vars that don't
+// reference an external secret are returned unchanged. Secrets referenced by several env vars are
+// only resolved once.
+func ResolveEnvVars(ctx context.Context, envVars libapi.EnvVars) (libapi.EnvVars, error) {
+	if !HasExternal(envVars) {
+		return envVars, nil
+	}
+	resolved := make(libapi.EnvVars, len(envVars))
+	cache := map[string]string{}
+	for k, v := range envVars {
+		if v.External == nil {
+			resolved[k] = v
+			continue
+		}
+		value, ok := cache[*v.External]
+		if !ok {
+			var err error
+			if value, err = Resolve(ctx, *v.External); err != nil {
+				return nil, errors.Wrapf(err, "env var %s", k)
+			}
+			cache[*v.External] = value
+		}
+		v := value
+		resolved[k] = libapi.EnvVarValue{Value: &v}
+	}
+	return resolved, nil
+}
+
+// HasExternal returns true if any of envVars references an external secret.
+func HasExternal(envVars libapi.EnvVars) bool {
+	for _, v := range envVars {
+		if v.External != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// selectKey returns the value of the secret with the given fields. If key is empty, the secret
+// must have a single field.
+func selectKey(ref Reference, fields map[string]interface{}) (string, error) {
+	if ref.Key == "" {
+		if len(fields) != 1 {
+			return "", errors.Errorf("secret has %d fields, select one with #<key>", len(fields))
+		}
+		for k := range fields {
+			ref.Key = k
+		}
+	}
+	v, ok := fields[ref.Key]
+	if !ok {
+		return "", errors.Errorf("secret has no key %s", ref.Key)
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	default:
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return "", errors.Wrap(err, "marshaling secret value")
+		}
+		return string(buf), nil
+	}
+}
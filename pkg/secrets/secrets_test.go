@@ -0,0 +1,157 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	secrets map[string]string
+	calls   int
+}
+
+func (p *fakeProvider) Scheme() string {
+	return "fake"
+}
+
+func (p *fakeProvider) Resolve(ctx context.Context, ref Reference) (string, error) {
+	p.calls++
+	v, ok := p.secrets[ref.Path]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found", ref.Path)
+	}
+	return v, nil
+}
+
+func TestParseReference(t *testing.T) {
+	for _, test := range []struct {
+		ref      string
+		expected Reference
+		err      bool
+	}{
+		{ref: "vault:secret/data/foo#key", expected: Reference{Scheme: "vault", Path: "secret/data/foo", Key: "key"}},
+		{ref: "vault:secret/foo", expected: Reference{Scheme: "vault", Path: "secret/foo"}},
+		{
+			ref:      "aws-sm:arn:aws:secretsmanager:us-west-2:123456789012:secret:prod/db#password",
+			expected: Reference{Scheme: "aws-sm", Path: "arn:aws:secretsmanager:us-west-2:123456789012:secret:prod/db", Key: "password"},
+		},
+		{ref: "secret/foo", err: true},
+		{ref: "vault:", err: true},
+		{ref: "vault:#key", err: true},
+	} {
+		t.Run(test.ref, func(t *testing.T) {
+			require := require.New(t)
+			ref, err := ParseReference(test.ref)
+			if test.err {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(test.expected, ref)
+			require.Equal(test.ref, ref.String())
+		})
+	}
+}
+
+func TestResolveEnvVars(t *testing.T) {
+	require := require.New(t)
+	p := &fakeProvider{secrets: map[string]string{"db": "hunter2"}}
+	Register(p)
+
+	resolved, err := ResolveEnvVars(context.Background(), libapi.EnvVars{
+		"PLAIN":    {Value: pointers.String("value")},
+		"CONFIG":   {Config: pointers.String("db_password")},
+		"SECRET":   {External: pointers.String("fake:db")},
+		"SECRET_2": {External: pointers.String("fake:db")},
+	})
+	require.NoError(err)
+	require.Equal(libapi.EnvVars{
+		"PLAIN":    {Value: pointers.String("value")},
+		"CONFIG":   {Config: pointers.String("db_password")},
+		"SECRET":   {Value: pointers.String("hunter2")},
+		"SECRET_2": {Value: pointers.String("hunter2")},
+	}, resolved)
+	require.Equal(1, p.calls)
+
+	_, err = ResolveEnvVars(context.Background(), libapi.EnvVars{"SECRET": {External: pointers.String("fake:missing")}})
+	require.EqualError(err, "env var SECRET: resolving secret fake:missing: secret missing not found")
+
+	_, err = ResolveEnvVars(context.Background(), libapi.EnvVars{"SECRET": {External: pointers.String("unknown:db")}})
+	require.ErrorContains(err, `unknown secret provider "unknown"`)
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/db":
+			fmt.Fprint(w, `{"data":{"data":{"username":"admin","password":"hunter2"},"metadata":{"version":1}}}`)
+		case "/v1/kv/token":
+			fmt.Fprint(w, `{"data":{"token":"abc"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "token")
+
+	ctx := context.Background()
+	p := &VaultProvider{}
+	for _, test := range []struct {
+		ref      Reference
+		expected string
+		err      string
+	}{
+		{ref: Reference{Path: "secret/data/db", Key: "password"}, expected: "hunter2"},
+		{ref: Reference{Path: "kv/token"}, expected: "abc"},
+		{ref: Reference{Path: "secret/data/db"}, err: "secret has 2 fields, select one with #<key>"},
+		{ref: Reference{Path: "secret/data/db", Key: "missing"}, err: "secret has no key missing"},
+		{ref: Reference{Path: "kv/missing"}, err: "vault returned 404 Not Found: "},
+	} {
+		t.Run(test.ref.String(), func(t *testing.T) {
+			require := require.New(t)
+			value, err := p.Resolve(ctx, test.ref)
+			if test.err != "" {
+				require.EqualError(err, test.err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(test.expected, value)
+		})
+	}
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	require := require.New(t)
+	var region string
+	p := &AWSSecretsManagerProvider{
+		GetSecretValue: func(ctx context.Context, r, secretID string) (string, error) {
+			region = r
+			return `{"password":"hunter2"}`, nil
+		},
+	}
+
+	value, err := p.Resolve(context.Background(), Reference{
+		Path: "arn:aws:secretsmanager:us-west-2:123456789012:secret:prod/db",
+		Key:  "password",
+	})
+	require.NoError(err)
+	require.Equal("hunter2", value)
+	require.Equal("us-west-2", region)
+
+	value, err = p.Resolve(context.Background(), Reference{Path: "prod/db"})
+	require.NoError(err)
+	require.Equal(`{"password":"hunter2"}`, value)
+	require.Equal("", region)
+}
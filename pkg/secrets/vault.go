@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// VaultProvider resolves `vault:<path>[#<key>]` references with the HashiCorp Vault HTTP API,
+// e.g. `vault:secret/data/db#password`. Both KV v1 and v2 secrets are supported.
+//
+// It's configured like the Vault CLI: VAULT_ADDR is the address of the server, VAULT_TOKEN or the
+// ~/.vault-token file is the token, and VAULT_NAMESPACE is the namespace, if any.
+type VaultProvider struct {
+	// Client is the HTTP client to use. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+var _ Provider = &VaultProvider{}
+
+func (p *VaultProvider) Scheme() string {
+	return "vault"
+}
+
+func (p *VaultProvider) Resolve(ctx context.Context, ref Reference) (string, error) {
+	addr := strings.TrimRight(os.Getenv("VAULT_ADDR"), "/")
+	if addr == "" {
+		return "", errors.New("VAULT_ADDR is not set")
+	}
+	token, err := vaultToken()
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s", addr, strings.TrimLeft(ref.Path, "/")), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "creating request")
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "reading secret from vault")
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "reading vault response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("vault returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", errors.Wrap(err, "decoding vault response")
+	}
+	fields := secret.Data
+	// KV v2 nests the secret's fields under data.data, next to its metadata.
+	if nested, ok := fields["data"].(map[string]interface{}); ok {
+		if _, ok := fields["metadata"]; ok {
+			fields = nested
+		}
+	}
+	return selectKey(ref, fields)
+}
+
+func vaultToken() (string, error) {
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	home, err := os.UserHomeDir()
+	if err == nil {
+		if buf, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+			return strings.TrimSpace(string(buf)), nil
+		}
+	}
+	return "", errors.New("VAULT_TOKEN is not set and ~/.vault-token does not exist")
+}
@@ -58,7 +58,9 @@ func AttachDevRoutes(r *mux.Router, s *state.State) {
 	r.Handle("/startView/{view_slug}", handlers.New(s, StartViewHandler)).Methods("POST", "OPTIONS")
 
 	r.Handle("/logs/{run_id}", handlers.SSE(s, LogsHandler)).Methods("GET", "OPTIONS")
+	r.Handle("/runs/history", handlers.New(s, ListRunHistoryHandler)).Methods("GET", "OPTIONS")
 	r.Handle("/tasks/errors", handlers.New(s, GetTaskErrorsHandler)).Methods("GET", "OPTIONS")
+	r.Handle("/parameters/options", handlers.New(s, ListParameterOptionsHandler)).Methods("GET", "OPTIONS")
 
 	r.Handle("/tasks/create", handlers.WithBody(s, InitTaskHandler)).Methods("POST", "OPTIONS")
 	r.Handle("/tasks/isSlugAvailable", handlers.New(s, IsTaskSlugAvailableHandler)).Methods("GET", "OPTIONS")
@@ -0,0 +1,71 @@
+package apidev
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
+	libparams "github.com/airplanedev/cli/pkg/parameters"
+	"github.com/airplanedev/cli/pkg/server/state"
+	serverutils "github.com/airplanedev/cli/pkg/server/utils"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/pkg/errors"
+)
+
+type ListParameterOptionsResponse struct {
+	Options []libapi.ConstraintOption `json:"options"`
+}
+
+// ListParameterOptionsHandler returns the options of a parameter whose options are populated by a
+// source task or builtin, so that the run form can load them when the parameter is shown. Results
+// are cached per environment until the form asks for them to be refreshed with `refresh=true`.
+func ListParameterOptionsHandler(ctx context.Context, s *state.State, r *http.Request) (ListParameterOptionsResponse, error) {
+	query := r.URL.Query()
+	taskSlug := query.Get("taskSlug")
+	paramSlug := query.Get("paramSlug")
+	if taskSlug == "" || paramSlug == "" {
+		return ListParameterOptionsResponse{}, libhttp.NewErrBadRequest("taskSlug and paramSlug are required")
+	}
+
+	taskConfig, ok := s.TaskConfigs.Get(taskSlug)
+	if !ok {
+		return ListParameterOptionsResponse{}, libhttp.NewErrNotFound("task with slug %q not found", taskSlug)
+	}
+	params, err := taskConfig.Def.GetParameters()
+	if err != nil {
+		return ListParameterOptionsResponse{}, err
+	}
+	var source *libapi.OptionsSource
+	for _, param := range params {
+		if param.Slug == paramSlug {
+			source = param.Constraints.OptionsSource
+			break
+		}
+	}
+	if source == nil {
+		return ListParameterOptionsResponse{}, libhttp.NewErrNotFound("parameter %q of task %q does not have an options source", paramSlug, taskSlug)
+	}
+
+	envSlug := pointers.ToString(serverutils.GetEffectiveEnvSlugFromRequest(s, r))
+	key, err := json.Marshal(struct {
+		EnvSlug string                `json:"envSlug"`
+		Source  *libapi.OptionsSource `json:"source"`
+	}{envSlug, source})
+	if err != nil {
+		return ListParameterOptionsResponse{}, errors.Wrap(err, "marshaling options source")
+	}
+	if options, ok := s.OptionsCache.Get(string(key)); ok && query.Get("refresh") != "true" {
+		return ListParameterOptionsResponse{Options: options}, nil
+	}
+
+	// Sources are run through the dev server itself, so that they can refer to local tasks.
+	loader := &libparams.TaskOptionsLoader{Client: s.LocalClient, EnvSlug: envSlug}
+	options, err := loader.LoadOptions(ctx, *source)
+	if err != nil {
+		return ListParameterOptionsResponse{}, err
+	}
+	s.OptionsCache.Add(string(key), options)
+	return ListParameterOptionsResponse{Options: options}, nil
+}
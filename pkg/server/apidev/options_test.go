@@ -0,0 +1,82 @@
+package apidev_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/server"
+	"github.com/airplanedev/cli/pkg/server/apidev"
+	"github.com/airplanedev/cli/pkg/server/state"
+	"github.com/airplanedev/cli/pkg/server/test_utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListParameterOptions(t *testing.T) {
+	require := require.New(t)
+
+	source := &libapi.OptionsSource{Task: "list_customers", ValuePath: "id", LabelPath: "name"}
+	key, err := json.Marshal(struct {
+		EnvSlug string                `json:"envSlug"`
+		Source  *libapi.OptionsSource `json:"source"`
+	}{"", source})
+	require.NoError(err)
+	options := []libapi.ConstraintOption{{Label: "Acme", Value: "c1"}}
+
+	h := test_utils.GetHttpExpect(
+		context.Background(),
+		t,
+		server.NewRouter(&state.State{
+			TaskConfigs: state.NewStore(map[string]discover.TaskConfig{
+				"my_task": {
+					Def: definitions.Definition{
+						Name: "My task",
+						Slug: "my_task",
+						Parameters: []definitions.ParameterDefinition{
+							{
+								Slug: "customer",
+								Type: "shorttext",
+								OptionsSource: &definitions.OptionsSourceDefinition{
+									Task:      source.Task,
+									ValuePath: source.ValuePath,
+									LabelPath: source.LabelPath,
+								},
+							},
+							{Slug: "note", Type: "shorttext"},
+						},
+						Shell: &definitions.ShellDefinition{Entrypoint: "my_task.sh"},
+					},
+				},
+			}),
+			OptionsCache: state.NewStore(map[string][]libapi.ConstraintOption{
+				string(key): options,
+			}),
+		}, server.Options{}),
+	)
+
+	// Options that were already loaded are served from the cache.
+	body := h.GET("/dev/parameters/options").
+		WithQuery("taskSlug", "my_task").
+		WithQuery("paramSlug", "customer").
+		Expect().
+		Status(http.StatusOK).Body()
+	var resp apidev.ListParameterOptionsResponse
+	require.NoError(json.Unmarshal([]byte(body.Raw()), &resp))
+	require.Equal(options, resp.Options)
+
+	h.GET("/dev/parameters/options").
+		WithQuery("taskSlug", "my_task").
+		WithQuery("paramSlug", "note").
+		Expect().
+		Status(http.StatusNotFound)
+
+	h.GET("/dev/parameters/options").
+		WithQuery("taskSlug", "missing").
+		WithQuery("paramSlug", "customer").
+		Expect().
+		Status(http.StatusNotFound)
+}
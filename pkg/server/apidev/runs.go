@@ -0,0 +1,68 @@
+package apidev
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/dev/history"
+	"github.com/airplanedev/cli/pkg/server/state"
+)
+
+// defaultRunHistoryLimit is the number of runs per page of the run history, if a limit isn't given.
+const defaultRunHistoryLimit = 25
+
+type ListRunHistoryResponse struct {
+	Runs []dev.LocalRun `json:"runs"`
+	// Total is the number of runs across all pages.
+	Total int `json:"total"`
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
+
+// ListRunHistoryHandler returns a page of the runs that were executed in the server's directory,
+// including runs of previous dev servers, most recent first. Runs can be filtered by `taskSlug`, and
+// are paged with the zero-indexed `page` and `limit`.
+func ListRunHistoryHandler(ctx context.Context, s *state.State, r *http.Request) (ListRunHistoryResponse, error) {
+	query := r.URL.Query()
+	page, err := queryInt(r, "page", 0)
+	if err != nil {
+		return ListRunHistoryResponse{}, err
+	}
+	limit, err := queryInt(r, "limit", defaultRunHistoryLimit)
+	if err != nil {
+		return ListRunHistoryResponse{}, err
+	}
+	if page < 0 || limit <= 0 {
+		return ListRunHistoryResponse{}, libhttp.NewErrBadRequest("page must not be negative and limit must be positive")
+	}
+
+	runs, total, err := s.ListRunHistory(ctx, history.ListOpts{
+		TaskSlug: query.Get("taskSlug"),
+		Page:     page,
+		Limit:    limit,
+	})
+	if err != nil {
+		return ListRunHistoryResponse{}, err
+	}
+	return ListRunHistoryResponse{
+		Runs:  runs,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+func queryInt(r *http.Request, key string, defaultValue int) (int, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, libhttp.NewErrBadRequest("invalid %s %q", key, v)
+	}
+	return i, nil
+}
@@ -82,7 +82,11 @@ func getRunIDFromToken(r *http.Request) (string, error) {
 
 // GetRunHandler handles requests to the /v0/runs/get endpoint
 func GetRunHandler(ctx context.Context, state *state.State, r *http.Request) (dev.LocalRun, error) {
-	runID := r.URL.Query().Get("id")
+	return GetRun(ctx, state, r.URL.Query().Get("id"))
+}
+
+// GetRun returns a run, fetching runs that executed in the fallback environment from the API.
+func GetRun(ctx context.Context, state *state.State, runID string) (dev.LocalRun, error) {
 	run, err := state.GetRun(ctx, runID)
 	if err != nil {
 		return dev.LocalRun{}, err
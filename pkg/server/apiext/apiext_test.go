@@ -6,7 +6,9 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -234,6 +236,57 @@ func TestExecuteDescendantFallback(t *testing.T) {
 	require.Equal(run.EnvSlug, "test")
 }
 
+func TestExecuteMock(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "list_users.yaml"), []byte("- name: Alice\n- name: Bob\n"), 0644))
+
+	store := state.NewRunStore()
+	h := test_utils.GetHttpExpect(
+		context.Background(),
+		t,
+		server.NewRouter(&state.State{
+			RemoteClient: &api.MockClient{},
+			Executor:     new(dev.MockExecutor),
+			Runs:         store,
+			TaskConfigs:  state.NewStore(map[string]discover.TaskConfig{}),
+			DevConfig: &devconf.DevConfig{
+				Path: filepath.Join(dir, devconf.DefaultDevConfigFileName),
+				Mocks: devconf.TaskMocks{
+					Tasks:    map[string]string{"list_users": "list_users.yaml"},
+					Fallback: devconf.MockFallbackNone,
+				},
+			},
+			InitialRemoteEnvSlug: pointers.String("test"),
+		}, server.Options{}),
+	)
+
+	body := h.POST("/v0/tasks/execute").
+		WithJSON(apiext.ExecuteTaskRequest{
+			Slug:        "list_users",
+			ParamValues: api.Values{"limit": 2},
+		}).
+		Expect().
+		Status(http.StatusOK).Body()
+
+	var resp api.RunTaskResponse
+	require.NoError(json.Unmarshal([]byte(body.Raw()), &resp))
+	run, found := store.Get(resp.RunID)
+	require.True(found)
+	require.True(run.Mocked)
+	require.False(run.Remote)
+	require.Equal(api.RunSucceeded, run.Status)
+	outputs, err := json.Marshal(run.Outputs)
+	require.NoError(err)
+	require.JSONEq(`[{"name":"Alice"},{"name":"Bob"}]`, string(outputs))
+
+	// Tasks that aren't mocked don't pass through to the fallback environment.
+	h.POST("/v0/tasks/execute").
+		WithJSON(apiext.ExecuteTaskRequest{Slug: "delete_user"}).
+		Expect().
+		Status(http.StatusNotFound)
+}
+
 func TestExecuteBuiltin(t *testing.T) {
 	require := require.New(t)
 	mockExecutor := new(dev.MockExecutor)
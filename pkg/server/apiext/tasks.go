@@ -15,6 +15,7 @@ import (
 	"github.com/airplanedev/cli/pkg/builtins"
 	"github.com/airplanedev/cli/pkg/configs"
 	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/devconf"
 	"github.com/airplanedev/cli/pkg/parameters"
 	resources "github.com/airplanedev/cli/pkg/resources/cliresources"
 	"github.com/airplanedev/cli/pkg/server/state"
@@ -33,28 +34,36 @@ type ExecuteTaskRequest struct {
 	Slug        string            `json:"slug"`
 	ParamValues api.Values        `json:"paramValues"`
 	Resources   map[string]string `json:"resources"`
+	// Priority overrides the task's priority for this run.
+	Priority *int `json:"priority"`
 }
 
 // ExecuteTaskHandler handles requests to the /v0/tasks/execute endpoint
 func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request, req ExecuteTaskRequest) (api.RunTaskResponse, error) {
-	run := *dev.NewLocalRun()
 	parentID, err := getRunIDFromToken(r)
 	if err != nil {
 		return api.RunTaskResponse{}, err
 	}
+	return ExecuteTask(ctx, state, parentID, serverutils.GetEffectiveEnvSlugFromRequest(state, r), req)
+}
+
+// ExecuteTask starts a run of a task. Child runs of parentID, if set, fall back to the
+// environment of their parent rather than envSlug.
+func ExecuteTask(ctx context.Context, state *state.State, parentID string, envSlug *string, req ExecuteTaskRequest) (api.RunTaskResponse, error) {
+	run := *dev.NewLocalRun()
 	run.ParentID = parentID
 
 	runID := dev.GenerateRunID()
 	run.ID = runID
 	run.RunID = runID
 
-	var envSlug *string
 	if parentID != "" {
 		// Pull env slug from the parent run.
 		parentRun, err := state.GetRunInternal(ctx, parentID)
 		if err != nil {
 			return api.RunTaskResponse{}, err
 		}
+		envSlug = nil
 		if parentRun.FallbackEnvSlug != "" {
 			envSlug = &parentRun.FallbackEnvSlug
 		}
@@ -69,8 +78,10 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 				return api.RunTaskResponse{}, libhttp.NewErrBadRequest("Parent run has exceeded the maximum limit of %d child runs", maxWorkflowChildRuns)
 			}
 		}
-	} else {
-		envSlug = serverutils.GetEffectiveEnvSlugFromRequest(state, r)
+	}
+
+	if fixture, ok := state.DevConfig.GetTaskMock(req.Slug); ok {
+		return executeMock(state, run, envSlug, req, fixture)
 	}
 
 	localTaskConfig, ok := state.TaskConfigs.Get(req.Slug)
@@ -85,11 +96,14 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 		hasErrors = len(taskErrors.Errors) > 0
 	}
 	if hasErrors || (!isBuiltin && !ok) {
-		if envSlug == nil {
+		if envSlug == nil || !state.DevConfig.PassthroughUnmocked() {
 			message := fmt.Sprintf("task with slug %q is not registered locally", req.Slug)
 			if hasErrors {
 				message = fmt.Sprintf("task with slug %q cannot be executed locally", req.Slug)
 			}
+			if envSlug != nil {
+				message += " or mocked in the dev config file"
+			}
 			return api.RunTaskResponse{}, libhttp.NewErrNotFound(message)
 		}
 
@@ -97,6 +111,7 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 			TaskSlug:    &req.Slug,
 			ParamValues: req.ParamValues,
 			EnvSlug:     *envSlug,
+			Priority:    req.Priority,
 		})
 		if err != nil {
 			var taskMissingError *libapi.TaskMissingError
@@ -135,8 +150,14 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 		WorkingDir:      state.Dir,
 		StudioURL:       state.StudioURL,
 		EnvVars:         state.DevConfig.EnvVars,
+
+		KeepFailedArtifacts: state.RetentionPolicy.KeepFailed,
 	}
 	params := libapi.Parameters{}
+	priority := localTaskConfig.Def.Priority
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
 	resourceAttachments := map[string]string{}
 	mergedResources, err := resources.MergeRemoteResources(ctx, state.RemoteClient, state.DevConfig, envSlug)
 	if err != nil {
@@ -190,6 +211,11 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 		}
 		runConfig.Kind = kind
 		runConfig.KindOptions = kindOptions
+		if state.SandboxedRuns && dev.SupportsSandboxedExecution(kind) {
+			runConfig.Sandboxed = true
+			run.Sandboxed = true
+			run.FidelityNotes = dev.SandboxFidelityNotes(kind)
+		}
 		runConfig.Name = localTaskConfig.Def.GetName()
 		runConfig.File = localTaskConfig.TaskEntrypoint
 		resourceAttachments, err = localTaskConfig.Def.GetResourceAttachments()
@@ -209,12 +235,16 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 		run.TaskID = req.Slug
 		run.TaskSlug = req.Slug
 		run.TaskName = localTaskConfig.Def.GetName()
+		run.RunAs = localTaskConfig.Def.RunAs
 		runConfig.ConfigVars, err = configs.MergeRemoteConfigs(ctx, state, envSlug)
 		if err != nil {
 			return api.RunTaskResponse{}, errors.Wrap(err, "merging local and remote configs")
 		}
 		run.TaskRevision = localTaskConfig
 		paramValuesWithDefaults := parameters.ApplyDefaults(params, req.ParamValues)
+		if verr, ok := parameters.AsValidationError(parameters.Validate(params, paramValuesWithDefaults)); ok {
+			return api.RunTaskResponse{}, verr.StatusError()
+		}
 		run.ParamValues = paramValuesWithDefaults
 		runConfig.ParamValues, err = parameters.StandardizeParamValues(ctx, state.RemoteClient, params, paramValuesWithDefaults)
 		if err != nil {
@@ -238,7 +268,8 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 	run.Parameters = &params
 	run.FallbackEnvSlug = pointers.ToString(envSlug)
 
-	run.Status = api.RunActive
+	// Runs start out as queued and become active once the run queue starts them.
+	run.Status = api.RunQueued
 	runCtx, fn := context.WithCancel(context.Background()) // Context used for cancelling a run.
 	run.CancelFn = fn
 	// if the user is authenticated in CLI, use their ID
@@ -248,7 +279,22 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 	state.AddRun(req.Slug, runID, run)
 
 	// Use a new context while executing so the handler context doesn't cancel task execution
-	go func() {
+	state.RunQueue.Enqueue(runID, parentID, priority, func() {
+		runState, err := state.UpdateRun(runID, func(run *dev.LocalRun) error {
+			if run.Status == api.RunQueued {
+				run.Status = api.RunActive
+			}
+			return nil
+		})
+		if err != nil {
+			logger.Error("updating run with status: %+v", err)
+			return
+		}
+		if runState.Status == api.RunCancelled {
+			// The run was cancelled while it was queued.
+			return
+		}
+
 		outputs, err := state.Executor.Execute(runCtx, runConfig)
 		completedAt := time.Now()
 
@@ -302,11 +348,53 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 		}); err != nil {
 			logger.Error("updating run with status: %+v", err)
 		}
-	}()
+
+		state.ApplyRetention()
+	})
 
 	return api.RunTaskResponse{RunID: runID}, nil
 }
 
+// executeMock completes run with the outputs in the fixture file of a mocked task, without
+// executing the task.
+func executeMock(state *state.State, run dev.LocalRun, envSlug *string, req ExecuteTaskRequest, fixture string) (api.RunTaskResponse, error) {
+	outputs, err := devconf.ReadFixture(fixture)
+	if err != nil {
+		return api.RunTaskResponse{}, libhttp.NewErrBadRequest("unable to read mocked outputs of task %q: %s", req.Slug, err.Error())
+	}
+
+	run.TaskID = req.Slug
+	run.TaskSlug = req.Slug
+	run.TaskName = req.Slug
+	if localTaskConfig, ok := state.TaskConfigs.Get(req.Slug); ok {
+		run.TaskName = localTaskConfig.Def.GetName()
+	}
+	run.ParamValues = req.ParamValues
+	run.FallbackEnvSlug = pointers.ToString(envSlug)
+	if state.AuthInfo.User != nil {
+		run.CreatorID = state.AuthInfo.User.ID
+	}
+
+	now := time.Now().UTC()
+	run.CreatedAt = now
+	run.SucceededAt = &now
+	run.Status = api.RunSucceeded
+	run.Outputs = outputs
+	run.Mocked = true
+	run.FidelityNotes = []string{fmt.Sprintf("The task wasn't executed. Its outputs were read from %s.", fixture)}
+	run.LogBroker.Record(api.LogItem{
+		Timestamp: now,
+		InsertID:  dev.LogIDGen.Next(),
+		Text:      fmt.Sprintf("Mocked run of %s: returning the outputs in %s", req.Slug, fixture),
+		Level:     api.LogLevelInfo,
+		TaskSlug:  req.Slug,
+	})
+	run.LogBroker.Close()
+
+	state.AddRun(req.Slug, run.ID, run)
+	return api.RunTaskResponse{RunID: run.ID}, nil
+}
+
 // GetTaskMetadataHandler handles requests to the /v0/tasks/getMetadata endpoint. It generates a deterministic task ID
 // for each task found locally, and its primary purpose is to ensure that the task discoverer does not error.
 // If a task is not local, it tries the fallback environment, so that local views
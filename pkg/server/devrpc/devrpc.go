@@ -0,0 +1,398 @@
+// Package devrpc serves DevService, a gRPC API of the local dev server for editors and agents. It
+// exposes the same state as the HTTP endpoints that the previewer uses, and streams run logs and
+// outcomes so that clients don't have to poll for them.
+package devrpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/server/apiext"
+	"github.com/airplanedev/cli/pkg/server/devrpc/devrpcpb"
+	"github.com/airplanedev/cli/pkg/server/state"
+	serverutils "github.com/airplanedev/cli/pkg/server/utils"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TokenMetadataKey is the metadata key that clients pass the dev server's token in, like the
+// X-Airplane-Dev-Token header of HTTP requests.
+const TokenMetadataKey = "x-airplane-dev-token"
+
+// runPollInterval is how often the status of a run is checked after its logs have closed, since
+// runs are marked as finished shortly after their last log.
+const runPollInterval = 100 * time.Millisecond
+
+// Server implements DevService against the state of a dev server.
+type Server struct {
+	devrpcpb.UnimplementedDevServiceServer
+	state *state.State
+}
+
+// New returns a server for the given state.
+func New(s *state.State) *Server {
+	return &Server{state: s}
+}
+
+// NewGRPCServer returns a gRPC server that serves DevService. If token is set, every call must
+// pass it in the TokenMetadataKey metadata.
+func NewGRPCServer(s *state.State, token *string) *grpc.Server {
+	var opts []grpc.ServerOption
+	if token != nil {
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if err := verifyToken(ctx, *token); err != nil {
+					return nil, err
+				}
+				return handler(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				if err := verifyToken(ss.Context(), *token); err != nil {
+					return err
+				}
+				return handler(srv, ss)
+			}),
+		)
+	}
+	srv := grpc.NewServer(opts...)
+	devrpcpb.RegisterDevServiceServer(srv, New(s))
+	return srv
+}
+
+func verifyToken(ctx context.Context, token string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, t := range md.Get(TokenMetadataKey) {
+		if t == token {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid dev token")
+}
+
+// ListEntities lists the tasks and views that the dev server discovered.
+func (s *Server) ListEntities(ctx context.Context, req *devrpcpb.ListEntitiesRequest) (*devrpcpb.ListEntitiesResponse, error) {
+	var entities []*devrpcpb.Entity
+	for slug, taskConfig := range s.state.TaskConfigs.Items() {
+		absoluteEntrypoint := taskConfig.TaskEntrypoint
+		if absoluteEntrypoint == "" {
+			// YAML-only tasks, e.g. REST tasks, don't have entrypoints.
+			absoluteEntrypoint = taskConfig.Def.GetDefnFilePath()
+		}
+		ep, err := filepath.Rel(s.state.Dir, absoluteEntrypoint)
+		if err != nil {
+			return nil, toStatus(errors.Wrap(err, "getting relative path to task"))
+		}
+		entities = append(entities, &devrpcpb.Entity{
+			Slug:       slug,
+			Name:       taskConfig.Def.GetName(),
+			Kind:       devrpcpb.EntityKind_ENTITY_KIND_TASK,
+			Runtime:    string(taskConfig.Def.GetRuntime()),
+			Entrypoint: ep,
+		})
+	}
+	for slug, viewConfig := range s.state.ViewConfigs.Items() {
+		ep, err := filepath.Rel(s.state.Dir, viewConfig.Def.Entrypoint)
+		if err != nil {
+			return nil, toStatus(errors.Wrap(err, "getting relative path to view"))
+		}
+		entities = append(entities, &devrpcpb.Entity{
+			Slug:       slug,
+			Name:       viewConfig.Def.Name,
+			Kind:       devrpcpb.EntityKind_ENTITY_KIND_VIEW,
+			Entrypoint: ep,
+		})
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		if entities[i].Entrypoint != entities[j].Entrypoint {
+			return entities[i].Entrypoint < entities[j].Entrypoint
+		}
+		return entities[i].Slug < entities[j].Slug
+	})
+	return &devrpcpb.ListEntitiesResponse{Entities: entities}, nil
+}
+
+// ExecuteTask starts a run of a task.
+func (s *Server) ExecuteTask(ctx context.Context, req *devrpcpb.ExecuteTaskRequest) (*devrpcpb.ExecuteTaskResponse, error) {
+	if req.Slug == "" {
+		return nil, status.Error(codes.InvalidArgument, "slug is required")
+	}
+	resp, err := apiext.ExecuteTask(ctx, s.state, "", serverutils.EffectiveEnvSlug(s.state, req.EnvSlug), apiext.ExecuteTaskRequest{
+		Slug:        req.Slug,
+		ParamValues: req.ParamValues.AsMap(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &devrpcpb.ExecuteTaskResponse{RunId: resp.RunID}, nil
+}
+
+// GetRun returns a run.
+func (s *Server) GetRun(ctx context.Context, req *devrpcpb.GetRunRequest) (*devrpcpb.Run, error) {
+	run, err := apiext.GetRun(ctx, s.state, req.RunId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	pbRun, err := toRun(run)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return pbRun, nil
+}
+
+// StreamLogs streams the logs of a run until the run finishes.
+func (s *Server) StreamLogs(req *devrpcpb.StreamLogsRequest, stream devrpcpb.DevService_StreamLogsServer) error {
+	ctx := stream.Context()
+	run, err := s.state.GetRun(ctx, req.RunId)
+	if err != nil {
+		return toStatus(err)
+	}
+	// Runs in the fallback environment don't stream their logs to the dev server, and their log
+	// broker is never closed.
+	if run.Remote {
+		return status.Errorf(codes.FailedPrecondition, "run %s executed in the fallback environment, its logs are only available there", req.RunId)
+	}
+
+	watcher := run.LogBroker.NewWatcher()
+	defer watcher.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case log, open := <-watcher.Logs():
+			if !open {
+				// All logs have been received.
+				return nil
+			}
+			if err := stream.Send(toLogItem(log)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Watch streams the logs and outcomes of the runs that the client asks to watch. Once the client
+// closes its side of the stream, Watch returns after every watched run has finished.
+func (s *Server) Watch(stream devrpcpb.DevService_WatchServer) error {
+	ctx := stream.Context()
+	w := &watch{
+		server: s,
+		stream: stream,
+		runs:   map[string]*watchedRun{},
+	}
+	defer w.wg.Wait()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			// The call was cancelled, which stops every watched run.
+			return err
+		}
+		for _, runID := range req.Remove {
+			w.remove(runID)
+		}
+		for _, runID := range req.Add {
+			w.add(ctx, runID)
+		}
+	}
+}
+
+// watch is the state of a single Watch call.
+type watch struct {
+	server *Server
+	stream devrpcpb.DevService_WatchServer
+	wg     sync.WaitGroup
+
+	// sendMu guards stream.Send, which isn't safe to call concurrently.
+	sendMu sync.Mutex
+	// mu guards runs, which holds the runs being watched by ID.
+	mu   sync.Mutex
+	runs map[string]*watchedRun
+}
+
+type watchedRun struct {
+	// cancel stops watching the run.
+	cancel context.CancelFunc
+}
+
+func (w *watch) add(ctx context.Context, runID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.runs[runID]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	wr := &watchedRun{cancel: cancel}
+	w.runs[runID] = wr
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer w.done(runID, wr)
+		if err := w.watchRun(ctx, runID); err != nil && ctx.Err() == nil {
+			_ = w.send(&devrpcpb.WatchEvent{RunId: runID, Error: err.Error()})
+		}
+	}()
+}
+
+func (w *watch) remove(runID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if wr, ok := w.runs[runID]; ok {
+		wr.cancel()
+		delete(w.runs, runID)
+	}
+}
+
+// done forgets a run once it's no longer watched, unless it's been removed and added again since.
+func (w *watch) done(runID string, wr *watchedRun) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wr.cancel()
+	if w.runs[runID] == wr {
+		delete(w.runs, runID)
+	}
+}
+
+func (w *watch) send(event *devrpcpb.WatchEvent) error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+	return w.stream.Send(event)
+}
+
+// watchRun sends the logs of a run as they're written, and then the run once it finishes.
+func (w *watch) watchRun(ctx context.Context, runID string) error {
+	run, err := w.server.state.GetRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	// Runs in the fallback environment don't stream their logs to the dev server.
+	if !run.Remote {
+		watcher := run.LogBroker.NewWatcher()
+		defer watcher.Close()
+	logs:
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case log, open := <-watcher.Logs():
+				if !open {
+					break logs
+				}
+				if err := w.send(&devrpcpb.WatchEvent{RunId: runID, Log: toLogItem(log)}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	ticker := time.NewTicker(runPollInterval)
+	defer ticker.Stop()
+	for {
+		run, err := apiext.GetRun(ctx, w.server.state, runID)
+		if err != nil {
+			return err
+		}
+		if run.Status.IsTerminal() {
+			pbRun, err := toRun(run)
+			if err != nil {
+				return err
+			}
+			return w.send(&devrpcpb.WatchEvent{RunId: runID, Run: pbRun})
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func toLogItem(log api.LogItem) *devrpcpb.LogItem {
+	return &devrpcpb.LogItem{
+		Timestamp: timestamppb.New(log.Timestamp),
+		Text:      log.Text,
+		Level:     string(log.Level),
+		TaskSlug:  log.TaskSlug,
+	}
+}
+
+func toRun(run dev.LocalRun) (*devrpcpb.Run, error) {
+	// Param values and outputs are round-tripped through JSON, since they may hold values that
+	// structpb doesn't support directly, e.g. times or ordered objects.
+	paramValues := &structpb.Struct{}
+	if err := toProtoJSON(run.ParamValues, paramValues); err != nil {
+		return nil, errors.Wrap(err, "converting param values")
+	}
+	var outputs *structpb.Value
+	if run.Outputs.V != nil {
+		outputs = &structpb.Value{}
+		if err := toProtoJSON(run.Outputs, outputs); err != nil {
+			return nil, errors.Wrap(err, "converting outputs")
+		}
+	}
+	return &devrpcpb.Run{
+		RunId:       run.RunID,
+		TaskSlug:    run.TaskSlug,
+		Status:      string(run.Status),
+		ParamValues: paramValues,
+		Outputs:     outputs,
+		CreatedAt:   timestamppb.New(run.CreatedAt),
+		ParentId:    run.ParentID,
+		Remote:      run.Remote,
+	}, nil
+}
+
+func toProtoJSON(v interface{}, m proto.Message) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(buf, m)
+}
+
+// toStatus converts errors of the HTTP handlers, which carry HTTP status codes, into gRPC errors.
+func toStatus(err error) error {
+	var statusErr libhttp.ErrStatusCode
+	if !errors.As(err, &statusErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	code := codes.Unknown
+	switch statusErr.StatusCode {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		code = codes.Unimplemented
+	case http.StatusInternalServerError:
+		code = codes.Internal
+	}
+	return status.Error(code, statusErr.Msg)
+}
@@ -0,0 +1,175 @@
+package devrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/dev/logs"
+	"github.com/airplanedev/cli/pkg/server/devrpc/devrpcpb"
+	"github.com/airplanedev/cli/pkg/server/state"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newClient serves DevService for s in memory and returns a client of it.
+func newClient(t *testing.T, s *state.State, token *string) devrpcpb.DevServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := NewGRPCServer(s, token)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return devrpcpb.NewDevServiceClient(conn)
+}
+
+func TestListEntities(t *testing.T) {
+	require := require.New(t)
+
+	taskDefinition := definitions.Definition{
+		Name:    "My task",
+		Slug:    "my_task",
+		Runtime: buildtypes.TaskRuntimeWorkflow,
+		Node:    &definitions.NodeDefinition{Entrypoint: "my_task.ts", NodeVersion: "18"},
+	}
+	client := newClient(t, &state.State{
+		Dir: "/project",
+		TaskConfigs: state.NewStore(map[string]discover.TaskConfig{
+			"my_task": {TaskEntrypoint: "/project/tasks/my_task.ts", Def: taskDefinition},
+		}),
+		ViewConfigs: state.NewStore(map[string]discover.ViewConfig{
+			"my_view": {Def: definitions.ViewDefinition{Name: "My view", Slug: "my_view", Entrypoint: "/project/my_view.tsx"}},
+		}),
+	}, nil)
+
+	resp, err := client.ListEntities(context.Background(), &devrpcpb.ListEntitiesRequest{})
+	require.NoError(err)
+	require.Len(resp.Entities, 2)
+	require.Equal("my_view", resp.Entities[0].Slug)
+	require.Equal(devrpcpb.EntityKind_ENTITY_KIND_VIEW, resp.Entities[0].Kind)
+	require.Equal("my_view.tsx", resp.Entities[0].Entrypoint)
+	require.Equal("my_task", resp.Entities[1].Slug)
+	require.Equal("My task", resp.Entities[1].Name)
+	require.Equal(devrpcpb.EntityKind_ENTITY_KIND_TASK, resp.Entities[1].Kind)
+	require.Equal("workflow", resp.Entities[1].Runtime)
+	require.Equal("tasks/my_task.ts", resp.Entities[1].Entrypoint)
+}
+
+func TestRuns(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s := &state.State{Runs: state.NewRunStore()}
+	broker := logs.NewDevLogBroker()
+	s.AddRun("my_task", "run_1", dev.LocalRun{
+		RunID:       "run_1",
+		TaskSlug:    "my_task",
+		Status:      api.RunActive,
+		ParamValues: map[string]interface{}{"name": "world"},
+		LogBroker:   broker,
+	})
+	client := newClient(t, s, nil)
+
+	watch, err := client.Watch(ctx)
+	require.NoError(err)
+	require.NoError(watch.Send(&devrpcpb.WatchRequest{Add: []string{"run_1", "run_missing"}}))
+	require.NoError(watch.CloseSend())
+
+	broker.Record(api.LogItem{Text: "hello", Level: api.LogLevelInfo})
+	broker.Close()
+	// Runs are marked as finished after their logs close, which Watch waits for.
+	_, err = s.UpdateRun("run_1", func(run *dev.LocalRun) error {
+		run.Status = api.RunSucceeded
+		run.Outputs = api.Outputs{V: "hello world"}
+		return nil
+	})
+	require.NoError(err)
+
+	var events []*devrpcpb.WatchEvent
+	for {
+		event, err := watch.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		events = append(events, event)
+	}
+	require.Len(events, 3)
+	var runEvents []*devrpcpb.WatchEvent
+	for _, e := range events {
+		if e.RunId == "run_missing" {
+			require.Contains(e.Error, `Run with id "run_missing" not found`)
+		} else {
+			runEvents = append(runEvents, e)
+		}
+	}
+	require.Len(runEvents, 2)
+	require.Equal("hello", runEvents[0].Log.Text)
+	require.Equal("info", runEvents[0].Log.Level)
+	require.Equal("Succeeded", runEvents[1].Run.Status)
+	require.Equal("hello world", runEvents[1].Run.Outputs.GetStringValue())
+	require.Equal("world", runEvents[1].Run.ParamValues.AsMap()["name"])
+
+	// Logs of finished runs are replayed.
+	stream, err := client.StreamLogs(ctx, &devrpcpb.StreamLogsRequest{RunId: "run_1"})
+	require.NoError(err)
+	log, err := stream.Recv()
+	require.NoError(err)
+	require.Equal("hello", log.Text)
+	_, err = stream.Recv()
+	require.Equal(io.EOF, err)
+
+	_, err = client.GetRun(ctx, &devrpcpb.GetRunRequest{RunId: "run_missing"})
+	require.Equal(codes.NotFound, status.Code(err))
+
+	// Logs of runs in the fallback environment aren't streamed to the dev server.
+	s.AddRun("my_task", "run_remote", dev.LocalRun{
+		RunID:     "run_remote",
+		TaskSlug:  "my_task",
+		Status:    api.RunActive,
+		Remote:    true,
+		LogBroker: logs.NewDevLogBroker(),
+	})
+	stream, err = client.StreamLogs(ctx, &devrpcpb.StreamLogsRequest{RunId: "run_remote"})
+	require.NoError(err)
+	_, err = stream.Recv()
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func TestToken(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	client := newClient(t, &state.State{
+		TaskConfigs: state.NewStore[string, discover.TaskConfig](nil),
+		ViewConfigs: state.NewStore[string, discover.ViewConfig](nil),
+	}, pointers.String("secret"))
+
+	_, err := client.ListEntities(ctx, &devrpcpb.ListEntitiesRequest{})
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(ctx, TokenMetadataKey, "secret")
+	_, err = client.ListEntities(ctx, &devrpcpb.ListEntitiesRequest{})
+	require.NoError(err)
+}
@@ -59,6 +59,9 @@ type AppWatcher struct {
 	pollInterval time.Duration
 	callback     func(e Event) error
 	isValid      func(path string) bool
+	// additionalPaths are the paths watched outside of the working directory, or that aren't
+	// definition files, like env files.
+	additionalPaths map[string]bool
 }
 
 type AppWatcherOpts struct {
@@ -108,6 +111,20 @@ func (f *AppWatcher) Watch(wd string, additionalPaths ...string) error {
 	logger.Log(logger.Green("Watching for changes in: %s", wd))
 	logger.Log(logger.Green("Changes to tasks and views will be applied automatically."))
 
+	// Watch any additional paths for changes. These are added before hidden files are ignored,
+	// since env files like .env are hidden.
+	f.additionalPaths = map[string]bool{}
+	for _, path := range additionalPaths {
+		if err := f.watcher.Add(path); err != nil {
+			return err
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		f.additionalPaths[abs] = true
+	}
+
 	// Ignore hidden files and known directories
 	f.watcher.IgnoreHiddenFiles(true)
 	directoriesToIgnore := make([]string, 0, len(discover.IgnoredDirectories))
@@ -122,25 +139,19 @@ func (f *AppWatcher) Watch(wd string, additionalPaths ...string) error {
 		return err
 	}
 
-	// Watch any additional paths for changes.
-	for _, path := range additionalPaths {
-		if err := f.watcher.Add(path); err != nil {
-			return err
-		}
-	}
 	// Listen for changes
 	go func() {
 		for {
 			select {
 			case e := <-f.watcher.Event:
-				if !e.IsDir() && f.isValid(e.Path) {
+				if !e.IsDir() && (f.additionalPaths[e.Path] || f.isValid(e.Path)) {
 					event := toEvent(e)
 					if err := f.callback(event); err != nil {
 						logger.Log("Error refreshing app in [%s]: %v", event.Path, err)
 					}
 				}
 			case err := <-f.watcher.Error:
-				logger.Error("Watching for changes: ", err)
+				logger.Error("Watching for changes: %v", err)
 			case <-f.watcher.Closed:
 				logger.Log(" ")
 				logger.Log("Stopped watching for changes.")
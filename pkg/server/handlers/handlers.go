@@ -134,7 +134,8 @@ func WriteHTTPError(w http.ResponseWriter, r *http.Request, err error, report fu
 	}
 
 	out, err := json.Marshal(libhttp.ErrorResponse{
-		Error: errStatusCode.Msg,
+		Error:  errStatusCode.Msg,
+		Issues: errStatusCode.Issues,
 	})
 	if err != nil {
 		report(errors.Wrap(err, "marshaling error response"))
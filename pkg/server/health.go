@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/airplanedev/cli/pkg/server/state"
+	"github.com/gorilla/mux"
+)
+
+const (
+	healthzPath = "/healthz"
+	readyzPath  = "/readyz"
+)
+
+// HealthResponse is the response body of the /healthz and /readyz endpoints.
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// attachHealthRoutes attaches the liveness and readiness endpoints. /healthz succeeds as long as the
+// server is serving requests, whereas /readyz only succeeds once the initial discovery has completed
+// and the server has been marked as ready.
+func attachHealthRoutes(r *mux.Router, s *state.State) {
+	r.HandleFunc(healthzPath, func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, http.StatusOK, "ok")
+	}).Methods("GET", "HEAD")
+
+	r.HandleFunc(readyzPath, func(w http.ResponseWriter, r *http.Request) {
+		if !s.IsReady() {
+			writeHealth(w, http.StatusServiceUnavailable, "starting")
+			return
+		}
+		writeHealth(w, http.StatusOK, "ready")
+	}).Methods("GET", "HEAD")
+}
+
+func isHealthPath(path string) bool {
+	return path == healthzPath || path == readyzPath
+}
+
+func writeHealth(w http.ResponseWriter, code int, status string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(HealthResponse{Status: status})
+}
@@ -0,0 +1,29 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/server"
+	"github.com/airplanedev/cli/pkg/server/state"
+	"github.com/airplanedev/cli/pkg/server/test_utils"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	s := &state.State{}
+	// Health checks must not require the dev token.
+	h := test_utils.GetHttpExpect(
+		context.Background(),
+		t,
+		server.NewRouter(s, server.Options{Token: pointers.String("secret")}),
+	)
+
+	h.GET("/healthz").Expect().Status(http.StatusOK).JSON().Object().Value("status").Equal("ok")
+	h.GET("/readyz").Expect().Status(http.StatusServiceUnavailable).JSON().Object().Value("status").Equal("starting")
+	h.GET("/dev/version").Expect().Status(http.StatusUnauthorized)
+
+	s.MarkReady()
+	h.GET("/readyz").Expect().Status(http.StatusOK).JSON().Object().Value("status").Equal("ready")
+}
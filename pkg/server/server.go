@@ -8,11 +8,13 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/airplanedev/cli/pkg/deploy/discover"
 	"github.com/airplanedev/cli/pkg/server/apidev"
 	"github.com/airplanedev/cli/pkg/server/apiext"
 	"github.com/airplanedev/cli/pkg/server/apiint"
+	"github.com/airplanedev/cli/pkg/server/devrpc"
 	"github.com/airplanedev/cli/pkg/server/filewatcher"
 	"github.com/airplanedev/cli/pkg/server/middleware"
 	"github.com/airplanedev/cli/pkg/server/network"
@@ -21,16 +23,24 @@ import (
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
 type Server struct {
 	srv      *http.Server
 	listener net.Listener
 	state    *state.State
+
+	// grpcSrv serves DevService on grpcListener, if enabled.
+	grpcSrv      *grpc.Server
+	grpcListener net.Listener
 }
 
 const defaultPort = 4000
 
+// retentionInterval is how often the retention policy is applied to clean up runs that have aged out.
+const retentionInterval = time.Minute
+
 var corsOrigins = []string{
 	`\.airplane\.so:5000$`,
 	`\.airstage\.app$`,
@@ -87,6 +97,12 @@ func NewRouter(state *state.State, opts Options) *mux.Router {
 	if opts.Token != nil && !opts.Sandbox {
 		r.Use(func(next http.Handler) http.Handler {
 			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Health checks are unauthenticated so that scripts can wait for the server without a token.
+				if isHealthPath(r.URL.Path) {
+					next.ServeHTTP(w, r)
+					return
+				}
+
 				// Requests to /dev/views are authenticated by the token in the path, since Vite does not support
 				// appending query parameters or headers to source paths.
 				if strings.HasPrefix(r.URL.Path, "/dev/views/") {
@@ -109,6 +125,7 @@ func NewRouter(state *state.State, opts Options) *mux.Router {
 
 	r.Use(middleware.ReqBodyDecompression)
 
+	attachHealthRoutes(r, state)
 	apiext.AttachExternalAPIRoutes(r.NewRoute().Subrouter(), state)
 	apiint.AttachInternalAPIRoutes(r.NewRoute().Subrouter(), state)
 	apidev.AttachDevRoutes(r.NewRoute().Subrouter(), state)
@@ -123,12 +140,19 @@ type Options struct {
 	// accessed outside a container.
 	Sandbox bool
 
+	// MaxConcurrentRuns is the maximum number of local runs that can execute at once. Additional runs are queued
+	// and started in priority order. If 0, the number of concurrent runs is not limited.
+	MaxConcurrentRuns int
+
 	// Optional listener that will be used in lieu of port/expose configuration. This is used for ngrok tunnels.
 	Listener net.Listener
 
 	// Optional token that will install auth middleware for all non-OPTIONS requests. Auth will need to be passed
 	// in the "Authorization" header with format "Bearer <token>".
 	Token *string
+
+	// GRPCPort is the port to serve the gRPC API for editors and agents on. If 0, it isn't served.
+	GRPCPort int
 }
 
 // newServer returns a new HTTP server with API routes
@@ -161,11 +185,27 @@ func newServer(router *mux.Router, state *state.State, opts Options) (*Server, e
 		}
 	}
 
-	return &Server{
+	s := &Server{
 		srv:      srv,
 		listener: opts.Listener,
 		state:    state,
-	}, nil
+	}
+
+	if opts.GRPCPort != 0 {
+		if !network.IsPortOpen("", opts.GRPCPort) {
+			return nil, errors.Errorf("port %d is already in use - select a different port for --grpc-port", opts.GRPCPort)
+		}
+		var err error
+		s.grpcListener, err = net.Listen("tcp", network.LocalAddress(opts.GRPCPort, opts.Sandbox))
+		if err != nil {
+			return nil, errors.Wrap(err, "listening on gRPC port")
+		}
+		// Unlike HTTP requests, gRPC calls aren't proxied through the API server in sandbox mode, so
+		// the token is always checked.
+		s.grpcSrv = devrpc.NewGRPCServer(state, opts.Token)
+	}
+
+	return s, nil
 }
 
 // Start starts and returns a new instance of the Airplane API server along with the port it is listening on.
@@ -174,6 +214,7 @@ func Start(opts Options) (*Server, int, error) {
 	if err != nil {
 		return nil, 0, err
 	}
+	s.RunQueue = state.NewRunQueue(opts.MaxConcurrentRuns)
 
 	r := NewRouter(s, opts)
 	apiServer, err := newServer(r, s, opts)
@@ -188,6 +229,15 @@ func Start(opts Options) (*Server, int, error) {
 			os.Exit(1)
 		}
 	}()
+	if apiServer.grpcSrv != nil {
+		go func() {
+			if err := apiServer.grpcSrv.Serve(apiServer.grpcListener); err != nil {
+				logger.Log("")
+				logger.Error(fmt.Sprintf("failed to start gRPC server: %v", err))
+				os.Exit(1)
+			}
+		}()
+	}
 
 	var port int
 	tcpAddr, ok := apiServer.listener.Addr().(*net.TCPAddr)
@@ -205,6 +255,9 @@ func (s *Server) RegisterState(newState *state.State) {
 	s.state.RemoteClient = newState.RemoteClient
 	s.state.InitialRemoteEnvSlug = newState.InitialRemoteEnvSlug
 	s.state.Executor = newState.Executor
+	s.state.SandboxedRuns = newState.SandboxedRuns
+	s.state.RetentionPolicy = newState.RetentionPolicy
+	s.state.History = newState.History
 	s.state.DevConfig = newState.DevConfig
 	s.state.Dir = newState.Dir
 	s.state.AuthInfo = newState.AuthInfo
@@ -215,6 +268,27 @@ func (s *Server) RegisterState(newState *state.State) {
 	s.state.ServerHost = newState.ServerHost
 }
 
+// StartRetention periodically cleans up runs and temporary run directories that fall outside of the
+// server's retention policy until ctx is cancelled.
+func (s *Server) StartRetention(ctx context.Context) {
+	s.state.StartRetention(ctx, retentionInterval)
+}
+
+// LoadRunHistory restores the runs that a previous dev server executed in the same directory, and then
+// applies the retention policy to them.
+func (s *Server) LoadRunHistory() error {
+	if err := s.state.LoadRunHistory(); err != nil {
+		return err
+	}
+	s.state.ApplyRetention()
+	return nil
+}
+
+// MarkReady marks the server as ready, after which /readyz succeeds.
+func (s *Server) MarkReady() {
+	s.state.MarkReady()
+}
+
 func (s *Server) DiscoverTasksAndViews(ctx context.Context, paths ...string) ([]discover.TaskConfig, []discover.ViewConfig, error) {
 	return s.state.DiscoverTasksAndViews(ctx, paths...)
 }
@@ -252,5 +326,8 @@ func (s *Server) RegisterTasksAndViews(ctx context.Context, opts state.DiscoverO
 // Stop terminates the local dev API server.
 func (s *Server) Stop(ctx context.Context) error {
 	s.state.ViteContexts.Purge()
+	if s.grpcSrv != nil {
+		s.grpcSrv.GracefulStop()
+	}
 	return s.srv.Shutdown(ctx)
 }
@@ -47,8 +47,10 @@ func (s *State) ReloadPath(ctx context.Context, path string) error {
 				}
 			}
 
-			// Refresh any tasks that have the modified entrypoint.
-			shouldRefreshTask = shouldRefreshTask || tC.TaskEntrypoint == path
+			// Refresh any tasks that have the modified entrypoint, env file, or shared file that
+			// their definition extends.
+			shouldRefreshTask = shouldRefreshTask || tC.TaskEntrypoint == path || slices.Contains(tC.Def.GetEnvFiles(), path) ||
+				slices.Contains(tC.Def.GetExtendedFiles(), path)
 			if shouldRefreshTask {
 				pathsToDiscover = append(pathsToDiscover, tC.Def.GetDefnFilePath())
 			}
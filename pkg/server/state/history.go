@@ -0,0 +1,109 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	libhttp "github.com/airplanedev/cli/pkg/api/http"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/dev/history"
+	"github.com/airplanedev/cli/pkg/dev/logs"
+	"github.com/airplanedev/cli/pkg/flags/flagsiface"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/airplanedev/ojson"
+)
+
+// saveRun persists a run to the server's run history, if it has one. The values of sensitive
+// parameters are removed, and secrets are redacted from its logs, since the history is kept on disk.
+func (s *State) saveRun(run dev.LocalRun) {
+	if s.History == nil || run.Remote {
+		return
+	}
+	if err := sanitizeInputs(&run); err != nil {
+		logger.Warning("Unable to save run %s to the run history: %v", run.RunID, err)
+		return
+	}
+	record := history.Record{Run: run, Dir: s.Dir}
+	if run.LogBroker != nil {
+		for _, l := range run.LogBroker.Logs() {
+			l.Text = logger.Redact(l.Text)
+			record.Logs = append(record.Logs, l)
+		}
+	}
+	if err := s.History.Save(record); err != nil {
+		logger.Warning("Unable to save run %s to the run history: %v", run.RunID, err)
+	}
+}
+
+// LoadRunHistory restores the runs that were previously executed in the server's directory. Runs
+// that were still in progress when their dev server exited are marked as failed.
+func (s *State) LoadRunHistory() error {
+	if s.History == nil {
+		return nil
+	}
+	records, _, err := s.History.List(history.ListOpts{})
+	if err != nil {
+		return err
+	}
+
+	// Runs are listed most recent first, but need to be added oldest first.
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		run := r.Run
+		run.LogBroker = logs.NewClosedDevLogBroker(r.Logs)
+		if !run.Status.IsTerminal() {
+			run.Status = api.RunFailed
+			if run.Outputs.V == nil {
+				run.Outputs = api.Outputs{
+					V: ojson.NewObject().SetAndReturn("error", "The dev server exited before the run completed."),
+				}
+			}
+			s.saveRun(run)
+		}
+		s.Runs.Add(run.TaskSlug, run.RunID, run)
+	}
+	return nil
+}
+
+// ListRunHistory returns a page of the runs that were executed in the server's directory, most
+// recent first, and the total number of such runs.
+func (s *State) ListRunHistory(ctx context.Context, opts history.ListOpts) ([]dev.LocalRun, int, error) {
+	if s.History == nil {
+		return nil, 0, libhttp.NewErrBadRequest("run history is not enabled")
+	}
+	records, total, err := s.History.List(opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	sanitize := s.Flagger != nil && s.Flagger.Bool(ctx, s.Logger, flagsiface.SanitizeInputs)
+	runs := make([]dev.LocalRun, len(records))
+	for i, r := range records {
+		runs[i] = r.Run
+		if sanitize {
+			if err := sanitizeInputs(&runs[i]); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return runs, total, nil
+}
+
+// pruneHistory applies the retention policy to the whole run history, which includes runs that
+// this server never loaded. The history keeps history.DefaultMaxRuns if the policy doesn't limit
+// the number of runs.
+func (s *State) pruneHistory(now time.Time) {
+	if s.History == nil {
+		return
+	}
+	pruned, err := s.History.Prune(history.PruneOpts{
+		MaxRuns:    s.RetentionPolicy.MaxRuns,
+		MaxAge:     s.RetentionPolicy.MaxAge,
+		KeepFailed: s.RetentionPolicy.KeepFailed,
+	}, now)
+	if err != nil {
+		logger.Warning("Unable to prune the run history: %v", err)
+	} else if pruned > 0 {
+		logger.Debug("Removed %d runs from the run history", pruned)
+	}
+}
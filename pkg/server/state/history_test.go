@@ -0,0 +1,78 @@
+package state
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/dev/history"
+	"github.com/airplanedev/cli/pkg/dev/logs"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHistory(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := history.New(filepath.Join(t.TempDir(), "runs.db"))
+	now := time.Now()
+
+	s := &State{Runs: NewRunStore(), History: store, Dir: "/project"}
+	broker := logs.NewDevLogBroker()
+	logger.RegisterSecrets("hunter2-password")
+	s.AddRun("task1", "run_0", dev.LocalRun{
+		TaskSlug:    "task1",
+		Status:      api.RunActive,
+		CreatedAt:   now.Add(-time.Hour),
+		LogBroker:   broker,
+		Parameters:  &libapi.Parameters{{Slug: "password", Type: libapi.TypeString}},
+		ParamValues: map[string]interface{}{"password": "hunter2-password"},
+	})
+	s.AddRun("task1", "run_1", dev.LocalRun{TaskSlug: "task1", Status: api.RunActive, CreatedAt: now, LogBroker: logs.NewDevLogBroker()})
+	broker.Record(api.LogItem{Text: "hello hunter2-password"})
+	broker.Close()
+	_, err := s.UpdateRun("run_0", func(run *dev.LocalRun) error {
+		run.Status = api.RunSucceeded
+		return nil
+	})
+	require.NoError(err)
+
+	// Sensitive parameters and secrets aren't saved.
+	saved, ok, err := store.Get("run_0")
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(map[string]interface{}{"password": "****"}, saved.Run.ParamValues)
+	require.NotContains(saved.Logs[0].Text, "hunter2-password")
+
+	// A new server restores the runs, including run_1 that never completed.
+	restored := &State{Runs: NewRunStore(), History: store, Dir: "/project"}
+	require.NoError(restored.LoadRunHistory())
+	runs, err := restored.GetRunHistory(ctx, "task1")
+	require.NoError(err)
+	require.Len(runs, 2)
+	require.Equal("run_1", runs[0].RunID)
+	require.Equal(api.RunFailed, runs[0].Status)
+	require.Equal("run_0", runs[1].RunID)
+	require.Equal(api.RunSucceeded, runs[1].Status)
+	require.Equal(saved.Logs, runs[1].LogBroker.Logs())
+
+	page, total, err := restored.ListRunHistory(ctx, history.ListOpts{Limit: 1})
+	require.NoError(err)
+	require.Equal(2, total)
+	require.Len(page, 1)
+	require.Equal("run_1", page[0].RunID)
+
+	// The retention policy applies to the history too.
+	restored.RetentionPolicy = RetentionPolicy{MaxRuns: 1}
+	restored.ApplyRetention()
+	_, ok, err = store.Get("run_0")
+	require.NoError(err)
+	require.False(ok)
+	_, ok, err = store.Get("run_1")
+	require.NoError(err)
+	require.True(ok)
+}
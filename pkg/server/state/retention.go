@@ -0,0 +1,209 @@
+package state
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/dev/history"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+)
+
+// Defaults of the dev server's retention policy, so that a long-running dev server doesn't
+// accumulate runs in memory and run directories on disk without bound.
+const (
+	DefaultMaxRuns   = history.DefaultMaxRuns
+	DefaultMaxRunAge = 7 * 24 * time.Hour
+)
+
+// RetentionPolicy controls how long the local dev server holds on to completed runs (including their
+// logs and outputs) and the temporary directories created while executing them.
+type RetentionPolicy struct {
+	// MaxRuns is the maximum number of completed runs to retain, e.g. DefaultMaxRuns. If zero, runs are
+	// not limited by count.
+	MaxRuns int
+	// MaxAge is the maximum age of completed runs and temporary run directories to retain, e.g.
+	// DefaultMaxRunAge. If zero, they are not limited by age.
+	MaxAge time.Duration
+	// KeepFailed exempts failed runs from cleanup, along with their temporary run directories. Since
+	// sandbox workspaces cannot be told apart from other stale ones, and the run of a task's run
+	// directory may no longer be known, such directories are not removed when this is set.
+	KeepFailed bool
+}
+
+// IsZero returns true if the policy never cleans anything up.
+func (p RetentionPolicy) IsZero() bool {
+	return p.MaxRuns == 0 && p.MaxAge == 0
+}
+
+// Prune removes completed runs that fall outside of the policy. Runs that are still in progress are
+// never removed. It returns the IDs of the runs that were removed.
+func (store *runsStore) Prune(policy RetentionPolicy, now time.Time) []string {
+	if policy.IsZero() {
+		return nil
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var candidates []dev.LocalRun
+	for _, run := range store.runs {
+		if !run.Status.IsTerminal() {
+			continue
+		}
+		if policy.KeepFailed && run.Status == api.RunFailed {
+			continue
+		}
+		candidates = append(candidates, run)
+	}
+	// Most recent runs first.
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].CreatedAt.After(candidates[j].CreatedAt)
+	})
+
+	pruned := map[string]bool{}
+	for i, run := range candidates {
+		tooMany := policy.MaxRuns > 0 && i >= policy.MaxRuns
+		tooOld := policy.MaxAge > 0 && now.Sub(run.CreatedAt) > policy.MaxAge
+		if tooMany || tooOld {
+			pruned[run.RunID] = true
+		}
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+
+	for runID := range pruned {
+		delete(store.runs, runID)
+		delete(store.runDescendants, runID)
+	}
+	for taskSlug, runIDs := range store.runHistory {
+		store.runHistory[taskSlug] = filterRunIDs(runIDs, pruned)
+	}
+	for parentID, runIDs := range store.runDescendants {
+		store.runDescendants[parentID] = filterRunIDs(runIDs, pruned)
+	}
+
+	ids := make([]string, 0, len(pruned))
+	for runID := range pruned {
+		ids = append(ids, runID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func filterRunIDs(runIDs []string, exclude map[string]bool) []string {
+	filtered := make([]string, 0, len(runIDs))
+	for _, runID := range runIDs {
+		if !exclude[runID] {
+			filtered = append(filtered, runID)
+		}
+	}
+	return filtered
+}
+
+// ApplyRetention removes runs and temporary run directories that fall outside of the server's retention
+// policy, and prunes the run history.
+func (s *State) ApplyRetention() {
+	now := time.Now()
+	if s.RetentionPolicy.MaxAge > 0 {
+		if !s.RetentionPolicy.KeepFailed {
+			removeStaleRunDirs(os.TempDir(), s.RetentionPolicy.MaxAge, now)
+		}
+		// Run directories are cleaned up before runs are pruned, since the status of their runs
+		// is unknown afterwards.
+		for _, taskConfig := range s.TaskConfigs.Values() {
+			taskDir := filepath.Join(taskConfig.TaskRoot, ".airplane", taskConfig.Def.GetSlug())
+			s.removeStaleTaskRunDirs(taskDir, now)
+		}
+	}
+	if pruned := s.Runs.Prune(s.RetentionPolicy, now); len(pruned) > 0 {
+		logger.Debug("Removed %d runs outside of the retention policy", len(pruned))
+	}
+	s.pruneHistory(now)
+}
+
+// StartRetention periodically applies the server's retention policy until ctx is cancelled.
+func (s *State) StartRetention(ctx context.Context, interval time.Duration) {
+	if s.RetentionPolicy.IsZero() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.ApplyRetention()
+			}
+		}
+	}()
+}
+
+// removeStaleTaskRunDirs removes run directories in a task's .airplane/<task slug> directory that were
+// last modified longer ago than the retention policy's max age. Like sandbox workspaces, these are
+// normally removed once a run completes, but can be left behind by runs that the dev server didn't
+// see through to the end.
+func (s *State) removeStaleTaskRunDirs(taskDir string, now time.Time) {
+	entries, err := os.ReadDir(taskDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Debug("Unable to read %s: %v", taskDir, err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), utils.DevRunPrefix) {
+			continue
+		}
+		run, ok := s.Runs.Get(entry.Name())
+		if ok && !run.Status.IsTerminal() {
+			// The run is still using its directory.
+			continue
+		}
+		if s.RetentionPolicy.KeepFailed && (!ok || run.Status == api.RunFailed) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) <= s.RetentionPolicy.MaxAge {
+			continue
+		}
+		path := filepath.Join(taskDir, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			logger.Debug("Unable to remove %s: %v", path, err)
+		}
+	}
+}
+
+// removeStaleRunDirs removes temporary run directories in dir that were last modified more than maxAge
+// ago. These are normally removed once a run completes, but can be left behind if the dev server exits
+// while a run is in progress.
+func removeStaleRunDirs(dir string, maxAge time.Duration, now time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logger.Debug("Unable to read %s: %v", dir, err)
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), dev.RunWorkspacePrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) <= maxAge {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			logger.Debug("Unable to remove %s: %v", path, err)
+		}
+	}
+}
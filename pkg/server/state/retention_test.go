@@ -0,0 +1,162 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/airplanedev/cli/pkg/deploy/discover"
+	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStorePrune(t *testing.T) {
+	now := time.Now()
+	newStore := func() *runsStore {
+		s := NewRunStore()
+		s.Add("task1", "run_0", dev.LocalRun{Status: api.RunSucceeded, CreatedAt: now.Add(-3 * time.Hour)})
+		s.Add("task1", "run_1", dev.LocalRun{Status: api.RunFailed, CreatedAt: now.Add(-2 * time.Hour)})
+		s.Add("task2", "run_2", dev.LocalRun{Status: api.RunActive, CreatedAt: now.Add(-4 * time.Hour)})
+		s.Add("task2", "run_3", dev.LocalRun{Status: api.RunSucceeded, CreatedAt: now.Add(-time.Hour), ParentID: "run_2"})
+		s.Add("task2", "run_4", dev.LocalRun{Status: api.RunCancelled, CreatedAt: now})
+		return s
+	}
+
+	testCases := []struct {
+		desc      string
+		policy    RetentionPolicy
+		pruned    []string
+		remaining map[string][]string
+	}{
+		{
+			desc:      "no policy",
+			policy:    RetentionPolicy{},
+			remaining: map[string][]string{"task1": {"run_1", "run_0"}, "task2": {"run_4", "run_3", "run_2"}},
+		},
+		{
+			desc:      "max runs",
+			policy:    RetentionPolicy{MaxRuns: 2},
+			pruned:    []string{"run_0", "run_1"},
+			remaining: map[string][]string{"task1": {}, "task2": {"run_4", "run_3", "run_2"}},
+		},
+		{
+			desc:      "max age",
+			policy:    RetentionPolicy{MaxAge: 90 * time.Minute},
+			pruned:    []string{"run_0", "run_1"},
+			remaining: map[string][]string{"task1": {}, "task2": {"run_4", "run_3", "run_2"}},
+		},
+		{
+			desc:      "keep failed",
+			policy:    RetentionPolicy{MaxRuns: 1, KeepFailed: true},
+			pruned:    []string{"run_0", "run_3"},
+			remaining: map[string][]string{"task1": {"run_1"}, "task2": {"run_4", "run_2"}},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			require := require.New(t)
+			s := newStore()
+
+			pruned := s.Prune(tC.policy, now)
+			require.Equal(tC.pruned, pruned)
+			for taskSlug, expected := range tC.remaining {
+				var actual []string
+				for _, run := range s.GetRunHistory(taskSlug) {
+					actual = append(actual, run.RunID)
+				}
+				require.ElementsMatch(expected, actual, taskSlug)
+			}
+			for _, runID := range tC.pruned {
+				_, ok := s.Get(runID)
+				require.False(ok)
+			}
+		})
+	}
+
+	// Pruned descendants are removed from their parent.
+	s := newStore()
+	s.Prune(RetentionPolicy{MaxRuns: 1}, now)
+	require.Empty(t, s.GetDescendants("run_2"))
+}
+
+func TestRemoveStaleRunDirs(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	now := time.Now()
+
+	stale := filepath.Join(dir, dev.RunWorkspacePrefix+"stale")
+	fresh := filepath.Join(dir, dev.RunWorkspacePrefix+"fresh")
+	other := filepath.Join(dir, "other")
+	for _, path := range []string{stale, fresh, other} {
+		require.NoError(os.Mkdir(path, 0755))
+	}
+	old := now.Add(-2 * time.Hour)
+	require.NoError(os.Chtimes(stale, old, old))
+	require.NoError(os.Chtimes(other, old, old))
+
+	removeStaleRunDirs(dir, time.Hour, now)
+
+	require.NoDirExists(stale)
+	require.DirExists(fresh)
+	require.DirExists(other)
+}
+
+func TestRemoveStaleTaskRunDirs(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+
+	for _, keepFailed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("keepFailed=%v", keepFailed), func(t *testing.T) {
+			require := require.New(t)
+			// Keep ApplyRetention away from the real temp directory.
+			t.Setenv("TMPDIR", t.TempDir())
+			root := t.TempDir()
+			taskDir := filepath.Join(root, ".airplane", "my_task")
+
+			s := &State{
+				Runs:            NewRunStore(),
+				TaskConfigs:     NewStore[string, discover.TaskConfig](nil),
+				RetentionPolicy: RetentionPolicy{MaxAge: time.Hour, KeepFailed: keepFailed},
+			}
+			s.TaskConfigs.Add("my_task", discover.TaskConfig{
+				TaskRoot: root,
+				Def:      definitions.Definition{Slug: "my_task"},
+			})
+			s.Runs.Add("my_task", "devrunsucceeded", dev.LocalRun{Status: api.RunSucceeded})
+			s.Runs.Add("my_task", "devrunfailed", dev.LocalRun{Status: api.RunFailed})
+			s.Runs.Add("my_task", "devrunactive", dev.LocalRun{Status: api.RunActive})
+
+			dirs := map[string]time.Time{
+				"devrunsucceeded": old,
+				"devrunfailed":    old,
+				"devrunactive":    old,
+				"devrununknown":   old,
+				"devrunfresh":     now,
+				"node_modules":    old,
+			}
+			for name, modTime := range dirs {
+				path := filepath.Join(taskDir, name)
+				require.NoError(os.MkdirAll(path, 0755))
+				require.NoError(os.Chtimes(path, modTime, modTime))
+			}
+
+			s.ApplyRetention()
+
+			require.NoDirExists(filepath.Join(taskDir, "devrunsucceeded"))
+			require.DirExists(filepath.Join(taskDir, "devrunactive"))
+			require.DirExists(filepath.Join(taskDir, "devrunfresh"))
+			require.DirExists(filepath.Join(taskDir, "node_modules"))
+			if keepFailed {
+				require.DirExists(filepath.Join(taskDir, "devrunfailed"))
+				require.DirExists(filepath.Join(taskDir, "devrununknown"))
+			} else {
+				require.NoDirExists(filepath.Join(taskDir, "devrunfailed"))
+				require.NoDirExists(filepath.Join(taskDir, "devrununknown"))
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package state
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// RunQueue limits the number of local runs that execute concurrently. Once all slots are in use,
+// additional runs are queued and started in priority order (highest first), falling back to the
+// order in which they were enqueued.
+//
+// A parent run that is waiting on child runs does not count against the limit: otherwise a parent
+// holding the last slot would wait forever on children that can never start.
+//
+// A nil RunQueue does not limit concurrency.
+type RunQueue struct {
+	// limit is the maximum number of runs that can execute at once. If zero, there is no limit.
+	limit   int
+	active  int
+	pending pendingRuns
+	seq     int64
+
+	// running tracks the runs that have started and not yet finished, and whether each one
+	// currently holds a slot.
+	running map[string]bool
+	// children counts the unfinished child runs, queued or running, of each parent run.
+	children map[string]int
+
+	mu sync.Mutex
+}
+
+// NewRunQueue returns a RunQueue that executes at most limit runs at once. If limit is zero, runs are
+// never queued.
+func NewRunQueue(limit int) *RunQueue {
+	return &RunQueue{
+		limit:    limit,
+		running:  map[string]bool{},
+		children: map[string]int{},
+	}
+}
+
+// Enqueue schedules fn, which executes run runID, to be called in a new goroutine as soon as a slot
+// is available. If parentID is set, the parent run gives up its slot until all of its children
+// have finished. It returns true if the run was queued rather than started immediately.
+func (q *RunQueue) Enqueue(runID, parentID string, priority int, fn func()) bool {
+	if q == nil {
+		go fn()
+		return false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if parentID != "" {
+		q.children[parentID]++
+		if q.running[parentID] {
+			// The parent is now waiting on this child, so release its slot.
+			q.running[parentID] = false
+			q.active--
+		}
+	}
+
+	q.seq++
+	heap.Push(&q.pending, pendingRun{runID: runID, parentID: parentID, priority: priority, seq: q.seq, fn: fn})
+	q.startPending()
+	_, started := q.running[runID]
+	return !started
+}
+
+// Len returns the number of runs that are waiting for a slot.
+func (q *RunQueue) Len() int {
+	if q == nil {
+		return 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pending.Len()
+}
+
+// startPending starts queued runs while slots are available. It must be called with q.mu held.
+func (q *RunQueue) startPending() {
+	for q.pending.Len() > 0 && (q.limit <= 0 || q.active < q.limit) {
+		q.start(heap.Pop(&q.pending).(pendingRun))
+	}
+}
+
+// start must be called with q.mu held.
+func (q *RunQueue) start(run pendingRun) {
+	// A parent whose children were enqueued before it started is already waiting on them.
+	holdsSlot := q.children[run.runID] == 0
+	if holdsSlot {
+		q.active++
+	}
+	q.running[run.runID] = holdsSlot
+	go func() {
+		defer q.done(run)
+		run.fn()
+	}()
+}
+
+func (q *RunQueue) done(run pendingRun) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.running[run.runID] {
+		q.active--
+	}
+	delete(q.running, run.runID)
+
+	if run.parentID != "" {
+		q.children[run.parentID]--
+		if q.children[run.parentID] <= 0 {
+			delete(q.children, run.parentID)
+			if holdsSlot, ok := q.running[run.parentID]; ok && !holdsSlot {
+				// The parent is no longer waiting on children, so it counts against the limit
+				// again. This can briefly exceed the limit, but never blocks the parent.
+				q.running[run.parentID] = true
+				q.active++
+			}
+		}
+	}
+
+	q.startPending()
+}
+
+type pendingRun struct {
+	runID    string
+	parentID string
+	priority int
+	seq      int64
+	fn       func()
+}
+
+// pendingRuns implements heap.Interface, ordering runs by descending priority and then by ascending
+// enqueue order.
+type pendingRuns []pendingRun
+
+func (p pendingRuns) Len() int { return len(p) }
+
+func (p pendingRuns) Less(i, j int) bool {
+	if p[i].priority != p[j].priority {
+		return p[i].priority > p[j].priority
+	}
+	return p[i].seq < p[j].seq
+}
+
+func (p pendingRuns) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+func (p *pendingRuns) Push(x any) { *p = append(*p, x.(pendingRun)) }
+
+func (p *pendingRuns) Pop() any {
+	old := *p
+	n := len(old)
+	item := old[n-1]
+	*p = old[:n-1]
+	return item
+}
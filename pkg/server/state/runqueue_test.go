@@ -0,0 +1,90 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunQueue(t *testing.T) {
+	require := require.New(t)
+	q := NewRunQueue(1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	require.False(q.Enqueue("run0", "", 0, func() {
+		close(started)
+		<-release
+	}))
+	<-started
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	record := func(name string) func() {
+		wg.Add(1)
+		return func() {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+		}
+	}
+
+	require.True(q.Enqueue("low", "", 0, record("low")))
+	require.True(q.Enqueue("high", "", 10, record("high")))
+	require.True(q.Enqueue("medium", "", 5, record("medium")))
+	require.True(q.Enqueue("high2", "", 10, record("high2")))
+	require.Equal(4, q.Len())
+
+	close(release)
+	wg.Wait()
+	require.Equal([]string{"high", "high2", "medium", "low"}, order)
+	require.Equal(0, q.Len())
+}
+
+func TestRunQueueUnlimited(t *testing.T) {
+	require := require.New(t)
+
+	for _, q := range []*RunQueue{NewRunQueue(0), nil} {
+		var wg sync.WaitGroup
+		release := make(chan struct{})
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			require.False(q.Enqueue(fmt.Sprintf("run%d", i), "", i, func() {
+				defer wg.Done()
+				<-release
+			}))
+		}
+		require.Equal(0, q.Len())
+		close(release)
+		wg.Wait()
+	}
+}
+
+func TestRunQueueParentWaitingOnChildren(t *testing.T) {
+	require := require.New(t)
+	q := NewRunQueue(1)
+
+	childDone := make(chan struct{})
+	parentDone := make(chan struct{})
+	release := make(chan struct{})
+	require.False(q.Enqueue("parent", "", 0, func() {
+		defer close(parentDone)
+		// The parent holds the only slot, but its child must still be able to run.
+		require.False(q.Enqueue("child", "parent", 0, func() {
+			defer close(childDone)
+			// While the child runs, it holds the slot the parent gave up.
+			require.True(q.Enqueue("other", "", 0, func() {}))
+			<-release
+		}))
+		<-childDone
+	}))
+
+	close(release)
+	<-parentDone
+	require.Eventually(func() bool { return q.Len() == 0 }, time.Second, 10*time.Millisecond)
+}
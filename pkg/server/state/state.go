@@ -9,6 +9,7 @@ import (
 	"os"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	libapi "github.com/airplanedev/cli/pkg/api"
@@ -17,6 +18,7 @@ import (
 	"github.com/airplanedev/cli/pkg/deploy/bundlediscover"
 	"github.com/airplanedev/cli/pkg/deploy/discover"
 	"github.com/airplanedev/cli/pkg/dev"
+	"github.com/airplanedev/cli/pkg/dev/history"
 	"github.com/airplanedev/cli/pkg/devconf"
 	"github.com/airplanedev/cli/pkg/flags/flagsiface"
 	libparams "github.com/airplanedev/cli/pkg/parameters"
@@ -49,14 +51,24 @@ type State struct {
 	// Directory from which tasks and views were discovered
 	Dir      string
 	Executor dev.Executor
+	// SandboxedRuns is set if shell and Python tasks should be executed in a sandboxed native process.
+	SandboxedRuns bool
 
 	Runs *runsStore
+	// RunQueue limits how many local runs execute at once.
+	RunQueue *RunQueue
+	// RetentionPolicy controls when completed runs and their temporary directories are cleaned up.
+	RetentionPolicy RetentionPolicy
+	// History persists runs so that they outlive the server. If nil, runs are only kept in memory.
+	History *history.Store
 	// Mapping from task slug to task config
 	TaskConfigs Store[string, discover.TaskConfig]
 	// Mapping from view slug to view config
 	ViewConfigs Store[string, discover.ViewConfig]
 	// AppCondition holds info about task such as errors to display and time registered
 	AppCondition Store[string, AppCondition]
+	// OptionsCache maps environments and parameter options sources to the options they returned.
+	OptionsCache Store[string, []libapi.ConstraintOption]
 
 	Discoverer       *discover.Discoverer
 	BundleDiscoverer *bundlediscover.Discoverer
@@ -85,6 +97,10 @@ type State struct {
 
 	ServerStatus      status.ServerStatus
 	ServerStatusMutex sync.Mutex
+
+	// ready is set once the initial discovery has completed and the services the server depends on are
+	// up. Unlike ServerStatus, it is not reset when tasks and views are re-discovered.
+	ready atomic.Bool
 }
 
 type AppCondition struct {
@@ -124,8 +140,10 @@ func New(devToken *string) (*State, error) {
 	return &State{
 		EnvCache:     NewStore[string, libapi.Env](nil),
 		Runs:         NewRunStore(),
+		RunQueue:     NewRunQueue(0),
 		TaskConfigs:  NewStore[string, discover.TaskConfig](nil),
 		AppCondition: NewStore[string, AppCondition](nil),
+		OptionsCache: NewStore[string, []libapi.ConstraintOption](nil),
 		ViewConfigs:  NewStore[string, discover.ViewConfig](nil),
 		Debouncers:   NewStore[string, func()](nil),
 		ViteContexts: viteContextCache,
@@ -254,6 +272,8 @@ func sanitizeInputs(run *dev.LocalRun) error {
 
 func (s *State) AddRun(taskSlug string, runID string, run dev.LocalRun) {
 	s.Runs.Add(taskSlug, runID, run)
+	run.RunID = runID
+	s.saveRun(run)
 }
 
 func (s *State) GetRun(ctx context.Context, runID string) (dev.LocalRun, error) {
@@ -290,7 +310,15 @@ func (s *State) GetRunDescendants(ctx context.Context, runID string) ([]dev.Loca
 }
 
 func (s *State) UpdateRun(runID string, f func(run *dev.LocalRun) error) (dev.LocalRun, error) {
-	return s.Runs.Update(runID, f)
+	run, err := s.Runs.Update(runID, f)
+	if err != nil {
+		return dev.LocalRun{}, err
+	}
+	// Runs are saved once more when they complete, along with their outputs and logs.
+	if run.Status.IsTerminal() {
+		s.saveRun(run)
+	}
+	return run, nil
 }
 
 func (s *State) GetRunHistory(ctx context.Context, taskID string) ([]dev.LocalRun, error) {
@@ -342,11 +370,17 @@ func (store *runsStore) Add(taskSlug string, runID string, run dev.LocalRun) {
 }
 
 func (store *runsStore) Get(runID string) (dev.LocalRun, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
 	res, ok := store.runs[runID]
 	return res, ok
 }
 
 func (store *runsStore) GetDescendants(runID string) []dev.LocalRun {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
 	descendants := []dev.LocalRun{}
 	descIDs, ok := store.runDescendants[runID]
 	if !ok {
@@ -375,6 +409,9 @@ func (store *runsStore) Update(runID string, f func(run *dev.LocalRun) error) (d
 }
 
 func (store *runsStore) GetRunHistory(taskID string) []dev.LocalRun {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
 	runIDs := store.runHistory[taskID]
 	res := make([]dev.LocalRun, len(runIDs))
 	for i, runID := range runIDs {
@@ -384,6 +421,16 @@ func (store *runsStore) GetRunHistory(taskID string) []dev.LocalRun {
 	return res
 }
 
+// MarkReady marks the server as ready to serve requests.
+func (s *State) MarkReady() {
+	s.ready.Store(true)
+}
+
+// IsReady returns whether the server has been marked as ready.
+func (s *State) IsReady() bool {
+	return s.ready.Load()
+}
+
 func (s *State) SetServerStatus(status status.ServerStatus) {
 	s.ServerStatusMutex.Lock()
 	defer s.ServerStatusMutex.Unlock()
@@ -10,7 +10,13 @@ import (
 const NO_FALLBACK_ENVIRONMENT = "no-fallback-environment"
 
 func GetEffectiveEnvSlugFromRequest(s *state.State, r *http.Request) *string {
-	envSlug := r.Header.Get("X-Airplane-Studio-Fallback-Env-Slug")
+	return EffectiveEnvSlug(s, r.Header.Get("X-Airplane-Studio-Fallback-Env-Slug"))
+}
+
+// EffectiveEnvSlug returns the fallback environment that a client asked for, which defaults to the
+// environment that the server started with. Clients opt out of falling back with
+// NO_FALLBACK_ENVIRONMENT.
+func EffectiveEnvSlug(s *state.State, envSlug string) *string {
 	if envSlug == "" {
 		return s.InitialRemoteEnvSlug
 	} else if envSlug == NO_FALLBACK_ENVIRONMENT {
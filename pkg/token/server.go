@@ -31,6 +31,9 @@ import (
 //	}
 type Server struct {
 	tokens          chan string
+	codes           chan string
+	errs            chan error
+	state           string
 	lstn            net.Listener
 	ctx             context.Context
 	loginSuccessURL string
@@ -40,6 +43,20 @@ type Server struct {
 
 // NewServer returns a new server.
 func NewServer(ctx context.Context, loginSuccessURL string) (*Server, error) {
+	return newServer(ctx, loginSuccessURL, "")
+}
+
+// NewSSOServer returns a new server that receives the authorization code of an SSO login,
+// instead of a token. Callbacks are only accepted if they include state, which protects against
+// codes that were not requested by this login attempt.
+func NewSSOServer(ctx context.Context, loginSuccessURL, state string) (*Server, error) {
+	if state == "" {
+		return nil, errors.New("state is required")
+	}
+	return newServer(ctx, loginSuccessURL, state)
+}
+
+func newServer(ctx context.Context, loginSuccessURL, state string) (*Server, error) {
 	l, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		return nil, errors.Wrap(err, "bind")
@@ -47,6 +64,9 @@ func NewServer(ctx context.Context, loginSuccessURL string) (*Server, error) {
 
 	srv := &Server{
 		tokens:          make(chan string, 1),
+		codes:           make(chan string, 1),
+		errs:            make(chan error, 1),
+		state:           state,
 		lstn:            l,
 		ctx:             ctx,
 		loginSuccessURL: loginSuccessURL,
@@ -69,8 +89,24 @@ func (srv *Server) Token() <-chan string {
 	return srv.tokens
 }
 
+// Code returns the SSO authorization code channel.
+func (srv *Server) Code() <-chan string {
+	return srv.codes
+}
+
+// Err returns the channel of SSO callbacks that failed the login, e.g. because the identity
+// provider returned an error.
+func (srv *Server) Err() <-chan error {
+	return srv.errs
+}
+
 // ServeHTTP implementation.
 func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if srv.state != "" {
+		srv.serveSSOCallback(w, r)
+		return
+	}
+
 	select {
 	case <-r.Context().Done():
 	case srv.tokens <- r.URL.Query().Get("token"):
@@ -78,6 +114,47 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (srv *Server) serveSSOCallback(w http.ResponseWriter, r *http.Request) {
+	// Browsers may request other paths, e.g. /favicon.ico, which aren't callbacks.
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	if q.Get("state") != srv.state {
+		http.Error(w, "Invalid login state. Please run airplane login again.", http.StatusBadRequest)
+		srv.fail(errors.New("SSO login returned an invalid state"))
+		return
+	}
+	if msg := q.Get("error"); msg != "" {
+		if desc := q.Get("error_description"); desc != "" {
+			msg += ": " + desc
+		}
+		http.Error(w, "Login failed: "+msg, http.StatusUnauthorized)
+		srv.fail(errors.Errorf("SSO login failed: %s", msg))
+		return
+	}
+	if q.Get("code") == "" {
+		http.Error(w, "Login failed: no authorization code. Please run airplane login again.", http.StatusBadRequest)
+		srv.fail(errors.New("SSO login returned no authorization code"))
+		return
+	}
+
+	select {
+	case <-r.Context().Done():
+	case srv.codes <- q.Get("code"):
+		http.Redirect(w, r, srv.loginSuccessURL, http.StatusSeeOther)
+	}
+}
+
+// fail reports err to the login, unless a failure was already reported.
+func (srv *Server) fail(err error) {
+	select {
+	case srv.errs <- err:
+	default:
+	}
+}
+
 // Start starts the server.
 func (srv *Server) start() {
 	srv.wg.Add(1)
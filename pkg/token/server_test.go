@@ -21,12 +21,53 @@ func TestServer(t *testing.T) {
 		assert.Equal("token", <-srv.Token())
 		assert.NoError(srv.Close())
 	})
+
+	t.Run("receive an sso code", func(t *testing.T) {
+		var ctx = context.Background()
+		var assert = require.New(t)
+
+		srv, err := NewSSOServer(ctx, "https://fake.airplane.so/cli/success", "state123")
+		assert.NoError(err)
+
+		assert.Equal(http.StatusNotFound, get(ctx, t, srv.URL()+"/favicon.ico"))
+		assert.Equal(http.StatusSeeOther, get(ctx, t, srv.URL()+"?code=code&state=state123"))
+
+		assert.Equal("code", <-srv.Code())
+		assert.NoError(srv.Close())
+	})
+
+	t.Run("fail an sso login", func(t *testing.T) {
+		for _, test := range []struct {
+			query  string
+			status int
+			err    string
+		}{
+			{query: "?code=bad&state=other", status: http.StatusBadRequest, err: "invalid state"},
+			{query: "?error=access_denied&error_description=Not+allowed&state=state123", status: http.StatusUnauthorized, err: "SSO login failed: access_denied: Not allowed"},
+			{query: "?state=state123", status: http.StatusBadRequest, err: "no authorization code"},
+		} {
+			var ctx = context.Background()
+			var assert = require.New(t)
+
+			srv, err := NewSSOServer(ctx, "https://fake.airplane.so/cli/success", "state123")
+			assert.NoError(err)
+
+			assert.Equal(test.status, get(ctx, t, srv.URL()+test.query))
+			assert.ErrorContains(<-srv.Err(), test.err)
+			assert.NoError(srv.Close())
+		}
+	})
 }
 
 func send(ctx context.Context, t testing.TB, url, token string) {
 	t.Helper()
+	get(ctx, t, url+"?token="+token)
+}
+
+func get(ctx context.Context, t testing.TB, url string) int {
+	t.Helper()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url+"?token="+token, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		t.Fatalf("new request: %s", err)
 	}
@@ -42,4 +83,5 @@ func send(ctx context.Context, t testing.TB, url, token string) {
 		t.Fatalf("do request: %s", err)
 	}
 	resp.Body.Close()
+	return resp.StatusCode
 }
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -93,6 +94,47 @@ func (tv *TimeValue) String() string {
 	return t.Format(time.RFC3339)
 }
 
+// DurationValue is a pflag.Value that parses a time.Duration. In addition to the units accepted by
+// time.ParseDuration, it accepts whole days and weeks, e.g. "90d" or "2w".
+//
+// DurationValue's are alias types of time.Duration. You can convert safely via `time.Duration(dv)`.
+type DurationValue time.Duration
+
+var _ pflag.Value = new(DurationValue)
+
+func (dv *DurationValue) Set(s string) error {
+	for suffix, unit := range map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return errors.Errorf(`expected a duration formatted as "90d", "2w" or "12h" but got %q`, s)
+			}
+			*dv = DurationValue(time.Duration(v) * unit)
+			return nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.Errorf(`expected a duration formatted as "90d", "2w" or "12h" but got %q`, s)
+	}
+	*dv = DurationValue(d)
+	return nil
+}
+
+func (dv *DurationValue) Type() string {
+	return "duration"
+}
+
+func (dv *DurationValue) String() string {
+	if dv == nil || *dv == 0 {
+		return ""
+	}
+	return time.Duration(*dv).String()
+}
+
 // NewlineFileValue is a pflag.Value that can be used to parse a file with newline
 // separated values.
 type NewlineFileValue []string
@@ -79,17 +79,23 @@ func CopyFromGithubPath(p prompts.Prompter, l logger.Logger, gitPath string) err
 	}
 	defer closer.Close()
 
-	fileInfo, err := os.Stat(tempPath)
+	return CopyFromPath(p, l, tempPath)
+}
+
+// CopyFromPath copies a directory or file into the working directory. Dependencies of copied
+// directories with a package.json are installed.
+func CopyFromPath(p prompts.Prompter, l logger.Logger, srcPath string) error {
+	fileInfo, err := os.Stat(srcPath)
 	if err != nil {
 		return errors.New("path to directory or file not found")
 	}
 
 	if fileInfo.IsDir() {
-		directory := filepath.Base(tempPath)
+		directory := filepath.Base(srcPath)
 		if err := CreateDirectory(p, l, directory); err != nil {
 			return err
 		}
-		if err := CopyDirectoryContents(l, tempPath, directory); err != nil {
+		if err := CopyDirectoryContents(l, srcPath, directory); err != nil {
 			return err
 		}
 
@@ -129,7 +135,7 @@ func CopyFromGithubPath(p prompts.Prompter, l logger.Logger, gitPath string) err
 		if err != nil {
 			return err
 		}
-		fileName := filepath.Base(tempPath)
+		fileName := filepath.Base(srcPath)
 
 		if fsx.Exists(fileName) {
 			question := fmt.Sprintf("File %s already exists. Do you want to overwrite it?", fileName)
@@ -140,7 +146,7 @@ func CopyFromGithubPath(p prompts.Prompter, l logger.Logger, gitPath string) err
 				return errors.New("canceled airplane views init")
 			}
 		}
-		if err := CopyFile(l, tempPath, cwd); err != nil {
+		if err := CopyFile(l, srcPath, cwd); err != nil {
 			return err
 		}
 	}
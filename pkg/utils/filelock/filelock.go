@@ -0,0 +1,141 @@
+// filelock provides advisory file locks and atomic writes for files that may be written by
+// several airplane commands at once, such as the user config.
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrTimeout is returned when a lock couldn't be acquired before Timeout.
+var ErrTimeout = errors.New("timed out acquiring file lock")
+
+// Timeout is how long a lock held by another process is waited for.
+var Timeout = 10 * time.Second
+
+const (
+	minBackoff = 10 * time.Millisecond
+	maxBackoff = 500 * time.Millisecond
+)
+
+// ReadFile reads the file at path. Writes by WriteFile and Update replace the file atomically, so
+// it never sees a partial write and doesn't need the lock.
+func ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// WriteFile replaces the contents of the file at path with data while holding its lock, creating
+// it with perm if it doesn't exist.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return Update(path, perm, func([]byte) ([]byte, error) {
+		return data, nil
+	})
+}
+
+// Update replaces the contents of the file at path with the result of calling update on its
+// current contents, which are empty if the file doesn't exist yet. The lock is held from reading
+// the file until it has been replaced, so that concurrent updates aren't lost.
+//
+// The lock is taken on a hidden `.<name>.lock` file next to the file, which is never removed since
+// removing it would race with other processes taking the lock. The new contents are written to a
+// temporary file in the same directory, synced, and renamed over the file, so that a crash never
+// leaves it partially written. If path is a symlink, the file it points to is replaced. If update
+// returns an error, the file is left unchanged, and isn't created if it doesn't exist.
+func Update(path string, perm os.FileMode, update func(buf []byte) ([]byte, error)) (rerr error) {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrapf(err, "resolving %s", path)
+	}
+
+	f, err := os.OpenFile(lockPath(path), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(err, "opening lock file")
+	}
+	defer f.Close()
+	if err := lock(f, path); err != nil {
+		return err
+	}
+	defer func() {
+		if err := unlock(f); err != nil && rerr == nil {
+			rerr = errors.Wrapf(err, "unlocking %s", path)
+		}
+	}()
+
+	buf, err := os.ReadFile(path)
+	if err == nil {
+		// Keep the permissions of an existing file.
+		if info, err := os.Stat(path); err == nil {
+			perm = info.Mode().Perm()
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrapf(err, "reading %s", path)
+	}
+	data, err := update(buf)
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, data, perm)
+}
+
+// lockPath returns the path of the file that the lock of path is taken on.
+func lockPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".lock")
+}
+
+// replaceFile atomically replaces the file at path with data by renaming a temporary file in the
+// same directory over it.
+func replaceFile(path string, data []byte, perm os.FileMode) (rerr error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "creating temporary file")
+	}
+	defer func() {
+		if rerr != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	if _, err := f.Write(data); err != nil {
+		return errors.Wrap(err, "writing temporary file")
+	}
+	if err := f.Chmod(perm); err != nil {
+		return errors.Wrap(err, "chmod temporary file")
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Wrap(err, "syncing temporary file")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "closing temporary file")
+	}
+	return errors.Wrapf(os.Rename(f.Name(), path), "replacing %s", path)
+}
+
+// lock acquires an exclusive lock on f, retrying with backoff while another process holds it. If
+// the lock can't be acquired within Timeout, an error wrapping ErrTimeout is returned.
+//
+// The lock is released automatically if the process exits without unlocking it.
+func lock(f *os.File, path string) error {
+	deadline := time.Now().Add(Timeout)
+	backoff := minBackoff
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			return errors.Wrapf(err, "locking %s", path)
+		}
+		if ok {
+			return nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return errors.Wrapf(ErrTimeout, "%s is in use by another airplane command (waited %s); retry once it has finished", path, Timeout)
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFile(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(WriteFile(path, []byte("hello"), 0600))
+	require.NoError(WriteFile(path, []byte("hi"), 0600))
+
+	buf, err := ReadFile(path)
+	require.NoError(err)
+	require.Equal("hi", string(buf))
+
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// Only the lock file is left behind, not temporary files.
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	require.ElementsMatch([]string{".config.lock", "config"}, names)
+
+	// The permissions of an existing file are kept.
+	require.NoError(os.Chmod(path, 0640))
+	require.NoError(WriteFile(path, []byte("hey"), 0600))
+	info, err = os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0640), info.Mode().Perm())
+
+	_, err = ReadFile(filepath.Join(t.TempDir(), "missing"))
+	require.True(errors.Is(err, os.ErrNotExist))
+}
+
+func TestWriteFileSymlink(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "config")
+	require.NoError(os.MkdirAll(filepath.Dir(target), 0755))
+	require.NoError(os.WriteFile(target, []byte("hello"), 0600))
+	link := filepath.Join(dir, "config")
+	require.NoError(os.Symlink(target, link))
+
+	require.NoError(WriteFile(link, []byte("hi"), 0600))
+
+	info, err := os.Lstat(link)
+	require.NoError(err)
+	require.NotZero(info.Mode() & os.ModeSymlink)
+	buf, err := os.ReadFile(target)
+	require.NoError(err)
+	require.Equal("hi", string(buf))
+}
+
+func TestUpdateConcurrent(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "counter")
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- Update(path, 0600, func(buf []byte) ([]byte, error) {
+				n := 0
+				if len(buf) > 0 {
+					var err error
+					if n, err = strconv.Atoi(string(buf)); err != nil {
+						return nil, err
+					}
+				}
+				return []byte(strconv.Itoa(n + 1)), nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(err)
+	}
+
+	// Every update saw the previous one's write.
+	buf, err := ReadFile(path)
+	require.NoError(err)
+	require.Equal("10", string(buf))
+}
+
+func TestUpdateError(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(WriteFile(path, []byte("hello"), 0600))
+	require.EqualError(Update(path, 0600, func([]byte) ([]byte, error) {
+		return nil, errors.New("invalid config")
+	}), "invalid config")
+
+	buf, err := ReadFile(path)
+	require.NoError(err)
+	require.Equal("hello", string(buf))
+
+	// A file that doesn't exist isn't created.
+	missing := filepath.Join(t.TempDir(), "missing")
+	require.Error(Update(missing, 0600, func([]byte) ([]byte, error) {
+		return nil, errors.New("invalid config")
+	}))
+	_, err = os.Stat(missing)
+	require.True(errors.Is(err, os.ErrNotExist))
+}
+
+func TestLockTimeout(t *testing.T) {
+	require := require.New(t)
+
+	defer func(d time.Duration) { Timeout = d }(Timeout)
+	Timeout = 100 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(WriteFile(path, []byte("hello"), 0600))
+	f, err := os.Open(lockPath(path))
+	require.NoError(err)
+	defer f.Close()
+	require.NoError(lock(f, path))
+
+	err = WriteFile(path, []byte("hi"), 0600)
+	require.True(errors.Is(err, ErrTimeout), "expected a timeout, got %v", err)
+	require.ErrorContains(err, "in use by another airplane command")
+
+	require.NoError(unlock(f))
+	require.NoError(WriteFile(path, []byte("hi"), 0600))
+}
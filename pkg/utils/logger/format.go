@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+)
+
+// Format is the format that logs are written in.
+type Format string
+
+const (
+	// FormatText writes human-readable logs.
+	FormatText Format = "text"
+	// FormatJSON writes each log as a line of JSON, so that it can be parsed by e.g. CI systems.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses a format name: text or json.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return FormatText, errors.Errorf("unknown log format %q: expected text or json", s)
+	}
+}
+
+var (
+	formatMu  sync.Mutex
+	jsonLogs  bool
+	jsonOut   io.Writer = os.Stderr
+	colorsOff bool
+)
+
+// SetFormat sets the format of logs. Colors are disabled while logs are written as JSON.
+func SetFormat(f Format) {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	if f == FormatJSON && !jsonLogs {
+		colorsOff = color.NoColor
+		color.NoColor = true
+	} else if f != FormatJSON && jsonLogs {
+		color.NoColor = colorsOff
+	}
+	jsonLogs = f == FormatJSON
+}
+
+func jsonEnabled() bool {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	return jsonLogs
+}
+
+type jsonLog struct {
+	Level     string    `json:"level"`
+	Timestamp time.Time `json:"timestamp"`
+	Module    string    `json:"module,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// writeJSON writes a log as a line of JSON. Empty messages, which are used to space out text
+// logs, are dropped.
+func writeJSON(level, module, msg string) {
+	msg = Redact(strings.TrimSpace(msg))
+	if msg == "" {
+		return
+	}
+	buf, err := json.Marshal(jsonLog{
+		Level:     level,
+		Timestamp: time.Now().UTC(),
+		Module:    module,
+		Message:   msg,
+	})
+	if err != nil {
+		return
+	}
+
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	fmt.Fprintln(jsonOut, string(buf))
+}
+
+// sprintf only applies formatting if there are args, so that msg isn't treated like a format
+// string otherwise.
+func sprintf(msg string, args ...interface{}) string {
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFormat(t *testing.T) {
+	require := require.New(t)
+	var buf bytes.Buffer
+	jsonOut = &buf
+	SetFormat(FormatJSON)
+	t.Cleanup(func() {
+		SetFormat(FormatText)
+		SetLevel(LevelInfo)
+		require.NoError(SetModuleLevels(""))
+		jsonOut = os.Stderr
+	})
+	SetLevel(LevelInfo)
+	require.NoError(SetModuleLevels("discover"))
+
+	Log("Deploying %s", Bold("hello"))
+	Log("")
+	Step("Built %d task(s)", 2)
+	Warning("slow build")
+	Error("deploy failed: %s", "timeout")
+	Debug("not emitted")
+	DebugFor(ModuleDiscover, "found %s", "hello.task.yaml")
+
+	var logs []jsonLog
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var l jsonLog
+		require.NoError(json.Unmarshal([]byte(line), &l))
+		require.False(l.Timestamp.IsZero())
+		logs = append(logs, jsonLog{Level: l.Level, Module: l.Module, Message: l.Message})
+	}
+	require.Equal([]jsonLog{
+		{Level: "info", Message: "Deploying hello"},
+		{Level: "info", Message: "Built 2 task(s)"},
+		{Level: "warning", Message: "slow build"},
+		{Level: "error", Message: "deploy failed: timeout"},
+		{Level: "debug", Module: ModuleDiscover, Message: "found hello.task.yaml"},
+	}, logs)
+}
+
+func TestParseFormat(t *testing.T) {
+	require := require.New(t)
+
+	f, err := ParseFormat("JSON")
+	require.NoError(err)
+	require.Equal(FormatJSON, f)
+
+	f, err = ParseFormat("")
+	require.NoError(err)
+	require.Equal(FormatText, f)
+
+	_, err = ParseFormat("xml")
+	require.ErrorContains(err, "unknown log format")
+}
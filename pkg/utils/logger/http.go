@@ -6,7 +6,7 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 )
 
-// HTTPLogger is a wrapper around the default debug logger that
+// HTTPLogger is a wrapper around the api module's debug logger that
 // can be used for HTTP requests via `hashicorp/go-retryablehttp`.
 type HTTPLogger struct{}
 
@@ -14,22 +14,22 @@ var _ retryablehttp.LeveledLogger = HTTPLogger{}
 
 func (_ HTTPLogger) Error(msg string, keyAndValues ...interface{}) {
 	format, args := toFormatAndArgs(msg, keyAndValues)
-	Debug(format, args...)
+	DebugFor(ModuleAPI, format, args...)
 }
 
 func (_ HTTPLogger) Info(msg string, keyAndValues ...interface{}) {
 	format, args := toFormatAndArgs(msg, keyAndValues)
-	Debug(format, args...)
+	DebugFor(ModuleAPI, format, args...)
 }
 
 func (_ HTTPLogger) Debug(msg string, keyAndValues ...interface{}) {
 	format, args := toFormatAndArgs(msg, keyAndValues)
-	Debug(format, args...)
+	DebugFor(ModuleAPI, format, args...)
 }
 
 func (_ HTTPLogger) Warn(msg string, keyAndValues ...interface{}) {
 	format, args := toFormatAndArgs(msg, keyAndValues)
-	Debug(format, args...)
+	DebugFor(ModuleAPI, format, args...)
 }
 
 func toFormatAndArgs(msg string, keyAndValues []interface{}) (string, []interface{}) {
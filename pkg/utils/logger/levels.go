@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Level is the verbosity of debug logging.
+type Level int
+
+const (
+	// LevelInfo only emits regular output.
+	LevelInfo Level = iota
+	// LevelDebug additionally emits debug logs.
+	LevelDebug
+	// LevelTrace additionally emits the most detailed logs, such as HTTP request and response bodies.
+	LevelTrace
+)
+
+// Modules that debug logs can be enabled for individually with SetModuleLevels.
+const (
+	ModuleAPI      = "api"
+	ModuleDiscover = "discover"
+)
+
+var levelNames = map[string]Level{
+	"info":  LevelInfo,
+	"debug": LevelDebug,
+	"trace": LevelTrace,
+}
+
+func (l Level) String() string {
+	for name, level := range levelNames {
+		if level == l {
+			return name
+		}
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// ParseLevel parses a level name: info, debug, or trace.
+func ParseLevel(s string) (Level, error) {
+	level, ok := levelNames[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return LevelInfo, errors.Errorf("unknown log level %q: expected info, debug, or trace", s)
+	}
+	return level, nil
+}
+
+var (
+	levelsMu     sync.RWMutex
+	defaultLevel = LevelInfo
+	moduleLevels = map[string]Level{}
+)
+
+// SetLevel sets the level of modules that do not have a level of their own.
+func SetLevel(level Level) {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	defaultLevel = level
+	EnableDebug = level >= LevelDebug
+}
+
+// SetModuleLevels sets the level of individual modules from a comma-separated list of modules,
+// e.g. "api,discover=trace". Modules without an explicit level are set to debug.
+func SetModuleLevels(spec string) error {
+	levels := map[string]Level{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		module, levelName, hasLevel := strings.Cut(entry, "=")
+		level := LevelDebug
+		if hasLevel {
+			var err error
+			if level, err = ParseLevel(levelName); err != nil {
+				return errors.Wrapf(err, "parsing level of module %s", module)
+			}
+		}
+		levels[strings.TrimSpace(module)] = level
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	moduleLevels = levels
+	return nil
+}
+
+// Enabled returns whether logs of the given module are emitted at the given level.
+func Enabled(module string, level Level) bool {
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	if l, ok := moduleLevels[module]; ok {
+		return level <= l
+	}
+	return level <= defaultLevel
+}
+
+// DebugFor is like Debug, but the log is only emitted if debug logs are enabled for module.
+func DebugFor(module string, msg string, args ...interface{}) {
+	if Enabled(module, LevelDebug) {
+		writeDebug(module, msg, args...)
+	}
+}
+
+// TraceFor is like DebugFor, but the log is only emitted if trace logs are enabled for module.
+func TraceFor(module string, msg string, args ...interface{}) {
+	if Enabled(module, LevelTrace) {
+		writeDebug(module, msg, args...)
+	}
+}
+
+func writeDebug(module string, msg string, args ...interface{}) {
+	msgf := msg
+	if len(args) > 0 {
+		msgf = fmt.Sprintf(msg, args...)
+	}
+	msgf = Redact(msgf)
+	if jsonEnabled() {
+		writeJSON("debug", module, msgf)
+		return
+	}
+
+	tag := "debug"
+	if module != "" {
+		tag += ":" + module
+	}
+	debugPrefix := "[" + Blue(tag) + "] "
+	msgf = debugPrefix + strings.Join(strings.Split(msgf, "\n"), "\n"+debugPrefix)
+
+	fmt.Fprint(os.Stderr, msgf+"\n")
+}
@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevels(t *testing.T) {
+	require := require.New(t)
+	t.Cleanup(func() {
+		SetLevel(LevelInfo)
+		require.NoError(SetModuleLevels(""))
+	})
+
+	SetLevel(LevelDebug)
+	require.NoError(SetModuleLevels("api=info, discover=trace,runtime"))
+	require.True(EnableDebug)
+	require.True(Enabled("", LevelDebug))
+	require.False(Enabled("", LevelTrace))
+	require.False(Enabled(ModuleAPI, LevelDebug))
+	require.True(Enabled(ModuleDiscover, LevelTrace))
+	require.True(Enabled("runtime", LevelDebug))
+	require.False(Enabled("runtime", LevelTrace))
+
+	SetLevel(LevelInfo)
+	require.False(EnableDebug)
+	require.True(Enabled(ModuleDiscover, LevelDebug))
+
+	require.ErrorContains(SetModuleLevels("api=verbose"), "unknown log level")
+}
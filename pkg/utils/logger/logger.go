@@ -12,7 +12,7 @@ import (
 )
 
 var (
-	// EnableDebug determines if debug logs are emitted.
+	// EnableDebug determines if debug logs are emitted. It is set by SetLevel.
 	EnableDebug bool
 )
 
@@ -133,38 +133,55 @@ func (l *StdErrLogger) SuggestSteps(title string, steps ...string) {
 // Log writes a log message to stderr, followed by a newline. Printf-style
 // formatting is applied to msg using args.
 func Log(msg string, args ...interface{}) {
-	if len(args) == 0 {
-		// Use Fprint if no args - avoids treating msg like a format string
-		fmt.Fprint(os.Stderr, msg+"\n")
-	} else {
-		fmt.Fprintf(os.Stderr, msg+"\n", args...)
+	if jsonEnabled() {
+		writeJSON("info", "", sprintf(msg, args...))
+		return
 	}
+	fmt.Fprint(os.Stderr, Redact(sprintf(msg, args...))+"\n")
 }
 
 // Step prints a step that was performed.
 func Step(msg string, args ...interface{}) {
+	if jsonEnabled() {
+		writeJSON("info", "", sprintf(msg, args...))
+		return
+	}
 	Log("- "+msg, args...)
 }
 
 // Suggest suggests a command with title and args.
 func Suggest(title, command string, args ...interface{}) {
+	if jsonEnabled() {
+		writeJSON("info", "", title+" "+sprintf(command, args...))
+		return
+	}
 	Log("\n"+Gray(title)+"\n  "+command, args...)
 }
 
 func SuggestSteps(title string, steps ...string) {
-	if len(steps) > 0 {
+	if len(steps) > 0 && jsonEnabled() {
+		writeJSON("info", "", title+" "+strings.Join(steps, "; "))
+	} else if len(steps) > 0 {
 		Log("\n" + Gray(title) + "\n- " + strings.Join(steps, "\n- "))
 	}
 }
 
 // Error logs an error message.
 func Error(msg string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, Red("Error: ")+msg+"\n", args...)
+	if jsonEnabled() {
+		writeJSON("error", "", fmt.Sprintf(msg, args...))
+		return
+	}
+	fmt.Fprint(os.Stderr, Red("Error: ")+Redact(fmt.Sprintf(msg, args...))+"\n")
 }
 
 // Warning logs a warning message.
 func Warning(msg string, args ...interface{}) {
-	fmt.Fprint(os.Stderr, Yellow("[warning] "+msg+"\n", args...))
+	if jsonEnabled() {
+		writeJSON("warning", "", fmt.Sprintf(msg, args...))
+		return
+	}
+	fmt.Fprint(os.Stderr, Yellow("%s", "[warning] "+Redact(fmt.Sprintf(msg, args...))+"\n"))
 }
 
 // Debug writes a log message to stderr, followed by a newline, if the CLI
@@ -174,16 +191,7 @@ func Debug(msg string, args ...interface{}) {
 	if !EnableDebug {
 		return
 	}
-
-	msgf := msg
-	if len(args) > 0 {
-		msgf = fmt.Sprintf(msg, args...)
-	}
-
-	debugPrefix := "[" + Blue("debug") + "] "
-	msgf = debugPrefix + strings.Join(strings.Split(msgf, "\n"), "\n"+debugPrefix)
-
-	fmt.Fprint(os.Stderr, msgf+"\n")
+	writeDebug("", msg, args...)
 }
 
 type Loader interface {
@@ -203,7 +211,7 @@ type NoopLoader struct {
 }
 
 func NewLoader() Loader {
-	if !term.IsTerminal(int(os.Stderr.Fd())) {
+	if jsonEnabled() || !term.IsTerminal(int(os.Stderr.Fd())) {
 		return &NoopLoader{}
 	}
 	return &SpinnerLoader{
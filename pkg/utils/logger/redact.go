@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces secrets in logs.
+const Redacted = "[REDACTED]"
+
+// minSecretLength is the length of the shortest value that is registered as a secret. Shorter
+// values, e.g. "true", would redact unrelated parts of logs.
+const minSecretLength = 6
+
+var (
+	secretsMu sync.RWMutex
+	secrets   = map[string]bool{}
+)
+
+// credentialPatterns match common credentials. If a pattern has a group, only the text after the
+// group is redacted, so that logs still show e.g. which header was set.
+var credentialPatterns = []*regexp.Regexp{
+	// Authorization headers, e.g. "Authorization: Bearer <token>".
+	regexp.MustCompile(`(?i)(\b(?:bearer|basic)\s+)[A-Za-z0-9\-._~+/]+=*`),
+	// Keys and values, e.g. "api_key=<key>", "X-Airplane-Token: <token>" or `"password": "<password>"`.
+	regexp.MustCompile(`(?i)((?:api[_-]?key|token|secret|password|passwd)["']?\s*[:=]\s*["']?)[^\s"',&;]+`),
+	// AWS access key IDs.
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	// JWTs.
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	// PEM-encoded private keys.
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`),
+}
+
+// RegisterSecrets registers values that are redacted from logs, such as API tokens and the values
+// of secret config vars.
+func RegisterSecrets(values ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, v := range values {
+		if v = strings.TrimSpace(v); len(v) >= minSecretLength {
+			secrets[v] = true
+		}
+	}
+}
+
+// Redact replaces registered secrets and common credential patterns in s.
+func Redact(s string) string {
+	if s == "" {
+		return s
+	}
+
+	secretsMu.RLock()
+	values := make([]string, 0, len(secrets))
+	for v := range secrets {
+		values = append(values, v)
+	}
+	secretsMu.RUnlock()
+	// Longer secrets are replaced first, in case one secret contains another.
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, Redacted)
+	}
+
+	for _, p := range credentialPatterns {
+		if p.NumSubexp() > 0 {
+			s = p.ReplaceAllString(s, "${1}"+Redacted)
+		} else {
+			s = p.ReplaceAllString(s, Redacted)
+		}
+	}
+	return s
+}
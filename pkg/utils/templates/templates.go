@@ -0,0 +1,49 @@
+// templates implements the `{{name}}` variables of templated fields that are resolved at deploy
+// time, e.g. `image: registry/app:{{git.shortSHA}}`. Names are namespaced, like the variables of
+// JS templates, e.g. git.sha or task.slug.
+package templates
+
+import (
+	"regexp"
+	"strings"
+)
+
+var varRegex = regexp.MustCompile(`{{\s*([A-Za-z0-9_.]+)\s*}}`)
+
+// IsTemplated returns whether s references any template variables.
+func IsTemplated(s string) bool {
+	return varRegex.MatchString(s)
+}
+
+// Expand replaces the template variables referenced in s with the values returned by lookup. It
+// returns the first error returned by lookup.
+func Expand(s string, lookup func(name string) (string, error)) (string, error) {
+	var err error
+	expanded := varRegex.ReplaceAllStringFunc(s, func(match string) string {
+		value, lerr := lookup(varRegex.FindStringSubmatch(match)[1])
+		if lerr != nil && err == nil {
+			err = lerr
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
+
+// Matches returns whether s could have been produced by expanding template, e.g.
+// "registry/app:abc1234" matches "registry/app:{{git.shortSHA}}".
+func Matches(template, s string) bool {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, loc := range varRegex.FindAllStringIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		pattern.WriteString(".+")
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]))
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String()).MatchString(s)
+}
@@ -53,7 +53,10 @@ func CheckLatest(ctx context.Context, userConfig *conf.UserConfig) bool {
 			Updated: time.Now().UTC(),
 		}
 		//nolint: staticcheck
-		if err := conf.WriteDefaultUserConfig(*userConfig); err != nil {
+		if err := conf.UpdateDefaultUserConfig(func(cfg *conf.UserConfig) error {
+			cfg.LatestVersion = userConfig.LatestVersion
+			return nil
+		}); err != nil {
 			// Do nothing
 		}
 	}
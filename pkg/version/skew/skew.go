@@ -0,0 +1,162 @@
+// skew checks that the versions of the SDKs that tasks depend on are ones that the builders of
+// this CLI support, so that skew is reported up front rather than failing deep inside a build.
+package skew
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/utils/fsx"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/pkg/errors"
+)
+
+// SDKVersion is the version of an SDK that a task root depends on.
+type SDKVersion struct {
+	Type    buildtypes.BuildType
+	Root    string
+	Version string
+}
+
+// Warning describes an incompatibility between versions.
+type Warning struct {
+	Message string
+	// Fix describes how to resolve the incompatibility.
+	Fix string
+}
+
+// CheckSDKs checks the versions of SDKs against the versions that the builders of this CLI support.
+func CheckSDKs(sdks []SDKVersion) []Warning {
+	var warnings []Warning
+	for _, sdk := range sdks {
+		pkg := buildtypes.SDKPackages[sdk.Type]
+		v, err := semver.NewVersion(sdk.Version)
+		if err != nil {
+			logger.Debug("Unable to parse version %q of %s in %s: %v", sdk.Version, pkg, sdk.Root, err)
+			continue
+		}
+		c, ok := buildtypes.SDKConstraints[sdk.Type]
+		if !ok {
+			continue
+		}
+		constraint, err := semver.NewConstraint(c)
+		if err != nil {
+			logger.Debug("Invalid constraint %q: %v", c, err)
+			continue
+		}
+		if !constraint.Check(v) {
+			warnings = append(warnings, Warning{
+				Message: fmt.Sprintf("%s depends on %s %s, which this version of the CLI can't build tasks with (expected %s).", sdk.Root, pkg, v, c),
+				Fix:     fmt.Sprintf("Change the version of %s to match %s, or use a version of the CLI that supports %s %s.", pkg, c, pkg, v),
+			})
+		}
+	}
+	return warnings
+}
+
+// Report logs each warning. If strict is set and there are any warnings, an error is returned.
+func Report(l logger.Logger, warnings []Warning, strict bool) error {
+	for _, w := range warnings {
+		l.Warning("%s %s", w.Message, w.Fix)
+	}
+	if strict && len(warnings) > 0 {
+		if len(warnings) == 1 {
+			return errors.New("found an incompatible version (--strict-versions)")
+		}
+		return errors.Errorf("found %d incompatible versions (--strict-versions)", len(warnings))
+	}
+	return nil
+}
+
+// FindSDKVersions returns the version of the SDK that each of the given task roots depends on.
+// Roots whose SDK version can't be determined are skipped.
+func FindSDKVersions(roots map[string]buildtypes.BuildType) []SDKVersion {
+	var sdks []SDKVersion
+	for root, t := range roots {
+		var version string
+		switch t {
+		case buildtypes.NodeBuildType:
+			version = nodeSDKVersion(root)
+		case buildtypes.PythonBuildType:
+			version = pythonSDKVersion(root)
+		}
+		if version != "" {
+			sdks = append(sdks, SDKVersion{Type: t, Root: root, Version: version})
+		}
+	}
+	sort.Slice(sdks, func(i, j int) bool {
+		return sdks[i].Root < sdks[j].Root
+	})
+	return sdks
+}
+
+// nodeSDKVersion returns the installed version of the Node SDK, or the version that the
+// package.json of root depends on if it isn't installed.
+func nodeSDKVersion(root string) string {
+	pkg := buildtypes.SDKPackages[buildtypes.NodeBuildType]
+	if dir, ok := fsx.Find(root, filepath.Join("node_modules", pkg, "package.json")); ok {
+		var installed struct {
+			Version string `json:"version"`
+		}
+		buf, err := os.ReadFile(filepath.Join(dir, "node_modules", pkg, "package.json"))
+		if err == nil && json.Unmarshal(buf, &installed) == nil && installed.Version != "" {
+			return installed.Version
+		}
+	}
+
+	buf, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var packageJSON struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(buf, &packageJSON); err != nil {
+		return ""
+	}
+	dep, ok := packageJSON.Dependencies[pkg]
+	if !ok {
+		dep = packageJSON.DevDependencies[pkg]
+	}
+	return minVersion(dep)
+}
+
+// pythonRequirement matches the SDK in requirements.txt and pyproject.toml files, e.g.
+// `airplanesdk==0.3.26`, `"airplanesdk>=0.3"` or `airplanesdk = "^0.3.26"`.
+var pythonRequirement = regexp.MustCompile(`(?m)^\s*"?airplanesdk(?:\[[^\]]*\])?"?\s*(?:=\s*")?\s*((?:[=~<>!^]=?|===)?\s*[0-9][0-9A-Za-z.+\-]*)`)
+
+// pythonSDKVersion returns the version of the Python SDK that root depends on.
+func pythonSDKVersion(root string) string {
+	for _, name := range []string{"requirements.txt", "pyproject.toml"} {
+		buf, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			continue
+		}
+		if m := pythonRequirement.FindSubmatch(buf); m != nil {
+			return minVersion(string(m[1]))
+		}
+	}
+	return ""
+}
+
+// minVersion returns the oldest version that a dependency specifier allows, e.g. 0.2.54 for
+// ^0.2.54, or an empty string if it doesn't have one, e.g. for *.
+func minVersion(spec string) string {
+	spec = strings.TrimSpace(spec)
+	spec = strings.TrimLeft(spec, "=~^>v ")
+	if spec == "" || spec[0] < '0' || spec[0] > '9' {
+		return ""
+	}
+	if i := strings.IndexAny(spec, " ,<|"); i >= 0 {
+		spec = spec[:i]
+	}
+	return spec
+}
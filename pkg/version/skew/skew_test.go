@@ -0,0 +1,63 @@
+package skew
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
+	"github.com/airplanedev/cli/pkg/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSDKs(t *testing.T) {
+	require := require.New(t)
+	warnings := CheckSDKs([]SDKVersion{
+		{Type: buildtypes.NodeBuildType, Root: "old", Version: "0.1.9"},
+		{Type: buildtypes.NodeBuildType, Root: "new", Version: "0.2.54"},
+		{Type: buildtypes.PythonBuildType, Root: "py", Version: "0.4.1"},
+		{Type: buildtypes.PythonBuildType, Root: "ok", Version: "0.3.26"},
+		{Type: buildtypes.PythonBuildType, Root: "invalid", Version: "latest"},
+	})
+	require.Len(warnings, 2)
+	require.Contains(warnings[0].Message, "old depends on airplane 0.1.9, which this version of the CLI can't build tasks with")
+	require.Contains(warnings[1].Message, "py depends on airplanesdk 0.4.1, which this version of the CLI can't build tasks with")
+
+	require.NoError(Report(&logger.MockLogger{}, warnings, false))
+	require.EqualError(Report(&logger.MockLogger{}, warnings, true), "found 2 incompatible versions (--strict-versions)")
+	require.NoError(Report(&logger.MockLogger{}, nil, true))
+}
+
+func TestFindSDKVersions(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"installed/package.json":                       `{"dependencies": {"airplane": "^0.2.0"}}`,
+		"installed/node_modules/airplane/package.json": `{"name": "airplane", "version": "0.2.54"}`,
+		"declared/package.json":                        `{"devDependencies": {"airplane": "~0.2.31"}}`,
+		"unpinned/package.json":                        `{"dependencies": {"airplane": "*"}}`,
+		"requirements/requirements.txt":                "requests==2.31.0\nairplanesdk[extra] ~= 0.3.26\n",
+		"poetry/pyproject.toml":                        "[tool.poetry.dependencies]\nairplanesdk = \"^0.3.14\"\n",
+		"project/pyproject.toml":                       "[project]\ndependencies = [\n  \"airplanesdk>=0.3.2,<0.4\",\n]\n",
+		"none/requirements.txt":                        "requests\n",
+	} {
+		p := filepath.Join(dir, name)
+		require.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(os.WriteFile(p, []byte(content), 0644))
+	}
+
+	roots := map[string]buildtypes.BuildType{}
+	for _, name := range []string{"installed", "declared", "unpinned"} {
+		roots[filepath.Join(dir, name)] = buildtypes.NodeBuildType
+	}
+	for _, name := range []string{"requirements", "poetry", "project", "none"} {
+		roots[filepath.Join(dir, name)] = buildtypes.PythonBuildType
+	}
+	require.Equal([]SDKVersion{
+		{Type: buildtypes.NodeBuildType, Root: filepath.Join(dir, "declared"), Version: "0.2.31"},
+		{Type: buildtypes.NodeBuildType, Root: filepath.Join(dir, "installed"), Version: "0.2.54"},
+		{Type: buildtypes.PythonBuildType, Root: filepath.Join(dir, "poetry"), Version: "0.3.14"},
+		{Type: buildtypes.PythonBuildType, Root: filepath.Join(dir, "project"), Version: "0.3.2"},
+		{Type: buildtypes.PythonBuildType, Root: filepath.Join(dir, "requirements"), Version: "0.3.26"},
+	}, FindSDKVersions(roots))
+}
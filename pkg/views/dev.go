@@ -19,6 +19,7 @@ import (
 
 	api "github.com/airplanedev/cli/pkg/api/cliapi"
 	"github.com/airplanedev/cli/pkg/build/node"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	libviews "github.com/airplanedev/cli/pkg/build/views"
 	"github.com/airplanedev/cli/pkg/server/network"
 	"github.com/airplanedev/cli/pkg/utils"
@@ -142,7 +143,7 @@ func Dev(ctx context.Context, v viewdir.ViewDirectoryInterface, viteOpts ViteOpt
 	}
 
 	// Create vite config.
-	if err := createViteConfig(root, airplaneViewDir, viteOpts.Port, viteOpts.Token); err != nil {
+	if err := createViteConfig(root, airplaneViewDir, viteOpts.Port, viteOpts.Token, v.Bundler()); err != nil {
 		return nil, "", nil, errors.Wrap(err, "creating vite config")
 	}
 
@@ -285,7 +286,7 @@ func ensureAirplaneViewDir(airplaneViewDir string, l logger.Logger) error {
 	return nil
 }
 
-func createViteConfig(root string, airplaneViewDir string, port int, token *string) error {
+func createViteConfig(root string, airplaneViewDir string, port int, token *string, bundler buildtypes.ViewBundler) error {
 	viteConfigStr, err := libviews.ViteConfigString(libviews.ViteConfigOpts{
 		Root:  root,
 		Port:  port,
@@ -295,8 +296,35 @@ func createViteConfig(root string, airplaneViewDir string, port int, token *stri
 	if err != nil {
 		return errors.Wrap(err, "loading vite.config.ts value")
 	}
+
 	viteConfigPath := filepath.Join(airplaneViewDir, "vite.config.ts")
-	if err := os.WriteFile(viteConfigPath, []byte(viteConfigStr), 0644); err != nil {
+	airplaneViteConfigPath := filepath.Join(airplaneViewDir, "airplane.vite.config.ts")
+	if bundler != buildtypes.ViewBundlerVite {
+		// Remove the config that a previous run with the vite bundler may have left behind.
+		if err := os.RemoveAll(airplaneViteConfigPath); err != nil {
+			return errors.Wrap(err, "removing airplane.vite.config.ts")
+		}
+		if err := os.WriteFile(viteConfigPath, []byte(viteConfigStr), 0644); err != nil {
+			return errors.Wrap(err, "writing vite.config.ts")
+		}
+		return nil
+	}
+
+	// With the vite bundler, vite.config.ts merges the config managed by Airplane into the project's own
+	// config, so that the project's plugins are used. The dev server still proxies requests through the
+	// base path of Airplane's config.
+	projectConfig, err := libviews.FindProjectViteConfig(root)
+	if err != nil {
+		return err
+	}
+	projectViteConfigStr, err := libviews.ProjectViteConfigString("./airplane.vite.config", "../"+projectConfig)
+	if err != nil {
+		return errors.Wrap(err, "loading vite.config.ts value")
+	}
+	if err := os.WriteFile(airplaneViteConfigPath, []byte(viteConfigStr), 0644); err != nil {
+		return errors.Wrap(err, "writing airplane.vite.config.ts")
+	}
+	if err := os.WriteFile(viteConfigPath, []byte(projectViteConfigStr), 0644); err != nil {
 		return errors.Wrap(err, "writing vite.config.ts")
 	}
 	return nil
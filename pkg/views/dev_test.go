@@ -3,9 +3,11 @@ package views
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/airplanedev/cli/pkg/build/node"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -167,3 +169,32 @@ func TestAddDepsToPackageJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateViteConfig(t *testing.T) {
+	r := require.New(t)
+	root := t.TempDir()
+	airplaneViewDir := filepath.Join(root, ".airplane-view")
+	r.NoError(os.Mkdir(airplaneViewDir, 0755))
+	token := "tkn"
+
+	// The vite bundler requires the project to have its own config.
+	err := createViteConfig(root, airplaneViewDir, 5173, &token, buildtypes.ViewBundlerVite)
+	r.ErrorContains(err, "requires a Vite config")
+
+	r.NoError(os.WriteFile(filepath.Join(root, "vite.config.ts"), []byte("export default {};"), 0644))
+	r.NoError(createViteConfig(root, airplaneViewDir, 5173, &token, buildtypes.ViewBundlerVite))
+	config, err := os.ReadFile(filepath.Join(airplaneViewDir, "vite.config.ts"))
+	r.NoError(err)
+	r.Contains(string(config), `import airplaneConfig from "./airplane.vite.config";`)
+	r.Contains(string(config), `import projectConfig from "../vite.config.ts";`)
+	airplaneConfig, err := os.ReadFile(filepath.Join(airplaneViewDir, "airplane.vite.config.ts"))
+	r.NoError(err)
+	r.Contains(string(airplaneConfig), `base: "/dev/views/5173/tkn/",`)
+
+	// Switching back to the default bundler removes the merged config.
+	r.NoError(createViteConfig(root, airplaneViewDir, 5173, &token, ""))
+	config, err = os.ReadFile(filepath.Join(airplaneViewDir, "vite.config.ts"))
+	r.NoError(err)
+	r.Contains(string(config), `base: "/dev/views/5173/tkn/",`)
+	r.NoFileExists(filepath.Join(airplaneViewDir, "airplane.vite.config.ts"))
+}
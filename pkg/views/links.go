@@ -0,0 +1,131 @@
+package views
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/pkg/errors"
+)
+
+// LinksPath returns the path of the file that the link helpers of def are generated into. The file
+// is placed next to the view's definition file or, for views without one, next to its entrypoint.
+func LinksPath(def definitions.ViewDefinition) string {
+	dir := filepath.Dir(def.Entrypoint)
+	if def.DefnFilePath != "" {
+		dir = filepath.Dir(def.DefnFilePath)
+	}
+	return filepath.Join(dir, def.Slug+".links.ts")
+}
+
+// GenerateLinks generates a TypeScript module with a typed helper for each of the links of def.
+// Each helper takes the values that the view passes and returns the slug and parameters of the
+// linked task, e.g. to pass to useTaskMutation. taskParams holds the parameters of the linked
+// tasks, keyed by task slug.
+func GenerateLinks(def definitions.ViewDefinition, taskParams map[string]libapi.Parameters) ([]byte, error) {
+	names := make([]string, 0, len(def.Links))
+	for name := range def.Links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by airplane views codegen. DO NOT EDIT.\n")
+	fmt.Fprintf(&buf, "// Source: the links of view %s.\n", def.Slug)
+
+	for _, name := range names {
+		link := def.Links[name]
+		params, ok := taskParams[link.Task]
+		if !ok {
+			return nil, errors.Errorf("missing parameters of task %s linked as %s", link.Task, name)
+		}
+
+		args := make([]string, 0, len(link.Params))
+		for arg := range link.Params {
+			args = append(args, arg)
+		}
+		sort.Strings(args)
+
+		typeName := pascalCase(name) + "Params"
+		fmt.Fprintf(&buf, "\nexport type %s = {\n", typeName)
+		for _, arg := range args {
+			p, ok := findParameter(params, link.Params[arg])
+			if !ok {
+				return nil, errors.Errorf("task %s has no parameter %s", link.Task, link.Params[arg])
+			}
+			optional := ""
+			if !isRequired(p) {
+				optional = "?"
+			}
+			fmt.Fprintf(&buf, "  %s%s: %s;\n", arg, optional, tsType(p.Type))
+		}
+		fmt.Fprintf(&buf, "};\n")
+
+		fmt.Fprintf(&buf, "\nexport const %s = (params: %s) => ({\n", name, typeName)
+		fmt.Fprintf(&buf, "  slug: %s,\n", strconv.Quote(link.Task))
+		fmt.Fprintf(&buf, "  params: {\n")
+		for _, arg := range args {
+			fmt.Fprintf(&buf, "    %s: params.%s,\n", tsKey(link.Params[arg]), arg)
+		}
+		fmt.Fprintf(&buf, "  },\n")
+		fmt.Fprintf(&buf, "});\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func findParameter(params libapi.Parameters, slug string) (libapi.Parameter, bool) {
+	for _, p := range params {
+		if p.Slug == slug {
+			return p, true
+		}
+	}
+	return libapi.Parameter{}, false
+}
+
+// isRequired returns whether a value has to be passed for p. Booleans are never required, since
+// unset booleans are false.
+func isRequired(p libapi.Parameter) bool {
+	return !p.Constraints.Optional && p.Default == nil && p.Type != libapi.TypeBoolean
+}
+
+func tsType(t libapi.Type) string {
+	switch t {
+	case libapi.TypeString, libapi.TypeDate, libapi.TypeDatetime, libapi.TypeConfigVar, libapi.TypeUpload:
+		return "string"
+	case libapi.TypeBoolean:
+		return "boolean"
+	case libapi.TypeInteger, libapi.TypeFloat:
+		return "number"
+	default:
+		return "unknown"
+	}
+}
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey returns key as an object key, quoting it if it isn't a valid identifier.
+func tsKey(key string) string {
+	if identifierRegex.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// pascalCase converts an identifier such as "refund_order" or "refundOrder" into "RefundOrder".
+func pascalCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package views
+
+import (
+	"path/filepath"
+	"testing"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateLinks(t *testing.T) {
+	require := require.New(t)
+
+	def := definitions.ViewDefinition{
+		Slug:         "support",
+		DefnFilePath: filepath.Join("views", "support", "support.view.yaml"),
+		Links: map[string]definitions.ViewLinkDefinition{
+			"issue_refund": {
+				Task: "issue_refund",
+				Params: map[string]string{
+					"email":  "customer_email",
+					"amount": "amount",
+					"notify": "notify",
+				},
+			},
+		},
+	}
+	buf, err := GenerateLinks(def, map[string]libapi.Parameters{
+		"issue_refund": {
+			{Slug: "customer_email", Type: libapi.TypeString},
+			{Slug: "amount", Type: libapi.TypeFloat, Default: 10},
+			{Slug: "notify", Type: libapi.TypeBoolean},
+		},
+	})
+	require.NoError(err)
+	require.Equal(`// Code generated by airplane views codegen. DO NOT EDIT.
+// Source: the links of view support.
+
+export type IssueRefundParams = {
+  amount?: number;
+  email: string;
+  notify?: boolean;
+};
+
+export const issue_refund = (params: IssueRefundParams) => ({
+  slug: "issue_refund",
+  params: {
+    amount: params.amount,
+    customer_email: params.email,
+    notify: params.notify,
+  },
+});
+`, string(buf))
+	require.Equal(filepath.Join("views", "support", "support.links.ts"), LinksPath(def))
+
+	_, err = GenerateLinks(def, map[string]libapi.Parameters{})
+	require.Error(err)
+}
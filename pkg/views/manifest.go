@@ -0,0 +1,189 @@
+package views
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+)
+
+// BuildAssetManifest lists the files within dir, along with their sizes and hashes. Paths are relative
+// to dir and always use forward slashes.
+func BuildAssetManifest(dir string) ([]api.ViewAsset, error) {
+	var assets []api.ViewAsset
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return errors.Wrap(err, "getting relative path")
+		}
+		size, hash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		assets = append(assets, api.ViewAsset{
+			Path:   filepath.ToSlash(rel),
+			Size:   size,
+			SHA256: hash,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading assets in %s", dir)
+	}
+
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].Path < assets[j].Path
+	})
+	return assets, nil
+}
+
+func hashFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "opening %s", path)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "hashing %s", path)
+	}
+	return size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// AssetChange is an asset whose contents differ between two manifests.
+type AssetChange struct {
+	Before api.ViewAsset
+	After  api.ViewAsset
+}
+
+// SizeDelta returns how many bytes the asset grew by.
+func (c AssetChange) SizeDelta() int64 {
+	return c.After.Size - c.Before.Size
+}
+
+// AssetDiff is the difference between two asset manifests.
+type AssetDiff struct {
+	Added   []api.ViewAsset
+	Removed []api.ViewAsset
+	Changed []AssetChange
+}
+
+// IsEmpty returns true if both manifests contain the same assets.
+func (d AssetDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Warnings flags changes that are likely unintentional: assets that were removed and assets that were
+// added or grew by more than maxAddedSize bytes. If maxAddedSize is zero, size changes are not flagged.
+func (d AssetDiff) Warnings(maxAddedSize int64) []string {
+	var warnings []string
+	for _, a := range d.Added {
+		if maxAddedSize > 0 && a.Size > maxAddedSize {
+			warnings = append(warnings, fmt.Sprintf("%s is a new asset of %s", a.Path, FormatSize(a.Size)))
+		}
+	}
+	for _, c := range d.Changed {
+		if maxAddedSize > 0 && c.SizeDelta() > maxAddedSize {
+			warnings = append(warnings, fmt.Sprintf("%s grew by %s", c.After.Path, FormatSize(c.SizeDelta())))
+		}
+	}
+	for _, a := range d.Removed {
+		warnings = append(warnings, fmt.Sprintf("%s was removed", a.Path))
+	}
+	return warnings
+}
+
+// Vite appends a content hash to the names of the files it generates, e.g. index-4f3a2b1c.js.
+var assetHashRegex = regexp.MustCompile(`-[A-Za-z0-9_-]{8}(\.[^./]+)$`)
+
+// assetKey strips content hashes from an asset's path so that the same asset can be matched
+// across builds.
+func assetKey(path string) string {
+	return assetHashRegex.ReplaceAllString(path, "$1")
+}
+
+// DiffAssets compares the assets of two builds. Assets are matched by path, falling back to matching
+// paths without content hashes, as long as that match is unambiguous.
+func DiffAssets(before, after []api.ViewAsset) AssetDiff {
+	beforeByPath := map[string]api.ViewAsset{}
+	for _, a := range before {
+		beforeByPath[a.Path] = a
+	}
+
+	var diff AssetDiff
+	var unmatchedAfter []api.ViewAsset
+	for _, a := range after {
+		b, ok := beforeByPath[a.Path]
+		if !ok {
+			unmatchedAfter = append(unmatchedAfter, a)
+			continue
+		}
+		delete(beforeByPath, a.Path)
+		if b.SHA256 != a.SHA256 || b.Size != a.Size {
+			diff.Changed = append(diff.Changed, AssetChange{Before: b, After: a})
+		}
+	}
+
+	// Match the remaining assets on their paths without content hashes.
+	beforeByKey := map[string][]api.ViewAsset{}
+	for _, b := range beforeByPath {
+		key := assetKey(b.Path)
+		beforeByKey[key] = append(beforeByKey[key], b)
+	}
+	afterByKey := map[string][]api.ViewAsset{}
+	for _, a := range unmatchedAfter {
+		key := assetKey(a.Path)
+		afterByKey[key] = append(afterByKey[key], a)
+	}
+	for key, as := range afterByKey {
+		bs := beforeByKey[key]
+		if len(as) == 1 && len(bs) == 1 {
+			if bs[0].SHA256 != as[0].SHA256 || bs[0].Size != as[0].Size {
+				diff.Changed = append(diff.Changed, AssetChange{Before: bs[0], After: as[0]})
+			}
+			delete(beforeByKey, key)
+			continue
+		}
+		diff.Added = append(diff.Added, as...)
+	}
+	for _, bs := range beforeByKey {
+		diff.Removed = append(diff.Removed, bs...)
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool {
+		return diff.Added[i].Path < diff.Added[j].Path
+	})
+	sort.Slice(diff.Removed, func(i, j int) bool {
+		return diff.Removed[i].Path < diff.Removed[j].Path
+	})
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].After.Path < diff.Changed[j].After.Path
+	})
+	return diff
+}
+
+// FormatSize formats a size in bytes for display.
+func FormatSize(size int64) string {
+	if size < 0 {
+		return "-" + humanize.Bytes(uint64(-size))
+	}
+	return humanize.Bytes(uint64(size))
+}
@@ -0,0 +1,68 @@
+package views
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAssetManifest(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(dir, "assets"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "assets", "index-4f3a2b1c.js"), []byte("hello"), 0644))
+
+	assets, err := BuildAssetManifest(dir)
+	require.NoError(err)
+	require.Equal([]api.ViewAsset{
+		{
+			Path:   "assets/index-4f3a2b1c.js",
+			Size:   5,
+			SHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		},
+		{
+			Path:   "index.html",
+			Size:   13,
+			SHA256: "b633a587c652d02386c4f16f8c6f6aab7352d97f16367c3c40576214372dd628",
+		},
+	}, assets)
+}
+
+func TestDiffAssets(t *testing.T) {
+	require := require.New(t)
+
+	before := []api.ViewAsset{
+		{Path: "index.html", Size: 100, SHA256: "a"},
+		{Path: "assets/index-4f3a2b1c.js", Size: 1000, SHA256: "b"},
+		{Path: "assets/vendor-11111111.js", Size: 2000, SHA256: "c"},
+		{Path: "assets/logo.svg", Size: 300, SHA256: "d"},
+	}
+	after := []api.ViewAsset{
+		{Path: "index.html", Size: 100, SHA256: "a"},
+		{Path: "assets/index-9e8d7c6b.js", Size: 5000, SHA256: "e"},
+		{Path: "assets/vendor-11111111.js", Size: 2000, SHA256: "c"},
+		{Path: "assets/chart-22222222.js", Size: 800, SHA256: "f"},
+	}
+
+	diff := DiffAssets(before, after)
+	require.Equal([]api.ViewAsset{{Path: "assets/chart-22222222.js", Size: 800, SHA256: "f"}}, diff.Added)
+	require.Equal([]api.ViewAsset{{Path: "assets/logo.svg", Size: 300, SHA256: "d"}}, diff.Removed)
+	require.Equal([]AssetChange{{
+		Before: api.ViewAsset{Path: "assets/index-4f3a2b1c.js", Size: 1000, SHA256: "b"},
+		After:  api.ViewAsset{Path: "assets/index-9e8d7c6b.js", Size: 5000, SHA256: "e"},
+	}}, diff.Changed)
+	require.False(diff.IsEmpty())
+
+	require.Equal([]string{
+		"assets/chart-22222222.js is a new asset of 800 B",
+		"assets/index-9e8d7c6b.js grew by 4.0 kB",
+		"assets/logo.svg was removed",
+	}, diff.Warnings(500))
+	require.Equal([]string{"assets/logo.svg was removed"}, diff.Warnings(0))
+
+	require.True(DiffAssets(before, before).IsEmpty())
+}
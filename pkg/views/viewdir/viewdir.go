@@ -7,6 +7,7 @@ import (
 
 	libapi "github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/api/cliapi"
+	buildtypes "github.com/airplanedev/cli/pkg/build/types"
 	"github.com/airplanedev/cli/pkg/definitions"
 	"github.com/airplanedev/cli/pkg/deploy/discover"
 	"github.com/airplanedev/cli/pkg/utils/logger"
@@ -17,12 +18,14 @@ type ViewDirectoryInterface interface {
 	Root() string
 	EntrypointPath() string
 	Slug() string
+	Bundler() buildtypes.ViewBundler
 }
 
 type ViewDirectory struct {
 	root           string
 	entrypointPath string
 	slug           string
+	bundler        buildtypes.ViewBundler
 }
 
 func (this *ViewDirectory) Root() string {
@@ -36,6 +39,10 @@ func (this *ViewDirectory) Slug() string {
 	return this.slug
 }
 
+func (this *ViewDirectory) Bundler() buildtypes.ViewBundler {
+	return this.bundler
+}
+
 func missingViewHandler(ctx context.Context, defn definitions.ViewDefinition) (*libapi.View, error) {
 	// TODO(zhan): generate view?
 	return &libapi.View{
@@ -44,8 +51,8 @@ func missingViewHandler(ctx context.Context, defn definitions.ViewDefinition) (*
 	}, nil
 }
 
-func NewViewDirectory(ctx context.Context, client api.APIClient, searchPath string, envSlug string) (ViewDirectory, error) {
-	d := &discover.Discoverer{
+func newDiscoverer(client api.APIClient, envSlug string) *discover.Discoverer {
+	return &discover.Discoverer{
 		ViewDiscoverers: []discover.ViewDiscoverer{
 			&discover.ViewDefnDiscoverer{
 				Client:             client,
@@ -61,6 +68,10 @@ func NewViewDirectory(ctx context.Context, client api.APIClient, searchPath stri
 		EnvSlug: envSlug,
 		Client:  client,
 	}
+}
+
+func NewViewDirectory(ctx context.Context, client api.APIClient, searchPath string, envSlug string) (ViewDirectory, error) {
+	d := newDiscoverer(client, envSlug)
 
 	// If pointing towards a view definition file, we just use that file as the view to run.
 	if definitions.IsViewDef(searchPath) {
@@ -108,6 +119,23 @@ func NewViewDirectory(ctx context.Context, client api.APIClient, searchPath stri
 	return vd, nil
 }
 
+// NewViewDirectoryFromSlug discovers the views within searchPath and returns the one with the given slug.
+func NewViewDirectoryFromSlug(ctx context.Context, client api.APIClient, searchPath string, slug string, envSlug string) (ViewDirectory, error) {
+	d := newDiscoverer(client, envSlug)
+
+	_, viewConfigs, err := d.Discover(ctx, searchPath)
+	if err != nil {
+		return ViewDirectory{}, errors.Wrap(err, "discovering view configs")
+	}
+	for _, vc := range viewConfigs {
+		if vc.Def.Slug == slug {
+			return NewViewDirectoryFromViewConfig(vc)
+		}
+	}
+
+	return ViewDirectory{}, errors.Errorf("no view with slug %q found in %s", slug, searchPath)
+}
+
 // NewViewDirectoryFromViewConfig constructs a new ViewDirectory from a ViewConfig.
 func NewViewDirectoryFromViewConfig(vc discover.ViewConfig) (ViewDirectory, error) {
 	absRoot, err := filepath.Abs(vc.Root)
@@ -119,5 +147,6 @@ func NewViewDirectoryFromViewConfig(vc discover.ViewConfig) (ViewDirectory, erro
 		root:           absRoot,
 		entrypointPath: vc.Def.Entrypoint,
 		slug:           vc.Def.Slug,
+		bundler:        vc.Def.Bundler,
 	}, nil
 }
@@ -0,0 +1,267 @@
+// viewexport exports views to files and imports them into another team, f.e. to copy the views of
+// a sandbox team into a production team.
+package viewexport
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/pkg/errors"
+)
+
+// FileSuffix is the suffix of exported view files.
+const FileSuffix = ".view.json"
+
+// View is the exported form of a view.
+type View struct {
+	Slug        string            `json:"slug"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	EnvVars     map[string]string `json:"envVars,omitempty"`
+
+	RequireExplicitPermissions bool         `json:"requireExplicitPermissions"`
+	Permissions                []Permission `json:"permissions,omitempty"`
+}
+
+// Permission is the exported form of a permission. Users and groups are referenced by email and
+// name, since their IDs differ between teams.
+type Permission struct {
+	Action libapi.Action `json:"action,omitempty"`
+	RoleID libapi.RoleID `json:"roleID,omitempty"`
+	User   string        `json:"user,omitempty"`
+	Group  string        `json:"group,omitempty"`
+}
+
+// Export fetches the views with the given slugs. If no slugs are given, every view is exported,
+// except archived views.
+func Export(ctx context.Context, client api.APIClient, slugs []string) ([]View, error) {
+	var views []libapi.View
+	if len(slugs) == 0 {
+		resp, err := client.ListViews(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing views")
+		}
+		for _, v := range resp.Views {
+			if v.ArchivedAt == nil && !v.IsLocal {
+				views = append(views, v)
+			}
+		}
+	} else {
+		for _, slug := range slugs {
+			v, err := client.GetView(ctx, libapi.GetViewRequest{Slug: slug})
+			if err != nil {
+				return nil, err
+			}
+			views = append(views, v)
+		}
+	}
+
+	d, err := newDirectory(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]View, 0, len(views))
+	for _, v := range views {
+		perms, err := client.GetViewPermissions(ctx, v.Slug)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting permissions of view %s", v.Slug)
+		}
+		export := View{
+			Slug:                       v.Slug,
+			Name:                       v.Name,
+			Description:                v.Description,
+			EnvVars:                    v.EnvVars,
+			RequireExplicitPermissions: perms.RequireExplicitPermissions,
+		}
+		for _, p := range perms.Permissions {
+			ep := Permission{Action: p.Action, RoleID: p.RoleID}
+			if p.SubUserID != nil {
+				email, ok := d.userEmails[*p.SubUserID]
+				if !ok {
+					return nil, errors.Errorf("view %s: unknown user %s", v.Slug, *p.SubUserID)
+				}
+				ep.User = email
+			}
+			if p.SubGroupID != nil {
+				name, ok := d.groupNames[*p.SubGroupID]
+				if !ok {
+					return nil, errors.Errorf("view %s: unknown group %s", v.Slug, *p.SubGroupID)
+				}
+				ep.Group = name
+			}
+			export.Permissions = append(export.Permissions, ep)
+		}
+		exports = append(exports, export)
+	}
+	sort.Slice(exports, func(i, j int) bool {
+		return exports[i].Slug < exports[j].Slug
+	})
+	return exports, nil
+}
+
+// Write writes each view to its own file in dir. Files are only readable by the current user,
+// since environment variables may contain secrets.
+func Write(dir string, views []View) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrap(err, "creating export directory")
+	}
+	for _, v := range views {
+		buf, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return errors.Wrapf(err, "marshaling view %s", v.Slug)
+		}
+		if err := os.WriteFile(filepath.Join(dir, v.Slug+FileSuffix), append(buf, '\n'), 0600); err != nil {
+			return errors.Wrapf(err, "writing view %s", v.Slug)
+		}
+	}
+	return nil
+}
+
+// Read reads the views exported to dir.
+func Read(dir string) ([]View, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading export directory")
+	}
+	var views []View
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), FileSuffix) {
+			continue
+		}
+		buf, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", e.Name())
+		}
+		var v View
+		if err := json.Unmarshal(buf, &v); err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", e.Name())
+		}
+		if v.Slug == "" {
+			return nil, errors.Errorf("%s: missing slug", e.Name())
+		}
+		views = append(views, v)
+	}
+	return views, nil
+}
+
+// ImportResult describes the changes made by Import.
+type ImportResult struct {
+	Created []string
+	Updated []string
+	// Skipped describes the permissions that were not imported, since their user or group doesn't
+	// exist in the team.
+	Skipped []string
+}
+
+// Import creates or updates each of the views. Permissions are replaced, except for permissions
+// whose user or group doesn't exist in the team, which are skipped.
+func Import(ctx context.Context, client api.APIClient, views []View) (ImportResult, error) {
+	d, err := newDirectory(ctx, client)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	var res ImportResult
+	for _, v := range views {
+		envVars := libapi.EnvVars{}
+		for k, val := range v.EnvVars {
+			envVars[k] = libapi.EnvVarValue{Value: pointers.String(val)}
+		}
+
+		_, err := client.UpdateView(ctx, api.UpdateViewRequest{
+			Slug:        v.Slug,
+			Name:        v.Name,
+			Description: v.Description,
+			EnvVars:     envVars,
+		})
+		var merr *libapi.ViewMissingError
+		if errors.As(err, &merr) {
+			if _, err := client.CreateView(ctx, libapi.CreateViewRequest{
+				Slug:        v.Slug,
+				Name:        v.Name,
+				Description: v.Description,
+				EnvVars:     envVars,
+			}); err != nil {
+				return res, errors.Wrapf(err, "creating view %s", v.Slug)
+			}
+			res.Created = append(res.Created, v.Slug)
+		} else if err != nil {
+			return res, errors.Wrapf(err, "updating view %s", v.Slug)
+		} else {
+			res.Updated = append(res.Updated, v.Slug)
+		}
+
+		var perms libapi.Permissions
+		for _, p := range v.Permissions {
+			perm := libapi.Permission{Action: p.Action, RoleID: p.RoleID}
+			if p.User != "" {
+				id, ok := d.userIDs[p.User]
+				if !ok {
+					res.Skipped = append(res.Skipped, v.Slug+": user "+p.User)
+					continue
+				}
+				perm.SubUserID = pointers.String(id)
+			}
+			if p.Group != "" {
+				id, ok := d.groupIDs[p.Group]
+				if !ok {
+					res.Skipped = append(res.Skipped, v.Slug+": group "+p.Group)
+					continue
+				}
+				perm.SubGroupID = pointers.String(id)
+			}
+			perms = append(perms, perm)
+		}
+		if err := client.UpdateViewPermissions(ctx, api.UpdateViewPermissionsRequest{
+			Slug:                       v.Slug,
+			RequireExplicitPermissions: v.RequireExplicitPermissions,
+			Permissions:                perms,
+		}); err != nil {
+			return res, errors.Wrapf(err, "updating permissions of view %s", v.Slug)
+		}
+	}
+	return res, nil
+}
+
+// directory maps the users and groups of a team between their IDs and their portable names.
+type directory struct {
+	userEmails map[string]string
+	userIDs    map[string]string
+	groupNames map[string]string
+	groupIDs   map[string]string
+}
+
+func newDirectory(ctx context.Context, client api.APIClient) (directory, error) {
+	users, err := client.ListUsers(ctx)
+	if err != nil {
+		return directory{}, errors.Wrap(err, "listing users")
+	}
+	groups, err := client.ListGroups(ctx)
+	if err != nil {
+		return directory{}, errors.Wrap(err, "listing groups")
+	}
+
+	d := directory{
+		userEmails: map[string]string{},
+		userIDs:    map[string]string{},
+		groupNames: map[string]string{},
+		groupIDs:   map[string]string{},
+	}
+	for _, u := range users.Users {
+		d.userEmails[u.ID] = u.Email
+		d.userIDs[u.Email] = u.ID
+	}
+	for _, g := range groups.Groups {
+		d.groupNames[g.ID] = g.Name
+		d.groupIDs[g.Name] = g.ID
+	}
+	return d, nil
+}
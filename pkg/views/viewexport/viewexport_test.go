@@ -0,0 +1,101 @@
+package viewexport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libapi "github.com/airplanedev/cli/pkg/api"
+	api "github.com/airplanedev/cli/pkg/api/cliapi"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	source := &api.MockClient{
+		Users: map[string]api.User{
+			"usr_alice": {ID: "usr_alice", Email: "alice@example.com"},
+			"usr_bob":   {ID: "usr_bob", Email: "bob@example.com"},
+		},
+		Groups: []api.Group{{ID: "grp_ops", Name: "Ops"}},
+		Views: map[string]libapi.View{
+			"dashboard": {
+				ID:          "view_1",
+				Slug:        "dashboard",
+				Name:        "Dashboard",
+				Description: "The dashboard",
+				EnvVars:     map[string]string{"API_URL": "https://sandbox.example.com"},
+			},
+			"old": {ID: "view_2", Slug: "old", Name: "Old", ArchivedAt: pointers.Time(time.Now())},
+		},
+		ViewPermissions: map[string]api.ViewPermissions{
+			"dashboard": {
+				RequireExplicitPermissions: true,
+				Permissions: libapi.Permissions{
+					{RoleID: libapi.RoleTaskAdmin, SubUserID: pointers.String("usr_alice")},
+					{RoleID: libapi.RoleTaskViewer, SubUserID: pointers.String("usr_bob")},
+					{RoleID: libapi.RoleTaskViewer, SubGroupID: pointers.String("grp_ops")},
+				},
+			},
+		},
+	}
+
+	views, err := Export(ctx, source, nil)
+	require.NoError(err)
+	require.Equal([]View{
+		{
+			Slug:                       "dashboard",
+			Name:                       "Dashboard",
+			Description:                "The dashboard",
+			EnvVars:                    map[string]string{"API_URL": "https://sandbox.example.com"},
+			RequireExplicitPermissions: true,
+			Permissions: []Permission{
+				{RoleID: libapi.RoleTaskAdmin, User: "alice@example.com"},
+				{RoleID: libapi.RoleTaskViewer, User: "bob@example.com"},
+				{RoleID: libapi.RoleTaskViewer, Group: "Ops"},
+			},
+		},
+	}, views, "expected archived views to be skipped")
+
+	dir := t.TempDir()
+	require.NoError(Write(dir, views))
+	read, err := Read(dir)
+	require.NoError(err)
+	require.Equal(views, read)
+
+	// Users and groups have different IDs in the target team, and bob isn't a member.
+	target := &api.MockClient{
+		Users: map[string]api.User{
+			"usr_alice2": {ID: "usr_alice2", Email: "alice@example.com"},
+		},
+		Groups: []api.Group{{ID: "grp_ops2", Name: "Ops"}},
+	}
+	res, err := Import(ctx, target, read)
+	require.NoError(err)
+	require.Equal([]string{"dashboard"}, res.Created)
+	require.Empty(res.Updated)
+	require.Equal([]string{"dashboard: user bob@example.com"}, res.Skipped)
+
+	view := target.Views["dashboard"]
+	require.Equal("Dashboard", view.Name)
+	require.Equal("The dashboard", view.Description)
+	require.Equal(map[string]string{"API_URL": "https://sandbox.example.com"}, view.EnvVars)
+	require.Equal(api.ViewPermissions{
+		RequireExplicitPermissions: true,
+		Permissions: libapi.Permissions{
+			{RoleID: libapi.RoleTaskAdmin, SubUserID: pointers.String("usr_alice2")},
+			{RoleID: libapi.RoleTaskViewer, SubGroupID: pointers.String("grp_ops2")},
+		},
+	}, target.ViewPermissions["dashboard"])
+
+	// Importing again updates the existing view.
+	read[0].Name = "Dashboard v2"
+	res, err = Import(ctx, target, read)
+	require.NoError(err)
+	require.Empty(res.Created)
+	require.Equal([]string{"dashboard"}, res.Updated)
+	require.Equal("Dashboard v2", target.Views["dashboard"].Name)
+}